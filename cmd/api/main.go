@@ -1,15 +1,24 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
 	"thuchanhgolang/config"
 	"thuchanhgolang/internal/appconfig/mongo"
 	"thuchanhgolang/internal/httpserver"
+	"thuchanhgolang/internal/migration"
+	"thuchanhgolang/pkg/encrypter"
 	pkgLog "thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/ratelimit"
 	"time"
 )
 
 func main() {
+	// --migrate: chạy các migration chưa áp dụng trước khi mở HTTP server
+	migrate := flag.Bool("migrate", false, "apply pending database migrations before starting the server")
+	flag.Parse()
+
 	// Load config  (chạy main.go thì dòng này chạy thứ 1)
 	//lần thứ 3 dòng này nhận được cấu hình từ config.go (URI và DBName đã được load từ file .env)
 	cfg, err := config.Load()
@@ -38,13 +47,42 @@ func main() {
 
 	log.Println("Connected to MongoDB successfully!")
 
+	if *migrate {
+		m, err := migration.New(l, db, migration.NewStore(l, db), migration.All())
+		if err != nil {
+			panic(err)
+		}
+		done, err := m.Up(context.Background())
+		if err != nil {
+			panic(err)
+		}
+		log.Printf("Applied %d migration(s)", len(done))
+	}
+
+	// Rate limit: mongo để giới hạn dùng chung giữa các instance
+	var rateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.RateLimit.Store == "mongo" {
+		rateLimitStore = ratelimit.NewMongoStore(db)
+	}
+
 	// Sử dụng db để làm việc với collections
 	// collection := db.Collection("your-collection")
 	srv := httpserver.New(l, httpserver.Config{
 		Port:           cfg.HTTPServer.Port,
+		GRPCPort:       cfg.HTTPServer.GRPCPort,
+		TrustedProxies: cfg.HTTPServer.TrustedProxies,
 		Database:       db,
 		JWTSecretKey:   cfg.JWT.SecretKey,
 		AccessDuration: time.Duration(cfg.JWT.AccessDuration) * time.Second,
+
+		ImpersonationDuration: time.Duration(cfg.JWT.ImpersonationDuration) * time.Second,
+		RateLimitStore:        rateLimitStore,
+		Encrypter:             encrypter.NewEncrypter(cfg.Encrypter.Key),
+		RateLimits: httpserver.RateLimits{
+			Auth:    cfg.RateLimit.Auth,
+			Users:   cfg.RateLimit.Users,
+			Exports: cfg.RateLimit.Exports,
+		},
 	})
 	srv.Run()
 }
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"thuchanhgolang/config"
+	"thuchanhgolang/internal/appconfig/mongo"
+	"thuchanhgolang/internal/migration"
+	pkgLog "thuchanhgolang/pkg/log"
+	pkgMongo "thuchanhgolang/pkg/mongo"
+)
+
+// migrate chạy các migration của database.
+//
+//	go run ./cmd/migrate up              # chạy mọi migration chưa áp dụng
+//	go run ./cmd/migrate down            # rollback migration mới nhất
+//	go run ./cmd/migrate down -steps 2   # rollback 2 migration mới nhất
+//	go run ./cmd/migrate status          # liệt kê migration và thời điểm áp dụng
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: migrate [up|down|status] [-steps N]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	command := "up"
+	args := flag.Args()
+	if len(args) > 0 {
+		command = args[0]
+		args = args[1:]
+	}
+
+	// -steps đặt sau subcommand: migrate down -steps 2
+	sub := flag.NewFlagSet(command, flag.ExitOnError)
+	steps := sub.Int("steps", 1, "number of migrations to roll back (down only)")
+	sub.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	client, err := mongo.Connect(cfg.Mongo.URI)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer mongo.Disconnect(client)
+
+	db := client.Database(cfg.Mongo.DBName)
+
+	l := pkgLog.InitializeZapLogger(pkgLog.ZapConfig{
+		Level:    cfg.Logger.Level,
+		Mode:     cfg.Logger.Mode,
+		Encoding: cfg.Logger.Encoding,
+	})
+
+	m, err := migration.New(l, db, migration.NewStore(l, db), migration.All())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := context.Background()
+	switch command {
+	case "up":
+		done, err := m.Up(ctx)
+		for _, r := range done {
+			fmt.Printf("applied  %d_%s\n", r.Version, r.Name)
+		}
+		exitOnError(client, err)
+		if len(done) == 0 {
+			fmt.Println("database is up to date")
+		}
+
+	case "down":
+		done, err := m.Down(ctx, *steps)
+		for _, r := range done {
+			fmt.Printf("reverted %d_%s\n", r.Version, r.Name)
+		}
+		exitOnError(client, err)
+
+	case "status":
+		statuses, err := m.Status(ctx)
+		exitOnError(client, err)
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%4d  %-30s %s\n", s.Version, s.Name, applied)
+		}
+
+	default:
+		flag.Usage()
+		mongo.Disconnect(client)
+		os.Exit(2)
+	}
+}
+
+// exitOnError in lỗi và thoát với code 1, đóng kết nối trước vì os.Exit bỏ qua defer
+func exitOnError(client pkgMongo.Client, err error) {
+	if err == nil {
+		return
+	}
+	log.Print(err)
+	mongo.Disconnect(client)
+	os.Exit(1)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"thuchanhgolang/config"
+	"thuchanhgolang/internal/appconfig/mongo"
+	branchMongo "thuchanhgolang/internal/branch/repository/mongo"
+	departmentMongo "thuchanhgolang/internal/department/repository/mongo"
+	"thuchanhgolang/internal/orgcheck"
+	orgcheckMongo "thuchanhgolang/internal/orgcheck/repository/mongo"
+	orgcheckUsecase "thuchanhgolang/internal/orgcheck/usecase"
+	regionMongo "thuchanhgolang/internal/region/repository/mongo"
+	"thuchanhgolang/internal/user/repository/query"
+	pkgLog "thuchanhgolang/pkg/log"
+)
+
+// orgcheck quét cây tổ chức, in báo cáo JSON về bản ghi mồ côi và ID bị lệch.
+// Exit code 1 khi còn lỗi chưa được sửa, để dùng được trong CI/cron.
+//
+//	go run ./cmd/orgcheck            # chỉ báo cáo
+//	go run ./cmd/orgcheck --fix      # sửa user bị lệch
+//	go run ./cmd/orgcheck --output report.json
+func main() {
+	fix := flag.Bool("fix", false, "re-resolve mismatched user hierarchy IDs and save them")
+	output := flag.String("output", "", "write the JSON report to this file instead of stdout")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	client, err := mongo.Connect(cfg.Mongo.URI)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer mongo.Disconnect(client)
+
+	db := client.Database(cfg.Mongo.DBName)
+
+	l := pkgLog.InitializeZapLogger(pkgLog.ZapConfig{
+		Level:    cfg.Logger.Level,
+		Mode:     cfg.Logger.Mode,
+		Encoding: cfg.Logger.Encoding,
+	})
+
+	// Query service dùng chung với user usecase để resolve hierarchy
+	queryService := query.NewService(l,
+		branchMongo.NewRepository(l, db),
+		departmentMongo.NewRepository(l, db),
+		regionMongo.NewRepository(l, db),
+	)
+	uc := orgcheckUsecase.NewUsecase(l, orgcheckMongo.NewRepository(l, db), queryService)
+
+	report, err := uc.Check(context.Background(), orgcheck.CheckInput{Fix: *fix})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		log.Fatal(err)
+	}
+
+	if report.Summary.Unresolved > 0 {
+		// Đóng kết nối trước khi thoát vì os.Exit bỏ qua defer
+		mongo.Disconnect(client)
+		os.Exit(1)
+	}
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"thuchanhgolang/pkg/ratelimit"
+
 	"github.com/caarlos0/env/v6"
 	"github.com/joho/godotenv"
 )
@@ -10,12 +12,31 @@ type Config struct {
 	Logger     LoggerConfig
 	Mongo      MongoConfig
 	JWT        JWTConfig
+	RateLimit  RateLimitConfig
+	Encrypter  EncrypterConfig
 }
 
 type JWTConfig struct {
 	SecretKey       string `env:"JWT_SECRET_KEY" envDefault:"your-secret-key-change-in-production"`
 	AccessDuration  int    `env:"JWT_ACCESS_DURATION" envDefault:"86400"`   // 24 hours in seconds
 	RefreshDuration int    `env:"JWT_REFRESH_DURATION" envDefault:"604800"` // 7 days in seconds
+
+	ImpersonationDuration int `env:"JWT_IMPERSONATION_DURATION" envDefault:"900"` // 15 minutes in seconds
+}
+
+// RateLimitConfig: giới hạn viết dạng "<số request>/<khoảng thời gian>", "off" để tắt.
+// Store "mongo" dùng chung giới hạn giữa các instance, "memory" chỉ giới hạn trong từng instance
+type RateLimitConfig struct {
+	Store   string          `env:"RATE_LIMIT_STORE" envDefault:"memory"`
+	Auth    ratelimit.Limit `env:"RATE_LIMIT_AUTH" envDefault:"10/1m"`
+	Users   ratelimit.Limit `env:"RATE_LIMIT_USERS" envDefault:"300/1m"`
+	Exports ratelimit.Limit `env:"RATE_LIMIT_EXPORTS" envDefault:"10/1m"`
+}
+
+// EncrypterConfig: khóa AES dùng để mã hóa dữ liệu bí mật lưu trong database (như mật khẩu tạm của job import),
+// phải dài đúng 16, 24 hoặc 32 byte
+type EncrypterConfig struct {
+	Key string `env:"ENCRYPT_KEY" envDefault:"change-this-32-byte-key-in-prod!"`
 }
 
 type MongoConfig struct {
@@ -26,6 +47,12 @@ type MongoConfig struct {
 type HTTPServerConfig struct {
 	Port int    `env:"PORT" envDefault:"8080"`
 	Mode string `env:"MODE" envDefault:"development"`
+
+	GRPCPort int `env:"GRPC_PORT" envDefault:"9090"` // gRPC API cho các service nội bộ, 0 → tắt
+
+	// TrustedProxies là IP/CIDR của reverse proxy, chỉ X-Forwarded-For từ các proxy này mới được tin.
+	// Để trống → dùng IP kết nối trực tiếp, client không giả được IP để né rate limit
+	TrustedProxies []string `env:"TRUSTED_PROXIES" envSeparator:","`
 }
 
 type LoggerConfig struct {
@@ -2,15 +2,26 @@ package http
 
 import (
 	"errors"
+	"net/http"
 	"thuchanhgolang/internal/auth"
 	pkgErrors "thuchanhgolang/pkg/errors"
 )
 
 var (
 	errWrongBody          = pkgErrors.NewHTTPError(40000, "Wrong body")
-	errUsernameExists     = pkgErrors.NewHTTPError(40001, "Username already exists")
-	errInvalidCredentials = pkgErrors.NewHTTPError(40002, "Invalid username or password")
-	errUserNotFound       = pkgErrors.NewHTTPError(40003, "User not found")
+	errUsernameExists     = pkgErrors.NewConflictError(40001, "Username already exists", "username")
+	errInvalidCredentials = pkgErrors.NewHTTPErrorWithStatus(40002, "Invalid username or password", http.StatusUnauthorized)
+	errUserNotFound       = pkgErrors.NewHTTPErrorWithStatus(40003, "User not found", http.StatusNotFound)
+	errWrongPassword      = pkgErrors.NewHTTPErrorWithStatus(40004, "Current password is incorrect", http.StatusUnprocessableEntity)
+	errSamePassword       = pkgErrors.NewHTTPErrorWithStatus(40005, "New password must be different from current password", http.StatusUnprocessableEntity)
+	errUnauthorized       = pkgErrors.NewUnauthorizedHTTPError()
+
+	errImpersonationForbidden  = pkgErrors.NewHTTPErrorWithStatus(40007, "Impersonation is not allowed", http.StatusForbidden)
+	errImpersonationOutOfScope = pkgErrors.NewHTTPErrorWithStatus(40008, "Target user is outside of your scope", http.StatusForbidden)
+	errInvalidImpersonationID  = pkgErrors.NewHTTPError(40009, "Invalid impersonation ID")
+	errImpersonationNotFound   = pkgErrors.NewHTTPErrorWithStatus(40010, "Impersonation session not found", http.StatusNotFound)
+	errUserDeactivated         = pkgErrors.NewHTTPErrorWithStatus(40011, "User is deactivated", http.StatusForbidden)
+	errEmailExists             = pkgErrors.NewConflictError(40012, "Email already exists in this shop", "email")
 )
 
 // mapError chuyển đổi domain error thành HTTP error
@@ -19,12 +30,33 @@ func (h handler) mapError(err error) error {
 	if errors.Is(err, auth.ErrUsernameExists) {
 		return errUsernameExists
 	}
+	if errors.Is(err, auth.ErrEmailExists) {
+		return errEmailExists
+	}
 	if errors.Is(err, auth.ErrUserNotFound) {
 		return errUserNotFound
 	}
 	if errors.Is(err, auth.ErrInvalidCredentials) {
 		return errInvalidCredentials
 	}
+	if errors.Is(err, auth.ErrWrongCurrentPassword) {
+		return errWrongPassword
+	}
+	if errors.Is(err, auth.ErrSamePassword) {
+		return errSamePassword
+	}
+	if errors.Is(err, auth.ErrImpersonationForbidden) {
+		return errImpersonationForbidden
+	}
+	if errors.Is(err, auth.ErrImpersonationOutOfScope) {
+		return errImpersonationOutOfScope
+	}
+	if errors.Is(err, auth.ErrImpersonationNotFound) {
+		return errImpersonationNotFound
+	}
+	if errors.Is(err, auth.ErrUserDeactivated) {
+		return errUserDeactivated
+	}
 
 	return err
 }
@@ -4,6 +4,7 @@ import (
 	"thuchanhgolang/pkg/response"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // register xử lý HTTP request đăng ký user mới
@@ -28,7 +29,8 @@ func (h handler) register(c *gin.Context) {
 		return
 	}
 
-	// Trả về kết quả thành công
+	// Trả về kết quả thành công, response chứa token nên không được cache hay lưu lại
+	c.Header("Cache-Control", "no-store")
 	response.OK(c, h.newRegisterResp(result))
 }
 
@@ -54,6 +56,118 @@ func (h handler) login(c *gin.Context) {
 		return
 	}
 
-	// Trả về kết quả thành công
+	// Trả về kết quả thành công, response chứa token nên không được cache hay lưu lại
+	c.Header("Cache-Control", "no-store")
 	response.OK(c, h.newLoginResp(result))
 }
+
+// me xử lý HTTP request lấy thông tin user đang đăng nhập
+func (h handler) me(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Lấy scope từ token
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "auth.handler.me.processScope: %s", err)
+		response.Error(c, err)
+		return
+	}
+
+	// Gọi usecase để lấy thông tin
+	result, err := h.uc.Me(ctx, sc)
+	if err != nil {
+		h.l.Warnf(ctx, "auth.handler.me.uc.Me: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Trả về kết quả thành công
+	response.OK(c, h.newMeResp(result))
+}
+
+// changePassword xử lý HTTP request đổi mật khẩu
+func (h handler) changePassword(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Xử lý và validate request
+	req, sc, err := h.processChangePasswordRequest(c)
+	if err != nil {
+		h.l.Warnf(ctx, "auth.handler.changePassword.processChangePasswordRequest: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Gọi usecase để đổi mật khẩu
+	err = h.uc.ChangePassword(ctx, sc, req.toInput())
+	if err != nil {
+		h.l.Warnf(ctx, "auth.handler.changePassword.uc.ChangePassword: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Trả về kết quả thành công
+	response.OK(c, gin.H{"message": "Password changed successfully"})
+}
+
+// impersonate xử lý HTTP request bắt đầu impersonation
+func (h handler) impersonate(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Xử lý và validate request
+	req, sc, err := h.processImpersonateRequest(c)
+	if err != nil {
+		h.l.Warnf(ctx, "auth.handler.impersonate.processImpersonateRequest: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Gọi usecase để tạo token impersonation
+	result, err := h.uc.Impersonate(ctx, sc, req.toInput())
+	if err != nil {
+		h.l.Warnf(ctx, "auth.handler.impersonate.uc.Impersonate: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Trả về kết quả thành công, response chứa token nên không được cache hay lưu lại
+	c.Header("Cache-Control", "no-store")
+	response.OK(c, h.newImpersonateResp(result))
+}
+
+// endImpersonation xử lý HTTP request kết thúc impersonation
+func (h handler) endImpersonation(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Lấy ID phiên từ URL param
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		h.l.Warnf(ctx, "auth.handler.endImpersonation.ObjectIDFromHex: %s", err)
+		response.Error(c, errInvalidImpersonationID)
+		return
+	}
+
+	// Lấy scope từ token
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "auth.handler.endImpersonation.processScope: %s", err)
+		response.Error(c, err)
+		return
+	}
+
+	// Gọi usecase để kết thúc phiên
+	err = h.uc.EndImpersonation(ctx, sc, id)
+	if err != nil {
+		h.l.Warnf(ctx, "auth.handler.endImpersonation.uc.EndImpersonation: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Trả về kết quả thành công
+	response.OK(c, gin.H{"message": "Impersonation ended successfully"})
+}
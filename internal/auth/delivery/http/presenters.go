@@ -4,58 +4,21 @@ import (
 	"strings"
 	"thuchanhgolang/internal/auth"
 	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/response"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // registerReq là cấu trúc nhận dữ liệu đăng ký từ HTTP request
 type registerReq struct {
-	Username     string  `json:"username" binding:"required,min=3"`
-	Password     string  `json:"password" binding:"required,min=6"`
+	Username     string  `json:"username" binding:"required,username"`
+	Password     string  `json:"password" binding:"required,min=6,max=72"`
 	Email        string  `json:"email" binding:"required,email"`
-	Role         string  `json:"role" binding:"required"` // Role: manager, region_manager, etc.
-	ShopID       string  `json:"shop_id" binding:"required"`
-	RegionID     *string `json:"region_id,omitempty"`
-	BranchID     *string `json:"branch_id,omitempty"`
-	DepartmentID *string `json:"department_id,omitempty"`
-}
-
-// validate kiểm tra dữ liệu đầu vào
-func (r registerReq) validate() error {
-	// Kiểm tra các fields không rỗng
-	if strings.TrimSpace(r.Username) == "" || strings.TrimSpace(r.Password) == "" || strings.TrimSpace(r.Email) == "" {
-		return errWrongBody
-	}
-
-	// Validate role
-	role := models.Role(r.Role)
-	if !role.IsValid() {
-		return errWrongBody
-	}
-
-	// Kiểm tra ShopID hợp lệ
-	if _, err := primitive.ObjectIDFromHex(r.ShopID); err != nil {
-		return errWrongBody
-	}
-
-	// Validate optional IDs
-	if r.RegionID != nil {
-		if _, err := primitive.ObjectIDFromHex(*r.RegionID); err != nil {
-			return errWrongBody
-		}
-	}
-	if r.BranchID != nil {
-		if _, err := primitive.ObjectIDFromHex(*r.BranchID); err != nil {
-			return errWrongBody
-		}
-	}
-	if r.DepartmentID != nil {
-		if _, err := primitive.ObjectIDFromHex(*r.DepartmentID); err != nil {
-			return errWrongBody
-		}
-	}
-
-	return nil
+	Role         string  `json:"role" binding:"required,role"` // Role: manager, region_manager, etc.
+	ShopID       string  `json:"shop_id" binding:"required,objectid"`
+	RegionID     *string `json:"region_id,omitempty" binding:"omitempty,objectid"`
+	BranchID     *string `json:"branch_id,omitempty" binding:"omitempty,objectid"`
+	DepartmentID *string `json:"department_id,omitempty" binding:"omitempty,objectid"`
 }
 
 // toInput chuyển đổi request thành input cho usecase
@@ -110,17 +73,8 @@ func (h handler) newRegisterResp(output auth.RegisterOutput) registerResp {
 
 // loginReq là cấu trúc nhận dữ liệu đăng nhập từ HTTP request
 type loginReq struct {
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
-}
-
-// validate kiểm tra dữ liệu đầu vào
-func (r loginReq) validate() error {
-	// Kiểm tra các fields không rỗng
-	if strings.TrimSpace(r.Username) == "" || strings.TrimSpace(r.Password) == "" {
-		return errWrongBody
-	}
-	return nil
+	Username string `json:"username" binding:"required,notblank"`
+	Password string `json:"password" binding:"required,notblank"`
 }
 
 // toInput chuyển đổi request thành input cho usecase
@@ -153,6 +107,114 @@ func (h handler) newLoginResp(output auth.LoginOutput) loginResp {
 	}
 }
 
+// meResp là cấu trúc response cho thông tin user đang đăng nhập
+type meResp struct {
+	ID             string   `json:"id"`
+	Username       string   `json:"username"`
+	Email          string   `json:"email"`
+	Role           string   `json:"role"`
+	ShopID         string   `json:"shop_id,omitempty"`
+	ShopName       string   `json:"shop_name,omitempty"`
+	RegionID       string   `json:"region_id,omitempty"`
+	RegionName     string   `json:"region_name,omitempty"`
+	BranchID       string   `json:"branch_id,omitempty"`
+	BranchName     string   `json:"branch_name,omitempty"`
+	DepartmentID   string   `json:"department_id,omitempty"`
+	DepartmentName string   `json:"department_name,omitempty"`
+	Permissions    []string `json:"permissions"`
+}
+
+// newMeResp tạo response từ MeOutput
+func (h handler) newMeResp(output auth.MeOutput) meResp {
+	u := output.User
+	resp := meResp{
+		ID:             u.ID.Hex(),
+		Username:       u.Username,
+		Email:          u.Email,
+		Role:           string(u.Role),
+		ShopName:       output.ShopName,
+		RegionName:     output.RegionName,
+		BranchName:     output.BranchName,
+		DepartmentName: output.DepartmentName,
+		Permissions:    make([]string, 0, len(output.Permissions)),
+	}
+
+	// Chỉ thêm các ID nếu có giá trị
+	if !u.ShopID.IsZero() {
+		resp.ShopID = u.ShopID.Hex()
+	}
+	if !u.RegionID.IsZero() {
+		resp.RegionID = u.RegionID.Hex()
+	}
+	if !u.BranchID.IsZero() {
+		resp.BranchID = u.BranchID.Hex()
+	}
+	if u.DepartmentID != nil && !u.DepartmentID.IsZero() {
+		resp.DepartmentID = u.DepartmentID.Hex()
+	}
+
+	for _, p := range output.Permissions {
+		resp.Permissions = append(resp.Permissions, string(p))
+	}
+
+	return resp
+}
+
+// changePasswordReq là cấu trúc nhận dữ liệu đổi mật khẩu từ HTTP request
+type changePasswordReq struct {
+	CurrentPassword string `json:"current_password" binding:"required,notblank"`
+	NewPassword     string `json:"new_password" binding:"required,min=6,max=72"`
+}
+
+// toInput chuyển đổi request thành input cho usecase
+func (r changePasswordReq) toInput() auth.ChangePasswordInput {
+	return auth.ChangePasswordInput{
+		CurrentPassword: r.CurrentPassword,
+		NewPassword:     r.NewPassword,
+	}
+}
+
+// impersonateReq là cấu trúc nhận dữ liệu impersonate từ HTTP request
+type impersonateReq struct {
+	UserID     string `json:"user_id" binding:"required,objectid"`
+	AllowWrite bool   `json:"allow_write"` // Mặc định token chỉ được đọc
+	Reason     string `json:"reason" binding:"required,notblank"`
+}
+
+// toInput chuyển đổi request thành input cho usecase
+func (r impersonateReq) toInput() auth.ImpersonateInput {
+	userID, _ := primitive.ObjectIDFromHex(r.UserID)
+	return auth.ImpersonateInput{
+		UserID:     userID,
+		AllowWrite: r.AllowWrite,
+		Reason:     strings.TrimSpace(r.Reason),
+	}
+}
+
+// impersonateResp là cấu trúc response sau khi bắt đầu impersonation
+type impersonateResp struct {
+	ImpersonationID string            `json:"impersonation_id"`
+	UserID          string            `json:"user_id"`
+	Username        string            `json:"username"`
+	Role            string            `json:"role"`
+	AllowWrite      bool              `json:"allow_write"`
+	ExpiresAt       response.DateTime `json:"expires_at"`
+	Token           string            `json:"token"`
+}
+
+// newImpersonateResp tạo response từ ImpersonateOutput
+func (h handler) newImpersonateResp(output auth.ImpersonateOutput) impersonateResp {
+	return impersonateResp{
+		ImpersonationID: output.ImpersonationID.Hex(),
+		UserID:          output.Target.ID.Hex(),
+		Username:        output.Target.Username,
+		Role:            string(output.Target.Role),
+		AllowWrite:      output.AllowWrite,
+		ExpiresAt:       response.DateTime(output.ExpiresAt),
+		Token:           output.Token,
+	}
+}
+
 // emptyScope trả về scope rỗng
 func (h handler) emptyScope() models.Scope {
 	return models.Scope{}
@@ -2,6 +2,8 @@ package http
 
 import (
 	"thuchanhgolang/internal/models"
+	pkgErrors "thuchanhgolang/pkg/errors"
+	"thuchanhgolang/pkg/jwt"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,13 +16,7 @@ func (h handler) processRegisterRequest(c *gin.Context) (registerReq, models.Sco
 	var req registerReq
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.l.Warnf(ctx, "auth.http.processRegisterRequest.ShouldBindJSON: %v", err)
-		return registerReq{}, models.Scope{}, errWrongBody
-	}
-
-	// Validate dữ liệu
-	if err := req.validate(); err != nil {
-		h.l.Warnf(ctx, "auth.http.processRegisterRequest.validate: %v", err)
-		return registerReq{}, models.Scope{}, err
+		return registerReq{}, models.Scope{}, pkgErrors.BindingError(err, errWrongBody)
 	}
 
 	// Tạo scope trống
@@ -37,13 +33,7 @@ func (h handler) processLoginRequest(c *gin.Context) (loginReq, models.Scope, er
 	var req loginReq
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.l.Warnf(ctx, "auth.http.processLoginRequest.ShouldBindJSON: %v", err)
-		return loginReq{}, models.Scope{}, errWrongBody
-	}
-
-	// Validate dữ liệu
-	if err := req.validate(); err != nil {
-		h.l.Warnf(ctx, "auth.http.processLoginRequest.validate: %v", err)
-		return loginReq{}, models.Scope{}, err
+		return loginReq{}, models.Scope{}, pkgErrors.BindingError(err, errWrongBody)
 	}
 
 	// Tạo scope trống
@@ -51,3 +41,55 @@ func (h handler) processLoginRequest(c *gin.Context) (loginReq, models.Scope, er
 
 	return req, sc, nil
 }
+
+// processScope lấy scope của user đang đăng nhập từ JWT payload
+func (h handler) processScope(c *gin.Context) (models.Scope, error) {
+	payload, ok := jwt.GetPayloadFromContext(c.Request.Context())
+	if !ok {
+		return models.Scope{}, errUnauthorized
+	}
+
+	return jwt.NewScope(payload), nil
+}
+
+// processChangePasswordRequest xử lý và validate request đổi mật khẩu
+func (h handler) processChangePasswordRequest(c *gin.Context) (changePasswordReq, models.Scope, error) {
+	ctx := c.Request.Context()
+
+	// Lấy scope của user đang đăng nhập
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "auth.http.processChangePasswordRequest.processScope: %v", err)
+		return changePasswordReq{}, models.Scope{}, err
+	}
+
+	// Parse JSON body thành changePasswordReq struct
+	var req changePasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.l.Warnf(ctx, "auth.http.processChangePasswordRequest.ShouldBindJSON: %v", err)
+		return changePasswordReq{}, models.Scope{}, pkgErrors.BindingError(err, errWrongBody)
+	}
+
+	return req, sc, nil
+}
+
+// processImpersonateRequest xử lý và validate request impersonate
+func (h handler) processImpersonateRequest(c *gin.Context) (impersonateReq, models.Scope, error) {
+	ctx := c.Request.Context()
+
+	// Lấy scope của admin đang đăng nhập
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "auth.http.processImpersonateRequest.processScope: %v", err)
+		return impersonateReq{}, models.Scope{}, err
+	}
+
+	// Parse JSON body thành impersonateReq struct
+	var req impersonateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.l.Warnf(ctx, "auth.http.processImpersonateRequest.ShouldBindJSON: %v", err)
+		return impersonateReq{}, models.Scope{}, pkgErrors.BindingError(err, errWrongBody)
+	}
+
+	return req, sc, nil
+}
@@ -1,6 +1,12 @@
 package http
 
-import "github.com/gin-gonic/gin"
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"thuchanhgolang/pkg/openapi"
+)
 
 // MapRoutes map các routes cho auth
 func MapRoutes(g *gin.RouterGroup, h Handler) {
@@ -9,3 +15,32 @@ func MapRoutes(g *gin.RouterGroup, h Handler) {
 	g.POST("/register", hdl.register) // POST /api/v1/auth/register
 	g.POST("/login", hdl.login)       // POST /api/v1/auth/login
 }
+
+// MapProtectedRoutes map các routes cho auth yêu cầu đăng nhập
+func MapProtectedRoutes(g *gin.RouterGroup, h Handler) {
+	hdl := h.(*handler)
+
+	g.GET("/me", hdl.me)                   // GET /api/v1/auth/me
+	g.PUT("/password", hdl.changePassword) // PUT /api/v1/auth/password
+
+	g.POST("/impersonations", hdl.impersonate)            // POST /api/v1/auth/impersonations
+	g.DELETE("/impersonations/:id", hdl.endImpersonation) // DELETE /api/v1/auth/impersonations/:id
+}
+
+// Spec mô tả các route của MapRoutes cho OpenAPI, sửa route thì sửa cả ở đây
+func Spec() []openapi.Route {
+	return []openapi.Route{
+		{Method: http.MethodPost, Path: "/register", Summary: "Đăng ký tài khoản", Public: true, Body: registerReq{}, Response: registerResp{}},
+		{Method: http.MethodPost, Path: "/login", Summary: "Đăng nhập, trả về access token", Public: true, Body: loginReq{}, Response: loginResp{}},
+	}
+}
+
+// ProtectedSpec mô tả các route của MapProtectedRoutes cho OpenAPI
+func ProtectedSpec() []openapi.Route {
+	return []openapi.Route{
+		{Method: http.MethodGet, Path: "/me", Summary: "Xem thông tin user đang đăng nhập", Response: meResp{}},
+		{Method: http.MethodPut, Path: "/password", Summary: "Đổi mật khẩu", Body: changePasswordReq{}, Response: gin.H{}},
+		{Method: http.MethodPost, Path: "/impersonations", Summary: "Đăng nhập thay một user khác", Body: impersonateReq{}, Response: impersonateResp{}},
+		{Method: http.MethodDelete, Path: "/impersonations/:id", Summary: "Kết thúc phiên đăng nhập thay", Response: gin.H{}},
+	}
+}
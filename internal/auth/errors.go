@@ -1,17 +1,45 @@
 package auth
 
-import "errors"
+import (
+	"errors"
+
+	pkgErrors "thuchanhgolang/pkg/errors"
+)
 
 var (
 	// ErrUsernameExists được trả về khi username đã tồn tại
-	ErrUsernameExists = errors.New("username already exists")
+	ErrUsernameExists = pkgErrors.Conflict("username already exists")
+
+	// ErrEmailExists được trả về khi email đã được dùng bởi user khác trong cùng shop
+	ErrEmailExists = pkgErrors.Conflict("email already exists in this shop")
 
 	// ErrUserNotFound được trả về khi không tìm thấy user
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserNotFound = pkgErrors.NotFound("user not found")
 
 	// ErrInvalidCredentials được trả về khi username hoặc password không đúng
 	ErrInvalidCredentials = errors.New("invalid username or password")
 
 	// ErrInvalidPassword được trả về khi password không hợp lệ
 	ErrInvalidPassword = errors.New("invalid password")
+
+	// ErrUserDeactivated được trả về khi user đã bị vô hiệu hóa
+	ErrUserDeactivated = pkgErrors.Forbidden("user is deactivated")
+
+	// ErrWrongCurrentPassword được trả về khi mật khẩu hiện tại không đúng lúc đổi mật khẩu
+	ErrWrongCurrentPassword = pkgErrors.Invalid("current password is incorrect")
+
+	// ErrSamePassword được trả về khi mật khẩu mới trùng mật khẩu hiện tại
+	ErrSamePassword = pkgErrors.Invalid("new password must be different from current password")
+
+	// ErrImpersonationForbidden được trả về khi user không có quyền impersonate
+	ErrImpersonationForbidden = pkgErrors.Forbidden("impersonation is not allowed")
+
+	// ErrImpersonationOutOfScope được trả về khi user cần impersonate nằm ngoài scope của admin
+	ErrImpersonationOutOfScope = pkgErrors.Forbidden("target user is outside of your scope")
+
+	// ErrImpersonationNotFound được trả về khi không tìm thấy phiên impersonation đang mở
+	ErrImpersonationNotFound = pkgErrors.NotFound("impersonation session not found")
+
+	// ErrImpersonationEnded được trả về khi token impersonation thuộc phiên đã kết thúc
+	ErrImpersonationEnded = errors.New("impersonation session has ended")
 )
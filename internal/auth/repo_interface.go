@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"thuchanhgolang/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Repository định nghĩa các phương thức truy cập dữ liệu cho auth
@@ -14,6 +16,21 @@ type Repository interface {
 	// GetUserByUsername lấy user theo username
 	GetUserByUsername(ctx context.Context, opts GetUserOptions) (models.User, error)
 
-	// CheckUserExistsInShop kiểm tra user đã tồn tại trong shop chưa (theo email + shopID)
-	CheckUserExistsInShop(ctx context.Context, opts CheckUserInShopOptions) (bool, error)
+	// GetUserByID lấy user theo ID
+	GetUserByID(ctx context.Context, id primitive.ObjectID) (models.User, error)
+
+	// UpdatePassword cập nhật password (đã hash) của user
+	UpdatePassword(ctx context.Context, opts UpdatePasswordOptions) error
+
+	// GetHierarchyNames lấy tên shop/region/branch/department theo các ID
+	GetHierarchyNames(ctx context.Context, opts GetHierarchyNamesOptions) (HierarchyNames, error)
+
+	// CreateImpersonationLog ghi lại phiên impersonation mới
+	CreateImpersonationLog(ctx context.Context, opts CreateImpersonationLogOptions) (models.ImpersonationLog, error)
+
+	// EndImpersonationLog đánh dấu kết thúc phiên impersonation của actor
+	EndImpersonationLog(ctx context.Context, opts EndImpersonationLogOptions) error
+
+	// GetImpersonationLog lấy phiên impersonation theo ID
+	GetImpersonationLog(ctx context.Context, id primitive.ObjectID) (models.ImpersonationLog, error)
 }
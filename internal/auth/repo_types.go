@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"time"
+
 	"thuchanhgolang/internal/models"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -23,8 +25,42 @@ type GetUserOptions struct {
 	Username string
 }
 
-// CheckUserInShopOptions là options để kiểm tra user tồn tại trong shop
-type CheckUserInShopOptions struct {
-	Email  string
-	ShopID primitive.ObjectID
+// UpdatePasswordOptions là options để cập nhật password của user
+type UpdatePasswordOptions struct {
+	UserID   primitive.ObjectID
+	Password string // Password đã được hash
+}
+
+// GetHierarchyNamesOptions là options để lấy tên các cấp tổ chức.
+// ID nào rỗng sẽ được bỏ qua.
+type GetHierarchyNamesOptions struct {
+	ShopID       primitive.ObjectID
+	RegionID     primitive.ObjectID
+	BranchID     primitive.ObjectID
+	DepartmentID *primitive.ObjectID
+}
+
+// HierarchyNames là tên các cấp tổ chức của một user
+type HierarchyNames struct {
+	ShopName       string
+	RegionName     string
+	BranchName     string
+	DepartmentName string
+}
+
+// CreateImpersonationLogOptions là options để ghi log phiên impersonation
+type CreateImpersonationLogOptions struct {
+	Actor      models.User
+	Target     models.User
+	AllowWrite bool
+	Reason     string
+	StartedAt  time.Time
+	ExpiresAt  time.Time
+}
+
+// EndImpersonationLogOptions là options để kết thúc phiên impersonation
+type EndImpersonationLogOptions struct {
+	ID      primitive.ObjectID
+	ActorID primitive.ObjectID
+	EndedAt time.Time
 }
@@ -2,17 +2,20 @@ package mongo
 
 import (
 	"context"
+	"time"
 
 	"thuchanhgolang/internal/auth"
 	"thuchanhgolang/internal/models"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // CreateUser tạo user mới trong MongoDB
 func (repo *implRepository) CreateUser(ctx context.Context, opts auth.CreateUserOptions) (models.User, error) {
 	col := repo.db.Collection("users")
+	now := time.Now()
 
 	// Tạo user object
 	newUser := models.User{
@@ -23,6 +26,9 @@ func (repo *implRepository) CreateUser(ctx context.Context, opts auth.CreateUser
 		Role:         opts.Role,
 		ShopID:       opts.ShopID,
 		DepartmentID: opts.DepartmentID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		Version:      1,
 	}
 
 	// Set RegionID và BranchID nếu có
@@ -62,27 +68,87 @@ func (repo *implRepository) GetUserByUsername(ctx context.Context, opts auth.Get
 	return user, nil
 }
 
-// CheckUserExistsInShop kiểm tra user đã tồn tại trong shop chưa
-func (repo *implRepository) CheckUserExistsInShop(ctx context.Context, opts auth.CheckUserInShopOptions) (bool, error) {
+// GetUserByID lấy user theo ID từ MongoDB
+func (repo *implRepository) GetUserByID(ctx context.Context, id primitive.ObjectID) (models.User, error) {
 	col := repo.db.Collection("users")
 
-	// Tìm kiếm theo email và shop_id
-	filter := bson.M{
-		"email":   opts.Email,
-		"shop_id": opts.ShopID,
-	}
-
 	var user models.User
+	filter := bson.M{"_id": id}
 	err := col.FindOne(ctx, filter).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			// Không tìm thấy - user chưa tồn tại trong shop
-			return false, nil
+			return models.User{}, auth.ErrUserNotFound
+		}
+		repo.l.Errorf(ctx, "auth.repo.GetUserByID.FindOne: %v", err)
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+// UpdatePassword cập nhật password của user trong MongoDB
+func (repo *implRepository) UpdatePassword(ctx context.Context, opts auth.UpdatePasswordOptions) error {
+	col := repo.db.Collection("users")
+
+	filter := bson.M{"_id": opts.UserID}
+	update := bson.M{"$set": bson.M{
+		"password":   opts.Password,
+		"updated_at": time.Now(),
+		"updated_by": opts.UserID.Hex(), // User tự đổi mật khẩu của mình
+	}}
+	result, err := col.UpdateOne(ctx, filter, update)
+	if err != nil {
+		repo.l.Errorf(ctx, "auth.repo.UpdatePassword.UpdateOne: %v", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return auth.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// GetHierarchyNames lấy tên shop/region/branch/department từ MongoDB
+// Nếu một đơn vị không tồn tại thì tên tương ứng để trống
+func (repo *implRepository) GetHierarchyNames(ctx context.Context, opts auth.GetHierarchyNamesOptions) (auth.HierarchyNames, error) {
+	var names auth.HierarchyNames
+	var err error
+
+	if names.ShopName, err = repo.getName(ctx, "shops", opts.ShopID); err != nil {
+		return auth.HierarchyNames{}, err
+	}
+	if names.RegionName, err = repo.getName(ctx, "regions", opts.RegionID); err != nil {
+		return auth.HierarchyNames{}, err
+	}
+	if names.BranchName, err = repo.getName(ctx, "branches", opts.BranchID); err != nil {
+		return auth.HierarchyNames{}, err
+	}
+	if opts.DepartmentID != nil {
+		if names.DepartmentName, err = repo.getName(ctx, "departments", *opts.DepartmentID); err != nil {
+			return auth.HierarchyNames{}, err
+		}
+	}
+
+	return names, nil
+}
+
+// getName lấy field name của document theo ID trong collection
+func (repo *implRepository) getName(ctx context.Context, collection string, id primitive.ObjectID) (string, error) {
+	if id.IsZero() {
+		return "", nil
+	}
+
+	var doc struct {
+		Name string `bson:"name"`
+	}
+	err := repo.db.Collection(collection).FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "", nil
 		}
-		repo.l.Errorf(ctx, "auth.repo.CheckUserExistsInShop.FindOne: %v", err)
-		return false, err
+		repo.l.Errorf(ctx, "auth.repo.getName.FindOne(%s): %v", collection, err)
+		return "", err
 	}
 
-	// Tìm thấy - user đã tồn tại trong shop
-	return true, nil
+	return doc.Name, nil
 }
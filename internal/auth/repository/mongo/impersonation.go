@@ -0,0 +1,81 @@
+package mongo
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/auth"
+	"thuchanhgolang/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	impersonationLogCollection = "impersonation_logs"
+)
+
+// CreateImpersonationLog ghi lại phiên impersonation mới vào MongoDB
+func (repo *implRepository) CreateImpersonationLog(ctx context.Context, opts auth.CreateImpersonationLogOptions) (models.ImpersonationLog, error) {
+	col := repo.db.Collection(impersonationLogCollection)
+
+	newLog := models.ImpersonationLog{
+		ID:             repo.db.NewObjectID(),
+		ActorID:        opts.Actor.ID,
+		ActorUsername:  opts.Actor.Username,
+		TargetUserID:   opts.Target.ID,
+		TargetUsername: opts.Target.Username,
+		ShopID:         opts.Target.ShopID,
+		AllowWrite:     opts.AllowWrite,
+		Reason:         opts.Reason,
+		StartedAt:      opts.StartedAt,
+		ExpiresAt:      opts.ExpiresAt,
+	}
+
+	_, err := col.InsertOne(ctx, newLog)
+	if err != nil {
+		repo.l.Errorf(ctx, "auth.repo.CreateImpersonationLog.InsertOne: %v", err)
+		return models.ImpersonationLog{}, err
+	}
+
+	return newLog, nil
+}
+
+// EndImpersonationLog đánh dấu kết thúc phiên impersonation (chỉ phiên chưa kết thúc của actor)
+func (repo *implRepository) EndImpersonationLog(ctx context.Context, opts auth.EndImpersonationLogOptions) error {
+	col := repo.db.Collection(impersonationLogCollection)
+
+	filter := bson.M{
+		"_id":      opts.ID,
+		"actor_id": opts.ActorID,
+		"ended_at": bson.M{"$exists": false},
+	}
+	update := bson.M{"$set": bson.M{"ended_at": opts.EndedAt}}
+	result, err := col.UpdateOne(ctx, filter, update)
+	if err != nil {
+		repo.l.Errorf(ctx, "auth.repo.EndImpersonationLog.UpdateOne: %v", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return auth.ErrImpersonationNotFound
+	}
+
+	return nil
+}
+
+// GetImpersonationLog lấy phiên impersonation theo ID
+func (repo *implRepository) GetImpersonationLog(ctx context.Context, id primitive.ObjectID) (models.ImpersonationLog, error) {
+	col := repo.db.Collection(impersonationLogCollection)
+
+	var impLog models.ImpersonationLog
+	err := col.FindOne(ctx, bson.M{"_id": id}).Decode(&impLog)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.ImpersonationLog{}, auth.ErrImpersonationNotFound
+		}
+		repo.l.Errorf(ctx, "auth.repo.GetImpersonationLog.FindOne: %v", err)
+		return models.ImpersonationLog{}, err
+	}
+
+	return impLog, nil
+}
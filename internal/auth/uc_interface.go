@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"thuchanhgolang/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Usecase định nghĩa các business logic cho auth
@@ -13,4 +15,25 @@ type Usecase interface {
 
 	// Login đăng nhập user
 	Login(ctx context.Context, sc models.Scope, input LoginInput) (LoginOutput, error)
+
+	// Me lấy profile, tên các cấp tổ chức và quyền hiệu lực của user đang đăng nhập
+	Me(ctx context.Context, sc models.Scope) (MeOutput, error)
+
+	// ChangePassword đổi mật khẩu của user đang đăng nhập (yêu cầu mật khẩu hiện tại)
+	ChangePassword(ctx context.Context, sc models.Scope, input ChangePasswordInput) error
+
+	// Impersonate cấp token ngắn hạn để Manager xem hệ thống dưới danh nghĩa một user trong scope
+	Impersonate(ctx context.Context, sc models.Scope, input ImpersonateInput) (ImpersonateOutput, error)
+
+	// EndImpersonation kết thúc phiên impersonation do chính admin đã tạo
+	EndImpersonation(ctx context.Context, sc models.Scope, id primitive.ObjectID) error
+
+	ImpersonationChecker
+}
+
+// ImpersonationChecker kiểm tra phiên impersonation của token còn hiệu lực không,
+// dùng trong middleware Auth của REST và interceptor auth của gRPC
+type ImpersonationChecker interface {
+	// CheckImpersonation trả về ErrImpersonationEnded nếu phiên đã bị admin kết thúc hoặc hết hạn
+	CheckImpersonation(ctx context.Context, id string) error
 }
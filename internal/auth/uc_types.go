@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"time"
+
 	"thuchanhgolang/internal/models"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -43,3 +45,35 @@ type LoginOutput struct {
 	ShopID   primitive.ObjectID
 	Token    string // JWT token
 }
+
+// MeOutput là thông tin của user đang đăng nhập
+type MeOutput struct {
+	User           models.User
+	ShopName       string
+	RegionName     string
+	BranchName     string
+	DepartmentName string
+	Permissions    []models.Permission
+}
+
+// ChangePasswordInput là input để đổi mật khẩu
+type ChangePasswordInput struct {
+	CurrentPassword string
+	NewPassword     string
+}
+
+// ImpersonateInput là input để bắt đầu impersonation
+type ImpersonateInput struct {
+	UserID     primitive.ObjectID // User cần impersonate
+	AllowWrite bool               // Cho phép token thực hiện thao tác ghi
+	Reason     string             // Lý do (ghi vào audit log)
+}
+
+// ImpersonateOutput là kết quả sau khi bắt đầu impersonation
+type ImpersonateOutput struct {
+	ImpersonationID primitive.ObjectID
+	Target          models.User
+	AllowWrite      bool
+	ExpiresAt       time.Time
+	Token           string // JWT token có claim "act"
+}
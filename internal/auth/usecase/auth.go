@@ -5,70 +5,63 @@ import (
 
 	"thuchanhgolang/internal/auth"
 	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/user"
+	"thuchanhgolang/pkg/event"
 	"thuchanhgolang/pkg/jwt"
+	"thuchanhgolang/pkg/mongo"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // Register đăng ký user mới
 func (uc *implUsecase) Register(ctx context.Context, sc models.Scope, input auth.RegisterInput) (auth.RegisterOutput, error) {
-	// 1. Kiểm tra user đã tồn tại trong shop chưa (theo email + shopID)
-	exists, err := uc.repo.CheckUserExistsInShop(ctx, auth.CheckUserInShopOptions{
-		Email:  input.Email,
-		ShopID: input.ShopID,
-	})
-	if err != nil {
-		uc.l.Errorf(ctx, "auth.usecase.Register.CheckUserExistsInShop: %v", err)
-		return auth.RegisterOutput{}, err
-	}
-	if exists {
-		return auth.RegisterOutput{}, auth.ErrUsernameExists // Hoặc tạo error mới ErrUserExistsInShop
-	}
-
-	// 2. Hash password
+	// 1. Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
 	if err != nil {
 		uc.l.Errorf(ctx, "auth.usecase.Register.bcrypt: %v", err)
 		return auth.RegisterOutput{}, auth.ErrInvalidPassword
 	}
 
-	// 3. Tạo user trong database
-	newUser, err := uc.repo.CreateUser(ctx, auth.CreateUserOptions{
-		Username:     input.Username,
-		Password:     string(hashedPassword),
-		Email:        input.Email,
-		Role:         input.Role,
-		ShopID:       input.ShopID,
-		RegionID:     input.RegionID,
-		BranchID:     input.BranchID,
-		DepartmentID: input.DepartmentID,
+	// 2. Tạo user trong database, username/email trùng bị chặn bởi unique index.
+	// Event được ghi vào outbox trong cùng transaction
+	var newUser models.User
+	err = uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		newUser, err = uc.repo.CreateUser(ctx, auth.CreateUserOptions{
+			Username:     input.Username,
+			Password:     string(hashedPassword),
+			Email:        input.Email,
+			Role:         input.Role,
+			ShopID:       input.ShopID,
+			RegionID:     input.RegionID,
+			BranchID:     input.BranchID,
+			DepartmentID: input.DepartmentID,
+		})
+		if err != nil {
+			uc.l.Errorf(ctx, "auth.usecase.Register.CreateUser: %v", err)
+			return err
+		}
+		e := event.New(user.EventUserCreated, user.AggregateType, newUser.ID.Hex(), sc.UserID, user.Payload(newUser))
+		e.Location = newUser.Hierarchy().Location()
+		if err := uc.publisher.Publish(ctx, e); err != nil {
+			uc.l.Errorf(ctx, "auth.usecase.Register.publisher.Publish: %v", err)
+			return err
+		}
+		return nil
 	})
 	if err != nil {
-		uc.l.Errorf(ctx, "auth.usecase.Register.CreateUser: %v", err)
-		return auth.RegisterOutput{}, err
+		return auth.RegisterOutput{}, uniqueError(err)
 	}
 
-	// 4. Generate JWT token với role và scope
-	payload := jwt.Payload{
-		UserID:   newUser.ID.Hex(),
-		Username: newUser.Username,
-		Role:     string(newUser.Role),
-		ShopID:   newUser.ShopID.Hex(),
-	}
-	if !newUser.RegionID.IsZero() {
-		payload.RegionID = newUser.RegionID.Hex()
-	}
-	if !newUser.BranchID.IsZero() {
-		payload.BranchID = newUser.BranchID.Hex()
-	}
-
-	token, err := uc.jwtManager.Generate(payload, uc.accessDuration)
+	// 3. Generate JWT token với role và scope
+	token, err := uc.jwtManager.Generate(newPayload(newUser), uc.accessDuration)
 	if err != nil {
 		uc.l.Errorf(ctx, "auth.usecase.Register.jwtManager.Generate: %v", err)
 		return auth.RegisterOutput{}, err
 	}
 
-	// 5. Trả về kết quả
+	// 4. Trả về kết quả
 	return auth.RegisterOutput{
 		ID:       newUser.ID,
 		Username: newUser.Username,
@@ -95,21 +88,13 @@ func (uc *implUsecase) Login(ctx context.Context, sc models.Scope, input auth.Lo
 		return auth.LoginOutput{}, auth.ErrInvalidCredentials
 	}
 
-	// 3. Generate JWT token với role và scope
-	payload := jwt.Payload{
-		UserID:   user.ID.Hex(),
-		Username: user.Username,
-		Role:     string(user.Role),
-		ShopID:   user.ShopID.Hex(),
-	}
-	if !user.RegionID.IsZero() {
-		payload.RegionID = user.RegionID.Hex()
-	}
-	if !user.BranchID.IsZero() {
-		payload.BranchID = user.BranchID.Hex()
+	// 3. User đã bị vô hiệu hóa thì không cho đăng nhập
+	if !user.IsActive() {
+		return auth.LoginOutput{}, auth.ErrUserDeactivated
 	}
 
-	token, err := uc.jwtManager.Generate(payload, uc.accessDuration)
+	// 3. Generate JWT token với role và scope
+	token, err := uc.jwtManager.Generate(newPayload(user), uc.accessDuration)
 	if err != nil {
 		uc.l.Errorf(ctx, "auth.usecase.Login.jwtManager.Generate: %v", err)
 		return auth.LoginOutput{}, err
@@ -125,3 +110,123 @@ func (uc *implUsecase) Login(ctx context.Context, sc models.Scope, input auth.Lo
 		Token:    token,
 	}, nil
 }
+
+// Me lấy profile, tên các cấp tổ chức và quyền hiệu lực của user đang đăng nhập
+func (uc *implUsecase) Me(ctx context.Context, sc models.Scope) (auth.MeOutput, error) {
+	// 1. Lấy user từ scope
+	user, err := uc.getScopeUser(ctx, sc)
+	if err != nil {
+		return auth.MeOutput{}, err
+	}
+
+	// 2. Lấy tên các cấp tổ chức
+	names, err := uc.repo.GetHierarchyNames(ctx, auth.GetHierarchyNamesOptions{
+		ShopID:       user.ShopID,
+		RegionID:     user.RegionID,
+		BranchID:     user.BranchID,
+		DepartmentID: user.DepartmentID,
+	})
+	if err != nil {
+		uc.l.Errorf(ctx, "auth.usecase.Me.GetHierarchyNames: %v", err)
+		return auth.MeOutput{}, err
+	}
+
+	// 3. Trả về kết quả (quyền tính theo role hiện tại trong database, không theo token)
+	return auth.MeOutput{
+		User:           user,
+		ShopName:       names.ShopName,
+		RegionName:     names.RegionName,
+		BranchName:     names.BranchName,
+		DepartmentName: names.DepartmentName,
+		Permissions:    user.Role.Permissions(),
+	}, nil
+}
+
+// ChangePassword đổi mật khẩu của user đang đăng nhập
+func (uc *implUsecase) ChangePassword(ctx context.Context, sc models.Scope, input auth.ChangePasswordInput) error {
+	// 1. Lấy user từ scope
+	user, err := uc.getScopeUser(ctx, sc)
+	if err != nil {
+		return err
+	}
+
+	// 2. Kiểm tra mật khẩu hiện tại
+	err = bcrypt.CompareHashAndPassword([]byte(user.PassWord), []byte(input.CurrentPassword))
+	if err != nil {
+		return auth.ErrWrongCurrentPassword
+	}
+	if input.CurrentPassword == input.NewPassword {
+		return auth.ErrSamePassword
+	}
+
+	// 3. Hash mật khẩu mới
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		uc.l.Errorf(ctx, "auth.usecase.ChangePassword.bcrypt: %v", err)
+		return auth.ErrInvalidPassword
+	}
+
+	// 4. Lưu vào database
+	err = uc.repo.UpdatePassword(ctx, auth.UpdatePasswordOptions{
+		UserID:   user.ID,
+		Password: string(hashedPassword),
+	})
+	if err != nil {
+		uc.l.Errorf(ctx, "auth.usecase.ChangePassword.UpdatePassword: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// getScopeUser lấy user tương ứng với scope của request
+func (uc *implUsecase) getScopeUser(ctx context.Context, sc models.Scope) (models.User, error) {
+	userID, err := primitive.ObjectIDFromHex(sc.UserID)
+	if err != nil {
+		return models.User{}, auth.ErrUserNotFound
+	}
+
+	user, err := uc.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		uc.l.Warnf(ctx, "auth.usecase.getScopeUser.GetUserByID: %v", err)
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+// newPayload tạo JWT payload với role và scope của user
+func newPayload(user models.User) jwt.Payload {
+	payload := jwt.Payload{
+		UserID:   user.ID.Hex(),
+		Username: user.Username,
+		Role:     string(user.Role),
+		ShopID:   user.ShopID.Hex(),
+	}
+	if !user.RegionID.IsZero() {
+		payload.RegionID = user.RegionID.Hex()
+	}
+	if !user.BranchID.IsZero() {
+		payload.BranchID = user.BranchID.Hex()
+	}
+	if user.DepartmentID != nil && !user.DepartmentID.IsZero() {
+		payload.DepartmentID = user.DepartmentID.Hex()
+	}
+
+	return payload
+}
+
+// uniqueError chuyển lỗi vi phạm unique index thành lỗi domain tương ứng
+func uniqueError(err error) error {
+	dup, ok := mongo.AsDuplicateKey(err)
+	if !ok {
+		return err
+	}
+	switch dup.Field() {
+	case "username":
+		return auth.ErrUsernameExists
+	case "email":
+		return auth.ErrEmailExists
+	}
+	return err
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"thuchanhgolang/internal/auth"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/jwt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestMe(t *testing.T) {
+	user := models.User{ID: primitive.NewObjectID(), Username: "employee", Role: models.RoleEmployee, ShopID: primitive.NewObjectID()}
+
+	tests := []struct {
+		name    string
+		userID  string
+		wantErr error
+	}{
+		{name: "user không còn tồn tại", userID: primitive.NewObjectID().Hex(), wantErr: auth.ErrUserNotFound},
+		{name: "UserID trong scope không hợp lệ", userID: "abc", wantErr: auth.ErrUserNotFound},
+		{name: "thành công", userID: user.ID.Hex()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := newTestUsecase(newMockRepository(user), jwt.NewManager("test-secret"))
+
+			out, err := uc.Me(context.Background(), models.Scope{UserID: tt.userID})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Mong đợi lỗi %v, nhận được %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if out.User.ID != user.ID || len(out.Permissions) == 0 {
+				t.Errorf("Mong đợi profile và quyền của user, nhận được %+v", out)
+			}
+		})
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte("old-secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("Không mong đợi lỗi: %v", err)
+	}
+	user := models.User{ID: primitive.NewObjectID(), Username: "employee", PassWord: string(hashed)}
+	errUpdate := errors.New("update failed")
+
+	tests := []struct {
+		name      string
+		input     auth.ChangePasswordInput
+		repoErr   error
+		wantErr   error
+		wantCalls int // Số lần gọi UpdatePassword
+	}{
+		{
+			name:    "mật khẩu hiện tại sai",
+			input:   auth.ChangePasswordInput{CurrentPassword: "wrong", NewPassword: "new-secret"},
+			wantErr: auth.ErrWrongCurrentPassword,
+		},
+		{
+			name:    "mật khẩu mới trùng mật khẩu hiện tại",
+			input:   auth.ChangePasswordInput{CurrentPassword: "old-secret", NewPassword: "old-secret"},
+			wantErr: auth.ErrSamePassword,
+		},
+		{
+			name:      "lỗi khi lưu",
+			input:     auth.ChangePasswordInput{CurrentPassword: "old-secret", NewPassword: "new-secret"},
+			repoErr:   errUpdate,
+			wantErr:   errUpdate,
+			wantCalls: 1,
+		},
+		{
+			name:      "thành công",
+			input:     auth.ChangePasswordInput{CurrentPassword: "old-secret", NewPassword: "new-secret"},
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockRepository(user)
+			repo.updatePasswordErr = tt.repoErr
+			uc := newTestUsecase(repo, jwt.NewManager("test-secret"))
+
+			err := uc.ChangePassword(context.Background(), models.Scope{UserID: user.ID.Hex()}, tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Mong đợi lỗi %v, nhận được %v", tt.wantErr, err)
+			}
+			if repo.updatePasswordCalls != tt.wantCalls {
+				t.Fatalf("Mong đợi UpdatePassword được gọi %d lần, nhận được %d", tt.wantCalls, repo.updatePasswordCalls)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			// Mật khẩu được lưu dưới dạng hash của mật khẩu mới
+			if err := bcrypt.CompareHashAndPassword([]byte(repo.updatedPassword), []byte(tt.input.NewPassword)); err != nil {
+				t.Errorf("Mong đợi lưu hash của mật khẩu mới: %v", err)
+			}
+		})
+	}
+}
+
+func TestNewPayload(t *testing.T) {
+	shopID, regionID, branchID, departmentID := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	zeroID := primitive.NilObjectID
+
+	tests := []struct {
+		name             string
+		user             models.User
+		wantDepartmentID string
+	}{
+		{
+			name:             "head of department mang department_id",
+			user:             models.User{Role: models.RoleHeadOfDepartment, ShopID: shopID, RegionID: regionID, BranchID: branchID, DepartmentID: &departmentID},
+			wantDepartmentID: departmentID.Hex(),
+		},
+		{
+			name: "không thuộc department",
+			user: models.User{Role: models.RoleBranchManager, ShopID: shopID, RegionID: regionID, BranchID: branchID},
+		},
+		{
+			name: "department_id rỗng",
+			user: models.User{Role: models.RoleBranchManager, ShopID: shopID, RegionID: regionID, BranchID: branchID, DepartmentID: &zeroID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.user.ID = primitive.NewObjectID()
+			payload := newPayload(tt.user)
+
+			if payload.DepartmentID != tt.wantDepartmentID {
+				t.Errorf("Mong đợi department_id %q, nhận được %q", tt.wantDepartmentID, payload.DepartmentID)
+			}
+			if payload.ShopID != shopID.Hex() || payload.RegionID != regionID.Hex() || payload.BranchID != branchID.Hex() {
+				t.Errorf("Payload không mang đủ hierarchy: %+v", payload)
+			}
+
+			// Scope dựng từ payload phải giữ department của user
+			sc := jwt.NewScope(payload)
+			switch {
+			case tt.wantDepartmentID == "" && sc.DepartmentID != nil:
+				t.Errorf("Mong đợi scope không có department, nhận được %s", sc.DepartmentID.Hex())
+			case tt.wantDepartmentID != "" && (sc.DepartmentID == nil || *sc.DepartmentID != departmentID):
+				t.Errorf("Mong đợi scope có department %s, nhận được %v", departmentID.Hex(), sc.DepartmentID)
+			}
+			if sc.UserID != tt.user.ID.Hex() || sc.Role != tt.user.Role {
+				t.Errorf("Scope không khớp user: %+v", sc)
+			}
+		})
+	}
+}
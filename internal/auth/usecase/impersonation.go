@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"thuchanhgolang/internal/auth"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/jwt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Impersonate cấp token ngắn hạn để Manager xem hệ thống dưới danh nghĩa một user trong scope
+func (uc *implUsecase) Impersonate(ctx context.Context, sc models.Scope, input auth.ImpersonateInput) (auth.ImpersonateOutput, error) {
+	// 1. Chỉ Manager dùng token của chính mình mới được impersonate (không lồng nhau)
+	if sc.Role != models.RoleManager || sc.IsImpersonated() {
+		uc.l.Warnf(ctx, "auth.usecase.Impersonate: role %s not allowed (actor=%q)", sc.Role, sc.ActorID)
+		return auth.ImpersonateOutput{}, auth.ErrImpersonationForbidden
+	}
+
+	// 2. Lấy actor và target
+	actor, err := uc.getScopeUser(ctx, sc)
+	if err != nil {
+		return auth.ImpersonateOutput{}, err
+	}
+	if actor.ID == input.UserID {
+		return auth.ImpersonateOutput{}, auth.ErrImpersonationForbidden
+	}
+
+	target, err := uc.repo.GetUserByID(ctx, input.UserID)
+	if err != nil {
+		uc.l.Warnf(ctx, "auth.usecase.Impersonate.GetUserByID: %v", err)
+		return auth.ImpersonateOutput{}, err
+	}
+	if !target.IsActive() {
+		return auth.ImpersonateOutput{}, auth.ErrUserDeactivated
+	}
+
+	// 3. Target phải nằm trong scope hiện tại (lấy từ database) của actor,
+	// nhờ đó token impersonation không bao giờ rộng hơn quyền của admin
+	actorScope := models.Scope{
+		UserID: actor.ID.Hex(),
+		Role:   actor.Role,
+		ShopID: &actor.ShopID,
+	}
+	if actor.Role != models.RoleManager || !actorScope.Contains(target.Hierarchy()) {
+		uc.l.Warnf(ctx, "auth.usecase.Impersonate: user %s outside scope of %s", target.ID.Hex(), actor.ID.Hex())
+		return auth.ImpersonateOutput{}, auth.ErrImpersonationOutOfScope
+	}
+
+	// 4. Ghi audit log
+	now := time.Now()
+	expiresAt := now.Add(uc.impersonationDuration)
+	impLog, err := uc.repo.CreateImpersonationLog(ctx, auth.CreateImpersonationLogOptions{
+		Actor:      actor,
+		Target:     target,
+		AllowWrite: input.AllowWrite,
+		Reason:     input.Reason,
+		StartedAt:  now,
+		ExpiresAt:  expiresAt,
+	})
+	if err != nil {
+		uc.l.Errorf(ctx, "auth.usecase.Impersonate.CreateImpersonationLog: %v", err)
+		return auth.ImpersonateOutput{}, err
+	}
+
+	// 5. Generate token của target kèm claim "act"
+	payload := newPayload(target)
+	payload.Actor = &jwt.Actor{
+		UserID:          actor.ID.Hex(),
+		Username:        actor.Username,
+		ImpersonationID: impLog.ID.Hex(),
+		AllowWrite:      input.AllowWrite,
+	}
+	token, err := uc.jwtManager.Generate(payload, uc.impersonationDuration)
+	if err != nil {
+		uc.l.Errorf(ctx, "auth.usecase.Impersonate.jwtManager.Generate: %v", err)
+		return auth.ImpersonateOutput{}, err
+	}
+
+	return auth.ImpersonateOutput{
+		ImpersonationID: impLog.ID,
+		Target:          target,
+		AllowWrite:      input.AllowWrite,
+		ExpiresAt:       expiresAt,
+		Token:           token,
+	}, nil
+}
+
+// EndImpersonation kết thúc phiên impersonation do chính admin đã tạo
+func (uc *implUsecase) EndImpersonation(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+	// Phải gọi bằng token của chính admin, không phải token impersonation
+	if sc.IsImpersonated() {
+		return auth.ErrImpersonationForbidden
+	}
+
+	actorID, err := primitive.ObjectIDFromHex(sc.UserID)
+	if err != nil {
+		return auth.ErrImpersonationNotFound
+	}
+
+	err = uc.repo.EndImpersonationLog(ctx, auth.EndImpersonationLogOptions{
+		ID:      id,
+		ActorID: actorID,
+		EndedAt: time.Now(),
+	})
+	if err != nil {
+		uc.l.Warnf(ctx, "auth.usecase.EndImpersonation.EndImpersonationLog: %v", err)
+		return err
+	}
+
+	// Instance này từ chối token ngay, các instance khác biết sau tối đa impersonationCacheTTL
+	uc.sessions.end(id.Hex())
+
+	return nil
+}
+
+// CheckImpersonation kiểm tra phiên impersonation của token chưa bị kết thúc và chưa hết hạn.
+// Token impersonation vẫn hợp lệ về chữ ký sau khi admin kết thúc phiên nên phải đối chiếu với audit log.
+func (uc *implUsecase) CheckImpersonation(ctx context.Context, id string) error {
+	now := time.Now()
+	if ended, ok := uc.sessions.get(id, now); ok {
+		if ended {
+			return auth.ErrImpersonationEnded
+		}
+		return nil
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return auth.ErrImpersonationEnded
+	}
+
+	impLog, err := uc.repo.GetImpersonationLog(ctx, objectID)
+	if err != nil {
+		if errors.Is(err, auth.ErrImpersonationNotFound) {
+			return auth.ErrImpersonationEnded
+		}
+		uc.l.Errorf(ctx, "auth.usecase.CheckImpersonation.GetImpersonationLog: %v", err)
+		return err
+	}
+
+	ended := impLog.EndedAt != nil || !now.Before(impLog.ExpiresAt)
+	uc.sessions.set(id, ended, impLog.ExpiresAt, now)
+	if ended {
+		return auth.ErrImpersonationEnded
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"thuchanhgolang/internal/auth"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/jwt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// impersonationFixture là admin của shop cùng một nhân viên trong shop và một nhân viên shop khác
+type impersonationFixture struct {
+	manager  models.User
+	employee models.User
+	stranger models.User
+	sc       models.Scope
+}
+
+func newImpersonationFixture() impersonationFixture {
+	shopID := primitive.NewObjectID()
+	f := impersonationFixture{
+		manager:  models.User{ID: primitive.NewObjectID(), Username: "manager", Role: models.RoleManager, ShopID: shopID},
+		employee: models.User{ID: primitive.NewObjectID(), Username: "employee", Role: models.RoleEmployee, ShopID: shopID, RegionID: primitive.NewObjectID(), BranchID: primitive.NewObjectID()},
+		stranger: models.User{ID: primitive.NewObjectID(), Username: "stranger", Role: models.RoleEmployee, ShopID: primitive.NewObjectID()},
+	}
+	f.sc = models.Scope{UserID: f.manager.ID.Hex(), Role: models.RoleManager, ShopID: &shopID}
+	return f
+}
+
+func TestImpersonate(t *testing.T) {
+	f := newImpersonationFixture()
+	jwtMgr := jwt.NewManager("test-secret")
+
+	t.Run("token carries target scope and actor", func(t *testing.T) {
+		repo := newMockRepository(f.manager, f.employee)
+		uc := newTestUsecase(repo, jwtMgr)
+
+		out, err := uc.Impersonate(context.Background(), f.sc, auth.ImpersonateInput{UserID: f.employee.ID, Reason: "support"})
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+
+		payload, err := jwtMgr.Verify(out.Token)
+		if err != nil {
+			t.Fatalf("Token không hợp lệ: %v", err)
+		}
+		if payload.UserID != f.employee.ID.Hex() || payload.Role != string(models.RoleEmployee) || payload.BranchID != f.employee.BranchID.Hex() {
+			t.Errorf("Mong đợi token mang scope của employee, nhận được %+v", payload)
+		}
+		if payload.Actor == nil || payload.Actor.UserID != f.manager.ID.Hex() || payload.Actor.ImpersonationID != out.ImpersonationID.Hex() {
+			t.Errorf("Mong đợi claim act của manager, nhận được %+v", payload.Actor)
+		}
+		if payload.Actor.AllowWrite {
+			t.Errorf("Token mặc định chỉ được đọc")
+		}
+		if _, ok := repo.logs[out.ImpersonationID]; !ok {
+			t.Errorf("Mong đợi ghi audit log")
+		}
+	})
+
+	tests := []struct {
+		name    string
+		sc      func() models.Scope
+		target  primitive.ObjectID
+		wantErr error
+	}{
+		{
+			name: "non manager is forbidden",
+			sc: func() models.Scope {
+				sc := f.sc
+				sc.Role = models.RoleBranchManager
+				return sc
+			},
+			target:  f.employee.ID,
+			wantErr: auth.ErrImpersonationForbidden,
+		},
+		{
+			name: "nested impersonation is forbidden",
+			sc: func() models.Scope {
+				sc := f.sc
+				sc.ActorID = primitive.NewObjectID().Hex()
+				return sc
+			},
+			target:  f.employee.ID,
+			wantErr: auth.ErrImpersonationForbidden,
+		},
+		{
+			name:    "self is forbidden",
+			sc:      func() models.Scope { return f.sc },
+			target:  f.manager.ID,
+			wantErr: auth.ErrImpersonationForbidden,
+		},
+		{
+			name:    "target in other shop is out of scope",
+			sc:      func() models.Scope { return f.sc },
+			target:  f.stranger.ID,
+			wantErr: auth.ErrImpersonationOutOfScope,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockRepository(f.manager, f.employee, f.stranger)
+			uc := newTestUsecase(repo, jwtMgr)
+
+			_, err := uc.Impersonate(context.Background(), tt.sc(), auth.ImpersonateInput{UserID: tt.target})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Mong đợi %v, nhận được %v", tt.wantErr, err)
+			}
+			if repo.createLogCalls != 0 {
+				t.Errorf("Không được ghi audit log khi bị từ chối")
+			}
+		})
+	}
+
+	t.Run("demoted admin is out of scope", func(t *testing.T) {
+		demoted := f.manager
+		demoted.Role = models.RoleRegionManager
+		repo := newMockRepository(demoted, f.employee)
+
+		// Token cũ vẫn ghi role manager nhưng quyền được lấy lại từ database
+		_, err := newTestUsecase(repo, jwtMgr).Impersonate(context.Background(), f.sc, auth.ImpersonateInput{UserID: f.employee.ID})
+
+		if !errors.Is(err, auth.ErrImpersonationOutOfScope) {
+			t.Errorf("Mong đợi ErrImpersonationOutOfScope, nhận được %v", err)
+		}
+	})
+}
+
+func TestEndImpersonation(t *testing.T) {
+	f := newImpersonationFixture()
+	jwtMgr := jwt.NewManager("test-secret")
+
+	start := func(t *testing.T) (*mockRepository, *implUsecase, primitive.ObjectID) {
+		t.Helper()
+		repo := newMockRepository(f.manager, f.employee)
+		uc := newTestUsecase(repo, jwtMgr)
+		out, err := uc.Impersonate(context.Background(), f.sc, auth.ImpersonateInput{UserID: f.employee.ID})
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		return repo, uc, out.ImpersonationID
+	}
+
+	t.Run("ended session is rejected immediately", func(t *testing.T) {
+		_, uc, id := start(t)
+		ctx := context.Background()
+
+		if err := uc.CheckImpersonation(ctx, id.Hex()); err != nil {
+			t.Fatalf("Phiên vừa tạo phải còn hiệu lực: %v", err)
+		}
+		if err := uc.EndImpersonation(ctx, f.sc, id); err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+
+		// Kết quả "còn hiệu lực" đã cache không được dùng lại sau khi kết thúc
+		if err := uc.CheckImpersonation(ctx, id.Hex()); !errors.Is(err, auth.ErrImpersonationEnded) {
+			t.Errorf("Mong đợi ErrImpersonationEnded, nhận được %v", err)
+		}
+	})
+
+	t.Run("impersonation token cannot end session", func(t *testing.T) {
+		_, uc, id := start(t)
+		sc := models.Scope{UserID: f.employee.ID.Hex(), Role: models.RoleEmployee, ActorID: f.manager.ID.Hex(), ImpersonationID: id.Hex()}
+
+		err := uc.EndImpersonation(context.Background(), sc, id)
+
+		if !errors.Is(err, auth.ErrImpersonationForbidden) {
+			t.Errorf("Mong đợi ErrImpersonationForbidden, nhận được %v", err)
+		}
+	})
+
+	t.Run("other admin cannot end session", func(t *testing.T) {
+		_, uc, id := start(t)
+		sc := f.sc
+		sc.UserID = primitive.NewObjectID().Hex()
+
+		err := uc.EndImpersonation(context.Background(), sc, id)
+
+		if !errors.Is(err, auth.ErrImpersonationNotFound) {
+			t.Errorf("Mong đợi ErrImpersonationNotFound, nhận được %v", err)
+		}
+		if err := uc.CheckImpersonation(context.Background(), id.Hex()); err != nil {
+			t.Errorf("Phiên phải còn hiệu lực, nhận được %v", err)
+		}
+	})
+}
+
+func TestCheckImpersonation(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	endedAt := now.Add(-time.Minute)
+	active := models.ImpersonationLog{ID: primitive.NewObjectID(), StartedAt: now, ExpiresAt: now.Add(time.Hour)}
+	ended := models.ImpersonationLog{ID: primitive.NewObjectID(), StartedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour), EndedAt: &endedAt}
+	expired := models.ImpersonationLog{ID: primitive.NewObjectID(), StartedAt: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Minute)}
+
+	newRepo := func() *mockRepository {
+		repo := newMockRepository()
+		for _, l := range []models.ImpersonationLog{active, ended, expired} {
+			repo.logs[l.ID] = l
+		}
+		return repo
+	}
+
+	tests := []struct {
+		name    string
+		id      string
+		wantErr error
+	}{
+		{name: "active session", id: active.ID.Hex()},
+		{name: "ended session", id: ended.ID.Hex(), wantErr: auth.ErrImpersonationEnded},
+		{name: "expired session", id: expired.ID.Hex(), wantErr: auth.ErrImpersonationEnded},
+		{name: "unknown session", id: primitive.NewObjectID().Hex(), wantErr: auth.ErrImpersonationEnded},
+		{name: "invalid id", id: "imp1", wantErr: auth.ErrImpersonationEnded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := newTestUsecase(newRepo(), nil)
+
+			err := uc.CheckImpersonation(ctx, tt.id)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Mong đợi %v, nhận được %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("result is cached", func(t *testing.T) {
+		repo := newRepo()
+		uc := newTestUsecase(repo, nil)
+
+		for i := 0; i < 3; i++ {
+			if err := uc.CheckImpersonation(ctx, active.ID.Hex()); err != nil {
+				t.Fatalf("Không mong đợi lỗi: %v", err)
+			}
+			if err := uc.CheckImpersonation(ctx, ended.ID.Hex()); !errors.Is(err, auth.ErrImpersonationEnded) {
+				t.Fatalf("Mong đợi ErrImpersonationEnded, nhận được %v", err)
+			}
+		}
+		if repo.getLogCalls != 2 {
+			t.Errorf("Mong đợi đọc audit log 2 lần, nhận được %d", repo.getLogCalls)
+		}
+	})
+
+	t.Run("lookup error is returned and not cached", func(t *testing.T) {
+		repo := newRepo()
+		repo.getLogErr = errors.New("db down")
+		uc := newTestUsecase(repo, nil)
+
+		if err := uc.CheckImpersonation(ctx, active.ID.Hex()); err == nil || errors.Is(err, auth.ErrImpersonationEnded) {
+			t.Fatalf("Mong đợi lỗi database, nhận được %v", err)
+		}
+
+		repo.getLogErr = nil
+		if err := uc.CheckImpersonation(ctx, active.ID.Hex()); err != nil {
+			t.Errorf("Mong đợi đọc lại audit log sau lỗi, nhận được %v", err)
+		}
+	})
+}
@@ -4,24 +4,35 @@ import (
 	"time"
 
 	"thuchanhgolang/internal/auth"
+	"thuchanhgolang/pkg/event"
 	"thuchanhgolang/pkg/jwt"
 	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/mongo"
 )
 
 // implUsecase là implementation của auth.Usecase
 type implUsecase struct {
-	l              log.Logger      // Logger
-	repo           auth.Repository // Auth repository
-	jwtManager     jwt.Manager     // JWT manager
-	accessDuration time.Duration   // Access token duration
+	l              log.Logger       // Logger
+	repo           auth.Repository  // Auth repository
+	jwtManager     jwt.Manager      // JWT manager
+	accessDuration time.Duration    // Access token duration
+	tx             mongo.Transactor // Transaction khi tạo user cùng event
+	publisher      event.Publisher  // Ghi event vào outbox khi user được tạo
+
+	impersonationDuration time.Duration       // Impersonation token duration
+	sessions              *impersonationCache // Cache trạng thái phiên impersonation cho mỗi request dùng token impersonation
 }
 
 // NewUsecase tạo auth usecase mới
-func NewUsecase(l log.Logger, repo auth.Repository, jwtManager jwt.Manager, accessDuration time.Duration) auth.Usecase {
+func NewUsecase(l log.Logger, repo auth.Repository, jwtManager jwt.Manager, accessDuration, impersonationDuration time.Duration, tx mongo.Transactor, publisher event.Publisher) auth.Usecase {
 	return &implUsecase{
-		l:              l,
-		repo:           repo,
-		jwtManager:     jwtManager,
-		accessDuration: accessDuration,
+		l:                     l,
+		repo:                  repo,
+		jwtManager:            jwtManager,
+		accessDuration:        accessDuration,
+		impersonationDuration: impersonationDuration,
+		tx:                    tx,
+		publisher:             publisher,
+		sessions:              newImpersonationCache(),
 	}
 }
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+)
+
+// impersonationCacheTTL là thời gian giữ kết quả "phiên còn hiệu lực" trước khi đọc lại audit log,
+// cũng là độ trễ tối đa để instance khác từ chối token sau khi phiên bị kết thúc
+const impersonationCacheTTL = 10 * time.Second
+
+// impersonationCache lưu trạng thái phiên impersonation để không phải query database ở mỗi request.
+// Phiên đã kết thúc không thể mở lại nên được giữ tới khi token hết hạn.
+type impersonationCache struct {
+	mu      sync.Mutex
+	entries map[string]impersonationEntry
+}
+
+type impersonationEntry struct {
+	ended bool
+	until time.Time // Hết hạn cache
+}
+
+func newImpersonationCache() *impersonationCache {
+	return &impersonationCache{entries: make(map[string]impersonationEntry)}
+}
+
+// get trả về trạng thái đã cache của phiên, ok = false khi chưa có hoặc đã hết hạn cache
+func (c *impersonationCache) get(id string, now time.Time) (ended bool, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[id]
+	if !ok || !now.Before(e.until) {
+		return false, false
+	}
+	return e.ended, true
+}
+
+// set ghi trạng thái phiên đọc từ audit log, expiresAt là lúc token impersonation hết hạn
+func (c *impersonationCache) set(id string, ended bool, expiresAt, now time.Time) {
+	until := expiresAt
+	if !ended && now.Add(impersonationCacheTTL).Before(until) {
+		until = now.Add(impersonationCacheTTL)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Dọn các entry đã hết hạn, số phiên impersonation cùng lúc nhỏ nên duyệt cả map là đủ
+	for k, e := range c.entries {
+		if !now.Before(e.until) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[id] = impersonationEntry{ended: ended, until: until}
+}
+
+// end đánh dấu phiên đã kết thúc ngay khi admin gọi EndImpersonation
+func (c *impersonationCache) end(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Không biết thời điểm token hết hạn, giữ đủ lâu để entry "còn hiệu lực" cũ không được dùng lại
+	c.entries[id] = impersonationEntry{ended: true, until: time.Now().Add(impersonationCacheTTL)}
+}
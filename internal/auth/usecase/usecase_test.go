@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"thuchanhgolang/internal/auth"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/event"
+	"thuchanhgolang/pkg/jwt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// mockRepository giữ user và audit log impersonation trong bộ nhớ
+type mockRepository struct {
+	users          map[primitive.ObjectID]models.User
+	logs           map[primitive.ObjectID]models.ImpersonationLog
+	getLogCalls    int
+	getLogErr      error
+	createLogCalls int
+
+	updatePasswordErr   error
+	updatePasswordCalls int
+	updatedPassword     string // Mật khẩu đã hash của lần UpdatePassword gần nhất
+}
+
+func newMockRepository(users ...models.User) *mockRepository {
+	repo := &mockRepository{
+		users: make(map[primitive.ObjectID]models.User),
+		logs:  make(map[primitive.ObjectID]models.ImpersonationLog),
+	}
+	for _, u := range users {
+		repo.users[u.ID] = u
+	}
+	return repo
+}
+
+func (m *mockRepository) CreateUser(ctx context.Context, opts auth.CreateUserOptions) (models.User, error) {
+	return models.User{}, nil
+}
+
+func (m *mockRepository) GetUserByUsername(ctx context.Context, opts auth.GetUserOptions) (models.User, error) {
+	return models.User{}, auth.ErrUserNotFound
+}
+
+func (m *mockRepository) GetUserByID(ctx context.Context, id primitive.ObjectID) (models.User, error) {
+	u, ok := m.users[id]
+	if !ok {
+		return models.User{}, auth.ErrUserNotFound
+	}
+	return u, nil
+}
+
+func (m *mockRepository) UpdatePassword(ctx context.Context, opts auth.UpdatePasswordOptions) error {
+	m.updatePasswordCalls++
+	if m.updatePasswordErr != nil {
+		return m.updatePasswordErr
+	}
+	m.updatedPassword = opts.Password
+	return nil
+}
+
+func (m *mockRepository) GetHierarchyNames(ctx context.Context, opts auth.GetHierarchyNamesOptions) (auth.HierarchyNames, error) {
+	return auth.HierarchyNames{}, nil
+}
+
+func (m *mockRepository) CreateImpersonationLog(ctx context.Context, opts auth.CreateImpersonationLogOptions) (models.ImpersonationLog, error) {
+	m.createLogCalls++
+	impLog := models.ImpersonationLog{
+		ID:           primitive.NewObjectID(),
+		ActorID:      opts.Actor.ID,
+		TargetUserID: opts.Target.ID,
+		AllowWrite:   opts.AllowWrite,
+		StartedAt:    opts.StartedAt,
+		ExpiresAt:    opts.ExpiresAt,
+	}
+	m.logs[impLog.ID] = impLog
+	return impLog, nil
+}
+
+func (m *mockRepository) EndImpersonationLog(ctx context.Context, opts auth.EndImpersonationLogOptions) error {
+	impLog, ok := m.logs[opts.ID]
+	if !ok || impLog.ActorID != opts.ActorID || impLog.EndedAt != nil {
+		return auth.ErrImpersonationNotFound
+	}
+	endedAt := opts.EndedAt
+	impLog.EndedAt = &endedAt
+	m.logs[opts.ID] = impLog
+	return nil
+}
+
+func (m *mockRepository) GetImpersonationLog(ctx context.Context, id primitive.ObjectID) (models.ImpersonationLog, error) {
+	m.getLogCalls++
+	if m.getLogErr != nil {
+		return models.ImpersonationLog{}, m.getLogErr
+	}
+	impLog, ok := m.logs[id]
+	if !ok {
+		return models.ImpersonationLog{}, auth.ErrImpersonationNotFound
+	}
+	return impLog, nil
+}
+
+type mockTransactor struct{}
+
+func (m *mockTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// mockLogger là mock logger cho testing
+type mockLogger struct{}
+
+func (m *mockLogger) Debug(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Debugf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Info(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Infof(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Warn(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Warnf(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Error(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Errorf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Fatal(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Fatalf(ctx context.Context, template string, arg ...any) {}
+
+func newTestUsecase(repo *mockRepository, jwtMgr jwt.Manager) *implUsecase {
+	return &implUsecase{
+		l:                     &mockLogger{},
+		repo:                  repo,
+		jwtManager:            jwtMgr,
+		accessDuration:        time.Hour,
+		impersonationDuration: 15 * time.Minute,
+		tx:                    &mockTransactor{},
+		publisher:             event.NewMemoryPublisher(),
+		sessions:              newImpersonationCache(),
+	}
+}
@@ -0,0 +1,100 @@
+package grpc
+
+import (
+	"context"
+
+	"thuchanhgolang/pkg/orgapi"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Get lấy branch theo ID
+func (h handler) Get(ctx context.Context, req *orgapi.GetRequest) (*orgapi.Branch, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để lấy branch
+	id, _ := primitive.ObjectIDFromHex(req.ID)
+	branch, err := h.uc.GetByID(ctx, sc, id)
+	if err != nil {
+		h.l.Warnf(ctx, "branch.grpc.Get.uc.GetByID: %s", err)
+		return nil, err
+	}
+
+	return h.newBranch(branch), nil
+}
+
+// List lấy danh sách branch có phân trang
+func (h handler) List(ctx context.Context, req *orgapi.ListBranchesRequest) (*orgapi.ListBranchesResponse, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để lấy danh sách
+	out, err := h.uc.List(ctx, sc, h.toListInput(req))
+	if err != nil {
+		h.l.Warnf(ctx, "branch.grpc.List.uc.List: %s", err)
+		return nil, err
+	}
+
+	return h.newListResp(out), nil
+}
+
+// Create tạo branch mới
+func (h handler) Create(ctx context.Context, req *orgapi.CreateBranchRequest) (*orgapi.Branch, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để tạo branch
+	branch, err := h.uc.Create(ctx, sc, h.toCreateInput(req))
+	if err != nil {
+		h.l.Warnf(ctx, "branch.grpc.Create.uc.Create: %s", err)
+		return nil, err
+	}
+
+	return h.newBranch(branch), nil
+}
+
+// Update cập nhật branch, chỉ khi branch chưa bị sửa sau version client đã đọc
+func (h handler) Update(ctx context.Context, req *orgapi.UpdateBranchRequest) (*orgapi.Branch, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để cập nhật branch
+	branch, err := h.uc.Update(ctx, sc, h.toUpdateInput(req))
+	if err != nil {
+		h.l.Warnf(ctx, "branch.grpc.Update.uc.Update: %s", err)
+		return nil, err
+	}
+
+	return h.newBranch(branch), nil
+}
+
+// Delete xóa branch, chỉ khi branch chưa bị sửa sau version client đã đọc
+func (h handler) Delete(ctx context.Context, req *orgapi.DeleteRequest) (*orgapi.Empty, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để xóa branch
+	id, _ := primitive.ObjectIDFromHex(req.ID)
+	if err := h.uc.Delete(ctx, sc, id, req.Version); err != nil {
+		h.l.Warnf(ctx, "branch.grpc.Delete.uc.Delete: %s", err)
+		return nil, err
+	}
+
+	return &orgapi.Empty{}, nil
+}
@@ -0,0 +1,21 @@
+package grpc
+
+import (
+	"thuchanhgolang/internal/branch"
+	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/orgapi"
+)
+
+// handler là implementation của orgapi.BranchServiceServer
+type handler struct {
+	l  log.Logger     // Logger để ghi log
+	uc branch.Usecase // Usecase để xử lý business logic
+}
+
+// New tạo gRPC service cho branch, dùng chung usecase với REST
+func New(l log.Logger, uc branch.Usecase) orgapi.BranchServiceServer {
+	return handler{
+		l:  l,
+		uc: uc,
+	}
+}
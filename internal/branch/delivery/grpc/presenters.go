@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"thuchanhgolang/internal/branch"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/orgapi"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// toCreateInput chuyển đổi request thành input cho usecase, định dạng đã được kiểm tra qua binding tag
+func (h handler) toCreateInput(req *orgapi.CreateBranchRequest) branch.CreateInput {
+	regionID, _ := primitive.ObjectIDFromHex(req.RegionID)
+	return branch.CreateInput{
+		RegionID: regionID,
+		Name:     req.Name,
+	}
+}
+
+// toUpdateInput chuyển đổi request thành input cho usecase
+func (h handler) toUpdateInput(req *orgapi.UpdateBranchRequest) branch.UpdateInput {
+	id, _ := primitive.ObjectIDFromHex(req.ID)
+	return branch.UpdateInput{
+		ID:      id,
+		Name:    req.Name,
+		Version: req.Version,
+	}
+}
+
+// toListInput chuyển đổi request thành input cho usecase
+func (h handler) toListInput(req *orgapi.ListBranchesRequest) branch.ListInput {
+	input := branch.ListInput{
+		Pagin: paginator.PaginatorQuery{Page: req.Page.Page, Limit: req.Page.Limit},
+	}
+	if req.RegionID != "" {
+		regionID, _ := primitive.ObjectIDFromHex(req.RegionID)
+		input.RegionID = &regionID
+	}
+	return input
+}
+
+// newBranch tạo message từ branch model
+func (h handler) newBranch(d models.Branch) *orgapi.Branch {
+	return &orgapi.Branch{
+		ID:        d.ID.Hex(),
+		RegionID:  d.RegionID.Hex(),
+		Name:      d.Name,
+		Version:   d.Version,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+		CreatedBy: d.CreatedBy,
+		UpdatedBy: d.UpdatedBy,
+	}
+}
+
+// newListResp tạo message từ danh sách branch
+func (h handler) newListResp(out branch.ListOutput) *orgapi.ListBranchesResponse {
+	items := make([]orgapi.Branch, 0, len(out.Branches))
+	for _, d := range out.Branches {
+		items = append(items, *h.newBranch(d))
+	}
+	return &orgapi.ListBranchesResponse{
+		Items: items,
+		Meta:  newPageInfo(out.Pagin),
+	}
+}
+
+// newPageInfo tạo thông tin phân trang
+func newPageInfo(p paginator.Paginator) orgapi.PageInfo {
+	resp := p.ToResponse()
+	return orgapi.PageInfo{
+		Total:       resp.Total,
+		Count:       resp.Count,
+		PerPage:     resp.PerPage,
+		CurrentPage: resp.CurrentPage,
+		TotalPages:  resp.TotalPages,
+	}
+}
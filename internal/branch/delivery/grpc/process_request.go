@@ -0,0 +1,31 @@
+package grpc
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/jwt"
+)
+
+// processScope lấy scope của user đang đăng nhập và kiểm tra quyền như middleware CheckBranchAccess của REST:
+// Manager, RegionManager hoặc BranchManager, BranchManager chỉ với branch của mình khi có id
+func (h handler) processScope(ctx context.Context, id string) (models.Scope, error) {
+	payload, ok := jwt.GetPayloadFromContext(ctx)
+	if !ok {
+		return models.Scope{}, errUnauthorized
+	}
+
+	role := models.Role(payload.Role)
+	switch role {
+	case models.RoleManager, models.RoleRegionManager, models.RoleBranchManager:
+	default:
+		h.l.Warnf(ctx, "branch.grpc.processScope: user role %s not allowed", role)
+		return models.Scope{}, errForbidden
+	}
+	if role == models.RoleBranchManager && id != "" && payload.BranchID != id {
+		h.l.Warnf(ctx, "branch.grpc.processScope: branch_id mismatch")
+		return models.Scope{}, errForbidden
+	}
+
+	return jwt.NewScope(payload), nil
+}
@@ -2,15 +2,26 @@ package http
 
 import (
 	"errors"
+	"net/http"
 	"thuchanhgolang/internal/branch"
 	pkgErrors "thuchanhgolang/pkg/errors"
 )
 
 var (
-	errWrongBody       = pkgErrors.NewHTTPError(10000, "Wrong body")
-	errInvalidID       = pkgErrors.NewHTTPError(10001, "Invalid branch ID")
-	errInvalidRegionID = pkgErrors.NewHTTPError(10002, "Invalid region ID")
-	errBranchInUse     = pkgErrors.NewHTTPError(10004, "branch is being used by departments, cannot delete")
+	errWrongBody       = pkgErrors.NewHTTPError(12000, "Wrong body")
+	errInvalidID       = pkgErrors.NewHTTPError(12001, "Invalid branch ID")
+	errInvalidRegionID = pkgErrors.NewHTTPError(12002, "Invalid region ID")
+	errBranchInUse     = pkgErrors.NewHTTPErrorWithStatus(12004, "branch is being used by departments, cannot delete", http.StatusConflict)
+	errBranchNotFound  = pkgErrors.NewHTTPErrorWithStatus(12005, "Branch not found", http.StatusNotFound)
+	errRegionNotFound  = pkgErrors.NewHTTPErrorWithStatus(12006, "Target region not found", http.StatusNotFound)
+	errDifferentShop   = pkgErrors.NewHTTPErrorWithStatus(12007, "Target region belongs to another shop", http.StatusUnprocessableEntity)
+	errMoveOutOfScope  = pkgErrors.NewHTTPErrorWithStatus(12008, "Branch or target region is outside of your scope", http.StatusForbidden)
+	errUnauthorized    = pkgErrors.NewUnauthorizedHTTPError()
+
+	errVersionMismatch      = pkgErrors.NewHTTPErrorWithStatus(12009, "Branch has been modified by another request, reload and try again", http.StatusPreconditionFailed)
+	errPreconditionRequired = pkgErrors.NewHTTPErrorWithStatus(12010, "If-Match header with the branch ETag is required", http.StatusPreconditionRequired)
+
+	errWrongQuery = pkgErrors.NewHTTPError(12011, "Wrong query")
 )
 
 func (h handler) mapError(err error) error {
@@ -18,5 +29,20 @@ func (h handler) mapError(err error) error {
 	if errors.Is(err, branch.ErrBranchInUse) {
 		return errBranchInUse
 	}
+	if errors.Is(err, branch.ErrBranchNotFound) {
+		return errBranchNotFound
+	}
+	if errors.Is(err, branch.ErrTargetRegionNotFound) {
+		return errRegionNotFound
+	}
+	if errors.Is(err, branch.ErrDifferentShop) {
+		return errDifferentShop
+	}
+	if errors.Is(err, branch.ErrMoveOutOfScope) {
+		return errMoveOutOfScope
+	}
+	if errors.Is(err, branch.ErrVersionMismatch) {
+		return errVersionMismatch
+	}
 	return err
 }
@@ -2,6 +2,7 @@ package http
 
 import (
 	"thuchanhgolang/internal/branch"
+	"thuchanhgolang/internal/cascade"
 	"thuchanhgolang/pkg/log"
 
 	"github.com/gin-gonic/gin"
@@ -9,22 +10,28 @@ import (
 
 // handler là implementation của Handler interface
 type handler struct {
-	l  log.Logger     // Logger để ghi log
-	uc branch.Usecase // Usecase để xử lý business logic
+	l         log.Logger      // Logger để ghi log
+	uc        branch.Usecase  // Usecase để xử lý business logic
+	cascadeUC cascade.Usecase // Usecase xóa dây chuyền
 }
 
 // Handler định nghĩa interface cho HTTP handler
 type Handler interface {
 	create(c *gin.Context)
 	getByID(c *gin.Context)
+	history(c *gin.Context)
+	list(c *gin.Context)
 	update(c *gin.Context)
+	patch(c *gin.Context)
 	delete(c *gin.Context)
+	move(c *gin.Context)
 }
 
 // New tạo HTTP handler mới cho region
-func New(l log.Logger, uc branch.Usecase) Handler {
+func New(l log.Logger, uc branch.Usecase, cascadeUC cascade.Usecase) Handler {
 	return handler{
-		l:  l,
-		uc: uc,
+		l:         l,
+		uc:        uc,
+		cascadeUC: cascadeUC,
 	}
 }
@@ -1,30 +1,22 @@
 package http
 
 import (
-	"strings"
+	"errors"
 	"thuchanhgolang/internal/branch"
 	"thuchanhgolang/internal/models"
+	pkgErrors "thuchanhgolang/pkg/errors"
+	"thuchanhgolang/pkg/paginator"
+	"thuchanhgolang/pkg/patch"
+	"thuchanhgolang/pkg/response"
+	"thuchanhgolang/pkg/util"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // createReq là cấu trúc nhận dữ liệu từ HTTP request
 type createReq struct {
-	RegionID string `json:"region_id" binding:"required"` // ID của region (bắt buộc)
-	Name     string `json:"name" binding:"required"`      // Tên branch (bắt buộc)
-}
-
-// validate kiểm tra dữ liệu đầu vào
-func (r createReq) validate() error {
-	// Kiểm tra RegionID hợp lệ
-	if _, err := primitive.ObjectIDFromHex(r.RegionID); err != nil {
-		return errInvalidRegionID
-	}
-	// Kiểm tra Name không được rỗng
-	if strings.TrimSpace(r.Name) == "" {
-		return errWrongBody
-	}
-	return nil
+	RegionID string `json:"region_id" binding:"required,objectid"`    // ID của region (bắt buộc)
+	Name     string `json:"name" binding:"required,notblank,max=255"` // Tên branch (bắt buộc)
 }
 
 // toInput chuyển đổi request thành input cho usecase
@@ -36,19 +28,100 @@ func (r createReq) toInput() branch.CreateInput {
 	}
 }
 
+// listReq là query lấy danh sách branch, các mốc thời gian theo định dạng "2006-01-02 15:04:05"
+type listReq struct {
+	paginator.PaginatorQuery
+	RegionID    string `form:"region_id"`    // Lọc theo region (optional)
+	CreatedFrom string `form:"created_from"` // Tạo từ thời điểm (optional)
+	CreatedTo   string `form:"created_to"`   // Tạo đến thời điểm (optional)
+	UpdatedFrom string `form:"updated_from"` // Cập nhật từ thời điểm (optional)
+	UpdatedTo   string `form:"updated_to"`   // Cập nhật đến thời điểm (optional)
+}
+
+// validate kiểm tra query lấy danh sách
+func (r listReq) validate() error {
+	// Nếu có RegionID, phải là ObjectID hợp lệ
+	if r.RegionID != "" {
+		if _, err := primitive.ObjectIDFromHex(r.RegionID); err != nil {
+			return errInvalidRegionID
+		}
+	}
+	// Các mốc thời gian phải đúng định dạng, mốc bắt đầu không được sau mốc kết thúc
+	filter, err := r.timeFilter()
+	if err != nil || !filter.IsValid() {
+		return errWrongQuery
+	}
+	return nil
+}
+
+// toInput chuyển đổi query thành input cho usecase
+func (r listReq) toInput() branch.ListInput {
+	filter, _ := r.timeFilter()
+	input := branch.ListInput{
+		Filter: filter,
+		Pagin:  r.PaginatorQuery,
+	}
+	if r.RegionID != "" {
+		regionID, _ := primitive.ObjectIDFromHex(r.RegionID)
+		input.RegionID = &regionID
+	}
+	return input
+}
+
+// timeFilter đọc các mốc thời gian trong query, mốc rỗng thì không giới hạn
+func (r listReq) timeFilter() (models.TimeFilter, error) {
+	createdFrom, errCreatedFrom := util.StrToDateTimePtr(r.CreatedFrom)
+	createdTo, errCreatedTo := util.StrToDateTimePtr(r.CreatedTo)
+	updatedFrom, errUpdatedFrom := util.StrToDateTimePtr(r.UpdatedFrom)
+	updatedTo, errUpdatedTo := util.StrToDateTimePtr(r.UpdatedTo)
+
+	filter := models.TimeFilter{
+		CreatedFrom: createdFrom,
+		CreatedTo:   createdTo,
+		UpdatedFrom: updatedFrom,
+		UpdatedTo:   updatedTo,
+	}
+	return filter, errors.Join(errCreatedFrom, errCreatedTo, errUpdatedFrom, errUpdatedTo)
+}
+
 // detailResp là cấu trúc trả về cho client
 type detailResp struct {
-	ID       string `json:"id"`        // ID của region
-	RegionID string `json:"region_id"` // ID của region
-	Name     string `json:"name"`      // Tên region
+	ID        string            `json:"id"`                   // ID của region
+	RegionID  string            `json:"region_id"`            // ID của region
+	Name      string            `json:"name"`                 // Tên region
+	CreatedAt response.DateTime `json:"created_at"`           // Thời gian tạo
+	UpdatedAt response.DateTime `json:"updated_at"`           // Thời gian cập nhật gần nhất
+	CreatedBy string            `json:"created_by,omitempty"` // ID người tạo
+	UpdatedBy string            `json:"updated_by,omitempty"` // ID người cập nhật gần nhất
+}
+
+// moveReq là cấu trúc nhận dữ liệu chuyển branch
+type moveReq struct {
+	RegionID string `json:"region_id" binding:"required,objectid"` // Region đích (bắt buộc)
+
+	version int // Version lấy từ If-Match, không đọc từ body
+}
+
+// toInput chuyển đổi move request thành input cho usecase
+func (r moveReq) toInput(id primitive.ObjectID) branch.MoveInput {
+	regionID, _ := primitive.ObjectIDFromHex(r.RegionID)
+	return branch.MoveInput{
+		ID:       id,
+		RegionID: regionID,
+		Version:  r.version,
+	}
 }
 
 // newDetailResp tạo response từ region model
 func (h handler) newDetailResp(d models.Branch) detailResp {
 	return detailResp{
-		ID:       d.ID.Hex(),
-		RegionID: d.RegionID.Hex(),
-		Name:     d.Name,
+		ID:        d.ID.Hex(),
+		RegionID:  d.RegionID.Hex(),
+		Name:      d.Name,
+		CreatedAt: response.DateTime(d.CreatedAt),
+		UpdatedAt: response.DateTime(d.UpdatedAt),
+		CreatedBy: d.CreatedBy,
+		UpdatedBy: d.UpdatedBy,
 	}
 }
 
@@ -59,22 +132,115 @@ func (h handler) emptyScope() models.Scope {
 
 // updateReq là cấu trúc nhận dữ liệu update từ HTTP request
 type updateReq struct {
-	Name string `json:"name" binding:"required"` // Tên branch mới (bắt buộc)
+	Name string `json:"name" binding:"required,notblank,max=255"` // Tên branch mới (bắt buộc)
+
+	version int // Version lấy từ If-Match, không đọc từ body
 }
 
-// validate kiểm tra dữ liệu update
-func (r updateReq) validate() error {
-	// Kiểm tra Name không được rỗng
-	if strings.TrimSpace(r.Name) == "" {
+// toInput chuyển đổi update request thành input cho usecase
+func (r updateReq) toInput(id primitive.ObjectID) branch.UpdateInput {
+	return branch.UpdateInput{
+		ID:      id,
+		Name:    &r.Name,
+		Version: r.version,
+	}
+}
+
+// patchReq là JSON Merge Patch (RFC 7396) của branch: field không gửi thì giữ nguyên, các field đều bắt buộc nên không nhận null
+type patchReq struct {
+	Name patch.Field[string] `json:"name" binding:"omitempty,notblank,max=255"` // Tên branch mới (không được null)
+
+	version int // Version lấy từ If-Match, không đọc từ body
+}
+
+// validate từ chối null cho field bắt buộc, định dạng từng field đã được kiểm tra qua binding tag
+func (r patchReq) validate() error {
+	// Ít nhất 1 field phải được gửi
+	if !r.Name.Present {
 		return errWrongBody
 	}
+
+	collector := pkgErrors.NewValidationErrorCollector()
+	if r.Name.IsNull() {
+		collector.Add(pkgErrors.NewValidationError("name", "cannot be null"))
+	}
+	if collector.HasError() {
+		return collector
+	}
 	return nil
 }
 
-// toInput chuyển đổi update request thành input cho usecase
-func (r updateReq) toInput(id primitive.ObjectID) branch.UpdateInput {
+// toInput chuyển đổi merge patch thành input cho usecase
+func (r patchReq) toInput(id primitive.ObjectID) branch.UpdateInput {
 	return branch.UpdateInput{
-		ID:   id,
-		Name: r.Name,
+		ID:      id,
+		Name:    r.Name.Ptr(),
+		Version: r.version,
+	}
+}
+
+// listResp là danh sách branch trả về cho client
+type listResp struct {
+	Items []detailResp                `json:"items"` // Các branch của trang hiện tại
+	Meta  paginator.PaginatorResponse `json:"meta"`  // Thông tin phân trang
+}
+
+// newListResp tạo response từ danh sách branch
+func (h handler) newListResp(out branch.ListOutput) listResp {
+	items := make([]detailResp, 0, len(out.Branches))
+	for _, d := range out.Branches {
+		items = append(items, h.newDetailResp(d))
+	}
+	return listResp{
+		Items: items,
+		Meta:  out.Pagin.ToResponse(),
+	}
+}
+
+// historyReq là query lấy lịch sử thay đổi của branch
+type historyReq struct {
+	paginator.PaginatorQuery
+}
+
+// toInput chuyển đổi query thành input cho usecase
+func (r historyReq) toInput(id primitive.ObjectID) branch.HistoryInput {
+	return branch.HistoryInput{
+		ID:    id,
+		Pagin: r.PaginatorQuery,
+	}
+}
+
+// revisionResp là trạng thái của branch trước một lần sửa hoặc xóa
+type revisionResp struct {
+	Version   int               `json:"version"`              // Version của snapshot
+	Action    string            `json:"action"`               // Thao tác đã thay thế snapshot: update | delete
+	ValidFrom response.DateTime `json:"valid_from"`           // Snapshot có hiệu lực từ
+	ValidTo   response.DateTime `json:"valid_to"`             // Snapshot bị thay thế lúc
+	ChangedBy string            `json:"changed_by,omitempty"` // ID người sửa / xóa
+	Snapshot  detailResp        `json:"snapshot"`             // Trạng thái của branch
+}
+
+// historyResp là lịch sử thay đổi của branch trả về cho client
+type historyResp struct {
+	Items []revisionResp              `json:"items"` // Các revision của trang hiện tại, mới nhất đứng trước
+	Meta  paginator.PaginatorResponse `json:"meta"`  // Thông tin phân trang
+}
+
+// newHistoryResp tạo response từ lịch sử thay đổi của branch
+func (h handler) newHistoryResp(out branch.HistoryOutput) historyResp {
+	items := make([]revisionResp, 0, len(out.Revisions))
+	for _, r := range out.Revisions {
+		items = append(items, revisionResp{
+			Version:   r.Branch.Version,
+			Action:    string(r.Action),
+			ValidFrom: response.DateTime(r.Branch.UpdatedAt),
+			ValidTo:   response.DateTime(r.ChangedAt),
+			ChangedBy: r.ChangedBy,
+			Snapshot:  h.newDetailResp(r.Branch),
+		})
+	}
+	return historyResp{
+		Items: items,
+		Meta:  out.Pagin.ToResponse(),
 	}
 }
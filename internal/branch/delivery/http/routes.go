@@ -1,11 +1,49 @@
 package http
 
-import "github.com/gin-gonic/gin"
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	cascadeHTTP "thuchanhgolang/internal/cascade/delivery/http"
+	"thuchanhgolang/pkg/openapi"
+	"thuchanhgolang/pkg/patch"
+)
 
 // MapRoutes maps the routes to the handler functions
 func MapRoutes(r *gin.RouterGroup, h Handler) {
-	r.POST("", h.create)       // Tạo branch mới
-	r.GET("/:id", h.getByID)   // Xem chi tiết branch theo ID
-	r.PUT("/:id", h.update)    // Cập nhật branch theo ID
-	r.DELETE("/:id", h.delete) // Xóa branch theo ID
+	r.POST("", h.create)             // Tạo branch mới
+	r.GET("", h.list)                // Lấy danh sách branch, lọc theo thời gian tạo / cập nhật
+	r.GET("/:id", h.getByID)         // Xem chi tiết branch theo ID
+	r.GET("/:id/history", h.history) // Xem lịch sử thay đổi, mới nhất đứng trước
+	r.PUT("/:id", h.update)          // Cập nhật branch theo ID
+	r.PATCH("/:id", h.patch)         // Cập nhật một phần branch theo JSON Merge Patch
+	r.DELETE("/:id", h.delete)       // Xóa branch theo ID
+	r.POST("/:id/move", h.move)      // Chuyển branch sang region khác
+}
+
+// Spec mô tả các route của MapRoutes cho OpenAPI, sửa route thì sửa cả ở đây
+func Spec() []openapi.Route {
+	return []openapi.Route{
+		{Method: http.MethodPost, Path: "", Summary: "Tạo branch mới", Body: createReq{}, Response: detailResp{}, ETag: true},
+		{Method: http.MethodGet, Path: "", Summary: "Lấy danh sách branch, lọc theo thời gian tạo / cập nhật", Query: listReq{}, Response: listResp{}},
+		{
+			Method: http.MethodGet, Path: "/:id", Summary: "Xem chi tiết branch theo ID",
+			Params:   []openapi.Parameter{openapi.QueryParam("as_of", "Thời điểm cần xem, định dạng 2006-01-02 15:04:05")},
+			Response: detailResp{}, ETag: true,
+		},
+		{Method: http.MethodGet, Path: "/:id/history", Summary: "Xem lịch sử thay đổi, mới nhất đứng trước", Query: historyReq{}, Response: historyResp{}},
+		{Method: http.MethodPut, Path: "/:id", Summary: "Cập nhật branch theo ID", IfMatch: true, Body: updateReq{}, Response: detailResp{}, ETag: true},
+		{
+			Method: http.MethodPatch, Path: "/:id", Summary: "Cập nhật một phần branch theo JSON Merge Patch, field không gửi thì giữ nguyên",
+			IfMatch: true, Body: patchReq{}, BodyTypes: []string{patch.ContentType, openapi.ContentJSON}, Response: detailResp{}, ETag: true,
+		},
+		{
+			Method: http.MethodDelete, Path: "/:id", Summary: "Xóa branch theo ID, cascade=true để xóa luôn các bản ghi con",
+			Query:    cascadeHTTP.DeleteReq{},
+			Params:   []openapi.Parameter{openapi.HeaderParam("If-Match", "ETag của branch, không cần khi cascade=true&dry_run=true")},
+			Response: openapi.OneOf{gin.H{}, cascadeHTTP.DeleteResp{}},
+		},
+		{Method: http.MethodPost, Path: "/:id/move", Summary: "Chuyển branch sang region khác", Body: moveReq{}, Response: detailResp{}, ETag: true},
+	}
 }
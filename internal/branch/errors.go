@@ -1,8 +1,23 @@
 package branch
 
-import "errors"
+import pkgErrors "thuchanhgolang/pkg/errors"
 
 var (
 	// ErrBranchInUse trả về khi branch đang được sử dụng bởi department
-	ErrBranchInUse = errors.New("branch is being used by departments, cannot delete")
+	ErrBranchInUse = pkgErrors.Conflict("branch is being used by departments, cannot delete")
+
+	// ErrBranchNotFound trả về khi không tìm thấy branch
+	ErrBranchNotFound = pkgErrors.NotFound("branch not found")
+
+	// ErrTargetRegionNotFound trả về khi không tìm thấy region đích khi chuyển branch
+	ErrTargetRegionNotFound = pkgErrors.NotFound("target region not found")
+
+	// ErrDifferentShop trả về khi region đích thuộc shop khác
+	ErrDifferentShop = pkgErrors.Invalid("target region belongs to another shop")
+
+	// ErrMoveOutOfScope trả về khi branch hoặc region đích nằm ngoài scope của user
+	ErrMoveOutOfScope = pkgErrors.Forbidden("branch or target region is outside of your scope")
+
+	// ErrVersionMismatch trả về khi branch đã bị thay đổi sau lần đọc của client (If-Match không khớp version)
+	ErrVersionMismatch = pkgErrors.PreconditionFailed("branch has been modified by another request")
 )
@@ -0,0 +1,15 @@
+package branch
+
+const (
+	// AggregateType là loại aggregate của các event branch
+	AggregateType = "branch"
+
+	// EventBranchCreated được phát khi branch được tạo
+	EventBranchCreated = "branch.created"
+	// EventBranchUpdated được phát khi thông tin branch thay đổi
+	EventBranchUpdated = "branch.updated"
+	// EventBranchDeleted được phát khi branch bị xóa (kể cả xóa dây chuyền)
+	EventBranchDeleted = "branch.deleted"
+	// EventBranchMoved được phát khi branch chuyển sang region khác
+	EventBranchMoved = "branch.moved"
+)
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/paginator"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -18,15 +19,21 @@ type Repository interface {
 	// GetByID lấy branch theo ID từ database
 	GetByID(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Branch, error)
 
+	// List lấy danh sách branch theo bộ lọc, có phân trang
+	List(ctx context.Context, sc models.Scope, opts ListOptions) ([]models.Branch, paginator.Paginator, error)
+
 	// Update cập nhật region trong database
 	Update(ctx context.Context, sc models.Scope, opts UpdateOptions) (models.Branch, error)
 
-	// Delete xóa region khỏi database
-	Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error
+	// Delete xóa region khỏi database, version là version client đã đọc (0 → không kiểm tra)
+	Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error
 
 	// HasDepartments kiểm tra xem branch có department nào không
 	HasDepartments(ctx context.Context, branchID primitive.ObjectID) (bool, error)
 
 	// HasUsers kiểm tra xem branch có user nào không
 	HasUsers(ctx context.Context, branchID primitive.ObjectID) (bool, error)
+
+	// Move đổi region của branch và ghi lại region_id, shop_id của các user thuộc branch
+	Move(ctx context.Context, sc models.Scope, opts MoveOptions) (models.Branch, error)
 }
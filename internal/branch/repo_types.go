@@ -1,6 +1,11 @@
 package branch
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // CreateInput là dữ liệu đầu vào để tạo region mới
 type CreateOptions struct {
@@ -10,6 +15,22 @@ type CreateOptions struct {
 
 // UpdateOptions là tùy chọn để cập nhật branch
 type UpdateOptions struct {
-	ID   primitive.ObjectID // ID branch cần cập nhật
-	Name string             // Tên mới
+	ID      primitive.ObjectID // ID branch cần cập nhật
+	Name    *string            // Tên mới, nil → giữ nguyên
+	Version int                // Version client đã đọc (If-Match), 0 → không kiểm tra
+}
+
+// MoveOptions là tùy chọn để chuyển branch sang region khác
+type MoveOptions struct {
+	ID       primitive.ObjectID // ID branch cần chuyển
+	RegionID primitive.ObjectID // Region đích
+	ShopID   primitive.ObjectID // Shop của region đích (ghi lại vào user)
+	Version  int                // Version client đã đọc (If-Match), 0 → không kiểm tra
+}
+
+// ListOptions là bộ lọc và phân trang khi lấy danh sách branch
+type ListOptions struct {
+	RegionID *primitive.ObjectID      // Chỉ lấy branch thuộc region này (nil = mọi region)
+	Filter   models.TimeFilter        // Lọc theo thời gian tạo, cập nhật
+	Pagin    paginator.PaginatorQuery // Trang cần lấy (đã Adjust)
 }
@@ -2,13 +2,18 @@ package mongo
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"thuchanhgolang/internal/branch"
+	"thuchanhgolang/internal/history"
 	"thuchanhgolang/internal/models"
 	"thuchanhgolang/pkg/mongo"
+	"thuchanhgolang/pkg/paginator"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const (
@@ -23,12 +28,18 @@ func (repo implRepository) getBranchCollection() mongo.Collection {
 // Create tạo region mới trong MongoDB
 func (repo implRepository) Create(ctx context.Context, sc models.Scope, opts branch.CreateOptions) (models.Branch, error) {
 	col := repo.getBranchCollection()
+	now := time.Now()
 
 	// Tạo branch object mới
 	newBranch := models.Branch{
-		ID:       repo.db.NewObjectID(),
-		RegionID: opts.RegionID,
-		Name:     opts.Name,
+		ID:        repo.db.NewObjectID(),
+		RegionID:  opts.RegionID,
+		Name:      opts.Name,
+		CreatedAt: now,
+		UpdatedAt: now,
+		CreatedBy: sc.UserID,
+		UpdatedBy: sc.UserID,
+		Version:   1,
 	}
 
 	// Lưu vào database
@@ -57,21 +68,84 @@ func (repo implRepository) GetByID(ctx context.Context, sc models.Scope, id prim
 	return branch, nil
 }
 
+// List lấy danh sách branch theo bộ lọc, branch tạo sau đứng trước
+func (repo implRepository) List(ctx context.Context, sc models.Scope, opts branch.ListOptions) ([]models.Branch, paginator.Paginator, error) {
+	col := repo.getBranchCollection()
+
+	// Bước 1: Đếm tổng số branch khớp bộ lọc
+	filter := repo.buildListQuery(opts)
+	total, err := col.CountDocuments(ctx, filter)
+	if err != nil {
+		repo.l.Errorf(ctx, "branch.mongo.List.CountDocuments: %v", err)
+		return nil, paginator.Paginator{}, err
+	}
+
+	// Bước 2: Lấy branch của trang hiện tại
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetSkip(opts.Pagin.Offset()).
+		SetLimit(opts.Pagin.Limit)
+	cursor, err := col.Find(ctx, filter, findOpts)
+	if err != nil {
+		repo.l.Errorf(ctx, "branch.mongo.List.Find: %v", err)
+		return nil, paginator.Paginator{}, err
+	}
+
+	var branches []models.Branch
+	if err := cursor.All(ctx, &branches); err != nil {
+		repo.l.Errorf(ctx, "branch.mongo.List.All: %v", err)
+		return nil, paginator.Paginator{}, err
+	}
+
+	return branches, paginator.Paginator{
+		Total:       total,
+		Count:       int64(len(branches)),
+		PerPage:     opts.Pagin.Limit,
+		CurrentPage: opts.Pagin.Page,
+	}, nil
+}
+
 // Update cập nhật thông tin region trong MongoDB (chỉ cho phép đổi tên)
 func (repo implRepository) Update(ctx context.Context, sc models.Scope, opts branch.UpdateOptions) (models.Branch, error) {
 	col := repo.getBranchCollection()
 
-	// Chỉ update name, không cho phép đổi shop_id
-	update := bson.M{
-		"name": opts.Name,
+	// Chỉ update name, không cho phép đổi cha (dùng Move), field nil thì giữ nguyên
+	update := mongo.NewUpdate()
+	mongo.SetPtr(update, "name", opts.Name)
+
+	// Nếu không có gì để update
+	if !update.HasChanges() {
+		current, err := repo.GetByID(ctx, sc, opts.ID)
+		if err != nil {
+			return models.Branch{}, err
+		}
+		if opts.Version > 0 && current.Version != opts.Version {
+			return models.Branch{}, branch.ErrVersionMismatch
+		}
+		return current, nil
 	}
 
-	// Update branch trong database
+	// Ghi lại thời điểm và người sửa
+	now := time.Now()
+	update.Set("updated_at", now).Set("updated_by", sc.UserID).Inc("version", 1)
+
+	// Update branch trong database, chỉ khi version khớp với version client đã đọc, đồng thời tăng version.
+	// Lấy về branch trước khi update để lưu vào lịch sử
 	filter := bson.M{"_id": opts.ID}
-	updateDoc := bson.M{"$set": update}
-	_, err := col.UpdateOne(ctx, filter, updateDoc)
+	if opts.Version > 0 {
+		filter["version"] = opts.Version
+	}
+	var prior bson.Raw
+	err := col.FindOneAndUpdate(ctx, filter, update.Document()).Decode(&prior)
+	if errors.Is(err, mongo.ErrNoDocuments) && opts.Version > 0 {
+		return models.Branch{}, repo.versionError(ctx, sc, opts.ID)
+	}
 	if err != nil {
-		repo.l.Errorf(ctx, "branch.mongo.Update.UpdateOne: %v", err)
+		repo.l.Errorf(ctx, "branch.mongo.Update.FindOneAndUpdate: %v", err)
+		return models.Branch{}, err
+	}
+	if err := history.RecordRevisions(ctx, repo.db, history.EntityBranch, models.RevisionUpdate, sc.UserID, now, prior); err != nil {
+		repo.l.Errorf(ctx, "branch.mongo.Update.history.RecordRevisions: %v", err)
 		return models.Branch{}, err
 	}
 
@@ -80,17 +154,31 @@ func (repo implRepository) Update(ctx context.Context, sc models.Scope, opts bra
 }
 
 // Delete xóa branch khỏi MongoDB
-func (repo implRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+func (repo implRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
 	col := repo.getBranchCollection()
 
-	// Xóa branch theo ID
+	// Xóa branch theo ID, lấy về branch đã xóa để lưu vào lịch sử
 	filter := bson.M{"_id": id}
-	_, err := col.DeleteOne(ctx, filter)
+	if version > 0 {
+		filter["version"] = version
+	}
+	var prior bson.Raw
+	err := col.FindOneAndDelete(ctx, filter).Decode(&prior)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if version > 0 {
+			return repo.versionError(ctx, sc, id)
+		}
+		return nil
+	}
 	if err != nil {
-		repo.l.Errorf(ctx, "branch.mongo.Delete.DeleteOne: %v", err)
+		repo.l.Errorf(ctx, "branch.mongo.Delete.FindOneAndDelete: %v", err)
 		return err
 	}
 
+	if err := history.RecordRevisions(ctx, repo.db, history.EntityBranch, models.RevisionDelete, sc.UserID, time.Now(), prior); err != nil {
+		repo.l.Errorf(ctx, "branch.mongo.Delete.history.RecordRevisions: %v", err)
+		return err
+	}
 	return nil
 }
 
@@ -123,3 +211,95 @@ func (repo implRepository) HasUsers(ctx context.Context, branchID primitive.Obje
 
 	return count > 0, nil
 }
+
+// Move đổi region_id của branch và cập nhật region_id, shop_id lưu sẵn trên user thuộc branch.
+// Department chỉ lưu branch_id nên không cần cập nhật.
+func (repo implRepository) Move(ctx context.Context, sc models.Scope, opts branch.MoveOptions) (models.Branch, error) {
+	col := repo.getBranchCollection()
+	now := time.Now()
+
+	// Bước 1: Đổi region của branch
+	// Chỉ đổi khi version khớp với version client đã đọc, giống Update
+	filter := bson.M{"_id": opts.ID}
+	if opts.Version > 0 {
+		filter["version"] = opts.Version
+	}
+	updateDoc := bson.M{
+		"$set": bson.M{
+			"region_id":  opts.RegionID,
+			"updated_at": now,
+			"updated_by": sc.UserID,
+		},
+		"$inc": bson.M{"version": 1},
+	}
+	var prior bson.Raw
+	err := col.FindOneAndUpdate(ctx, filter, updateDoc).Decode(&prior)
+	if errors.Is(err, mongo.ErrNoDocuments) && opts.Version > 0 {
+		return models.Branch{}, repo.versionError(ctx, sc, opts.ID)
+	}
+	if err != nil {
+		repo.l.Errorf(ctx, "branch.mongo.Move.FindOneAndUpdate: %v", err)
+		return models.Branch{}, err
+	}
+	if err := history.RecordRevisions(ctx, repo.db, history.EntityBranch, models.RevisionUpdate, sc.UserID, now, prior); err != nil {
+		repo.l.Errorf(ctx, "branch.mongo.Move.history.RecordRevisions: %v", err)
+		return models.Branch{}, err
+	}
+
+	// Bước 2: Lưu trạng thái hiện tại của user thuộc branch vào lịch sử
+	userCollection := repo.db.Collection("users")
+	userFilter := bson.M{"branch_id": opts.ID}
+	cursor, err := userCollection.Find(ctx, userFilter, options.Find().SetProjection(history.UserProjection))
+	if err != nil {
+		repo.l.Errorf(ctx, "branch.mongo.Move.Find: %v", err)
+		return models.Branch{}, err
+	}
+	var users []bson.Raw
+	if err := cursor.All(ctx, &users); err != nil {
+		repo.l.Errorf(ctx, "branch.mongo.Move.All: %v", err)
+		return models.Branch{}, err
+	}
+	if err := history.RecordRevisions(ctx, repo.db, history.EntityUser, models.RevisionUpdate, sc.UserID, now, users...); err != nil {
+		repo.l.Errorf(ctx, "branch.mongo.Move.history.RecordRevisions: %v", err)
+		return models.Branch{}, err
+	}
+
+	// Bước 3: Cập nhật hierarchy của user thuộc branch
+	userUpdate := bson.M{
+		"$set": bson.M{
+			"region_id":  opts.RegionID,
+			"shop_id":    opts.ShopID,
+			"updated_at": now,
+			"updated_by": sc.UserID,
+		},
+		"$inc": bson.M{"version": 1},
+	}
+	_, err = userCollection.UpdateMany(ctx, userFilter, userUpdate)
+	if err != nil {
+		repo.l.Errorf(ctx, "branch.mongo.Move.UpdateMany: %v", err)
+		return models.Branch{}, err
+	}
+
+	// Lấy branch đã update
+	return repo.GetByID(ctx, sc, opts.ID)
+}
+
+// versionError xác định lý do update/delete theo version không khớp document nào:
+// document không còn thì trả lỗi của GetByID, còn tồn tại thì version đã bị thay đổi
+func (repo implRepository) versionError(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+	if _, err := repo.GetByID(ctx, sc, id); err != nil {
+		return err
+	}
+	return branch.ErrVersionMismatch
+}
+
+// buildListQuery tạo filter lấy danh sách branch từ bộ lọc
+func (repo implRepository) buildListQuery(opts branch.ListOptions) bson.M {
+	filter := bson.M{}
+	if opts.RegionID != nil {
+		filter["region_id"] = *opts.RegionID
+	}
+	mongo.BuildQueryWithTimeRange(filter, "created_at", opts.Filter.CreatedFrom, opts.Filter.CreatedTo)
+	mongo.BuildQueryWithTimeRange(filter, "updated_at", opts.Filter.UpdatedFrom, opts.Filter.UpdatedTo)
+	return filter
+}
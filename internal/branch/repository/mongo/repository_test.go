@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"thuchanhgolang/internal/branch"
 	"thuchanhgolang/internal/models"
 	"thuchanhgolang/pkg/mongo"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	driverMongo "go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -135,8 +137,8 @@ func TestUpdate(t *testing.T) {
 		updated := models.Branch{ID: id, Name: "Updated"}
 
 		mockColl := &mockCollection{
-			updateOneFunc: func(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*driverMongo.UpdateResult, error) {
-				return &driverMongo.UpdateResult{ModifiedCount: 1}, nil
+			findOneAndUpdateFunc: func(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) mongo.SingleResult {
+				return newMockSingleResult(models.Branch{ID: id, Version: 1}, nil)
 			},
 			findOneFunc: func(ctx context.Context, filter interface{}) mongo.SingleResult {
 				return newMockSingleResult(updated, nil)
@@ -150,7 +152,7 @@ func TestUpdate(t *testing.T) {
 		}
 
 		repo := &implRepository{db: mockDB, l: &mockLogger{}}
-		result, err := repo.Update(ctx, models.Scope{}, branch.UpdateOptions{ID: id, Name: "Updated"})
+		result, err := repo.Update(ctx, models.Scope{}, branch.UpdateOptions{ID: id, Name: &updated.Name})
 
 		if err != nil {
 			t.Fatalf("Không mong đợi lỗi: %v", err)
@@ -165,8 +167,8 @@ func TestUpdate(t *testing.T) {
 		id := primitive.NewObjectID()
 
 		mockColl := &mockCollection{
-			updateOneFunc: func(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*driverMongo.UpdateResult, error) {
-				return nil, errors.New("update failed")
+			findOneAndUpdateFunc: func(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) mongo.SingleResult {
+				return newMockSingleResult(nil, errors.New("update failed"))
 			},
 		}
 
@@ -177,7 +179,8 @@ func TestUpdate(t *testing.T) {
 		}
 
 		repo := &implRepository{db: mockDB, l: &mockLogger{}}
-		_, err := repo.Update(ctx, models.Scope{}, branch.UpdateOptions{ID: id})
+		name := "Updated"
+		_, err := repo.Update(ctx, models.Scope{}, branch.UpdateOptions{ID: id, Name: &name})
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi")
@@ -190,8 +193,8 @@ func TestDelete(t *testing.T) {
 		ctx := context.Background()
 
 		mockColl := &mockCollection{
-			deleteOneFunc: func(ctx context.Context, filter interface{}) (int64, error) {
-				return 1, nil
+			findOneAndDeleteFunc: func(ctx context.Context, filter interface{}, opts ...*options.FindOneAndDeleteOptions) mongo.SingleResult {
+				return newMockSingleResult(models.Branch{ID: primitive.NewObjectID(), Version: 1}, nil)
 			},
 		}
 
@@ -202,7 +205,7 @@ func TestDelete(t *testing.T) {
 		}
 
 		repo := &implRepository{db: mockDB, l: &mockLogger{}}
-		err := repo.Delete(ctx, models.Scope{}, primitive.NewObjectID())
+		err := repo.Delete(ctx, models.Scope{}, primitive.NewObjectID(), 0)
 
 		if err != nil {
 			t.Fatalf("Không mong đợi lỗi: %v", err)
@@ -213,8 +216,8 @@ func TestDelete(t *testing.T) {
 		ctx := context.Background()
 
 		mockColl := &mockCollection{
-			deleteOneFunc: func(ctx context.Context, filter interface{}) (int64, error) {
-				return 0, errors.New("delete failed")
+			findOneAndDeleteFunc: func(ctx context.Context, filter interface{}, opts ...*options.FindOneAndDeleteOptions) mongo.SingleResult {
+				return newMockSingleResult(nil, errors.New("delete failed"))
 			},
 		}
 
@@ -225,7 +228,7 @@ func TestDelete(t *testing.T) {
 		}
 
 		repo := &implRepository{db: mockDB, l: &mockLogger{}}
-		err := repo.Delete(ctx, models.Scope{}, primitive.NewObjectID())
+		err := repo.Delete(ctx, models.Scope{}, primitive.NewObjectID(), 0)
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi")
@@ -233,6 +236,49 @@ func TestDelete(t *testing.T) {
 	})
 }
 
+func TestMoveVersion(t *testing.T) {
+	id := primitive.NewObjectID()
+
+	tests := []struct {
+		name    string
+		current mongo.SingleResult // Kết quả GetByID khi version không khớp
+		wantErr error
+	}{
+		{name: "version đã bị thay đổi", current: newMockSingleResult(models.Branch{ID: id, Version: 3}, nil), wantErr: branch.ErrVersionMismatch},
+		{name: "branch đã bị xóa", current: newMockSingleResult(nil, driverMongo.ErrNoDocuments), wantErr: driverMongo.ErrNoDocuments},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotFilter bson.M
+			mockColl := &mockCollection{
+				findOneAndUpdateFunc: func(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) mongo.SingleResult {
+					gotFilter = filter.(bson.M)
+					return newMockSingleResult(nil, driverMongo.ErrNoDocuments)
+				},
+				findOneFunc: func(ctx context.Context, filter interface{}) mongo.SingleResult {
+					return tt.current
+				},
+			}
+			mockDB := &mockDatabase{
+				collectionFunc: func(name string) mongo.Collection {
+					return mockColl
+				},
+			}
+
+			repo := &implRepository{db: mockDB, l: &mockLogger{}}
+			_, err := repo.Move(context.Background(), models.Scope{}, branch.MoveOptions{ID: id, RegionID: primitive.NewObjectID(), Version: 2})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Mong đợi lỗi %v, nhận được %v", tt.wantErr, err)
+			}
+			if gotFilter["version"] != 2 {
+				t.Errorf("Mong đợi filter theo version 2, nhận được %v", gotFilter)
+			}
+		})
+	}
+}
+
 func TestHasDepartments(t *testing.T) {
 	t.Run("has departments", func(t *testing.T) {
 		ctx := context.Background()
@@ -366,3 +412,30 @@ func TestHasUsers(t *testing.T) {
 		}
 	})
 }
+
+func TestBuildListQuery(t *testing.T) {
+	t.Run("build with region and time range", func(t *testing.T) {
+		regionID := primitive.NewObjectID()
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		repo := &implRepository{}
+		filter := repo.buildListQuery(branch.ListOptions{
+			RegionID: &regionID,
+			Filter:   models.TimeFilter{CreatedFrom: &from},
+		})
+
+		if filter["region_id"] != regionID {
+			t.Errorf("region_id không khớp")
+		}
+		createdAt, ok := filter["created_at"].(bson.M)
+		if !ok || createdAt["$gte"] != from {
+			t.Errorf("Mong đợi created_at có $gte, nhận được %v", filter["created_at"])
+		}
+		if _, ok := createdAt["$lte"]; ok {
+			t.Errorf("Không mong đợi $lte khi không có created_to")
+		}
+		if _, ok := filter["updated_at"]; ok {
+			t.Errorf("Không mong đợi điều kiện updated_at")
+		}
+	})
+}
@@ -2,6 +2,7 @@ package branch
 
 import (
 	"context"
+	"time"
 
 	"thuchanhgolang/internal/models"
 
@@ -16,9 +17,21 @@ type Usecase interface {
 	// GetByID lấy thông tin region theo ID
 	GetByID(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Branch, error)
 
+	// List lấy danh sách branch có phân trang, lọc theo thời gian tạo và cập nhật
+	List(ctx context.Context, sc models.Scope, input ListInput) (ListOutput, error)
+
 	// Update cập nhật thông tin region
 	Update(ctx context.Context, sc models.Scope, input UpdateInput) (models.Branch, error)
 
-	// Delete xóa branch
-	Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error
+	// Delete xóa branch, version là version client đã đọc (0 → không kiểm tra)
+	Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error
+
+	// Move chuyển branch sang region khác trong cùng shop
+	Move(ctx context.Context, sc models.Scope, input MoveInput) (models.Branch, error)
+
+	// GetAsOf lấy trạng thái của branch tại thời điểm asOf
+	GetAsOf(ctx context.Context, sc models.Scope, id primitive.ObjectID, asOf time.Time) (models.Branch, error)
+
+	// History lấy lịch sử thay đổi của branch, mới nhất đứng trước
+	History(ctx context.Context, sc models.Scope, input HistoryInput) (HistoryOutput, error)
 }
@@ -1,6 +1,13 @@
 package branch
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // CreateInput là dữ liệu đầu vào để tạo region mới
 type CreateInput struct {
@@ -10,6 +17,47 @@ type CreateInput struct {
 
 // UpdateInput là dữ liệu đầu vào để cập nhật branch
 type UpdateInput struct {
-	ID   primitive.ObjectID // ID branch cần cập nhật
-	Name string             // Tên branch mới
+	ID      primitive.ObjectID // ID branch cần cập nhật
+	Name    *string            // Tên mới, nil → giữ nguyên
+	Version int                // Version client đã đọc (If-Match), 0 → không kiểm tra
+}
+
+// MoveInput là dữ liệu đầu vào để chuyển branch sang region khác
+type MoveInput struct {
+	ID       primitive.ObjectID // ID branch cần chuyển
+	RegionID primitive.ObjectID // Region đích
+	Version  int                // Version client đã đọc (If-Match), 0 → không kiểm tra
+}
+
+// ListInput là dữ liệu đầu vào để lấy danh sách branch
+type ListInput struct {
+	RegionID *primitive.ObjectID      // Chỉ lấy branch thuộc region này (nil = mọi region)
+	Filter   models.TimeFilter        // Lọc theo thời gian tạo, cập nhật
+	Pagin    paginator.PaginatorQuery // Trang cần lấy
+}
+
+// ListOutput là danh sách branch của một trang
+type ListOutput struct {
+	Branches []models.Branch
+	Pagin    paginator.Paginator
+}
+
+// HistoryInput là dữ liệu đầu vào để lấy lịch sử thay đổi của branch
+type HistoryInput struct {
+	ID    primitive.ObjectID       // ID branch
+	Pagin paginator.PaginatorQuery // Trang cần lấy
+}
+
+// Revision là trạng thái của branch trước một lần sửa hoặc xóa
+type Revision struct {
+	Branch    models.Branch         // Snapshot, có hiệu lực từ Branch.UpdatedAt đến ChangedAt
+	Action    models.RevisionAction // Thao tác đã thay thế snapshot
+	ChangedAt time.Time             // Thời điểm sửa / xóa
+	ChangedBy string                // UserID của người sửa / xóa
+}
+
+// HistoryOutput là lịch sử thay đổi của branch trong một trang, mới nhất đứng trước
+type HistoryOutput struct {
+	Revisions []Revision
+	Pagin     paginator.Paginator
 }
@@ -2,9 +2,11 @@ package usecase
 
 import (
 	"context"
+	"errors"
 
 	"thuchanhgolang/internal/branch"
 	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/mongo"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -17,10 +19,27 @@ func (uc *implUsecase) Create(ctx context.Context, sc models.Scope, input branch
 		Name:     input.Name,
 	}
 
-	// Bước 2: Gọi repository để lưu vào database
-	newBranch, err := uc.repo.Create(ctx, sc, opts)
+	// Bước 2: Lưu vào database và ghi event vào outbox trong cùng một transaction
+	var newBranch models.Branch
+	err := uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		newBranch, err = uc.repo.Create(ctx, sc, opts)
+		if err != nil {
+			uc.l.Errorf(ctx, "branch.usecase.Create.repo.Create: %v", err)
+			return err
+		}
+		h, err := uc.hierarchy(ctx, sc, newBranch)
+		if err != nil {
+			uc.l.Errorf(ctx, "branch.usecase.Create.hierarchy: %v", err)
+			return err
+		}
+		if err := uc.publisher.Publish(ctx, newEvent(branch.EventBranchCreated, sc, h, newPayload(newBranch))); err != nil {
+			uc.l.Errorf(ctx, "branch.usecase.Create.publisher.Publish: %v", err)
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		uc.l.Errorf(ctx, "branch.usecase.Create.repo.Create: %v", err)
 		return models.Branch{}, err
 	}
 
@@ -34,64 +53,131 @@ func (uc *implUsecase) GetByID(ctx context.Context, sc models.Scope, id primitiv
 	branch, err := uc.repo.GetByID(ctx, sc, id)
 	if err != nil {
 		uc.l.Errorf(ctx, "branch.usecase.GetByID.repo.GetByID: %v", err)
-		return models.Branch{}, err
+		return models.Branch{}, notFoundError(err)
 	}
 
 	return branch, nil
 }
 
+// List lấy danh sách branch có phân trang, lọc theo thời gian tạo và cập nhật
+func (uc *implUsecase) List(ctx context.Context, sc models.Scope, input branch.ListInput) (branch.ListOutput, error) {
+	// Bước 1: Đưa page, limit về giá trị mặc định nếu không hợp lệ
+	input.Pagin.Adjust()
+
+	// Bước 2: Gọi repository để lấy danh sách
+	opts := branch.ListOptions{
+		RegionID: input.RegionID,
+		Filter:   input.Filter,
+		Pagin:    input.Pagin,
+	}
+	items, pag, err := uc.repo.List(ctx, sc, opts)
+	if err != nil {
+		uc.l.Errorf(ctx, "branch.usecase.List.repo.List: %v", err)
+		return branch.ListOutput{}, err
+	}
+
+	return branch.ListOutput{Branches: items, Pagin: pag}, nil
+}
+
 // Update cập nhật thông tin branch (chỉ cho phép đổi tên, không đổi region)
 func (uc *implUsecase) Update(ctx context.Context, sc models.Scope, input branch.UpdateInput) (models.Branch, error) {
 	// Bước 1: Chuyển đổi input thành options
 	opts := branch.UpdateOptions{
-		ID:   input.ID,
-		Name: input.Name,
+		ID:      input.ID,
+		Name:    input.Name,
+		Version: input.Version,
 	}
 
-	// Bước 2: Gọi repository để update
-	updatedBranch, err := uc.repo.Update(ctx, sc, opts)
+	// Bước 2: Gọi repository để update, sửa và ghi lịch sử chạy trong cùng một transaction
+	var updatedBranch models.Branch
+	err := uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		updatedBranch, err = uc.repo.Update(ctx, sc, opts)
+		if err != nil {
+			uc.l.Errorf(ctx, "branch.usecase.Update.repo.Update: %v", err)
+			return err
+		}
+		h, err := uc.hierarchy(ctx, sc, updatedBranch)
+		if err != nil {
+			uc.l.Errorf(ctx, "branch.usecase.Update.hierarchy: %v", err)
+			return err
+		}
+		if err := uc.publisher.Publish(ctx, newEvent(branch.EventBranchUpdated, sc, h, newPayload(updatedBranch))); err != nil {
+			uc.l.Errorf(ctx, "branch.usecase.Update.publisher.Publish: %v", err)
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		uc.l.Errorf(ctx, "branch.usecase.Update.repo.Update: %v", err)
-		return models.Branch{}, err
+		return models.Branch{}, notFoundError(err)
 	}
 
 	return updatedBranch, nil
 }
 
 // Delete xóa branch (kiểm tra trước xem có đang được dùng không)
-func (uc *implUsecase) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
-	// Bước 1: Kiểm tra xem branch có department nào không
-	hasDepartments, err := uc.repo.HasDepartments(ctx, id)
-	if err != nil {
-		uc.l.Errorf(ctx, "branch.usecase.Delete.repo.HasDepartments: %v", err)
-		return err
-	}
-
-	// Bước 2: Nếu có department, không cho phép xóa
-	if hasDepartments {
-		uc.l.Warnf(ctx, "branch.usecase.Delete: branch is being used by departments")
-		return branch.ErrBranchInUse
-	}
-
-	// Bước 3: Kiểm tra xem branch có user nào không
-	hasUsers, err := uc.repo.HasUsers(ctx, id)
-	if err != nil {
-		uc.l.Errorf(ctx, "branch.usecase.Delete.repo.HasUsers: %v", err)
-		return err
-	}
+func (uc *implUsecase) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
+	// Kiểm tra và xóa chạy trong cùng một transaction, tránh trường hợp kiểm tra xong mới bị thay đổi
+	return uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		// Bước 1: Kiểm tra xem branch có department nào không
+		hasDepartments, err := uc.repo.HasDepartments(ctx, id)
+		if err != nil {
+			uc.l.Errorf(ctx, "branch.usecase.Delete.repo.HasDepartments: %v", err)
+			return err
+		}
+
+		// Bước 2: Nếu có department, không cho phép xóa
+		if hasDepartments {
+			uc.l.Warnf(ctx, "branch.usecase.Delete: branch is being used by departments")
+			return branch.ErrBranchInUse
+		}
+
+		// Bước 3: Kiểm tra xem branch có user nào không
+		hasUsers, err := uc.repo.HasUsers(ctx, id)
+		if err != nil {
+			uc.l.Errorf(ctx, "branch.usecase.Delete.repo.HasUsers: %v", err)
+			return err
+		}
+
+		// Bước 4: Nếu có user, không cho phép xóa
+		if hasUsers {
+			uc.l.Warnf(ctx, "branch.usecase.Delete: branch is being used by users")
+			return branch.ErrBranchInUse
+		}
+
+		// Bước 5: Lấy vị trí của branch để event xóa gửi đúng người nhận
+		b, err := uc.repo.GetByID(ctx, sc, id)
+		if err != nil {
+			uc.l.Errorf(ctx, "branch.usecase.Delete.repo.GetByID: %v", err)
+			return notFoundError(err)
+		}
+		h, err := uc.hierarchy(ctx, sc, b)
+		if err != nil {
+			uc.l.Errorf(ctx, "branch.usecase.Delete.hierarchy: %v", err)
+			return err
+		}
+
+		// Bước 6: Gọi repository để xóa branch
+		err = uc.repo.Delete(ctx, sc, id, version)
+		if err != nil {
+			uc.l.Errorf(ctx, "branch.usecase.Delete.repo.Delete: %v", err)
+			return notFoundError(err)
+		}
+
+		// Bước 7: Ghi event vào outbox cùng transaction với thao tác xóa
+		if err := uc.publisher.Publish(ctx, newEvent(branch.EventBranchDeleted, sc, h, nil)); err != nil {
+			uc.l.Errorf(ctx, "branch.usecase.Delete.publisher.Publish: %v", err)
+			return err
+		}
+
+		return nil
+	})
+}
 
-	// Bước 4: Nếu có user, không cho phép xóa
-	if hasUsers {
-		uc.l.Warnf(ctx, "branch.usecase.Delete: branch is being used by users")
-		return branch.ErrBranchInUse
+// notFoundError chuyển lỗi không tìm thấy document thành lỗi domain
+func notFoundError(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return branch.ErrBranchNotFound
 	}
-
-	// Bước 5: Gọi repository để xóa branch
-	err = uc.repo.Delete(ctx, sc, id)
-	if err != nil {
-		uc.l.Errorf(ctx, "branch.usecase.Delete.repo.Delete: %v", err)
-		return err
-	}
-
-	return nil
+	return err
 }
@@ -7,6 +7,7 @@ import (
 
 	"thuchanhgolang/internal/branch"
 	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/event"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -39,7 +40,7 @@ func TestCreate(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
+		uc := &implUsecase{repo: mockRepo, regionRepo: &mockRegionRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		result, err := uc.Create(ctx, sc, input)
 
 		if err != nil {
@@ -58,7 +59,7 @@ func TestCreate(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
+		uc := &implUsecase{repo: mockRepo, regionRepo: &mockRegionRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		_, err := uc.Create(ctx, models.Scope{}, branch.CreateInput{})
 
 		if err == nil {
@@ -80,7 +81,7 @@ func TestGetByID(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
+		uc := &implUsecase{repo: mockRepo, regionRepo: &mockRegionRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		result, err := uc.GetByID(ctx, models.Scope{}, id)
 
 		if err != nil {
@@ -99,7 +100,7 @@ func TestGetByID(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
+		uc := &implUsecase{repo: mockRepo, regionRepo: &mockRegionRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		_, err := uc.GetByID(ctx, models.Scope{}, primitive.NewObjectID())
 
 		if err == nil {
@@ -113,8 +114,9 @@ func TestUpdate(t *testing.T) {
 	t.Run("update branch successfully", func(t *testing.T) {
 		ctx := context.Background()
 		id := primitive.NewObjectID()
-		input := branch.UpdateInput{ID: id, Name: "Updated Name"}
-		expected := models.Branch{ID: id, Name: input.Name}
+		name := "Updated Name"
+		input := branch.UpdateInput{ID: id, Name: &name}
+		expected := models.Branch{ID: id, Name: name}
 
 		mockRepo := &mockRepository{
 			updateFunc: func(ctx context.Context, sc models.Scope, opts branch.UpdateOptions) (models.Branch, error) {
@@ -122,7 +124,7 @@ func TestUpdate(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
+		uc := &implUsecase{repo: mockRepo, regionRepo: &mockRegionRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		result, err := uc.Update(ctx, models.Scope{}, input)
 
 		if err != nil {
@@ -141,7 +143,7 @@ func TestUpdate(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
+		uc := &implUsecase{repo: mockRepo, regionRepo: &mockRegionRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		_, err := uc.Update(ctx, models.Scope{}, branch.UpdateInput{})
 
 		if err == nil {
@@ -163,17 +165,24 @@ func TestDelete(t *testing.T) {
 			hasUsersFunc: func(ctx context.Context, branchID primitive.ObjectID) (bool, error) {
 				return false, nil
 			},
-			deleteFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+			getByIDFunc: func(ctx context.Context, sc models.Scope, branchID primitive.ObjectID) (models.Branch, error) {
+				return models.Branch{ID: branchID}, nil
+			},
+			deleteFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
 				return nil
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
-		err := uc.Delete(ctx, models.Scope{}, id)
+		tx := &mockTransactor{}
+		uc := &implUsecase{repo: mockRepo, regionRepo: &mockRegionRepository{}, l: &mockLogger{}, tx: tx, publisher: event.NewMemoryPublisher()}
+		err := uc.Delete(ctx, models.Scope{}, id, 0)
 
 		if err != nil {
 			t.Fatalf("Không mong đợi lỗi: %v", err)
 		}
+		if tx.calls != 1 {
+			t.Errorf("Delete phải chạy trong transaction, số lần gọi: %d", tx.calls)
+		}
 	})
 
 	t.Run("delete branch in use", func(t *testing.T) {
@@ -187,14 +196,14 @@ func TestDelete(t *testing.T) {
 			hasUsersFunc: func(ctx context.Context, branchID primitive.ObjectID) (bool, error) {
 				return false, nil
 			},
-			deleteFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+			deleteFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
 				t.Error("Delete không nên được gọi")
 				return nil
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
-		err := uc.Delete(ctx, models.Scope{}, id)
+		uc := &implUsecase{repo: mockRepo, regionRepo: &mockRegionRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		err := uc.Delete(ctx, models.Scope{}, id, 0)
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi")
@@ -215,14 +224,14 @@ func TestDelete(t *testing.T) {
 			hasUsersFunc: func(ctx context.Context, branchID primitive.ObjectID) (bool, error) {
 				return true, nil
 			},
-			deleteFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+			deleteFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
 				t.Error("Delete không nên được gọi")
 				return nil
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
-		err := uc.Delete(ctx, models.Scope{}, id)
+		uc := &implUsecase{repo: mockRepo, regionRepo: &mockRegionRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		err := uc.Delete(ctx, models.Scope{}, id, 0)
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi")
@@ -242,8 +251,8 @@ func TestDelete(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
-		err := uc.Delete(ctx, models.Scope{}, id)
+		uc := &implUsecase{repo: mockRepo, regionRepo: &mockRegionRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		err := uc.Delete(ctx, models.Scope{}, id, 0)
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi")
@@ -263,8 +272,8 @@ func TestDelete(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
-		err := uc.Delete(ctx, models.Scope{}, id)
+		uc := &implUsecase{repo: mockRepo, regionRepo: &mockRegionRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		err := uc.Delete(ctx, models.Scope{}, id, 0)
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi")
@@ -282,13 +291,13 @@ func TestDelete(t *testing.T) {
 			hasUsersFunc: func(ctx context.Context, branchID primitive.ObjectID) (bool, error) {
 				return false, nil
 			},
-			deleteFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+			deleteFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
 				return errors.New("delete failed")
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
-		err := uc.Delete(ctx, models.Scope{}, id)
+		uc := &implUsecase{repo: mockRepo, regionRepo: &mockRegionRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		err := uc.Delete(ctx, models.Scope{}, id, 0)
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi")
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/branch"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/event"
+)
+
+// newEvent tạo event của branch nằm ở vị trí h, actor là user đang thao tác
+func newEvent(typ string, sc models.Scope, h models.Hierarchy, payload map[string]interface{}) event.Event {
+	e := event.New(typ, branch.AggregateType, h.BranchID.Hex(), sc.UserID, payload)
+	e.Location = h.Location()
+	return e
+}
+
+// hierarchy lấy vị trí của branch, branch chỉ lưu region_id nên shop được lấy qua region
+func (uc *implUsecase) hierarchy(ctx context.Context, sc models.Scope, b models.Branch) (models.Hierarchy, error) {
+	r, err := uc.regionRepo.GetByID(ctx, sc, b.RegionID)
+	if err != nil {
+		return models.Hierarchy{}, err
+	}
+	return models.Hierarchy{ShopID: r.ShopID, RegionID: r.ID, BranchID: b.ID}, nil
+}
+
+// newPayload là nội dung event khi branch được tạo hoặc cập nhật
+func newPayload(b models.Branch) map[string]interface{} {
+	return map[string]interface{}{
+		"region_id": b.RegionID.Hex(),
+		"name":      b.Name,
+		"version":   b.Version,
+	}
+}
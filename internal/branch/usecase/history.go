@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"thuchanhgolang/internal/branch"
+	"thuchanhgolang/internal/history"
+	"thuchanhgolang/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// historyReader đọc lịch sử của branch, trạng thái hiện tại được lấy trong scope của người gọi
+func (uc *implUsecase) historyReader(sc models.Scope) history.Reader[models.Branch] {
+	return history.Reader[models.Branch]{
+		Repo:   uc.history,
+		Entity: history.EntityBranch,
+		Current: func(ctx context.Context, id primitive.ObjectID) (models.Branch, error) {
+			return uc.repo.GetByID(ctx, sc, id)
+		},
+		CreatedAt: func(v models.Branch) time.Time { return v.CreatedAt },
+	}
+}
+
+// GetAsOf lấy trạng thái của branch tại thời điểm asOf
+func (uc *implUsecase) GetAsOf(ctx context.Context, sc models.Scope, id primitive.ObjectID, asOf time.Time) (models.Branch, error) {
+	result, err := uc.historyReader(sc).GetAsOf(ctx, id, asOf)
+	if err != nil {
+		if errors.Is(err, history.ErrNotCreated) {
+			return models.Branch{}, branch.ErrBranchNotFound
+		}
+		uc.l.Errorf(ctx, "branch.usecase.GetAsOf.historyReader.GetAsOf: %v", err)
+		return models.Branch{}, notFoundError(err)
+	}
+
+	return result, nil
+}
+
+// History lấy lịch sử thay đổi của branch, mới nhất đứng trước
+func (uc *implUsecase) History(ctx context.Context, sc models.Scope, input branch.HistoryInput) (branch.HistoryOutput, error) {
+	entries, pag, err := uc.historyReader(sc).List(ctx, input.ID, input.Pagin)
+	if err != nil {
+		uc.l.Errorf(ctx, "branch.usecase.History.historyReader.List: %v", err)
+		return branch.HistoryOutput{}, notFoundError(err)
+	}
+
+	out := branch.HistoryOutput{
+		Revisions: make([]branch.Revision, 0, len(entries)),
+		Pagin:     pag,
+	}
+	for _, e := range entries {
+		out.Revisions = append(out.Revisions, branch.Revision{
+			Branch:    e.Snapshot,
+			Action:    e.Action,
+			ChangedAt: e.ChangedAt,
+			ChangedBy: e.ChangedBy,
+		})
+	}
+
+	return out, nil
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"thuchanhgolang/internal/branch"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/mongo"
+)
+
+// Move chuyển branch sang region khác trong cùng shop
+// Flow: Lấy branch, region cũ, region đích -> Kiểm tra shop và scope -> Cập nhật branch + user -> Ghi event vào outbox
+func (uc *implUsecase) Move(ctx context.Context, sc models.Scope, input branch.MoveInput) (models.Branch, error) {
+	var moved models.Branch
+	var from models.Region
+
+	err := uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		// Bước 1: Lấy branch và region hiện tại
+		b, err := uc.repo.GetByID(ctx, sc, input.ID)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return branch.ErrBranchNotFound
+			}
+			uc.l.Errorf(ctx, "branch.usecase.Move.repo.GetByID: %v", err)
+			return err
+		}
+		from, err = uc.regionRepo.GetByID(ctx, sc, b.RegionID)
+		if err != nil {
+			uc.l.Errorf(ctx, "branch.usecase.Move.regionRepo.GetByID(from): %v", err)
+			return err
+		}
+
+		// Bước 2: Lấy region đích
+		to, err := uc.regionRepo.GetByID(ctx, sc, input.RegionID)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return branch.ErrTargetRegionNotFound
+			}
+			uc.l.Errorf(ctx, "branch.usecase.Move.regionRepo.GetByID(to): %v", err)
+			return err
+		}
+
+		// Bước 3: Region đích phải cùng shop
+		if to.ShopID != from.ShopID {
+			uc.l.Warnf(ctx, "branch.usecase.Move: region %s belongs to another shop", to.ID.Hex())
+			return branch.ErrDifferentShop
+		}
+
+		// Bước 4: Cả branch lẫn region đích phải nằm trong scope của user
+		source := models.Hierarchy{ShopID: from.ShopID, RegionID: from.ID, BranchID: b.ID}
+		target := models.Hierarchy{ShopID: to.ShopID, RegionID: to.ID}
+		if !sc.Contains(source) || !sc.Contains(target) {
+			uc.l.Warnf(ctx, "branch.usecase.Move: user %s cannot move branch %s", sc.UserID, b.ID.Hex())
+			return branch.ErrMoveOutOfScope
+		}
+
+		// Bước 5: Đã ở region đích thì không cần đổi gì, chỉ kiểm tra version
+		if to.ID == from.ID {
+			if input.Version > 0 && b.Version != input.Version {
+				return branch.ErrVersionMismatch
+			}
+			moved = b
+			return nil
+		}
+
+		// Bước 6: Đổi region của branch và cập nhật user
+		moved, err = uc.repo.Move(ctx, sc, branch.MoveOptions{
+			ID:       b.ID,
+			RegionID: to.ID,
+			ShopID:   to.ShopID,
+			Version:  input.Version,
+		})
+		if err != nil {
+			uc.l.Errorf(ctx, "branch.usecase.Move.repo.Move: %v", err)
+			return err
+		}
+
+		// Bước 7: Ghi event vào outbox cùng transaction, event chỉ được gửi khi thay đổi đã commit
+		e := newEvent(branch.EventBranchMoved, sc, models.Hierarchy{ShopID: to.ShopID, RegionID: to.ID, BranchID: moved.ID}, map[string]interface{}{
+			"from_region_id": from.ID.Hex(),
+			"to_region_id":   moved.RegionID.Hex(),
+			"shop_id":        from.ShopID.Hex(),
+		})
+		if err := uc.publisher.Publish(ctx, e); err != nil {
+			uc.l.Errorf(ctx, "branch.usecase.Move.publisher.Publish: %v", err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return models.Branch{}, err
+	}
+
+	return moved, nil
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"thuchanhgolang/internal/branch"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/event"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMove(t *testing.T) {
+	shopID := primitive.NewObjectID()
+	from := models.Region{ID: primitive.NewObjectID(), ShopID: shopID}
+	to := models.Region{ID: primitive.NewObjectID(), ShopID: shopID}
+	b := models.Branch{ID: primitive.NewObjectID(), RegionID: from.ID, Name: "Chi nhánh A"}
+	manager := models.Scope{UserID: "manager", Role: models.RoleManager, ShopID: &shopID}
+
+	newRegionRepo := func(regions ...models.Region) *mockRegionRepository {
+		return &mockRegionRepository{
+			getByIDFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Region, error) {
+				for _, r := range regions {
+					if r.ID == id {
+						return r, nil
+					}
+				}
+				return models.Region{}, errors.New("not found")
+			},
+		}
+	}
+
+	t.Run("move successfully", func(t *testing.T) {
+		var got branch.MoveOptions
+		mockRepo := &mockRepository{
+			getByIDFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Branch, error) {
+				return b, nil
+			},
+			moveFunc: func(ctx context.Context, sc models.Scope, opts branch.MoveOptions) (models.Branch, error) {
+				got = opts
+				return models.Branch{ID: b.ID, RegionID: opts.RegionID, Name: b.Name}, nil
+			},
+		}
+		tx := &mockTransactor{}
+		publisher := event.NewMemoryPublisher()
+
+		uc := &implUsecase{repo: mockRepo, regionRepo: newRegionRepo(from, to), l: &mockLogger{}, tx: tx, publisher: publisher}
+		result, err := uc.Move(context.Background(), manager, branch.MoveInput{ID: b.ID, RegionID: to.ID})
+
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if result.RegionID != to.ID {
+			t.Errorf("Mong đợi region %s, nhận được %s", to.ID.Hex(), result.RegionID.Hex())
+		}
+		if got.ShopID != shopID {
+			t.Errorf("Shop ghi vào user không đúng: %s", got.ShopID.Hex())
+		}
+		if tx.calls != 1 {
+			t.Errorf("Move phải chạy trong transaction, số lần gọi: %d", tx.calls)
+		}
+		if len(publisher.Events()) != 1 || publisher.Events()[0].Type != branch.EventBranchMoved {
+			t.Errorf("Mong đợi 1 event %s, nhận được %+v", branch.EventBranchMoved, publisher.Events())
+		}
+	})
+
+	t.Run("move to another shop", func(t *testing.T) {
+		other := models.Region{ID: primitive.NewObjectID(), ShopID: primitive.NewObjectID()}
+		mockRepo := &mockRepository{
+			getByIDFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Branch, error) {
+				return b, nil
+			},
+		}
+
+		uc := &implUsecase{repo: mockRepo, regionRepo: newRegionRepo(from, other), l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		_, err := uc.Move(context.Background(), manager, branch.MoveInput{ID: b.ID, RegionID: other.ID})
+
+		if !errors.Is(err, branch.ErrDifferentShop) {
+			t.Errorf("Mong đợi ErrDifferentShop, nhận được: %v", err)
+		}
+	})
+
+	t.Run("target region outside scope", func(t *testing.T) {
+		regionManager := models.Scope{UserID: "rm", Role: models.RoleRegionManager, ShopID: &shopID, RegionID: &from.ID}
+		moved := false
+		mockRepo := &mockRepository{
+			getByIDFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Branch, error) {
+				return b, nil
+			},
+			moveFunc: func(ctx context.Context, sc models.Scope, opts branch.MoveOptions) (models.Branch, error) {
+				moved = true
+				return models.Branch{}, nil
+			},
+		}
+		publisher := event.NewMemoryPublisher()
+
+		uc := &implUsecase{repo: mockRepo, regionRepo: newRegionRepo(from, to), l: &mockLogger{}, tx: &mockTransactor{}, publisher: publisher}
+		_, err := uc.Move(context.Background(), regionManager, branch.MoveInput{ID: b.ID, RegionID: to.ID})
+
+		if !errors.Is(err, branch.ErrMoveOutOfScope) {
+			t.Errorf("Mong đợi ErrMoveOutOfScope, nhận được: %v", err)
+		}
+		if moved || len(publisher.Events()) != 0 {
+			t.Error("Không được chuyển branch hoặc phát event khi ngoài scope")
+		}
+	})
+
+	t.Run("stale version when already in target region", func(t *testing.T) {
+		current := b
+		current.Version = 3
+		mockRepo := &mockRepository{
+			getByIDFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Branch, error) {
+				return current, nil
+			},
+		}
+		publisher := event.NewMemoryPublisher()
+
+		uc := &implUsecase{repo: mockRepo, regionRepo: newRegionRepo(from), l: &mockLogger{}, tx: &mockTransactor{}, publisher: publisher}
+		_, err := uc.Move(context.Background(), manager, branch.MoveInput{ID: b.ID, RegionID: from.ID, Version: 2})
+
+		if !errors.Is(err, branch.ErrVersionMismatch) {
+			t.Errorf("Mong đợi ErrVersionMismatch, nhận được: %v", err)
+		}
+		if len(publisher.Events()) != 0 {
+			t.Error("Không được phát event khi version không khớp")
+		}
+	})
+}
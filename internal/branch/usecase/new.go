@@ -2,19 +2,31 @@ package usecase
 
 import (
 	"thuchanhgolang/internal/branch"
+	"thuchanhgolang/internal/history"
+	"thuchanhgolang/internal/region"
+	"thuchanhgolang/pkg/event"
 	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/mongo"
 )
 
 // implUsecase là implementation của region.Usecase interface
 type implUsecase struct {
-	l    log.Logger        // Logger để ghi log
-	repo branch.Repository // Repository để tương tác với database
+	l          log.Logger         // Logger để ghi log
+	repo       branch.Repository  // Repository để tương tác với database
+	regionRepo region.Repository  // Dùng để kiểm tra region khi chuyển branch
+	history    history.Repository // Đọc lịch sử thay đổi
+	tx         mongo.Transactor   // Chạy các thao tác nhiều bước trong transaction
+	publisher  event.Publisher    // Ghi event vào outbox khi branch thay đổi
 }
 
 // NewUsecase tạo usecase mới cho region
-func NewUsecase(l log.Logger, repo branch.Repository) branch.Usecase {
+func NewUsecase(l log.Logger, repo branch.Repository, regionRepo region.Repository, historyRepo history.Repository, tx mongo.Transactor, publisher event.Publisher) branch.Usecase {
 	return &implUsecase{
-		l:    l,
-		repo: repo,
+		l:          l,
+		repo:       repo,
+		regionRepo: regionRepo,
+		history:    historyRepo,
+		tx:         tx,
+		publisher:  publisher,
 	}
 }
@@ -6,6 +6,8 @@ import (
 
 	"thuchanhgolang/internal/branch"
 	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/region"
+	"thuchanhgolang/pkg/paginator"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -14,10 +16,12 @@ import (
 type mockRepository struct {
 	createFunc         func(ctx context.Context, sc models.Scope, opts branch.CreateOptions) (models.Branch, error)
 	getByIDFunc        func(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Branch, error)
+	listFunc           func(ctx context.Context, sc models.Scope, opts branch.ListOptions) ([]models.Branch, paginator.Paginator, error)
 	updateFunc         func(ctx context.Context, sc models.Scope, opts branch.UpdateOptions) (models.Branch, error)
-	deleteFunc         func(ctx context.Context, sc models.Scope, id primitive.ObjectID) error
+	deleteFunc         func(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error
 	hasDepartmentsFunc func(ctx context.Context, branchID primitive.ObjectID) (bool, error)
 	hasUsersFunc       func(ctx context.Context, branchID primitive.ObjectID) (bool, error)
+	moveFunc           func(ctx context.Context, sc models.Scope, opts branch.MoveOptions) (models.Branch, error)
 }
 
 func (m *mockRepository) Create(ctx context.Context, sc models.Scope, opts branch.CreateOptions) (models.Branch, error) {
@@ -34,6 +38,13 @@ func (m *mockRepository) GetByID(ctx context.Context, sc models.Scope, id primit
 	return models.Branch{}, errors.New("mock GetByID not implemented")
 }
 
+func (m *mockRepository) List(ctx context.Context, sc models.Scope, opts branch.ListOptions) ([]models.Branch, paginator.Paginator, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, sc, opts)
+	}
+	return nil, paginator.Paginator{}, nil
+}
+
 func (m *mockRepository) Update(ctx context.Context, sc models.Scope, opts branch.UpdateOptions) (models.Branch, error) {
 	if m.updateFunc != nil {
 		return m.updateFunc(ctx, sc, opts)
@@ -41,9 +52,9 @@ func (m *mockRepository) Update(ctx context.Context, sc models.Scope, opts branc
 	return models.Branch{}, errors.New("mock Update not implemented")
 }
 
-func (m *mockRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+func (m *mockRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
 	if m.deleteFunc != nil {
-		return m.deleteFunc(ctx, sc, id)
+		return m.deleteFunc(ctx, sc, id, version)
 	}
 	return errors.New("mock Delete not implemented")
 }
@@ -75,3 +86,51 @@ func (m *mockLogger) Error(ctx context.Context, arg ...any)                   {}
 func (m *mockLogger) Errorf(ctx context.Context, template string, arg ...any) {}
 func (m *mockLogger) Fatal(ctx context.Context, arg ...any)                   {}
 func (m *mockLogger) Fatalf(ctx context.Context, template string, arg ...any) {}
+
+type mockTransactor struct {
+	calls int
+}
+
+func (m *mockTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	return fn(ctx)
+}
+
+func (m *mockRepository) Move(ctx context.Context, sc models.Scope, opts branch.MoveOptions) (models.Branch, error) {
+	if m.moveFunc != nil {
+		return m.moveFunc(ctx, sc, opts)
+	}
+	return models.Branch{}, nil
+}
+
+// mockRegionRepository giả lập region.Repository, chỉ cần GetByID
+type mockRegionRepository struct {
+	getByIDFunc func(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Region, error)
+}
+
+func (m *mockRegionRepository) Create(ctx context.Context, sc models.Scope, opts region.CreateOptions) (models.Region, error) {
+	return models.Region{}, nil
+}
+
+func (m *mockRegionRepository) GetByID(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Region, error) {
+	if m.getByIDFunc != nil {
+		return m.getByIDFunc(ctx, sc, id)
+	}
+	return models.Region{}, nil
+}
+
+func (m *mockRegionRepository) List(ctx context.Context, sc models.Scope, opts region.ListOptions) ([]models.Region, paginator.Paginator, error) {
+	return nil, paginator.Paginator{}, nil
+}
+
+func (m *mockRegionRepository) Update(ctx context.Context, sc models.Scope, opts region.UpdateOptions) (models.Region, error) {
+	return models.Region{}, nil
+}
+
+func (m *mockRegionRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
+	return nil
+}
+
+func (m *mockRegionRepository) HasBranches(ctx context.Context, regionID primitive.ObjectID) (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,44 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"thuchanhgolang/internal/cascade"
+	pkgErrors "thuchanhgolang/pkg/errors"
+)
+
+var (
+	errWrongQuery              = pkgErrors.NewHTTPError(50000, "Wrong query")
+	errInvalidReassignBranch   = pkgErrors.NewHTTPError(50001, "Invalid reassign branch ID")
+	errTargetNotFound          = pkgErrors.NewHTTPErrorWithStatus(50002, "Target not found", http.StatusNotFound)
+	errReassignBranchNotFound  = pkgErrors.NewHTTPErrorWithStatus(50003, "Reassign branch not found", http.StatusNotFound)
+	errReassignBranchDeleted   = pkgErrors.NewHTTPErrorWithStatus(50004, "Reassign branch is being deleted", http.StatusUnprocessableEntity)
+	errVersionMismatch         = pkgErrors.NewHTTPErrorWithStatus(50005, "Target has been modified by another request, reload and try again", http.StatusPreconditionFailed)
+	errOutOfScope              = pkgErrors.NewHTTPErrorWithStatus(50006, "Target or reassign branch is outside of your scope", http.StatusForbidden)
+	errReassignBranchOtherShop = pkgErrors.NewHTTPErrorWithStatus(50007, "Reassign branch belongs to another shop", http.StatusUnprocessableEntity)
+)
+
+// MapError chuyển đổi lỗi xóa dây chuyền thành HTTP error.
+// Lỗi không thuộc cascade được trả về nguyên vẹn để handler của từng domain xử lý tiếp.
+func MapError(err error) error {
+	if errors.Is(err, cascade.ErrTargetNotFound) {
+		return errTargetNotFound
+	}
+	if errors.Is(err, cascade.ErrReassignBranchNotFound) {
+		return errReassignBranchNotFound
+	}
+	if errors.Is(err, cascade.ErrReassignBranchDeleted) {
+		return errReassignBranchDeleted
+	}
+	if errors.Is(err, cascade.ErrOutOfScope) {
+		return errOutOfScope
+	}
+	if errors.Is(err, cascade.ErrReassignBranchOtherShop) {
+		return errReassignBranchOtherShop
+	}
+	if errors.Is(err, cascade.ErrVersionMismatch) {
+		return errVersionMismatch
+	}
+	return err
+}
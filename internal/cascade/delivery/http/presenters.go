@@ -0,0 +1,56 @@
+package http
+
+import (
+	"thuchanhgolang/internal/cascade"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// affectedResp là số lượng và danh sách ID bị ảnh hưởng của một loại bản ghi
+type affectedResp struct {
+	Count int      `json:"count"`
+	IDs   []string `json:"ids"`
+}
+
+// DeleteResp là response của xóa dây chuyền
+type DeleteResp struct {
+	Level            string       `json:"level"`
+	ID               string       `json:"id"`
+	DryRun           bool         `json:"dry_run"`
+	Regions          affectedResp `json:"regions"`
+	Branches         affectedResp `json:"branches"`
+	Departments      affectedResp `json:"departments"`
+	Users            affectedResp `json:"users"`
+	UserAction       string       `json:"user_action"` // "reassign" hoặc "deactivate"
+	ReassignBranchID string       `json:"reassign_branch_id,omitempty"`
+}
+
+// NewDeleteResp tạo response từ kết quả xóa dây chuyền
+func NewDeleteResp(out cascade.DeleteOutput) DeleteResp {
+	resp := DeleteResp{
+		Level:       string(out.Level),
+		ID:          out.ID.Hex(),
+		DryRun:      out.DryRun,
+		Regions:     newAffectedResp(out.Affected.RegionIDs),
+		Branches:    newAffectedResp(out.Affected.BranchIDs),
+		Departments: newAffectedResp(out.Affected.DepartmentIDs),
+		Users:       newAffectedResp(out.Affected.UserIDs),
+		UserAction:  "deactivate",
+	}
+	if out.ReassignBranchID != nil {
+		resp.UserAction = "reassign"
+		resp.ReassignBranchID = out.ReassignBranchID.Hex()
+	}
+	return resp
+}
+
+func newAffectedResp(ids []primitive.ObjectID) affectedResp {
+	hexIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		hexIDs = append(hexIDs, id.Hex())
+	}
+	return affectedResp{
+		Count: len(hexIDs),
+		IDs:   hexIDs,
+	}
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"thuchanhgolang/internal/cascade"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DeleteReq là query của request DELETE, dùng chung cho shop, region, branch
+type DeleteReq struct {
+	Cascade          bool   `form:"cascade"`            // Xóa luôn các bản ghi con
+	DryRun           bool   `form:"dry_run"`            // Chỉ xem trước, không xóa
+	ReassignBranchID string `form:"reassign_branch_id"` // Branch nhận user (không có thì user bị vô hiệu hóa)
+}
+
+// ProcessDeleteRequest đọc và validate query xóa
+func ProcessDeleteRequest(c *gin.Context) (DeleteReq, error) {
+	var req DeleteReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		return DeleteReq{}, errWrongQuery
+	}
+
+	if err := req.validate(); err != nil {
+		return DeleteReq{}, err
+	}
+
+	return req, nil
+}
+
+// validate kiểm tra query: dry_run và reassign_branch_id chỉ dùng được khi cascade=true
+func (r DeleteReq) validate() error {
+	if !r.Cascade && (r.DryRun || r.ReassignBranchID != "") {
+		return errWrongQuery
+	}
+	if r.ReassignBranchID != "" {
+		if _, err := primitive.ObjectIDFromHex(r.ReassignBranchID); err != nil {
+			return errInvalidReassignBranch
+		}
+	}
+	return nil
+}
+
+// ToInput chuyển query thành input cho cascade usecase, version lấy từ If-Match của request
+func (r DeleteReq) ToInput(level cascade.Level, id primitive.ObjectID, version int) cascade.DeleteInput {
+	input := cascade.DeleteInput{
+		Level:   level,
+		ID:      id,
+		DryRun:  r.DryRun,
+		Version: version,
+	}
+	if r.ReassignBranchID != "" {
+		branchID, _ := primitive.ObjectIDFromHex(r.ReassignBranchID)
+		input.ReassignBranchID = &branchID
+	}
+	return input
+}
@@ -0,0 +1,26 @@
+package cascade
+
+import pkgErrors "thuchanhgolang/pkg/errors"
+
+var (
+	// ErrInvalidLevel được trả về khi cấp tổ chức không hỗ trợ xóa dây chuyền
+	ErrInvalidLevel = pkgErrors.Invalid("invalid cascade level")
+
+	// ErrTargetNotFound được trả về khi không tìm thấy đơn vị cần xóa
+	ErrTargetNotFound = pkgErrors.NotFound("target not found")
+
+	// ErrReassignBranchNotFound được trả về khi không tìm thấy branch nhận user
+	ErrReassignBranchNotFound = pkgErrors.NotFound("reassign branch not found")
+
+	// ErrReassignBranchDeleted được trả về khi branch nhận user cũng nằm trong phần bị xóa
+	ErrReassignBranchDeleted = pkgErrors.Invalid("reassign branch is being deleted")
+
+	// ErrOutOfScope được trả về khi đơn vị cần xóa hoặc branch nhận user nằm ngoài phạm vi của người thực hiện
+	ErrOutOfScope = pkgErrors.Forbidden("target or reassign branch is outside of your scope")
+
+	// ErrReassignBranchOtherShop được trả về khi branch nhận user thuộc shop khác với đơn vị bị xóa
+	ErrReassignBranchOtherShop = pkgErrors.Invalid("reassign branch belongs to another shop")
+
+	// ErrVersionMismatch được trả về khi target đã bị thay đổi sau lần đọc của client (If-Match không khớp version)
+	ErrVersionMismatch = pkgErrors.PreconditionFailed("target has been modified by another request")
+)
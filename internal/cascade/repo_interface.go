@@ -0,0 +1,19 @@
+package cascade
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Repository định nghĩa các thao tác database cho xóa dây chuyền
+type Repository interface {
+	// Collect gom ID các region, branch, department và user nằm dưới một đơn vị
+	Collect(ctx context.Context, opts CollectOptions) (Affected, error)
+	// Apply xóa đơn vị cùng các bản ghi con, chuyển hoặc vô hiệu hóa user
+	Apply(ctx context.Context, opts ApplyOptions) error
+	// GetBranchHierarchy lấy shop, region của một branch (dùng khi chuyển user)
+	GetBranchHierarchy(ctx context.Context, branchID primitive.ObjectID) (models.Hierarchy, error)
+}
@@ -0,0 +1,70 @@
+package cascade
+
+import (
+	"time"
+
+	"thuchanhgolang/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Level là cấp tổ chức được xóa dây chuyền
+type Level string
+
+const (
+	LevelShop   Level = "shop"
+	LevelRegion Level = "region"
+	LevelBranch Level = "branch"
+)
+
+// IsValid kiểm tra level có được hỗ trợ không
+func (l Level) IsValid() bool {
+	switch l {
+	case LevelShop, LevelRegion, LevelBranch:
+		return true
+	}
+	return false
+}
+
+// CollectOptions là options để gom các bản ghi con của một đơn vị
+type CollectOptions struct {
+	Level Level
+	ID    primitive.ObjectID
+}
+
+// Affected là danh sách ID các bản ghi bị ảnh hưởng khi xóa một đơn vị.
+// Bản thân đơn vị bị xóa không nằm trong danh sách.
+type Affected struct {
+	RegionIDs     []primitive.ObjectID
+	BranchIDs     []primitive.ObjectID
+	DepartmentIDs []primitive.ObjectID
+	UserIDs       []primitive.ObjectID
+	// Root là vị trí của đơn vị bị xóa, Locations là vị trí của từng bản ghi bị ảnh hưởng theo ID.
+	// Dùng để event xóa dây chuyền đến đúng những người có quyền xem bản ghi.
+	Root      models.Hierarchy
+	Locations map[primitive.ObjectID]models.Hierarchy
+}
+
+// HasBranch kiểm tra branch có nằm trong danh sách bị xóa không
+func (a Affected) HasBranch(id primitive.ObjectID) bool {
+	for _, b := range a.BranchIDs {
+		if b == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyOptions là options để thực hiện xóa dây chuyền
+type ApplyOptions struct {
+	Level    Level
+	ID       primitive.ObjectID
+	Affected Affected
+	// Reassign là vị trí mới của user, nil thì user bị vô hiệu hóa
+	Reassign *models.Hierarchy
+	Now      time.Time
+	// ActorID là UserID người thực hiện, ghi vào updated_by của user bị ảnh hưởng
+	ActorID string
+	// Version là version của đơn vị bị xóa mà client đã đọc, 0 → không kiểm tra
+	Version int
+}
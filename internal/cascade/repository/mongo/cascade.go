@@ -0,0 +1,333 @@
+package mongo
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/cascade"
+	"thuchanhgolang/internal/history"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/mongo"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	shopCollection       = "shops"
+	regionCollection     = "regions"
+	branchCollection     = "branches"
+	departmentCollection = "departments"
+	userCollection       = "users"
+)
+
+// rootCollection trả về collection chứa đơn vị bị xóa
+func rootCollection(level cascade.Level) string {
+	switch level {
+	case cascade.LevelShop:
+		return shopCollection
+	case cascade.LevelRegion:
+		return regionCollection
+	default:
+		return branchCollection
+	}
+}
+
+// userField trả về field của user trỏ tới đơn vị bị xóa
+func userField(level cascade.Level) string {
+	switch level {
+	case cascade.LevelShop:
+		return "shop_id"
+	case cascade.LevelRegion:
+		return "region_id"
+	default:
+		return "branch_id"
+	}
+}
+
+// Collect gom ID và vị trí các bản ghi nằm dưới một đơn vị
+func (repo implRepository) Collect(ctx context.Context, opts cascade.CollectOptions) (cascade.Affected, error) {
+	// Bước 1: Kiểm tra đơn vị tồn tại và lấy vị trí của nó
+	root, err := repo.rootHierarchy(ctx, opts)
+	if err != nil {
+		return cascade.Affected{}, err
+	}
+
+	affected := cascade.Affected{
+		Root:      root,
+		Locations: map[primitive.ObjectID]models.Hierarchy{},
+	}
+
+	// Bước 2: Gom region (chỉ khi xóa shop)
+	if opts.Level == cascade.LevelShop {
+		regions, err := repo.findLocated(ctx, regionCollection, bson.M{"shop_id": opts.ID})
+		if err != nil {
+			return cascade.Affected{}, err
+		}
+		for _, r := range regions {
+			affected.RegionIDs = append(affected.RegionIDs, r.ID)
+			affected.Locations[r.ID] = models.Hierarchy{ShopID: r.ShopID, RegionID: r.ID}
+		}
+	}
+
+	// Bước 3: Gom branch (branch chỉ lưu region_id nên đi qua danh sách region)
+	var branches []located
+	switch opts.Level {
+	case cascade.LevelShop:
+		if len(affected.RegionIDs) > 0 {
+			branches, err = repo.findLocated(ctx, branchCollection, bson.M{"region_id": bson.M{"$in": affected.RegionIDs}})
+		}
+	case cascade.LevelRegion:
+		branches, err = repo.findLocated(ctx, branchCollection, bson.M{"region_id": opts.ID})
+	}
+	if err != nil {
+		return cascade.Affected{}, err
+	}
+	for _, b := range branches {
+		h, ok := affected.Locations[b.RegionID]
+		if !ok {
+			h = root
+		}
+		h.BranchID = b.ID
+		affected.BranchIDs = append(affected.BranchIDs, b.ID)
+		affected.Locations[b.ID] = h
+	}
+
+	// Bước 4: Gom department
+	branchIDs := affected.BranchIDs
+	if opts.Level == cascade.LevelBranch {
+		branchIDs = []primitive.ObjectID{opts.ID}
+	}
+	if len(branchIDs) > 0 {
+		departments, err := repo.findLocated(ctx, departmentCollection, bson.M{"branch_id": bson.M{"$in": branchIDs}})
+		if err != nil {
+			return cascade.Affected{}, err
+		}
+		for _, d := range departments {
+			h, ok := affected.Locations[d.BranchID]
+			if !ok {
+				h = root
+			}
+			h.DepartmentID = &d.ID
+			affected.DepartmentIDs = append(affected.DepartmentIDs, d.ID)
+			affected.Locations[d.ID] = h
+		}
+	}
+
+	// Bước 5: Gom user đang hoạt động thuộc đơn vị, user lưu đủ vị trí của mình
+	users, err := repo.findLocated(ctx, userCollection, bson.M{
+		userField(opts.Level): opts.ID,
+		"deactivated_at":      bson.M{"$exists": false},
+	})
+	if err != nil {
+		return cascade.Affected{}, err
+	}
+	for _, u := range users {
+		affected.UserIDs = append(affected.UserIDs, u.ID)
+		affected.Locations[u.ID] = models.Hierarchy{
+			ShopID:       u.ShopID,
+			RegionID:     u.RegionID,
+			BranchID:     u.BranchID,
+			DepartmentID: u.DepartmentID,
+		}
+	}
+
+	return affected, nil
+}
+
+// rootHierarchy lấy vị trí của đơn vị bị xóa, trả về ErrTargetNotFound khi đơn vị không tồn tại
+func (repo implRepository) rootHierarchy(ctx context.Context, opts cascade.CollectOptions) (models.Hierarchy, error) {
+	var root located
+	if err := repo.db.Collection(rootCollection(opts.Level)).FindOne(ctx, bson.M{"_id": opts.ID}).Decode(&root); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.Hierarchy{}, cascade.ErrTargetNotFound
+		}
+		repo.l.Errorf(ctx, "cascade.repository.rootHierarchy.FindOne: %v", err)
+		return models.Hierarchy{}, err
+	}
+
+	switch opts.Level {
+	case cascade.LevelShop:
+		return models.Hierarchy{ShopID: root.ID}, nil
+	case cascade.LevelRegion:
+		return models.Hierarchy{ShopID: root.ShopID, RegionID: root.ID}, nil
+	}
+
+	// Branch chỉ lưu region_id nên shop lấy qua region
+	var r located
+	if err := repo.db.Collection(regionCollection).FindOne(ctx, bson.M{"_id": root.RegionID}).Decode(&r); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.Hierarchy{}, cascade.ErrTargetNotFound
+		}
+		repo.l.Errorf(ctx, "cascade.repository.rootHierarchy.FindOne(region): %v", err)
+		return models.Hierarchy{}, err
+	}
+	return models.Hierarchy{ShopID: r.ShopID, RegionID: r.ID, BranchID: root.ID}, nil
+}
+
+// recordRevisions lưu snapshot hiện tại của các document khớp filter vào lịch sử, trước khi bị sửa hoặc xóa.
+// Tên collection cũng là loại entity của revision.
+func (repo implRepository) recordRevisions(ctx context.Context, opts cascade.ApplyOptions, collection string, action models.RevisionAction, filter bson.M) error {
+	findOpts := options.Find()
+	if collection == userCollection {
+		findOpts.SetProjection(history.UserProjection)
+	}
+	cursor, err := repo.db.Collection(collection).Find(ctx, filter, findOpts)
+	if err != nil {
+		repo.l.Errorf(ctx, "cascade.repository.recordRevisions.Find(%s): %v", collection, err)
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var snapshots []bson.Raw
+	if err := cursor.All(ctx, &snapshots); err != nil {
+		repo.l.Errorf(ctx, "cascade.repository.recordRevisions.All(%s): %v", collection, err)
+		return err
+	}
+	if err := history.RecordRevisions(ctx, repo.db, collection, action, opts.ActorID, opts.Now, snapshots...); err != nil {
+		repo.l.Errorf(ctx, "cascade.repository.recordRevisions.history.RecordRevisions(%s): %v", collection, err)
+		return err
+	}
+	return nil
+}
+
+// located là _id cùng các field vị trí của một document, field nào không có thì để rỗng
+type located struct {
+	ID           primitive.ObjectID  `bson:"_id"`
+	ShopID       primitive.ObjectID  `bson:"shop_id,omitempty"`
+	RegionID     primitive.ObjectID  `bson:"region_id,omitempty"`
+	BranchID     primitive.ObjectID  `bson:"branch_id,omitempty"`
+	DepartmentID *primitive.ObjectID `bson:"department_id,omitempty"`
+}
+
+// findLocated trả về _id và vị trí của các document khớp filter
+func (repo implRepository) findLocated(ctx context.Context, collection string, filter bson.M) ([]located, error) {
+	projection := bson.M{"_id": 1, "shop_id": 1, "region_id": 1, "branch_id": 1, "department_id": 1}
+	cursor, err := repo.db.Collection(collection).Find(ctx, filter, options.Find().SetProjection(projection))
+	if err != nil {
+		repo.l.Errorf(ctx, "cascade.repository.findLocated.Find(%s): %v", collection, err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []located
+	if err := cursor.All(ctx, &docs); err != nil {
+		repo.l.Errorf(ctx, "cascade.repository.findLocated.All(%s): %v", collection, err)
+		return nil, err
+	}
+	return docs, nil
+}
+
+// Apply xóa đơn vị cùng các bản ghi con, sau đó chuyển hoặc vô hiệu hóa user
+func (repo implRepository) Apply(ctx context.Context, opts cascade.ApplyOptions) error {
+	// Bước 0: Đơn vị phải còn ở đúng version client đã đọc (Collect đã kiểm tra đơn vị tồn tại)
+	rootFilter := bson.M{"_id": opts.ID}
+	if opts.Version > 0 {
+		rootFilter["version"] = opts.Version
+		count, err := repo.db.Collection(rootCollection(opts.Level)).CountDocuments(ctx, rootFilter)
+		if err != nil {
+			repo.l.Errorf(ctx, "cascade.repository.Apply.CountDocuments: %v", err)
+			return err
+		}
+		if count == 0 {
+			return cascade.ErrVersionMismatch
+		}
+	}
+
+	// Bước 1: Xử lý user trước khi xóa cây tổ chức
+	if len(opts.Affected.UserIDs) > 0 {
+		var update bson.M
+		if opts.Reassign != nil {
+			update = bson.M{
+				"$set": bson.M{
+					"shop_id":    opts.Reassign.ShopID,
+					"region_id":  opts.Reassign.RegionID,
+					"branch_id":  opts.Reassign.BranchID,
+					"updated_at": opts.Now,
+					"updated_by": opts.ActorID,
+				},
+				"$unset": bson.M{"department_id": ""},
+				"$inc":   bson.M{"version": 1},
+			}
+		} else {
+			update = bson.M{
+				"$set": bson.M{
+					"deactivated_at": opts.Now,
+					"updated_at":     opts.Now,
+					"updated_by":     opts.ActorID,
+				},
+				"$inc": bson.M{"version": 1},
+			}
+		}
+
+		userFilter := bson.M{"_id": bson.M{"$in": opts.Affected.UserIDs}}
+		if err := repo.recordRevisions(ctx, opts, userCollection, models.RevisionUpdate, userFilter); err != nil {
+			return err
+		}
+		_, err := repo.db.Collection(userCollection).UpdateMany(ctx, userFilter, update)
+		if err != nil {
+			repo.l.Errorf(ctx, "cascade.repository.Apply.UpdateMany(users): %v", err)
+			return err
+		}
+	}
+
+	// Bước 2: Xóa từ dưới lên: department -> branch -> region
+	for _, step := range []struct {
+		collection string
+		ids        []primitive.ObjectID
+	}{
+		{departmentCollection, opts.Affected.DepartmentIDs},
+		{branchCollection, opts.Affected.BranchIDs},
+		{regionCollection, opts.Affected.RegionIDs},
+	} {
+		if len(step.ids) == 0 {
+			continue
+		}
+		filter := bson.M{"_id": bson.M{"$in": step.ids}}
+		if err := repo.recordRevisions(ctx, opts, step.collection, models.RevisionDelete, filter); err != nil {
+			return err
+		}
+		if _, err := repo.db.Collection(step.collection).DeleteMany(ctx, filter); err != nil {
+			repo.l.Errorf(ctx, "cascade.repository.Apply.DeleteMany(%s): %v", step.collection, err)
+			return err
+		}
+	}
+
+	// Bước 3: Xóa chính đơn vị
+	if err := repo.recordRevisions(ctx, opts, rootCollection(opts.Level), models.RevisionDelete, rootFilter); err != nil {
+		return err
+	}
+	if _, err := repo.db.Collection(rootCollection(opts.Level)).DeleteOne(ctx, rootFilter); err != nil {
+		repo.l.Errorf(ctx, "cascade.repository.Apply.DeleteOne: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetBranchHierarchy lấy shop, region của một branch
+func (repo implRepository) GetBranchHierarchy(ctx context.Context, branchID primitive.ObjectID) (models.Hierarchy, error) {
+	var b models.Branch
+	if err := repo.db.Collection(branchCollection).FindOne(ctx, bson.M{"_id": branchID}).Decode(&b); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.Hierarchy{}, cascade.ErrReassignBranchNotFound
+		}
+		repo.l.Errorf(ctx, "cascade.repository.GetBranchHierarchy.FindOne(branch): %v", err)
+		return models.Hierarchy{}, err
+	}
+
+	var r models.Region
+	if err := repo.db.Collection(regionCollection).FindOne(ctx, bson.M{"_id": b.RegionID}).Decode(&r); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.Hierarchy{}, cascade.ErrReassignBranchNotFound
+		}
+		repo.l.Errorf(ctx, "cascade.repository.GetBranchHierarchy.FindOne(region): %v", err)
+		return models.Hierarchy{}, err
+	}
+
+	return models.Hierarchy{
+		ShopID:   r.ShopID,
+		RegionID: r.ID,
+		BranchID: b.ID,
+	}, nil
+}
@@ -0,0 +1,21 @@
+package mongo
+
+import (
+	"thuchanhgolang/internal/cascade"
+	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/mongo"
+)
+
+// implRepository là implementation của cascade.Repository
+type implRepository struct {
+	l  log.Logger     // Logger để ghi log
+	db mongo.Database // Database connection
+}
+
+// NewRepository tạo một cascade repository mới
+func NewRepository(l log.Logger, db mongo.Database) cascade.Repository {
+	return &implRepository{
+		l:  l,
+		db: db,
+	}
+}
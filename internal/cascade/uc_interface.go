@@ -0,0 +1,12 @@
+package cascade
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/models"
+)
+
+// Usecase định nghĩa business logic cho xóa dây chuyền shop, region, branch
+type Usecase interface {
+	Delete(ctx context.Context, sc models.Scope, input DeleteInput) (DeleteOutput, error)
+}
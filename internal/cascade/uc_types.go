@@ -0,0 +1,23 @@
+package cascade
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// DeleteInput là input để xóa dây chuyền một đơn vị
+type DeleteInput struct {
+	Level  Level
+	ID     primitive.ObjectID
+	DryRun bool // Chỉ trả về danh sách bị ảnh hưởng, không xóa
+	// ReassignBranchID là branch nhận user, nil thì user bị vô hiệu hóa
+	ReassignBranchID *primitive.ObjectID
+	// Version là version client đã đọc của đơn vị bị xóa (If-Match), 0 → không kiểm tra
+	Version int
+}
+
+// DeleteOutput là kết quả xóa dây chuyền
+type DeleteOutput struct {
+	Level            Level
+	ID               primitive.ObjectID
+	DryRun           bool
+	Affected         Affected
+	ReassignBranchID *primitive.ObjectID
+}
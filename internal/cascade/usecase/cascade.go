@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"thuchanhgolang/internal/cascade"
+	"thuchanhgolang/internal/models"
+)
+
+// Delete xóa dây chuyền một shop, region hoặc branch.
+// Flow: Gom bản ghi con -> Kiểm tra phạm vi -> Kiểm tra branch nhận user -> (dry run thì dừng) -> Xóa và xử lý user.
+// Gom và xóa nằm trong cùng transaction để danh sách trả về khớp với những gì đã xóa.
+func (uc *implUsecase) Delete(ctx context.Context, sc models.Scope, input cascade.DeleteInput) (cascade.DeleteOutput, error) {
+	if !input.Level.IsValid() {
+		return cascade.DeleteOutput{}, cascade.ErrInvalidLevel
+	}
+
+	output := cascade.DeleteOutput{
+		Level:            input.Level,
+		ID:               input.ID,
+		DryRun:           input.DryRun,
+		ReassignBranchID: input.ReassignBranchID,
+	}
+
+	err := uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		// Bước 1: Gom các bản ghi con
+		affected, err := uc.repo.Collect(ctx, cascade.CollectOptions{
+			Level: input.Level,
+			ID:    input.ID,
+		})
+		if err != nil {
+			uc.l.Errorf(ctx, "cascade.usecase.Delete.repo.Collect: %v", err)
+			return err
+		}
+		output.Affected = affected
+
+		// Bước 2: Kiểm tra đơn vị cần xóa nằm trong phạm vi của người thực hiện
+		if !sc.Contains(affected.Root) {
+			uc.l.Warnf(ctx, "cascade.usecase.Delete: %s %s is out of scope", input.Level, input.ID.Hex())
+			return cascade.ErrOutOfScope
+		}
+
+		// Bước 3: Kiểm tra branch nhận user (nếu có)
+		reassign, err := uc.resolveReassign(ctx, sc, input, affected)
+		if err != nil {
+			return err
+		}
+
+		// Bước 4: Dry run chỉ trả về danh sách
+		if input.DryRun {
+			return nil
+		}
+
+		// Bước 5: Xóa và chuyển/vô hiệu hóa user
+		err = uc.repo.Apply(ctx, cascade.ApplyOptions{
+			Level:    input.Level,
+			ID:       input.ID,
+			Affected: affected,
+			Reassign: reassign,
+			Now:      time.Now(),
+			ActorID:  sc.UserID,
+			Version:  input.Version,
+		})
+		if err != nil {
+			uc.l.Errorf(ctx, "cascade.usecase.Delete.repo.Apply: %v", err)
+			return err
+		}
+
+		// Bước 6: Ghi event vào outbox cùng transaction với thao tác xóa
+		if err := uc.publisher.Publish(ctx, cascadeEvents(sc, input, affected, reassign)...); err != nil {
+			uc.l.Errorf(ctx, "cascade.usecase.Delete.publisher.Publish: %v", err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return cascade.DeleteOutput{}, err
+	}
+
+	return output, nil
+}
+
+// resolveReassign lấy vị trí mới cho user, trả về nil nếu không chuyển user.
+// Branch nhận user phải nằm trong phạm vi người thực hiện và cùng shop với đơn vị bị xóa.
+func (uc *implUsecase) resolveReassign(ctx context.Context, sc models.Scope, input cascade.DeleteInput, affected cascade.Affected) (*models.Hierarchy, error) {
+	if input.ReassignBranchID == nil {
+		return nil, nil
+	}
+
+	branchID := *input.ReassignBranchID
+	if (input.Level == cascade.LevelBranch && branchID == input.ID) || affected.HasBranch(branchID) {
+		uc.l.Warnf(ctx, "cascade.usecase.resolveReassign: branch %s is being deleted", branchID.Hex())
+		return nil, cascade.ErrReassignBranchDeleted
+	}
+
+	h, err := uc.repo.GetBranchHierarchy(ctx, branchID)
+	if err != nil {
+		uc.l.Errorf(ctx, "cascade.usecase.resolveReassign.repo.GetBranchHierarchy: %v", err)
+		return nil, err
+	}
+
+	if h.ShopID != affected.Root.ShopID {
+		uc.l.Warnf(ctx, "cascade.usecase.resolveReassign: branch %s belongs to another shop", branchID.Hex())
+		return nil, cascade.ErrReassignBranchOtherShop
+	}
+	if !sc.Contains(h) {
+		uc.l.Warnf(ctx, "cascade.usecase.resolveReassign: branch %s is out of scope", branchID.Hex())
+		return nil, cascade.ErrOutOfScope
+	}
+
+	return &h, nil
+}
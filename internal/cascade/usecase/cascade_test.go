@@ -0,0 +1,254 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"thuchanhgolang/internal/branch"
+	"thuchanhgolang/internal/cascade"
+	"thuchanhgolang/internal/department"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/region"
+	"thuchanhgolang/internal/user"
+	"thuchanhgolang/pkg/event"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDelete(t *testing.T) {
+	regionID := primitive.NewObjectID()
+	affected := cascade.Affected{
+		BranchIDs:     []primitive.ObjectID{primitive.NewObjectID()},
+		DepartmentIDs: []primitive.ObjectID{primitive.NewObjectID()},
+		UserIDs:       []primitive.ObjectID{primitive.NewObjectID()},
+		Root:          models.Hierarchy{ShopID: primitive.NewObjectID(), RegionID: regionID},
+	}
+	branchLocation := affected.Root
+	branchLocation.BranchID = affected.BranchIDs[0]
+	affected.Locations = map[primitive.ObjectID]models.Hierarchy{affected.BranchIDs[0]: branchLocation}
+	sc := models.Scope{Role: models.RoleManager, ShopID: &affected.Root.ShopID}
+
+	t.Run("dry run does not apply", func(t *testing.T) {
+		applied := false
+		mockRepo := &mockRepository{
+			collectFunc: func(ctx context.Context, opts cascade.CollectOptions) (cascade.Affected, error) {
+				return affected, nil
+			},
+			applyFunc: func(ctx context.Context, opts cascade.ApplyOptions) error {
+				applied = true
+				return nil
+			},
+		}
+
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		out, err := uc.Delete(context.Background(), sc, cascade.DeleteInput{
+			Level:  cascade.LevelRegion,
+			ID:     regionID,
+			DryRun: true,
+		})
+
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if applied {
+			t.Error("Dry run không được xóa dữ liệu")
+		}
+		if len(out.Affected.BranchIDs) != 1 || len(out.Affected.UserIDs) != 1 {
+			t.Errorf("Danh sách bị ảnh hưởng không đúng: %+v", out.Affected)
+		}
+	})
+
+	t.Run("delete and deactivate users in transaction", func(t *testing.T) {
+		var got cascade.ApplyOptions
+		mockRepo := &mockRepository{
+			collectFunc: func(ctx context.Context, opts cascade.CollectOptions) (cascade.Affected, error) {
+				return affected, nil
+			},
+			applyFunc: func(ctx context.Context, opts cascade.ApplyOptions) error {
+				got = opts
+				return nil
+			},
+		}
+
+		tx := &mockTransactor{}
+		publisher := event.NewMemoryPublisher()
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: tx, publisher: publisher}
+		_, err := uc.Delete(context.Background(), sc, cascade.DeleteInput{
+			Level: cascade.LevelRegion,
+			ID:    regionID,
+		})
+
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if tx.calls != 1 {
+			t.Errorf("Delete phải chạy trong transaction, số lần gọi: %d", tx.calls)
+		}
+		if got.ID != regionID || got.Reassign != nil {
+			t.Errorf("ApplyOptions không đúng: %+v", got)
+		}
+
+		// Region gốc, branch, department bị xóa và user bị vô hiệu hóa đều có event
+		wantTypes := []string{region.EventRegionDeleted, branch.EventBranchDeleted, department.EventDepartmentDeleted, user.EventUserDeactivated}
+		events := publisher.Events()
+		if len(events) != len(wantTypes) {
+			t.Fatalf("Mong đợi %d event, nhận được %+v", len(wantTypes), events)
+		}
+		for i, want := range wantTypes {
+			if events[i].Type != want {
+				t.Errorf("Event %d: mong đợi %s, nhận được %s", i, want, events[i].Type)
+			}
+		}
+		if events[0].AggregateID != regionID.Hex() || events[3].AggregateID != affected.UserIDs[0].Hex() {
+			t.Errorf("Aggregate của event không đúng: %+v", events)
+		}
+		if events[0].RegionID != regionID.Hex() || events[1].BranchID != affected.BranchIDs[0].Hex() {
+			t.Errorf("Event phải mang vị trí của bản ghi bị xóa: %+v", events)
+		}
+	})
+
+	t.Run("reassign users to another branch", func(t *testing.T) {
+		target := models.Hierarchy{ShopID: affected.Root.ShopID, RegionID: primitive.NewObjectID(), BranchID: primitive.NewObjectID()}
+		var got cascade.ApplyOptions
+		mockRepo := &mockRepository{
+			collectFunc: func(ctx context.Context, opts cascade.CollectOptions) (cascade.Affected, error) {
+				return affected, nil
+			},
+			getBranchHierarchyFunc: func(ctx context.Context, branchID primitive.ObjectID) (models.Hierarchy, error) {
+				return target, nil
+			},
+			applyFunc: func(ctx context.Context, opts cascade.ApplyOptions) error {
+				got = opts
+				return nil
+			},
+		}
+
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		_, err := uc.Delete(context.Background(), sc, cascade.DeleteInput{
+			Level:            cascade.LevelRegion,
+			ID:               regionID,
+			ReassignBranchID: &target.BranchID,
+		})
+
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if got.Reassign == nil || got.Reassign.BranchID != target.BranchID {
+			t.Errorf("User phải được chuyển sang branch %s", target.BranchID.Hex())
+		}
+	})
+
+	t.Run("reassign to deleted branch", func(t *testing.T) {
+		mockRepo := &mockRepository{
+			collectFunc: func(ctx context.Context, opts cascade.CollectOptions) (cascade.Affected, error) {
+				return affected, nil
+			},
+		}
+
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		_, err := uc.Delete(context.Background(), sc, cascade.DeleteInput{
+			Level:            cascade.LevelRegion,
+			ID:               regionID,
+			ReassignBranchID: &affected.BranchIDs[0],
+		})
+
+		if !errors.Is(err, cascade.ErrReassignBranchDeleted) {
+			t.Errorf("Mong đợi ErrReassignBranchDeleted, nhận được: %v", err)
+		}
+	})
+
+	t.Run("target not found", func(t *testing.T) {
+		mockRepo := &mockRepository{
+			collectFunc: func(ctx context.Context, opts cascade.CollectOptions) (cascade.Affected, error) {
+				return cascade.Affected{}, cascade.ErrTargetNotFound
+			},
+		}
+
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		_, err := uc.Delete(context.Background(), sc, cascade.DeleteInput{
+			Level: cascade.LevelShop,
+			ID:    primitive.NewObjectID(),
+		})
+
+		if !errors.Is(err, cascade.ErrTargetNotFound) {
+			t.Errorf("Mong đợi ErrTargetNotFound, nhận được: %v", err)
+		}
+	})
+
+	t.Run("target out of scope", func(t *testing.T) {
+		applied := false
+		mockRepo := &mockRepository{
+			collectFunc: func(ctx context.Context, opts cascade.CollectOptions) (cascade.Affected, error) {
+				return affected, nil
+			},
+			applyFunc: func(ctx context.Context, opts cascade.ApplyOptions) error {
+				applied = true
+				return nil
+			},
+		}
+
+		otherRegion := primitive.NewObjectID()
+		regionManager := models.Scope{Role: models.RoleRegionManager, ShopID: &affected.Root.ShopID, RegionID: &otherRegion}
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		_, err := uc.Delete(context.Background(), regionManager, cascade.DeleteInput{
+			Level: cascade.LevelRegion,
+			ID:    regionID,
+		})
+
+		if !errors.Is(err, cascade.ErrOutOfScope) {
+			t.Errorf("Mong đợi ErrOutOfScope, nhận được: %v", err)
+		}
+		if applied {
+			t.Error("Không được xóa đơn vị nằm ngoài phạm vi")
+		}
+	})
+
+	t.Run("reassign branch out of scope", func(t *testing.T) {
+		// Region manager xóa region của mình nhưng chuyển user sang branch thuộc region khác
+		other := models.Hierarchy{ShopID: affected.Root.ShopID, RegionID: primitive.NewObjectID(), BranchID: primitive.NewObjectID()}
+		mockRepo := &mockRepository{
+			collectFunc: func(ctx context.Context, opts cascade.CollectOptions) (cascade.Affected, error) {
+				return affected, nil
+			},
+			getBranchHierarchyFunc: func(ctx context.Context, branchID primitive.ObjectID) (models.Hierarchy, error) {
+				return other, nil
+			},
+		}
+
+		regionManager := models.Scope{Role: models.RoleRegionManager, ShopID: &affected.Root.ShopID, RegionID: &regionID}
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		_, err := uc.Delete(context.Background(), regionManager, cascade.DeleteInput{
+			Level:            cascade.LevelRegion,
+			ID:               regionID,
+			ReassignBranchID: &other.BranchID,
+		})
+
+		if !errors.Is(err, cascade.ErrOutOfScope) {
+			t.Errorf("Mong đợi ErrOutOfScope, nhận được: %v", err)
+		}
+	})
+
+	t.Run("reassign branch in another shop", func(t *testing.T) {
+		other := models.Hierarchy{ShopID: primitive.NewObjectID(), RegionID: primitive.NewObjectID(), BranchID: primitive.NewObjectID()}
+		mockRepo := &mockRepository{
+			collectFunc: func(ctx context.Context, opts cascade.CollectOptions) (cascade.Affected, error) {
+				return affected, nil
+			},
+			getBranchHierarchyFunc: func(ctx context.Context, branchID primitive.ObjectID) (models.Hierarchy, error) {
+				return other, nil
+			},
+		}
+
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		_, err := uc.Delete(context.Background(), sc, cascade.DeleteInput{
+			Level:            cascade.LevelRegion,
+			ID:               regionID,
+			ReassignBranchID: &other.BranchID,
+		})
+
+		if !errors.Is(err, cascade.ErrReassignBranchOtherShop) {
+			t.Errorf("Mong đợi ErrReassignBranchOtherShop, nhận được: %v", err)
+		}
+	})
+}
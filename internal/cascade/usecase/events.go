@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"thuchanhgolang/internal/branch"
+	"thuchanhgolang/internal/cascade"
+	"thuchanhgolang/internal/department"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/region"
+	"thuchanhgolang/internal/shop"
+	"thuchanhgolang/internal/user"
+	"thuchanhgolang/pkg/event"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// deletedEvent là loại aggregate và event xóa của một cấp
+type deletedEvent struct {
+	aggregateType string
+	typ           string
+}
+
+// deletedEvents là event xóa của đơn vị gốc theo từng cấp
+var deletedEvents = map[cascade.Level]deletedEvent{
+	cascade.LevelShop:   {aggregateType: shop.AggregateType, typ: shop.EventShopDeleted},
+	cascade.LevelRegion: {aggregateType: region.AggregateType, typ: region.EventRegionDeleted},
+	cascade.LevelBranch: {aggregateType: branch.AggregateType, typ: branch.EventBranchDeleted},
+}
+
+// cascadeEvents tạo event cho đơn vị bị xóa, các đơn vị con và user bị chuyển / vô hiệu hóa.
+// Event của bản ghi con mang theo đơn vị gốc để bên nhận biết chúng bị xóa theo dây chuyền.
+func cascadeEvents(sc models.Scope, input cascade.DeleteInput, affected cascade.Affected, reassign *models.Hierarchy) []event.Event {
+	root := deletedEvents[input.Level]
+	cause := map[string]interface{}{
+		"cascade_level": string(input.Level),
+		"cascade_id":    input.ID.Hex(),
+	}
+
+	var events []event.Event
+	add := func(typ, aggregateType string, ids []primitive.ObjectID, payload map[string]interface{}) {
+		for _, id := range ids {
+			e := event.New(typ, aggregateType, id.Hex(), sc.UserID, payload)
+			e.Location = affected.Locations[id].Location()
+			events = append(events, e)
+		}
+	}
+
+	rootEvent := event.New(root.typ, root.aggregateType, input.ID.Hex(), sc.UserID, map[string]interface{}{"cascade": true})
+	rootEvent.Location = affected.Root.Location()
+	events = append(events, rootEvent)
+
+	add(region.EventRegionDeleted, region.AggregateType, affected.RegionIDs, cause)
+	add(branch.EventBranchDeleted, branch.AggregateType, affected.BranchIDs, cause)
+	add(department.EventDepartmentDeleted, department.AggregateType, affected.DepartmentIDs, cause)
+
+	if reassign != nil {
+		moved := map[string]interface{}{
+			"to": user.HierarchyPayload(*reassign),
+		}
+		for k, v := range cause {
+			moved[k] = v
+		}
+		// User được chuyển đi nên event mang vị trí mới của user
+		for _, id := range affected.UserIDs {
+			e := event.New(user.EventUserMoved, user.AggregateType, id.Hex(), sc.UserID, moved)
+			e.Location = reassign.Location()
+			events = append(events, e)
+		}
+	} else {
+		add(user.EventUserDeactivated, user.AggregateType, affected.UserIDs, cause)
+	}
+
+	return events
+}
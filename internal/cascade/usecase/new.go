@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"thuchanhgolang/internal/cascade"
+	"thuchanhgolang/pkg/event"
+	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/mongo"
+)
+
+// implUsecase là implementation của cascade.Usecase
+type implUsecase struct {
+	l         log.Logger         // Logger để ghi log
+	repo      cascade.Repository // Repository để tương tác với database
+	tx        mongo.Transactor   // Toàn bộ thao tác xóa chạy trong một transaction
+	publisher event.Publisher    // Ghi event vào outbox cho các bản ghi bị xóa
+}
+
+// NewUsecase tạo usecase mới cho xóa dây chuyền
+func NewUsecase(l log.Logger, repo cascade.Repository, tx mongo.Transactor, publisher event.Publisher) cascade.Usecase {
+	return &implUsecase{
+		l:         l,
+		repo:      repo,
+		tx:        tx,
+		publisher: publisher,
+	}
+}
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/cascade"
+	"thuchanhgolang/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockRepository struct {
+	collectFunc            func(context.Context, cascade.CollectOptions) (cascade.Affected, error)
+	applyFunc              func(context.Context, cascade.ApplyOptions) error
+	getBranchHierarchyFunc func(context.Context, primitive.ObjectID) (models.Hierarchy, error)
+}
+
+func (m *mockRepository) Collect(ctx context.Context, opts cascade.CollectOptions) (cascade.Affected, error) {
+	if m.collectFunc != nil {
+		return m.collectFunc(ctx, opts)
+	}
+	return cascade.Affected{}, nil
+}
+
+func (m *mockRepository) Apply(ctx context.Context, opts cascade.ApplyOptions) error {
+	if m.applyFunc != nil {
+		return m.applyFunc(ctx, opts)
+	}
+	return nil
+}
+
+func (m *mockRepository) GetBranchHierarchy(ctx context.Context, branchID primitive.ObjectID) (models.Hierarchy, error) {
+	if m.getBranchHierarchyFunc != nil {
+		return m.getBranchHierarchyFunc(ctx, branchID)
+	}
+	return models.Hierarchy{}, nil
+}
+
+type mockTransactor struct {
+	calls int
+}
+
+func (m *mockTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	return fn(ctx)
+}
+
+type mockLogger struct{}
+
+func (m *mockLogger) Debug(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Debugf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Info(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Infof(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Warn(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Warnf(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Error(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Errorf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Fatal(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Fatalf(ctx context.Context, template string, arg ...any) {}
@@ -0,0 +1,11 @@
+package grpc
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errUnauthorized = status.Error(codes.Unauthenticated, "Unauthorized")
+	errForbidden    = status.Error(codes.PermissionDenied, "Forbidden: You don't have permission to access this resource")
+)
@@ -0,0 +1,100 @@
+package grpc
+
+import (
+	"context"
+
+	"thuchanhgolang/pkg/orgapi"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Get lấy department theo ID
+func (h handler) Get(ctx context.Context, req *orgapi.GetRequest) (*orgapi.Department, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để lấy department
+	id, _ := primitive.ObjectIDFromHex(req.ID)
+	department, err := h.uc.GetByID(ctx, sc, id)
+	if err != nil {
+		h.l.Warnf(ctx, "department.grpc.Get.uc.GetByID: %s", err)
+		return nil, err
+	}
+
+	return h.newDepartment(department), nil
+}
+
+// List lấy danh sách department có phân trang
+func (h handler) List(ctx context.Context, req *orgapi.ListDepartmentsRequest) (*orgapi.ListDepartmentsResponse, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để lấy danh sách
+	out, err := h.uc.List(ctx, sc, h.toListInput(req))
+	if err != nil {
+		h.l.Warnf(ctx, "department.grpc.List.uc.List: %s", err)
+		return nil, err
+	}
+
+	return h.newListResp(out), nil
+}
+
+// Create tạo department mới
+func (h handler) Create(ctx context.Context, req *orgapi.CreateDepartmentRequest) (*orgapi.Department, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để tạo department
+	department, err := h.uc.Create(ctx, sc, h.toCreateInput(req))
+	if err != nil {
+		h.l.Warnf(ctx, "department.grpc.Create.uc.Create: %s", err)
+		return nil, err
+	}
+
+	return h.newDepartment(department), nil
+}
+
+// Update cập nhật department, chỉ khi department chưa bị sửa sau version client đã đọc
+func (h handler) Update(ctx context.Context, req *orgapi.UpdateDepartmentRequest) (*orgapi.Department, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để cập nhật department
+	department, err := h.uc.Update(ctx, sc, h.toUpdateInput(req))
+	if err != nil {
+		h.l.Warnf(ctx, "department.grpc.Update.uc.Update: %s", err)
+		return nil, err
+	}
+
+	return h.newDepartment(department), nil
+}
+
+// Delete xóa department, chỉ khi department chưa bị sửa sau version client đã đọc
+func (h handler) Delete(ctx context.Context, req *orgapi.DeleteRequest) (*orgapi.Empty, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để xóa department
+	id, _ := primitive.ObjectIDFromHex(req.ID)
+	if err := h.uc.Delete(ctx, sc, id, req.Version); err != nil {
+		h.l.Warnf(ctx, "department.grpc.Delete.uc.Delete: %s", err)
+		return nil, err
+	}
+
+	return &orgapi.Empty{}, nil
+}
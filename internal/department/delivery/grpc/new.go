@@ -0,0 +1,21 @@
+package grpc
+
+import (
+	"thuchanhgolang/internal/department"
+	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/orgapi"
+)
+
+// handler là implementation của orgapi.DepartmentServiceServer
+type handler struct {
+	l  log.Logger         // Logger để ghi log
+	uc department.Usecase // Usecase để xử lý business logic
+}
+
+// New tạo gRPC service cho department, dùng chung usecase với REST
+func New(l log.Logger, uc department.Usecase) orgapi.DepartmentServiceServer {
+	return handler{
+		l:  l,
+		uc: uc,
+	}
+}
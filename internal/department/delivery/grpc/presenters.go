@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"thuchanhgolang/internal/department"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/orgapi"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// toCreateInput chuyển đổi request thành input cho usecase, định dạng đã được kiểm tra qua binding tag
+func (h handler) toCreateInput(req *orgapi.CreateDepartmentRequest) department.CreateInput {
+	branchID, _ := primitive.ObjectIDFromHex(req.BranchID)
+	return department.CreateInput{
+		BranchID: branchID,
+		Name:     req.Name,
+	}
+}
+
+// toUpdateInput chuyển đổi request thành input cho usecase
+func (h handler) toUpdateInput(req *orgapi.UpdateDepartmentRequest) department.UpdateInput {
+	id, _ := primitive.ObjectIDFromHex(req.ID)
+	return department.UpdateInput{
+		ID:      id,
+		Name:    req.Name,
+		Version: req.Version,
+	}
+}
+
+// toListInput chuyển đổi request thành input cho usecase
+func (h handler) toListInput(req *orgapi.ListDepartmentsRequest) department.ListInput {
+	input := department.ListInput{
+		Pagin: paginator.PaginatorQuery{Page: req.Page.Page, Limit: req.Page.Limit},
+	}
+	if req.BranchID != "" {
+		branchID, _ := primitive.ObjectIDFromHex(req.BranchID)
+		input.BranchID = &branchID
+	}
+	return input
+}
+
+// newDepartment tạo message từ department model
+func (h handler) newDepartment(d models.Department) *orgapi.Department {
+	return &orgapi.Department{
+		ID:        d.ID.Hex(),
+		BranchID:  d.BranchID.Hex(),
+		Name:      d.Name,
+		Version:   d.Version,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+		CreatedBy: d.CreatedBy,
+		UpdatedBy: d.UpdatedBy,
+	}
+}
+
+// newListResp tạo message từ danh sách department
+func (h handler) newListResp(out department.ListOutput) *orgapi.ListDepartmentsResponse {
+	items := make([]orgapi.Department, 0, len(out.Departments))
+	for _, d := range out.Departments {
+		items = append(items, *h.newDepartment(d))
+	}
+	return &orgapi.ListDepartmentsResponse{
+		Items: items,
+		Meta:  newPageInfo(out.Pagin),
+	}
+}
+
+// newPageInfo tạo thông tin phân trang
+func newPageInfo(p paginator.Paginator) orgapi.PageInfo {
+	resp := p.ToResponse()
+	return orgapi.PageInfo{
+		Total:       resp.Total,
+		Count:       resp.Count,
+		PerPage:     resp.PerPage,
+		CurrentPage: resp.CurrentPage,
+		TotalPages:  resp.TotalPages,
+	}
+}
@@ -0,0 +1,27 @@
+package grpc
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/jwt"
+)
+
+// processScope lấy scope của user đang đăng nhập và kiểm tra quyền như middleware CheckDepartmentAccess của REST:
+// Manager, RegionManager, BranchManager hoặc HeadOfDepartment
+func (h handler) processScope(ctx context.Context) (models.Scope, error) {
+	payload, ok := jwt.GetPayloadFromContext(ctx)
+	if !ok {
+		return models.Scope{}, errUnauthorized
+	}
+
+	role := models.Role(payload.Role)
+	switch role {
+	case models.RoleManager, models.RoleRegionManager, models.RoleBranchManager, models.RoleHeadOfDepartment:
+	default:
+		h.l.Warnf(ctx, "department.grpc.processScope: user role %s not allowed", role)
+		return models.Scope{}, errForbidden
+	}
+
+	return jwt.NewScope(payload), nil
+}
@@ -2,15 +2,26 @@ package http
 
 import (
 	"errors"
+	"net/http"
 	"thuchanhgolang/internal/department"
 	pkgErrors "thuchanhgolang/pkg/errors"
 )
 
 var (
-	errWrongBody       = pkgErrors.NewHTTPError(10000, "Wrong body")
-	errInvalidID       = pkgErrors.NewHTTPError(10001, "Invalid department ID")
-	errInvalidbranchID = pkgErrors.NewHTTPError(10002, "Invalid branch ID")
-	errDepartmentInUse = pkgErrors.NewHTTPError(10004, "department is being used by users, cannot delete")
+	errWrongBody          = pkgErrors.NewHTTPError(13000, "Wrong body")
+	errInvalidID          = pkgErrors.NewHTTPError(13001, "Invalid department ID")
+	errInvalidbranchID    = pkgErrors.NewHTTPError(13002, "Invalid branch ID")
+	errDepartmentInUse    = pkgErrors.NewHTTPErrorWithStatus(13004, "department is being used by users, cannot delete", http.StatusConflict)
+	errDepartmentNotFound = pkgErrors.NewHTTPErrorWithStatus(13005, "Department not found", http.StatusNotFound)
+	errBranchNotFound     = pkgErrors.NewHTTPErrorWithStatus(13006, "Target branch not found", http.StatusNotFound)
+	errDifferentShop      = pkgErrors.NewHTTPErrorWithStatus(13007, "Target branch belongs to another shop", http.StatusUnprocessableEntity)
+	errMoveOutOfScope     = pkgErrors.NewHTTPErrorWithStatus(13008, "Department or target branch is outside of your scope", http.StatusForbidden)
+	errUnauthorized       = pkgErrors.NewUnauthorizedHTTPError()
+
+	errVersionMismatch      = pkgErrors.NewHTTPErrorWithStatus(13009, "Department has been modified by another request, reload and try again", http.StatusPreconditionFailed)
+	errPreconditionRequired = pkgErrors.NewHTTPErrorWithStatus(13010, "If-Match header with the department ETag is required", http.StatusPreconditionRequired)
+
+	errWrongQuery = pkgErrors.NewHTTPError(13011, "Wrong query")
 )
 
 func (h handler) mapError(err error) error {
@@ -18,5 +29,20 @@ func (h handler) mapError(err error) error {
 	if errors.Is(err, department.ErrDepartmentInUse) {
 		return errDepartmentInUse
 	}
+	if errors.Is(err, department.ErrDepartmentNotFound) {
+		return errDepartmentNotFound
+	}
+	if errors.Is(err, department.ErrTargetBranchNotFound) {
+		return errBranchNotFound
+	}
+	if errors.Is(err, department.ErrDifferentShop) {
+		return errDifferentShop
+	}
+	if errors.Is(err, department.ErrMoveOutOfScope) {
+		return errMoveOutOfScope
+	}
+	if errors.Is(err, department.ErrVersionMismatch) {
+		return errVersionMismatch
+	}
 	return err
 }
@@ -1,7 +1,9 @@
 package http
 
 import (
+	"thuchanhgolang/internal/models"
 	"thuchanhgolang/pkg/response"
+	"thuchanhgolang/pkg/util"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -30,6 +32,7 @@ func (h handler) create(c *gin.Context) {
 	}
 
 	// Bước 3: Trả về kết quả thành công
+	util.SetETag(c, department.Version)
 	response.OK(c, h.newDetailResp(department))
 }
 
@@ -46,8 +49,21 @@ func (h handler) getByID(c *gin.Context) {
 		return
 	}
 
-	// Bước 2: Gọi usecase để lấy department
-	department, err := h.uc.GetByID(ctx, h.emptyScope(), id)
+	// Bước 2: Đọc thời điểm cần xem (as_of), không có thì xem trạng thái hiện tại
+	asOf, err := h.processAsOf(c)
+	if err != nil {
+		h.l.Warnf(ctx, "department.handler.getByID.processAsOf: %s", err)
+		response.Error(c, err)
+		return
+	}
+
+	// Bước 3: Gọi usecase để lấy department
+	var department models.Department
+	if asOf != nil {
+		department, err = h.uc.GetAsOf(ctx, h.emptyScope(), id, *asOf)
+	} else {
+		department, err = h.uc.GetByID(ctx, h.emptyScope(), id)
+	}
 	if err != nil {
 		h.l.Warnf(ctx, "department.handler.getByID.uc.GetByID: %s", err)
 		mapErr := h.mapError(err)
@@ -55,10 +71,74 @@ func (h handler) getByID(c *gin.Context) {
 		return
 	}
 
-	// Bước 3: Trả về kết quả
+	// Bước 4: Trả về kết quả, trạng thái trong quá khứ không có ETag vì không dùng để sửa được
+	if asOf == nil {
+		util.SetETag(c, department.Version)
+	}
 	response.OK(c, h.newDetailResp(department))
 }
 
+// history xử lý HTTP request để lấy lịch sử thay đổi của department
+func (h handler) history(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Lấy ID từ URL param
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		h.l.Warnf(ctx, "department.handler.history.ObjectIDFromHex: %s", err)
+		response.Error(c, errInvalidID)
+		return
+	}
+
+	// Bước 2: Xử lý và validate query
+	req, sc, err := h.processHistoryRequest(c)
+	if err != nil {
+		h.l.Warnf(ctx, "department.handler.history.processHistoryRequest: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 3: Gọi usecase để lấy lịch sử thay đổi
+	out, err := h.uc.History(ctx, sc, req.toInput(id))
+	if err != nil {
+		h.l.Warnf(ctx, "department.handler.history.uc.History: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 4: Trả về lịch sử, mới nhất đứng trước
+	response.OK(c, h.newHistoryResp(out))
+}
+
+// list xử lý HTTP request để lấy danh sách department
+func (h handler) list(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Xử lý và validate query
+	req, sc, err := h.processListRequest(c)
+	if err != nil {
+		h.l.Warnf(ctx, "department.handler.list.processListRequest: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 2: Gọi usecase để lấy danh sách department
+	out, err := h.uc.List(ctx, sc, req.toInput())
+	if err != nil {
+		h.l.Warnf(ctx, "department.handler.list.uc.List: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 3: Trả về danh sách
+	response.OK(c, h.newListResp(out))
+}
+
 // update xử lý HTTP request để cập nhật region
 func (h handler) update(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -91,6 +171,43 @@ func (h handler) update(c *gin.Context) {
 	}
 
 	// Bước 4: Trả về kết quả
+	util.SetETag(c, department.Version)
+	response.OK(c, h.newDetailResp(department))
+}
+
+// patch xử lý HTTP request cập nhật một phần region theo JSON Merge Patch
+func (h handler) patch(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Lấy ID từ URL param
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		h.l.Warnf(ctx, "department.handler.patch.ObjectIDFromHex: %s", err)
+		response.Error(c, errInvalidID)
+		return
+	}
+
+	// Bước 2: Xử lý và validate request
+	req, sc, err := h.processPatchRequest(c)
+	if err != nil {
+		h.l.Warnf(ctx, "department.handler.patch.processPatchRequest: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 3: Gọi usecase để update region
+	department, err := h.uc.Update(ctx, sc, req.toInput(id))
+	if err != nil {
+		h.l.Warnf(ctx, "department.handler.patch.uc.Update: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 4: Trả về kết quả
+	util.SetETag(c, department.Version)
 	response.OK(c, h.newDetailResp(department))
 }
 
@@ -107,8 +224,24 @@ func (h handler) delete(c *gin.Context) {
 		return
 	}
 
-	// Bước 2: Gọi usecase để xóa department
-	err = h.uc.Delete(ctx, h.emptyScope(), id)
+	// Bước 2: Lấy scope của user đang đăng nhập
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "department.handler.delete.processScope: %s", err)
+		response.Error(c, err)
+		return
+	}
+
+	// Bước 3: Đọc version từ If-Match
+	version, err := h.processVersion(c)
+	if err != nil {
+		h.l.Warnf(ctx, "department.handler.delete.processVersion: %s", err)
+		response.Error(c, err)
+		return
+	}
+
+	// Bước 4: Gọi usecase để xóa department
+	err = h.uc.Delete(ctx, sc, id, version)
 	if err != nil {
 		h.l.Warnf(ctx, "department.handler.delete.uc.Delete: %s", err)
 		mapErr := h.mapError(err)
@@ -116,6 +249,42 @@ func (h handler) delete(c *gin.Context) {
 		return
 	}
 
-	// Bước 3: Trả về success
+	// Bước 5: Trả về success
 	response.OK(c, gin.H{"message": "Department deleted successfully"})
 }
+
+// move xử lý HTTP request để chuyển department sang branch khác
+func (h handler) move(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Lấy ID từ URL param
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		h.l.Warnf(ctx, "department.handler.move.ObjectIDFromHex: %s", err)
+		response.Error(c, errInvalidID)
+		return
+	}
+
+	// Bước 2: Xử lý và validate request
+	req, sc, err := h.processMoveRequest(c)
+	if err != nil {
+		h.l.Warnf(ctx, "department.handler.move.processMoveRequest: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 3: Gọi usecase để chuyển department
+	d, err := h.uc.Move(ctx, sc, req.toInput(id))
+	if err != nil {
+		h.l.Warnf(ctx, "department.handler.move.uc.Move: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 4: Trả về kết quả
+	util.SetETag(c, d.Version)
+	response.OK(c, h.newDetailResp(d))
+}
@@ -17,8 +17,12 @@ type handler struct {
 type Handler interface {
 	create(c *gin.Context)
 	getByID(c *gin.Context)
+	history(c *gin.Context)
+	list(c *gin.Context)
 	update(c *gin.Context)
+	patch(c *gin.Context)
 	delete(c *gin.Context)
+	move(c *gin.Context)
 }
 
 // New tạo HTTP handler mới cho region
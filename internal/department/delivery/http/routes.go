@@ -1,11 +1,43 @@
 package http
 
-import "github.com/gin-gonic/gin"
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"thuchanhgolang/pkg/openapi"
+	"thuchanhgolang/pkg/patch"
+)
 
 // MapRoutes maps the routes to the handler functions
 func MapRoutes(r *gin.RouterGroup, h Handler) {
-	r.POST("", h.create)       // Tạo department mới
-	r.GET("/:id", h.getByID)   // Lấy department theo ID
-	r.PUT("/:id", h.update)    // Cập nhật department theo ID
-	r.DELETE("/:id", h.delete) // Xóa department theo ID
+	r.POST("", h.create)             // Tạo department mới
+	r.GET("", h.list)                // Lấy danh sách department, lọc theo thời gian tạo / cập nhật
+	r.GET("/:id", h.getByID)         // Lấy department theo ID
+	r.GET("/:id/history", h.history) // Xem lịch sử thay đổi, mới nhất đứng trước
+	r.PUT("/:id", h.update)          // Cập nhật department theo ID
+	r.PATCH("/:id", h.patch)         // Cập nhật một phần department theo JSON Merge Patch
+	r.DELETE("/:id", h.delete)       // Xóa department theo ID
+	r.POST("/:id/move", h.move)      // Chuyển department sang branch khác
+}
+
+// Spec mô tả các route của MapRoutes cho OpenAPI, sửa route thì sửa cả ở đây
+func Spec() []openapi.Route {
+	return []openapi.Route{
+		{Method: http.MethodPost, Path: "", Summary: "Tạo department mới", Body: createReq{}, Response: detailResp{}, ETag: true},
+		{Method: http.MethodGet, Path: "", Summary: "Lấy danh sách department, lọc theo thời gian tạo / cập nhật", Query: listReq{}, Response: listResp{}},
+		{
+			Method: http.MethodGet, Path: "/:id", Summary: "Lấy department theo ID",
+			Params:   []openapi.Parameter{openapi.QueryParam("as_of", "Thời điểm cần xem, định dạng 2006-01-02 15:04:05")},
+			Response: detailResp{}, ETag: true,
+		},
+		{Method: http.MethodGet, Path: "/:id/history", Summary: "Xem lịch sử thay đổi, mới nhất đứng trước", Query: historyReq{}, Response: historyResp{}},
+		{Method: http.MethodPut, Path: "/:id", Summary: "Cập nhật department theo ID", IfMatch: true, Body: updateReq{}, Response: detailResp{}, ETag: true},
+		{
+			Method: http.MethodPatch, Path: "/:id", Summary: "Cập nhật một phần department theo JSON Merge Patch, field không gửi thì giữ nguyên",
+			IfMatch: true, Body: patchReq{}, BodyTypes: []string{patch.ContentType, openapi.ContentJSON}, Response: detailResp{}, ETag: true,
+		},
+		{Method: http.MethodDelete, Path: "/:id", Summary: "Xóa department theo ID", IfMatch: true, Response: gin.H{}},
+		{Method: http.MethodPost, Path: "/:id/move", Summary: "Chuyển department sang branch khác", Body: moveReq{}, Response: detailResp{}, ETag: true},
+	}
 }
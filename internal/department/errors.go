@@ -1,8 +1,23 @@
 package department
 
-import "errors"
+import pkgErrors "thuchanhgolang/pkg/errors"
 
 var (
 	// ErrDepartmentInUse trả về khi department đang được sử dụng bởi users
-	ErrDepartmentInUse = errors.New("department is being used by users, cannot delete")
+	ErrDepartmentInUse = pkgErrors.Conflict("department is being used by users, cannot delete")
+
+	// ErrDepartmentNotFound trả về khi không tìm thấy department
+	ErrDepartmentNotFound = pkgErrors.NotFound("department not found")
+
+	// ErrTargetBranchNotFound trả về khi không tìm thấy branch đích khi chuyển department
+	ErrTargetBranchNotFound = pkgErrors.NotFound("target branch not found")
+
+	// ErrDifferentShop trả về khi branch đích thuộc shop khác
+	ErrDifferentShop = pkgErrors.Invalid("target branch belongs to another shop")
+
+	// ErrMoveOutOfScope trả về khi department hoặc branch đích nằm ngoài scope của user
+	ErrMoveOutOfScope = pkgErrors.Forbidden("department or target branch is outside of your scope")
+
+	// ErrVersionMismatch trả về khi department đã bị thay đổi sau lần đọc của client (If-Match không khớp version)
+	ErrVersionMismatch = pkgErrors.PreconditionFailed("department has been modified by another request")
 )
@@ -0,0 +1,15 @@
+package department
+
+const (
+	// AggregateType là loại aggregate của các event department
+	AggregateType = "department"
+
+	// EventDepartmentCreated được phát khi department được tạo
+	EventDepartmentCreated = "department.created"
+	// EventDepartmentUpdated được phát khi thông tin department thay đổi
+	EventDepartmentUpdated = "department.updated"
+	// EventDepartmentDeleted được phát khi department bị xóa (kể cả xóa dây chuyền)
+	EventDepartmentDeleted = "department.deleted"
+	// EventDepartmentMoved được phát khi department chuyển sang branch khác
+	EventDepartmentMoved = "department.moved"
+)
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/paginator"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -18,12 +19,18 @@ type Repository interface {
 	// GetByID lấy branch theo ID từ database
 	GetByID(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Department, error)
 
+	// List lấy danh sách department theo bộ lọc, có phân trang
+	List(ctx context.Context, sc models.Scope, opts ListOptions) ([]models.Department, paginator.Paginator, error)
+
 	// Update cập nhật region trong database
 	Update(ctx context.Context, sc models.Scope, opts UpdateOptions) (models.Department, error)
 
-	// Delete xóa region khỏi database
-	Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error
+	// Delete xóa region khỏi database, version là version client đã đọc (0 → không kiểm tra)
+	Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error
 
 	// HasBranches kiểm tra xem region có branch nào không
 	HasUsers(ctx context.Context, departmentID primitive.ObjectID) (bool, error)
+
+	// Move đổi branch của department và ghi lại hierarchy của các user thuộc department
+	Move(ctx context.Context, sc models.Scope, opts MoveOptions) (models.Department, error)
 }
@@ -1,6 +1,11 @@
 package department
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // CreateInput là dữ liệu đầu vào để tạo branch mới
 type CreateOptions struct {
@@ -10,6 +15,23 @@ type CreateOptions struct {
 
 // UpdateOptions là tùy chọn để cập nhật branch
 type UpdateOptions struct {
-	ID   primitive.ObjectID // ID branch cần cập nhật
-	Name string             // Tên mới
+	ID      primitive.ObjectID // ID branch cần cập nhật
+	Name    *string            // Tên mới, nil → giữ nguyên
+	Version int                // Version client đã đọc (If-Match), 0 → không kiểm tra
+}
+
+// MoveOptions là tùy chọn để chuyển department sang branch khác
+type MoveOptions struct {
+	ID       primitive.ObjectID // ID department cần chuyển
+	BranchID primitive.ObjectID // Branch đích
+	RegionID primitive.ObjectID // Region của branch đích (ghi lại vào user)
+	ShopID   primitive.ObjectID // Shop của branch đích (ghi lại vào user)
+	Version  int                // Version client đã đọc (If-Match), 0 → không kiểm tra
+}
+
+// ListOptions là bộ lọc và phân trang khi lấy danh sách department
+type ListOptions struct {
+	BranchID *primitive.ObjectID      // Chỉ lấy department thuộc branch này (nil = mọi branch)
+	Filter   models.TimeFilter        // Lọc theo thời gian tạo, cập nhật
+	Pagin    paginator.PaginatorQuery // Trang cần lấy (đã Adjust)
 }
@@ -2,13 +2,18 @@ package mongo
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"thuchanhgolang/internal/department"
+	"thuchanhgolang/internal/history"
 	"thuchanhgolang/internal/models"
 	"thuchanhgolang/pkg/mongo"
+	"thuchanhgolang/pkg/paginator"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const (
@@ -23,12 +28,18 @@ func (repo implRepository) getDepartmentCollection() mongo.Collection {
 // Create tạo region mới trong MongoDB
 func (repo implRepository) Create(ctx context.Context, sc models.Scope, opts department.CreateOptions) (models.Department, error) {
 	col := repo.getDepartmentCollection()
+	now := time.Now()
 
 	// Tạo department object mới
 	newDepartment := models.Department{
-		ID:       repo.db.NewObjectID(),
-		BranchID: opts.BranchID,
-		Name:     opts.Name,
+		ID:        repo.db.NewObjectID(),
+		BranchID:  opts.BranchID,
+		Name:      opts.Name,
+		CreatedAt: now,
+		UpdatedAt: now,
+		CreatedBy: sc.UserID,
+		UpdatedBy: sc.UserID,
+		Version:   1,
 	}
 
 	// Lưu vào database
@@ -57,21 +68,84 @@ func (repo implRepository) GetByID(ctx context.Context, sc models.Scope, id prim
 	return department, nil
 }
 
+// List lấy danh sách department theo bộ lọc, department tạo sau đứng trước
+func (repo implRepository) List(ctx context.Context, sc models.Scope, opts department.ListOptions) ([]models.Department, paginator.Paginator, error) {
+	col := repo.getDepartmentCollection()
+
+	// Bước 1: Đếm tổng số department khớp bộ lọc
+	filter := repo.buildListQuery(opts)
+	total, err := col.CountDocuments(ctx, filter)
+	if err != nil {
+		repo.l.Errorf(ctx, "department.mongo.List.CountDocuments: %v", err)
+		return nil, paginator.Paginator{}, err
+	}
+
+	// Bước 2: Lấy department của trang hiện tại
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetSkip(opts.Pagin.Offset()).
+		SetLimit(opts.Pagin.Limit)
+	cursor, err := col.Find(ctx, filter, findOpts)
+	if err != nil {
+		repo.l.Errorf(ctx, "department.mongo.List.Find: %v", err)
+		return nil, paginator.Paginator{}, err
+	}
+
+	var departments []models.Department
+	if err := cursor.All(ctx, &departments); err != nil {
+		repo.l.Errorf(ctx, "department.mongo.List.All: %v", err)
+		return nil, paginator.Paginator{}, err
+	}
+
+	return departments, paginator.Paginator{
+		Total:       total,
+		Count:       int64(len(departments)),
+		PerPage:     opts.Pagin.Limit,
+		CurrentPage: opts.Pagin.Page,
+	}, nil
+}
+
 // Update cập nhật thông tin region trong MongoDB (chỉ cho phép đổi tên)
 func (repo implRepository) Update(ctx context.Context, sc models.Scope, opts department.UpdateOptions) (models.Department, error) {
 	col := repo.getDepartmentCollection()
 
-	// Chỉ update name, không cho phép đổi shop_id
-	update := bson.M{
-		"name": opts.Name,
+	// Chỉ update name, không cho phép đổi cha (dùng Move), field nil thì giữ nguyên
+	update := mongo.NewUpdate()
+	mongo.SetPtr(update, "name", opts.Name)
+
+	// Nếu không có gì để update
+	if !update.HasChanges() {
+		current, err := repo.GetByID(ctx, sc, opts.ID)
+		if err != nil {
+			return models.Department{}, err
+		}
+		if opts.Version > 0 && current.Version != opts.Version {
+			return models.Department{}, department.ErrVersionMismatch
+		}
+		return current, nil
 	}
 
-	// Update branch trong database
+	// Ghi lại thời điểm và người sửa
+	now := time.Now()
+	update.Set("updated_at", now).Set("updated_by", sc.UserID).Inc("version", 1)
+
+	// Update branch trong database, chỉ khi version khớp với version client đã đọc, đồng thời tăng version.
+	// Lấy về department trước khi update để lưu vào lịch sử
 	filter := bson.M{"_id": opts.ID}
-	updateDoc := bson.M{"$set": update}
-	_, err := col.UpdateOne(ctx, filter, updateDoc)
+	if opts.Version > 0 {
+		filter["version"] = opts.Version
+	}
+	var prior bson.Raw
+	err := col.FindOneAndUpdate(ctx, filter, update.Document()).Decode(&prior)
+	if errors.Is(err, mongo.ErrNoDocuments) && opts.Version > 0 {
+		return models.Department{}, repo.versionError(ctx, sc, opts.ID)
+	}
 	if err != nil {
-		repo.l.Errorf(ctx, "department.mongo.Update.UpdateOne: %v", err)
+		repo.l.Errorf(ctx, "department.mongo.Update.FindOneAndUpdate: %v", err)
+		return models.Department{}, err
+	}
+	if err := history.RecordRevisions(ctx, repo.db, history.EntityDepartment, models.RevisionUpdate, sc.UserID, now, prior); err != nil {
+		repo.l.Errorf(ctx, "department.mongo.Update.history.RecordRevisions: %v", err)
 		return models.Department{}, err
 	}
 
@@ -80,17 +154,31 @@ func (repo implRepository) Update(ctx context.Context, sc models.Scope, opts dep
 }
 
 // Delete xóa department khỏi MongoDB
-func (repo implRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+func (repo implRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
 	col := repo.getDepartmentCollection()
 
-	// Xóa department theo ID
+	// Xóa department theo ID, lấy về department đã xóa để lưu vào lịch sử
 	filter := bson.M{"_id": id}
-	_, err := col.DeleteOne(ctx, filter)
+	if version > 0 {
+		filter["version"] = version
+	}
+	var prior bson.Raw
+	err := col.FindOneAndDelete(ctx, filter).Decode(&prior)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if version > 0 {
+			return repo.versionError(ctx, sc, id)
+		}
+		return nil
+	}
 	if err != nil {
-		repo.l.Errorf(ctx, "department.mongo.Delete.DeleteOne: %v", err)
+		repo.l.Errorf(ctx, "department.mongo.Delete.FindOneAndDelete: %v", err)
 		return err
 	}
 
+	if err := history.RecordRevisions(ctx, repo.db, history.EntityDepartment, models.RevisionDelete, sc.UserID, time.Now(), prior); err != nil {
+		repo.l.Errorf(ctx, "department.mongo.Delete.history.RecordRevisions: %v", err)
+		return err
+	}
 	return nil
 }
 
@@ -108,3 +196,95 @@ func (repo implRepository) HasUsers(ctx context.Context, departmentID primitive.
 
 	return count > 0, nil
 }
+
+// Move đổi branch_id của department và cập nhật branch_id, region_id, shop_id lưu sẵn trên user thuộc department
+func (repo implRepository) Move(ctx context.Context, sc models.Scope, opts department.MoveOptions) (models.Department, error) {
+	col := repo.getDepartmentCollection()
+	now := time.Now()
+
+	// Bước 1: Đổi branch của department
+	// Chỉ đổi khi version khớp với version client đã đọc, giống Update
+	filter := bson.M{"_id": opts.ID}
+	if opts.Version > 0 {
+		filter["version"] = opts.Version
+	}
+	updateDoc := bson.M{
+		"$set": bson.M{
+			"branch_id":  opts.BranchID,
+			"updated_at": now,
+			"updated_by": sc.UserID,
+		},
+		"$inc": bson.M{"version": 1},
+	}
+	var prior bson.Raw
+	err := col.FindOneAndUpdate(ctx, filter, updateDoc).Decode(&prior)
+	if errors.Is(err, mongo.ErrNoDocuments) && opts.Version > 0 {
+		return models.Department{}, repo.versionError(ctx, sc, opts.ID)
+	}
+	if err != nil {
+		repo.l.Errorf(ctx, "department.mongo.Move.FindOneAndUpdate: %v", err)
+		return models.Department{}, err
+	}
+	if err := history.RecordRevisions(ctx, repo.db, history.EntityDepartment, models.RevisionUpdate, sc.UserID, now, prior); err != nil {
+		repo.l.Errorf(ctx, "department.mongo.Move.history.RecordRevisions: %v", err)
+		return models.Department{}, err
+	}
+
+	// Bước 2: Lưu trạng thái hiện tại của user thuộc department vào lịch sử
+	userCollection := repo.db.Collection("users")
+	userFilter := bson.M{"department_id": opts.ID}
+	cursor, err := userCollection.Find(ctx, userFilter, options.Find().SetProjection(history.UserProjection))
+	if err != nil {
+		repo.l.Errorf(ctx, "department.mongo.Move.Find: %v", err)
+		return models.Department{}, err
+	}
+	var users []bson.Raw
+	if err := cursor.All(ctx, &users); err != nil {
+		repo.l.Errorf(ctx, "department.mongo.Move.All: %v", err)
+		return models.Department{}, err
+	}
+	if err := history.RecordRevisions(ctx, repo.db, history.EntityUser, models.RevisionUpdate, sc.UserID, now, users...); err != nil {
+		repo.l.Errorf(ctx, "department.mongo.Move.history.RecordRevisions: %v", err)
+		return models.Department{}, err
+	}
+
+	// Bước 3: Cập nhật hierarchy của user thuộc department
+	userUpdate := bson.M{
+		"$set": bson.M{
+			"branch_id":  opts.BranchID,
+			"region_id":  opts.RegionID,
+			"shop_id":    opts.ShopID,
+			"updated_at": now,
+			"updated_by": sc.UserID,
+		},
+		"$inc": bson.M{"version": 1},
+	}
+	_, err = userCollection.UpdateMany(ctx, userFilter, userUpdate)
+	if err != nil {
+		repo.l.Errorf(ctx, "department.mongo.Move.UpdateMany: %v", err)
+		return models.Department{}, err
+	}
+
+	// Lấy department đã update
+	return repo.GetByID(ctx, sc, opts.ID)
+}
+
+// versionError xác định lý do update/delete theo version không khớp document nào:
+// document không còn thì trả lỗi của GetByID, còn tồn tại thì version đã bị thay đổi
+func (repo implRepository) versionError(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+	if _, err := repo.GetByID(ctx, sc, id); err != nil {
+		return err
+	}
+	return department.ErrVersionMismatch
+}
+
+// buildListQuery tạo filter lấy danh sách department từ bộ lọc
+func (repo implRepository) buildListQuery(opts department.ListOptions) bson.M {
+	filter := bson.M{}
+	if opts.BranchID != nil {
+		filter["branch_id"] = *opts.BranchID
+	}
+	mongo.BuildQueryWithTimeRange(filter, "created_at", opts.Filter.CreatedFrom, opts.Filter.CreatedTo)
+	mongo.BuildQueryWithTimeRange(filter, "updated_at", opts.Filter.UpdatedFrom, opts.Filter.UpdatedTo)
+	return filter
+}
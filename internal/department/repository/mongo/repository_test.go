@@ -9,6 +9,7 @@ import (
 	"thuchanhgolang/internal/models"
 	"thuchanhgolang/pkg/mongo"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	driverMongo "go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -132,8 +133,8 @@ func TestUpdate(t *testing.T) {
 		updated := models.Department{ID: id, Name: "Updated"}
 
 		mockColl := &mockCollection{
-			updateOneFunc: func(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*driverMongo.UpdateResult, error) {
-				return &driverMongo.UpdateResult{ModifiedCount: 1}, nil
+			findOneAndUpdateFunc: func(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) mongo.SingleResult {
+				return newMockSingleResult(models.Department{ID: id, Version: 1}, nil)
 			},
 			findOneFunc: func(ctx context.Context, filter interface{}) mongo.SingleResult {
 				return newMockSingleResult(updated, nil)
@@ -147,7 +148,7 @@ func TestUpdate(t *testing.T) {
 		}
 
 		repo := &implRepository{db: mockDB, l: &mockLogger{}}
-		result, err := repo.Update(ctx, models.Scope{}, department.UpdateOptions{ID: id, Name: "Updated"})
+		result, err := repo.Update(ctx, models.Scope{}, department.UpdateOptions{ID: id, Name: &updated.Name})
 
 		if err != nil {
 			t.Fatalf("Không mong đợi lỗi: %v", err)
@@ -162,8 +163,8 @@ func TestUpdate(t *testing.T) {
 		id := primitive.NewObjectID()
 
 		mockColl := &mockCollection{
-			updateOneFunc: func(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*driverMongo.UpdateResult, error) {
-				return nil, errors.New("update failed")
+			findOneAndUpdateFunc: func(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) mongo.SingleResult {
+				return newMockSingleResult(nil, errors.New("update failed"))
 			},
 		}
 
@@ -174,7 +175,8 @@ func TestUpdate(t *testing.T) {
 		}
 
 		repo := &implRepository{db: mockDB, l: &mockLogger{}}
-		_, err := repo.Update(ctx, models.Scope{}, department.UpdateOptions{ID: id})
+		name := "Updated"
+		_, err := repo.Update(ctx, models.Scope{}, department.UpdateOptions{ID: id, Name: &name})
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi")
@@ -187,8 +189,8 @@ func TestDelete(t *testing.T) {
 		ctx := context.Background()
 
 		mockColl := &mockCollection{
-			deleteOneFunc: func(ctx context.Context, filter interface{}) (int64, error) {
-				return 1, nil
+			findOneAndDeleteFunc: func(ctx context.Context, filter interface{}, opts ...*options.FindOneAndDeleteOptions) mongo.SingleResult {
+				return newMockSingleResult(models.Department{ID: primitive.NewObjectID(), Version: 1}, nil)
 			},
 		}
 
@@ -199,7 +201,7 @@ func TestDelete(t *testing.T) {
 		}
 
 		repo := &implRepository{db: mockDB, l: &mockLogger{}}
-		err := repo.Delete(ctx, models.Scope{}, primitive.NewObjectID())
+		err := repo.Delete(ctx, models.Scope{}, primitive.NewObjectID(), 0)
 
 		if err != nil {
 			t.Fatalf("Không mong đợi lỗi: %v", err)
@@ -210,8 +212,8 @@ func TestDelete(t *testing.T) {
 		ctx := context.Background()
 
 		mockColl := &mockCollection{
-			deleteOneFunc: func(ctx context.Context, filter interface{}) (int64, error) {
-				return 0, errors.New("delete failed")
+			findOneAndDeleteFunc: func(ctx context.Context, filter interface{}, opts ...*options.FindOneAndDeleteOptions) mongo.SingleResult {
+				return newMockSingleResult(nil, errors.New("delete failed"))
 			},
 		}
 
@@ -222,7 +224,7 @@ func TestDelete(t *testing.T) {
 		}
 
 		repo := &implRepository{db: mockDB, l: &mockLogger{}}
-		err := repo.Delete(ctx, models.Scope{}, primitive.NewObjectID())
+		err := repo.Delete(ctx, models.Scope{}, primitive.NewObjectID(), 0)
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi")
@@ -230,6 +232,49 @@ func TestDelete(t *testing.T) {
 	})
 }
 
+func TestMoveVersion(t *testing.T) {
+	id := primitive.NewObjectID()
+
+	tests := []struct {
+		name    string
+		current mongo.SingleResult // Kết quả GetByID khi version không khớp
+		wantErr error
+	}{
+		{name: "version đã bị thay đổi", current: newMockSingleResult(models.Department{ID: id, Version: 3}, nil), wantErr: department.ErrVersionMismatch},
+		{name: "department đã bị xóa", current: newMockSingleResult(nil, driverMongo.ErrNoDocuments), wantErr: driverMongo.ErrNoDocuments},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotFilter bson.M
+			mockColl := &mockCollection{
+				findOneAndUpdateFunc: func(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) mongo.SingleResult {
+					gotFilter = filter.(bson.M)
+					return newMockSingleResult(nil, driverMongo.ErrNoDocuments)
+				},
+				findOneFunc: func(ctx context.Context, filter interface{}) mongo.SingleResult {
+					return tt.current
+				},
+			}
+			mockDB := &mockDatabase{
+				collectionFunc: func(name string) mongo.Collection {
+					return mockColl
+				},
+			}
+
+			repo := &implRepository{db: mockDB, l: &mockLogger{}}
+			_, err := repo.Move(context.Background(), models.Scope{}, department.MoveOptions{ID: id, BranchID: primitive.NewObjectID(), Version: 2})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Mong đợi lỗi %v, nhận được %v", tt.wantErr, err)
+			}
+			if gotFilter["version"] != 2 {
+				t.Errorf("Mong đợi filter theo version 2, nhận được %v", gotFilter)
+			}
+		})
+	}
+}
+
 func TestHasUsers(t *testing.T) {
 	t.Run("has users", func(t *testing.T) {
 		ctx := context.Background()
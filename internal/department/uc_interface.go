@@ -2,6 +2,7 @@ package department
 
 import (
 	"context"
+	"time"
 
 	"thuchanhgolang/internal/models"
 
@@ -16,9 +17,21 @@ type Usecase interface {
 	// GetByID lấy thông tin region theo ID
 	GetByID(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Department, error)
 
+	// List lấy danh sách department có phân trang, lọc theo thời gian tạo và cập nhật
+	List(ctx context.Context, sc models.Scope, input ListInput) (ListOutput, error)
+
 	// Update cập nhật thông tin region
 	Update(ctx context.Context, sc models.Scope, input UpdateInput) (models.Department, error)
 
-	// Delete xóa branch
-	Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error
+	// Delete xóa branch, version là version client đã đọc (0 → không kiểm tra)
+	Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error
+
+	// Move chuyển department sang branch khác trong cùng shop
+	Move(ctx context.Context, sc models.Scope, input MoveInput) (models.Department, error)
+
+	// GetAsOf lấy trạng thái của department tại thời điểm asOf
+	GetAsOf(ctx context.Context, sc models.Scope, id primitive.ObjectID, asOf time.Time) (models.Department, error)
+
+	// History lấy lịch sử thay đổi của department, mới nhất đứng trước
+	History(ctx context.Context, sc models.Scope, input HistoryInput) (HistoryOutput, error)
 }
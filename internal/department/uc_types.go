@@ -1,6 +1,13 @@
 package department
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // CreateInput là dữ liệu đầu vào để tạo region mới
 type CreateInput struct {
@@ -10,6 +17,47 @@ type CreateInput struct {
 
 // UpdateInput là dữ liệu đầu vào để cập nhật branch
 type UpdateInput struct {
-	ID   primitive.ObjectID // ID branch cần cập nhật
-	Name string             // Tên branch mới
+	ID      primitive.ObjectID // ID branch cần cập nhật
+	Name    *string            // Tên mới, nil → giữ nguyên
+	Version int                // Version client đã đọc (If-Match), 0 → không kiểm tra
+}
+
+// MoveInput là dữ liệu đầu vào để chuyển department sang branch khác
+type MoveInput struct {
+	ID       primitive.ObjectID // ID department cần chuyển
+	BranchID primitive.ObjectID // Branch đích
+	Version  int                // Version client đã đọc (If-Match), 0 → không kiểm tra
+}
+
+// ListInput là dữ liệu đầu vào để lấy danh sách department
+type ListInput struct {
+	BranchID *primitive.ObjectID      // Chỉ lấy department thuộc branch này (nil = mọi branch)
+	Filter   models.TimeFilter        // Lọc theo thời gian tạo, cập nhật
+	Pagin    paginator.PaginatorQuery // Trang cần lấy
+}
+
+// ListOutput là danh sách department của một trang
+type ListOutput struct {
+	Departments []models.Department
+	Pagin       paginator.Paginator
+}
+
+// HistoryInput là dữ liệu đầu vào để lấy lịch sử thay đổi của department
+type HistoryInput struct {
+	ID    primitive.ObjectID       // ID department
+	Pagin paginator.PaginatorQuery // Trang cần lấy
+}
+
+// Revision là trạng thái của department trước một lần sửa hoặc xóa
+type Revision struct {
+	Department models.Department     // Snapshot, có hiệu lực từ Department.UpdatedAt đến ChangedAt
+	Action     models.RevisionAction // Thao tác đã thay thế snapshot
+	ChangedAt  time.Time             // Thời điểm sửa / xóa
+	ChangedBy  string                // UserID của người sửa / xóa
+}
+
+// HistoryOutput là lịch sử thay đổi của department trong một trang, mới nhất đứng trước
+type HistoryOutput struct {
+	Revisions []Revision
+	Pagin     paginator.Paginator
 }
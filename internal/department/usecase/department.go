@@ -2,9 +2,11 @@ package usecase
 
 import (
 	"context"
+	"errors"
 
 	"thuchanhgolang/internal/department"
 	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/mongo"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -17,10 +19,27 @@ func (uc *implUsecase) Create(ctx context.Context, sc models.Scope, input depart
 		Name:     input.Name,
 	}
 
-	// Bước 2: Gọi repository để lưu vào database
-	newDepartment, err := uc.repo.Create(ctx, sc, opts)
+	// Bước 2: Lưu vào database và ghi event vào outbox trong cùng một transaction
+	var newDepartment models.Department
+	err := uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		newDepartment, err = uc.repo.Create(ctx, sc, opts)
+		if err != nil {
+			uc.l.Errorf(ctx, "department.usecase.Create.repo.Create: %v", err)
+			return err
+		}
+		h, err := uc.hierarchy(ctx, sc, newDepartment)
+		if err != nil {
+			uc.l.Errorf(ctx, "department.usecase.Create.hierarchy: %v", err)
+			return err
+		}
+		if err := uc.publisher.Publish(ctx, newEvent(department.EventDepartmentCreated, sc, h, newPayload(newDepartment))); err != nil {
+			uc.l.Errorf(ctx, "department.usecase.Create.publisher.Publish: %v", err)
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		uc.l.Errorf(ctx, "department.usecase.Create.repo.Create: %v", err)
 		return models.Department{}, err
 	}
 
@@ -34,51 +53,118 @@ func (uc *implUsecase) GetByID(ctx context.Context, sc models.Scope, id primitiv
 	department, err := uc.repo.GetByID(ctx, sc, id)
 	if err != nil {
 		uc.l.Errorf(ctx, "department.usecase.GetByID.repo.GetByID: %v", err)
-		return models.Department{}, err
+		return models.Department{}, notFoundError(err)
 	}
 
 	return department, nil
 }
 
+// List lấy danh sách department có phân trang, lọc theo thời gian tạo và cập nhật
+func (uc *implUsecase) List(ctx context.Context, sc models.Scope, input department.ListInput) (department.ListOutput, error) {
+	// Bước 1: Đưa page, limit về giá trị mặc định nếu không hợp lệ
+	input.Pagin.Adjust()
+
+	// Bước 2: Gọi repository để lấy danh sách
+	opts := department.ListOptions{
+		BranchID: input.BranchID,
+		Filter:   input.Filter,
+		Pagin:    input.Pagin,
+	}
+	items, pag, err := uc.repo.List(ctx, sc, opts)
+	if err != nil {
+		uc.l.Errorf(ctx, "department.usecase.List.repo.List: %v", err)
+		return department.ListOutput{}, err
+	}
+
+	return department.ListOutput{Departments: items, Pagin: pag}, nil
+}
+
 // Update cập nhật thông tin branch (chỉ cho phép đổi tên, không đổi region)
 func (uc *implUsecase) Update(ctx context.Context, sc models.Scope, input department.UpdateInput) (models.Department, error) {
 	// Bước 1: Chuyển đổi input thành options
 	opts := department.UpdateOptions{
-		ID:   input.ID,
-		Name: input.Name,
+		ID:      input.ID,
+		Name:    input.Name,
+		Version: input.Version,
 	}
 
-	// Bước 2: Gọi repository để update
-	updatedDepartment, err := uc.repo.Update(ctx, sc, opts)
+	// Bước 2: Gọi repository để update, sửa và ghi lịch sử chạy trong cùng một transaction
+	var updatedDepartment models.Department
+	err := uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		updatedDepartment, err = uc.repo.Update(ctx, sc, opts)
+		if err != nil {
+			uc.l.Errorf(ctx, "department.usecase.Update.repo.Update: %v", err)
+			return err
+		}
+		h, err := uc.hierarchy(ctx, sc, updatedDepartment)
+		if err != nil {
+			uc.l.Errorf(ctx, "department.usecase.Update.hierarchy: %v", err)
+			return err
+		}
+		if err := uc.publisher.Publish(ctx, newEvent(department.EventDepartmentUpdated, sc, h, newPayload(updatedDepartment))); err != nil {
+			uc.l.Errorf(ctx, "department.usecase.Update.publisher.Publish: %v", err)
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		uc.l.Errorf(ctx, "department.usecase.Update.repo.Update: %v", err)
-		return models.Department{}, err
+		return models.Department{}, notFoundError(err)
 	}
 
 	return updatedDepartment, nil
 }
 
 // Delete xóa region (kiểm tra trước xem có đang được dùng không)
-func (uc *implUsecase) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
-	// Bước 1: Kiểm tra xem region có branch nào không
-	hasUsers, err := uc.repo.HasUsers(ctx, id)
-	if err != nil {
-		uc.l.Errorf(ctx, "department.usecase.Delete.repo.HasUsers: %v", err)
-		return err
-	}
+func (uc *implUsecase) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
+	// Kiểm tra và xóa chạy trong cùng một transaction, tránh trường hợp kiểm tra xong mới bị thay đổi
+	return uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		// Bước 1: Kiểm tra xem region có branch nào không
+		hasUsers, err := uc.repo.HasUsers(ctx, id)
+		if err != nil {
+			uc.l.Errorf(ctx, "department.usecase.Delete.repo.HasUsers: %v", err)
+			return err
+		}
 
-	// Bước 2: Nếu có branch, không cho phép xóa
-	if hasUsers {
-		uc.l.Warnf(ctx, "department.usecase.Delete: department is being used by users")
-		return department.ErrDepartmentInUse
-	}
+		// Bước 2: Nếu có branch, không cho phép xóa
+		if hasUsers {
+			uc.l.Warnf(ctx, "department.usecase.Delete: department is being used by users")
+			return department.ErrDepartmentInUse
+		}
 
-	// Bước 3: Gọi repository để xóa region
-	err = uc.repo.Delete(ctx, sc, id)
-	if err != nil {
-		uc.l.Errorf(ctx, "department.usecase.Delete.repo.Delete: %v", err)
-		return err
-	}
+		// Bước 3: Lấy vị trí của department để event xóa gửi đúng người nhận
+		d, err := uc.repo.GetByID(ctx, sc, id)
+		if err != nil {
+			uc.l.Errorf(ctx, "department.usecase.Delete.repo.GetByID: %v", err)
+			return notFoundError(err)
+		}
+		h, err := uc.hierarchy(ctx, sc, d)
+		if err != nil {
+			uc.l.Errorf(ctx, "department.usecase.Delete.hierarchy: %v", err)
+			return err
+		}
 
-	return nil
+		// Bước 4: Gọi repository để xóa region
+		err = uc.repo.Delete(ctx, sc, id, version)
+		if err != nil {
+			uc.l.Errorf(ctx, "department.usecase.Delete.repo.Delete: %v", err)
+			return notFoundError(err)
+		}
+
+		// Bước 5: Ghi event vào outbox cùng transaction với thao tác xóa
+		if err := uc.publisher.Publish(ctx, newEvent(department.EventDepartmentDeleted, sc, h, nil)); err != nil {
+			uc.l.Errorf(ctx, "department.usecase.Delete.publisher.Publish: %v", err)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// notFoundError chuyển lỗi không tìm thấy document thành lỗi domain
+func notFoundError(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return department.ErrDepartmentNotFound
+	}
+	return err
 }
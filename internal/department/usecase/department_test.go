@@ -7,6 +7,7 @@ import (
 
 	"thuchanhgolang/internal/department"
 	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/event"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -26,7 +27,7 @@ func TestCreate(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
+		uc := &implUsecase{repo: mockRepo, branchRepo: &mockBranchRepository{}, regionRepo: &mockRegionRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		result, err := uc.Create(ctx, models.Scope{}, input)
 
 		if err != nil {
@@ -44,7 +45,7 @@ func TestCreate(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
+		uc := &implUsecase{repo: mockRepo, branchRepo: &mockBranchRepository{}, regionRepo: &mockRegionRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		_, err := uc.Create(context.Background(), models.Scope{}, department.CreateInput{})
 
 		if err == nil {
@@ -64,7 +65,7 @@ func TestGetByID(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
+		uc := &implUsecase{repo: mockRepo, branchRepo: &mockBranchRepository{}, regionRepo: &mockRegionRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		result, err := uc.GetByID(context.Background(), models.Scope{}, id)
 
 		if err != nil {
@@ -82,7 +83,7 @@ func TestGetByID(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
+		uc := &implUsecase{repo: mockRepo, branchRepo: &mockBranchRepository{}, regionRepo: &mockRegionRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		_, err := uc.GetByID(context.Background(), models.Scope{}, primitive.NewObjectID())
 
 		if err == nil {
@@ -94,8 +95,9 @@ func TestGetByID(t *testing.T) {
 func TestUpdate(t *testing.T) {
 	t.Run("update successfully", func(t *testing.T) {
 		id := primitive.NewObjectID()
-		input := department.UpdateInput{ID: id, Name: "Updated"}
-		expected := models.Department{ID: id, Name: input.Name}
+		name := "Updated"
+		input := department.UpdateInput{ID: id, Name: &name}
+		expected := models.Department{ID: id, Name: name}
 
 		mockRepo := &mockRepository{
 			updateFunc: func(ctx context.Context, sc models.Scope, opts department.UpdateOptions) (models.Department, error) {
@@ -103,7 +105,7 @@ func TestUpdate(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
+		uc := &implUsecase{repo: mockRepo, branchRepo: &mockBranchRepository{}, regionRepo: &mockRegionRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		result, err := uc.Update(context.Background(), models.Scope{}, input)
 
 		if err != nil {
@@ -121,7 +123,7 @@ func TestUpdate(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
+		uc := &implUsecase{repo: mockRepo, branchRepo: &mockBranchRepository{}, regionRepo: &mockRegionRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		_, err := uc.Update(context.Background(), models.Scope{}, department.UpdateInput{})
 
 		if err == nil {
@@ -141,17 +143,21 @@ func TestDelete(t *testing.T) {
 			hasUsersFunc: func(ctx context.Context, departmentID primitive.ObjectID) (bool, error) {
 				return false, nil
 			},
-			deleteFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+			deleteFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
 				return nil
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
-		err := uc.Delete(context.Background(), models.Scope{}, id)
+		tx := &mockTransactor{}
+		uc := &implUsecase{repo: mockRepo, branchRepo: &mockBranchRepository{}, regionRepo: &mockRegionRepository{}, l: &mockLogger{}, tx: tx, publisher: event.NewMemoryPublisher()}
+		err := uc.Delete(context.Background(), models.Scope{}, id, 0)
 
 		if err != nil {
 			t.Fatalf("Không mong đợi lỗi: %v", err)
 		}
+		if tx.calls != 1 {
+			t.Errorf("Delete phải chạy trong transaction, số lần gọi: %d", tx.calls)
+		}
 	})
 
 	t.Run("delete department in use", func(t *testing.T) {
@@ -164,8 +170,8 @@ func TestDelete(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
-		err := uc.Delete(context.Background(), models.Scope{}, primitive.NewObjectID())
+		uc := &implUsecase{repo: mockRepo, branchRepo: &mockBranchRepository{}, regionRepo: &mockRegionRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		err := uc.Delete(context.Background(), models.Scope{}, primitive.NewObjectID(), 0)
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi")
@@ -182,8 +188,8 @@ func TestDelete(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
-		err := uc.Delete(context.Background(), models.Scope{}, primitive.NewObjectID())
+		uc := &implUsecase{repo: mockRepo, branchRepo: &mockBranchRepository{}, regionRepo: &mockRegionRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		err := uc.Delete(context.Background(), models.Scope{}, primitive.NewObjectID(), 0)
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi")
@@ -195,13 +201,13 @@ func TestDelete(t *testing.T) {
 			hasUsersFunc: func(ctx context.Context, deptID primitive.ObjectID) (bool, error) {
 				return false, nil
 			},
-			deleteFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+			deleteFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
 				return errors.New("delete failed")
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
-		err := uc.Delete(context.Background(), models.Scope{}, primitive.NewObjectID())
+		uc := &implUsecase{repo: mockRepo, branchRepo: &mockBranchRepository{}, regionRepo: &mockRegionRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		err := uc.Delete(context.Background(), models.Scope{}, primitive.NewObjectID(), 0)
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi")
@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/department"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/event"
+)
+
+// newEvent tạo event của department nằm ở vị trí h, actor là user đang thao tác
+func newEvent(typ string, sc models.Scope, h models.Hierarchy, payload map[string]interface{}) event.Event {
+	e := event.New(typ, department.AggregateType, h.DepartmentID.Hex(), sc.UserID, payload)
+	e.Location = h.Location()
+	return e
+}
+
+// hierarchy lấy vị trí đầy đủ của department từ branch chứa nó
+func (uc *implUsecase) hierarchy(ctx context.Context, sc models.Scope, d models.Department) (models.Hierarchy, error) {
+	h, err := uc.branchHierarchy(ctx, sc, d.BranchID)
+	if err != nil {
+		return models.Hierarchy{}, err
+	}
+	h.DepartmentID = &d.ID
+	return h, nil
+}
+
+// newPayload là nội dung event khi department được tạo hoặc cập nhật
+func newPayload(d models.Department) map[string]interface{} {
+	return map[string]interface{}{
+		"branch_id": d.BranchID.Hex(),
+		"name":      d.Name,
+		"version":   d.Version,
+	}
+}
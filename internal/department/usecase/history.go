@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"thuchanhgolang/internal/department"
+	"thuchanhgolang/internal/history"
+	"thuchanhgolang/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// historyReader đọc lịch sử của department, trạng thái hiện tại được lấy trong scope của người gọi
+func (uc *implUsecase) historyReader(sc models.Scope) history.Reader[models.Department] {
+	return history.Reader[models.Department]{
+		Repo:   uc.history,
+		Entity: history.EntityDepartment,
+		Current: func(ctx context.Context, id primitive.ObjectID) (models.Department, error) {
+			return uc.repo.GetByID(ctx, sc, id)
+		},
+		CreatedAt: func(v models.Department) time.Time { return v.CreatedAt },
+	}
+}
+
+// GetAsOf lấy trạng thái của department tại thời điểm asOf
+func (uc *implUsecase) GetAsOf(ctx context.Context, sc models.Scope, id primitive.ObjectID, asOf time.Time) (models.Department, error) {
+	result, err := uc.historyReader(sc).GetAsOf(ctx, id, asOf)
+	if err != nil {
+		if errors.Is(err, history.ErrNotCreated) {
+			return models.Department{}, department.ErrDepartmentNotFound
+		}
+		uc.l.Errorf(ctx, "department.usecase.GetAsOf.historyReader.GetAsOf: %v", err)
+		return models.Department{}, notFoundError(err)
+	}
+
+	return result, nil
+}
+
+// History lấy lịch sử thay đổi của department, mới nhất đứng trước
+func (uc *implUsecase) History(ctx context.Context, sc models.Scope, input department.HistoryInput) (department.HistoryOutput, error) {
+	entries, pag, err := uc.historyReader(sc).List(ctx, input.ID, input.Pagin)
+	if err != nil {
+		uc.l.Errorf(ctx, "department.usecase.History.historyReader.List: %v", err)
+		return department.HistoryOutput{}, notFoundError(err)
+	}
+
+	out := department.HistoryOutput{
+		Revisions: make([]department.Revision, 0, len(entries)),
+		Pagin:     pag,
+	}
+	for _, e := range entries {
+		out.Revisions = append(out.Revisions, department.Revision{
+			Department: e.Snapshot,
+			Action:     e.Action,
+			ChangedAt:  e.ChangedAt,
+			ChangedBy:  e.ChangedBy,
+		})
+	}
+
+	return out, nil
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"thuchanhgolang/internal/department"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/mongo"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Move chuyển department sang branch khác trong cùng shop
+// Flow: Lấy department, hierarchy cũ, hierarchy đích -> Kiểm tra shop và scope -> Cập nhật department + user -> Ghi event vào outbox
+func (uc *implUsecase) Move(ctx context.Context, sc models.Scope, input department.MoveInput) (models.Department, error) {
+	var moved models.Department
+	var from models.Hierarchy
+
+	err := uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		// Bước 1: Lấy department và hierarchy hiện tại
+		d, err := uc.repo.GetByID(ctx, sc, input.ID)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return department.ErrDepartmentNotFound
+			}
+			uc.l.Errorf(ctx, "department.usecase.Move.repo.GetByID: %v", err)
+			return err
+		}
+		from, err = uc.branchHierarchy(ctx, sc, d.BranchID)
+		if err != nil {
+			uc.l.Errorf(ctx, "department.usecase.Move.branchHierarchy(from): %v", err)
+			return err
+		}
+		from.DepartmentID = &d.ID
+
+		// Bước 2: Lấy hierarchy của branch đích
+		to, err := uc.branchHierarchy(ctx, sc, input.BranchID)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return department.ErrTargetBranchNotFound
+			}
+			uc.l.Errorf(ctx, "department.usecase.Move.branchHierarchy(to): %v", err)
+			return err
+		}
+
+		// Bước 3: Branch đích phải cùng shop
+		if to.ShopID != from.ShopID {
+			uc.l.Warnf(ctx, "department.usecase.Move: branch %s belongs to another shop", to.BranchID.Hex())
+			return department.ErrDifferentShop
+		}
+
+		// Bước 4: Cả department lẫn branch đích phải nằm trong scope của user
+		if !sc.Contains(from) || !sc.Contains(to) {
+			uc.l.Warnf(ctx, "department.usecase.Move: user %s cannot move department %s", sc.UserID, d.ID.Hex())
+			return department.ErrMoveOutOfScope
+		}
+
+		// Bước 5: Đã ở branch đích thì không cần đổi gì, chỉ kiểm tra version
+		if to.BranchID == from.BranchID {
+			if input.Version > 0 && d.Version != input.Version {
+				return department.ErrVersionMismatch
+			}
+			moved = d
+			return nil
+		}
+
+		// Bước 6: Đổi branch của department và cập nhật user
+		moved, err = uc.repo.Move(ctx, sc, department.MoveOptions{
+			ID:       d.ID,
+			BranchID: to.BranchID,
+			RegionID: to.RegionID,
+			ShopID:   to.ShopID,
+			Version:  input.Version,
+		})
+		if err != nil {
+			uc.l.Errorf(ctx, "department.usecase.Move.repo.Move: %v", err)
+			return err
+		}
+
+		// Bước 7: Ghi event vào outbox cùng transaction, event chỉ được gửi khi thay đổi đã commit
+		target := to
+		target.DepartmentID = &moved.ID
+		e := newEvent(department.EventDepartmentMoved, sc, target, map[string]interface{}{
+			"from_branch_id": from.BranchID.Hex(),
+			"to_branch_id":   moved.BranchID.Hex(),
+			"shop_id":        from.ShopID.Hex(),
+		})
+		if err := uc.publisher.Publish(ctx, e); err != nil {
+			uc.l.Errorf(ctx, "department.usecase.Move.publisher.Publish: %v", err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return models.Department{}, err
+	}
+
+	return moved, nil
+}
+
+// branchHierarchy lấy shop, region của một branch
+func (uc *implUsecase) branchHierarchy(ctx context.Context, sc models.Scope, branchID primitive.ObjectID) (models.Hierarchy, error) {
+	b, err := uc.branchRepo.GetByID(ctx, sc, branchID)
+	if err != nil {
+		return models.Hierarchy{}, err
+	}
+
+	r, err := uc.regionRepo.GetByID(ctx, sc, b.RegionID)
+	if err != nil {
+		return models.Hierarchy{}, err
+	}
+
+	return models.Hierarchy{
+		ShopID:   r.ShopID,
+		RegionID: r.ID,
+		BranchID: b.ID,
+	}, nil
+}
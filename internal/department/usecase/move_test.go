@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"thuchanhgolang/internal/department"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/event"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMove(t *testing.T) {
+	shopID := primitive.NewObjectID()
+	region := models.Region{ID: primitive.NewObjectID(), ShopID: shopID}
+	fromBranch := models.Branch{ID: primitive.NewObjectID(), RegionID: region.ID}
+	toBranch := models.Branch{ID: primitive.NewObjectID(), RegionID: region.ID}
+	d := models.Department{ID: primitive.NewObjectID(), BranchID: fromBranch.ID, Name: "Kế toán"}
+	manager := models.Scope{UserID: "manager", Role: models.RoleManager, ShopID: &shopID}
+
+	newUsecase := func(repo *mockRepository, regions []models.Region, branches ...models.Branch) (*implUsecase, *event.MemoryPublisher) {
+		publisher := event.NewMemoryPublisher()
+		return &implUsecase{
+			repo: repo,
+			branchRepo: &mockBranchRepository{
+				getByIDFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Branch, error) {
+					for _, b := range branches {
+						if b.ID == id {
+							return b, nil
+						}
+					}
+					return models.Branch{}, errors.New("not found")
+				},
+			},
+			regionRepo: &mockRegionRepository{
+				getByIDFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Region, error) {
+					for _, r := range regions {
+						if r.ID == id {
+							return r, nil
+						}
+					}
+					return models.Region{}, errors.New("not found")
+				},
+			},
+			l:         &mockLogger{},
+			tx:        &mockTransactor{},
+			publisher: publisher,
+		}, publisher
+	}
+
+	t.Run("move successfully", func(t *testing.T) {
+		var got department.MoveOptions
+		mockRepo := &mockRepository{
+			getByIDFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Department, error) {
+				return d, nil
+			},
+			moveFunc: func(ctx context.Context, sc models.Scope, opts department.MoveOptions) (models.Department, error) {
+				got = opts
+				return models.Department{ID: d.ID, BranchID: opts.BranchID, Name: d.Name}, nil
+			},
+		}
+
+		uc, publisher := newUsecase(mockRepo, []models.Region{region}, fromBranch, toBranch)
+		result, err := uc.Move(context.Background(), manager, department.MoveInput{ID: d.ID, BranchID: toBranch.ID})
+
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if result.BranchID != toBranch.ID {
+			t.Errorf("Mong đợi branch %s, nhận được %s", toBranch.ID.Hex(), result.BranchID.Hex())
+		}
+		if got.RegionID != region.ID || got.ShopID != shopID {
+			t.Errorf("Hierarchy ghi vào user không đúng: %+v", got)
+		}
+		if len(publisher.Events()) != 1 || publisher.Events()[0].Type != department.EventDepartmentMoved {
+			t.Errorf("Mong đợi 1 event %s, nhận được %+v", department.EventDepartmentMoved, publisher.Events())
+		}
+	})
+
+	t.Run("move to another shop", func(t *testing.T) {
+		otherRegion := models.Region{ID: primitive.NewObjectID(), ShopID: primitive.NewObjectID()}
+		otherBranch := models.Branch{ID: primitive.NewObjectID(), RegionID: otherRegion.ID}
+		mockRepo := &mockRepository{
+			getByIDFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Department, error) {
+				return d, nil
+			},
+		}
+
+		uc, _ := newUsecase(mockRepo, []models.Region{region, otherRegion}, fromBranch, otherBranch)
+		_, err := uc.Move(context.Background(), manager, department.MoveInput{ID: d.ID, BranchID: otherBranch.ID})
+
+		if !errors.Is(err, department.ErrDifferentShop) {
+			t.Errorf("Mong đợi ErrDifferentShop, nhận được: %v", err)
+		}
+	})
+
+	t.Run("head of department cannot move", func(t *testing.T) {
+		hod := models.Scope{UserID: "hod", Role: models.RoleHeadOfDepartment, ShopID: &shopID, DepartmentID: &d.ID}
+		mockRepo := &mockRepository{
+			getByIDFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Department, error) {
+				return d, nil
+			},
+		}
+
+		uc, _ := newUsecase(mockRepo, []models.Region{region}, fromBranch, toBranch)
+		_, err := uc.Move(context.Background(), hod, department.MoveInput{ID: d.ID, BranchID: toBranch.ID})
+
+		if !errors.Is(err, department.ErrMoveOutOfScope) {
+			t.Errorf("Mong đợi ErrMoveOutOfScope, nhận được: %v", err)
+		}
+	})
+
+	t.Run("stale version when already in target branch", func(t *testing.T) {
+		current := d
+		current.Version = 3
+		mockRepo := &mockRepository{
+			getByIDFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Department, error) {
+				return current, nil
+			},
+		}
+
+		uc, publisher := newUsecase(mockRepo, []models.Region{region}, fromBranch)
+		_, err := uc.Move(context.Background(), manager, department.MoveInput{ID: d.ID, BranchID: fromBranch.ID, Version: 2})
+
+		if !errors.Is(err, department.ErrVersionMismatch) {
+			t.Errorf("Mong đợi ErrVersionMismatch, nhận được: %v", err)
+		}
+		if len(publisher.Events()) != 0 {
+			t.Error("Không được phát event khi version không khớp")
+		}
+	})
+}
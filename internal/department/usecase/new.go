@@ -1,20 +1,35 @@
 package usecase
 
 import (
+	"thuchanhgolang/internal/branch"
 	"thuchanhgolang/internal/department"
+	"thuchanhgolang/internal/history"
+	"thuchanhgolang/internal/region"
+	"thuchanhgolang/pkg/event"
 	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/mongo"
 )
 
 // implUsecase là implementation của region.Usecase interface
 type implUsecase struct {
-	l    log.Logger            // Logger để ghi log
-	repo department.Repository // Repository để tương tác với database
+	l          log.Logger            // Logger để ghi log
+	repo       department.Repository // Repository để tương tác với database
+	branchRepo branch.Repository     // Dùng để kiểm tra branch khi chuyển department
+	regionRepo region.Repository     // Dùng để lấy shop của branch
+	history    history.Repository    // Đọc lịch sử thay đổi
+	tx         mongo.Transactor      // Chạy các thao tác nhiều bước trong transaction
+	publisher  event.Publisher       // Ghi event vào outbox khi department thay đổi
 }
 
 // NewUsecase tạo usecase mới cho region
-func NewUsecase(l log.Logger, repo department.Repository) department.Usecase {
+func NewUsecase(l log.Logger, repo department.Repository, branchRepo branch.Repository, regionRepo region.Repository, historyRepo history.Repository, tx mongo.Transactor, publisher event.Publisher) department.Usecase {
 	return &implUsecase{
-		l:    l,
-		repo: repo,
+		l:          l,
+		repo:       repo,
+		branchRepo: branchRepo,
+		regionRepo: regionRepo,
+		history:    historyRepo,
+		tx:         tx,
+		publisher:  publisher,
 	}
 }
@@ -3,8 +3,11 @@ package usecase
 import (
 	"context"
 
+	"thuchanhgolang/internal/branch"
 	"thuchanhgolang/internal/department"
 	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/region"
+	"thuchanhgolang/pkg/paginator"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -13,10 +16,12 @@ import (
 type mockRepository struct {
 	createFunc   func(context.Context, models.Scope, department.CreateOptions) (models.Department, error)
 	getByIDFunc  func(context.Context, models.Scope, primitive.ObjectID) (models.Department, error)
+	listFunc     func(context.Context, models.Scope, department.ListOptions) ([]models.Department, paginator.Paginator, error)
 	updateFunc   func(context.Context, models.Scope, department.UpdateOptions) (models.Department, error)
-	deleteFunc   func(context.Context, models.Scope, primitive.ObjectID) error
+	deleteFunc   func(context.Context, models.Scope, primitive.ObjectID, int) error
 	hasShopsFunc func(context.Context, primitive.ObjectID) (bool, error)
 	hasUsersFunc func(context.Context, primitive.ObjectID) (bool, error)
+	moveFunc     func(context.Context, models.Scope, department.MoveOptions) (models.Department, error)
 }
 
 func (m *mockRepository) Create(ctx context.Context, sc models.Scope, opts department.CreateOptions) (models.Department, error) {
@@ -33,6 +38,13 @@ func (m *mockRepository) GetByID(ctx context.Context, sc models.Scope, id primit
 	return models.Department{}, nil
 }
 
+func (m *mockRepository) List(ctx context.Context, sc models.Scope, opts department.ListOptions) ([]models.Department, paginator.Paginator, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, sc, opts)
+	}
+	return nil, paginator.Paginator{}, nil
+}
+
 func (m *mockRepository) Update(ctx context.Context, sc models.Scope, opts department.UpdateOptions) (models.Department, error) {
 	if m.updateFunc != nil {
 		return m.updateFunc(ctx, sc, opts)
@@ -40,9 +52,9 @@ func (m *mockRepository) Update(ctx context.Context, sc models.Scope, opts depar
 	return models.Department{}, nil
 }
 
-func (m *mockRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+func (m *mockRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
 	if m.deleteFunc != nil {
-		return m.deleteFunc(ctx, sc, id)
+		return m.deleteFunc(ctx, sc, id, version)
 	}
 	return nil
 }
@@ -74,3 +86,91 @@ func (m *mockLogger) Error(ctx context.Context, arg ...any)                   {}
 func (m *mockLogger) Errorf(ctx context.Context, template string, arg ...any) {}
 func (m *mockLogger) Fatal(ctx context.Context, arg ...any)                   {}
 func (m *mockLogger) Fatalf(ctx context.Context, template string, arg ...any) {}
+
+type mockTransactor struct {
+	calls int
+}
+
+func (m *mockTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	return fn(ctx)
+}
+
+func (m *mockRepository) Move(ctx context.Context, sc models.Scope, opts department.MoveOptions) (models.Department, error) {
+	if m.moveFunc != nil {
+		return m.moveFunc(ctx, sc, opts)
+	}
+	return models.Department{}, nil
+}
+
+// mockBranchRepository giả lập branch.Repository, chỉ cần GetByID
+type mockBranchRepository struct {
+	getByIDFunc func(context.Context, models.Scope, primitive.ObjectID) (models.Branch, error)
+}
+
+func (m *mockBranchRepository) Create(ctx context.Context, sc models.Scope, opts branch.CreateOptions) (models.Branch, error) {
+	return models.Branch{}, nil
+}
+
+func (m *mockBranchRepository) GetByID(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Branch, error) {
+	if m.getByIDFunc != nil {
+		return m.getByIDFunc(ctx, sc, id)
+	}
+	return models.Branch{}, nil
+}
+
+func (m *mockBranchRepository) List(ctx context.Context, sc models.Scope, opts branch.ListOptions) ([]models.Branch, paginator.Paginator, error) {
+	return nil, paginator.Paginator{}, nil
+}
+
+func (m *mockBranchRepository) Update(ctx context.Context, sc models.Scope, opts branch.UpdateOptions) (models.Branch, error) {
+	return models.Branch{}, nil
+}
+
+func (m *mockBranchRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
+	return nil
+}
+
+func (m *mockBranchRepository) HasDepartments(ctx context.Context, branchID primitive.ObjectID) (bool, error) {
+	return false, nil
+}
+
+func (m *mockBranchRepository) HasUsers(ctx context.Context, branchID primitive.ObjectID) (bool, error) {
+	return false, nil
+}
+
+func (m *mockBranchRepository) Move(ctx context.Context, sc models.Scope, opts branch.MoveOptions) (models.Branch, error) {
+	return models.Branch{}, nil
+}
+
+// mockRegionRepository giả lập region.Repository, chỉ cần GetByID
+type mockRegionRepository struct {
+	getByIDFunc func(context.Context, models.Scope, primitive.ObjectID) (models.Region, error)
+}
+
+func (m *mockRegionRepository) Create(ctx context.Context, sc models.Scope, opts region.CreateOptions) (models.Region, error) {
+	return models.Region{}, nil
+}
+
+func (m *mockRegionRepository) GetByID(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Region, error) {
+	if m.getByIDFunc != nil {
+		return m.getByIDFunc(ctx, sc, id)
+	}
+	return models.Region{}, nil
+}
+
+func (m *mockRegionRepository) List(ctx context.Context, sc models.Scope, opts region.ListOptions) ([]models.Region, paginator.Paginator, error) {
+	return nil, paginator.Paginator{}, nil
+}
+
+func (m *mockRegionRepository) Update(ctx context.Context, sc models.Scope, opts region.UpdateOptions) (models.Region, error) {
+	return models.Region{}, nil
+}
+
+func (m *mockRegionRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
+	return nil
+}
+
+func (m *mockRegionRepository) HasBranches(ctx context.Context, regionID primitive.ObjectID) (bool, error) {
+	return false, nil
+}
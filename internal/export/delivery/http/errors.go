@@ -0,0 +1,29 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"thuchanhgolang/internal/export"
+	pkgErrors "thuchanhgolang/pkg/errors"
+)
+
+var (
+	errWrongQuery       = pkgErrors.NewHTTPError(60000, "Wrong query")
+	errInvalidFormat    = pkgErrors.NewHTTPErrorWithStatus(60001, "Unsupported export format, use csv or ndjson", http.StatusUnprocessableEntity)
+	errInvalidBranchID  = pkgErrors.NewHTTPError(60002, "Invalid branch ID")
+	errInvalidDeptID    = pkgErrors.NewHTTPError(60003, "Invalid department ID")
+	errInvalidRole      = pkgErrors.NewHTTPError(60004, "Invalid role")
+	errFilterOutOfScope = pkgErrors.NewHTTPErrorWithStatus(60005, "Export filter is outside of your scope", http.StatusForbidden)
+	errUnauthorized     = pkgErrors.NewUnauthorizedHTTPError()
+)
+
+func (h handler) mapError(err error) error {
+	if errors.Is(err, export.ErrInvalidFormat) {
+		return errInvalidFormat
+	}
+	if errors.Is(err, export.ErrFilterOutOfScope) {
+		return errFilterOutOfScope
+	}
+	return err
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"thuchanhgolang/internal/export"
+	"thuchanhgolang/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportUsers stream danh sách user trong scope dưới dạng CSV hoặc NDJSON
+func (h handler) exportUsers(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	input, sc, err := h.processExportUsersRequest(c)
+	if err != nil {
+		h.l.Warnf(ctx, "export.handler.exportUsers.processExportUsersRequest: %s", err)
+		response.Error(c, h.mapError(err))
+		return
+	}
+
+	h.setDownloadHeaders(c, exportFilename("users", input.Format), input.Format)
+	if err := h.uc.ExportUsers(ctx, sc, input, c.Writer); err != nil {
+		h.l.Warnf(ctx, "export.handler.exportUsers.uc.ExportUsers: %s", err)
+		h.abort(c, err)
+	}
+}
+
+// exportOrg stream cây tổ chức trong scope dưới dạng CSV hoặc NDJSON
+func (h handler) exportOrg(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	input, sc, err := h.processExportOrgRequest(c)
+	if err != nil {
+		h.l.Warnf(ctx, "export.handler.exportOrg.processExportOrgRequest: %s", err)
+		response.Error(c, h.mapError(err))
+		return
+	}
+
+	h.setDownloadHeaders(c, exportFilename("org", input.Format), input.Format)
+	if err := h.uc.ExportOrg(ctx, sc, input, c.Writer); err != nil {
+		h.l.Warnf(ctx, "export.handler.exportOrg.uc.ExportOrg: %s", err)
+		h.abort(c, err)
+	}
+}
+
+// setDownloadHeaders đặt header để trình duyệt tải file về
+func (h handler) setDownloadHeaders(c *gin.Context, filename string, format export.Format) {
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+}
+
+// abort xử lý lỗi khi export: nếu chưa ghi byte nào thì trả JSON lỗi như bình thường,
+// còn nếu đã stream một phần thì chỉ có thể cắt kết nối (không gửi chunk kết thúc) để client biết file bị thiếu
+func (h handler) abort(c *gin.Context, err error) {
+	if !c.Writer.Written() {
+		c.Writer.Header().Del("Content-Disposition")
+		response.Error(c, h.mapError(err))
+		return
+	}
+
+	c.Abort()
+	conn, _, hErr := c.Writer.Hijack()
+	if hErr != nil {
+		h.l.Warnf(c.Request.Context(), "export.handler.abort.Hijack: %s", hErr)
+		return
+	}
+	conn.Close()
+}
@@ -0,0 +1,23 @@
+package http
+
+import (
+	"thuchanhgolang/internal/export"
+	"thuchanhgolang/pkg/log"
+)
+
+// Handler interface cho export HTTP handlers
+type Handler interface{}
+
+// handler implementation
+type handler struct {
+	l  log.Logger     // Logger
+	uc export.Usecase // Export usecase
+}
+
+// New tạo handler mới cho export
+func New(l log.Logger, uc export.Usecase) Handler {
+	return &handler{
+		l:  l,
+		uc: uc,
+	}
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"thuchanhgolang/internal/export"
+	"thuchanhgolang/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// exportUsersReq là query của request export user
+type exportUsersReq struct {
+	Format          string `form:"format"`
+	BranchID        string `form:"branch_id"`
+	DepartmentID    string `form:"department_id"`
+	Role            string `form:"role"`
+	IncludeInactive string `form:"include_inactive"`
+}
+
+// toInput kiểm tra và chuyển query thành input cho usecase
+func (r exportUsersReq) toInput() (export.ExportUsersInput, error) {
+	format, err := parseFormat(r.Format)
+	if err != nil {
+		return export.ExportUsersInput{}, err
+	}
+	input := export.ExportUsersInput{Format: format}
+
+	if r.BranchID != "" {
+		id, err := primitive.ObjectIDFromHex(r.BranchID)
+		if err != nil {
+			return export.ExportUsersInput{}, errInvalidBranchID
+		}
+		input.BranchID = &id
+	}
+	if r.DepartmentID != "" {
+		id, err := primitive.ObjectIDFromHex(r.DepartmentID)
+		if err != nil {
+			return export.ExportUsersInput{}, errInvalidDeptID
+		}
+		input.DepartmentID = &id
+	}
+	if r.Role != "" {
+		input.Role = models.Role(strings.ToLower(r.Role))
+		if !input.Role.IsValid() {
+			return export.ExportUsersInput{}, errInvalidRole
+		}
+	}
+	if r.IncludeInactive != "" {
+		v, err := strconv.ParseBool(r.IncludeInactive)
+		if err != nil {
+			return export.ExportUsersInput{}, errWrongQuery
+		}
+		input.IncludeInactive = v
+	}
+
+	return input, nil
+}
+
+// exportOrgReq là query của request export cây tổ chức
+type exportOrgReq struct {
+	Format string `form:"format"`
+}
+
+// toInput kiểm tra và chuyển query thành input cho usecase
+func (r exportOrgReq) toInput() (export.ExportOrgInput, error) {
+	format, err := parseFormat(r.Format)
+	if err != nil {
+		return export.ExportOrgInput{}, err
+	}
+	return export.ExportOrgInput{Format: format}, nil
+}
+
+// parseFormat đọc định dạng export, mặc định là CSV
+func parseFormat(v string) (export.Format, error) {
+	if v == "" {
+		return export.FormatCSV, nil
+	}
+	f := export.Format(strings.ToLower(v))
+	if !f.IsValid() {
+		return "", errInvalidFormat
+	}
+	return f, nil
+}
+
+// exportFilename tạo tên file tải về, ví dụ users-20260101.csv
+func exportFilename(name string, format export.Format) string {
+	return fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102"), format)
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"thuchanhgolang/internal/export"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// processExportUsersRequest đọc query và scope cho request export user
+func (h handler) processExportUsersRequest(c *gin.Context) (export.ExportUsersInput, models.Scope, error) {
+	ctx := c.Request.Context()
+
+	var req exportUsersReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.l.Warnf(ctx, "export.http.processExportUsersRequest.ShouldBindQuery: %v", err)
+		return export.ExportUsersInput{}, models.Scope{}, errWrongQuery
+	}
+
+	input, err := req.toInput()
+	if err != nil {
+		h.l.Warnf(ctx, "export.http.processExportUsersRequest.toInput: %v", err)
+		return export.ExportUsersInput{}, models.Scope{}, err
+	}
+
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "export.http.processExportUsersRequest.processScope: %v", err)
+		return export.ExportUsersInput{}, models.Scope{}, err
+	}
+
+	return input, sc, nil
+}
+
+// processExportOrgRequest đọc query và scope cho request export cây tổ chức
+func (h handler) processExportOrgRequest(c *gin.Context) (export.ExportOrgInput, models.Scope, error) {
+	ctx := c.Request.Context()
+
+	var req exportOrgReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.l.Warnf(ctx, "export.http.processExportOrgRequest.ShouldBindQuery: %v", err)
+		return export.ExportOrgInput{}, models.Scope{}, errWrongQuery
+	}
+
+	input, err := req.toInput()
+	if err != nil {
+		h.l.Warnf(ctx, "export.http.processExportOrgRequest.toInput: %v", err)
+		return export.ExportOrgInput{}, models.Scope{}, err
+	}
+
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "export.http.processExportOrgRequest.processScope: %v", err)
+		return export.ExportOrgInput{}, models.Scope{}, err
+	}
+
+	return input, sc, nil
+}
+
+// processScope lấy scope của user đang đăng nhập từ JWT payload
+func (h handler) processScope(c *gin.Context) (models.Scope, error) {
+	payload, ok := jwt.GetPayloadFromContext(c.Request.Context())
+	if !ok {
+		return models.Scope{}, errUnauthorized
+	}
+
+	return jwt.NewScope(payload), nil
+}
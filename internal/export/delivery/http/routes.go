@@ -0,0 +1,25 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"thuchanhgolang/pkg/openapi"
+)
+
+// MapRoutes map các routes cho export
+func MapRoutes(g *gin.RouterGroup, h Handler) {
+	hdl := h.(*handler)
+	g.GET("/users", hdl.exportUsers)
+	g.GET("/org", hdl.exportOrg)
+}
+
+// Spec mô tả các route của MapRoutes cho OpenAPI, file trả về là CSV hoặc NDJSON theo query format
+func Spec() []openapi.Route {
+	types := []string{openapi.ContentCSV, openapi.ContentNDJSON}
+	return []openapi.Route{
+		{Method: http.MethodGet, Path: "/users", Summary: "Export user trong scope", Query: exportUsersReq{}, ResponseTypes: types},
+		{Method: http.MethodGet, Path: "/org", Summary: "Export cây tổ chức trong scope", Query: exportOrgReq{}, ResponseTypes: types},
+	}
+}
@@ -0,0 +1,10 @@
+package export
+
+import pkgErrors "thuchanhgolang/pkg/errors"
+
+var (
+	// ErrInvalidFormat trả về khi định dạng export không được hỗ trợ
+	ErrInvalidFormat = pkgErrors.Invalid("unsupported export format")
+	// ErrFilterOutOfScope trả về khi bộ lọc branch/department nằm ngoài scope của người gọi
+	ErrFilterOutOfScope = pkgErrors.Forbidden("export filter is outside of your scope")
+)
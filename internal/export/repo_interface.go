@@ -0,0 +1,17 @@
+package export
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/models"
+)
+
+// Repository đọc dữ liệu cần export theo cursor, mỗi document được đưa vào fn ngay khi đọc được
+// để không phải giữ toàn bộ kết quả trong bộ nhớ. fn trả lỗi thì dừng đọc.
+type Repository interface {
+	EachShop(ctx context.Context, opts UnitOptions, fn func(models.Shop) error) error
+	EachRegion(ctx context.Context, opts UnitOptions, fn func(models.Region) error) error
+	EachBranch(ctx context.Context, opts UnitOptions, fn func(models.Branch) error) error
+	EachDepartment(ctx context.Context, opts UnitOptions, fn func(models.Department) error) error
+	EachUser(ctx context.Context, opts UserOptions, fn func(models.User) error) error
+}
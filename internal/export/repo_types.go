@@ -0,0 +1,23 @@
+package export
+
+import (
+	"thuchanhgolang/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UnitOptions là bộ lọc khi đọc các đơn vị tổ chức (nil = không lọc theo điều kiện đó)
+type UnitOptions struct {
+	IDs       []primitive.ObjectID // Lọc theo _id
+	ParentIDs []primitive.ObjectID // Lọc theo ID đơn vị cha (shop_id / region_id / branch_id)
+}
+
+// UserOptions là bộ lọc khi đọc user
+type UserOptions struct {
+	ShopID          *primitive.ObjectID
+	RegionID        *primitive.ObjectID
+	BranchID        *primitive.ObjectID
+	DepartmentID    *primitive.ObjectID
+	Role            models.Role // Rỗng = mọi role
+	IncludeInactive bool        // true → lấy cả user đã bị vô hiệu hóa
+}
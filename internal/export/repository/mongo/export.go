@@ -0,0 +1,129 @@
+package mongo
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/export"
+	"thuchanhgolang/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	shopCollection       = "shops"
+	regionCollection     = "regions"
+	branchCollection     = "branches"
+	departmentCollection = "departments"
+	userCollection       = "users"
+)
+
+// each mở cursor trên collection và gọi handle cho từng document, handle tự decode vào kiểu cần dùng
+func (repo implRepository) each(ctx context.Context, collection string, filter bson.M, sort bson.D, handle func(decode func(interface{}) error) error) error {
+	findOpts := options.Find().SetSort(sort)
+	cursor, err := repo.db.Collection(collection).Find(ctx, filter, findOpts)
+	if err != nil {
+		repo.l.Errorf(ctx, "export.mongo.each.Find(%s): %v", collection, err)
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		if err := handle(cursor.Decode); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// unitFilter tạo filter cho các đơn vị tổ chức, parentField là tên field trỏ tới đơn vị cha
+func unitFilter(opts export.UnitOptions, parentField string) bson.M {
+	filter := bson.M{}
+	if opts.IDs != nil {
+		filter["_id"] = bson.M{"$in": opts.IDs}
+	}
+	if opts.ParentIDs != nil && parentField != "" {
+		filter[parentField] = bson.M{"$in": opts.ParentIDs}
+	}
+	return filter
+}
+
+// EachShop đọc lần lượt các shop theo tên
+func (repo implRepository) EachShop(ctx context.Context, opts export.UnitOptions, fn func(models.Shop) error) error {
+	return repo.each(ctx, shopCollection, unitFilter(opts, ""), bson.D{{Key: "name", Value: 1}}, func(decode func(interface{}) error) error {
+		var s models.Shop
+		if err := decode(&s); err != nil {
+			repo.l.Errorf(ctx, "export.mongo.EachShop.Decode: %v", err)
+			return err
+		}
+		return fn(s)
+	})
+}
+
+// EachRegion đọc lần lượt các region theo tên
+func (repo implRepository) EachRegion(ctx context.Context, opts export.UnitOptions, fn func(models.Region) error) error {
+	return repo.each(ctx, regionCollection, unitFilter(opts, "shop_id"), bson.D{{Key: "name", Value: 1}}, func(decode func(interface{}) error) error {
+		var r models.Region
+		if err := decode(&r); err != nil {
+			repo.l.Errorf(ctx, "export.mongo.EachRegion.Decode: %v", err)
+			return err
+		}
+		return fn(r)
+	})
+}
+
+// EachBranch đọc lần lượt các branch theo tên
+func (repo implRepository) EachBranch(ctx context.Context, opts export.UnitOptions, fn func(models.Branch) error) error {
+	return repo.each(ctx, branchCollection, unitFilter(opts, "region_id"), bson.D{{Key: "name", Value: 1}}, func(decode func(interface{}) error) error {
+		var b models.Branch
+		if err := decode(&b); err != nil {
+			repo.l.Errorf(ctx, "export.mongo.EachBranch.Decode: %v", err)
+			return err
+		}
+		return fn(b)
+	})
+}
+
+// EachDepartment đọc lần lượt các department theo tên
+func (repo implRepository) EachDepartment(ctx context.Context, opts export.UnitOptions, fn func(models.Department) error) error {
+	return repo.each(ctx, departmentCollection, unitFilter(opts, "branch_id"), bson.D{{Key: "name", Value: 1}}, func(decode func(interface{}) error) error {
+		var d models.Department
+		if err := decode(&d); err != nil {
+			repo.l.Errorf(ctx, "export.mongo.EachDepartment.Decode: %v", err)
+			return err
+		}
+		return fn(d)
+	})
+}
+
+// EachUser đọc lần lượt các user khớp bộ lọc theo username
+func (repo implRepository) EachUser(ctx context.Context, opts export.UserOptions, fn func(models.User) error) error {
+	filter := bson.M{}
+	if opts.ShopID != nil {
+		filter["shop_id"] = *opts.ShopID
+	}
+	if opts.RegionID != nil {
+		filter["region_id"] = *opts.RegionID
+	}
+	if opts.BranchID != nil {
+		filter["branch_id"] = *opts.BranchID
+	}
+	if opts.DepartmentID != nil {
+		filter["department_id"] = *opts.DepartmentID
+	}
+	if opts.Role != "" {
+		filter["role"] = opts.Role
+	}
+	if !opts.IncludeInactive {
+		filter["deactivated_at"] = bson.M{"$exists": false}
+	}
+
+	return repo.each(ctx, userCollection, filter, bson.D{{Key: "username", Value: 1}}, func(decode func(interface{}) error) error {
+		var u models.User
+		if err := decode(&u); err != nil {
+			repo.l.Errorf(ctx, "export.mongo.EachUser.Decode: %v", err)
+			return err
+		}
+		return fn(u)
+	})
+}
@@ -0,0 +1,21 @@
+package mongo
+
+import (
+	"thuchanhgolang/internal/export"
+	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/mongo"
+)
+
+// implRepository là implementation của export.Repository
+type implRepository struct {
+	l  log.Logger     // Logger để ghi log
+	db mongo.Database // Database connection
+}
+
+// NewRepository tạo một export repository mới
+func NewRepository(l log.Logger, db mongo.Database) export.Repository {
+	return &implRepository{
+		l:  l,
+		db: db,
+	}
+}
@@ -0,0 +1,18 @@
+package export
+
+import (
+	"context"
+	"io"
+
+	"thuchanhgolang/internal/models"
+)
+
+// Usecase ghi dữ liệu trong scope của người gọi ra w theo định dạng CSV hoặc NDJSON.
+// Tên shop/region/branch/department được resolve thay cho ObjectID.
+type Usecase interface {
+	// ExportUsers ghi danh sách user
+	ExportUsers(ctx context.Context, sc models.Scope, input ExportUsersInput, w io.Writer) error
+
+	// ExportOrg ghi cây tổ chức, mỗi dòng là một shop/region/branch/department
+	ExportOrg(ctx context.Context, sc models.Scope, input ExportOrgInput, w io.Writer) error
+}
@@ -0,0 +1,44 @@
+package export
+
+import (
+	"thuchanhgolang/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Format là định dạng file export
+type Format string
+
+const (
+	// FormatCSV là file CSV có dòng header
+	FormatCSV Format = "csv"
+	// FormatNDJSON là JSON Lines, mỗi dòng một object
+	FormatNDJSON Format = "ndjson"
+)
+
+// IsValid kiểm tra định dạng có được hỗ trợ không
+func (f Format) IsValid() bool {
+	return f == FormatCSV || f == FormatNDJSON
+}
+
+// ContentType trả về Content-Type tương ứng với định dạng
+func (f Format) ContentType() string {
+	if f == FormatNDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// ExportUsersInput là bộ lọc export user, luôn bị giới hạn thêm bởi scope của người gọi
+type ExportUsersInput struct {
+	Format          Format
+	BranchID        *primitive.ObjectID
+	DepartmentID    *primitive.ObjectID
+	Role            models.Role
+	IncludeInactive bool
+}
+
+// ExportOrgInput là input export cây tổ chức
+type ExportOrgInput struct {
+	Format Format
+}
@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"context"
+	"io"
+
+	"thuchanhgolang/internal/export"
+	"thuchanhgolang/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	levelShop       = "shop"
+	levelRegion     = "region"
+	levelBranch     = "branch"
+	levelDepartment = "department"
+
+	statusActive      = "active"
+	statusDeactivated = "deactivated"
+)
+
+var (
+	// userColumns là các cột khi export user
+	userColumns = []string{"id", "username", "email", "role", "status", "shop", "region", "branch", "department"}
+	// orgColumns là các cột khi export cây tổ chức
+	orgColumns = []string{"level", "id", "name", "code", "shop", "region", "branch"}
+)
+
+// orgTree giữ tên và quan hệ cha-con của các đơn vị trong shop của người gọi để resolve tên
+type orgTree struct {
+	shops        map[primitive.ObjectID]string
+	regions      map[primitive.ObjectID]string
+	branches     map[primitive.ObjectID]string
+	departments  map[primitive.ObjectID]string
+	regionShop   map[primitive.ObjectID]primitive.ObjectID
+	branchRegion map[primitive.ObjectID]primitive.ObjectID
+}
+
+// orgUnit là một đơn vị được duyệt qua khi đọc cây tổ chức
+type orgUnit struct {
+	level string
+	id    primitive.ObjectID
+	name  string
+	code  string
+	h     models.Hierarchy
+}
+
+// ExportUsers ghi danh sách user trong scope ra w
+func (uc *implUsecase) ExportUsers(ctx context.Context, sc models.Scope, input export.ExportUsersInput, w io.Writer) error {
+	opts, visible, err := userOptions(sc, input)
+	if err != nil {
+		return err
+	}
+
+	rw, err := newRecordWriter(input.Format, w, userColumns)
+	if err != nil {
+		return err
+	}
+	if !visible {
+		return rw.Close()
+	}
+
+	// Đọc tên các đơn vị trước, sau đó stream user
+	tree, err := uc.walkOrg(ctx, sc, nil)
+	if err != nil {
+		return err
+	}
+
+	err = uc.repo.EachUser(ctx, opts, func(u models.User) error {
+		// Lọc lại theo scope phòng trường hợp filter ở database rộng hơn
+		if !sc.Contains(u.Hierarchy()) {
+			return nil
+		}
+
+		status := statusActive
+		if !u.IsActive() {
+			status = statusDeactivated
+		}
+		var department string
+		if u.DepartmentID != nil {
+			department = tree.departments[*u.DepartmentID]
+		}
+
+		return rw.Write([]string{
+			u.ID.Hex(),
+			u.Username,
+			u.Email,
+			u.Role.String(),
+			status,
+			tree.shops[u.ShopID],
+			tree.regions[u.RegionID],
+			tree.branches[u.BranchID],
+			department,
+		})
+	})
+	if err != nil {
+		uc.l.Errorf(ctx, "export.usecase.ExportUsers.repo.EachUser: %v", err)
+		return err
+	}
+
+	return rw.Close()
+}
+
+// ExportOrg ghi các shop/region/branch/department trong scope ra w, theo thứ tự từ trên xuống
+func (uc *implUsecase) ExportOrg(ctx context.Context, sc models.Scope, input export.ExportOrgInput, w io.Writer) error {
+	rw, err := newRecordWriter(input.Format, w, orgColumns)
+	if err != nil {
+		return err
+	}
+
+	_, err = uc.walkOrg(ctx, sc, func(tree *orgTree, u orgUnit) error {
+		if !sc.Contains(u.h) {
+			return nil
+		}
+
+		record := []string{u.level, u.id.Hex(), u.name, u.code, "", "", ""}
+		if u.level != levelShop {
+			record[4] = tree.shops[u.h.ShopID]
+		}
+		if u.level == levelBranch || u.level == levelDepartment {
+			record[5] = tree.regions[u.h.RegionID]
+		}
+		if u.level == levelDepartment {
+			record[6] = tree.branches[u.h.BranchID]
+		}
+		return rw.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+
+	return rw.Close()
+}
+
+// walkOrg đọc lần lượt shop → region → branch → department thuộc shop của người gọi,
+// ghi nhận tên vào cây và gọi visit (nếu có) cho từng đơn vị
+func (uc *implUsecase) walkOrg(ctx context.Context, sc models.Scope, visit func(*orgTree, orgUnit) error) (*orgTree, error) {
+	tree := &orgTree{
+		shops:        make(map[primitive.ObjectID]string),
+		regions:      make(map[primitive.ObjectID]string),
+		branches:     make(map[primitive.ObjectID]string),
+		departments:  make(map[primitive.ObjectID]string),
+		regionShop:   make(map[primitive.ObjectID]primitive.ObjectID),
+		branchRegion: make(map[primitive.ObjectID]primitive.ObjectID),
+	}
+	if sc.ShopID == nil {
+		return tree, nil
+	}
+	emit := func(u orgUnit) error {
+		if visit == nil {
+			return nil
+		}
+		return visit(tree, u)
+	}
+
+	shopIDs := make([]primitive.ObjectID, 0, 1)
+	err := uc.repo.EachShop(ctx, export.UnitOptions{IDs: []primitive.ObjectID{*sc.ShopID}}, func(s models.Shop) error {
+		tree.shops[s.ID] = s.Name
+		shopIDs = append(shopIDs, s.ID)
+		return emit(orgUnit{level: levelShop, id: s.ID, name: s.Name, code: s.Code, h: models.Hierarchy{ShopID: s.ID}})
+	})
+	if err != nil {
+		uc.l.Errorf(ctx, "export.usecase.walkOrg.repo.EachShop: %v", err)
+		return nil, err
+	}
+
+	regionIDs := make([]primitive.ObjectID, 0)
+	err = uc.repo.EachRegion(ctx, export.UnitOptions{ParentIDs: shopIDs}, func(r models.Region) error {
+		tree.regions[r.ID] = r.Name
+		tree.regionShop[r.ID] = r.ShopID
+		regionIDs = append(regionIDs, r.ID)
+		return emit(orgUnit{level: levelRegion, id: r.ID, name: r.Name, h: models.Hierarchy{ShopID: r.ShopID, RegionID: r.ID}})
+	})
+	if err != nil {
+		uc.l.Errorf(ctx, "export.usecase.walkOrg.repo.EachRegion: %v", err)
+		return nil, err
+	}
+
+	branchIDs := make([]primitive.ObjectID, 0)
+	err = uc.repo.EachBranch(ctx, export.UnitOptions{ParentIDs: regionIDs}, func(b models.Branch) error {
+		tree.branches[b.ID] = b.Name
+		tree.branchRegion[b.ID] = b.RegionID
+		branchIDs = append(branchIDs, b.ID)
+		h := models.Hierarchy{ShopID: tree.regionShop[b.RegionID], RegionID: b.RegionID, BranchID: b.ID}
+		return emit(orgUnit{level: levelBranch, id: b.ID, name: b.Name, h: h})
+	})
+	if err != nil {
+		uc.l.Errorf(ctx, "export.usecase.walkOrg.repo.EachBranch: %v", err)
+		return nil, err
+	}
+
+	err = uc.repo.EachDepartment(ctx, export.UnitOptions{ParentIDs: branchIDs}, func(d models.Department) error {
+		tree.departments[d.ID] = d.Name
+		regionID := tree.branchRegion[d.BranchID]
+		deptID := d.ID
+		h := models.Hierarchy{ShopID: tree.regionShop[regionID], RegionID: regionID, BranchID: d.BranchID, DepartmentID: &deptID}
+		return emit(orgUnit{level: levelDepartment, id: d.ID, name: d.Name, h: h})
+	})
+	if err != nil {
+		uc.l.Errorf(ctx, "export.usecase.walkOrg.repo.EachDepartment: %v", err)
+		return nil, err
+	}
+
+	return tree, nil
+}
+
+// userOptions kết hợp scope của người gọi với bộ lọc của client.
+// visible = false khi scope không thấy được user nào (role không hợp lệ hoặc thiếu ID).
+func userOptions(sc models.Scope, input export.ExportUsersInput) (export.UserOptions, bool, error) {
+	opts := export.UserOptions{
+		Role:            input.Role,
+		IncludeInactive: input.IncludeInactive,
+	}
+
+	switch sc.Role {
+	case models.RoleManager:
+		opts.ShopID = sc.ShopID
+	case models.RoleRegionManager:
+		opts.ShopID, opts.RegionID = sc.ShopID, sc.RegionID
+	case models.RoleBranchManager, models.RoleEmployee:
+		opts.ShopID, opts.BranchID = sc.ShopID, sc.BranchID
+	case models.RoleHeadOfDepartment:
+		opts.DepartmentID = sc.DepartmentID
+	}
+	if opts.ShopID == nil && opts.DepartmentID == nil {
+		return export.UserOptions{}, false, nil
+	}
+
+	// Bộ lọc của client chỉ được thu hẹp, không được mở rộng scope
+	if input.BranchID != nil {
+		if opts.BranchID != nil && *opts.BranchID != *input.BranchID {
+			return export.UserOptions{}, false, export.ErrFilterOutOfScope
+		}
+		opts.BranchID = input.BranchID
+	}
+	if input.DepartmentID != nil {
+		if opts.DepartmentID != nil && *opts.DepartmentID != *input.DepartmentID {
+			return export.UserOptions{}, false, export.ErrFilterOutOfScope
+		}
+		opts.DepartmentID = input.DepartmentID
+	}
+
+	return opts, true, nil
+}
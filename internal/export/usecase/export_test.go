@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"thuchanhgolang/internal/export"
+	"thuchanhgolang/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newTestRepo tạo cây: Shop A → Region Bắc → {Branch HN → Dept Sales, Branch HP}
+func newTestRepo() (*mockRepository, models.Hierarchy, models.Hierarchy) {
+	shop := models.Shop{ID: primitive.NewObjectID(), Name: "Shop A", Code: "SA"}
+	region := models.Region{ID: primitive.NewObjectID(), ShopID: shop.ID, Name: "Bắc"}
+	hn := models.Branch{ID: primitive.NewObjectID(), RegionID: region.ID, Name: "Hà Nội"}
+	hp := models.Branch{ID: primitive.NewObjectID(), RegionID: region.ID, Name: "Hải Phòng"}
+	sales := models.Department{ID: primitive.NewObjectID(), BranchID: hn.ID, Name: "Sales, HN"}
+
+	inHN := models.Hierarchy{ShopID: shop.ID, RegionID: region.ID, BranchID: hn.ID, DepartmentID: &sales.ID}
+	inHP := models.Hierarchy{ShopID: shop.ID, RegionID: region.ID, BranchID: hp.ID}
+
+	repo := &mockRepository{
+		shops:       []models.Shop{shop},
+		regions:     []models.Region{region},
+		branches:    []models.Branch{hn, hp},
+		departments: []models.Department{sales},
+		users: []models.User{
+			{ID: primitive.NewObjectID(), Username: "an", Email: "an@x.vn", Role: models.RoleEmployee,
+				ShopID: shop.ID, RegionID: region.ID, BranchID: hn.ID, DepartmentID: &sales.ID},
+			{ID: primitive.NewObjectID(), Username: "binh", Email: "binh@x.vn", Role: models.RoleBranchManager,
+				ShopID: shop.ID, RegionID: region.ID, BranchID: hp.ID},
+		},
+	}
+	return repo, inHN, inHP
+}
+
+func TestExportUsers(t *testing.T) {
+	t.Run("csv resolves names", func(t *testing.T) {
+		repo, inHN, _ := newTestRepo()
+		uc := &implUsecase{l: &mockLogger{}, repo: repo}
+		sc := models.Scope{Role: models.RoleManager, ShopID: &inHN.ShopID}
+
+		var buf bytes.Buffer
+		err := uc.ExportUsers(context.Background(), sc, export.ExportUsersInput{Format: export.FormatCSV}, &buf)
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("Mong đợi header + 2 dòng, nhận được %d:\n%s", len(lines), buf.String())
+		}
+		if lines[0] != "id,username,email,role,status,shop,region,branch,department" {
+			t.Errorf("Header sai: %s", lines[0])
+		}
+		if !strings.HasSuffix(lines[1], `,an,an@x.vn,employee,active,Shop A,Bắc,Hà Nội,"Sales, HN"`) {
+			t.Errorf("Dòng user không được resolve tên: %s", lines[1])
+		}
+	})
+
+	t.Run("branch manager only sees own branch", func(t *testing.T) {
+		repo, _, inHP := newTestRepo()
+		uc := &implUsecase{l: &mockLogger{}, repo: repo}
+		sc := models.Scope{Role: models.RoleBranchManager, ShopID: &inHP.ShopID, BranchID: &inHP.BranchID}
+
+		var buf bytes.Buffer
+		err := uc.ExportUsers(context.Background(), sc, export.ExportUsersInput{Format: export.FormatNDJSON}, &buf)
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 1 || !strings.Contains(lines[0], `"username":"binh"`) {
+			t.Fatalf("Mong đợi chỉ có user binh, nhận được:\n%s", buf.String())
+		}
+		if !strings.Contains(lines[0], `"department":null`) {
+			t.Errorf("Department rỗng phải là null: %s", lines[0])
+		}
+		if repo.userOpts == nil || repo.userOpts.BranchID == nil || *repo.userOpts.BranchID != inHP.BranchID {
+			t.Errorf("Bộ lọc database phải giới hạn theo branch của scope: %+v", repo.userOpts)
+		}
+	})
+
+	t.Run("filter outside scope", func(t *testing.T) {
+		repo, inHN, inHP := newTestRepo()
+		uc := &implUsecase{l: &mockLogger{}, repo: repo}
+		sc := models.Scope{Role: models.RoleBranchManager, ShopID: &inHP.ShopID, BranchID: &inHP.BranchID}
+
+		var buf bytes.Buffer
+		input := export.ExportUsersInput{Format: export.FormatCSV, BranchID: &inHN.BranchID}
+		err := uc.ExportUsers(context.Background(), sc, input, &buf)
+		if !errors.Is(err, export.ErrFilterOutOfScope) {
+			t.Fatalf("Mong đợi ErrFilterOutOfScope, nhận được %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Error("Không được ghi dữ liệu khi bộ lọc sai")
+		}
+	})
+}
+
+func TestExportOrg(t *testing.T) {
+	repo, inHN, _ := newTestRepo()
+	uc := &implUsecase{l: &mockLogger{}, repo: repo}
+
+	t.Run("region manager", func(t *testing.T) {
+		sc := models.Scope{Role: models.RoleRegionManager, ShopID: &inHN.ShopID, RegionID: &inHN.RegionID}
+
+		var buf bytes.Buffer
+		err := uc.ExportOrg(context.Background(), sc, export.ExportOrgInput{Format: export.FormatCSV}, &buf)
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+
+		out := buf.String()
+		if strings.Contains(out, "\nshop,") {
+			t.Errorf("Region manager không được thấy dòng shop:\n%s", out)
+		}
+		for _, want := range []string{"region,", "branch,", "Hải Phòng,,Shop A,Bắc,", `"Sales, HN",,Shop A,Bắc,Hà Nội`} {
+			if !strings.Contains(out, want) {
+				t.Errorf("Thiếu %q trong:\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		sc := models.Scope{Role: models.RoleManager, ShopID: &inHN.ShopID}
+		err := uc.ExportOrg(context.Background(), sc, export.ExportOrgInput{Format: "xlsx"}, &bytes.Buffer{})
+		if !errors.Is(err, export.ErrInvalidFormat) {
+			t.Fatalf("Mong đợi ErrInvalidFormat, nhận được %v", err)
+		}
+	})
+}
@@ -0,0 +1,20 @@
+package usecase
+
+import (
+	"thuchanhgolang/internal/export"
+	"thuchanhgolang/pkg/log"
+)
+
+// implUsecase là implementation của export.Usecase
+type implUsecase struct {
+	l    log.Logger        // Logger
+	repo export.Repository // Đọc dữ liệu theo cursor
+}
+
+// NewUsecase tạo export usecase mới
+func NewUsecase(l log.Logger, repo export.Repository) export.Usecase {
+	return &implUsecase{
+		l:    l,
+		repo: repo,
+	}
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/export"
+	"thuchanhgolang/internal/models"
+)
+
+// mockRepository duyệt dữ liệu cố định và ghi lại bộ lọc user nhận được
+type mockRepository struct {
+	shops       []models.Shop
+	regions     []models.Region
+	branches    []models.Branch
+	departments []models.Department
+	users       []models.User
+	userOpts    *export.UserOptions
+}
+
+func (m *mockRepository) EachShop(ctx context.Context, opts export.UnitOptions, fn func(models.Shop) error) error {
+	for _, s := range m.shops {
+		if err := fn(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockRepository) EachRegion(ctx context.Context, opts export.UnitOptions, fn func(models.Region) error) error {
+	for _, r := range m.regions {
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockRepository) EachBranch(ctx context.Context, opts export.UnitOptions, fn func(models.Branch) error) error {
+	for _, b := range m.branches {
+		if err := fn(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockRepository) EachDepartment(ctx context.Context, opts export.UnitOptions, fn func(models.Department) error) error {
+	for _, d := range m.departments {
+		if err := fn(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockRepository) EachUser(ctx context.Context, opts export.UserOptions, fn func(models.User) error) error {
+	m.userOpts = &opts
+	for _, u := range m.users {
+		if err := fn(u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type mockLogger struct{}
+
+func (m *mockLogger) Debug(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Debugf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Info(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Infof(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Warn(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Warnf(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Error(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Errorf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Fatal(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Fatalf(ctx context.Context, template string, arg ...any) {}
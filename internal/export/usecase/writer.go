@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+
+	"thuchanhgolang/internal/export"
+)
+
+// flushEvery là số dòng giữa hai lần đẩy dữ liệu xuống client
+const flushEvery = 100
+
+// recordWriter ghi từng dòng dữ liệu theo thứ tự cột đã khai báo
+type recordWriter interface {
+	Write(values []string) error
+	// Close đẩy phần dữ liệu còn lại xuống w
+	Close() error
+}
+
+// newRecordWriter tạo writer theo định dạng; với CSV, dòng header được ghi ngay
+func newRecordWriter(format export.Format, w io.Writer, columns []string) (recordWriter, error) {
+	switch format {
+	case export.FormatCSV:
+		cw := &csvRecordWriter{out: w, w: csv.NewWriter(w)}
+		if err := cw.w.Write(columns); err != nil {
+			return nil, err
+		}
+		return cw, nil
+	case export.FormatNDJSON:
+		return &ndjsonRecordWriter{out: w, w: bufio.NewWriter(w), columns: columns}, nil
+	}
+	return nil, export.ErrInvalidFormat
+}
+
+// flushOut đẩy dữ liệu xuống client nếu w hỗ trợ (ví dụ http.ResponseWriter)
+func flushOut(w io.Writer) {
+	if f, ok := w.(interface{ Flush() }); ok {
+		f.Flush()
+	}
+}
+
+// csvRecordWriter ghi CSV
+type csvRecordWriter struct {
+	out io.Writer
+	w   *csv.Writer
+	n   int
+}
+
+func (c *csvRecordWriter) Write(values []string) error {
+	if err := c.w.Write(values); err != nil {
+		return err
+	}
+	c.n++
+	if c.n%flushEvery == 0 {
+		return c.Close()
+	}
+	return nil
+}
+
+func (c *csvRecordWriter) Close() error {
+	c.w.Flush()
+	if err := c.w.Error(); err != nil {
+		return err
+	}
+	flushOut(c.out)
+	return nil
+}
+
+// ndjsonRecordWriter ghi mỗi dòng một JSON object, giữ nguyên thứ tự cột; giá trị rỗng ghi là null
+type ndjsonRecordWriter struct {
+	out     io.Writer
+	w       *bufio.Writer
+	columns []string
+	n       int
+}
+
+func (j *ndjsonRecordWriter) Write(values []string) error {
+	j.w.WriteByte('{')
+	for i, col := range j.columns {
+		if i > 0 {
+			j.w.WriteByte(',')
+		}
+		key, _ := json.Marshal(col)
+		j.w.Write(key)
+		j.w.WriteByte(':')
+
+		if i >= len(values) || values[i] == "" {
+			j.w.WriteString("null")
+			continue
+		}
+		val, err := json.Marshal(values[i])
+		if err != nil {
+			return err
+		}
+		j.w.Write(val)
+	}
+	if _, err := j.w.WriteString("}\n"); err != nil {
+		return err
+	}
+
+	j.n++
+	if j.n%flushEvery == 0 {
+		return j.Close()
+	}
+	return nil
+}
+
+func (j *ndjsonRecordWriter) Close() error {
+	if err := j.w.Flush(); err != nil {
+		return err
+	}
+	flushOut(j.out)
+	return nil
+}
@@ -0,0 +1,104 @@
+package grpcserver
+
+import (
+	"errors"
+	"net/http"
+
+	pkgErrors "thuchanhgolang/pkg/errors"
+	"thuchanhgolang/pkg/response"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errUnauthenticated = status.Error(codes.Unauthenticated, "Unauthorized")
+	errReadOnlyToken   = status.Error(codes.PermissionDenied, "Impersonation token is read-only")
+	errWrongRequest    = status.Error(codes.InvalidArgument, "Wrong request")
+)
+
+// kindCodes là status code của từng loại lỗi nghiệp vụ, tương ứng HTTP status của REST.
+// Đơn vị đang được dùng là FailedPrecondition, version không khớp là Aborted (đọc lại rồi thử lại)
+var kindCodes = map[pkgErrors.Kind]codes.Code{
+	pkgErrors.KindNotFound:           codes.NotFound,
+	pkgErrors.KindConflict:           codes.FailedPrecondition,
+	pkgErrors.KindForbidden:          codes.PermissionDenied,
+	pkgErrors.KindValidation:         codes.InvalidArgument,
+	pkgErrors.KindPreconditionFailed: codes.Aborted,
+}
+
+// httpCodes là status code của lỗi mang HTTP status (lỗi của middleware, delivery dùng chung)
+var httpCodes = map[int]codes.Code{
+	http.StatusBadRequest:            codes.InvalidArgument,
+	http.StatusUnauthorized:          codes.Unauthenticated,
+	http.StatusForbidden:             codes.PermissionDenied,
+	http.StatusNotFound:              codes.NotFound,
+	http.StatusConflict:              codes.FailedPrecondition,
+	http.StatusPreconditionFailed:    codes.Aborted,
+	http.StatusRequestEntityTooLarge: codes.ResourceExhausted,
+	http.StatusUnprocessableEntity:   codes.InvalidArgument,
+	http.StatusPreconditionRequired:  codes.FailedPrecondition,
+	http.StatusTooManyRequests:       codes.ResourceExhausted,
+}
+
+// toStatus chuyển lỗi của usecase / delivery thành gRPC status theo cùng quy tắc với response.Error của REST.
+// Lỗi không xác định trả về Internal với thông báo chung, không lộ lỗi bên trong
+func toStatus(err error) *status.Status {
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+
+	var (
+		collector *pkgErrors.ValidationErrorCollector
+		conflict  *pkgErrors.ConflictError
+		httpErr   *pkgErrors.HTTPError
+		domainErr *pkgErrors.DomainError
+	)
+	switch {
+	case errors.As(err, &collector):
+		return validationStatus(collector)
+	case errors.As(err, &conflict):
+		st := status.New(codes.AlreadyExists, conflict.Message)
+		return withDetails(st, &errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: conflict.Field, Description: "already exists"},
+		}})
+	case errors.As(err, &httpErr):
+		code, ok := httpCodes[httpErr.StatusCode]
+		if !ok {
+			code = codes.InvalidArgument
+		}
+		return status.New(code, httpErr.Message)
+	case errors.As(err, &domainErr):
+		code, ok := kindCodes[domainErr.Kind]
+		if !ok {
+			code = codes.InvalidArgument
+		}
+		return status.New(code, domainErr.Message)
+	default:
+		return status.New(codes.Internal, response.DefaultErrorMessage)
+	}
+}
+
+// validationStatus trả về InvalidArgument kèm lỗi của từng field trong BadRequest
+func validationStatus(collector *pkgErrors.ValidationErrorCollector) *status.Status {
+	details := &errdetails.BadRequest{}
+	for _, e := range collector.Errors() {
+		for _, msg := range e.Messages {
+			details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       e.Field,
+				Description: msg,
+			})
+		}
+	}
+	return withDetails(status.New(codes.InvalidArgument, response.ValidationErrorMsg+": "+collector.Error()), details)
+}
+
+// withDetails gắn details vào status, không gắn được thì trả về status ban đầu
+func withDetails(st *status.Status, details *errdetails.BadRequest) *status.Status {
+	detailed, err := st.WithDetails(details)
+	if err != nil {
+		return st
+	}
+	return detailed
+}
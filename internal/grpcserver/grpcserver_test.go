@@ -0,0 +1,371 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"thuchanhgolang/internal/auth"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/shop"
+	shopGRPC "thuchanhgolang/internal/shop/delivery/grpc"
+	"thuchanhgolang/internal/user"
+	userGRPC "thuchanhgolang/internal/user/delivery/grpc"
+	userUsecase "thuchanhgolang/internal/user/usecase"
+	"thuchanhgolang/pkg/event"
+	"thuchanhgolang/pkg/jwt"
+	"thuchanhgolang/pkg/orgapi"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type mockLogger struct{}
+
+func (m *mockLogger) Debug(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Debugf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Info(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Infof(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Warn(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Warnf(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Error(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Errorf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Fatal(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Fatalf(ctx context.Context, template string, arg ...any) {}
+
+// fakeShopUsecase giữ các shop trong map, method không dùng tới thì panic qua interface nhúng
+type fakeShopUsecase struct {
+	shop.Usecase
+	shops map[primitive.ObjectID]models.Shop
+	scope models.Scope // Scope nhận được ở lần gọi gần nhất
+}
+
+func (uc *fakeShopUsecase) GetByID(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Shop, error) {
+	uc.scope = sc
+	s, ok := uc.shops[id]
+	if !ok {
+		return models.Shop{}, shop.ErrShopNotFound
+	}
+	return s, nil
+}
+
+func (uc *fakeShopUsecase) Create(ctx context.Context, sc models.Scope, input shop.CreateInput) (models.Shop, error) {
+	uc.scope = sc
+	for _, s := range uc.shops {
+		if s.Code == input.Code {
+			return models.Shop{}, shop.ErrCodeExists
+		}
+	}
+	s := models.Shop{ID: primitive.NewObjectID(), Name: input.Name, Code: input.Code, Version: 1}
+	uc.shops[s.ID] = s
+	return s, nil
+}
+
+func (uc *fakeShopUsecase) Update(ctx context.Context, sc models.Scope, input shop.UpdateInput) (models.Shop, error) {
+	s, ok := uc.shops[input.ID]
+	if !ok {
+		return models.Shop{}, shop.ErrShopNotFound
+	}
+	if input.Version != s.Version {
+		return models.Shop{}, shop.ErrVersionMismatch
+	}
+	if input.Name != nil {
+		s.Name = *input.Name
+	}
+	s.Version++
+	uc.shops[s.ID] = s
+	return s, nil
+}
+
+// fakeUserUsecase trả về user theo ID
+type fakeUserUsecase struct {
+	user.Usecase
+	users map[primitive.ObjectID]models.User
+}
+
+func (uc *fakeUserUsecase) GetByID(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.User, error) {
+	u, ok := uc.users[id]
+	if !ok {
+		return models.User{}, user.ErrUserNotFound
+	}
+	return u, nil
+}
+
+// fakeUserRepository lưu user trong map, đủ để chạy user usecase thật qua gRPC
+type fakeUserRepository struct {
+	user.Repository
+	users   map[primitive.ObjectID]models.User
+	deleted []primitive.ObjectID
+}
+
+func (repo *fakeUserRepository) GetByID(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.User, error) {
+	u, ok := repo.users[id]
+	if !ok {
+		return models.User{}, mongo.ErrNoDocuments
+	}
+	return u, nil
+}
+
+func (repo *fakeUserRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
+	repo.deleted = append(repo.deleted, id)
+	return nil
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// fakeImpersonations giả lập audit log impersonation, phiên có trong ended đã bị kết thúc
+type fakeImpersonations struct {
+	ended map[string]bool
+}
+
+func (f *fakeImpersonations) CheckImpersonation(ctx context.Context, id string) error {
+	if f.ended[id] {
+		return auth.ErrImpersonationEnded
+	}
+	return nil
+}
+
+// testEnv là gRPC server chạy trên bufconn cùng client
+type testEnv struct {
+	jwtMgr         jwt.Manager
+	shopUC         *fakeShopUsecase
+	impersonations *fakeImpersonations
+	shops          orgapi.ShopServiceClient
+	users          orgapi.UserServiceClient
+}
+
+func newTestEnv(t *testing.T, shopUC *fakeShopUsecase, userUC user.Usecase) testEnv {
+	t.Helper()
+
+	jwtMgr := jwt.NewManager("test-secret")
+	impersonations := &fakeImpersonations{ended: map[string]bool{}}
+	srv := New(&mockLogger{}, jwtMgr, impersonations)
+	orgapi.RegisterShopServiceServer(srv, shopGRPC.New(&mockLogger{}, shopUC))
+	orgapi.RegisterUserServiceServer(srv, userGRPC.New(&mockLogger{}, userUC))
+
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Không tạo được client: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return testEnv{
+		jwtMgr:         jwtMgr,
+		shopUC:         shopUC,
+		impersonations: impersonations,
+		shops:          orgapi.NewShopServiceClient(conn),
+		users:          orgapi.NewUserServiceClient(conn),
+	}
+}
+
+// withToken gắn access token của payload vào metadata như client thật
+func (e testEnv) withToken(t *testing.T, payload jwt.Payload) context.Context {
+	t.Helper()
+
+	token, err := e.jwtMgr.Generate(payload, time.Hour)
+	if err != nil {
+		t.Fatalf("Không tạo được token: %v", err)
+	}
+	return metadata.AppendToOutgoingContext(context.Background(), orgapi.MetadataAuthorization, "Bearer "+token)
+}
+
+func assertCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Errorf("Mong đợi %s, nhận được %s (%v)", want, got, err)
+	}
+}
+
+func TestShopService(t *testing.T) {
+	shopID := primitive.NewObjectID()
+	otherShopID := primitive.NewObjectID()
+	newEnv := func(t *testing.T) testEnv {
+		return newTestEnv(t, &fakeShopUsecase{shops: map[primitive.ObjectID]models.Shop{
+			shopID:      {ID: shopID, Name: "Shop A", Code: "SHOP-A", Version: 3},
+			otherShopID: {ID: otherShopID, Name: "Shop B", Code: "SHOP-B", Version: 1},
+		}}, &fakeUserUsecase{})
+	}
+	manager := jwt.Payload{UserID: "u1", Role: string(models.RoleManager), ShopID: shopID.Hex()}
+
+	t.Run("request without token is unauthenticated", func(t *testing.T) {
+		env := newEnv(t)
+		_, err := env.shops.Get(context.Background(), &orgapi.GetRequest{ID: shopID.Hex()})
+		assertCode(t, err, codes.Unauthenticated)
+	})
+
+	t.Run("invalid token is unauthenticated", func(t *testing.T) {
+		env := newEnv(t)
+		ctx := metadata.AppendToOutgoingContext(context.Background(), orgapi.MetadataAuthorization, "Bearer invalid")
+		_, err := env.shops.Get(ctx, &orgapi.GetRequest{ID: shopID.Hex()})
+		assertCode(t, err, codes.Unauthenticated)
+	})
+
+	t.Run("manager gets own shop with scope", func(t *testing.T) {
+		env := newEnv(t)
+		got, err := env.shops.Get(env.withToken(t, manager), &orgapi.GetRequest{ID: shopID.Hex()})
+		if err != nil {
+			t.Fatalf("Mong đợi không lỗi, nhận được %v", err)
+		}
+		if got.ID != shopID.Hex() || got.Code != "SHOP-A" || got.Version != 3 {
+			t.Errorf("Shop không khớp: %+v", got)
+		}
+		if env.shopUC.scope.UserID != "u1" || env.shopUC.scope.ShopID == nil || *env.shopUC.scope.ShopID != shopID {
+			t.Errorf("Usecase nhận scope không khớp: %+v", env.shopUC.scope)
+		}
+	})
+
+	t.Run("manager cannot get other shop", func(t *testing.T) {
+		env := newEnv(t)
+		_, err := env.shops.Get(env.withToken(t, manager), &orgapi.GetRequest{ID: otherShopID.Hex()})
+		assertCode(t, err, codes.PermissionDenied)
+	})
+
+	t.Run("employee cannot use shop service", func(t *testing.T) {
+		env := newEnv(t)
+		employee := jwt.Payload{UserID: "u2", Role: string(models.RoleEmployee), ShopID: shopID.Hex()}
+		_, err := env.shops.Get(env.withToken(t, employee), &orgapi.GetRequest{ID: shopID.Hex()})
+		assertCode(t, err, codes.PermissionDenied)
+	})
+
+	t.Run("invalid request returns field violations", func(t *testing.T) {
+		env := newEnv(t)
+		_, err := env.shops.Create(env.withToken(t, manager), &orgapi.CreateShopRequest{Name: " ", Code: "bad code"})
+		assertCode(t, err, codes.InvalidArgument)
+
+		fields := map[string]bool{}
+		for _, d := range status.Convert(err).Details() {
+			if br, ok := d.(*errdetails.BadRequest); ok {
+				for _, v := range br.GetFieldViolations() {
+					fields[v.GetField()] = true
+				}
+			}
+		}
+		if !fields["name"] || !fields["code"] {
+			t.Errorf("Mong đợi lỗi của name và code, nhận được %v", fields)
+		}
+	})
+
+	t.Run("domain errors are mapped to status codes", func(t *testing.T) {
+		env := newEnv(t)
+		ctx := env.withToken(t, manager)
+		name := "Shop A2"
+
+		_, err := env.shops.Create(ctx, &orgapi.CreateShopRequest{Name: "Shop C", Code: "SHOP-A"})
+		assertCode(t, err, codes.AlreadyExists)
+
+		_, err = env.shops.Update(ctx, &orgapi.UpdateShopRequest{ID: shopID.Hex(), Name: &name, Version: 1})
+		assertCode(t, err, codes.Aborted)
+
+		delete(env.shopUC.shops, shopID)
+		_, err = env.shops.Get(ctx, &orgapi.GetRequest{ID: shopID.Hex()})
+		assertCode(t, err, codes.NotFound)
+	})
+
+	t.Run("read-only impersonation cannot write", func(t *testing.T) {
+		env := newEnv(t)
+		impersonated := manager
+		impersonated.Actor = &jwt.Actor{UserID: "admin", ImpersonationID: "imp1"}
+		ctx := env.withToken(t, impersonated)
+
+		if _, err := env.shops.Get(ctx, &orgapi.GetRequest{ID: shopID.Hex()}); err != nil {
+			t.Errorf("Mong đợi được đọc, nhận được %v", err)
+		}
+		_, err := env.shops.Create(ctx, &orgapi.CreateShopRequest{Name: "Shop C", Code: "SHOP-C"})
+		assertCode(t, err, codes.PermissionDenied)
+	})
+
+	t.Run("write impersonation can write", func(t *testing.T) {
+		env := newEnv(t)
+		impersonated := manager
+		impersonated.Actor = &jwt.Actor{UserID: "admin", ImpersonationID: "imp1", AllowWrite: true}
+
+		_, err := env.shops.Create(env.withToken(t, impersonated), &orgapi.CreateShopRequest{Name: "Shop C", Code: "SHOP-C"})
+		if err != nil {
+			t.Errorf("Mong đợi được ghi, nhận được %v", err)
+		}
+	})
+
+	t.Run("ended impersonation is unauthenticated", func(t *testing.T) {
+		env := newEnv(t)
+		impersonated := manager
+		impersonated.Actor = &jwt.Actor{UserID: "admin", ImpersonationID: "imp1"}
+		ctx := env.withToken(t, impersonated)
+		env.impersonations.ended["imp1"] = true
+
+		_, err := env.shops.Get(ctx, &orgapi.GetRequest{ID: shopID.Hex()})
+		assertCode(t, err, codes.Unauthenticated)
+	})
+}
+
+func TestUserServiceScope(t *testing.T) {
+	shopID, branchID, otherBranchID := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	colleague := models.User{ID: primitive.NewObjectID(), Username: "colleague", ShopID: shopID, BranchID: branchID}
+	stranger := models.User{ID: primitive.NewObjectID(), Username: "stranger", ShopID: shopID, BranchID: otherBranchID}
+
+	env := newTestEnv(t, &fakeShopUsecase{}, &fakeUserUsecase{users: map[primitive.ObjectID]models.User{
+		colleague.ID: colleague,
+		stranger.ID:  stranger,
+	}})
+	employee := jwt.Payload{UserID: "u1", Role: string(models.RoleEmployee), ShopID: shopID.Hex(), BranchID: branchID.Hex()}
+	ctx := env.withToken(t, employee)
+
+	got, err := env.users.Get(ctx, &orgapi.GetRequest{ID: colleague.ID.Hex()})
+	if err != nil || got.Username != "colleague" {
+		t.Errorf("Mong đợi đọc được user cùng branch, nhận được %+v, %v", got, err)
+	}
+
+	_, err = env.users.Get(ctx, &orgapi.GetRequest{ID: stranger.ID.Hex()})
+	assertCode(t, err, codes.PermissionDenied)
+
+	_, err = env.users.Delete(ctx, &orgapi.DeleteRequest{ID: colleague.ID.Hex(), Version: 1})
+	assertCode(t, err, codes.PermissionDenied)
+}
+
+func TestUserServiceDeleteScope(t *testing.T) {
+	shopID, branchID, otherBranchID := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	colleague := models.User{ID: primitive.NewObjectID(), Username: "colleague", ShopID: shopID, BranchID: branchID}
+	stranger := models.User{ID: primitive.NewObjectID(), Username: "stranger", ShopID: shopID, BranchID: otherBranchID}
+
+	repo := &fakeUserRepository{users: map[primitive.ObjectID]models.User{
+		colleague.ID: colleague,
+		stranger.ID:  stranger,
+	}}
+	userUC := userUsecase.NewUsecase(&mockLogger{}, repo, nil, nil, nil, nil, fakeTransactor{}, event.NewMemoryPublisher(), nil)
+	env := newTestEnv(t, &fakeShopUsecase{}, userUC)
+	manager := jwt.Payload{UserID: "u1", Role: string(models.RoleBranchManager), ShopID: shopID.Hex(), BranchID: branchID.Hex()}
+	ctx := env.withToken(t, manager)
+
+	// User ở branch khác: usecase từ chối trước khi xóa
+	_, err := env.users.Delete(ctx, &orgapi.DeleteRequest{ID: stranger.ID.Hex(), Version: 1})
+	assertCode(t, err, codes.PermissionDenied)
+	if len(repo.deleted) != 0 {
+		t.Errorf("Không được xóa user ngoài scope, đã xóa: %v", repo.deleted)
+	}
+
+	_, err = env.users.Delete(ctx, &orgapi.DeleteRequest{ID: colleague.ID.Hex(), Version: 1})
+	if err != nil {
+		t.Errorf("Mong đợi xóa được user cùng branch, nhận được %v", err)
+	}
+	if len(repo.deleted) != 1 || repo.deleted[0] != colleague.ID {
+		t.Errorf("Mong đợi xóa %s, đã xóa: %v", colleague.ID.Hex(), repo.deleted)
+	}
+}
@@ -0,0 +1,93 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"thuchanhgolang/internal/auth"
+	pkgErrors "thuchanhgolang/pkg/errors"
+	"thuchanhgolang/pkg/jwt"
+	"thuchanhgolang/pkg/orgapi"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// logging ghi log mỗi lời gọi với status code và thời gian xử lý
+func (i interceptors) logging(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+
+	code := status.Code(err)
+	switch code {
+	case codes.OK:
+		i.l.Infof(ctx, "grpc %s %s %s", info.FullMethod, code, time.Since(start))
+	case codes.Internal, codes.Unknown:
+		i.l.Errorf(ctx, "grpc %s %s %s", info.FullMethod, code, time.Since(start))
+	default:
+		i.l.Warnf(ctx, "grpc %s %s %s", info.FullMethod, code, time.Since(start))
+	}
+	return resp, err
+}
+
+// errors chuyển lỗi trả về từ service thành gRPC status
+func (i interceptors) errors(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err == nil {
+		return resp, nil
+	}
+
+	st := toStatus(err)
+	if st.Code() == codes.Internal {
+		i.l.Errorf(ctx, "grpcserver.errors: %s: %v", info.FullMethod, err)
+	}
+	return nil, st.Err()
+}
+
+// auth xác thực access token trong metadata "authorization" như middleware Auth của REST,
+// token impersonation phải thuộc phiên chưa kết thúc và chỉ được gọi method đọc trừ khi admin cho phép ghi
+func (i interceptors) auth(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(orgapi.MetadataAuthorization)
+	if len(values) == 0 {
+		return nil, errUnauthenticated
+	}
+
+	tokenString := strings.TrimPrefix(values[0], "Bearer ")
+	payload, err := i.jwtMgr.Verify(tokenString)
+	if err != nil {
+		i.l.Warnf(ctx, "grpcserver.auth.Verify: %v", err)
+		return nil, errUnauthenticated
+	}
+
+	if payload.IsImpersonated() {
+		if err := i.impersonations.CheckImpersonation(ctx, payload.Actor.ImpersonationID); err != nil {
+			if errors.Is(err, auth.ErrImpersonationEnded) {
+				i.l.Warnf(ctx, "grpcserver.auth: impersonation %s has ended", payload.Actor.ImpersonationID)
+				return nil, errUnauthenticated
+			}
+			return nil, err
+		}
+		if !payload.Actor.AllowWrite && !orgapi.IsReadOnly(info.FullMethod) {
+			i.l.Warnf(ctx, "grpcserver.auth: impersonation %s is read-only", payload.Actor.ImpersonationID)
+			return nil, errReadOnlyToken
+		}
+		i.l.Infof(ctx, "grpcserver.auth: actor %s impersonating user %s: %s",
+			payload.Actor.UserID, payload.UserID, info.FullMethod)
+	}
+
+	return handler(jwt.SetPayloadToContext(ctx, payload), req)
+}
+
+// validate kiểm tra request theo binding tag của message, lỗi trả về theo từng field
+func (i interceptors) validate(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := i.validator.Struct(req); err != nil {
+		i.l.Warnf(ctx, "grpcserver.validate: %s: %v", info.FullMethod, err)
+		return nil, pkgErrors.BindingError(err, errWrongRequest)
+	}
+	return handler(ctx, req)
+}
@@ -0,0 +1,38 @@
+package grpcserver
+
+import (
+	"thuchanhgolang/internal/auth"
+	"thuchanhgolang/internal/models"
+	pkgErrors "thuchanhgolang/pkg/errors"
+	"thuchanhgolang/pkg/jwt"
+	"thuchanhgolang/pkg/log"
+
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+)
+
+// validationTag là tag của các message trong orgapi, giống binding tag của request REST
+const validationTag = "binding"
+
+type interceptors struct {
+	l              log.Logger
+	jwtMgr         jwt.Manager
+	impersonations auth.ImpersonationChecker
+	validator      *validator.Validate
+}
+
+// New tạo gRPC server với các interceptor: log, chuyển lỗi thành status, xác thực JWT và validate request.
+// Service được đăng ký sau bằng orgapi.Register...Server
+func New(l log.Logger, jwtMgr jwt.Manager, impersonations auth.ImpersonationChecker) *grpc.Server {
+	// Validator riêng với các tag giống REST (objectid, shopcode, username, notblank...).
+	// Chỉ lỗi khi tag rỗng nên panic như regexp.MustCompile
+	v := validator.New()
+	v.SetTagName(validationTag)
+	isRole := func(r string) bool { return models.Role(r).IsValid() }
+	if err := pkgErrors.RegisterValidators(v, isRole); err != nil {
+		panic(err)
+	}
+
+	i := interceptors{l: l, jwtMgr: jwtMgr, impersonations: impersonations, validator: v}
+	return grpc.NewServer(grpc.ChainUnaryInterceptor(i.logging, i.errors, i.auth, i.validate))
+}
@@ -0,0 +1,102 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/mongo"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrNotCreated trả về khi entity chưa được tạo tại thời điểm cần xem
+var ErrNotCreated = errors.New("entity did not exist at the given time")
+
+// Reader đọc lịch sử của một loại entity, T là model được lưu trong snapshot.
+// Dùng chung cho GetAsOf / History của usecase các entity, usecase chỉ ghi log và đổi lỗi sang lỗi của domain.
+type Reader[T any] struct {
+	Repo   Repository
+	Entity string
+	// Current lấy trạng thái hiện tại của entity, trả về mongo.ErrNoDocuments khi không còn tồn tại
+	Current func(ctx context.Context, id primitive.ObjectID) (T, error)
+	// CreatedAt trả về thời điểm tạo của entity
+	CreatedAt func(T) time.Time
+}
+
+// Entry là một revision đã đọc snapshot
+type Entry[T any] struct {
+	Snapshot  T
+	Action    models.RevisionAction
+	ChangedAt time.Time
+	ChangedBy string
+}
+
+// GetAsOf lấy trạng thái của entity tại thời điểm asOf
+// Flow: Tìm revision đầu tiên bị thay thế sau asOf -> Không có thì lấy trạng thái hiện tại -> Kiểm tra entity đã tồn tại tại asOf
+func (r Reader[T]) GetAsOf(ctx context.Context, id primitive.ObjectID, asOf time.Time) (T, error) {
+	var result, empty T
+
+	// Bước 1: Snapshot của revision đầu tiên bị thay thế sau asOf là trạng thái tại asOf
+	rev, err := r.Repo.GetAsOf(ctx, GetAsOfOptions{Entity: r.Entity, EntityID: id, AsOf: asOf})
+	switch {
+	case err == nil:
+		if err := rev.Decode(&result); err != nil {
+			return empty, err
+		}
+	case errors.Is(err, mongo.ErrNoDocuments):
+		// Bước 2: Không thay đổi từ sau asOf thì trạng thái tại asOf là trạng thái hiện tại
+		result, err = r.Current(ctx, id)
+		if err != nil {
+			return empty, err
+		}
+	default:
+		return empty, err
+	}
+
+	// Bước 3: Entity tạo sau asOf thì chưa tồn tại tại thời điểm đó
+	if r.CreatedAt(result).After(asOf) {
+		return empty, ErrNotCreated
+	}
+
+	return result, nil
+}
+
+// List lấy lịch sử thay đổi của entity, mới nhất đứng trước.
+// Chưa có revision nào thì entity phải còn tồn tại, nếu không trả về lỗi của Current.
+func (r Reader[T]) List(ctx context.Context, id primitive.ObjectID, pagin paginator.PaginatorQuery) ([]Entry[T], paginator.Paginator, error) {
+	// Bước 1: Đưa page, limit về giá trị mặc định nếu không hợp lệ
+	pagin.Adjust()
+
+	// Bước 2: Lấy các revision của entity
+	revs, pag, err := r.Repo.List(ctx, ListOptions{Entity: r.Entity, EntityID: id, Pagin: pagin})
+	if err != nil {
+		return nil, paginator.Paginator{}, err
+	}
+
+	// Bước 3: Chưa có revision nào thì entity phải còn tồn tại
+	if pag.Total == 0 {
+		if _, err := r.Current(ctx, id); err != nil {
+			return nil, paginator.Paginator{}, err
+		}
+	}
+
+	// Bước 4: Đọc snapshot của từng revision
+	entries := make([]Entry[T], 0, len(revs))
+	for _, rev := range revs {
+		var snapshot T
+		if err := rev.Decode(&snapshot); err != nil {
+			return nil, paginator.Paginator{}, err
+		}
+		entries = append(entries, Entry[T]{
+			Snapshot:  snapshot,
+			Action:    rev.Action,
+			ChangedAt: rev.ChangedAt,
+			ChangedBy: rev.ChangedBy,
+		})
+	}
+
+	return entries, pag, nil
+}
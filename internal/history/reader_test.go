@@ -0,0 +1,156 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/mongo"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryRepository trả về các revision cho trước, revision mới nhất đứng trước
+type memoryRepository struct {
+	revs     []models.Revision
+	err      error
+	lastList ListOptions
+}
+
+func (m *memoryRepository) List(ctx context.Context, opts ListOptions) ([]models.Revision, paginator.Paginator, error) {
+	m.lastList = opts
+	if m.err != nil {
+		return nil, paginator.Paginator{}, m.err
+	}
+	return m.revs, paginator.Paginator{Total: int64(len(m.revs)), Count: int64(len(m.revs))}, nil
+}
+
+func (m *memoryRepository) GetAsOf(ctx context.Context, opts GetAsOfOptions) (models.Revision, error) {
+	if m.err != nil {
+		return models.Revision{}, m.err
+	}
+	if len(m.revs) == 0 {
+		return models.Revision{}, mongo.ErrNoDocuments
+	}
+	return m.revs[len(m.revs)-1], nil
+}
+
+func newShopReader(repo Repository, current *models.Shop) Reader[models.Shop] {
+	return Reader[models.Shop]{
+		Repo:   repo,
+		Entity: EntityShop,
+		Current: func(ctx context.Context, id primitive.ObjectID) (models.Shop, error) {
+			if current == nil {
+				return models.Shop{}, mongo.ErrNoDocuments
+			}
+			return *current, nil
+		},
+		CreatedAt: func(s models.Shop) time.Time { return s.CreatedAt },
+	}
+}
+
+func shopRevision(t *testing.T, s models.Shop) models.Revision {
+	t.Helper()
+	raw, err := bson.Marshal(s)
+	if err != nil {
+		t.Fatalf("Không marshal được snapshot: %v", err)
+	}
+	return models.Revision{Action: models.RevisionUpdate, Snapshot: raw, ChangedBy: "u1"}
+}
+
+func TestReaderGetAsOf(t *testing.T) {
+	id := primitive.NewObjectID()
+	asOf := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	current := models.Shop{ID: id, Name: "Hiện tại", CreatedAt: asOf.AddDate(0, -1, 0)}
+
+	t.Run("snapshot changed after as_of", func(t *testing.T) {
+		repo := &memoryRepository{revs: []models.Revision{shopRevision(t, models.Shop{ID: id, Name: "Tên cũ", CreatedAt: current.CreatedAt})}}
+
+		got, err := newShopReader(repo, &current).GetAsOf(context.Background(), id, asOf)
+
+		if err != nil || got.Name != "Tên cũ" {
+			t.Errorf("Mong đợi trạng thái trong snapshot, nhận được %q, %v", got.Name, err)
+		}
+	})
+
+	t.Run("current when unchanged after as_of", func(t *testing.T) {
+		got, err := newShopReader(&memoryRepository{}, &current).GetAsOf(context.Background(), id, asOf)
+
+		if err != nil || got.Name != "Hiện tại" {
+			t.Errorf("Mong đợi trạng thái hiện tại, nhận được %q, %v", got.Name, err)
+		}
+	})
+
+	t.Run("before created", func(t *testing.T) {
+		_, err := newShopReader(&memoryRepository{}, &current).GetAsOf(context.Background(), id, current.CreatedAt.Add(-time.Hour))
+
+		if !errors.Is(err, ErrNotCreated) {
+			t.Errorf("Mong đợi ErrNotCreated, nhận được %v", err)
+		}
+	})
+
+	t.Run("missing entity", func(t *testing.T) {
+		_, err := newShopReader(&memoryRepository{}, nil).GetAsOf(context.Background(), id, asOf)
+
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			t.Errorf("Mong đợi lỗi của Current, nhận được %v", err)
+		}
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		dbErr := errors.New("db down")
+
+		_, err := newShopReader(&memoryRepository{err: dbErr}, &current).GetAsOf(context.Background(), id, asOf)
+
+		if !errors.Is(err, dbErr) {
+			t.Errorf("Mong đợi lỗi database, nhận được %v", err)
+		}
+	})
+}
+
+func TestReaderList(t *testing.T) {
+	id := primitive.NewObjectID()
+	current := models.Shop{ID: id, Name: "Hiện tại"}
+
+	t.Run("decodes snapshots", func(t *testing.T) {
+		repo := &memoryRepository{revs: []models.Revision{
+			shopRevision(t, models.Shop{ID: id, Name: "Tên 2", Version: 2}),
+			shopRevision(t, models.Shop{ID: id, Name: "Tên 1", Version: 1}),
+		}}
+
+		entries, pag, err := newShopReader(repo, nil).List(context.Background(), id, paginator.PaginatorQuery{})
+
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if pag.Total != 2 || len(entries) != 2 || entries[0].Snapshot.Name != "Tên 2" || entries[1].Snapshot.Version != 1 {
+			t.Errorf("Entry không khớp: %+v", entries)
+		}
+		if entries[0].Action != models.RevisionUpdate || entries[0].ChangedBy != "u1" {
+			t.Errorf("Thông tin revision không khớp: %+v", entries[0])
+		}
+		if repo.lastList.Entity != EntityShop || repo.lastList.EntityID != id || repo.lastList.Pagin.Limit == 0 {
+			t.Errorf("Options không khớp hoặc chưa adjust phân trang: %+v", repo.lastList)
+		}
+	})
+
+	t.Run("no revisions for existing entity", func(t *testing.T) {
+		entries, _, err := newShopReader(&memoryRepository{}, &current).List(context.Background(), id, paginator.PaginatorQuery{})
+
+		if err != nil || len(entries) != 0 {
+			t.Errorf("Mong đợi danh sách rỗng, nhận được %v, %v", entries, err)
+		}
+	})
+
+	t.Run("no revisions for missing entity", func(t *testing.T) {
+		_, _, err := newShopReader(&memoryRepository{}, nil).List(context.Background(), id, paginator.PaginatorQuery{})
+
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			t.Errorf("Mong đợi lỗi của Current, nhận được %v", err)
+		}
+	})
+}
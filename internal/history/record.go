@@ -0,0 +1,46 @@
+package history
+
+import (
+	"context"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/mongo"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RecordRevisions lưu snapshot của các document trước khi bị sửa hoặc xóa vào lịch sử.
+// Dùng chung cho repository của mọi entity, ctx mang session thì revision được ghi trong cùng transaction.
+func RecordRevisions(ctx context.Context, db mongo.Database, entity string, action models.RevisionAction, changedBy string, changedAt time.Time, snapshots ...bson.Raw) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	docs := newRevisions(db.NewObjectID, entity, action, changedBy, changedAt, snapshots)
+	_, err := db.Collection(Collection).InsertMany(ctx, docs)
+	return err
+}
+
+// newRevisions tạo revision cho các snapshot của cùng một loại entity, sẵn sàng để InsertMany.
+// ID và version của entity được đọc từ chính snapshot.
+func newRevisions(newID func() primitive.ObjectID, entity string, action models.RevisionAction, changedBy string, changedAt time.Time, snapshots []bson.Raw) []interface{} {
+	docs := make([]interface{}, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		rev := models.Revision{
+			ID:        newID(),
+			Entity:    entity,
+			Action:    action,
+			Snapshot:  snapshot,
+			ChangedAt: changedAt,
+			ChangedBy: changedBy,
+		}
+		rev.EntityID, _ = snapshot.Lookup("_id").ObjectIDOK()
+		if version, ok := snapshot.Lookup("version").AsInt64OK(); ok {
+			rev.Version = int(version)
+		}
+		docs = append(docs, rev)
+	}
+	return docs
+}
@@ -0,0 +1,18 @@
+package history
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/paginator"
+)
+
+// Repository định nghĩa các thao tác đọc lịch sử thay đổi.
+// Revision được ghi bởi repository của từng entity trong Update / Delete.
+type Repository interface {
+	// List lấy các revision của một entity, revision mới nhất đứng trước
+	List(ctx context.Context, opts ListOptions) ([]models.Revision, paginator.Paginator, error)
+	// GetAsOf lấy revision đầu tiên bị thay thế sau thời điểm AsOf,
+	// trả về mongo.ErrNoDocuments nếu entity không thay đổi từ sau AsOf
+	GetAsOf(ctx context.Context, opts GetAsOfOptions) (models.Revision, error)
+}
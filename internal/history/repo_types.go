@@ -0,0 +1,39 @@
+package history
+
+import (
+	"time"
+
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Collection là collection lưu revision của mọi entity
+const Collection = "revisions"
+
+// Loại entity có lịch sử, trùng tên collection của entity
+const (
+	EntityShop       = "shops"
+	EntityRegion     = "regions"
+	EntityBranch     = "branches"
+	EntityDepartment = "departments"
+	EntityUser       = "users"
+)
+
+// UserProjection bỏ mật khẩu khỏi snapshot của user, lịch sử không giữ lại mật khẩu cũ
+var UserProjection = bson.M{"password": 0}
+
+// ListOptions là options để lấy lịch sử của một entity
+type ListOptions struct {
+	Entity   string
+	EntityID primitive.ObjectID
+	Pagin    paginator.PaginatorQuery
+}
+
+// GetAsOfOptions là options để lấy trạng thái của một entity tại một thời điểm
+type GetAsOfOptions struct {
+	Entity   string
+	EntityID primitive.ObjectID
+	AsOf     time.Time
+}
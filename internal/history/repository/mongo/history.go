@@ -0,0 +1,86 @@
+package mongo
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/history"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/mongo"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// getRevisionCollection lấy collection revisions từ database
+func (repo implRepository) getRevisionCollection() mongo.Collection {
+	return repo.db.Collection(history.Collection)
+}
+
+// List lấy các revision của một entity, revision mới nhất đứng trước
+func (repo implRepository) List(ctx context.Context, opts history.ListOptions) ([]models.Revision, paginator.Paginator, error) {
+	col := repo.getRevisionCollection()
+
+	// Bước 1: Đếm tổng số revision của entity
+	filter := bson.M{"entity": opts.Entity, "entity_id": opts.EntityID}
+	total, err := col.CountDocuments(ctx, filter)
+	if err != nil {
+		repo.l.Errorf(ctx, "history.mongo.List.CountDocuments: %v", err)
+		return nil, paginator.Paginator{}, err
+	}
+
+	// Bước 2: Lấy revision của trang hiện tại
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "changed_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(opts.Pagin.Offset()).
+		SetLimit(opts.Pagin.Limit)
+	cursor, err := col.Find(ctx, filter, findOpts)
+	if err != nil {
+		repo.l.Errorf(ctx, "history.mongo.List.Find: %v", err)
+		return nil, paginator.Paginator{}, err
+	}
+
+	var revisions []models.Revision
+	if err := cursor.All(ctx, &revisions); err != nil {
+		repo.l.Errorf(ctx, "history.mongo.List.All: %v", err)
+		return nil, paginator.Paginator{}, err
+	}
+
+	return revisions, paginator.Paginator{
+		Total:       total,
+		Count:       int64(len(revisions)),
+		PerPage:     opts.Pagin.Limit,
+		CurrentPage: opts.Pagin.Page,
+	}, nil
+}
+
+// GetAsOf lấy revision đầu tiên bị thay thế sau thời điểm AsOf.
+// Snapshot của revision này là trạng thái của entity tại AsOf.
+func (repo implRepository) GetAsOf(ctx context.Context, opts history.GetAsOfOptions) (models.Revision, error) {
+	col := repo.getRevisionCollection()
+
+	filter := bson.M{
+		"entity":     opts.Entity,
+		"entity_id":  opts.EntityID,
+		"changed_at": bson.M{"$gt": opts.AsOf},
+	}
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "changed_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(1)
+	cursor, err := col.Find(ctx, filter, findOpts)
+	if err != nil {
+		repo.l.Errorf(ctx, "history.mongo.GetAsOf.Find: %v", err)
+		return models.Revision{}, err
+	}
+
+	var revisions []models.Revision
+	if err := cursor.All(ctx, &revisions); err != nil {
+		repo.l.Errorf(ctx, "history.mongo.GetAsOf.All: %v", err)
+		return models.Revision{}, err
+	}
+	if len(revisions) == 0 {
+		return models.Revision{}, mongo.ErrNoDocuments
+	}
+
+	return revisions[0], nil
+}
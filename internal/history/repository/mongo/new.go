@@ -0,0 +1,21 @@
+package mongo
+
+import (
+	"thuchanhgolang/internal/history"
+	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/mongo"
+)
+
+// implRepository là implementation của history.Repository
+type implRepository struct {
+	l  log.Logger     // Logger để ghi log
+	db mongo.Database // Database connection
+}
+
+// NewRepository tạo một history repository mới
+func NewRepository(l log.Logger, db mongo.Database) history.Repository {
+	return &implRepository{
+		l:  l,
+		db: db,
+	}
+}
@@ -7,44 +7,93 @@ import (
 	authUsecase "thuchanhgolang/internal/auth/usecase"
 
 	// branches
+	branchGRPC "thuchanhgolang/internal/branch/delivery/grpc"
 	branchHTTP "thuchanhgolang/internal/branch/delivery/http"
 	branchMongo "thuchanhgolang/internal/branch/repository/mongo"
 	branchUsecase "thuchanhgolang/internal/branch/usecase"
 
+	// cascade delete
+	cascadeMongo "thuchanhgolang/internal/cascade/repository/mongo"
+	cascadeUsecase "thuchanhgolang/internal/cascade/usecase"
+
 	// departments
+	departmentGRPC "thuchanhgolang/internal/department/delivery/grpc"
 	departmentHTTP "thuchanhgolang/internal/department/delivery/http"
 	departmentMongo "thuchanhgolang/internal/department/repository/mongo"
 	departmentUsecase "thuchanhgolang/internal/department/usecase"
 
+	// export
+	exportHTTP "thuchanhgolang/internal/export/delivery/http"
+	exportMongo "thuchanhgolang/internal/export/repository/mongo"
+	exportUsecase "thuchanhgolang/internal/export/usecase"
+
+	// history
+	historyMongo "thuchanhgolang/internal/history/repository/mongo"
+
+	// idempotency keys
+	idempotencyMongo "thuchanhgolang/internal/idempotency/repository/mongo"
+
+	// outbox
+	outboxMongo "thuchanhgolang/internal/outbox/repository/mongo"
+
 	// regions
+	regionGRPC "thuchanhgolang/internal/region/delivery/grpc"
 	regionHTTP "thuchanhgolang/internal/region/delivery/http"
 	regionMongo "thuchanhgolang/internal/region/repository/mongo"
 	regionUsecase "thuchanhgolang/internal/region/usecase"
 
 	// shops
+	shopGRPC "thuchanhgolang/internal/shop/delivery/grpc"
 	shopHTTP "thuchanhgolang/internal/shop/delivery/http"
 	shopMongo "thuchanhgolang/internal/shop/repository/mongo"
 	shopUsecase "thuchanhgolang/internal/shop/usecase"
 
+	// SSE stream
+	streamHTTP "thuchanhgolang/internal/stream/delivery/http"
+	streamMongo "thuchanhgolang/internal/stream/repository/mongo"
+	streamUsecase "thuchanhgolang/internal/stream/usecase"
+
 	// users
+	userGRPC "thuchanhgolang/internal/user/delivery/grpc"
 	userHTTP "thuchanhgolang/internal/user/delivery/http"
 	userMongo "thuchanhgolang/internal/user/repository/mongo"
 	userUsecase "thuchanhgolang/internal/user/usecase"
 
+	// webhooks
+	webhookHTTP "thuchanhgolang/internal/webhook/delivery/http"
+	webhookMongo "thuchanhgolang/internal/webhook/repository/mongo"
+	webhookUsecase "thuchanhgolang/internal/webhook/usecase"
+
+	// gRPC
+	"thuchanhgolang/internal/grpcserver"
+	"thuchanhgolang/pkg/orgapi"
+
+	"google.golang.org/grpc"
+
 	// JWT
 	"thuchanhgolang/pkg/jwt"
 
+	// Models
+	"thuchanhgolang/internal/models"
+
+	// Mongo
+	"thuchanhgolang/pkg/mongo"
+
+	// Rate limit
+	"thuchanhgolang/pkg/ratelimit"
+
 	// Middleware
 	"thuchanhgolang/internal/middleware"
 )
 
-func (srv HTTPServer) mapHandlers() {
+// mapHandlers gắn các route REST vào gin và trả về gRPC server với các service dùng chung usecase
+func (srv HTTPServer) mapHandlers() *grpc.Server {
+	// Validator cho binding tag của request
+	registerValidators()
+
 	// JWT Manager
 	jwtManager := jwt.NewManager(srv.jwtSecretKey)
 
-	// Middleware (encrypter có thể nil tạm thời)
-	authMiddleware := middleware.New(srv.l, jwtManager, nil)
-
 	// Repositories
 	authRepo := authMongo.NewRepository(srv.l, srv.database)
 	shopRepo := shopMongo.NewRepository(srv.l, srv.database)
@@ -52,56 +101,134 @@ func (srv HTTPServer) mapHandlers() {
 	branchRepo := branchMongo.NewRepository(srv.l, srv.database)
 	departmentRepo := departmentMongo.NewRepository(srv.l, srv.database)
 	userRepo := userMongo.NewRepository(srv.l, srv.database)
+	cascadeRepo := cascadeMongo.NewRepository(srv.l, srv.database)
+	exportRepo := exportMongo.NewRepository(srv.l, srv.database)
+	historyRepo := historyMongo.NewRepository(srv.l, srv.database)
+	webhookRepo := webhookMongo.NewRepository(srv.l, srv.database)
+	outboxRepo := outboxMongo.NewRepository(srv.l, srv.database)
+	streamRepo := streamMongo.NewRepository(srv.l, srv.database)
+	idempotencyRepo := idempotencyMongo.NewRepository(srv.l, srv.database)
+
+	// Rate limit store, mặc định giữ trong bộ nhớ
+	rateLimitStore := srv.rateLimitStore
+	if rateLimitStore == nil {
+		rateLimitStore = ratelimit.NewMemoryStore()
+	}
+
+	// Transaction dùng chung cho các usecase
+	tx := mongo.NewTransactor(srv.database.Client())
+
+	// Event nghiệp vụ được ghi vào outbox cùng transaction, dispatcher gửi đi sau (xem Run)
+	publisher := outboxRepo
 
 	// Usecases
-	authUC := authUsecase.NewUsecase(srv.l, authRepo, jwtManager, srv.accessDuration)
-	shopUC := shopUsecase.NewUsecase(srv.l, shopRepo)
-	regionUC := regionUsecase.NewUsecase(srv.l, regionRepo)
-	branchUC := branchUsecase.NewUsecase(srv.l, branchRepo)
-	departmentUC := departmentUsecase.NewUsecase(srv.l, departmentRepo)
-	userUC := userUsecase.NewUsecase(srv.l, userRepo, branchRepo, departmentRepo, regionRepo)
+	authUC := authUsecase.NewUsecase(srv.l, authRepo, jwtManager, srv.accessDuration, srv.impersonationDuration, tx, publisher)
+	shopUC := shopUsecase.NewUsecase(srv.l, shopRepo, historyRepo, tx, publisher)
+	regionUC := regionUsecase.NewUsecase(srv.l, regionRepo, historyRepo, tx, publisher)
+	branchUC := branchUsecase.NewUsecase(srv.l, branchRepo, regionRepo, historyRepo, tx, publisher)
+	departmentUC := departmentUsecase.NewUsecase(srv.l, departmentRepo, branchRepo, regionRepo, historyRepo, tx, publisher)
+	userUC := userUsecase.NewUsecase(srv.l, userRepo, branchRepo, departmentRepo, regionRepo, historyRepo, tx, publisher, srv.encrypter)
+	cascadeUC := cascadeUsecase.NewUsecase(srv.l, cascadeRepo, tx, publisher)
+	exportUC := exportUsecase.NewUsecase(srv.l, exportRepo)
+	webhookUC := webhookUsecase.NewUsecase(srv.l, webhookRepo)
+	streamUC := streamUsecase.NewUsecase(srv.l, streamRepo)
+
+	// Middleware, token impersonation được đối chiếu với audit log qua auth usecase
+	authMiddleware := middleware.New(srv.l, jwtManager, srv.encrypter, idempotencyRepo, rateLimitStore, authUC)
 
 	// Handlers
 	authH := authHTTP.New(srv.l, authUC)
-	shopH := shopHTTP.New(srv.l, shopUC)
-	regionH := regionHTTP.New(srv.l, regionUC)
-	branchH := branchHTTP.New(srv.l, branchUC)
+	shopH := shopHTTP.New(srv.l, shopUC, cascadeUC)
+	regionH := regionHTTP.New(srv.l, regionUC, cascadeUC)
+	branchH := branchHTTP.New(srv.l, branchUC, cascadeUC)
 	departmentH := departmentHTTP.New(srv.l, departmentUC)
 	userH := userHTTP.New(srv.l, userUC)
+	exportH := exportHTTP.New(srv.l, exportUC)
+	webhookH := webhookHTTP.New(srv.l, webhookUC)
+	streamH := streamHTTP.New(srv.l, streamUC)
 
 	// Routes
 	api := srv.gin.Group("/api/v1")
+	spec := newSpec()
 
-	// Public routes (không cần token)
-	authHTTP.MapRoutes(api.Group("/auth"), authH)
+	// Public routes (không cần token), giới hạn theo IP để chống dò mật khẩu.
+	// Idempotency-Key của request không có token thuộc về IP của client
+	publicAuth := api.Group("/auth")
+	publicAuth.Use(authMiddleware.RateLimit("auth", srv.rateLimits.Auth))
+	publicAuth.Use(authMiddleware.Idempotency())
+	authHTTP.MapRoutes(publicAuth, authH)
+	spec.Add(publicAuth.BasePath(), "auth", rateLimited(idempotent(authHTTP.Spec()))...)
 
 	// Protected routes với authentication
 	protected := api.Group("")
 	protected.Use(authMiddleware.Auth())
 	protected.Use(authMiddleware.SetScopeFromPayload()) // Set scope từ JWT
+	protected.Use(authMiddleware.Idempotency())         // POST có Idempotency-Key chỉ được xử lý một lần
+
+	// Auth routes cần token (me, đổi mật khẩu)
+	protectedAuth := protected.Group("/auth")
+	protectedAuth.Use(authMiddleware.RateLimit("auth", srv.rateLimits.Auth))
+	authHTTP.MapProtectedRoutes(protectedAuth, authH)
+	spec.Add(protectedAuth.BasePath(), "auth", rateLimited(idempotent(authHTTP.ProtectedSpec()))...)
 
 	// Shop routes - Chỉ Manager
 	shops := protected.Group("/shops")
 	shops.Use(authMiddleware.CheckShopAccess())
 	shopHTTP.MapRoutes(shops, shopH)
+	spec.Add(shops.BasePath(), "shops", idempotent(shopHTTP.Spec())...)
 
 	// Region routes - Manager hoặc RegionManager
 	regions := protected.Group("/regions")
 	regions.Use(authMiddleware.CheckRegionAccess())
 	regionHTTP.MapRoutes(regions, regionH)
+	spec.Add(regions.BasePath(), "regions", idempotent(regionHTTP.Spec())...)
 
 	// Branch routes - Manager, RegionManager, BranchManager
 	branches := protected.Group("/branches")
 	branches.Use(authMiddleware.CheckBranchAccess())
 	branchHTTP.MapRoutes(branches, branchH)
+	spec.Add(branches.BasePath(), "branches", idempotent(branchHTTP.Spec())...)
 
 	// Department routes - Manager, RegionManager, BranchManager, HeadOfDepartment
 	departments := protected.Group("/departments")
 	departments.Use(authMiddleware.CheckDepartmentAccess())
 	departmentHTTP.MapRoutes(departments, departmentH)
+	spec.Add(departments.BasePath(), "departments", idempotent(departmentHTTP.Spec())...)
 
 	// User routes - Tất cả roles (Employee chỉ GET)
 	users := protected.Group("/users")
+	users.Use(authMiddleware.RateLimit("users", srv.rateLimits.Users))
 	users.Use(authMiddleware.CheckUserAccess())
 	userHTTP.MapRoutes(users, userH)
+	spec.Add(users.BasePath(), "users", rateLimited(idempotent(userHTTP.Spec()))...)
+
+	// Export routes - Tất cả roles, dữ liệu được giới hạn theo scope
+	exports := protected.Group("/export")
+	exports.Use(authMiddleware.RateLimit("exports", srv.rateLimits.Exports))
+	exportHTTP.MapRoutes(exports, exportH)
+	spec.Add(exports.BasePath(), "export", rateLimited(idempotent(exportHTTP.Spec()))...)
+
+	// Webhook routes - Chỉ Manager, webhook thuộc shop của user
+	webhooks := protected.Group("/webhooks")
+	webhooks.Use(authMiddleware.RequireRole(models.RoleManager))
+	webhookHTTP.MapRoutes(webhooks, webhookH)
+	spec.Add(webhooks.BasePath(), "webhooks", idempotent(webhookHTTP.Spec())...)
+
+	// SSE stream - Tất cả roles, mỗi user chỉ nhận thay đổi của dữ liệu họ được xem qua REST
+	events := protected.Group("/events")
+	streamHTTP.MapRoutes(events, streamH)
+	spec.Add(events.BasePath(), "events", idempotent(streamHTTP.Spec())...)
+
+	// OpenAPI spec và trang docs - Public, sinh từ các route ở trên
+	mapDocs(api, spec)
+
+	// gRPC services cho các service nội bộ, cùng JWT và scope với REST
+	grpcSrv := grpcserver.New(srv.l, jwtManager, authUC)
+	orgapi.RegisterShopServiceServer(grpcSrv, shopGRPC.New(srv.l, shopUC))
+	orgapi.RegisterRegionServiceServer(grpcSrv, regionGRPC.New(srv.l, regionUC))
+	orgapi.RegisterBranchServiceServer(grpcSrv, branchGRPC.New(srv.l, branchUC))
+	orgapi.RegisterDepartmentServiceServer(grpcSrv, departmentGRPC.New(srv.l, departmentUC))
+	orgapi.RegisterUserServiceServer(grpcSrv, userGRPC.New(srv.l, userUC))
+
+	return grpcSrv
 }
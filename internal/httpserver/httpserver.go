@@ -3,21 +3,45 @@ package httpserver
 import (
 	"context"
 	"fmt"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
 )
 
-// Run starts the HTTP server.
+// Run starts the HTTP server and the gRPC server.
 func (srv HTTPServer) Run() {
-	srv.mapHandlers()
+	grpcSrv := srv.mapHandlers()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Dispatcher gửi event từ outbox, dừng khi server tắt
+	go srv.newDispatcher().Run(ctx)
+	// Deliverer gửi event tới webhook của shop, gửi lại với backoff khi lỗi
+	go srv.newDeliverer().Run(ctx)
 
-	ctx := context.Background()
 	go func() {
 		srv.gin.Run(fmt.Sprintf(":%d", srv.port))
 	}()
 
 	srv.l.Infof(ctx, "Started server on :%d", srv.port)
+
+	// gRPC server chạy trên port riêng, dừng cùng HTTP server
+	if srv.grpcPort != 0 {
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", srv.grpcPort))
+		if err != nil {
+			srv.l.Fatalf(ctx, "Failed to listen on gRPC port :%d: %v", srv.grpcPort, err)
+		}
+		go func() {
+			if err := grpcSrv.Serve(lis); err != nil {
+				srv.l.Errorf(ctx, "gRPC server stopped: %v", err)
+			}
+		}()
+		defer grpcSrv.GracefulStop()
+		srv.l.Infof(ctx, "Started gRPC server on :%d", srv.grpcPort)
+	}
+
 	ch := make(chan os.Signal, 1)
 	signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
 	srv.l.Info(ctx, <-ch)
@@ -1,10 +1,13 @@
 package httpserver
 
 import (
+	"context"
 	"time"
 
+	pkgCrt "thuchanhgolang/pkg/encrypter"
 	pkgLog "thuchanhgolang/pkg/log"
 	"thuchanhgolang/pkg/mongo"
+	"thuchanhgolang/pkg/ratelimit"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,31 +16,60 @@ type HTTPServer struct {
 	gin            *gin.Engine
 	l              pkgLog.Logger
 	port           int
+	grpcPort       int
 	database       mongo.Database
 	jwtSecretKey   string
 	accessDuration time.Duration
-	// encrypter    pkgCrt.Encrypter
+
+	impersonationDuration time.Duration
+	rateLimitStore        ratelimit.Store
+	rateLimits            RateLimits
+	encrypter             pkgCrt.Encrypter
 	// secretConfig SecretConfig
 }
 
 type Config struct {
 	Port           int
+	GRPCPort       int      // 0 → không mở gRPC server
+	TrustedProxies []string // Proxy được tin X-Forwarded-For, rỗng → chỉ dùng IP kết nối
 	Database       mongo.Database
 	JWTSecretKey   string
 	AccessDuration time.Duration
-	// Encrypter    pkgCrt.Encrypter
+
+	ImpersonationDuration time.Duration
+	RateLimitStore        ratelimit.Store // nil thì dùng store trong bộ nhớ
+	RateLimits            RateLimits
+	Encrypter             pkgCrt.Encrypter // Mã hóa dữ liệu bí mật trước khi lưu
 	// SecretConfig SecretConfig
 }
 
+// RateLimits là giới hạn request của từng nhóm route, Limit rỗng là không giới hạn
+type RateLimits struct {
+	Auth    ratelimit.Limit
+	Users   ratelimit.Limit
+	Exports ratelimit.Limit
+}
+
 func New(l pkgLog.Logger, cfg Config) *HTTPServer {
+	engine := gin.Default()
+	// ClientIP dùng cho rate limit theo IP, không tin X-Forwarded-For từ nguồn ngoài danh sách proxy
+	if err := engine.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		l.Fatalf(context.Background(), "httpserver.New.SetTrustedProxies: %v", err)
+	}
+
 	return &HTTPServer{
 		l:              l,
-		gin:            gin.Default(),
+		gin:            engine,
 		port:           cfg.Port,
+		grpcPort:       cfg.GRPCPort,
 		database:       cfg.Database,
 		jwtSecretKey:   cfg.JWTSecretKey,
 		accessDuration: cfg.AccessDuration,
-		// encrypter:    cfg.Encrypter,
+
+		impersonationDuration: cfg.ImpersonationDuration,
+		rateLimitStore:        cfg.RateLimitStore,
+		rateLimits:            cfg.RateLimits,
+		encrypter:             cfg.Encrypter,
 		// secretConfig: cfg.SecretConfig,
 	}
 }
@@ -0,0 +1,56 @@
+package httpserver
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"thuchanhgolang/internal/middleware"
+	"thuchanhgolang/pkg/openapi"
+	"thuchanhgolang/pkg/response"
+)
+
+const (
+	specPath = "/openapi.json"
+	docsPath = "/docs"
+)
+
+// newSpec tạo builder cho OpenAPI spec, response thành công được bọc trong response.Resp
+func newSpec() *openapi.Builder {
+	return openapi.NewBuilder(openapi.Info{
+		Title:       "thuchanhgolang API",
+		Description: "Quản lý cây tổ chức shop / region / branch / department và user",
+		Version:     "1.0.0",
+	}, response.Resp{})
+}
+
+// mapDocs map route trả về OpenAPI spec và trang docs đọc spec đó
+func mapDocs(api *gin.RouterGroup, spec *openapi.Builder) {
+	spec.Add(api.BasePath(), "docs",
+		openapi.Route{Method: http.MethodGet, Path: specPath, Summary: "OpenAPI spec của API", Public: true, Raw: true},
+		openapi.Route{Method: http.MethodGet, Path: docsPath, Summary: "Trang docs của API", Public: true, ResponseTypes: []string{openapi.ContentHTML}},
+	)
+
+	api.GET(specPath, openapi.JSONHandler(spec.Document()))
+	api.GET(docsPath, openapi.DocsHandler(api.BasePath()+specPath))
+}
+
+// idempotent thêm header Idempotency-Key vào các route POST cần token, nơi middleware Idempotency được gắn
+func idempotent(routes []openapi.Route) []openapi.Route {
+	for i, r := range routes {
+		if r.Method == http.MethodPost {
+			r.Params = append(append([]openapi.Parameter{}, r.Params...),
+				openapi.HeaderParam(middleware.IdempotencyKeyHeader, "Key duy nhất của request, gửi lại cùng key thì nhận lại response của lần đầu"))
+			routes[i] = r
+		}
+	}
+	return routes
+}
+
+// rateLimited đánh dấu các route có middleware RateLimit, có thể trả về 429 kèm Retry-After
+func rateLimited(routes []openapi.Route) []openapi.Route {
+	for i := range routes {
+		routes[i].RateLimited = true
+	}
+	return routes
+}
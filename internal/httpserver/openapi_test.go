@@ -0,0 +1,195 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"thuchanhgolang/pkg/mongo"
+	"thuchanhgolang/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockLogger struct{}
+
+func (m *mockLogger) Debug(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Debugf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Info(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Infof(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Warn(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Warnf(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Error(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Errorf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Fatal(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Fatalf(ctx context.Context, template string, arg ...any) {}
+
+// mockDatabase chỉ đủ để dựng repository, test không gọi tới mongo
+type mockDatabase struct {
+	mongo.Database
+}
+
+func (m mockDatabase) Collection(string) mongo.Collection { return nil }
+func (m mockDatabase) Client() mongo.Client               { return nil }
+
+// newTestServer dựng server với đầy đủ route như khi chạy thật
+func newTestServer() HTTPServer {
+	gin.SetMode(gin.TestMode)
+	srv := HTTPServer{gin: gin.New(), l: &mockLogger{}, database: mockDatabase{}}
+	srv.mapHandlers()
+	return srv
+}
+
+// getSpec lấy OpenAPI spec qua HTTP như client
+func getSpec(t *testing.T, srv HTTPServer) openapi.Document {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	srv.gin.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1"+specPath, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET %s trả về %d", specPath, w.Code)
+	}
+
+	var doc openapi.Document
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Spec không phải JSON hợp lệ: %v", err)
+	}
+	return doc
+}
+
+// ginPathToOpenAPI đổi /:id thành /{id}
+func ginPathToOpenAPI(path string) string {
+	parts := strings.Split(path, "/")
+	for i, p := range parts {
+		if strings.HasPrefix(p, ":") {
+			parts[i] = "{" + p[1:] + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+func TestOpenAPISpecMatchesRoutes(t *testing.T) {
+	srv := newTestServer()
+	doc := getSpec(t, srv)
+
+	if doc.OpenAPI != openapi.Version {
+		t.Errorf("Mong đợi openapi %s, nhận được %s", openapi.Version, doc.OpenAPI)
+	}
+
+	registered := map[string]bool{}
+	for _, r := range srv.gin.Routes() {
+		registered[r.Method+" "+ginPathToOpenAPI(r.Path)] = true
+	}
+	documented := map[string]bool{}
+	for path, item := range doc.Paths {
+		for method := range item {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	var missing, stale []string
+	for k := range registered {
+		if !documented[k] {
+			missing = append(missing, k)
+		}
+	}
+	for k := range documented {
+		if !registered[k] {
+			stale = append(stale, k)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(stale)
+
+	if len(missing) > 0 {
+		t.Errorf("Route chưa có trong spec (thêm vào Spec() của delivery): %v", missing)
+	}
+	if len(stale) > 0 {
+		t.Errorf("Spec có route không tồn tại: %v", stale)
+	}
+}
+
+func TestOpenAPISpecOperations(t *testing.T) {
+	doc := getSpec(t, newTestServer())
+
+	ids := map[string]string{}
+	for path, item := range doc.Paths {
+		for method, op := range item {
+			name := strings.ToUpper(method) + " " + path
+
+			if prev, ok := ids[op.OperationID]; ok {
+				t.Errorf("operationId %s trùng giữa %s và %s", op.OperationID, prev, name)
+			}
+			ids[op.OperationID] = name
+
+			if _, ok := op.Responses["200"]; !ok {
+				t.Errorf("%s thiếu response 200", name)
+			}
+
+			// Mỗi path param phải được khai báo
+			declared := map[string]bool{}
+			for _, p := range op.Parameters {
+				if p.In == "path" {
+					declared[p.Name] = true
+				}
+			}
+			for _, seg := range strings.Split(path, "/") {
+				if strings.HasPrefix(seg, "{") && !declared[strings.Trim(seg, "{}")] {
+					t.Errorf("%s thiếu path param %s", name, seg)
+				}
+			}
+		}
+	}
+}
+
+func TestOpenAPISpecRefsResolve(t *testing.T) {
+	srv := newTestServer()
+
+	w := httptest.NewRecorder()
+	srv.gin.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1"+specPath, nil))
+
+	var raw map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("Spec không phải JSON hợp lệ: %v", err)
+	}
+	schemas, _ := raw["components"].(map[string]any)["schemas"].(map[string]any)
+
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if ref, ok := v["$ref"].(string); ok {
+				name := strings.TrimPrefix(ref, "#/components/schemas/")
+				if _, ok := schemas[name]; !ok {
+					t.Errorf("$ref %s không có trong components", ref)
+				}
+			}
+			for _, child := range v {
+				walk(child)
+			}
+		case []any:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(raw)
+}
+
+func TestDocsPage(t *testing.T) {
+	srv := newTestServer()
+
+	w := httptest.NewRecorder()
+	srv.gin.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1"+docsPath, nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET %s trả về %d", docsPath, w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "/api/v1"+specPath) {
+		t.Errorf("Trang docs không trỏ tới spec")
+	}
+}
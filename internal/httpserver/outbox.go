@@ -0,0 +1,30 @@
+package httpserver
+
+import (
+	"thuchanhgolang/internal/outbox"
+	outboxMongo "thuchanhgolang/internal/outbox/repository/mongo"
+	outboxUsecase "thuchanhgolang/internal/outbox/usecase"
+	streamMongo "thuchanhgolang/internal/stream/repository/mongo"
+	streamUsecase "thuchanhgolang/internal/stream/usecase"
+	"thuchanhgolang/internal/webhook"
+	webhookMongo "thuchanhgolang/internal/webhook/repository/mongo"
+	webhookUsecase "thuchanhgolang/internal/webhook/usecase"
+	"thuchanhgolang/pkg/event"
+)
+
+// newDispatcher tạo dispatcher gửi event từ outbox ra log, tới hàng chờ webhook của shop và change log của SSE stream
+func (srv HTTPServer) newDispatcher() outbox.Dispatcher {
+	repo := outboxMongo.NewRepository(srv.l, srv.database)
+	publisher := event.NewMultiPublisher(
+		event.NewLogPublisher(srv.l),
+		webhookUsecase.NewPublisher(srv.l, webhookMongo.NewRepository(srv.l, srv.database)),
+		streamUsecase.NewRecorder(srv.l, streamMongo.NewRepository(srv.l, srv.database)),
+	)
+	return outboxUsecase.NewDispatcher(srv.l, repo, publisher, outbox.DispatcherOptions{})
+}
+
+// newDeliverer tạo deliverer gửi delivery đang chờ tới URL của webhook
+func (srv HTTPServer) newDeliverer() webhook.Deliverer {
+	repo := webhookMongo.NewRepository(srv.l, srv.database)
+	return webhookUsecase.NewDeliverer(srv.l, repo, nil, webhook.DelivererOptions{})
+}
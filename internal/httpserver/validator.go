@@ -0,0 +1,26 @@
+package httpserver
+
+import (
+	"thuchanhgolang/internal/models"
+	pkgErrors "thuchanhgolang/pkg/errors"
+	"thuchanhgolang/pkg/patch"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// registerValidators gắn các validator riêng (objectid, role, shopcode, username, notblank) vào validator
+// mà gin dùng khi bind request, field merge patch được validate theo giá trị bên trong.
+// Chỉ lỗi khi tag rỗng nên panic như regexp.MustCompile
+func registerValidators() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	isRole := func(r string) bool { return models.Role(r).IsValid() }
+	if err := pkgErrors.RegisterValidators(v, isRole); err != nil {
+		panic(err)
+	}
+	v.RegisterCustomTypeFunc(patch.ValidationValue, patch.Field[string]{}, patch.Field[bool]{}, patch.Field[[]string]{})
+}
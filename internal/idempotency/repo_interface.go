@@ -0,0 +1,21 @@
+package idempotency
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/models"
+)
+
+// Repository lưu khóa và response của request POST có Idempotency-Key
+//
+//go:generate mockery --name=Repository
+type Repository interface {
+	// Acquire tạo bản ghi khóa cho key. acquired = true khi request này được xử lý,
+	// key đã có bản ghi thì trả về bản ghi đó với acquired = false.
+	// Khóa đã quá hạn mà chưa có response (request trước bị dừng giữa chừng) được chiếm lại.
+	Acquire(ctx context.Context, opts AcquireOptions) (record models.IdempotencyRecord, acquired bool, err error)
+	// Complete lưu response của request đang giữ khóa
+	Complete(ctx context.Context, opts CompleteOptions) error
+	// Release xóa khóa chưa có response, lần gửi lại với cùng key được xử lý như request mới
+	Release(ctx context.Context, id string) error
+}
@@ -0,0 +1,38 @@
+package idempotency
+
+import (
+	"time"
+
+	"thuchanhgolang/internal/models"
+)
+
+// Collection là collection lưu khóa và response theo Idempotency-Key
+const Collection = "idempotency_keys"
+
+// AcquireOptions là options để tạo khóa cho một request
+type AcquireOptions struct {
+	UserID      string
+	Key         string
+	Method      string
+	Path        string
+	RequestHash string
+	Now         time.Time
+	LockTimeout time.Duration // Thời gian giữ khóa tối đa, hết hạn thì request khác được chiếm
+}
+
+// ID trả về _id của bản ghi, key của mỗi người gọi là riêng biệt
+func (o AcquireOptions) ID() string {
+	return RecordID(o.UserID, o.Key)
+}
+
+// CompleteOptions là options để lưu response của request
+type CompleteOptions struct {
+	ID          string
+	Response    models.IdempotencyResponse
+	CompletedAt time.Time
+}
+
+// RecordID ghép UserID và key thành _id của bản ghi
+func RecordID(userID, key string) string {
+	return userID + ":" + key
+}
@@ -0,0 +1,106 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"thuchanhgolang/internal/idempotency"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/mongo"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// getIdempotencyCollection lấy collection idempotency_keys từ database
+func (repo implRepository) getIdempotencyCollection() mongo.Collection {
+	return repo.db.Collection(idempotency.Collection)
+}
+
+// Acquire tạo khóa bằng insert theo _id, hai request trùng key đến cùng lúc thì chỉ một insert thành công
+func (repo implRepository) Acquire(ctx context.Context, opts idempotency.AcquireOptions) (models.IdempotencyRecord, bool, error) {
+	col := repo.getIdempotencyCollection()
+
+	record := models.IdempotencyRecord{
+		ID:          opts.ID(),
+		UserID:      opts.UserID,
+		Key:         opts.Key,
+		Method:      opts.Method,
+		Path:        opts.Path,
+		RequestHash: opts.RequestHash,
+		LockedUntil: opts.Now.Add(opts.LockTimeout),
+		CreatedAt:   opts.Now,
+	}
+
+	// Bước 1: Key chưa được dùng → request này giữ khóa
+	_, err := col.InsertOne(ctx, record)
+	if err == nil {
+		return record, true, nil
+	}
+	if _, ok := mongo.AsDuplicateKey(err); !ok {
+		repo.l.Errorf(ctx, "idempotency.mongo.Acquire.InsertOne: %v", err)
+		return models.IdempotencyRecord{}, false, err
+	}
+
+	// Bước 2: Chiếm lại khóa quá hạn chưa có response, request trước đã bị dừng giữa chừng
+	filter := bson.M{
+		"_id":          record.ID,
+		"completed_at": bson.M{"$exists": false},
+		"locked_until": bson.M{"$lte": opts.Now},
+	}
+	update := bson.M{"$set": bson.M{
+		"method":       record.Method,
+		"path":         record.Path,
+		"request_hash": record.RequestHash,
+		"locked_until": record.LockedUntil,
+		"created_at":   record.CreatedAt,
+	}}
+	var taken models.IdempotencyRecord
+	err = col.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&taken)
+	if err == nil {
+		return taken, true, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		repo.l.Errorf(ctx, "idempotency.mongo.Acquire.FindOneAndUpdate: %v", err)
+		return models.IdempotencyRecord{}, false, err
+	}
+
+	// Bước 3: Khóa đang được giữ hoặc đã có response → trả về cho middleware quyết định
+	var existing models.IdempotencyRecord
+	err = col.FindOne(ctx, bson.M{"_id": record.ID}).Decode(&existing)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// Bản ghi vừa bị xóa (Release hoặc TTL) giữa hai bước, thử lại từ đầu
+		return repo.Acquire(ctx, opts)
+	}
+	if err != nil {
+		repo.l.Errorf(ctx, "idempotency.mongo.Acquire.FindOne: %v", err)
+		return models.IdempotencyRecord{}, false, err
+	}
+
+	return existing, false, nil
+}
+
+// Complete lưu response và mở khóa, bản ghi được giữ tới khi TTL xóa.
+// Chỉ lưu response đầu tiên nếu khóa quá hạn đã bị request khác chiếm và xử lý xong trước
+func (repo implRepository) Complete(ctx context.Context, opts idempotency.CompleteOptions) error {
+	update := bson.M{"$set": bson.M{
+		"response":     opts.Response,
+		"completed_at": opts.CompletedAt,
+	}}
+	filter := bson.M{"_id": opts.ID, "completed_at": bson.M{"$exists": false}}
+	if _, err := repo.getIdempotencyCollection().UpdateOne(ctx, filter, update); err != nil {
+		repo.l.Errorf(ctx, "idempotency.mongo.Complete.UpdateOne: %v", err)
+		return err
+	}
+	return nil
+}
+
+// Release xóa khóa chưa có response
+func (repo implRepository) Release(ctx context.Context, id string) error {
+	filter := bson.M{"_id": id, "completed_at": bson.M{"$exists": false}}
+	if _, err := repo.getIdempotencyCollection().DeleteOne(ctx, filter); err != nil {
+		repo.l.Errorf(ctx, "idempotency.mongo.Release.DeleteOne: %v", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package mongo
+
+import (
+	"thuchanhgolang/internal/idempotency"
+	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/mongo"
+)
+
+// implRepository là implementation của idempotency.Repository
+type implRepository struct {
+	l  log.Logger     // Logger để ghi log
+	db mongo.Database // Database connection
+}
+
+// NewRepository tạo một idempotency repository mới
+func NewRepository(l log.Logger, db mongo.Database) idempotency.Repository {
+	return &implRepository{
+		l:  l,
+		db: db,
+	}
+}
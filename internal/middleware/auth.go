@@ -1,8 +1,11 @@
 package middleware
 
 import (
+	"errors"
+	"net/http"
 	"strings"
 
+	"thuchanhgolang/internal/auth"
 	"thuchanhgolang/pkg/jwt"
 	"thuchanhgolang/pkg/response"
 
@@ -26,6 +29,32 @@ func (mw *implMiddleware) Auth() gin.HandlerFunc {
 		}
 
 		ctx := c.Request.Context()
+
+		// Token impersonation chỉ dùng được khi phiên chưa bị admin kết thúc,
+		// và chỉ được đọc trừ khi admin cho phép ghi khi tạo token
+		if payload.IsImpersonated() {
+			if err := mw.impersonations.CheckImpersonation(ctx, payload.Actor.ImpersonationID); err != nil {
+				if !errors.Is(err, auth.ErrImpersonationEnded) {
+					mw.l.Errorf(ctx, "middleware.Auth.CheckImpersonation: %v", err)
+					response.Error(c, err)
+					c.Abort()
+					return
+				}
+				mw.l.Warnf(ctx, "middleware.Auth: impersonation %s has ended", payload.Actor.ImpersonationID)
+				response.Unauthorized(c)
+				c.Abort()
+				return
+			}
+			if !payload.Actor.AllowWrite && !isReadOnlyMethod(c.Request.Method) {
+				mw.l.Warnf(ctx, "middleware.Auth: impersonation %s is read-only", payload.Actor.ImpersonationID)
+				response.Forbidden(c)
+				c.Abort()
+				return
+			}
+			mw.l.Infof(ctx, "middleware.Auth: actor %s impersonating user %s: %s %s",
+				payload.Actor.UserID, payload.UserID, c.Request.Method, c.Request.URL.Path)
+		}
+
 		ctx = jwt.SetPayloadToContext(ctx, payload)
 		c.Request = c.Request.WithContext(ctx)
 
@@ -33,3 +62,12 @@ func (mw *implMiddleware) Auth() gin.HandlerFunc {
 	}
 
 }
+
+// isReadOnlyMethod kiểm tra HTTP method có phải chỉ đọc không
+func isReadOnlyMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"thuchanhgolang/internal/auth"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeImpersonations giả lập audit log impersonation, phiên có trong ended đã bị kết thúc
+type fakeImpersonations struct {
+	ended map[string]bool
+	err   error
+	calls int
+}
+
+func (f *fakeImpersonations) CheckImpersonation(ctx context.Context, id string) error {
+	f.calls++
+	if f.err != nil {
+		return f.err
+	}
+	if f.ended[id] {
+		return auth.ErrImpersonationEnded
+	}
+	return nil
+}
+
+// newAuthRouter dựng router đi qua Auth và SetScopeFromPayload như route thật,
+// handler trả lại scope mà usecase sẽ nhận
+func newAuthRouter(jwtMgr jwt.Manager, impersonations auth.ImpersonationChecker, scopes *[]models.Scope) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	mw := New(&mockLogger{}, jwtMgr, nil, nil, nil, impersonations)
+
+	handler := func(c *gin.Context) {
+		sc, _ := c.Get("scope")
+		*scopes = append(*scopes, sc.(models.Scope))
+		c.JSON(http.StatusOK, gin.H{})
+	}
+
+	r := gin.New()
+	r.Use(mw.Auth(), mw.SetScopeFromPayload())
+	r.GET("/users", handler)
+	r.POST("/users", handler)
+	return r
+}
+
+func requestWithToken(t *testing.T, r *gin.Engine, jwtMgr jwt.Manager, method string, payload jwt.Payload) *httptest.ResponseRecorder {
+	t.Helper()
+
+	token, err := jwtMgr.Generate(payload, time.Hour)
+	if err != nil {
+		t.Fatalf("Không tạo được token: %v", err)
+	}
+	req := httptest.NewRequest(method, "/users", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthImpersonation(t *testing.T) {
+	jwtMgr := jwt.NewManager("test-secret")
+	shopID, branchID := primitive.NewObjectID(), primitive.NewObjectID()
+	// Token impersonation mang payload của user được impersonate (employee), admin nằm trong claim act
+	target := jwt.Payload{
+		UserID:   "employee-1",
+		Role:     string(models.RoleEmployee),
+		ShopID:   shopID.Hex(),
+		BranchID: branchID.Hex(),
+		Actor:    &jwt.Actor{UserID: "manager-1", ImpersonationID: "imp1"},
+	}
+
+	t.Run("scope is narrowed to target user", func(t *testing.T) {
+		var scopes []models.Scope
+		r := newAuthRouter(jwtMgr, &fakeImpersonations{}, &scopes)
+
+		w := requestWithToken(t, r, jwtMgr, http.MethodGet, target)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Mong đợi 200, nhận được %d", w.Code)
+		}
+		sc := scopes[0]
+		if sc.UserID != "employee-1" || sc.Role != models.RoleEmployee || sc.BranchID == nil || *sc.BranchID != branchID {
+			t.Errorf("Mong đợi scope của employee, nhận được %+v", sc)
+		}
+		if sc.ActorID != "manager-1" || sc.ImpersonationID != "imp1" {
+			t.Errorf("Mong đợi scope giữ actor và phiên impersonation, nhận được %+v", sc)
+		}
+	})
+
+	t.Run("read-only token cannot write", func(t *testing.T) {
+		var scopes []models.Scope
+		r := newAuthRouter(jwtMgr, &fakeImpersonations{}, &scopes)
+
+		w := requestWithToken(t, r, jwtMgr, http.MethodPost, target)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Mong đợi 403, nhận được %d", w.Code)
+		}
+		if len(scopes) != 0 {
+			t.Errorf("Handler không được chạy")
+		}
+	})
+
+	t.Run("write token can write", func(t *testing.T) {
+		var scopes []models.Scope
+		r := newAuthRouter(jwtMgr, &fakeImpersonations{}, &scopes)
+		writable := target
+		writable.Actor = &jwt.Actor{UserID: "manager-1", ImpersonationID: "imp1", AllowWrite: true}
+
+		w := requestWithToken(t, r, jwtMgr, http.MethodPost, writable)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Mong đợi 200, nhận được %d", w.Code)
+		}
+	})
+
+	t.Run("ended session is rejected", func(t *testing.T) {
+		var scopes []models.Scope
+		r := newAuthRouter(jwtMgr, &fakeImpersonations{ended: map[string]bool{"imp1": true}}, &scopes)
+
+		w := requestWithToken(t, r, jwtMgr, http.MethodGet, target)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Mong đợi 401, nhận được %d", w.Code)
+		}
+		if len(scopes) != 0 {
+			t.Errorf("Handler không được chạy")
+		}
+	})
+
+	t.Run("session lookup error is not treated as valid", func(t *testing.T) {
+		var scopes []models.Scope
+		r := newAuthRouter(jwtMgr, &fakeImpersonations{err: errors.New("db down")}, &scopes)
+
+		w := requestWithToken(t, r, jwtMgr, http.MethodGet, target)
+
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("Mong đợi 500, nhận được %d", w.Code)
+		}
+		if len(scopes) != 0 {
+			t.Errorf("Handler không được chạy")
+		}
+	})
+
+	t.Run("regular token does not look up sessions", func(t *testing.T) {
+		var scopes []models.Scope
+		impersonations := &fakeImpersonations{}
+		r := newAuthRouter(jwtMgr, impersonations, &scopes)
+		regular := target
+		regular.Actor = nil
+
+		w := requestWithToken(t, r, jwtMgr, http.MethodPost, regular)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Mong đợi 200, nhận được %d", w.Code)
+		}
+		if impersonations.calls != 0 {
+			t.Errorf("Token thường không cần đối chiếu audit log")
+		}
+	})
+}
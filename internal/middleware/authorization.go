@@ -6,7 +6,6 @@ import (
 	"thuchanhgolang/pkg/response"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // RequireRole middleware kiểm tra user có role yêu cầu không
@@ -199,27 +198,8 @@ func (mw *implMiddleware) SetScopeFromPayload() gin.HandlerFunc {
 			return
 		}
 
-		scope := models.Scope{
-			UserID: payload.UserID,
-			Role:   models.Role(payload.Role),
-		}
-
-		// Parse IDs từ payload
-		if payload.ShopID != "" {
-			if shopID, err := primitive.ObjectIDFromHex(payload.ShopID); err == nil {
-				scope.ShopID = &shopID
-			}
-		}
-		if payload.RegionID != "" {
-			if regionID, err := primitive.ObjectIDFromHex(payload.RegionID); err == nil {
-				scope.RegionID = &regionID
-			}
-		}
-		if payload.BranchID != "" {
-			if branchID, err := primitive.ObjectIDFromHex(payload.BranchID); err == nil {
-				scope.BranchID = &branchID
-			}
-		}
+		// Parse role và các IDs (shop/region/branch/department) từ payload
+		scope := jwt.NewScope(payload)
 
 		c.Set("scope", scope)
 		c.Next()
@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"net/http"
+
+	pkgErrors "thuchanhgolang/pkg/errors"
+)
+
+var (
+	errInvalidIdempotencyKey    = pkgErrors.NewHTTPErrorWithStatus(80001, "Idempotency-Key header must have 1-255 characters", http.StatusBadRequest)
+	errIdempotencyKeyReused     = pkgErrors.NewHTTPErrorWithStatus(80002, "Idempotency-Key has already been used with a different request", http.StatusUnprocessableEntity)
+	errIdempotencyKeyInProgress = pkgErrors.NewHTTPErrorWithStatus(80003, "A request with this Idempotency-Key is still being processed, retry later", http.StatusConflict)
+	errIdempotentBodyTooLarge   = pkgErrors.NewHTTPErrorWithStatus(80004, "Request body is too large to be used with Idempotency-Key", http.StatusRequestEntityTooLarge)
+
+	errTooManyRequests = pkgErrors.NewHTTPErrorWithStatus(90001, "Too many requests, retry later", http.StatusTooManyRequests)
+)
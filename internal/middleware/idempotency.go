@@ -0,0 +1,206 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"thuchanhgolang/internal/idempotency"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/jwt"
+	"thuchanhgolang/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader là header client gửi để request POST được xử lý đúng một lần
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader được gắn vào response trả lại từ lần xử lý trước
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// maxIdempotentBodyBytes giới hạn body được đọc vào bộ nhớ để tính hash, lớn hơn file import tối đa
+	maxIdempotentBodyBytes = 8 << 20
+	// idempotencyLockTimeout là thời gian giữ khóa tối đa, request bị dừng giữa chừng không giữ key mãi
+	idempotencyLockTimeout = time.Minute
+)
+
+// replayedHeaders là các header của response được lưu lại và trả về khi gửi lại
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// noStoreDirective trong Cache-Control đánh dấu response chứa thông tin bí mật (token, mật khẩu tạm).
+// Body của response này không được lưu, gửi lại chỉ nhận lại status và header.
+const noStoreDirective = "no-store"
+
+// Idempotency middleware xử lý mỗi request POST có header Idempotency-Key đúng một lần cho mỗi người gọi.
+// Gửi lại cùng key và cùng nội dung thì nhận lại response đã lưu, cùng key khác nội dung thì bị từ chối (422),
+// gửi lại lúc request đầu còn đang xử lý thì bị từ chối (409). Trên route cần token thì phải đứng sau Auth,
+// route public (đăng ký) không có token thì người gọi là IP của client.
+// Response có Cache-Control: no-store chỉ được lưu status và header, không lưu body.
+func (mw *implMiddleware) Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		if len(key) > maxIdempotencyKeyLength {
+			response.Error(c, errInvalidIdempotencyKey)
+			c.Abort()
+			return
+		}
+
+		// Bước 1: Đọc body để tính hash, trả lại body cho handler
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxIdempotentBodyBytes+1))
+		if err != nil {
+			mw.l.Warnf(ctx, "middleware.Idempotency.ReadAll: %v", err)
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+		if len(body) > maxIdempotentBodyBytes {
+			response.Error(c, errIdempotentBodyTooLarge)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		hash := requestHash(c.Request, body)
+
+		// Bước 2: Giữ khóa cho key, key đã có bản ghi thì trả lại response cũ hoặc từ chối
+		record, acquired, err := mw.idempotencyRepo.Acquire(ctx, idempotency.AcquireOptions{
+			UserID:      idempotencyCaller(c),
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			RequestHash: hash,
+			Now:         time.Now(),
+			LockTimeout: idempotencyLockTimeout,
+		})
+		if err != nil {
+			mw.l.Errorf(ctx, "middleware.Idempotency.Acquire: %v", err)
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+		if !acquired {
+			mw.replayIdempotent(c, record, hash)
+			c.Abort()
+			return
+		}
+
+		// Bước 3: Xử lý request, ghi lại response để lưu
+		w := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+
+		// Bước 4: Lưu response, lỗi phía server thì bỏ khóa để client gửi lại được.
+		// Client ngắt kết nối thì ctx bị hủy nhưng response vẫn phải được lưu
+		storeCtx := context.WithoutCancel(ctx)
+		if w.Status() >= http.StatusInternalServerError {
+			if err := mw.idempotencyRepo.Release(storeCtx, record.ID); err != nil {
+				mw.l.Errorf(ctx, "middleware.Idempotency.Release: %v", err)
+			}
+			return
+		}
+		err = mw.idempotencyRepo.Complete(storeCtx, idempotency.CompleteOptions{
+			ID:          record.ID,
+			Response:    w.response(),
+			CompletedAt: time.Now(),
+		})
+		if err != nil {
+			mw.l.Errorf(ctx, "middleware.Idempotency.Complete: %v", err)
+		}
+	}
+}
+
+// idempotencyCaller trả về người gọi sở hữu key: user đã đăng nhập, không có token thì là IP.
+// IP có tiền tố "ip:" nên không trùng với UserID, client sau cùng NAT dùng chung không gian key.
+func idempotencyCaller(c *gin.Context) string {
+	if payload, ok := jwt.GetPayloadFromContext(c.Request.Context()); ok && payload.UserID != "" {
+		return payload.UserID
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// replayIdempotent trả lời request gửi lại với key đã có bản ghi
+func (mw *implMiddleware) replayIdempotent(c *gin.Context, record models.IdempotencyRecord, hash string) {
+	ctx := c.Request.Context()
+
+	if record.RequestHash != hash {
+		mw.l.Warnf(ctx, "middleware.Idempotency: key %s reused with a different request", record.Key)
+		response.Error(c, errIdempotencyKeyReused)
+		return
+	}
+	if !record.IsCompleted() {
+		mw.l.Warnf(ctx, "middleware.Idempotency: key %s is still being processed", record.Key)
+		response.Error(c, errIdempotencyKeyInProgress)
+		return
+	}
+
+	for name, value := range record.Response.Header {
+		c.Header(name, value)
+	}
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Status(record.Response.Status)
+	if _, err := c.Writer.Write(record.Response.Body); err != nil {
+		mw.l.Warnf(ctx, "middleware.Idempotency.Write: %v", err)
+	}
+}
+
+// requestHash là SHA-256 của method, path và body, dùng để phát hiện key bị dùng lại cho request khác
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordingWriter ghi lại body của response trong khi vẫn gửi cho client
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write ghi body cho client và giữ lại một bản
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString ghi body cho client và giữ lại một bản
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// response trả về response đã ghi lại, chỉ giữ các header cần trả lại.
+// Response no-store không giữ body (và Content-Type của body đó) để bí mật không nằm trong database.
+func (w *recordingWriter) response() models.IdempotencyResponse {
+	noStore := strings.Contains(w.Header().Get("Cache-Control"), noStoreDirective)
+
+	header := map[string]string{}
+	for _, name := range replayedHeaders {
+		if noStore && name == "Content-Type" {
+			continue
+		}
+		if v := w.Header().Get(name); v != "" {
+			header[name] = v
+		}
+	}
+	resp := models.IdempotencyResponse{
+		Status: w.Status(),
+		Header: header,
+	}
+	if !noStore {
+		resp.Body = w.body.Bytes()
+	}
+	return resp
+}
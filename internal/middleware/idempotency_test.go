@@ -0,0 +1,318 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"thuchanhgolang/internal/idempotency"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockLogger struct{}
+
+func (m *mockLogger) Debug(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Debugf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Info(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Infof(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Warn(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Warnf(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Error(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Errorf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Fatal(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Fatalf(ctx context.Context, template string, arg ...any) {}
+
+// memoryIdempotencyRepo giữ bản ghi trong bộ nhớ, insert trùng _id bị từ chối như unique index của mongo
+type memoryIdempotencyRepo struct {
+	mu      sync.Mutex
+	records map[string]models.IdempotencyRecord
+}
+
+func newMemoryIdempotencyRepo() *memoryIdempotencyRepo {
+	return &memoryIdempotencyRepo{records: map[string]models.IdempotencyRecord{}}
+}
+
+func (r *memoryIdempotencyRepo) Acquire(ctx context.Context, opts idempotency.AcquireOptions) (models.IdempotencyRecord, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.records[opts.ID()]; ok {
+		return existing, false, nil
+	}
+	record := models.IdempotencyRecord{
+		ID:          opts.ID(),
+		UserID:      opts.UserID,
+		Key:         opts.Key,
+		RequestHash: opts.RequestHash,
+		LockedUntil: opts.Now.Add(opts.LockTimeout),
+		CreatedAt:   opts.Now,
+	}
+	r.records[record.ID] = record
+	return record, true, nil
+}
+
+func (r *memoryIdempotencyRepo) Complete(ctx context.Context, opts idempotency.CompleteOptions) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record := r.records[opts.ID]
+	resp := opts.Response
+	record.Response = &resp
+	record.CompletedAt = &opts.CompletedAt
+	r.records[opts.ID] = record
+	return nil
+}
+
+func (r *memoryIdempotencyRepo) Release(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.records, id)
+	return nil
+}
+
+// newIdempotencyRouter dựng router với user đã đăng nhập, handler đếm số lần được gọi
+func newIdempotencyRouter(repo idempotency.Repository, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	mw := New(&mockLogger{}, nil, nil, repo, nil, nil)
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		ctx := jwt.SetPayloadToContext(c.Request.Context(), jwt.Payload{UserID: c.GetHeader("X-User")})
+		c.Request = c.Request.WithContext(ctx)
+	})
+	r.Use(mw.Idempotency())
+	r.POST("/departments", handler)
+	return r
+}
+
+func postWithKey(r *gin.Engine, user, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/departments", strings.NewReader(body))
+	req.Header.Set("X-User", user)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency(t *testing.T) {
+	t.Run("retry returns stored response", func(t *testing.T) {
+		var calls int32
+		r := newIdempotencyRouter(newMemoryIdempotencyRepo(), func(c *gin.Context) {
+			n := atomic.AddInt32(&calls, 1)
+			c.Header("ETag", `"1"`)
+			c.JSON(http.StatusOK, gin.H{"call": n})
+		})
+
+		first := postWithKey(r, "u1", "k1", `{"name":"A"}`)
+		retry := postWithKey(r, "u1", "k1", `{"name":"A"}`)
+
+		if calls != 1 {
+			t.Fatalf("Mong đợi handler được gọi 1 lần, nhận được %d", calls)
+		}
+		if retry.Code != first.Code || retry.Body.String() != first.Body.String() {
+			t.Errorf("Response gửi lại không khớp: %d %s, lần đầu %d %s", retry.Code, retry.Body, first.Code, first.Body)
+		}
+		if retry.Header().Get("ETag") != `"1"` {
+			t.Errorf("Mong đợi ETag được trả lại")
+		}
+		if retry.Header().Get(IdempotentReplayedHeader) != "true" {
+			t.Errorf("Mong đợi header %s", IdempotentReplayedHeader)
+		}
+	})
+
+	t.Run("no-store response body is not persisted", func(t *testing.T) {
+		var calls int32
+		repo := newMemoryIdempotencyRepo()
+		r := newIdempotencyRouter(repo, func(c *gin.Context) {
+			atomic.AddInt32(&calls, 1)
+			c.Header("Cache-Control", "no-store")
+			c.Header("Location", "/impersonations/1")
+			c.JSON(http.StatusOK, gin.H{"access_token": "secret-token"})
+		})
+
+		first := postWithKey(r, "u1", "k1", `{"user_id":"x"}`)
+		retry := postWithKey(r, "u1", "k1", `{"user_id":"x"}`)
+
+		if calls != 1 {
+			t.Fatalf("Mong đợi handler được gọi 1 lần, nhận được %d", calls)
+		}
+		if !strings.Contains(first.Body.String(), "secret-token") {
+			t.Fatalf("Lần đầu phải nhận được token, nhận được %s", first.Body)
+		}
+		for _, record := range repo.records {
+			if record.Response == nil || len(record.Response.Body) != 0 || record.Response.Header["Content-Type"] != "" {
+				t.Errorf("Không được lưu body chứa bí mật, nhận được %+v", record.Response)
+			}
+		}
+		if retry.Code != http.StatusOK || retry.Body.Len() != 0 || retry.Header().Get("Location") != "/impersonations/1" {
+			t.Errorf("Gửi lại phải nhận status và header, không có body: %d %q", retry.Code, retry.Body)
+		}
+	})
+
+	t.Run("same key with different body is rejected", func(t *testing.T) {
+		r := newIdempotencyRouter(newMemoryIdempotencyRepo(), func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{})
+		})
+
+		postWithKey(r, "u1", "k1", `{"name":"A"}`)
+		w := postWithKey(r, "u1", "k1", `{"name":"B"}`)
+
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Mong đợi 422, nhận được %d", w.Code)
+		}
+	})
+
+	t.Run("keys are scoped by caller", func(t *testing.T) {
+		var calls int32
+		r := newIdempotencyRouter(newMemoryIdempotencyRepo(), func(c *gin.Context) {
+			atomic.AddInt32(&calls, 1)
+			c.JSON(http.StatusOK, gin.H{})
+		})
+
+		postWithKey(r, "u1", "k1", `{"name":"A"}`)
+		postWithKey(r, "u2", "k1", `{"name":"A"}`)
+
+		if calls != 2 {
+			t.Errorf("Mong đợi mỗi user được xử lý riêng, handler được gọi %d lần", calls)
+		}
+	})
+
+	t.Run("server error releases the key", func(t *testing.T) {
+		var calls int32
+		r := newIdempotencyRouter(newMemoryIdempotencyRepo(), func(c *gin.Context) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				c.JSON(http.StatusInternalServerError, gin.H{})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{})
+		})
+
+		postWithKey(r, "u1", "k1", `{}`)
+		w := postWithKey(r, "u1", "k1", `{}`)
+
+		if calls != 2 || w.Code != http.StatusOK {
+			t.Errorf("Mong đợi lần gửi lại được xử lý, handler được gọi %d lần, nhận được %d", calls, w.Code)
+		}
+	})
+
+	t.Run("request without key is not stored", func(t *testing.T) {
+		var calls int32
+		r := newIdempotencyRouter(newMemoryIdempotencyRepo(), func(c *gin.Context) {
+			atomic.AddInt32(&calls, 1)
+			c.JSON(http.StatusOK, gin.H{})
+		})
+
+		postWithKey(r, "u1", "", `{}`)
+		postWithKey(r, "u1", "", `{}`)
+
+		if calls != 2 {
+			t.Errorf("Mong đợi handler được gọi 2 lần, nhận được %d", calls)
+		}
+	})
+
+	t.Run("key too long is rejected", func(t *testing.T) {
+		r := newIdempotencyRouter(newMemoryIdempotencyRepo(), func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{})
+		})
+
+		w := postWithKey(r, "u1", strings.Repeat("k", maxIdempotencyKeyLength+1), `{}`)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Mong đợi 400, nhận được %d", w.Code)
+		}
+	})
+
+	t.Run("concurrent duplicates are processed once", func(t *testing.T) {
+		var calls int32
+		started := make(chan struct{}, 1)
+		release := make(chan struct{})
+		r := newIdempotencyRouter(newMemoryIdempotencyRepo(), func(c *gin.Context) {
+			atomic.AddInt32(&calls, 1)
+			started <- struct{}{}
+			<-release
+			c.JSON(http.StatusOK, gin.H{})
+		})
+
+		// Request đầu giữ khóa và đang xử lý
+		done := make(chan *httptest.ResponseRecorder)
+		go func() { done <- postWithKey(r, "u1", "k1", `{}`) }()
+		<-started
+
+		// Các request trùng đến lúc đó đều bị từ chối
+		var wg sync.WaitGroup
+		var conflicts int32
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if postWithKey(r, "u1", "k1", `{}`).Code == http.StatusConflict {
+					atomic.AddInt32(&conflicts, 1)
+				}
+			}()
+		}
+		wg.Wait()
+		close(release)
+
+		if w := <-done; w.Code != http.StatusOK {
+			t.Errorf("Mong đợi request đầu thành công, nhận được %d", w.Code)
+		}
+		if calls != 1 {
+			t.Errorf("Mong đợi handler được gọi 1 lần, nhận được %d", calls)
+		}
+		if conflicts != 5 {
+			t.Errorf("Mong đợi 5 request trùng bị từ chối, nhận được %d", conflicts)
+		}
+	})
+}
+
+func TestIdempotencyPublicRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var calls int32
+	mw := New(&mockLogger{}, nil, nil, newMemoryIdempotencyRepo(), nil, nil)
+
+	// Route đăng ký không có token, key thuộc về IP của client
+	r := gin.New()
+	r.Use(mw.Idempotency())
+	r.POST("/auth/register", func(c *gin.Context) {
+		atomic.AddInt32(&calls, 1)
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, gin.H{"token": "secret-token"})
+	})
+
+	register := func(ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/auth/register", strings.NewReader(`{"username":"a"}`))
+		req.RemoteAddr = ip + ":1234"
+		req.Header.Set(IdempotencyKeyHeader, "k1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := register("10.0.0.1")
+	retry := register("10.0.0.1")
+	if first.Code != http.StatusOK || retry.Code != http.StatusOK {
+		t.Fatalf("Mong đợi 200, nhận được %d và %d", first.Code, retry.Code)
+	}
+	if calls != 1 {
+		t.Fatalf("Mong đợi đăng ký chỉ được xử lý 1 lần, handler được gọi %d lần", calls)
+	}
+	if retry.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("Mong đợi header %s", IdempotentReplayedHeader)
+	}
+
+	// Cùng key từ IP khác là người gọi khác
+	register("10.0.0.2")
+	if calls != 2 {
+		t.Errorf("Mong đợi mỗi IP được xử lý riêng, handler được gọi %d lần", calls)
+	}
+}
@@ -1,10 +1,13 @@
 package middleware
 
 import (
+	"thuchanhgolang/internal/auth"
+	"thuchanhgolang/internal/idempotency"
 	"thuchanhgolang/internal/models"
 	"thuchanhgolang/pkg/encrypter"
 	"thuchanhgolang/pkg/jwt"
 	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/ratelimit"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,18 +21,26 @@ type Middleware interface {
 	CheckDepartmentAccess() gin.HandlerFunc
 	CheckUserAccess() gin.HandlerFunc
 	SetScopeFromPayload() gin.HandlerFunc
+	Idempotency() gin.HandlerFunc
+	RateLimit(name string, limit ratelimit.Limit) gin.HandlerFunc
 }
 
 type implMiddleware struct {
-	l         log.Logger
-	jwtMgr    jwt.Manager
-	encrypter encrypter.Encrypter
+	l               log.Logger
+	jwtMgr          jwt.Manager
+	encrypter       encrypter.Encrypter
+	idempotencyRepo idempotency.Repository
+	rateLimitStore  ratelimit.Store
+	impersonations  auth.ImpersonationChecker
 }
 
-func New(l log.Logger, jwtMgr jwt.Manager, enc encrypter.Encrypter) Middleware {
+func New(l log.Logger, jwtMgr jwt.Manager, enc encrypter.Encrypter, idempotencyRepo idempotency.Repository, rateLimitStore ratelimit.Store, impersonations auth.ImpersonationChecker) Middleware {
 	return &implMiddleware{
-		l:         l,
-		jwtMgr:    jwtMgr,
-		encrypter: enc,
+		l:               l,
+		jwtMgr:          jwtMgr,
+		encrypter:       enc,
+		idempotencyRepo: idempotencyRepo,
+		rateLimitStore:  rateLimitStore,
+		impersonations:  impersonations,
 	}
 }
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"thuchanhgolang/pkg/jwt"
+	"thuchanhgolang/pkg/ratelimit"
+	"thuchanhgolang/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	// RateLimitResetHeader là số giây tới khi bucket được nạp đầy lại
+	RateLimitResetHeader = "X-RateLimit-Reset"
+	RetryAfterHeader     = "Retry-After"
+)
+
+// RateLimit middleware giới hạn số request của mỗi người gọi trong nhóm route name theo token bucket.
+// Người gọi là user trong JWT, nếu chưa đăng nhập thì là IP. Mỗi response có header X-RateLimit-*,
+// vượt giới hạn thì bị từ chối (429) kèm Retry-After. Store lỗi thì request vẫn được xử lý.
+func (mw *implMiddleware) RateLimit(name string, limit ratelimit.Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !limit.Enabled() {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		res, err := mw.rateLimitStore.Take(ctx, name+":"+rateLimitKey(c), limit, time.Now())
+		if err != nil {
+			mw.l.Errorf(ctx, "middleware.RateLimit.Take: %v", err)
+			c.Next()
+			return
+		}
+
+		c.Header(RateLimitLimitHeader, strconv.Itoa(res.Limit))
+		c.Header(RateLimitRemainingHeader, strconv.Itoa(res.Remaining))
+		c.Header(RateLimitResetHeader, strconv.Itoa(ceilSeconds(res.Reset)))
+		if !res.Allowed {
+			mw.l.Warnf(ctx, "middleware.RateLimit: %s limit %s exceeded", name, limit)
+			c.Header(RetryAfterHeader, strconv.Itoa(max(ceilSeconds(res.RetryAfter), 1)))
+			response.Error(c, errTooManyRequests)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rateLimitKey trả về người gọi của request: user đã đăng nhập (payload đã được verify) hoặc IP.
+// Header do client tự gửi (như API key) không được dùng làm khóa, nếu không mỗi request đổi header là có bucket mới.
+// IP lấy qua ClientIP nên chỉ tin X-Forwarded-For từ proxy đã cấu hình trong TRUSTED_PROXIES.
+func rateLimitKey(c *gin.Context) string {
+	if payload, ok := jwt.GetPayloadFromContext(c.Request.Context()); ok && payload.UserID != "" {
+		return "user:" + payload.UserID
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// ceilSeconds làm tròn lên theo giây, header chỉ nhận số giây nguyên
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
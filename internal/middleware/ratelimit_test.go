@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"thuchanhgolang/pkg/jwt"
+	"thuchanhgolang/pkg/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// failingStore giả lập store không truy cập được
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit ratelimit.Limit, now time.Time) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store down")
+}
+
+// newRateLimitRouter dựng router với user lấy từ header X-User (nếu có)
+func newRateLimitRouter(store ratelimit.Store, limit ratelimit.Limit) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	mw := New(&mockLogger{}, nil, nil, nil, store, nil)
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if user := c.GetHeader("X-User"); user != "" {
+			ctx := jwt.SetPayloadToContext(c.Request.Context(), jwt.Payload{UserID: user})
+			c.Request = c.Request.WithContext(ctx)
+		}
+	})
+	r.Use(mw.RateLimit("users", limit))
+	r.GET("/users", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+	return r
+}
+
+func getUsers(r *gin.Engine, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Run("limit exceeded is rejected with retry after", func(t *testing.T) {
+		r := newRateLimitRouter(ratelimit.NewMemoryStore(), ratelimit.Limit{Requests: 2, Per: time.Minute})
+		user := map[string]string{"X-User": "u1"}
+
+		first := getUsers(r, user)
+		if first.Code != http.StatusOK {
+			t.Fatalf("Mong đợi 200, nhận được %d", first.Code)
+		}
+		if got := first.Header().Get(RateLimitLimitHeader); got != "2" {
+			t.Errorf("Mong đợi %s là 2, nhận được %q", RateLimitLimitHeader, got)
+		}
+		if got := first.Header().Get(RateLimitRemainingHeader); got != "1" {
+			t.Errorf("Mong đợi %s là 1, nhận được %q", RateLimitRemainingHeader, got)
+		}
+		if got := first.Header().Get(RateLimitResetHeader); got != "30" {
+			t.Errorf("Mong đợi %s là 30, nhận được %q", RateLimitResetHeader, got)
+		}
+
+		getUsers(r, user)
+		w := getUsers(r, user)
+		if w.Code != http.StatusTooManyRequests {
+			t.Fatalf("Mong đợi 429, nhận được %d", w.Code)
+		}
+		if got := w.Header().Get(RetryAfterHeader); got != "30" {
+			t.Errorf("Mong đợi %s là 30, nhận được %q", RetryAfterHeader, got)
+		}
+		if got := w.Header().Get(RateLimitRemainingHeader); got != "0" {
+			t.Errorf("Mong đợi %s là 0, nhận được %q", RateLimitRemainingHeader, got)
+		}
+	})
+
+	t.Run("callers have separate buckets", func(t *testing.T) {
+		r := newRateLimitRouter(ratelimit.NewMemoryStore(), ratelimit.Limit{Requests: 1, Per: time.Minute})
+
+		callers := []map[string]string{
+			{"X-User": "u1"},
+			{"X-User": "u2"},
+			{},
+		}
+		for _, h := range callers {
+			if w := getUsers(r, h); w.Code != http.StatusOK {
+				t.Errorf("Mong đợi request đầu của %v thành công, nhận được %d", h, w.Code)
+			}
+		}
+		for _, h := range callers {
+			if w := getUsers(r, h); w.Code != http.StatusTooManyRequests {
+				t.Errorf("Mong đợi request thứ hai của %v bị từ chối, nhận được %d", h, w.Code)
+			}
+		}
+	})
+
+	t.Run("random api key does not reset ip bucket", func(t *testing.T) {
+		r := newRateLimitRouter(ratelimit.NewMemoryStore(), ratelimit.Limit{Requests: 1, Per: time.Minute})
+
+		if w := getUsers(r, nil); w.Code != http.StatusOK {
+			t.Fatalf("Mong đợi 200, nhận được %d", w.Code)
+		}
+		for _, key := range []string{"k1", "k2", "k3"} {
+			if w := getUsers(r, map[string]string{"X-API-Key": key}); w.Code != http.StatusTooManyRequests {
+				t.Errorf("Mong đợi API key %s vẫn dùng bucket của IP (429), nhận được %d", key, w.Code)
+			}
+		}
+	})
+
+	t.Run("forwarded ip from untrusted proxy is ignored", func(t *testing.T) {
+		r := newRateLimitRouter(ratelimit.NewMemoryStore(), ratelimit.Limit{Requests: 1, Per: time.Minute})
+		if err := r.SetTrustedProxies(nil); err != nil {
+			t.Fatalf("Không cấu hình được trusted proxies: %v", err)
+		}
+
+		getUsers(r, nil)
+		for _, ip := range []string{"203.0.113.1", "203.0.113.2"} {
+			if w := getUsers(r, map[string]string{"X-Forwarded-For": ip}); w.Code != http.StatusTooManyRequests {
+				t.Errorf("Mong đợi X-Forwarded-For %s không đổi được bucket (429), nhận được %d", ip, w.Code)
+			}
+		}
+	})
+
+	t.Run("tokens are refilled over time", func(t *testing.T) {
+		r := newRateLimitRouter(ratelimit.NewMemoryStore(), ratelimit.Limit{Requests: 1, Per: 50 * time.Millisecond})
+		user := map[string]string{"X-User": "u1"}
+
+		getUsers(r, user)
+		if w := getUsers(r, user); w.Code != http.StatusTooManyRequests {
+			t.Fatalf("Mong đợi 429, nhận được %d", w.Code)
+		}
+		time.Sleep(60 * time.Millisecond)
+		if w := getUsers(r, user); w.Code != http.StatusOK {
+			t.Errorf("Mong đợi 200 sau khi nạp lại, nhận được %d", w.Code)
+		}
+	})
+
+	t.Run("disabled limit does not throttle", func(t *testing.T) {
+		r := newRateLimitRouter(ratelimit.NewMemoryStore(), ratelimit.Limit{})
+
+		for i := 0; i < 5; i++ {
+			w := getUsers(r, nil)
+			if w.Code != http.StatusOK || w.Header().Get(RateLimitLimitHeader) != "" {
+				t.Fatalf("Mong đợi request không bị giới hạn, nhận được %d", w.Code)
+			}
+		}
+	})
+
+	t.Run("store error lets request through", func(t *testing.T) {
+		r := newRateLimitRouter(failingStore{}, ratelimit.Limit{Requests: 1, Per: time.Minute})
+
+		for i := 0; i < 2; i++ {
+			if w := getUsers(r, nil); w.Code != http.StatusOK {
+				t.Fatalf("Mong đợi 200, nhận được %d", w.Code)
+			}
+		}
+	})
+}
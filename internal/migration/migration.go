@@ -0,0 +1,209 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/mongo"
+)
+
+var (
+	// ErrLocked trả về khi một tiến trình khác đang chạy migration
+	ErrLocked = errors.New("migration is locked by another process")
+	// ErrIrreversible trả về khi rollback một migration không có bước down
+	ErrIrreversible = errors.New("migration cannot be rolled back")
+	// ErrInvalidMigrations trả về khi danh sách migration khai báo sai (trùng version, thiếu up...)
+	ErrInvalidMigrations = errors.New("invalid migration list")
+)
+
+// Func là một bước migration, nhận database để tạo index / sửa dữ liệu
+type Func func(ctx context.Context, db mongo.Database) error
+
+// Migration là một phiên bản thay đổi database.
+// Version phải tăng dần và không được đổi sau khi đã chạy trên môi trường thật.
+type Migration struct {
+	Version int
+	Name    string
+	Up      Func
+	Down    Func // nil → không thể rollback
+}
+
+// Record là một migration đã chạy, lưu trong collection migrations
+type Record struct {
+	Version   int       `bson:"_id"`
+	Name      string    `bson:"name"`
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+// Status là trạng thái của một migration
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time // nil → chưa chạy
+}
+
+// Store lưu lịch sử migration và khóa để hai tiến trình không chạy cùng lúc
+type Store interface {
+	Applied(ctx context.Context) ([]Record, error)
+	Save(ctx context.Context, r Record) error
+	Remove(ctx context.Context, version int) error
+
+	// Lock giữ khóa migration, trả ErrLocked nếu tiến trình khác đang giữ
+	Lock(ctx context.Context) error
+	Unlock(ctx context.Context) error
+}
+
+// Migrator chạy các migration theo thứ tự version
+type Migrator struct {
+	l          log.Logger
+	db         mongo.Database
+	store      Store
+	migrations []Migration
+}
+
+// New tạo Migrator, kiểm tra danh sách migration hợp lệ và sắp xếp theo version
+func New(l log.Logger, db mongo.Database, store Store, migrations []Migration) (*Migrator, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	for i, m := range sorted {
+		if m.Version <= 0 || m.Up == nil || m.Name == "" {
+			return nil, fmt.Errorf("%w: version %d must be positive and have a name and an up step", ErrInvalidMigrations, m.Version)
+		}
+		if i > 0 && sorted[i-1].Version == m.Version {
+			return nil, fmt.Errorf("%w: duplicated version %d", ErrInvalidMigrations, m.Version)
+		}
+	}
+
+	return &Migrator{
+		l:          l,
+		db:         db,
+		store:      store,
+		migrations: sorted,
+	}, nil
+}
+
+// Up chạy tất cả migration chưa được áp dụng, trả về các migration vừa chạy.
+// Mỗi migration được ghi lại ngay sau khi chạy xong, nên nếu dừng giữa chừng thì lần sau chạy tiếp từ chỗ lỗi.
+func (m *Migrator) Up(ctx context.Context) ([]Record, error) {
+	var done []Record
+	err := m.withLock(ctx, func() error {
+		applied, err := m.appliedSet(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+
+			m.l.Infof(ctx, "migration.Up: applying %d_%s", mig.Version, mig.Name)
+			if err := mig.Up(ctx, m.db); err != nil {
+				return fmt.Errorf("migration %d_%s up: %w", mig.Version, mig.Name, err)
+			}
+
+			r := Record{Version: mig.Version, Name: mig.Name, AppliedAt: time.Now()}
+			if err := m.store.Save(ctx, r); err != nil {
+				return fmt.Errorf("migration %d_%s save: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, r)
+		}
+		return nil
+	})
+
+	return done, err
+}
+
+// Down rollback steps migration mới nhất đã áp dụng, trả về các migration đã rollback
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Record, error) {
+	var done []Record
+	err := m.withLock(ctx, func() error {
+		records, err := m.store.Applied(ctx)
+		if err != nil {
+			return err
+		}
+		sort.Slice(records, func(i, j int) bool { return records[i].Version > records[j].Version })
+
+		byVersion := make(map[int]Migration, len(m.migrations))
+		for _, mig := range m.migrations {
+			byVersion[mig.Version] = mig
+		}
+
+		for i := 0; i < steps && i < len(records); i++ {
+			r := records[i]
+			mig, ok := byVersion[r.Version]
+			if !ok {
+				return fmt.Errorf("migration %d_%s is not known by this binary", r.Version, r.Name)
+			}
+			if mig.Down == nil {
+				return fmt.Errorf("migration %d_%s: %w", mig.Version, mig.Name, ErrIrreversible)
+			}
+
+			m.l.Infof(ctx, "migration.Down: rolling back %d_%s", mig.Version, mig.Name)
+			if err := mig.Down(ctx, m.db); err != nil {
+				return fmt.Errorf("migration %d_%s down: %w", mig.Version, mig.Name, err)
+			}
+			if err := m.store.Remove(ctx, mig.Version); err != nil {
+				return fmt.Errorf("migration %d_%s remove: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, r)
+		}
+		return nil
+	})
+
+	return done, err
+}
+
+// Status trả về trạng thái của mọi migration đã khai báo, theo thứ tự version
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.appliedSet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		s := Status{Version: mig.Version, Name: mig.Name}
+		if r, ok := applied[mig.Version]; ok {
+			appliedAt := r.AppliedAt
+			s.AppliedAt = &appliedAt
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// appliedSet đọc các migration đã chạy thành map theo version
+func (m *Migrator) appliedSet(ctx context.Context) (map[int]Record, error) {
+	records, err := m.store.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]Record, len(records))
+	for _, r := range records {
+		applied[r.Version] = r
+	}
+	return applied, nil
+}
+
+// withLock giữ khóa trong lúc chạy fn, khóa được nhả kể cả khi fn lỗi
+func (m *Migrator) withLock(ctx context.Context, fn func() error) error {
+	if err := m.store.Lock(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		// Dùng context riêng để vẫn nhả được khóa khi ctx đã bị hủy
+		if err := m.store.Unlock(context.WithoutCancel(ctx)); err != nil {
+			m.l.Errorf(ctx, "migration.withLock.Unlock: %v", err)
+		}
+	}()
+
+	return fn()
+}
@@ -0,0 +1,188 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"thuchanhgolang/pkg/mongo"
+)
+
+// memoryStore lưu lịch sử migration trong bộ nhớ
+type memoryStore struct {
+	records map[int]Record
+	locked  bool
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{records: make(map[int]Record)}
+}
+
+func (s *memoryStore) Applied(ctx context.Context) ([]Record, error) {
+	out := make([]Record, 0, len(s.records))
+	for _, r := range s.records {
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func (s *memoryStore) Save(ctx context.Context, r Record) error {
+	s.records[r.Version] = r
+	return nil
+}
+
+func (s *memoryStore) Remove(ctx context.Context, version int) error {
+	delete(s.records, version)
+	return nil
+}
+
+func (s *memoryStore) Lock(ctx context.Context) error {
+	if s.locked {
+		return ErrLocked
+	}
+	s.locked = true
+	return nil
+}
+
+func (s *memoryStore) Unlock(ctx context.Context) error {
+	s.locked = false
+	return nil
+}
+
+type mockLogger struct{}
+
+func (m *mockLogger) Debug(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Debugf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Info(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Infof(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Warn(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Warnf(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Error(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Errorf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Fatal(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Fatalf(ctx context.Context, template string, arg ...any) {}
+
+// recorder tạo migration ghi lại thứ tự các bước đã chạy
+type recorder struct {
+	calls []string
+}
+
+func (r *recorder) step(name string, err error) Func {
+	return func(ctx context.Context, db mongo.Database) error {
+		r.calls = append(r.calls, name)
+		return err
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("up applies pending in version order", func(t *testing.T) {
+		rec := &recorder{}
+		store := newMemoryStore()
+		store.records[1] = Record{Version: 1, Name: "one"}
+
+		m, err := New(&mockLogger{}, nil, store, []Migration{
+			{Version: 3, Name: "three", Up: rec.step("up3", nil)},
+			{Version: 1, Name: "one", Up: rec.step("up1", nil)},
+			{Version: 2, Name: "two", Up: rec.step("up2", nil)},
+		})
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+
+		done, err := m.Up(ctx)
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if len(done) != 2 || len(rec.calls) != 2 || rec.calls[0] != "up2" || rec.calls[1] != "up3" {
+			t.Errorf("Mong đợi chạy up2 rồi up3, nhận được %v", rec.calls)
+		}
+		if len(store.records) != 3 {
+			t.Errorf("Mong đợi 3 migration được ghi lại, nhận được %d", len(store.records))
+		}
+		if store.locked {
+			t.Error("Khóa phải được nhả sau khi chạy xong")
+		}
+	})
+
+	t.Run("up stops at failing migration", func(t *testing.T) {
+		rec := &recorder{}
+		store := newMemoryStore()
+		boom := errors.New("boom")
+
+		m, _ := New(&mockLogger{}, nil, store, []Migration{
+			{Version: 1, Name: "one", Up: rec.step("up1", nil)},
+			{Version: 2, Name: "two", Up: rec.step("up2", boom)},
+			{Version: 3, Name: "three", Up: rec.step("up3", nil)},
+		})
+
+		_, err := m.Up(ctx)
+		if !errors.Is(err, boom) {
+			t.Fatalf("Mong đợi lỗi boom, nhận được %v", err)
+		}
+		if _, ok := store.records[2]; ok || len(store.records) != 1 {
+			t.Errorf("Chỉ migration 1 được ghi lại, nhận được %v", store.records)
+		}
+		if store.locked {
+			t.Error("Khóa phải được nhả kể cả khi lỗi")
+		}
+	})
+
+	t.Run("down rolls back newest first", func(t *testing.T) {
+		rec := &recorder{}
+		store := newMemoryStore()
+		store.records[1] = Record{Version: 1, Name: "one"}
+		store.records[2] = Record{Version: 2, Name: "two"}
+
+		m, _ := New(&mockLogger{}, nil, store, []Migration{
+			{Version: 1, Name: "one", Up: rec.step("up1", nil), Down: rec.step("down1", nil)},
+			{Version: 2, Name: "two", Up: rec.step("up2", nil), Down: rec.step("down2", nil)},
+		})
+
+		done, err := m.Down(ctx, 1)
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if len(done) != 1 || done[0].Version != 2 || rec.calls[0] != "down2" {
+			t.Errorf("Mong đợi rollback migration 2, nhận được %v", rec.calls)
+		}
+		if _, ok := store.records[2]; ok {
+			t.Error("Bản ghi migration 2 phải bị xóa")
+		}
+	})
+
+	t.Run("down without step is irreversible", func(t *testing.T) {
+		store := newMemoryStore()
+		store.records[1] = Record{Version: 1, Name: "one"}
+
+		m, _ := New(&mockLogger{}, nil, store, []Migration{
+			{Version: 1, Name: "one", Up: (&recorder{}).step("up1", nil)},
+		})
+
+		if _, err := m.Down(ctx, 1); !errors.Is(err, ErrIrreversible) {
+			t.Fatalf("Mong đợi ErrIrreversible, nhận được %v", err)
+		}
+	})
+
+	t.Run("locked", func(t *testing.T) {
+		store := newMemoryStore()
+		store.locked = true
+
+		m, _ := New(&mockLogger{}, nil, store, All())
+		if _, err := m.Up(ctx); !errors.Is(err, ErrLocked) {
+			t.Fatalf("Mong đợi ErrLocked, nhận được %v", err)
+		}
+	})
+
+	t.Run("duplicated version", func(t *testing.T) {
+		up := (&recorder{}).step("up", nil)
+		_, err := New(&mockLogger{}, nil, newMemoryStore(), []Migration{
+			{Version: 1, Name: "a", Up: up},
+			{Version: 1, Name: "b", Up: up},
+		})
+		if !errors.Is(err, ErrInvalidMigrations) {
+			t.Fatalf("Mong đợi ErrInvalidMigrations, nhận được %v", err)
+		}
+	})
+}
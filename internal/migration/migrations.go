@@ -0,0 +1,356 @@
+package migration
+
+import (
+	"context"
+
+	"thuchanhgolang/pkg/mongo"
+
+	"go.mongodb.org/mongo-driver/bson"
+	driverMongo "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// All trả về danh sách migration của ứng dụng.
+// Chỉ thêm migration mới vào cuối, không sửa migration đã phát hành.
+func All() []Migration {
+	return []Migration{
+		{
+			Version: 1,
+			Name:    "create_users_indexes",
+			Up:      createIndexes("users", usersIndexes),
+			Down:    dropIndexes("users", usersIndexes),
+		},
+		{
+			Version: 2,
+			Name:    "create_org_fk_indexes",
+			Up:      createOrgIndexes,
+			Down:    dropOrgIndexes,
+		},
+		{
+			Version: 3,
+			Name:    "backfill_created_at",
+			Up:      backfillCreatedAt,
+			// Giữ nguyên created_at khi rollback: không phân biệt được giá trị backfill với giá trị thật
+			Down: func(ctx context.Context, db mongo.Database) error { return nil },
+		},
+		{
+			Version: 4,
+			Name:    "create_unique_indexes",
+			Up:      createUniqueIndexes,
+			Down:    dropUniqueIndexes,
+		},
+		{
+			Version: 5,
+			Name:    "backfill_version",
+			Up:      backfillVersion,
+			// Giữ nguyên version khi rollback: version chỉ tăng, bỏ đi sẽ làm ETag client đang giữ không còn khớp
+			Down: func(ctx context.Context, db mongo.Database) error { return nil },
+		},
+		{
+			Version: 6,
+			Name:    "backfill_updated_at",
+			Up:      backfillUpdatedAt,
+			// Giữ nguyên updated_at khi rollback, lý do như backfill_created_at
+			Down: func(ctx context.Context, db mongo.Database) error { return nil },
+		},
+		{
+			Version: 7,
+			Name:    "create_revisions_indexes",
+			Up:      createIndexes("revisions", revisionsIndexes),
+			Down:    dropIndexes("revisions", revisionsIndexes),
+		},
+		{
+			Version: 8,
+			Name:    "create_outbox_indexes",
+			Up:      createIndexes("outbox", outboxIndexes),
+			Down:    dropIndexes("outbox", outboxIndexes),
+		},
+		{
+			Version: 9,
+			Name:    "create_webhook_indexes",
+			Up:      createWebhookIndexes,
+			Down:    dropWebhookIndexes,
+		},
+		{
+			Version: 10,
+			Name:    "create_changes_indexes",
+			Up:      createIndexes("changes", changesIndexes),
+			Down:    dropIndexes("changes", changesIndexes),
+		},
+		{
+			Version: 11,
+			Name:    "create_idempotency_indexes",
+			Up:      createIndexes("idempotency_keys", idempotencyIndexes),
+			Down:    dropIndexes("idempotency_keys", idempotencyIndexes),
+		},
+		{
+			Version: 12,
+			Name:    "create_rate_limits_indexes",
+			Up:      createIndexes("rate_limits", rateLimitsIndexes),
+			Down:    dropIndexes("rate_limits", rateLimitsIndexes),
+		},
+		{
+			Version: 13,
+			Name:    "create_import_jobs_indexes",
+			Up:      createIndexes("import_jobs", importJobsIndexes),
+			Down:    dropIndexes("import_jobs", importJobsIndexes),
+		},
+		{
+			Version: 14,
+			Name:    "create_changes_seq_index",
+			Up:      createIndexes("changes", changesSeqIndexes),
+			Down:    dropIndexes("changes", changesSeqIndexes),
+		},
+	}
+}
+
+// usersIndexes: username dùng khi login và phải duy nhất,
+// các khóa ngoại dùng trong HasUsers và khi lọc user theo đơn vị
+var usersIndexes = []driverMongo.IndexModel{
+	{Keys: bson.D{{Key: "username", Value: 1}}, Options: options.Index().SetName("username_unique").SetUnique(true)},
+	{Keys: bson.D{{Key: "shop_id", Value: 1}}, Options: options.Index().SetName("shop_id")},
+	{Keys: bson.D{{Key: "region_id", Value: 1}}, Options: options.Index().SetName("region_id")},
+	{Keys: bson.D{{Key: "branch_id", Value: 1}}, Options: options.Index().SetName("branch_id")},
+	{Keys: bson.D{{Key: "department_id", Value: 1}}, Options: options.Index().SetName("department_id").SetSparse(true)},
+}
+
+// revisionsIndexes dùng khi lấy lịch sử và trạng thái tại một thời điểm của một entity
+var revisionsIndexes = []driverMongo.IndexModel{
+	{
+		Keys:    bson.D{{Key: "entity", Value: 1}, {Key: "entity_id", Value: 1}, {Key: "changed_at", Value: 1}},
+		Options: options.Index().SetName("entity_changed_at"),
+	},
+}
+
+// outboxIndexes: dispatcher lấy event chưa gửi theo thứ tự occurred_at,
+// event đã gửi được MongoDB tự xóa sau 7 ngày (document chưa có delivered_at không bị TTL xóa)
+var outboxIndexes = []driverMongo.IndexModel{
+	{
+		Keys:    bson.D{{Key: "delivered_at", Value: 1}, {Key: "occurred_at", Value: 1}, {Key: "_id", Value: 1}},
+		Options: options.Index().SetName("pending"),
+	},
+	{
+		Keys:    bson.D{{Key: "delivered_at", Value: 1}},
+		Options: options.Index().SetName("delivered_ttl").SetExpireAfterSeconds(7 * 24 * 60 * 60),
+	},
+}
+
+// webhookIndexes: publisher tìm webhook đang bật của shop theo loại event,
+// deliverer lấy delivery tới hạn, mỗi event chỉ có một delivery cho một webhook, delivery log đọc theo webhook
+var webhookIndexes = map[string][]driverMongo.IndexModel{
+	"webhooks": {
+		{
+			Keys:    bson.D{{Key: "shop_id", Value: 1}, {Key: "active", Value: 1}, {Key: "event_types", Value: 1}},
+			Options: options.Index().SetName("subscribers"),
+		},
+	},
+	"webhook_deliveries": {
+		{
+			Keys:    bson.D{{Key: "webhook_id", Value: 1}, {Key: "event._id", Value: 1}},
+			Options: options.Index().SetName("webhook_event_unique").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+			Options: options.Index().SetName("due"),
+		},
+		{
+			Keys:    bson.D{{Key: "webhook_id", Value: 1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("webhook_log"),
+		},
+	},
+}
+
+// changesIndexes: mỗi event chỉ được ghi vào change log một lần dù outbox gửi lại,
+// change log chỉ giữ 24 giờ gần nhất để client SSE nối lại sau khi mất kết nối
+var changesIndexes = []driverMongo.IndexModel{
+	{
+		Keys:    bson.D{{Key: "event._id", Value: 1}},
+		Options: options.Index().SetName("event_unique").SetUnique(true),
+	},
+	{
+		Keys:    bson.D{{Key: "created_at", Value: 1}},
+		Options: options.Index().SetName("created_ttl").SetExpireAfterSeconds(24 * 60 * 60),
+	},
+}
+
+// changesSeqIndexes: client SSE đọc change log theo số thứ tự, mỗi số thứ tự chỉ cấp cho một change.
+// Change ghi trước khi có số thứ tự không có field seq, chúng tự hết hạn theo TTL
+var changesSeqIndexes = []driverMongo.IndexModel{
+	{
+		Keys: bson.D{{Key: "seq", Value: 1}},
+		Options: options.Index().SetName("seq_unique").SetUnique(true).
+			SetPartialFilterExpression(bson.M{"seq": bson.M{"$exists": true}}),
+	},
+}
+
+// idempotencyIndexes: _id đã là user và key nên không cần unique index riêng,
+// response chỉ được giữ 24 giờ, client gửi lại sau đó thì request được xử lý như mới
+var idempotencyIndexes = []driverMongo.IndexModel{
+	{
+		Keys:    bson.D{{Key: "created_at", Value: 1}},
+		Options: options.Index().SetName("created_ttl").SetExpireAfterSeconds(24 * 60 * 60),
+	},
+}
+
+// rateLimitsIndexes: bucket được xóa khi đã nạp đầy lại (expires_at), bucket đầy giống hệt bucket mới
+var rateLimitsIndexes = []driverMongo.IndexModel{
+	{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetName("expires_ttl").SetExpireAfterSeconds(0),
+	},
+}
+
+// importJobsIndexes: job import được xóa 1 ngày sau khi chạy xong, kể cả mật khẩu tạm (đã mã hóa) chưa được xem.
+// Job chưa có finished_at không bị TTL xóa
+var importJobsIndexes = []driverMongo.IndexModel{
+	{
+		Keys:    bson.D{{Key: "finished_at", Value: 1}},
+		Options: options.Index().SetName("finished_ttl").SetExpireAfterSeconds(24 * 60 * 60),
+	},
+}
+
+// orgIndexes là index khóa ngoại của các đơn vị, dùng trong HasRegions/HasBranches/HasDepartments
+var orgIndexes = map[string][]driverMongo.IndexModel{
+	"regions":     {{Keys: bson.D{{Key: "shop_id", Value: 1}}, Options: options.Index().SetName("shop_id")}},
+	"branches":    {{Keys: bson.D{{Key: "region_id", Value: 1}}, Options: options.Index().SetName("region_id")}},
+	"departments": {{Keys: bson.D{{Key: "branch_id", Value: 1}}, Options: options.Index().SetName("branch_id")}},
+}
+
+// nonEmptyString chỉ áp dụng unique index cho document có giá trị chuỗi khác rỗng,
+// để dữ liệu cũ chưa có email / code không bị coi là trùng nhau
+func nonEmptyString(field string) bson.M {
+	return bson.M{field: bson.M{"$type": "string", "$gt": ""}}
+}
+
+// uniqueIndexes: email duy nhất trong một shop, code duy nhất giữa các shop
+var uniqueIndexes = map[string][]driverMongo.IndexModel{
+	"users": {{
+		Keys:    bson.D{{Key: "shop_id", Value: 1}, {Key: "email", Value: 1}},
+		Options: options.Index().SetName("shop_email_unique").SetUnique(true).SetPartialFilterExpression(nonEmptyString("email")),
+	}},
+	"shops": {{
+		Keys:    bson.D{{Key: "code", Value: 1}},
+		Options: options.Index().SetName("code_unique").SetUnique(true).SetPartialFilterExpression(nonEmptyString("code")),
+	}},
+}
+
+// timestampedCollections là các collection cần có created_at, updated_at và version
+var timestampedCollections = []string{"shops", "regions", "branches", "departments", "users"}
+
+func createOrgIndexes(ctx context.Context, db mongo.Database) error {
+	for collection, indexes := range orgIndexes {
+		if err := createIndexes(collection, indexes)(ctx, db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dropOrgIndexes(ctx context.Context, db mongo.Database) error {
+	for collection, indexes := range orgIndexes {
+		if err := dropIndexes(collection, indexes)(ctx, db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createWebhookIndexes(ctx context.Context, db mongo.Database) error {
+	for collection, indexes := range webhookIndexes {
+		if err := createIndexes(collection, indexes)(ctx, db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dropWebhookIndexes(ctx context.Context, db mongo.Database) error {
+	for collection, indexes := range webhookIndexes {
+		if err := dropIndexes(collection, indexes)(ctx, db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createUniqueIndexes(ctx context.Context, db mongo.Database) error {
+	for collection, indexes := range uniqueIndexes {
+		if err := createIndexes(collection, indexes)(ctx, db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dropUniqueIndexes(ctx context.Context, db mongo.Database) error {
+	for collection, indexes := range uniqueIndexes {
+		if err := dropIndexes(collection, indexes)(ctx, db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillCreatedAt đặt created_at theo thời điểm tạo ObjectID cho document chưa có
+func backfillCreatedAt(ctx context.Context, db mongo.Database) error {
+	filter := bson.M{"created_at": bson.M{"$exists": false}}
+	pipeline := driverMongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"created_at": bson.M{"$toDate": "$_id"}}}},
+	}
+
+	for _, collection := range timestampedCollections {
+		if _, err := db.Collection(collection).UpdateMany(ctx, filter, pipeline); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillUpdatedAt đặt updated_at = created_at cho document chưa từng được ghi updated_at.
+// Chạy sau backfill_created_at nên created_at luôn có giá trị
+func backfillUpdatedAt(ctx context.Context, db mongo.Database) error {
+	filter := bson.M{"updated_at": bson.M{"$exists": false}}
+	pipeline := driverMongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"updated_at": "$created_at"}}},
+	}
+
+	for _, collection := range timestampedCollections {
+		if _, err := db.Collection(collection).UpdateMany(ctx, filter, pipeline); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillVersion đặt version = 1 cho document tạo trước khi có optimistic concurrency
+func backfillVersion(ctx context.Context, db mongo.Database) error {
+	filter := bson.M{"version": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"version": 1}}
+
+	for _, collection := range timestampedCollections {
+		if _, err := db.Collection(collection).UpdateMany(ctx, filter, update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createIndexes tạo step tạo các index trên collection (bỏ qua index đã có cùng định nghĩa)
+func createIndexes(collection string, indexes []driverMongo.IndexModel) Func {
+	return func(ctx context.Context, db mongo.Database) error {
+		_, err := db.Collection(collection).CreateIndexes(ctx, indexes)
+		return err
+	}
+}
+
+// dropIndexes tạo step xóa các index theo tên
+func dropIndexes(collection string, indexes []driverMongo.IndexModel) Func {
+	return func(ctx context.Context, db mongo.Database) error {
+		for _, idx := range indexes {
+			if err := db.Collection(collection).DropIndex(ctx, *idx.Options.Name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,118 @@
+package migration
+
+import (
+	"context"
+	"time"
+
+	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/mongo"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	migrationCollection = "migrations"
+	lockCollection      = "migration_lock"
+	lockID              = "migrate"
+
+	// lockTTL là thời gian sau đó khóa bị coi là bỏ quên (tiến trình giữ khóa đã chết)
+	lockTTL = 15 * time.Minute
+)
+
+// mongoStore lưu lịch sử migration trong MongoDB
+type mongoStore struct {
+	l     log.Logger
+	db    mongo.Database
+	owner string // Định danh của tiến trình đang giữ khóa
+}
+
+// lockDoc là document khóa migration
+type lockDoc struct {
+	ID       string    `bson:"_id"`
+	Owner    string    `bson:"owner"`
+	LockedAt time.Time `bson:"locked_at"`
+}
+
+// NewStore tạo Store dùng collection migrations và migration_lock
+func NewStore(l log.Logger, db mongo.Database) Store {
+	return &mongoStore{
+		l:     l,
+		db:    db,
+		owner: primitive.NewObjectID().Hex(),
+	}
+}
+
+// Applied đọc các migration đã chạy
+func (s *mongoStore) Applied(ctx context.Context) ([]Record, error) {
+	cursor, err := s.db.Collection(migrationCollection).Find(ctx, bson.M{})
+	if err != nil {
+		s.l.Errorf(ctx, "migration.store.Applied.Find: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var records []Record
+	if err := cursor.All(ctx, &records); err != nil {
+		s.l.Errorf(ctx, "migration.store.Applied.All: %v", err)
+		return nil, err
+	}
+	return records, nil
+}
+
+// Save ghi lại migration vừa chạy
+func (s *mongoStore) Save(ctx context.Context, r Record) error {
+	if _, err := s.db.Collection(migrationCollection).InsertOne(ctx, r); err != nil {
+		s.l.Errorf(ctx, "migration.store.Save.InsertOne: %v", err)
+		return err
+	}
+	return nil
+}
+
+// Remove xóa bản ghi của migration vừa rollback
+func (s *mongoStore) Remove(ctx context.Context, version int) error {
+	if _, err := s.db.Collection(migrationCollection).DeleteOne(ctx, bson.M{"_id": version}); err != nil {
+		s.l.Errorf(ctx, "migration.store.Remove.DeleteOne: %v", err)
+		return err
+	}
+	return nil
+}
+
+// Lock tạo document khóa; nếu khóa cũ đã quá lockTTL thì chiếm lại
+func (s *mongoStore) Lock(ctx context.Context) error {
+	col := s.db.Collection(lockCollection)
+	now := time.Now()
+
+	_, err := col.InsertOne(ctx, lockDoc{ID: lockID, Owner: s.owner, LockedAt: now})
+	if err == nil {
+		return nil
+	}
+	if !mongo.IsDuplicateKey(err) {
+		s.l.Errorf(ctx, "migration.store.Lock.InsertOne: %v", err)
+		return err
+	}
+
+	// Khóa đang tồn tại: chỉ chiếm lại khi đã quá hạn
+	filter := bson.M{"_id": lockID, "locked_at": bson.M{"$lt": now.Add(-lockTTL)}}
+	update := bson.M{"$set": bson.M{"owner": s.owner, "locked_at": now}}
+	res, err := col.UpdateOne(ctx, filter, update)
+	if err != nil {
+		s.l.Errorf(ctx, "migration.store.Lock.UpdateOne: %v", err)
+		return err
+	}
+	if res.ModifiedCount == 0 {
+		return ErrLocked
+	}
+
+	s.l.Warnf(ctx, "migration.store.Lock: took over a stale lock")
+	return nil
+}
+
+// Unlock xóa khóa nếu tiến trình này đang giữ
+func (s *mongoStore) Unlock(ctx context.Context) error {
+	if _, err := s.db.Collection(lockCollection).DeleteOne(ctx, bson.M{"_id": lockID, "owner": s.owner}); err != nil {
+		s.l.Errorf(ctx, "migration.store.Unlock.DeleteOne: %v", err)
+		return err
+	}
+	return nil
+}
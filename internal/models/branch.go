@@ -1,11 +1,20 @@
 package models
 
 import (
+	"time"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Branch struct {
-	ID       primitive.ObjectID `bson:"_id,omitempty"`
-	RegionID primitive.ObjectID `bson:"region_id"`
-	Name     string             `bson:"name"`
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	RegionID  primitive.ObjectID `bson:"region_id"`
+	Name      string             `bson:"name"`
+	CreatedAt time.Time          `bson:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at"`
+	// CreatedBy, UpdatedBy là UserID của người tạo và người sửa gần nhất, rỗng khi do hệ thống ghi
+	CreatedBy string `bson:"created_by,omitempty"`
+	UpdatedBy string `bson:"updated_by,omitempty"`
+	// Version tăng mỗi lần cập nhật, dùng làm ETag để phát hiện ghi đè đồng thời
+	Version int `bson:"version"`
 }
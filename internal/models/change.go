@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"thuchanhgolang/pkg/event"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Change là một event đã được ghi vào change log để đẩy tới client qua SSE.
+// Seq lấy từ bộ đếm chung của mọi instance nên tăng dần theo thứ tự ghi,
+// được dùng làm id của SSE event (Last-Event-ID).
+type Change struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	Seq       int64              `bson:"seq"`
+	Event     event.Event        `bson:"event"`
+	CreatedAt time.Time          `bson:"created_at"` // Change log tự xóa bản ghi cũ theo TTL trên field này
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// IdempotencyRecord là kết quả của một request POST có header Idempotency-Key.
+// Bản ghi được tạo trước khi xử lý để làm khóa, request trùng key đến lúc đang xử lý
+// thấy CompletedAt = nil và bị từ chối. Xử lý xong thì lưu lại response để trả cho lần gửi lại.
+type IdempotencyRecord struct {
+	ID          string               `bson:"_id"` // UserID và key, mỗi người gọi có không gian key riêng
+	UserID      string               `bson:"user_id"`
+	Key         string               `bson:"key"`
+	Method      string               `bson:"method"`
+	Path        string               `bson:"path"`
+	RequestHash string               `bson:"request_hash"` // SHA-256 của method, path và body
+	LockedUntil time.Time            `bson:"locked_until"` // Quá thời điểm này mà chưa xong thì request khác được chiếm khóa
+	Response    *IdempotencyResponse `bson:"response,omitempty"`
+	CreatedAt   time.Time            `bson:"created_at"` // Bản ghi tự xóa theo TTL trên field này
+	CompletedAt *time.Time           `bson:"completed_at,omitempty"`
+}
+
+// IdempotencyResponse là response đã trả về cho request đầu tiên
+type IdempotencyResponse struct {
+	Status int               `bson:"status"`
+	Header map[string]string `bson:"header,omitempty"` // Chỉ các header cần trả lại như Content-Type, ETag
+	Body   []byte            `bson:"body"`
+}
+
+// IsCompleted kiểm tra request đầu tiên đã xử lý xong và lưu response chưa
+func (r IdempotencyRecord) IsCompleted() bool {
+	return r.CompletedAt != nil && r.Response != nil
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ImpersonationLog ghi lại một phiên admin đăng nhập dưới danh nghĩa user khác
+type ImpersonationLog struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	ActorID        primitive.ObjectID `bson:"actor_id"`
+	ActorUsername  string             `bson:"actor_username"`
+	TargetUserID   primitive.ObjectID `bson:"target_user_id"`
+	TargetUsername string             `bson:"target_username"`
+	ShopID         primitive.ObjectID `bson:"shop_id"`
+	AllowWrite     bool               `bson:"allow_write"`
+	Reason         string             `bson:"reason"`
+	StartedAt      time.Time          `bson:"started_at"`
+	ExpiresAt      time.Time          `bson:"expires_at"`
+	EndedAt        *time.Time         `bson:"ended_at,omitempty"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ImportJobStatus là trạng thái của một lần import user
+type ImportJobStatus string
+
+const (
+	// ImportJobPending job đã được tạo, chưa bắt đầu xử lý
+	ImportJobPending ImportJobStatus = "pending"
+	// ImportJobRunning job đang validate / insert
+	ImportJobRunning ImportJobStatus = "running"
+	// ImportJobCompleted job chạy xong (dry-run xong, hoặc đã insert toàn bộ)
+	ImportJobCompleted ImportJobStatus = "completed"
+	// ImportJobFailed file có dòng lỗi hoặc gặp lỗi hệ thống, không user nào được insert
+	ImportJobFailed ImportJobStatus = "failed"
+)
+
+// ImportJob là kết quả (hoặc tiến độ) của một lần import user từ CSV
+type ImportJob struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	CreatedBy string             `bson:"created_by"` // UserID của người import, chỉ người này được xem job
+	Status    ImportJobStatus    `bson:"status"`
+	DryRun    bool               `bson:"dry_run"`
+	Total     int                `bson:"total"`     // Tổng số dòng dữ liệu
+	Processed int                `bson:"processed"` // Số dòng đã validate
+	Created   int                `bson:"created"`   // Số user đã insert
+	// Errors là lỗi theo từng dòng, field có dạng rows[<dòng>].<cột>
+	Errors []ImportError  `bson:"errors,omitempty"`
+	Users  []ImportedUser `bson:"users,omitempty"`
+	Error  string         `bson:"error,omitempty"` // Lỗi hệ thống (không phải lỗi dữ liệu)
+	// Các mốc thời gian
+	CreatedAt  time.Time  `bson:"created_at"`
+	UpdatedAt  time.Time  `bson:"updated_at"`
+	FinishedAt *time.Time `bson:"finished_at,omitempty"`
+}
+
+// ImportError là lỗi validate của một ô trong file CSV
+type ImportError struct {
+	Field    string   `bson:"field"`
+	Messages []string `bson:"messages"`
+}
+
+// ImportedUser là một dòng hợp lệ: user sẽ được tạo (dry-run) hoặc đã được tạo (commit)
+type ImportedUser struct {
+	Line         int                 `bson:"line"`
+	UserID       primitive.ObjectID  `bson:"user_id,omitempty"` // Rỗng khi dry-run
+	Username     string              `bson:"username"`
+	Email        string              `bson:"email"`
+	Role         Role                `bson:"role"`
+	ShopID       primitive.ObjectID  `bson:"shop_id"`
+	RegionID     primitive.ObjectID  `bson:"region_id"`
+	BranchID     primitive.ObjectID  `bson:"branch_id"`
+	DepartmentID *primitive.ObjectID `bson:"department_id,omitempty"`
+	// TemporaryPassword là mật khẩu sinh tự động khi dòng CSV không có password.
+	// Import chạy ngay chỉ trả về trong response; job chạy nền lưu bản mã hóa, trả về một lần rồi bị xóa khỏi job
+	TemporaryPassword string `bson:"temporary_password,omitempty"`
+}
+
+// IsFinished kiểm tra job đã chạy xong chưa (thành công hoặc thất bại)
+func (j ImportJob) IsFinished() bool {
+	return j.Status == ImportJobCompleted || j.Status == ImportJobFailed
+}
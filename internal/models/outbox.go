@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"thuchanhgolang/pkg/event"
+)
+
+// OutboxEvent là event đã được ghi vào outbox cùng transaction với thay đổi,
+// chờ dispatcher gửi đi
+type OutboxEvent struct {
+	event.Event   `bson:",inline"`
+	Attempts      int        `bson:"attempts"`               // Số lần gửi thất bại
+	NextAttemptAt time.Time  `bson:"next_attempt_at"`        // Chưa tới thời điểm này thì chưa gửi lại
+	DeliveredAt   *time.Time `bson:"delivered_at,omitempty"` // Khác nil khi đã gửi thành công
+	DeadAt        *time.Time `bson:"dead_at,omitempty"`      // Khác nil khi hết số lần thử, event nằm lại để kiểm tra và không được gửi nữa
+	LastError     string     `bson:"last_error,omitempty"`   // Lỗi của lần gửi gần nhất
+}
+
+// AggregateKey là khóa của aggregate, event cùng khóa phải được gửi theo đúng thứ tự
+func (e OutboxEvent) AggregateKey() string {
+	return e.AggregateType + "/" + e.AggregateID
+}
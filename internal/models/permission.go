@@ -0,0 +1,53 @@
+package models
+
+// Permission là quyền thao tác trên một loại tài nguyên, dạng "<resource>:<action>"
+type Permission string
+
+const (
+	PermissionShopRead        Permission = "shop:read"
+	PermissionShopWrite       Permission = "shop:write"
+	PermissionRegionRead      Permission = "region:read"
+	PermissionRegionWrite     Permission = "region:write"
+	PermissionBranchRead      Permission = "branch:read"
+	PermissionBranchWrite     Permission = "branch:write"
+	PermissionDepartmentRead  Permission = "department:read"
+	PermissionDepartmentWrite Permission = "department:write"
+	PermissionUserRead        Permission = "user:read"
+	PermissionUserWrite       Permission = "user:write"
+)
+
+// Permissions trả về các quyền hiệu lực của role.
+// Danh sách này phải khớp với các middleware Check*Access.
+func (r Role) Permissions() []Permission {
+	switch r {
+	case RoleManager:
+		return []Permission{
+			PermissionShopRead, PermissionShopWrite,
+			PermissionRegionRead, PermissionRegionWrite,
+			PermissionBranchRead, PermissionBranchWrite,
+			PermissionDepartmentRead, PermissionDepartmentWrite,
+			PermissionUserRead, PermissionUserWrite,
+		}
+	case RoleRegionManager:
+		return []Permission{
+			PermissionRegionRead, PermissionRegionWrite,
+			PermissionBranchRead, PermissionBranchWrite,
+			PermissionDepartmentRead, PermissionDepartmentWrite,
+			PermissionUserRead, PermissionUserWrite,
+		}
+	case RoleBranchManager:
+		return []Permission{
+			PermissionBranchRead, PermissionBranchWrite,
+			PermissionDepartmentRead, PermissionDepartmentWrite,
+			PermissionUserRead, PermissionUserWrite,
+		}
+	case RoleHeadOfDepartment:
+		return []Permission{
+			PermissionDepartmentRead, PermissionDepartmentWrite,
+			PermissionUserRead, PermissionUserWrite,
+		}
+	case RoleEmployee:
+		return []Permission{PermissionUserRead}
+	}
+	return nil
+}
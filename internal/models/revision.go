@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RevisionAction là thao tác đã thay thế snapshot của revision
+type RevisionAction string
+
+const (
+	// RevisionUpdate document bị sửa, snapshot là trạng thái trước khi sửa
+	RevisionUpdate RevisionAction = "update"
+	// RevisionDelete document bị xóa, snapshot là trạng thái cuối cùng trước khi xóa
+	RevisionDelete RevisionAction = "delete"
+)
+
+// Revision là snapshot đầy đủ của một document trước một lần sửa hoặc xóa.
+// Snapshot có hiệu lực từ updated_at của nó đến ChangedAt.
+type Revision struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	Entity    string             `bson:"entity"`    // Loại entity, trùng tên collection (shops, users, ...)
+	EntityID  primitive.ObjectID `bson:"entity_id"` // ID của document
+	Version   int                `bson:"version"`   // Version của snapshot
+	Action    RevisionAction     `bson:"action"`
+	Snapshot  bson.Raw           `bson:"snapshot"`
+	ChangedAt time.Time          `bson:"changed_at"`           // Thời điểm snapshot bị thay thế
+	ChangedBy string             `bson:"changed_by,omitempty"` // UserID của người sửa / xóa, rỗng khi do hệ thống ghi
+}
+
+// Decode đọc snapshot vào v (thường là model của entity)
+func (r Revision) Decode(v interface{}) error {
+	return bson.Unmarshal(r.Snapshot, v)
+}
@@ -33,3 +33,26 @@ func (r Role) IsValid() bool {
 func (r Role) String() string {
 	return string(r)
 }
+
+// rank trả về cấp bậc của role, số càng lớn quyền càng rộng (0 = role không hợp lệ)
+func (r Role) rank() int {
+	switch r {
+	case RoleManager:
+		return 5
+	case RoleRegionManager:
+		return 4
+	case RoleBranchManager:
+		return 3
+	case RoleHeadOfDepartment:
+		return 2
+	case RoleEmployee:
+		return 1
+	}
+	return 0
+}
+
+// CanAssign kiểm tra role r có được gán role other cho user khác không
+// Chỉ được gán role bằng hoặc thấp hơn role của chính mình
+func (r Role) CanAssign(other Role) bool {
+	return other.IsValid() && r.rank() >= other.rank()
+}
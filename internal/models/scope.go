@@ -1,6 +1,10 @@
 package models
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"thuchanhgolang/pkg/event"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // Scope is the scope of data and permissions.
 type Scope struct {
@@ -10,4 +14,77 @@ type Scope struct {
 	RegionID     *primitive.ObjectID `json:"region_id,omitempty"`
 	BranchID     *primitive.ObjectID `json:"branch_id,omitempty"`
 	DepartmentID *primitive.ObjectID `json:"department_id,omitempty"`
+
+	// ActorID là ID của admin thật khi request dùng token impersonation
+	ActorID string `json:"actor_id,omitempty"`
+	// ImpersonationID là ID của phiên impersonation
+	ImpersonationID string `json:"impersonation_id,omitempty"`
+}
+
+// Hierarchy là vị trí của một đối tượng trong cây tổ chức Shop → Region → Branch → Department
+type Hierarchy struct {
+	ShopID       primitive.ObjectID
+	RegionID     primitive.ObjectID
+	BranchID     primitive.ObjectID
+	DepartmentID *primitive.ObjectID
+}
+
+// Location chuyển vị trí thành vị trí của event, ID rỗng thì bỏ qua
+func (h Hierarchy) Location() event.Location {
+	loc := event.Location{
+		ShopID:   hexID(h.ShopID),
+		RegionID: hexID(h.RegionID),
+		BranchID: hexID(h.BranchID),
+	}
+	if h.DepartmentID != nil {
+		loc.DepartmentID = hexID(*h.DepartmentID)
+	}
+	return loc
+}
+
+// HierarchyOf đọc lại vị trí từ event, ID không hợp lệ được coi là rỗng
+func HierarchyOf(loc event.Location) Hierarchy {
+	shopID, _ := primitive.ObjectIDFromHex(loc.ShopID)
+	regionID, _ := primitive.ObjectIDFromHex(loc.RegionID)
+	branchID, _ := primitive.ObjectIDFromHex(loc.BranchID)
+	h := Hierarchy{ShopID: shopID, RegionID: regionID, BranchID: branchID}
+	if departmentID, err := primitive.ObjectIDFromHex(loc.DepartmentID); err == nil {
+		h.DepartmentID = &departmentID
+	}
+	return h
+}
+
+// hexID trả về dạng hex của ID, rỗng khi ID chưa có giá trị
+func hexID(id primitive.ObjectID) string {
+	if id.IsZero() {
+		return ""
+	}
+	return id.Hex()
+}
+
+// IsImpersonated kiểm tra request có đang dùng token impersonation không
+func (sc Scope) IsImpersonated() bool {
+	return sc.ActorID != ""
+}
+
+// Contains kiểm tra vị trí h có nằm trong phạm vi dữ liệu của scope không
+// Manager → cùng shop, RegionManager → cùng region, BranchManager/Employee → cùng branch,
+// HeadOfDepartment → cùng department
+func (sc Scope) Contains(h Hierarchy) bool {
+	switch sc.Role {
+	case RoleManager:
+		return sameID(sc.ShopID, h.ShopID)
+	case RoleRegionManager:
+		return sameID(sc.ShopID, h.ShopID) && sameID(sc.RegionID, h.RegionID)
+	case RoleBranchManager, RoleEmployee:
+		return sameID(sc.ShopID, h.ShopID) && sameID(sc.BranchID, h.BranchID)
+	case RoleHeadOfDepartment:
+		return h.DepartmentID != nil && sameID(sc.DepartmentID, *h.DepartmentID)
+	}
+	return false
+}
+
+// sameID so sánh ID trong scope (có thể nil) với ID của đối tượng
+func sameID(scopeID *primitive.ObjectID, id primitive.ObjectID) bool {
+	return scopeID != nil && !id.IsZero() && *scopeID == id
 }
@@ -11,4 +11,10 @@ type Shop struct {
 	Name      string             `bson:"name"`
 	Code      string             `bson:"code"`
 	CreatedAt time.Time          `bson:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at"`
+	// CreatedBy, UpdatedBy là UserID của người tạo và người sửa gần nhất, rỗng khi do hệ thống ghi
+	CreatedBy string `bson:"created_by,omitempty"`
+	UpdatedBy string `bson:"updated_by,omitempty"`
+	// Version tăng mỗi lần cập nhật, dùng làm ETag để phát hiện ghi đè đồng thời
+	Version int `bson:"version"`
 }
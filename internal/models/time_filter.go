@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// TimeFilter lọc danh sách theo khoảng created_at và updated_at, mốc nil thì không giới hạn
+type TimeFilter struct {
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+}
+
+// IsValid kiểm tra mốc bắt đầu không sau mốc kết thúc
+func (f TimeFilter) IsValid() bool {
+	return validRange(f.CreatedFrom, f.CreatedTo) && validRange(f.UpdatedFrom, f.UpdatedTo)
+}
+
+func validRange(from, to *time.Time) bool {
+	return from == nil || to == nil || !from.After(*to)
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -14,4 +16,28 @@ type User struct {
 	RegionID     primitive.ObjectID  `bson:"region_id"`
 	BranchID     primitive.ObjectID  `bson:"branch_id"`
 	DepartmentID *primitive.ObjectID `bson:"department_id,omitempty"`
+	// DeactivatedAt khác nil khi user bị vô hiệu hóa (ví dụ khi xóa dây chuyền đơn vị của user)
+	DeactivatedAt *time.Time `bson:"deactivated_at,omitempty"`
+	CreatedAt     time.Time  `bson:"created_at"`
+	UpdatedAt     time.Time  `bson:"updated_at"`
+	// CreatedBy, UpdatedBy là UserID của người tạo và người sửa gần nhất, rỗng khi do hệ thống ghi
+	CreatedBy string `bson:"created_by,omitempty"`
+	UpdatedBy string `bson:"updated_by,omitempty"`
+	// Version tăng mỗi lần cập nhật, dùng làm ETag để phát hiện ghi đè đồng thời
+	Version int `bson:"version"`
+}
+
+// Hierarchy trả về vị trí của user trong cây tổ chức
+func (u User) Hierarchy() Hierarchy {
+	return Hierarchy{
+		ShopID:       u.ShopID,
+		RegionID:     u.RegionID,
+		BranchID:     u.BranchID,
+		DepartmentID: u.DepartmentID,
+	}
+}
+
+// IsActive kiểm tra user còn hoạt động không
+func (u User) IsActive() bool {
+	return u.DeactivatedAt == nil
 }
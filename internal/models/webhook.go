@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"thuchanhgolang/pkg/event"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WebhookAllEvents là loại event đặc biệt, webhook đăng ký loại này nhận mọi event
+const WebhookAllEvents = "*"
+
+// Webhook là đăng ký nhận event của một shop qua HTTP POST
+type Webhook struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	ShopID     primitive.ObjectID `bson:"shop_id"`
+	URL        string             `bson:"url"`
+	EventTypes []string           `bson:"event_types"` // Loại event cần nhận, "*" → mọi event
+	Secret     string             `bson:"secret"`      // Khóa ký HMAC, chỉ trả về cho client khi tạo
+	Active     bool               `bson:"active"`      // Tắt thì không nhận event mới
+	CreatedAt  time.Time          `bson:"created_at"`
+	UpdatedAt  time.Time          `bson:"updated_at"`
+	// CreatedBy, UpdatedBy là UserID của người tạo và người sửa gần nhất
+	CreatedBy string `bson:"created_by,omitempty"`
+	UpdatedBy string `bson:"updated_by,omitempty"`
+	// Version tăng mỗi lần cập nhật, dùng làm ETag để phát hiện ghi đè đồng thời
+	Version int `bson:"version"`
+}
+
+// Subscribes kiểm tra webhook có nhận loại event này không
+func (w Webhook) Subscribes(eventType string) bool {
+	for _, t := range w.EventTypes {
+		if t == eventType || t == WebhookAllEvents {
+			return true
+		}
+	}
+	return false
+}
+
+// DeliveryStatus là trạng thái gửi một event tới webhook
+type DeliveryStatus string
+
+const (
+	// DeliveryPending chưa gửi được, đang chờ gửi (lần đầu hoặc gửi lại)
+	DeliveryPending DeliveryStatus = "pending"
+	// DeliverySucceeded webhook đã trả về 2xx
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	// DeliveryDead thất bại quá số lần cho phép (dead letter), chỉ gửi lại khi có yêu cầu redeliver
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+// IsValid kiểm tra trạng thái có được hỗ trợ không
+func (s DeliveryStatus) IsValid() bool {
+	switch s {
+	case DeliveryPending, DeliverySucceeded, DeliveryDead:
+		return true
+	}
+	return false
+}
+
+// WebhookDelivery là một lần gửi event tới webhook, cũng là dòng trong delivery log
+type WebhookDelivery struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	WebhookID      primitive.ObjectID `bson:"webhook_id"`
+	ShopID         primitive.ObjectID `bson:"shop_id"`
+	Event          event.Event        `bson:"event"` // Nội dung được POST tới webhook
+	Status         DeliveryStatus     `bson:"status"`
+	Attempts       int                `bson:"attempts"`                   // Số lần đã gửi
+	NextAttemptAt  time.Time          `bson:"next_attempt_at"`            // Chưa tới thời điểm này thì chưa gửi lại
+	LastStatusCode int                `bson:"last_status_code,omitempty"` // HTTP status của lần gửi gần nhất, 0 khi không kết nối được
+	LastError      string             `bson:"last_error,omitempty"`       // Lỗi của lần gửi gần nhất
+	DeliveredAt    *time.Time         `bson:"delivered_at,omitempty"`     // Khác nil khi đã gửi thành công
+	CreatedAt      time.Time          `bson:"created_at"`
+	UpdatedAt      time.Time          `bson:"updated_at"`
+}
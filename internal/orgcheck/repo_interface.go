@@ -0,0 +1,19 @@
+package orgcheck
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/models"
+)
+
+// Repository đọc toàn bộ cây tổ chức để kiểm tra
+type Repository interface {
+	ListShops(ctx context.Context) ([]models.Shop, error)
+	ListRegions(ctx context.Context) ([]models.Region, error)
+	ListBranches(ctx context.Context) ([]models.Branch, error)
+	ListDepartments(ctx context.Context) ([]models.Department, error)
+	ListUsers(ctx context.Context) ([]models.User, error)
+
+	// UpdateUserHierarchy ghi lại shop_id, region_id, branch_id (và department_id nếu có) của user
+	UpdateUserHierarchy(ctx context.Context, opts UpdateUserHierarchyOptions) error
+}
@@ -0,0 +1,12 @@
+package orgcheck
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// UpdateUserHierarchyOptions là options để ghi lại hierarchy của user
+type UpdateUserHierarchyOptions struct {
+	UserID       primitive.ObjectID
+	ShopID       primitive.ObjectID
+	RegionID     primitive.ObjectID
+	BranchID     primitive.ObjectID
+	DepartmentID *primitive.ObjectID // nil thì giữ nguyên department_id
+}
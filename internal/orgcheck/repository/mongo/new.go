@@ -0,0 +1,21 @@
+package mongo
+
+import (
+	"thuchanhgolang/internal/orgcheck"
+	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/mongo"
+)
+
+// implRepository là implementation của orgcheck.Repository
+type implRepository struct {
+	l  log.Logger     // Logger để ghi log
+	db mongo.Database // Database connection
+}
+
+// NewRepository tạo một orgcheck repository mới
+func NewRepository(l log.Logger, db mongo.Database) orgcheck.Repository {
+	return &implRepository{
+		l:  l,
+		db: db,
+	}
+}
@@ -0,0 +1,99 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"thuchanhgolang/internal/history"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/orgcheck"
+	"thuchanhgolang/pkg/mongo"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// findAll đọc toàn bộ document của collection vào result
+func (repo implRepository) findAll(ctx context.Context, collection string, result interface{}) error {
+	cursor, err := repo.db.Collection(collection).Find(ctx, bson.M{})
+	if err != nil {
+		repo.l.Errorf(ctx, "orgcheck.mongo.findAll.Find(%s): %v", collection, err)
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, result); err != nil {
+		repo.l.Errorf(ctx, "orgcheck.mongo.findAll.All(%s): %v", collection, err)
+		return err
+	}
+	return nil
+}
+
+// ListShops đọc toàn bộ shop
+func (repo implRepository) ListShops(ctx context.Context) ([]models.Shop, error) {
+	var shops []models.Shop
+	err := repo.findAll(ctx, "shops", &shops)
+	return shops, err
+}
+
+// ListRegions đọc toàn bộ region
+func (repo implRepository) ListRegions(ctx context.Context) ([]models.Region, error) {
+	var regions []models.Region
+	err := repo.findAll(ctx, "regions", &regions)
+	return regions, err
+}
+
+// ListBranches đọc toàn bộ branch
+func (repo implRepository) ListBranches(ctx context.Context) ([]models.Branch, error) {
+	var branches []models.Branch
+	err := repo.findAll(ctx, "branches", &branches)
+	return branches, err
+}
+
+// ListDepartments đọc toàn bộ department
+func (repo implRepository) ListDepartments(ctx context.Context) ([]models.Department, error) {
+	var departments []models.Department
+	err := repo.findAll(ctx, "departments", &departments)
+	return departments, err
+}
+
+// ListUsers đọc toàn bộ user
+func (repo implRepository) ListUsers(ctx context.Context) ([]models.User, error) {
+	var users []models.User
+	err := repo.findAll(ctx, "users", &users)
+	return users, err
+}
+
+// UpdateUserHierarchy ghi lại hierarchy của user, trạng thái trước khi sửa được lưu vào lịch sử
+func (repo implRepository) UpdateUserHierarchy(ctx context.Context, opts orgcheck.UpdateUserHierarchyOptions) error {
+	now := time.Now()
+	set := bson.M{
+		"shop_id":   opts.ShopID,
+		"region_id": opts.RegionID,
+		"branch_id": opts.BranchID,
+		// Sửa tự động bởi orgcheck nên không có updated_by
+		"updated_at": now,
+	}
+	if opts.DepartmentID != nil {
+		set["department_id"] = *opts.DepartmentID
+	}
+
+	var prior bson.Raw
+	findOpts := options.FindOneAndUpdate().SetProjection(history.UserProjection)
+	err := repo.db.Collection("users").FindOneAndUpdate(ctx, bson.M{"_id": opts.UserID}, bson.M{"$set": set, "$inc": bson.M{"version": 1}}, findOpts).Decode(&prior)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// User đã bị xóa trong lúc kiểm tra thì không còn gì để sửa
+		return nil
+	}
+	if err != nil {
+		repo.l.Errorf(ctx, "orgcheck.mongo.UpdateUserHierarchy.FindOneAndUpdate: %v", err)
+		return err
+	}
+
+	if err := history.RecordRevisions(ctx, repo.db, history.EntityUser, models.RevisionUpdate, "", now, prior); err != nil {
+		repo.l.Errorf(ctx, "orgcheck.mongo.UpdateUserHierarchy.history.RecordRevisions: %v", err)
+		return err
+	}
+	return nil
+}
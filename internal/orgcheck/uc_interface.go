@@ -0,0 +1,8 @@
+package orgcheck
+
+import "context"
+
+// Usecase kiểm tra tính nhất quán của cây tổ chức Shop → Region → Branch → Department → User
+type Usecase interface {
+	Check(ctx context.Context, input CheckInput) (Report, error)
+}
@@ -0,0 +1,61 @@
+package orgcheck
+
+import "time"
+
+// IssueKind là loại lỗi dữ liệu
+type IssueKind string
+
+const (
+	// KindOrphan: bản ghi trỏ tới cha không còn tồn tại
+	KindOrphan IssueKind = "orphan"
+	// KindMismatch: ID lưu sẵn trên user khác với ID suy ra từ cây tổ chức
+	KindMismatch IssueKind = "mismatch"
+)
+
+// CheckInput là input của lần kiểm tra
+type CheckInput struct {
+	Fix bool // Sửa các lỗi mismatch bằng cách resolve lại hierarchy
+}
+
+// Issue là một lỗi dữ liệu tìm thấy
+type Issue struct {
+	Kind       IssueKind `json:"kind"`
+	Collection string    `json:"collection"`
+	ID         string    `json:"id"`
+	Field      string    `json:"field"`
+	Actual     string    `json:"actual"`
+	Expected   string    `json:"expected,omitempty"`
+	Message    string    `json:"message"`
+	Fixable    bool      `json:"fixable"`
+	Fixed      bool      `json:"fixed"`
+	FixError   string    `json:"fix_error,omitempty"`
+}
+
+// Counts là số bản ghi đã quét của từng collection
+type Counts struct {
+	Shops       int `json:"shops"`
+	Regions     int `json:"regions"`
+	Branches    int `json:"branches"`
+	Departments int `json:"departments"`
+	Users       int `json:"users"`
+	// SkippedUsers là số user đã bị vô hiệu hóa (được phép trỏ tới đơn vị đã xóa)
+	SkippedUsers int `json:"skipped_users"`
+}
+
+// Summary là tổng hợp kết quả
+type Summary struct {
+	Total      int `json:"total"`
+	Orphans    int `json:"orphans"`
+	Mismatches int `json:"mismatches"`
+	Fixed      int `json:"fixed"`
+	Unresolved int `json:"unresolved"`
+}
+
+// Report là báo cáo dạng JSON của một lần kiểm tra
+type Report struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Fix         bool      `json:"fix"`
+	Scanned     Counts    `json:"scanned"`
+	Summary     Summary   `json:"summary"`
+	Issues      []Issue   `json:"issues"`
+}
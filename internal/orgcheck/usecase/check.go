@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/orgcheck"
+	"thuchanhgolang/internal/user/repository/query"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// tree là toàn bộ cây tổ chức được nạp vào bộ nhớ, đánh index theo ID
+type tree struct {
+	shops       map[primitive.ObjectID]models.Shop
+	regions     map[primitive.ObjectID]models.Region
+	branches    map[primitive.ObjectID]models.Branch
+	departments map[primitive.ObjectID]models.Department
+	users       []models.User
+}
+
+// Check quét toàn bộ collection, báo cáo bản ghi mồ côi và ID lưu sẵn bị lệch.
+// Khi Fix = true, user bị lệch được resolve lại qua query service rồi ghi lại.
+func (uc *implUsecase) Check(ctx context.Context, input orgcheck.CheckInput) (orgcheck.Report, error) {
+	// Bước 1: Nạp cây tổ chức
+	t, err := uc.load(ctx)
+	if err != nil {
+		return orgcheck.Report{}, err
+	}
+
+	report := orgcheck.Report{
+		GeneratedAt: time.Now(),
+		Fix:         input.Fix,
+		Scanned: orgcheck.Counts{
+			Shops:       len(t.shops),
+			Regions:     len(t.regions),
+			Branches:    len(t.branches),
+			Departments: len(t.departments),
+			Users:       len(t.users),
+		},
+		Issues: []orgcheck.Issue{},
+	}
+
+	// Bước 2: Kiểm tra các đơn vị tổ chức
+	report.Issues = append(report.Issues, t.checkUnits()...)
+
+	// Bước 3: Kiểm tra user (và sửa nếu cần)
+	for _, u := range t.users {
+		if !u.IsActive() {
+			report.Scanned.SkippedUsers++
+			continue
+		}
+
+		issues := t.checkUser(u)
+		if input.Fix && hasFixable(issues) {
+			uc.fixUser(ctx, u, issues)
+		}
+		report.Issues = append(report.Issues, issues...)
+	}
+
+	// Bước 4: Tổng hợp
+	report.Summary = summarize(report.Issues)
+
+	return report, nil
+}
+
+// load đọc toàn bộ collection vào bộ nhớ
+func (uc *implUsecase) load(ctx context.Context) (tree, error) {
+	t := tree{
+		shops:       map[primitive.ObjectID]models.Shop{},
+		regions:     map[primitive.ObjectID]models.Region{},
+		branches:    map[primitive.ObjectID]models.Branch{},
+		departments: map[primitive.ObjectID]models.Department{},
+	}
+
+	shops, err := uc.repo.ListShops(ctx)
+	if err != nil {
+		uc.l.Errorf(ctx, "orgcheck.usecase.load.ListShops: %v", err)
+		return tree{}, err
+	}
+	for _, s := range shops {
+		t.shops[s.ID] = s
+	}
+
+	regions, err := uc.repo.ListRegions(ctx)
+	if err != nil {
+		uc.l.Errorf(ctx, "orgcheck.usecase.load.ListRegions: %v", err)
+		return tree{}, err
+	}
+	for _, r := range regions {
+		t.regions[r.ID] = r
+	}
+
+	branches, err := uc.repo.ListBranches(ctx)
+	if err != nil {
+		uc.l.Errorf(ctx, "orgcheck.usecase.load.ListBranches: %v", err)
+		return tree{}, err
+	}
+	for _, b := range branches {
+		t.branches[b.ID] = b
+	}
+
+	departments, err := uc.repo.ListDepartments(ctx)
+	if err != nil {
+		uc.l.Errorf(ctx, "orgcheck.usecase.load.ListDepartments: %v", err)
+		return tree{}, err
+	}
+	for _, d := range departments {
+		t.departments[d.ID] = d
+	}
+
+	t.users, err = uc.repo.ListUsers(ctx)
+	if err != nil {
+		uc.l.Errorf(ctx, "orgcheck.usecase.load.ListUsers: %v", err)
+		return tree{}, err
+	}
+
+	return t, nil
+}
+
+// checkUnits tìm region, branch, department có cha không còn tồn tại
+func (t tree) checkUnits() []orgcheck.Issue {
+	var issues []orgcheck.Issue
+
+	for _, r := range t.regions {
+		if _, ok := t.shops[r.ShopID]; !ok {
+			issues = append(issues, orphan("regions", r.ID, "shop_id", r.ShopID, "shop does not exist"))
+		}
+	}
+	for _, b := range t.branches {
+		if _, ok := t.regions[b.RegionID]; !ok {
+			issues = append(issues, orphan("branches", b.ID, "region_id", b.RegionID, "region does not exist"))
+		}
+	}
+	for _, d := range t.departments {
+		if _, ok := t.branches[d.BranchID]; !ok {
+			issues = append(issues, orphan("departments", d.ID, "branch_id", d.BranchID, "branch does not exist"))
+		}
+	}
+
+	// Map không có thứ tự, sắp xếp để báo cáo giữa các lần chạy giống nhau
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Collection != issues[j].Collection {
+			return issues[i].Collection < issues[j].Collection
+		}
+		return issues[i].ID < issues[j].ID
+	})
+
+	return issues
+}
+
+// checkUser suy ra hierarchy đúng của user từ department (hoặc branch) rồi so với ID đang lưu
+func (t tree) checkUser(u models.User) []orgcheck.Issue {
+	// Bước 1: Xác định branch gốc để suy ra hierarchy
+	branchID := u.BranchID
+	if u.DepartmentID != nil {
+		d, ok := t.departments[*u.DepartmentID]
+		if !ok {
+			return []orgcheck.Issue{orphan("users", u.ID, "department_id", *u.DepartmentID, "department does not exist")}
+		}
+		branchID = d.BranchID
+	}
+
+	// Bước 2: Đi ngược lên branch → region → shop
+	b, ok := t.branches[branchID]
+	if !ok {
+		return []orgcheck.Issue{orphan("users", u.ID, "branch_id", branchID, "branch does not exist")}
+	}
+	r, ok := t.regions[b.RegionID]
+	if !ok {
+		return []orgcheck.Issue{orphan("users", u.ID, "region_id", b.RegionID, "region of branch does not exist")}
+	}
+	if _, ok := t.shops[r.ShopID]; !ok {
+		return []orgcheck.Issue{orphan("users", u.ID, "shop_id", r.ShopID, "shop of region does not exist")}
+	}
+
+	// Bước 3: So sánh với ID đang lưu trên user
+	var issues []orgcheck.Issue
+	for _, f := range []struct {
+		field            string
+		actual, expected primitive.ObjectID
+	}{
+		{"branch_id", u.BranchID, b.ID},
+		{"region_id", u.RegionID, r.ID},
+		{"shop_id", u.ShopID, r.ShopID},
+	} {
+		if f.actual != f.expected {
+			issues = append(issues, orgcheck.Issue{
+				Kind:       orgcheck.KindMismatch,
+				Collection: "users",
+				ID:         u.ID.Hex(),
+				Field:      f.field,
+				Actual:     f.actual.Hex(),
+				Expected:   f.expected.Hex(),
+				Message:    fmt.Sprintf("%s does not match hierarchy", f.field),
+				Fixable:    true,
+			})
+		}
+	}
+
+	return issues
+}
+
+// fixUser resolve lại hierarchy của user qua query service rồi ghi lại, đánh dấu kết quả lên issues
+func (uc *implUsecase) fixUser(ctx context.Context, u models.User, issues []orgcheck.Issue) {
+	var (
+		result *query.CascadeResult
+		err    error
+	)
+	if u.DepartmentID != nil {
+		result, err = uc.queryService.ResolveFromDepartment(ctx, models.Scope{}, *u.DepartmentID)
+	} else {
+		result, err = uc.queryService.ResolveFromBranch(ctx, models.Scope{}, u.BranchID)
+	}
+
+	if err == nil {
+		err = uc.repo.UpdateUserHierarchy(ctx, orgcheck.UpdateUserHierarchyOptions{
+			UserID:       u.ID,
+			ShopID:       result.ShopID,
+			RegionID:     result.RegionID,
+			BranchID:     result.BranchID,
+			DepartmentID: result.DepartmentID,
+		})
+	}
+
+	for i := range issues {
+		if !issues[i].Fixable {
+			continue
+		}
+		if err != nil {
+			uc.l.Errorf(ctx, "orgcheck.usecase.fixUser(%s): %v", u.ID.Hex(), err)
+			issues[i].FixError = err.Error()
+			continue
+		}
+		issues[i].Fixed = true
+	}
+}
+
+// orphan tạo issue cho bản ghi trỏ tới cha không tồn tại
+func orphan(collection string, id primitive.ObjectID, field string, actual primitive.ObjectID, msg string) orgcheck.Issue {
+	return orgcheck.Issue{
+		Kind:       orgcheck.KindOrphan,
+		Collection: collection,
+		ID:         id.Hex(),
+		Field:      field,
+		Actual:     actual.Hex(),
+		Message:    msg,
+	}
+}
+
+func hasFixable(issues []orgcheck.Issue) bool {
+	for _, i := range issues {
+		if i.Fixable {
+			return true
+		}
+	}
+	return false
+}
+
+func summarize(issues []orgcheck.Issue) orgcheck.Summary {
+	s := orgcheck.Summary{Total: len(issues)}
+	for _, i := range issues {
+		switch i.Kind {
+		case orgcheck.KindOrphan:
+			s.Orphans++
+		case orgcheck.KindMismatch:
+			s.Mismatches++
+		}
+		if i.Fixed {
+			s.Fixed++
+		} else {
+			s.Unresolved++
+		}
+	}
+	return s
+}
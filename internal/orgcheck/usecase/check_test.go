@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/orgcheck"
+	"thuchanhgolang/internal/user/repository/query"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCheck(t *testing.T) {
+	shop := models.Shop{ID: primitive.NewObjectID()}
+	r1 := models.Region{ID: primitive.NewObjectID(), ShopID: shop.ID}
+	r2 := models.Region{ID: primitive.NewObjectID(), ShopID: shop.ID}
+	b := models.Branch{ID: primitive.NewObjectID(), RegionID: r2.ID}
+	d := models.Department{ID: primitive.NewObjectID(), BranchID: b.ID}
+	deletedBranch := primitive.NewObjectID()
+	deactivatedAt := time.Now()
+
+	newRepo := func() *mockRepository {
+		return &mockRepository{
+			shops:       []models.Shop{shop},
+			regions:     []models.Region{r1, r2},
+			branches:    []models.Branch{b},
+			departments: []models.Department{d, {ID: primitive.NewObjectID(), BranchID: deletedBranch}},
+			users: []models.User{
+				// Đúng hierarchy
+				{ID: primitive.NewObjectID(), ShopID: shop.ID, RegionID: r2.ID, BranchID: b.ID, DepartmentID: &d.ID},
+				// region_id bị lệch (branch đã chuyển sang r2)
+				{ID: primitive.NewObjectID(), ShopID: shop.ID, RegionID: r1.ID, BranchID: b.ID},
+				// Trỏ tới branch đã xóa
+				{ID: primitive.NewObjectID(), ShopID: shop.ID, RegionID: r1.ID, BranchID: deletedBranch},
+				// Đã vô hiệu hóa, bỏ qua
+				{ID: primitive.NewObjectID(), BranchID: deletedBranch, DeactivatedAt: &deactivatedAt},
+			},
+		}
+	}
+	resolver := &mockQueryService{
+		resolveFromBranchFunc: func(ctx context.Context, sc models.Scope, branchID primitive.ObjectID) (*query.CascadeResult, error) {
+			return &query.CascadeResult{ShopID: shop.ID, RegionID: r2.ID, BranchID: b.ID}, nil
+		},
+	}
+
+	t.Run("report only", func(t *testing.T) {
+		repo := newRepo()
+		uc := &implUsecase{repo: repo, queryService: resolver, l: &mockLogger{}}
+		report, err := uc.Check(context.Background(), orgcheck.CheckInput{})
+
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if report.Summary.Orphans != 2 {
+			t.Errorf("Mong đợi 2 bản ghi mồ côi, nhận được %d: %+v", report.Summary.Orphans, report.Issues)
+		}
+		if report.Summary.Mismatches != 1 {
+			t.Errorf("Mong đợi 1 lỗi lệch ID, nhận được %d: %+v", report.Summary.Mismatches, report.Issues)
+		}
+		if report.Scanned.SkippedUsers != 1 {
+			t.Errorf("Mong đợi bỏ qua 1 user bị vô hiệu hóa, nhận được %d", report.Scanned.SkippedUsers)
+		}
+		if len(repo.updates) != 0 {
+			t.Error("Không được sửa dữ liệu khi không có --fix")
+		}
+	})
+
+	t.Run("fix mismatched users", func(t *testing.T) {
+		repo := newRepo()
+		uc := &implUsecase{repo: repo, queryService: resolver, l: &mockLogger{}}
+		report, err := uc.Check(context.Background(), orgcheck.CheckInput{Fix: true})
+
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if len(repo.updates) != 1 || repo.updates[0].RegionID != r2.ID {
+			t.Fatalf("Mong đợi 1 user được ghi lại region %s, nhận được %+v", r2.ID.Hex(), repo.updates)
+		}
+		if report.Summary.Fixed != 1 || report.Summary.Unresolved != 2 {
+			t.Errorf("Tổng hợp không đúng: %+v", report.Summary)
+		}
+	})
+}
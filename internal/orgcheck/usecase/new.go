@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"thuchanhgolang/internal/orgcheck"
+	"thuchanhgolang/internal/user/repository/query"
+	"thuchanhgolang/pkg/log"
+)
+
+// implUsecase là implementation của orgcheck.Usecase
+type implUsecase struct {
+	l            log.Logger          // Logger
+	repo         orgcheck.Repository // Đọc toàn bộ cây tổ chức
+	queryService query.Service       // Resolve lại hierarchy khi sửa user
+}
+
+// NewUsecase tạo orgcheck usecase mới
+func NewUsecase(l log.Logger, repo orgcheck.Repository, queryService query.Service) orgcheck.Usecase {
+	return &implUsecase{
+		l:            l,
+		repo:         repo,
+		queryService: queryService,
+	}
+}
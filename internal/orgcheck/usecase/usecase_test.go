@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/orgcheck"
+	"thuchanhgolang/internal/user/repository/query"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// mockRepository trả về dữ liệu cố định và ghi lại các lần update
+type mockRepository struct {
+	shops       []models.Shop
+	regions     []models.Region
+	branches    []models.Branch
+	departments []models.Department
+	users       []models.User
+	updates     []orgcheck.UpdateUserHierarchyOptions
+}
+
+func (m *mockRepository) ListShops(ctx context.Context) ([]models.Shop, error) {
+	return m.shops, nil
+}
+
+func (m *mockRepository) ListRegions(ctx context.Context) ([]models.Region, error) {
+	return m.regions, nil
+}
+
+func (m *mockRepository) ListBranches(ctx context.Context) ([]models.Branch, error) {
+	return m.branches, nil
+}
+
+func (m *mockRepository) ListDepartments(ctx context.Context) ([]models.Department, error) {
+	return m.departments, nil
+}
+
+func (m *mockRepository) ListUsers(ctx context.Context) ([]models.User, error) {
+	return m.users, nil
+}
+
+func (m *mockRepository) UpdateUserHierarchy(ctx context.Context, opts orgcheck.UpdateUserHierarchyOptions) error {
+	m.updates = append(m.updates, opts)
+	return nil
+}
+
+// mockQueryService giả lập query.Service
+type mockQueryService struct {
+	resolveFromDepartmentFunc func(context.Context, models.Scope, primitive.ObjectID) (*query.CascadeResult, error)
+	resolveFromBranchFunc     func(context.Context, models.Scope, primitive.ObjectID) (*query.CascadeResult, error)
+}
+
+func (m *mockQueryService) ResolveFromDepartment(ctx context.Context, sc models.Scope, departmentID primitive.ObjectID) (*query.CascadeResult, error) {
+	if m.resolveFromDepartmentFunc != nil {
+		return m.resolveFromDepartmentFunc(ctx, sc, departmentID)
+	}
+	return &query.CascadeResult{}, nil
+}
+
+func (m *mockQueryService) ResolveFromBranch(ctx context.Context, sc models.Scope, branchID primitive.ObjectID) (*query.CascadeResult, error) {
+	if m.resolveFromBranchFunc != nil {
+		return m.resolveFromBranchFunc(ctx, sc, branchID)
+	}
+	return &query.CascadeResult{}, nil
+}
+
+type mockLogger struct{}
+
+func (m *mockLogger) Debug(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Debugf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Info(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Infof(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Warn(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Warnf(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Error(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Errorf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Fatal(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Fatalf(ctx context.Context, template string, arg ...any) {}
@@ -0,0 +1,25 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/event"
+)
+
+// Repository lưu event vào outbox và theo dõi trạng thái gửi.
+// Publish ghi event bằng ctx được truyền vào, usecase gọi trong transaction
+// thì event được commit cùng với thay đổi.
+//
+//go:generate mockery --name=Repository
+type Repository interface {
+	event.Publisher
+	// ListPending lấy tối đa opts.Limit event chưa gửi và chưa vào dead letter, cũ nhất đứng trước,
+	// kể cả event đang chờ backoff để dispatcher giữ đúng thứ tự theo aggregate
+	ListPending(ctx context.Context, opts ListPendingOptions) ([]models.OutboxEvent, error)
+	// MarkDelivered đánh dấu event đã gửi thành công
+	MarkDelivered(ctx context.Context, id string, at time.Time) error
+	// MarkFailed ghi nhận lần gửi thất bại và thời điểm gửi lại (hoặc dead letter)
+	MarkFailed(ctx context.Context, opts MarkFailedOptions) error
+}
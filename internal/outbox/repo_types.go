@@ -0,0 +1,27 @@
+package outbox
+
+import (
+	"time"
+
+	"thuchanhgolang/internal/models"
+)
+
+// Collection là collection lưu event chờ gửi
+const Collection = "outbox"
+
+// ListPendingOptions là options để lấy event chưa gửi
+type ListPendingOptions struct {
+	Limit int64
+	// After là event cuối của trang trước, nil → đọc từ đầu.
+	// Dispatcher đọc tiếp trang sau khi cả trang đều thuộc aggregate đang bị chặn.
+	After *models.OutboxEvent
+}
+
+// MarkFailedOptions là options để ghi nhận một lần gửi thất bại
+type MarkFailedOptions struct {
+	ID            string
+	Attempts      int        // Tổng số lần đã thất bại, tính cả lần này
+	NextAttemptAt time.Time  // Thời điểm sớm nhất được gửi lại
+	DeadAt        *time.Time // Khác nil → hết số lần thử, event chuyển sang dead letter
+	LastError     string
+}
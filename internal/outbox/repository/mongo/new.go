@@ -0,0 +1,21 @@
+package mongo
+
+import (
+	"thuchanhgolang/internal/outbox"
+	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/mongo"
+)
+
+// implRepository là implementation của outbox.Repository
+type implRepository struct {
+	l  log.Logger     // Logger để ghi log
+	db mongo.Database // Database connection
+}
+
+// NewRepository tạo một outbox repository mới
+func NewRepository(l log.Logger, db mongo.Database) outbox.Repository {
+	return &implRepository{
+		l:  l,
+		db: db,
+	}
+}
@@ -0,0 +1,103 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/outbox"
+	"thuchanhgolang/pkg/event"
+	"thuchanhgolang/pkg/mongo"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// getOutboxCollection lấy collection outbox từ database
+func (repo implRepository) getOutboxCollection() mongo.Collection {
+	return repo.db.Collection(outbox.Collection)
+}
+
+// Publish ghi event vào outbox, event nằm trong transaction nếu ctx mang session
+func (repo implRepository) Publish(ctx context.Context, events ...event.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, 0, len(events))
+	for _, e := range events {
+		docs = append(docs, models.OutboxEvent{
+			Event:         e,
+			NextAttemptAt: e.OccurredAt,
+		})
+	}
+
+	if _, err := repo.getOutboxCollection().InsertMany(ctx, docs); err != nil {
+		repo.l.Errorf(ctx, "outbox.mongo.Publish.InsertMany: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// ListPending lấy các event chưa gửi theo thứ tự ghi, bắt đầu sau opts.After
+func (repo implRepository) ListPending(ctx context.Context, opts outbox.ListPendingOptions) ([]models.OutboxEvent, error) {
+	// delivered_at: nil khớp cả document chưa có field delivered_at
+	filter := bson.M{"delivered_at": nil, "dead_at": nil}
+	if opts.After != nil {
+		filter["$or"] = bson.A{
+			bson.M{"occurred_at": bson.M{"$gt": opts.After.OccurredAt}},
+			bson.M{"occurred_at": opts.After.OccurredAt, "_id": bson.M{"$gt": opts.After.ID}},
+		}
+	}
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "occurred_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(opts.Limit)
+
+	cursor, err := repo.getOutboxCollection().Find(ctx, filter, findOpts)
+	if err != nil {
+		repo.l.Errorf(ctx, "outbox.mongo.ListPending.Find: %v", err)
+		return nil, err
+	}
+
+	var events []models.OutboxEvent
+	if err := cursor.All(ctx, &events); err != nil {
+		repo.l.Errorf(ctx, "outbox.mongo.ListPending.All: %v", err)
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// MarkDelivered đánh dấu event đã gửi thành công
+func (repo implRepository) MarkDelivered(ctx context.Context, id string, at time.Time) error {
+	update := bson.M{
+		"$set":   bson.M{"delivered_at": at},
+		"$unset": bson.M{"last_error": ""},
+	}
+	if _, err := repo.getOutboxCollection().UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		repo.l.Errorf(ctx, "outbox.mongo.MarkDelivered.UpdateOne: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// MarkFailed ghi nhận lần gửi thất bại và thời điểm gửi lại, hết số lần thử thì chuyển sang dead letter
+func (repo implRepository) MarkFailed(ctx context.Context, opts outbox.MarkFailedOptions) error {
+	set := bson.M{
+		"attempts":        opts.Attempts,
+		"next_attempt_at": opts.NextAttemptAt,
+		"last_error":      opts.LastError,
+	}
+	if opts.DeadAt != nil {
+		set["dead_at"] = *opts.DeadAt
+	}
+	update := bson.M{"$set": set}
+	if _, err := repo.getOutboxCollection().UpdateOne(ctx, bson.M{"_id": opts.ID}, update); err != nil {
+		repo.l.Errorf(ctx, "outbox.mongo.MarkFailed.UpdateOne: %v", err)
+		return err
+	}
+
+	return nil
+}
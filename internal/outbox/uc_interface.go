@@ -0,0 +1,17 @@
+package outbox
+
+import "context"
+
+// Dispatcher đọc event từ outbox và gửi qua Publisher.
+// Event được gửi ít nhất một lần: gửi xong mới đánh dấu, lỗi khi đánh dấu thì event được gửi lại.
+// Event cùng aggregate được gửi theo thứ tự ghi, event lỗi chặn các event sau nó cho tới khi gửi được
+// hoặc hết số lần thử (chuyển sang dead letter). Aggregate bị chặn không làm các aggregate khác phải chờ.
+// Thứ tự chỉ được đảm bảo khi mỗi lúc có một dispatcher chạy.
+//
+//go:generate mockery --name=Dispatcher
+type Dispatcher interface {
+	// Run gửi event định kỳ cho tới khi ctx bị hủy
+	Run(ctx context.Context)
+	// Dispatch chạy một lượt gửi, trả về số event đã gửi thành công
+	Dispatch(ctx context.Context) (int, error)
+}
@@ -0,0 +1,48 @@
+package outbox
+
+import "time"
+
+// Giá trị mặc định của DispatcherOptions
+const (
+	DefaultBatchSize    = 100
+	DefaultPollInterval = time.Second
+	DefaultMinBackoff   = time.Second
+	DefaultMaxBackoff   = 5 * time.Minute
+	DefaultMaxAttempts  = 10
+	DefaultMaxPages     = 10
+)
+
+// DispatcherOptions cấu hình dispatcher, giá trị <= 0 được thay bằng mặc định
+type DispatcherOptions struct {
+	BatchSize    int64         // Số event tối đa đọc mỗi lượt
+	PollInterval time.Duration // Khoảng nghỉ giữa hai lượt
+	MinBackoff   time.Duration // Chờ sau lần thất bại đầu tiên, nhân đôi sau mỗi lần thất bại
+	MaxBackoff   time.Duration // Thời gian chờ tối đa giữa hai lần gửi lại
+	MaxAttempts  int           // Thất bại đủ số lần này thì chuyển sang dead letter, không chặn aggregate nữa
+	MaxPages     int           // Số trang BatchSize event tối đa đọc mỗi lượt khi các trang trước bị chặn
+}
+
+// Adjust thay các giá trị không hợp lệ bằng giá trị mặc định
+func (o *DispatcherOptions) Adjust() {
+	if o.BatchSize <= 0 {
+		o.BatchSize = DefaultBatchSize
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = DefaultPollInterval
+	}
+	if o.MinBackoff <= 0 {
+		o.MinBackoff = DefaultMinBackoff
+	}
+	if o.MaxBackoff < o.MinBackoff {
+		o.MaxBackoff = DefaultMaxBackoff
+		if o.MaxBackoff < o.MinBackoff {
+			o.MaxBackoff = o.MinBackoff
+		}
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = DefaultMaxAttempts
+	}
+	if o.MaxPages <= 0 {
+		o.MaxPages = DefaultMaxPages
+	}
+}
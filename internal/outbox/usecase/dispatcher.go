@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/outbox"
+	"thuchanhgolang/pkg/util"
+)
+
+// Run gửi event định kỳ cho tới khi ctx bị hủy
+func (d *implDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.Dispatch(ctx); err != nil && ctx.Err() == nil {
+			d.l.Errorf(ctx, "outbox.usecase.Run.Dispatch: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch gửi các event chưa gửi theo thứ tự ghi.
+// Flow: Lấy trang event chưa gửi -> Bỏ qua aggregate đang bị chặn -> Gửi -> Đánh dấu đã gửi hoặc hẹn gửi lại.
+// Trang đầy thì đọc tiếp trang sau, để event của aggregate đang bị chặn không chiếm hết lượt của các aggregate khác.
+func (d *implDispatcher) Dispatch(ctx context.Context) (int, error) {
+	now := d.now()
+	blocked := make(map[string]bool)
+	delivered, sent := 0, 0
+	var after *models.OutboxEvent
+
+	for page := 0; page < d.opts.MaxPages; page++ {
+		// Bước 1: Lấy event chưa gửi, cũ nhất đứng trước
+		pending, err := d.repo.ListPending(ctx, outbox.ListPendingOptions{Limit: d.opts.BatchSize, After: after})
+		if err != nil {
+			d.l.Errorf(ctx, "outbox.usecase.Dispatch.repo.ListPending: %v", err)
+			return delivered, err
+		}
+
+		for _, e := range pending {
+			key := e.AggregateKey()
+
+			// Bước 2: Event trước đó của aggregate chưa gửi được thì event sau phải chờ
+			if blocked[key] {
+				continue
+			}
+			if e.NextAttemptAt.After(now) {
+				blocked[key] = true
+				continue
+			}
+
+			// Bước 3: Gửi event, lỗi thì hẹn gửi lại với backoff tăng dần
+			sent++
+			if err := d.publisher.Publish(ctx, e.Event); err != nil {
+				d.markFailed(ctx, e, err, now, blocked)
+				continue
+			}
+
+			// Bước 4: Đánh dấu đã gửi. Lỗi ở bước này thì event sẽ được gửi lại ở lượt sau,
+			// nên các event sau của aggregate cũng phải chờ để không bị gửi vượt lên trước
+			if err := d.repo.MarkDelivered(ctx, e.ID, now); err != nil {
+				d.l.Errorf(ctx, "outbox.usecase.Dispatch.repo.MarkDelivered: %v", err)
+				blocked[key] = true
+				continue
+			}
+			delivered++
+		}
+
+		// Bước 5: Trang chưa đầy là đã đọc hết, đã gửi đủ BatchSize event là đủ việc cho lượt này
+		if int64(len(pending)) < d.opts.BatchSize || int64(sent) >= d.opts.BatchSize {
+			break
+		}
+		after = &pending[len(pending)-1]
+	}
+
+	return delivered, nil
+}
+
+// markFailed ghi nhận lần gửi lỗi và chặn aggregate của event.
+// Hết số lần thử thì event vào dead letter và aggregate không bị chặn ở các lượt sau.
+func (d *implDispatcher) markFailed(ctx context.Context, e models.OutboxEvent, sendErr error, now time.Time, blocked map[string]bool) {
+	blocked[e.AggregateKey()] = true
+	attempts := e.Attempts + 1
+	d.l.Warnf(ctx, "outbox.usecase.Dispatch.publisher.Publish: event %s (%s) attempt %d: %v", e.ID, e.Type, attempts, sendErr)
+
+	opts := outbox.MarkFailedOptions{
+		ID:            e.ID,
+		Attempts:      attempts,
+		NextAttemptAt: now.Add(d.backoff(attempts)),
+		LastError:     sendErr.Error(),
+	}
+	if attempts >= d.opts.MaxAttempts {
+		d.l.Errorf(ctx, "outbox.usecase.Dispatch: event %s (%s) moved to dead letter after %d attempts", e.ID, e.Type, attempts)
+		opts.DeadAt = &now
+	}
+	if err := d.repo.MarkFailed(ctx, opts); err != nil {
+		d.l.Errorf(ctx, "outbox.usecase.Dispatch.repo.MarkFailed: %v", err)
+	}
+}
+
+// backoff tính thời gian chờ sau lần thất bại thứ attempts: MinBackoff nhân đôi mỗi lần, tối đa MaxBackoff
+func (d *implDispatcher) backoff(attempts int) time.Duration {
+	return util.Backoff(d.opts.MinBackoff, d.opts.MaxBackoff, attempts)
+}
@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"thuchanhgolang/internal/outbox"
+	"thuchanhgolang/pkg/event"
+)
+
+// newTestDispatcher tạo dispatcher với đồng hồ cố định tại now
+func newTestDispatcher(repo outbox.Repository, publisher event.Publisher, now time.Time) *implDispatcher {
+	return newTestDispatcherWith(repo, publisher, now, outbox.DispatcherOptions{MinBackoff: time.Second, MaxBackoff: 4 * time.Second})
+}
+
+// newTestDispatcherWith tạo dispatcher với opts và đồng hồ cố định tại now
+func newTestDispatcherWith(repo outbox.Repository, publisher event.Publisher, now time.Time, opts outbox.DispatcherOptions) *implDispatcher {
+	opts.Adjust()
+	return &implDispatcher{l: &mockLogger{}, repo: repo, publisher: publisher, opts: opts, now: func() time.Time { return now }}
+}
+
+func TestDispatch(t *testing.T) {
+	branchMoved := event.New("branch.moved", "branch", "b1", "u1", nil)
+	branchUpdated := event.New("branch.updated", "branch", "b1", "u1", nil)
+	shopCreated := event.New("shop.created", "shop", "s1", "u1", nil)
+	now := time.Now()
+
+	t.Run("deliver in order and mark delivered", func(t *testing.T) {
+		repo := &mockRepository{}
+		_ = repo.Publish(context.Background(), branchMoved, shopCreated, branchUpdated)
+		publisher := event.NewMemoryPublisher()
+
+		n, err := newTestDispatcher(repo, publisher, now).Dispatch(context.Background())
+
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		got := publisher.Events()
+		if n != 3 || len(got) != 3 {
+			t.Fatalf("Mong đợi gửi 3 event, nhận được %d", len(got))
+		}
+		if got[0].ID != branchMoved.ID || got[2].ID != branchUpdated.ID {
+			t.Errorf("Event cùng aggregate phải được gửi theo thứ tự ghi: %+v", got)
+		}
+		if pending, _ := repo.ListPending(context.Background(), outbox.ListPendingOptions{Limit: 10}); len(pending) != 0 {
+			t.Errorf("Event đã gửi phải được đánh dấu, còn lại %d", len(pending))
+		}
+	})
+
+	t.Run("failure blocks the aggregate and schedules retry", func(t *testing.T) {
+		repo := &mockRepository{}
+		_ = repo.Publish(context.Background(), branchMoved, shopCreated, branchUpdated)
+		publisher := event.NewMemoryPublisher()
+		publisher.FailWith(errors.New("broker down"))
+
+		n, _ := newTestDispatcher(repo, publisher, now).Dispatch(context.Background())
+
+		failed := repo.get(branchMoved.ID)
+		if n != 0 || failed.Attempts != 1 || failed.LastError != "broker down" {
+			t.Errorf("Lần gửi lỗi phải được ghi nhận, nhận được %+v", failed)
+		}
+		if !failed.NextAttemptAt.Equal(now.Add(time.Second)) {
+			t.Errorf("Mong đợi gửi lại sau 1s, nhận được %v", failed.NextAttemptAt.Sub(now))
+		}
+		if repo.get(branchUpdated.ID).Attempts != 0 {
+			t.Error("Event sau của cùng aggregate không được gửi khi event trước lỗi")
+		}
+		if repo.get(shopCreated.ID).Attempts != 1 {
+			t.Error("Event của aggregate khác không bị chặn bởi event lỗi của branch")
+		}
+
+		// Broker hoạt động lại nhưng chưa tới lúc gửi lại: không event nào được gửi
+		publisher.FailWith(nil)
+		n, _ = newTestDispatcher(repo, publisher, now.Add(500*time.Millisecond)).Dispatch(context.Background())
+		if n != 0 || len(publisher.Events()) != 0 {
+			t.Errorf("Không được gửi trước thời điểm backoff, nhận được %+v", publisher.Events())
+		}
+
+		// Tới lúc gửi lại: event của branch được gửi đúng thứ tự
+		n, _ = newTestDispatcher(repo, publisher, now.Add(time.Second)).Dispatch(context.Background())
+		got := publisher.Events()
+		if n != 3 || len(got) != 3 || got[0].ID != branchMoved.ID || got[2].ID != branchUpdated.ID {
+			t.Errorf("Event của branch phải được gửi lại theo thứ tự, nhận được %+v", got)
+		}
+	})
+
+	t.Run("mark delivered error redelivers and keeps order", func(t *testing.T) {
+		failMark := true
+		repo := &mockRepository{
+			markDeliveredFunc: func(ctx context.Context, id string, at time.Time) error {
+				if failMark {
+					return errors.New("write conflict")
+				}
+				return nil
+			},
+		}
+		_ = repo.Publish(context.Background(), branchMoved, branchUpdated)
+		publisher := event.NewMemoryPublisher()
+
+		d := newTestDispatcher(repo, publisher, now)
+		n, _ := d.Dispatch(context.Background())
+		if got := publisher.Events(); n != 0 || len(got) != 1 || got[0].ID != branchMoved.ID {
+			t.Fatalf("Event sau không được gửi khi event trước chưa đánh dấu được, nhận được %+v", got)
+		}
+
+		failMark = false
+		n, _ = d.Dispatch(context.Background())
+		got := publisher.Events()
+		if n != 2 || len(got) != 3 || got[1].ID != branchMoved.ID || got[2].ID != branchUpdated.ID {
+			t.Errorf("Event phải được gửi lại ít nhất một lần và đúng thứ tự, nhận được %+v", got)
+		}
+	})
+}
+
+// failingPublisher từ chối event của một aggregate, gửi các event khác vào MemoryPublisher
+type failingPublisher struct {
+	*event.MemoryPublisher
+	aggregateID string
+}
+
+func (p *failingPublisher) Publish(ctx context.Context, events ...event.Event) error {
+	for _, e := range events {
+		if e.AggregateID == p.aggregateID {
+			return errors.New("consumer rejected")
+		}
+	}
+	return p.MemoryPublisher.Publish(ctx, events...)
+}
+
+func TestDispatchBlockedAggregate(t *testing.T) {
+	blocked := []event.Event{
+		event.New("branch.created", "branch", "b1", "u1", nil),
+		event.New("branch.updated", "branch", "b1", "u1", nil),
+		event.New("branch.moved", "branch", "b1", "u1", nil),
+	}
+	shopCreated := event.New("shop.created", "shop", "s1", "u1", nil)
+	now := time.Now()
+	opts := outbox.DispatcherOptions{BatchSize: 2, MinBackoff: time.Second, MaxBackoff: time.Second, MaxAttempts: 3}
+
+	repo := &mockRepository{}
+	_ = repo.Publish(context.Background(), append(blocked, shopCreated)...)
+	publisher := &failingPublisher{MemoryPublisher: event.NewMemoryPublisher(), aggregateID: "b1"}
+
+	// Lượt 1: cả trang đầu thuộc aggregate lỗi, event của shop ở trang sau vẫn được gửi
+	n, err := newTestDispatcherWith(repo, publisher, now, opts).Dispatch(context.Background())
+	if err != nil {
+		t.Fatalf("Không mong đợi lỗi: %v", err)
+	}
+	if got := publisher.Events(); n != 1 || len(got) != 1 || got[0].ID != shopCreated.ID {
+		t.Fatalf("Event của aggregate khác không được bị chặn, nhận được %+v", got)
+	}
+
+	// Các lượt sau: event đầu của branch thử lại đến hết số lần rồi vào dead letter
+	for i := 1; i <= 3; i++ {
+		_, _ = newTestDispatcherWith(repo, publisher, now.Add(time.Duration(i)*time.Second), opts).Dispatch(context.Background())
+	}
+	first := repo.get(blocked[0].ID)
+	if first.Attempts != 3 || first.DeadAt == nil {
+		t.Fatalf("Mong đợi event vào dead letter sau 3 lần, nhận được %+v", first)
+	}
+	if second := repo.get(blocked[1].ID); second.DeadAt != nil {
+		t.Errorf("Chỉ event hết số lần thử mới vào dead letter, nhận được %+v", second)
+	}
+
+	// Event trong dead letter không còn chặn aggregate: event sau được gửi khi consumer nhận lại
+	publisher.aggregateID = ""
+	n, _ = newTestDispatcherWith(repo, publisher, now.Add(time.Hour), opts).Dispatch(context.Background())
+	got := publisher.Events()
+	if n != 2 || len(got) != 3 || got[1].ID != blocked[1].ID || got[2].ID != blocked[2].ID {
+		t.Errorf("Event sau dead letter phải được gửi đúng thứ tự, nhận được %+v", got)
+	}
+	if pending, _ := repo.ListPending(context.Background(), outbox.ListPendingOptions{Limit: 10}); len(pending) != 0 {
+		t.Errorf("Không còn event chờ gửi ngoài dead letter, còn lại %+v", pending)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d := newTestDispatcher(&mockRepository{}, event.NewMemoryPublisher(), time.Now())
+
+	tests := map[int]time.Duration{
+		1: time.Second,
+		2: 2 * time.Second,
+		3: 4 * time.Second,
+		4: 4 * time.Second, // Không vượt MaxBackoff
+	}
+	for attempts, want := range tests {
+		if got := d.backoff(attempts); got != want {
+			t.Errorf("backoff(%d) = %v, mong đợi %v", attempts, got, want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"time"
+
+	"thuchanhgolang/internal/outbox"
+	"thuchanhgolang/pkg/event"
+	"thuchanhgolang/pkg/log"
+)
+
+// implDispatcher là implementation của outbox.Dispatcher
+type implDispatcher struct {
+	l         log.Logger        // Logger để ghi log
+	repo      outbox.Repository // Đọc và đánh dấu event trong outbox
+	publisher event.Publisher   // Nơi nhận event (message broker, log, bộ nhớ khi test)
+	opts      outbox.DispatcherOptions
+	now       func() time.Time // Đồng hồ, thay được khi test backoff
+}
+
+// NewDispatcher tạo dispatcher gửi event từ outbox qua publisher
+func NewDispatcher(l log.Logger, repo outbox.Repository, publisher event.Publisher, opts outbox.DispatcherOptions) outbox.Dispatcher {
+	opts.Adjust()
+	return &implDispatcher{
+		l:         l,
+		repo:      repo,
+		publisher: publisher,
+		opts:      opts,
+		now:       time.Now,
+	}
+}
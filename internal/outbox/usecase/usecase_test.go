@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/outbox"
+	"thuchanhgolang/pkg/event"
+)
+
+// mockRepository giữ outbox trong bộ nhớ, ListPending trả event chưa gửi theo thứ tự ghi
+type mockRepository struct {
+	events            []models.OutboxEvent
+	markDeliveredFunc func(ctx context.Context, id string, at time.Time) error
+}
+
+func (m *mockRepository) Publish(ctx context.Context, events ...event.Event) error {
+	for _, e := range events {
+		m.events = append(m.events, models.OutboxEvent{Event: e, NextAttemptAt: e.OccurredAt})
+	}
+	return nil
+}
+
+func (m *mockRepository) ListPending(ctx context.Context, opts outbox.ListPendingOptions) ([]models.OutboxEvent, error) {
+	var pending []models.OutboxEvent
+	started := opts.After == nil
+	for _, e := range m.events {
+		if !started {
+			started = e.ID == opts.After.ID
+			continue
+		}
+		if e.DeliveredAt == nil && e.DeadAt == nil && int64(len(pending)) < opts.Limit {
+			pending = append(pending, e)
+		}
+	}
+	return pending, nil
+}
+
+func (m *mockRepository) MarkDelivered(ctx context.Context, id string, at time.Time) error {
+	if m.markDeliveredFunc != nil {
+		if err := m.markDeliveredFunc(ctx, id, at); err != nil {
+			return err
+		}
+	}
+	for i := range m.events {
+		if m.events[i].ID == id {
+			m.events[i].DeliveredAt = &at
+		}
+	}
+	return nil
+}
+
+func (m *mockRepository) MarkFailed(ctx context.Context, opts outbox.MarkFailedOptions) error {
+	for i := range m.events {
+		if m.events[i].ID == opts.ID {
+			m.events[i].Attempts = opts.Attempts
+			m.events[i].NextAttemptAt = opts.NextAttemptAt
+			m.events[i].LastError = opts.LastError
+			m.events[i].DeadAt = opts.DeadAt
+		}
+	}
+	return nil
+}
+
+// get trả về event trong outbox theo ID
+func (m *mockRepository) get(id string) models.OutboxEvent {
+	for _, e := range m.events {
+		if e.ID == id {
+			return e
+		}
+	}
+	return models.OutboxEvent{}
+}
+
+type mockLogger struct{}
+
+func (m *mockLogger) Debug(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Debugf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Info(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Infof(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Warn(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Warnf(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Error(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Errorf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Fatal(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Fatalf(ctx context.Context, template string, arg ...any) {}
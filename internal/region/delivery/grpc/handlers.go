@@ -0,0 +1,100 @@
+package grpc
+
+import (
+	"context"
+
+	"thuchanhgolang/pkg/orgapi"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Get lấy region theo ID
+func (h handler) Get(ctx context.Context, req *orgapi.GetRequest) (*orgapi.Region, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để lấy region
+	id, _ := primitive.ObjectIDFromHex(req.ID)
+	region, err := h.uc.GetByID(ctx, sc, id)
+	if err != nil {
+		h.l.Warnf(ctx, "region.grpc.Get.uc.GetByID: %s", err)
+		return nil, err
+	}
+
+	return h.newRegion(region), nil
+}
+
+// List lấy danh sách region có phân trang
+func (h handler) List(ctx context.Context, req *orgapi.ListRegionsRequest) (*orgapi.ListRegionsResponse, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để lấy danh sách
+	out, err := h.uc.List(ctx, sc, h.toListInput(req))
+	if err != nil {
+		h.l.Warnf(ctx, "region.grpc.List.uc.List: %s", err)
+		return nil, err
+	}
+
+	return h.newListResp(out), nil
+}
+
+// Create tạo region mới
+func (h handler) Create(ctx context.Context, req *orgapi.CreateRegionRequest) (*orgapi.Region, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để tạo region
+	region, err := h.uc.Create(ctx, sc, h.toCreateInput(req))
+	if err != nil {
+		h.l.Warnf(ctx, "region.grpc.Create.uc.Create: %s", err)
+		return nil, err
+	}
+
+	return h.newRegion(region), nil
+}
+
+// Update cập nhật region, chỉ khi region chưa bị sửa sau version client đã đọc
+func (h handler) Update(ctx context.Context, req *orgapi.UpdateRegionRequest) (*orgapi.Region, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để cập nhật region
+	region, err := h.uc.Update(ctx, sc, h.toUpdateInput(req))
+	if err != nil {
+		h.l.Warnf(ctx, "region.grpc.Update.uc.Update: %s", err)
+		return nil, err
+	}
+
+	return h.newRegion(region), nil
+}
+
+// Delete xóa region, chỉ khi region chưa bị sửa sau version client đã đọc
+func (h handler) Delete(ctx context.Context, req *orgapi.DeleteRequest) (*orgapi.Empty, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để xóa region
+	id, _ := primitive.ObjectIDFromHex(req.ID)
+	if err := h.uc.Delete(ctx, sc, id, req.Version); err != nil {
+		h.l.Warnf(ctx, "region.grpc.Delete.uc.Delete: %s", err)
+		return nil, err
+	}
+
+	return &orgapi.Empty{}, nil
+}
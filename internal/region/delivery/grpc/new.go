@@ -0,0 +1,21 @@
+package grpc
+
+import (
+	"thuchanhgolang/internal/region"
+	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/orgapi"
+)
+
+// handler là implementation của orgapi.RegionServiceServer
+type handler struct {
+	l  log.Logger     // Logger để ghi log
+	uc region.Usecase // Usecase để xử lý business logic
+}
+
+// New tạo gRPC service cho region, dùng chung usecase với REST
+func New(l log.Logger, uc region.Usecase) orgapi.RegionServiceServer {
+	return handler{
+		l:  l,
+		uc: uc,
+	}
+}
@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/region"
+	"thuchanhgolang/pkg/orgapi"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// toCreateInput chuyển đổi request thành input cho usecase, định dạng đã được kiểm tra qua binding tag
+func (h handler) toCreateInput(req *orgapi.CreateRegionRequest) region.CreateInput {
+	shopID, _ := primitive.ObjectIDFromHex(req.ShopID)
+	return region.CreateInput{
+		ShopID: shopID,
+		Name:   req.Name,
+	}
+}
+
+// toUpdateInput chuyển đổi request thành input cho usecase
+func (h handler) toUpdateInput(req *orgapi.UpdateRegionRequest) region.UpdateInput {
+	id, _ := primitive.ObjectIDFromHex(req.ID)
+	return region.UpdateInput{
+		ID:      id,
+		Name:    req.Name,
+		Version: req.Version,
+	}
+}
+
+// toListInput chuyển đổi request thành input cho usecase
+func (h handler) toListInput(req *orgapi.ListRegionsRequest) region.ListInput {
+	input := region.ListInput{
+		Pagin: paginator.PaginatorQuery{Page: req.Page.Page, Limit: req.Page.Limit},
+	}
+	if req.ShopID != "" {
+		shopID, _ := primitive.ObjectIDFromHex(req.ShopID)
+		input.ShopID = &shopID
+	}
+	return input
+}
+
+// newRegion tạo message từ region model
+func (h handler) newRegion(d models.Region) *orgapi.Region {
+	return &orgapi.Region{
+		ID:        d.ID.Hex(),
+		ShopID:    d.ShopID.Hex(),
+		Name:      d.Name,
+		Version:   d.Version,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+		CreatedBy: d.CreatedBy,
+		UpdatedBy: d.UpdatedBy,
+	}
+}
+
+// newListResp tạo message từ danh sách region
+func (h handler) newListResp(out region.ListOutput) *orgapi.ListRegionsResponse {
+	items := make([]orgapi.Region, 0, len(out.Regions))
+	for _, d := range out.Regions {
+		items = append(items, *h.newRegion(d))
+	}
+	return &orgapi.ListRegionsResponse{
+		Items: items,
+		Meta:  newPageInfo(out.Pagin),
+	}
+}
+
+// newPageInfo tạo thông tin phân trang
+func newPageInfo(p paginator.Paginator) orgapi.PageInfo {
+	resp := p.ToResponse()
+	return orgapi.PageInfo{
+		Total:       resp.Total,
+		Count:       resp.Count,
+		PerPage:     resp.PerPage,
+		CurrentPage: resp.CurrentPage,
+		TotalPages:  resp.TotalPages,
+	}
+}
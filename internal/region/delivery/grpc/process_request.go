@@ -0,0 +1,29 @@
+package grpc
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/jwt"
+)
+
+// processScope lấy scope của user đang đăng nhập và kiểm tra quyền như middleware CheckRegionAccess của REST:
+// Manager hoặc RegionManager, RegionManager chỉ với region của mình khi có id
+func (h handler) processScope(ctx context.Context, id string) (models.Scope, error) {
+	payload, ok := jwt.GetPayloadFromContext(ctx)
+	if !ok {
+		return models.Scope{}, errUnauthorized
+	}
+
+	role := models.Role(payload.Role)
+	if role != models.RoleManager && role != models.RoleRegionManager {
+		h.l.Warnf(ctx, "region.grpc.processScope: user role %s not allowed", role)
+		return models.Scope{}, errForbidden
+	}
+	if role == models.RoleRegionManager && id != "" && payload.RegionID != id {
+		h.l.Warnf(ctx, "region.grpc.processScope: region_id mismatch")
+		return models.Scope{}, errForbidden
+	}
+
+	return jwt.NewScope(payload), nil
+}
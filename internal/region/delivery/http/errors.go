@@ -2,15 +2,23 @@ package http
 
 import (
 	"errors"
+	"net/http"
 	"thuchanhgolang/internal/region"
 	pkgErrors "thuchanhgolang/pkg/errors"
 )
 
 var (
-	errWrongBody     = pkgErrors.NewHTTPError(10000, "Wrong body")
-	errInvalidID     = pkgErrors.NewHTTPError(10001, "Invalid region ID")
-	errInvalidShopID = pkgErrors.NewHTTPError(10002, "Invalid shop ID")
-	errRegionInUse   = pkgErrors.NewHTTPError(10004, "Region is being used by branches, cannot delete")
+	errWrongBody      = pkgErrors.NewHTTPError(11000, "Wrong body")
+	errInvalidID      = pkgErrors.NewHTTPError(11001, "Invalid region ID")
+	errInvalidShopID  = pkgErrors.NewHTTPError(11002, "Invalid shop ID")
+	errRegionInUse    = pkgErrors.NewHTTPErrorWithStatus(11004, "Region is being used by branches, cannot delete", http.StatusConflict)
+	errRegionNotFound = pkgErrors.NewHTTPErrorWithStatus(11005, "Region not found", http.StatusNotFound)
+
+	errVersionMismatch      = pkgErrors.NewHTTPErrorWithStatus(11006, "Region has been modified by another request, reload and try again", http.StatusPreconditionFailed)
+	errPreconditionRequired = pkgErrors.NewHTTPErrorWithStatus(11007, "If-Match header with the region ETag is required", http.StatusPreconditionRequired)
+
+	errWrongQuery   = pkgErrors.NewHTTPError(11008, "Wrong query")
+	errUnauthorized = pkgErrors.NewUnauthorizedHTTPError()
 )
 
 func (h handler) mapError(err error) error {
@@ -18,5 +26,11 @@ func (h handler) mapError(err error) error {
 	if errors.Is(err, region.ErrRegionInUse) {
 		return errRegionInUse
 	}
+	if errors.Is(err, region.ErrRegionNotFound) {
+		return errRegionNotFound
+	}
+	if errors.Is(err, region.ErrVersionMismatch) {
+		return errVersionMismatch
+	}
 	return err
 }
@@ -1,7 +1,13 @@
 package http
 
 import (
+	"errors"
+	"time"
+
 	"thuchanhgolang/internal/models"
+	pkgErrors "thuchanhgolang/pkg/errors"
+	"thuchanhgolang/pkg/jwt"
+	"thuchanhgolang/pkg/util"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,18 +20,16 @@ func (h handler) processCreateRequest(c *gin.Context) (createReq, models.Scope,
 	var req createReq
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.l.Warnf(ctx, "region.http.processCreateRequest.ShouldBindJSON: %v", err)
-		return createReq{}, models.Scope{}, errWrongBody
+		return createReq{}, models.Scope{}, pkgErrors.BindingError(err, errWrongBody)
 	}
 
-	// Bước 2: Validate dữ liệu
-	if err := req.validate(); err != nil {
-		h.l.Warnf(ctx, "region.http.processCreateRequest.validate: %v", err)
+	// Bước 2: Lấy scope của user đang đăng nhập, dùng để ghi lại người tạo / sửa
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "region.http.processCreateRequest.processScope: %v", err)
 		return createReq{}, models.Scope{}, err
 	}
 
-	// Bước 3: Tạo scope trống
-	sc := models.Scope{}
-
 	return req, sc, nil
 }
 
@@ -37,17 +41,139 @@ func (h handler) processUpdateRequest(c *gin.Context) (updateReq, models.Scope,
 	var req updateReq
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.l.Warnf(ctx, "region.http.processUpdateRequest.ShouldBindJSON: %v", err)
-		return updateReq{}, models.Scope{}, errWrongBody
+		return updateReq{}, models.Scope{}, pkgErrors.BindingError(err, errWrongBody)
+	}
+
+	// Bước 2: Lấy scope của user đang đăng nhập, dùng để ghi lại người tạo / sửa
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "region.http.processUpdateRequest.processScope: %v", err)
+		return updateReq{}, models.Scope{}, err
+	}
+
+	// Bước 3: Đọc version client đã đọc từ If-Match
+	version, err := h.processVersion(c)
+	if err != nil {
+		h.l.Warnf(ctx, "region.http.processUpdateRequest.processVersion: %v", err)
+		return updateReq{}, models.Scope{}, err
+	}
+	req.version = version
+
+	return req, sc, nil
+}
+
+// processPatchRequest xử lý và validate merge patch của region
+func (h handler) processPatchRequest(c *gin.Context) (patchReq, models.Scope, error) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Parse merge patch thành patchReq struct
+	var req patchReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.l.Warnf(ctx, "region.http.processPatchRequest.ShouldBindJSON: %v", err)
+		return patchReq{}, models.Scope{}, pkgErrors.BindingError(err, errWrongBody)
 	}
 
 	// Bước 2: Validate dữ liệu
 	if err := req.validate(); err != nil {
-		h.l.Warnf(ctx, "region.http.processUpdateRequest.validate: %v", err)
-		return updateReq{}, models.Scope{}, err
+		h.l.Warnf(ctx, "region.http.processPatchRequest.validate: %v", err)
+		return patchReq{}, models.Scope{}, err
+	}
+
+	// Bước 3: Lấy scope của user đang đăng nhập, dùng để ghi lại người sửa
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "region.http.processPatchRequest.processScope: %v", err)
+		return patchReq{}, models.Scope{}, err
 	}
 
-	// Bước 3: Tạo scope trống
-	sc := models.Scope{}
+	// Bước 4: Đọc version client đã đọc từ If-Match
+	version, err := h.processVersion(c)
+	if err != nil {
+		h.l.Warnf(ctx, "region.http.processPatchRequest.processVersion: %v", err)
+		return patchReq{}, models.Scope{}, err
+	}
+	req.version = version
 
 	return req, sc, nil
 }
+
+// processListRequest xử lý và validate query lấy danh sách region
+func (h handler) processListRequest(c *gin.Context) (listReq, models.Scope, error) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Parse query thành listReq struct
+	var req listReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.l.Warnf(ctx, "region.http.processListRequest.ShouldBindQuery: %v", err)
+		return listReq{}, models.Scope{}, errWrongQuery
+	}
+
+	// Bước 2: Validate dữ liệu
+	if err := req.validate(); err != nil {
+		h.l.Warnf(ctx, "region.http.processListRequest.validate: %v", err)
+		return listReq{}, models.Scope{}, err
+	}
+
+	// Bước 3: Lấy scope của user đang đăng nhập
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "region.http.processListRequest.processScope: %v", err)
+		return listReq{}, models.Scope{}, err
+	}
+
+	return req, sc, nil
+}
+
+// processVersion đọc version client đã đọc từ header If-Match, bắt buộc khi sửa hoặc xóa
+func (h handler) processVersion(c *gin.Context) (int, error) {
+	version, err := util.GetIfMatchVersion(c)
+	if errors.Is(err, util.ErrIfMatchMissing) {
+		return 0, errPreconditionRequired
+	}
+	if err != nil {
+		return 0, errVersionMismatch
+	}
+	return version, nil
+}
+
+// processScope lấy scope của user đang đăng nhập từ JWT payload
+func (h handler) processScope(c *gin.Context) (models.Scope, error) {
+	payload, ok := jwt.GetPayloadFromContext(c.Request.Context())
+	if !ok {
+		return models.Scope{}, errUnauthorized
+	}
+
+	return jwt.NewScope(payload), nil
+}
+
+// processHistoryRequest xử lý query lấy lịch sử thay đổi của region
+func (h handler) processHistoryRequest(c *gin.Context) (historyReq, models.Scope, error) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Parse query thành historyReq struct
+	var req historyReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.l.Warnf(ctx, "region.http.processHistoryRequest.ShouldBindQuery: %v", err)
+		return historyReq{}, models.Scope{}, errWrongQuery
+	}
+
+	// Bước 2: Lấy scope của user đang đăng nhập
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "region.http.processHistoryRequest.processScope: %v", err)
+		return historyReq{}, models.Scope{}, err
+	}
+
+	return req, sc, nil
+}
+
+// processAsOf đọc thời điểm cần xem từ query as_of theo định dạng "2006-01-02 15:04:05",
+// không có as_of thì trả về nil (xem trạng thái hiện tại)
+func (h handler) processAsOf(c *gin.Context) (*time.Time, error) {
+	asOf, err := util.StrToDateTimePtr(c.Query("as_of"))
+	if err != nil {
+		h.l.Warnf(c.Request.Context(), "region.http.processAsOf.StrToDateTimePtr: %v", err)
+		return nil, errWrongQuery
+	}
+	return asOf, nil
+}
@@ -1,13 +1,47 @@
 package http
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
+
+	cascadeHTTP "thuchanhgolang/internal/cascade/delivery/http"
+	"thuchanhgolang/pkg/openapi"
+	"thuchanhgolang/pkg/patch"
 )
 
 // MapRoutes maps the routes to the handler functions
 func MapRoutes(r *gin.RouterGroup, h Handler) {
-	r.POST("", h.create)       // Tạo region mới
-	r.GET("/:id", h.getByID)   // Xem chi tiết region theo ID
-	r.PUT("/:id", h.update)    // Cập nhật region theo ID
-	r.DELETE("/:id", h.delete) // Xóa region theo ID
+	r.POST("", h.create)             // Tạo region mới
+	r.GET("", h.list)                // Lấy danh sách region, lọc theo thời gian tạo / cập nhật
+	r.GET("/:id", h.getByID)         // Xem chi tiết region theo ID
+	r.GET("/:id/history", h.history) // Xem lịch sử thay đổi, mới nhất đứng trước
+	r.PUT("/:id", h.update)          // Cập nhật region theo ID
+	r.PATCH("/:id", h.patch)         // Cập nhật một phần region theo JSON Merge Patch
+	r.DELETE("/:id", h.delete)       // Xóa region theo ID
+}
+
+// Spec mô tả các route của MapRoutes cho OpenAPI, sửa route thì sửa cả ở đây
+func Spec() []openapi.Route {
+	return []openapi.Route{
+		{Method: http.MethodPost, Path: "", Summary: "Tạo region mới", Body: createReq{}, Response: detailResp{}, ETag: true},
+		{Method: http.MethodGet, Path: "", Summary: "Lấy danh sách region, lọc theo thời gian tạo / cập nhật", Query: listReq{}, Response: listResp{}},
+		{
+			Method: http.MethodGet, Path: "/:id", Summary: "Xem chi tiết region theo ID",
+			Params:   []openapi.Parameter{openapi.QueryParam("as_of", "Thời điểm cần xem, định dạng 2006-01-02 15:04:05")},
+			Response: detailResp{}, ETag: true,
+		},
+		{Method: http.MethodGet, Path: "/:id/history", Summary: "Xem lịch sử thay đổi, mới nhất đứng trước", Query: historyReq{}, Response: historyResp{}},
+		{Method: http.MethodPut, Path: "/:id", Summary: "Cập nhật region theo ID", IfMatch: true, Body: updateReq{}, Response: detailResp{}, ETag: true},
+		{
+			Method: http.MethodPatch, Path: "/:id", Summary: "Cập nhật một phần region theo JSON Merge Patch, field không gửi thì giữ nguyên",
+			IfMatch: true, Body: patchReq{}, BodyTypes: []string{patch.ContentType, openapi.ContentJSON}, Response: detailResp{}, ETag: true,
+		},
+		{
+			Method: http.MethodDelete, Path: "/:id", Summary: "Xóa region theo ID, cascade=true để xóa luôn các bản ghi con",
+			Query:    cascadeHTTP.DeleteReq{},
+			Params:   []openapi.Parameter{openapi.HeaderParam("If-Match", "ETag của region, không cần khi cascade=true&dry_run=true")},
+			Response: openapi.OneOf{gin.H{}, cascadeHTTP.DeleteResp{}},
+		},
+	}
 }
@@ -1,8 +1,14 @@
 package region
 
-import "errors"
+import pkgErrors "thuchanhgolang/pkg/errors"
 
 var (
 	// ErrRegionInUse trả về khi region đang được sử dụng bởi branch
-	ErrRegionInUse = errors.New("region is being used by branches, cannot delete")
+	ErrRegionInUse = pkgErrors.Conflict("region is being used by branches, cannot delete")
+
+	// ErrRegionNotFound trả về khi không tìm thấy region
+	ErrRegionNotFound = pkgErrors.NotFound("region not found")
+
+	// ErrVersionMismatch trả về khi region đã bị thay đổi sau lần đọc của client (If-Match không khớp version)
+	ErrVersionMismatch = pkgErrors.PreconditionFailed("region has been modified by another request")
 )
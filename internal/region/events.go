@@ -0,0 +1,13 @@
+package region
+
+const (
+	// AggregateType là loại aggregate của các event region
+	AggregateType = "region"
+
+	// EventRegionCreated được phát khi region được tạo
+	EventRegionCreated = "region.created"
+	// EventRegionUpdated được phát khi thông tin region thay đổi
+	EventRegionUpdated = "region.updated"
+	// EventRegionDeleted được phát khi region bị xóa (kể cả xóa dây chuyền)
+	EventRegionDeleted = "region.deleted"
+)
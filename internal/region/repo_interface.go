@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/paginator"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -18,11 +19,14 @@ type Repository interface {
 	// GetByID lấy region theo ID từ database
 	GetByID(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Region, error)
 
+	// List lấy danh sách region theo bộ lọc, có phân trang
+	List(ctx context.Context, sc models.Scope, opts ListOptions) ([]models.Region, paginator.Paginator, error)
+
 	// Update cập nhật region trong database
 	Update(ctx context.Context, sc models.Scope, opts UpdateOptions) (models.Region, error)
 
-	// Delete xóa region khỏi database
-	Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error
+	// Delete xóa region khỏi database, version là version client đã đọc (0 → không kiểm tra)
+	Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error
 
 	// HasBranches kiểm tra xem region có branch nào không
 	HasBranches(ctx context.Context, regionID primitive.ObjectID) (bool, error)
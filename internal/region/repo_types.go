@@ -1,6 +1,11 @@
 package region
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // CreateOptions là tùy chọn để tạo region trong database
 type CreateOptions struct {
@@ -10,6 +15,14 @@ type CreateOptions struct {
 
 // UpdateOptions là tùy chọn để cập nhật region
 type UpdateOptions struct {
-	ID   primitive.ObjectID // ID region cần cập nhật
-	Name string             // Tên mới
+	ID      primitive.ObjectID // ID region cần cập nhật
+	Name    *string            // Tên mới, nil → giữ nguyên
+	Version int                // Version client đã đọc (If-Match), 0 → không kiểm tra
+}
+
+// ListOptions là bộ lọc và phân trang khi lấy danh sách region
+type ListOptions struct {
+	ShopID *primitive.ObjectID      // Chỉ lấy region thuộc shop này (nil = mọi shop)
+	Filter models.TimeFilter        // Lọc theo thời gian tạo, cập nhật
+	Pagin  paginator.PaginatorQuery // Trang cần lấy (đã Adjust)
 }
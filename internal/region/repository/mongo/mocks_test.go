@@ -42,6 +42,9 @@ type mockCollection struct {
 	deleteOneFunc      func(context.Context, interface{}) (int64, error)
 	updateOneFunc      func(context.Context, interface{}, interface{}, ...*options.UpdateOptions) (*driverMongo.UpdateResult, error)
 	countDocumentsFunc func(context.Context, interface{}, ...*options.CountOptions) (int64, error)
+
+	findOneAndUpdateFunc func(context.Context, interface{}, interface{}, ...*options.FindOneAndUpdateOptions) mongo.SingleResult
+	findOneAndDeleteFunc func(context.Context, interface{}, ...*options.FindOneAndDeleteOptions) mongo.SingleResult
 }
 
 func (m *mockCollection) FindOne(ctx context.Context, filter interface{}) mongo.SingleResult {
@@ -69,6 +72,18 @@ func (m *mockCollection) DeleteOne(ctx context.Context, filter interface{}) (int
 	return 0, nil
 }
 
+func (m *mockCollection) DeleteMany(ctx context.Context, filter interface{}) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockCollection) CreateIndexes(ctx context.Context, models []driverMongo.IndexModel) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockCollection) DropIndex(ctx context.Context, name string) error {
+	return nil
+}
+
 func (m *mockCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (mongo.Cursor, error) {
 	return nil, nil
 }
@@ -95,6 +110,20 @@ func (m *mockCollection) UpdateMany(ctx context.Context, filter interface{}, upd
 	return nil, nil
 }
 
+func (m *mockCollection) FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) mongo.SingleResult {
+	if m.findOneAndUpdateFunc != nil {
+		return m.findOneAndUpdateFunc(ctx, filter, update, opts...)
+	}
+	return nil
+}
+
+func (m *mockCollection) FindOneAndDelete(ctx context.Context, filter interface{}, opts ...*options.FindOneAndDeleteOptions) mongo.SingleResult {
+	if m.findOneAndDeleteFunc != nil {
+		return m.findOneAndDeleteFunc(ctx, filter, opts...)
+	}
+	return nil
+}
+
 // mockSingleResult implements mongo.SingleResult
 type mockSingleResult struct {
 	decodeFunc func(interface{}) error
@@ -2,13 +2,18 @@ package mongo
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"thuchanhgolang/internal/history"
 	"thuchanhgolang/internal/models"
 	"thuchanhgolang/internal/region"
 	"thuchanhgolang/pkg/mongo"
+	"thuchanhgolang/pkg/paginator"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const (
@@ -23,12 +28,18 @@ func (repo implRepository) getRegionCollection() mongo.Collection {
 // Create tạo region mới trong MongoDB
 func (repo implRepository) Create(ctx context.Context, sc models.Scope, opts region.CreateOptions) (models.Region, error) {
 	col := repo.getRegionCollection()
+	now := time.Now()
 
 	// Tạo region object mới
 	newRegion := models.Region{
-		ID:     repo.db.NewObjectID(),
-		ShopID: opts.ShopID,
-		Name:   opts.Name,
+		ID:        repo.db.NewObjectID(),
+		ShopID:    opts.ShopID,
+		Name:      opts.Name,
+		CreatedAt: now,
+		UpdatedAt: now,
+		CreatedBy: sc.UserID,
+		UpdatedBy: sc.UserID,
+		Version:   1,
 	}
 
 	// Lưu vào database
@@ -57,21 +68,84 @@ func (repo implRepository) GetByID(ctx context.Context, sc models.Scope, id prim
 	return region, nil
 }
 
+// List lấy danh sách region theo bộ lọc, region tạo sau đứng trước
+func (repo implRepository) List(ctx context.Context, sc models.Scope, opts region.ListOptions) ([]models.Region, paginator.Paginator, error) {
+	col := repo.getRegionCollection()
+
+	// Bước 1: Đếm tổng số region khớp bộ lọc
+	filter := repo.buildListQuery(opts)
+	total, err := col.CountDocuments(ctx, filter)
+	if err != nil {
+		repo.l.Errorf(ctx, "region.mongo.List.CountDocuments: %v", err)
+		return nil, paginator.Paginator{}, err
+	}
+
+	// Bước 2: Lấy region của trang hiện tại
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetSkip(opts.Pagin.Offset()).
+		SetLimit(opts.Pagin.Limit)
+	cursor, err := col.Find(ctx, filter, findOpts)
+	if err != nil {
+		repo.l.Errorf(ctx, "region.mongo.List.Find: %v", err)
+		return nil, paginator.Paginator{}, err
+	}
+
+	var regions []models.Region
+	if err := cursor.All(ctx, &regions); err != nil {
+		repo.l.Errorf(ctx, "region.mongo.List.All: %v", err)
+		return nil, paginator.Paginator{}, err
+	}
+
+	return regions, paginator.Paginator{
+		Total:       total,
+		Count:       int64(len(regions)),
+		PerPage:     opts.Pagin.Limit,
+		CurrentPage: opts.Pagin.Page,
+	}, nil
+}
+
 // Update cập nhật thông tin region trong MongoDB (chỉ cho phép đổi tên)
 func (repo implRepository) Update(ctx context.Context, sc models.Scope, opts region.UpdateOptions) (models.Region, error) {
 	col := repo.getRegionCollection()
 
-	// Chỉ update name, không cho phép đổi shop_id
-	update := bson.M{
-		"name": opts.Name,
+	// Chỉ update name, không cho phép đổi cha (dùng Move), field nil thì giữ nguyên
+	update := mongo.NewUpdate()
+	mongo.SetPtr(update, "name", opts.Name)
+
+	// Nếu không có gì để update
+	if !update.HasChanges() {
+		current, err := repo.GetByID(ctx, sc, opts.ID)
+		if err != nil {
+			return models.Region{}, err
+		}
+		if opts.Version > 0 && current.Version != opts.Version {
+			return models.Region{}, region.ErrVersionMismatch
+		}
+		return current, nil
 	}
 
-	// Update region
+	// Ghi lại thời điểm và người sửa
+	now := time.Now()
+	update.Set("updated_at", now).Set("updated_by", sc.UserID).Inc("version", 1)
+
+	// Update region, chỉ khi version khớp với version client đã đọc, đồng thời tăng version.
+	// Lấy về region trước khi update để lưu vào lịch sử
 	filter := bson.M{"_id": opts.ID}
-	updateDoc := bson.M{"$set": update}
-	_, err := col.UpdateOne(ctx, filter, updateDoc)
+	if opts.Version > 0 {
+		filter["version"] = opts.Version
+	}
+	var prior bson.Raw
+	err := col.FindOneAndUpdate(ctx, filter, update.Document()).Decode(&prior)
+	if errors.Is(err, mongo.ErrNoDocuments) && opts.Version > 0 {
+		return models.Region{}, repo.versionError(ctx, sc, opts.ID)
+	}
 	if err != nil {
-		repo.l.Errorf(ctx, "region.mongo.Update.UpdateOne: %v", err)
+		repo.l.Errorf(ctx, "region.mongo.Update.FindOneAndUpdate: %v", err)
+		return models.Region{}, err
+	}
+	if err := history.RecordRevisions(ctx, repo.db, history.EntityRegion, models.RevisionUpdate, sc.UserID, now, prior); err != nil {
+		repo.l.Errorf(ctx, "region.mongo.Update.history.RecordRevisions: %v", err)
 		return models.Region{}, err
 	}
 
@@ -80,17 +154,31 @@ func (repo implRepository) Update(ctx context.Context, sc models.Scope, opts reg
 }
 
 // Delete xóa region khỏi MongoDB
-func (repo implRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+func (repo implRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
 	col := repo.getRegionCollection()
 
-	// Xóa region theo ID
+	// Xóa region theo ID, lấy về region đã xóa để lưu vào lịch sử
 	filter := bson.M{"_id": id}
-	_, err := col.DeleteOne(ctx, filter)
+	if version > 0 {
+		filter["version"] = version
+	}
+	var prior bson.Raw
+	err := col.FindOneAndDelete(ctx, filter).Decode(&prior)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if version > 0 {
+			return repo.versionError(ctx, sc, id)
+		}
+		return nil
+	}
 	if err != nil {
-		repo.l.Errorf(ctx, "region.mongo.Delete.DeleteOne: %v", err)
+		repo.l.Errorf(ctx, "region.mongo.Delete.FindOneAndDelete: %v", err)
 		return err
 	}
 
+	if err := history.RecordRevisions(ctx, repo.db, history.EntityRegion, models.RevisionDelete, sc.UserID, time.Now(), prior); err != nil {
+		repo.l.Errorf(ctx, "region.mongo.Delete.history.RecordRevisions: %v", err)
+		return err
+	}
 	return nil
 }
 
@@ -108,3 +196,23 @@ func (repo implRepository) HasBranches(ctx context.Context, regionID primitive.O
 
 	return count > 0, nil
 }
+
+// versionError xác định lý do update/delete theo version không khớp document nào:
+// document không còn thì trả lỗi của GetByID, còn tồn tại thì version đã bị thay đổi
+func (repo implRepository) versionError(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+	if _, err := repo.GetByID(ctx, sc, id); err != nil {
+		return err
+	}
+	return region.ErrVersionMismatch
+}
+
+// buildListQuery tạo filter lấy danh sách region từ bộ lọc
+func (repo implRepository) buildListQuery(opts region.ListOptions) bson.M {
+	filter := bson.M{}
+	if opts.ShopID != nil {
+		filter["shop_id"] = *opts.ShopID
+	}
+	mongo.BuildQueryWithTimeRange(filter, "created_at", opts.Filter.CreatedFrom, opts.Filter.CreatedTo)
+	mongo.BuildQueryWithTimeRange(filter, "updated_at", opts.Filter.UpdatedFrom, opts.Filter.UpdatedTo)
+	return filter
+}
@@ -132,8 +132,8 @@ func TestUpdate(t *testing.T) {
 		updated := models.Region{ID: id, Name: "Updated"}
 
 		mockColl := &mockCollection{
-			updateOneFunc: func(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*driverMongo.UpdateResult, error) {
-				return &driverMongo.UpdateResult{ModifiedCount: 1}, nil
+			findOneAndUpdateFunc: func(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) mongo.SingleResult {
+				return newMockSingleResult(models.Region{ID: id, Version: 1}, nil)
 			},
 			findOneFunc: func(ctx context.Context, filter interface{}) mongo.SingleResult {
 				return newMockSingleResult(updated, nil)
@@ -147,7 +147,7 @@ func TestUpdate(t *testing.T) {
 		}
 
 		repo := &implRepository{db: mockDB, l: &mockLogger{}}
-		result, err := repo.Update(ctx, models.Scope{}, region.UpdateOptions{ID: id, Name: "Updated"})
+		result, err := repo.Update(ctx, models.Scope{}, region.UpdateOptions{ID: id, Name: &updated.Name})
 
 		if err != nil {
 			t.Fatalf("Không mong đợi lỗi: %v", err)
@@ -157,13 +157,45 @@ func TestUpdate(t *testing.T) {
 		}
 	})
 
+	t.Run("update without changes keeps region", func(t *testing.T) {
+		ctx := context.Background()
+		id := primitive.NewObjectID()
+		current := models.Region{ID: id, Name: "Current", Version: 2}
+
+		mockColl := &mockCollection{
+			findOneAndUpdateFunc: func(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) mongo.SingleResult {
+				t.Fatal("Không mong đợi ghi vào database")
+				return nil
+			},
+			findOneFunc: func(ctx context.Context, filter interface{}) mongo.SingleResult {
+				return newMockSingleResult(current, nil)
+			},
+		}
+
+		mockDB := &mockDatabase{
+			collectionFunc: func(name string) mongo.Collection {
+				return mockColl
+			},
+		}
+
+		repo := &implRepository{db: mockDB, l: &mockLogger{}}
+		result, err := repo.Update(ctx, models.Scope{}, region.UpdateOptions{ID: id, Version: 2})
+
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if result.Name != current.Name {
+			t.Errorf("Name không khớp")
+		}
+	})
+
 	t.Run("update with error", func(t *testing.T) {
 		ctx := context.Background()
 		id := primitive.NewObjectID()
 
 		mockColl := &mockCollection{
-			updateOneFunc: func(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*driverMongo.UpdateResult, error) {
-				return nil, errors.New("update failed")
+			findOneAndUpdateFunc: func(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) mongo.SingleResult {
+				return newMockSingleResult(nil, errors.New("update failed"))
 			},
 		}
 
@@ -174,7 +206,8 @@ func TestUpdate(t *testing.T) {
 		}
 
 		repo := &implRepository{db: mockDB, l: &mockLogger{}}
-		_, err := repo.Update(ctx, models.Scope{}, region.UpdateOptions{ID: id})
+		name := "Updated"
+		_, err := repo.Update(ctx, models.Scope{}, region.UpdateOptions{ID: id, Name: &name})
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi")
@@ -187,8 +220,8 @@ func TestDelete(t *testing.T) {
 		ctx := context.Background()
 
 		mockColl := &mockCollection{
-			deleteOneFunc: func(ctx context.Context, filter interface{}) (int64, error) {
-				return 1, nil
+			findOneAndDeleteFunc: func(ctx context.Context, filter interface{}, opts ...*options.FindOneAndDeleteOptions) mongo.SingleResult {
+				return newMockSingleResult(models.Region{ID: primitive.NewObjectID(), Version: 1}, nil)
 			},
 		}
 
@@ -199,7 +232,7 @@ func TestDelete(t *testing.T) {
 		}
 
 		repo := &implRepository{db: mockDB, l: &mockLogger{}}
-		err := repo.Delete(ctx, models.Scope{}, primitive.NewObjectID())
+		err := repo.Delete(ctx, models.Scope{}, primitive.NewObjectID(), 0)
 
 		if err != nil {
 			t.Fatalf("Không mong đợi lỗi: %v", err)
@@ -210,8 +243,8 @@ func TestDelete(t *testing.T) {
 		ctx := context.Background()
 
 		mockColl := &mockCollection{
-			deleteOneFunc: func(ctx context.Context, filter interface{}) (int64, error) {
-				return 0, errors.New("delete failed")
+			findOneAndDeleteFunc: func(ctx context.Context, filter interface{}, opts ...*options.FindOneAndDeleteOptions) mongo.SingleResult {
+				return newMockSingleResult(nil, errors.New("delete failed"))
 			},
 		}
 
@@ -222,7 +255,7 @@ func TestDelete(t *testing.T) {
 		}
 
 		repo := &implRepository{db: mockDB, l: &mockLogger{}}
-		err := repo.Delete(ctx, models.Scope{}, primitive.NewObjectID())
+		err := repo.Delete(ctx, models.Scope{}, primitive.NewObjectID(), 0)
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi")
@@ -2,6 +2,7 @@ package region
 
 import (
 	"context"
+	"time"
 
 	"thuchanhgolang/internal/models"
 
@@ -16,9 +17,18 @@ type Usecase interface {
 	// GetByID lấy thông tin region theo ID
 	GetByID(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Region, error)
 
+	// List lấy danh sách region có phân trang, lọc theo thời gian tạo và cập nhật
+	List(ctx context.Context, sc models.Scope, input ListInput) (ListOutput, error)
+
 	// Update cập nhật thông tin region
 	Update(ctx context.Context, sc models.Scope, input UpdateInput) (models.Region, error)
 
-	// Delete xóa region
-	Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error
+	// Delete xóa region, version là version client đã đọc (0 → không kiểm tra)
+	Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error
+
+	// GetAsOf lấy trạng thái của region tại thời điểm asOf
+	GetAsOf(ctx context.Context, sc models.Scope, id primitive.ObjectID, asOf time.Time) (models.Region, error)
+
+	// History lấy lịch sử thay đổi của region, mới nhất đứng trước
+	History(ctx context.Context, sc models.Scope, input HistoryInput) (HistoryOutput, error)
 }
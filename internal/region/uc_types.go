@@ -1,6 +1,13 @@
 package region
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // CreateInput là dữ liệu đầu vào để tạo region mới
 type CreateInput struct {
@@ -10,6 +17,40 @@ type CreateInput struct {
 
 // UpdateInput là dữ liệu đầu vào để cập nhật region
 type UpdateInput struct {
-	ID   primitive.ObjectID // ID region cần cập nhật
-	Name string             // Tên region mới
+	ID      primitive.ObjectID // ID region cần cập nhật
+	Name    *string            // Tên mới, nil → giữ nguyên
+	Version int                // Version client đã đọc (If-Match), 0 → không kiểm tra
+}
+
+// ListInput là dữ liệu đầu vào để lấy danh sách region
+type ListInput struct {
+	ShopID *primitive.ObjectID      // Chỉ lấy region thuộc shop này (nil = mọi shop)
+	Filter models.TimeFilter        // Lọc theo thời gian tạo, cập nhật
+	Pagin  paginator.PaginatorQuery // Trang cần lấy
+}
+
+// ListOutput là danh sách region của một trang
+type ListOutput struct {
+	Regions []models.Region
+	Pagin   paginator.Paginator
+}
+
+// HistoryInput là dữ liệu đầu vào để lấy lịch sử thay đổi của region
+type HistoryInput struct {
+	ID    primitive.ObjectID       // ID region
+	Pagin paginator.PaginatorQuery // Trang cần lấy
+}
+
+// Revision là trạng thái của region trước một lần sửa hoặc xóa
+type Revision struct {
+	Region    models.Region         // Snapshot, có hiệu lực từ Region.UpdatedAt đến ChangedAt
+	Action    models.RevisionAction // Thao tác đã thay thế snapshot
+	ChangedAt time.Time             // Thời điểm sửa / xóa
+	ChangedBy string                // UserID của người sửa / xóa
+}
+
+// HistoryOutput là lịch sử thay đổi của region trong một trang, mới nhất đứng trước
+type HistoryOutput struct {
+	Revisions []Revision
+	Pagin     paginator.Paginator
 }
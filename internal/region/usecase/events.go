@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/region"
+	"thuchanhgolang/pkg/event"
+)
+
+// newEvent tạo event của region r, actor là user đang thao tác
+func newEvent(typ string, sc models.Scope, r models.Region, payload map[string]interface{}) event.Event {
+	e := event.New(typ, region.AggregateType, r.ID.Hex(), sc.UserID, payload)
+	e.Location = models.Hierarchy{ShopID: r.ShopID, RegionID: r.ID}.Location()
+	return e
+}
+
+// newPayload là nội dung event khi region được tạo hoặc cập nhật
+func newPayload(r models.Region) map[string]interface{} {
+	return map[string]interface{}{
+		"shop_id": r.ShopID.Hex(),
+		"name":    r.Name,
+		"version": r.Version,
+	}
+}
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"thuchanhgolang/internal/history"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/region"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// historyReader đọc lịch sử của region, trạng thái hiện tại được lấy trong scope của người gọi
+func (uc *implUsecase) historyReader(sc models.Scope) history.Reader[models.Region] {
+	return history.Reader[models.Region]{
+		Repo:   uc.history,
+		Entity: history.EntityRegion,
+		Current: func(ctx context.Context, id primitive.ObjectID) (models.Region, error) {
+			return uc.repo.GetByID(ctx, sc, id)
+		},
+		CreatedAt: func(v models.Region) time.Time { return v.CreatedAt },
+	}
+}
+
+// GetAsOf lấy trạng thái của region tại thời điểm asOf
+func (uc *implUsecase) GetAsOf(ctx context.Context, sc models.Scope, id primitive.ObjectID, asOf time.Time) (models.Region, error) {
+	result, err := uc.historyReader(sc).GetAsOf(ctx, id, asOf)
+	if err != nil {
+		if errors.Is(err, history.ErrNotCreated) {
+			return models.Region{}, region.ErrRegionNotFound
+		}
+		uc.l.Errorf(ctx, "region.usecase.GetAsOf.historyReader.GetAsOf: %v", err)
+		return models.Region{}, notFoundError(err)
+	}
+
+	return result, nil
+}
+
+// History lấy lịch sử thay đổi của region, mới nhất đứng trước
+func (uc *implUsecase) History(ctx context.Context, sc models.Scope, input region.HistoryInput) (region.HistoryOutput, error) {
+	entries, pag, err := uc.historyReader(sc).List(ctx, input.ID, input.Pagin)
+	if err != nil {
+		uc.l.Errorf(ctx, "region.usecase.History.historyReader.List: %v", err)
+		return region.HistoryOutput{}, notFoundError(err)
+	}
+
+	out := region.HistoryOutput{
+		Revisions: make([]region.Revision, 0, len(entries)),
+		Pagin:     pag,
+	}
+	for _, e := range entries {
+		out.Revisions = append(out.Revisions, region.Revision{
+			Region:    e.Snapshot,
+			Action:    e.Action,
+			ChangedAt: e.ChangedAt,
+			ChangedBy: e.ChangedBy,
+		})
+	}
+
+	return out, nil
+}
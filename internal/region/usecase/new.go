@@ -1,20 +1,29 @@
 package usecase
 
 import (
+	"thuchanhgolang/internal/history"
 	"thuchanhgolang/internal/region"
+	"thuchanhgolang/pkg/event"
 	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/mongo"
 )
 
 // implUsecase là implementation của region.Usecase interface
 type implUsecase struct {
-	l    log.Logger        // Logger để ghi log
-	repo region.Repository // Repository để tương tác với database
+	l         log.Logger         // Logger để ghi log
+	repo      region.Repository  // Repository để tương tác với database
+	history   history.Repository // Đọc lịch sử thay đổi
+	tx        mongo.Transactor   // Chạy các thao tác nhiều bước trong transaction
+	publisher event.Publisher    // Ghi event vào outbox khi region thay đổi
 }
 
 // NewUsecase tạo usecase mới cho region
-func NewUsecase(l log.Logger, repo region.Repository) region.Usecase {
+func NewUsecase(l log.Logger, repo region.Repository, historyRepo history.Repository, tx mongo.Transactor, publisher event.Publisher) region.Usecase {
 	return &implUsecase{
-		l:    l,
-		repo: repo,
+		l:         l,
+		repo:      repo,
+		history:   historyRepo,
+		tx:        tx,
+		publisher: publisher,
 	}
 }
@@ -2,9 +2,11 @@ package usecase
 
 import (
 	"context"
+	"errors"
 
 	"thuchanhgolang/internal/models"
 	"thuchanhgolang/internal/region"
+	"thuchanhgolang/pkg/mongo"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -17,10 +19,22 @@ func (uc *implUsecase) Create(ctx context.Context, sc models.Scope, input region
 		Name:   input.Name,
 	}
 
-	// Bước 2: Gọi repository để lưu vào database
-	newRegion, err := uc.repo.Create(ctx, sc, opts)
+	// Bước 2: Lưu vào database và ghi event vào outbox trong cùng một transaction
+	var newRegion models.Region
+	err := uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		newRegion, err = uc.repo.Create(ctx, sc, opts)
+		if err != nil {
+			uc.l.Errorf(ctx, "region.usecase.Create.repo.Create: %v", err)
+			return err
+		}
+		if err := uc.publisher.Publish(ctx, newEvent(region.EventRegionCreated, sc, newRegion, newPayload(newRegion))); err != nil {
+			uc.l.Errorf(ctx, "region.usecase.Create.publisher.Publish: %v", err)
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		uc.l.Errorf(ctx, "region.usecase.Create.repo.Create: %v", err)
 		return models.Region{}, err
 	}
 
@@ -34,51 +48,108 @@ func (uc *implUsecase) GetByID(ctx context.Context, sc models.Scope, id primitiv
 	region, err := uc.repo.GetByID(ctx, sc, id)
 	if err != nil {
 		uc.l.Errorf(ctx, "region.usecase.GetByID.repo.GetByID: %v", err)
-		return models.Region{}, err
+		return models.Region{}, notFoundError(err)
 	}
 
 	return region, nil
 }
 
+// List lấy danh sách region có phân trang, lọc theo thời gian tạo và cập nhật
+func (uc *implUsecase) List(ctx context.Context, sc models.Scope, input region.ListInput) (region.ListOutput, error) {
+	// Bước 1: Đưa page, limit về giá trị mặc định nếu không hợp lệ
+	input.Pagin.Adjust()
+
+	// Bước 2: Gọi repository để lấy danh sách
+	opts := region.ListOptions{
+		ShopID: input.ShopID,
+		Filter: input.Filter,
+		Pagin:  input.Pagin,
+	}
+	items, pag, err := uc.repo.List(ctx, sc, opts)
+	if err != nil {
+		uc.l.Errorf(ctx, "region.usecase.List.repo.List: %v", err)
+		return region.ListOutput{}, err
+	}
+
+	return region.ListOutput{Regions: items, Pagin: pag}, nil
+}
+
 // Update cập nhật thông tin region (chỉ cho phép đổi tên, không đổi shop)
 func (uc *implUsecase) Update(ctx context.Context, sc models.Scope, input region.UpdateInput) (models.Region, error) {
 	// Bước 1: Chuyển đổi input thành options
 	opts := region.UpdateOptions{
-		ID:   input.ID,
-		Name: input.Name,
+		ID:      input.ID,
+		Name:    input.Name,
+		Version: input.Version,
 	}
 
-	// Bước 2: Gọi repository để update
-	updatedRegion, err := uc.repo.Update(ctx, sc, opts)
+	// Bước 2: Gọi repository để update, sửa và ghi lịch sử chạy trong cùng một transaction
+	var updatedRegion models.Region
+	err := uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		updatedRegion, err = uc.repo.Update(ctx, sc, opts)
+		if err != nil {
+			uc.l.Errorf(ctx, "region.usecase.Update.repo.Update: %v", err)
+			return err
+		}
+		if err := uc.publisher.Publish(ctx, newEvent(region.EventRegionUpdated, sc, updatedRegion, newPayload(updatedRegion))); err != nil {
+			uc.l.Errorf(ctx, "region.usecase.Update.publisher.Publish: %v", err)
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		uc.l.Errorf(ctx, "region.usecase.Update.repo.Update: %v", err)
-		return models.Region{}, err
+		return models.Region{}, notFoundError(err)
 	}
 
 	return updatedRegion, nil
 }
 
 // Delete xóa region (kiểm tra trước xem có đang được dùng không)
-func (uc *implUsecase) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
-	// Bước 1: Kiểm tra xem region có branch nào không
-	hasBranches, err := uc.repo.HasBranches(ctx, id)
-	if err != nil {
-		uc.l.Errorf(ctx, "region.usecase.Delete.repo.HasBranches: %v", err)
-		return err
-	}
+func (uc *implUsecase) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
+	// Kiểm tra và xóa chạy trong cùng một transaction, tránh trường hợp kiểm tra xong mới bị thay đổi
+	return uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		// Bước 1: Kiểm tra xem region có branch nào không
+		hasBranches, err := uc.repo.HasBranches(ctx, id)
+		if err != nil {
+			uc.l.Errorf(ctx, "region.usecase.Delete.repo.HasBranches: %v", err)
+			return err
+		}
 
-	// Bước 2: Nếu có branch, không cho phép xóa
-	if hasBranches {
-		uc.l.Warnf(ctx, "region.usecase.Delete: region is being used by branches")
-		return region.ErrRegionInUse
-	}
+		// Bước 2: Nếu có branch, không cho phép xóa
+		if hasBranches {
+			uc.l.Warnf(ctx, "region.usecase.Delete: region is being used by branches")
+			return region.ErrRegionInUse
+		}
 
-	// Bước 3: Gọi repository để xóa region
-	err = uc.repo.Delete(ctx, sc, id)
-	if err != nil {
-		uc.l.Errorf(ctx, "region.usecase.Delete.repo.Delete: %v", err)
-		return err
-	}
+		// Bước 3: Lấy region để event xóa mang theo vị trí của region
+		r, err := uc.repo.GetByID(ctx, sc, id)
+		if err != nil {
+			uc.l.Errorf(ctx, "region.usecase.Delete.repo.GetByID: %v", err)
+			return notFoundError(err)
+		}
 
-	return nil
+		// Bước 4: Gọi repository để xóa region
+		err = uc.repo.Delete(ctx, sc, id, version)
+		if err != nil {
+			uc.l.Errorf(ctx, "region.usecase.Delete.repo.Delete: %v", err)
+			return notFoundError(err)
+		}
+
+		// Bước 5: Ghi event vào outbox cùng transaction với thao tác xóa
+		if err := uc.publisher.Publish(ctx, newEvent(region.EventRegionDeleted, sc, r, nil)); err != nil {
+			uc.l.Errorf(ctx, "region.usecase.Delete.publisher.Publish: %v", err)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// notFoundError chuyển lỗi không tìm thấy document thành lỗi domain
+func notFoundError(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return region.ErrRegionNotFound
+	}
+	return err
 }
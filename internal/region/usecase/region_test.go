@@ -7,6 +7,7 @@ import (
 
 	"thuchanhgolang/internal/models"
 	"thuchanhgolang/internal/region"
+	"thuchanhgolang/pkg/event"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -20,7 +21,7 @@ func TestCreate(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{l: &mockLogger{}, repo: mockRepo}
+		uc := &implUsecase{l: &mockLogger{}, repo: mockRepo, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		result, err := uc.Create(ctx, models.Scope{}, region.CreateInput{Name: "Test Region"})
 
 		if err != nil {
@@ -39,7 +40,7 @@ func TestCreate(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{l: &mockLogger{}, repo: mockRepo}
+		uc := &implUsecase{l: &mockLogger{}, repo: mockRepo, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		_, err := uc.Create(ctx, models.Scope{}, region.CreateInput{})
 
 		if err == nil {
@@ -58,7 +59,7 @@ func TestGetByID(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{l: &mockLogger{}, repo: mockRepo}
+		uc := &implUsecase{l: &mockLogger{}, repo: mockRepo, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		result, err := uc.GetByID(ctx, models.Scope{}, id)
 
 		if err != nil {
@@ -77,7 +78,7 @@ func TestGetByID(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{l: &mockLogger{}, repo: mockRepo}
+		uc := &implUsecase{l: &mockLogger{}, repo: mockRepo, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		_, err := uc.GetByID(ctx, models.Scope{}, primitive.NewObjectID())
 
 		if err == nil {
@@ -96,9 +97,9 @@ func TestUpdate(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{l: &mockLogger{}, repo: mockRepo}
+		uc := &implUsecase{l: &mockLogger{}, repo: mockRepo, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		name := "Updated"
-		result, err := uc.Update(ctx, models.Scope{}, region.UpdateInput{ID: id, Name: name})
+		result, err := uc.Update(ctx, models.Scope{}, region.UpdateInput{ID: id, Name: &name})
 
 		if err != nil {
 			t.Fatalf("Không mong đợi lỗi: %v", err)
@@ -116,7 +117,7 @@ func TestUpdate(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{l: &mockLogger{}, repo: mockRepo}
+		uc := &implUsecase{l: &mockLogger{}, repo: mockRepo, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		_, err := uc.Update(ctx, models.Scope{}, region.UpdateInput{ID: primitive.NewObjectID()})
 
 		if err == nil {
@@ -128,21 +129,34 @@ func TestUpdate(t *testing.T) {
 func TestDelete(t *testing.T) {
 	t.Run("delete successfully", func(t *testing.T) {
 		ctx := context.Background()
+		existing := models.Region{ID: primitive.NewObjectID(), ShopID: primitive.NewObjectID()}
 		mockRepo := &mockRepository{
 			hasBranchesFunc: func(ctx context.Context, regionID primitive.ObjectID) (bool, error) {
 				return false, nil
 			},
-			deleteFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+			getByIDFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Region, error) {
+				return existing, nil
+			},
+			deleteFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
 				return nil
 			},
 		}
 
-		uc := &implUsecase{l: &mockLogger{}, repo: mockRepo}
-		err := uc.Delete(ctx, models.Scope{}, primitive.NewObjectID())
+		tx := &mockTransactor{}
+		publisher := event.NewMemoryPublisher()
+		uc := &implUsecase{l: &mockLogger{}, repo: mockRepo, tx: tx, publisher: publisher}
+		err := uc.Delete(ctx, models.Scope{}, existing.ID, 0)
 
 		if err != nil {
 			t.Fatalf("Không mong đợi lỗi: %v", err)
 		}
+		if tx.calls != 1 {
+			t.Errorf("Delete phải chạy trong transaction, số lần gọi: %d", tx.calls)
+		}
+		events := publisher.Events()
+		if len(events) != 1 || events[0].ShopID != existing.ShopID.Hex() || events[0].RegionID != existing.ID.Hex() {
+			t.Errorf("Event xóa phải mang vị trí của region: %+v", events)
+		}
 	})
 
 	t.Run("delete with branches exists", func(t *testing.T) {
@@ -153,8 +167,8 @@ func TestDelete(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{l: &mockLogger{}, repo: mockRepo}
-		err := uc.Delete(ctx, models.Scope{}, primitive.NewObjectID())
+		uc := &implUsecase{l: &mockLogger{}, repo: mockRepo, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		err := uc.Delete(ctx, models.Scope{}, primitive.NewObjectID(), 0)
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi khi region có branches")
@@ -169,8 +183,8 @@ func TestDelete(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{l: &mockLogger{}, repo: mockRepo}
-		err := uc.Delete(ctx, models.Scope{}, primitive.NewObjectID())
+		uc := &implUsecase{l: &mockLogger{}, repo: mockRepo, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		err := uc.Delete(ctx, models.Scope{}, primitive.NewObjectID(), 0)
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi")
@@ -183,13 +197,13 @@ func TestDelete(t *testing.T) {
 			hasBranchesFunc: func(ctx context.Context, regionID primitive.ObjectID) (bool, error) {
 				return false, nil
 			},
-			deleteFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+			deleteFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
 				return errors.New("delete failed")
 			},
 		}
 
-		uc := &implUsecase{l: &mockLogger{}, repo: mockRepo}
-		err := uc.Delete(ctx, models.Scope{}, primitive.NewObjectID())
+		uc := &implUsecase{l: &mockLogger{}, repo: mockRepo, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		err := uc.Delete(ctx, models.Scope{}, primitive.NewObjectID(), 0)
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi")
@@ -6,6 +6,7 @@ import (
 
 	"thuchanhgolang/internal/models"
 	"thuchanhgolang/internal/region"
+	"thuchanhgolang/pkg/paginator"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -14,8 +15,9 @@ import (
 type mockRepository struct {
 	createFunc      func(ctx context.Context, sc models.Scope, opts region.CreateOptions) (models.Region, error)
 	getByIDFunc     func(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Region, error)
+	listFunc        func(ctx context.Context, sc models.Scope, opts region.ListOptions) ([]models.Region, paginator.Paginator, error)
 	updateFunc      func(ctx context.Context, sc models.Scope, opts region.UpdateOptions) (models.Region, error)
-	deleteFunc      func(ctx context.Context, sc models.Scope, id primitive.ObjectID) error
+	deleteFunc      func(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error
 	hasBranchesFunc func(ctx context.Context, regionID primitive.ObjectID) (bool, error)
 }
 
@@ -33,6 +35,13 @@ func (m *mockRepository) GetByID(ctx context.Context, sc models.Scope, id primit
 	return models.Region{}, errors.New("mock GetByID not implemented")
 }
 
+func (m *mockRepository) List(ctx context.Context, sc models.Scope, opts region.ListOptions) ([]models.Region, paginator.Paginator, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, sc, opts)
+	}
+	return nil, paginator.Paginator{}, nil
+}
+
 func (m *mockRepository) Update(ctx context.Context, sc models.Scope, opts region.UpdateOptions) (models.Region, error) {
 	if m.updateFunc != nil {
 		return m.updateFunc(ctx, sc, opts)
@@ -40,9 +49,9 @@ func (m *mockRepository) Update(ctx context.Context, sc models.Scope, opts regio
 	return models.Region{}, errors.New("mock Update not implemented")
 }
 
-func (m *mockRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+func (m *mockRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
 	if m.deleteFunc != nil {
-		return m.deleteFunc(ctx, sc, id)
+		return m.deleteFunc(ctx, sc, id, version)
 	}
 	return errors.New("mock Delete not implemented")
 }
@@ -67,3 +76,12 @@ func (m *mockLogger) Error(ctx context.Context, arg ...any)                   {}
 func (m *mockLogger) Errorf(ctx context.Context, template string, arg ...any) {}
 func (m *mockLogger) Fatal(ctx context.Context, arg ...any)                   {}
 func (m *mockLogger) Fatalf(ctx context.Context, template string, arg ...any) {}
+
+type mockTransactor struct {
+	calls int
+}
+
+func (m *mockTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	return fn(ctx)
+}
@@ -0,0 +1,27 @@
+package grpc
+
+import (
+	"errors"
+
+	"thuchanhgolang/internal/shop"
+	pkgErrors "thuchanhgolang/pkg/errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errUnauthorized = status.Error(codes.Unauthenticated, "Unauthorized")
+	errForbidden    = status.Error(codes.PermissionDenied, "Forbidden: You don't have permission to access this resource")
+
+	errCodeExists = pkgErrors.NewConflictError(10009, "Shop code already exists", "code")
+)
+
+// mapError chuyển lỗi trùng giá trị duy nhất thành ConflictError (AlreadyExists kèm field) như REST,
+// các lỗi nghiệp vụ khác được chuyển theo loại lỗi ở grpcserver
+func (h handler) mapError(err error) error {
+	if errors.Is(err, shop.ErrCodeExists) {
+		return errCodeExists
+	}
+	return err
+}
@@ -0,0 +1,100 @@
+package grpc
+
+import (
+	"context"
+
+	"thuchanhgolang/pkg/orgapi"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Get lấy shop theo ID
+func (h handler) Get(ctx context.Context, req *orgapi.GetRequest) (*orgapi.Shop, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để lấy shop
+	id, _ := primitive.ObjectIDFromHex(req.ID)
+	shop, err := h.uc.GetByID(ctx, sc, id)
+	if err != nil {
+		h.l.Warnf(ctx, "shop.grpc.Get.uc.GetByID: %s", err)
+		return nil, err
+	}
+
+	return h.newShop(shop), nil
+}
+
+// List lấy danh sách shop có phân trang
+func (h handler) List(ctx context.Context, req *orgapi.ListShopsRequest) (*orgapi.ListShopsResponse, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để lấy danh sách
+	out, err := h.uc.List(ctx, sc, h.toListInput(req))
+	if err != nil {
+		h.l.Warnf(ctx, "shop.grpc.List.uc.List: %s", err)
+		return nil, err
+	}
+
+	return h.newListResp(out), nil
+}
+
+// Create tạo shop mới
+func (h handler) Create(ctx context.Context, req *orgapi.CreateShopRequest) (*orgapi.Shop, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để tạo shop
+	shop, err := h.uc.Create(ctx, sc, h.toCreateInput(req))
+	if err != nil {
+		h.l.Warnf(ctx, "shop.grpc.Create.uc.Create: %s", err)
+		return nil, h.mapError(err)
+	}
+
+	return h.newShop(shop), nil
+}
+
+// Update cập nhật shop, chỉ khi shop chưa bị sửa sau version client đã đọc
+func (h handler) Update(ctx context.Context, req *orgapi.UpdateShopRequest) (*orgapi.Shop, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để cập nhật shop
+	shop, err := h.uc.Update(ctx, sc, h.toUpdateInput(req))
+	if err != nil {
+		h.l.Warnf(ctx, "shop.grpc.Update.uc.Update: %s", err)
+		return nil, h.mapError(err)
+	}
+
+	return h.newShop(shop), nil
+}
+
+// Delete xóa shop, chỉ khi shop chưa bị sửa sau version client đã đọc
+func (h handler) Delete(ctx context.Context, req *orgapi.DeleteRequest) (*orgapi.Empty, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để xóa shop
+	id, _ := primitive.ObjectIDFromHex(req.ID)
+	if err := h.uc.Delete(ctx, sc, id, req.Version); err != nil {
+		h.l.Warnf(ctx, "shop.grpc.Delete.uc.Delete: %s", err)
+		return nil, err
+	}
+
+	return &orgapi.Empty{}, nil
+}
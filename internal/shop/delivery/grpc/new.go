@@ -0,0 +1,21 @@
+package grpc
+
+import (
+	"thuchanhgolang/internal/shop"
+	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/orgapi"
+)
+
+// handler là implementation của orgapi.ShopServiceServer
+type handler struct {
+	l  log.Logger   // Logger để ghi log
+	uc shop.Usecase // Usecase để xử lý business logic
+}
+
+// New tạo gRPC service cho shop, dùng chung usecase với REST
+func New(l log.Logger, uc shop.Usecase) orgapi.ShopServiceServer {
+	return handler{
+		l:  l,
+		uc: uc,
+	}
+}
@@ -0,0 +1,74 @@
+package grpc
+
+import (
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/shop"
+	"thuchanhgolang/pkg/orgapi"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// toCreateInput chuyển đổi request thành input cho usecase, định dạng đã được kiểm tra qua binding tag
+func (h handler) toCreateInput(req *orgapi.CreateShopRequest) shop.CreateInput {
+	return shop.CreateInput{
+		Name: req.Name,
+		Code: req.Code,
+	}
+}
+
+// toUpdateInput chuyển đổi request thành input cho usecase
+func (h handler) toUpdateInput(req *orgapi.UpdateShopRequest) shop.UpdateInput {
+	id, _ := primitive.ObjectIDFromHex(req.ID)
+	return shop.UpdateInput{
+		ID:      id,
+		Name:    req.Name,
+		Code:    req.Code,
+		Version: req.Version,
+	}
+}
+
+// toListInput chuyển đổi request thành input cho usecase
+func (h handler) toListInput(req *orgapi.ListShopsRequest) shop.ListInput {
+	return shop.ListInput{
+		Pagin: paginator.PaginatorQuery{Page: req.Page.Page, Limit: req.Page.Limit},
+	}
+}
+
+// newShop tạo message từ shop model
+func (h handler) newShop(d models.Shop) *orgapi.Shop {
+	return &orgapi.Shop{
+		ID:        d.ID.Hex(),
+		Name:      d.Name,
+		Code:      d.Code,
+		Version:   d.Version,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+		CreatedBy: d.CreatedBy,
+		UpdatedBy: d.UpdatedBy,
+	}
+}
+
+// newListResp tạo message từ danh sách shop
+func (h handler) newListResp(out shop.ListOutput) *orgapi.ListShopsResponse {
+	items := make([]orgapi.Shop, 0, len(out.Shops))
+	for _, d := range out.Shops {
+		items = append(items, *h.newShop(d))
+	}
+	return &orgapi.ListShopsResponse{
+		Items: items,
+		Meta:  newPageInfo(out.Pagin),
+	}
+}
+
+// newPageInfo tạo thông tin phân trang
+func newPageInfo(p paginator.Paginator) orgapi.PageInfo {
+	resp := p.ToResponse()
+	return orgapi.PageInfo{
+		Total:       resp.Total,
+		Count:       resp.Count,
+		PerPage:     resp.PerPage,
+		CurrentPage: resp.CurrentPage,
+		TotalPages:  resp.TotalPages,
+	}
+}
@@ -0,0 +1,29 @@
+package grpc
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/jwt"
+)
+
+// processScope lấy scope của user đang đăng nhập và kiểm tra quyền như middleware CheckShopAccess của REST:
+// chỉ Manager, và chỉ với shop của mình khi có id
+func (h handler) processScope(ctx context.Context, id string) (models.Scope, error) {
+	payload, ok := jwt.GetPayloadFromContext(ctx)
+	if !ok {
+		return models.Scope{}, errUnauthorized
+	}
+
+	role := models.Role(payload.Role)
+	if role != models.RoleManager {
+		h.l.Warnf(ctx, "shop.grpc.processScope: user role %s not allowed", role)
+		return models.Scope{}, errForbidden
+	}
+	if id != "" && payload.ShopID != id {
+		h.l.Warnf(ctx, "shop.grpc.processScope: shop_id mismatch")
+		return models.Scope{}, errForbidden
+	}
+
+	return jwt.NewScope(payload), nil
+}
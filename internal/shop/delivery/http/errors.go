@@ -2,14 +2,24 @@ package http
 
 import (
 	"errors"
+	"net/http"
 	"thuchanhgolang/internal/shop"
 	pkgErrors "thuchanhgolang/pkg/errors"
 )
 
 var (
-	errWrongBody = pkgErrors.NewHTTPError(10000, "Wrong body")
-	errInvalidID = pkgErrors.NewHTTPError(10001, "Invalid shop ID")
-	errShopInUse = pkgErrors.NewHTTPError(10003, "Shop is being used by regions, cannot delete")
+	errWrongBody    = pkgErrors.NewHTTPError(10000, "Wrong body")
+	errInvalidID    = pkgErrors.NewHTTPError(10001, "Invalid shop ID")
+	errShopInUse    = pkgErrors.NewHTTPErrorWithStatus(10003, "Shop is being used by regions, cannot delete", http.StatusConflict)
+	errShopNotFound = pkgErrors.NewHTTPErrorWithStatus(10005, "Shop not found", http.StatusNotFound)
+
+	errCodeExists = pkgErrors.NewConflictError(10009, "Shop code already exists", "code")
+
+	errVersionMismatch      = pkgErrors.NewHTTPErrorWithStatus(10010, "Shop has been modified by another request, reload and try again", http.StatusPreconditionFailed)
+	errPreconditionRequired = pkgErrors.NewHTTPErrorWithStatus(10011, "If-Match header with the shop ETag is required", http.StatusPreconditionRequired)
+
+	errWrongQuery   = pkgErrors.NewHTTPError(10012, "Wrong query")
+	errUnauthorized = pkgErrors.NewUnauthorizedHTTPError()
 )
 
 func (h handler) mapError(err error) error {
@@ -17,5 +27,14 @@ func (h handler) mapError(err error) error {
 	if errors.Is(err, shop.ErrShopInUse) {
 		return errShopInUse
 	}
+	if errors.Is(err, shop.ErrShopNotFound) {
+		return errShopNotFound
+	}
+	if errors.Is(err, shop.ErrCodeExists) {
+		return errCodeExists
+	}
+	if errors.Is(err, shop.ErrVersionMismatch) {
+		return errVersionMismatch
+	}
 	return err
 }
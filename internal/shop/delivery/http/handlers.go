@@ -1,7 +1,11 @@
 package http
 
 import (
+	"thuchanhgolang/internal/cascade"
+	cascadeHTTP "thuchanhgolang/internal/cascade/delivery/http"
+	"thuchanhgolang/internal/models"
 	"thuchanhgolang/pkg/response"
+	"thuchanhgolang/pkg/util"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -30,6 +34,7 @@ func (h handler) create(c *gin.Context) {
 	}
 
 	// Bước 3: Trả về kết quả thành công
+	util.SetETag(c, shop.Version)
 	response.OK(c, h.newDetailResp(shop))
 }
 
@@ -46,8 +51,21 @@ func (h handler) getByID(c *gin.Context) {
 		return
 	}
 
-	// Bước 2: Gọi usecase để lấy shop
-	shop, err := h.uc.GetByID(ctx, h.emptyScope(), id)
+	// Bước 2: Đọc thời điểm cần xem (as_of), không có thì xem trạng thái hiện tại
+	asOf, err := h.processAsOf(c)
+	if err != nil {
+		h.l.Warnf(ctx, "shop.handler.getByID.processAsOf: %s", err)
+		response.Error(c, err)
+		return
+	}
+
+	// Bước 3: Gọi usecase để lấy shop
+	var shop models.Shop
+	if asOf != nil {
+		shop, err = h.uc.GetAsOf(ctx, h.emptyScope(), id, *asOf)
+	} else {
+		shop, err = h.uc.GetByID(ctx, h.emptyScope(), id)
+	}
 	if err != nil {
 		h.l.Warnf(ctx, "shop.handler.getByID.uc.GetByID: %s", err)
 		mapErr := h.mapError(err)
@@ -55,10 +73,74 @@ func (h handler) getByID(c *gin.Context) {
 		return
 	}
 
-	// Bước 3: Trả về kết quả
+	// Bước 4: Trả về kết quả, trạng thái trong quá khứ không có ETag vì không dùng để sửa được
+	if asOf == nil {
+		util.SetETag(c, shop.Version)
+	}
 	response.OK(c, h.newDetailResp(shop))
 }
 
+// history xử lý HTTP request để lấy lịch sử thay đổi của shop
+func (h handler) history(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Lấy ID từ URL param
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		h.l.Warnf(ctx, "shop.handler.history.ObjectIDFromHex: %s", err)
+		response.Error(c, errInvalidID)
+		return
+	}
+
+	// Bước 2: Xử lý và validate query
+	req, sc, err := h.processHistoryRequest(c)
+	if err != nil {
+		h.l.Warnf(ctx, "shop.handler.history.processHistoryRequest: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 3: Gọi usecase để lấy lịch sử thay đổi
+	out, err := h.uc.History(ctx, sc, req.toInput(id))
+	if err != nil {
+		h.l.Warnf(ctx, "shop.handler.history.uc.History: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 4: Trả về lịch sử, mới nhất đứng trước
+	response.OK(c, h.newHistoryResp(out))
+}
+
+// list xử lý HTTP request để lấy danh sách shop
+func (h handler) list(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Xử lý và validate query
+	req, sc, err := h.processListRequest(c)
+	if err != nil {
+		h.l.Warnf(ctx, "shop.handler.list.processListRequest: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 2: Gọi usecase để lấy danh sách shop
+	out, err := h.uc.List(ctx, sc, req.toInput())
+	if err != nil {
+		h.l.Warnf(ctx, "shop.handler.list.uc.List: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 3: Trả về danh sách
+	response.OK(c, h.newListResp(out))
+}
+
 // update xử lý HTTP request để cập nhật shop
 func (h handler) update(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -91,6 +173,43 @@ func (h handler) update(c *gin.Context) {
 	}
 
 	// Bước 4: Trả về kết quả
+	util.SetETag(c, shop.Version)
+	response.OK(c, h.newDetailResp(shop))
+}
+
+// patch xử lý HTTP request cập nhật một phần shop theo JSON Merge Patch
+func (h handler) patch(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Lấy ID từ URL param
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		h.l.Warnf(ctx, "shop.handler.patch.ObjectIDFromHex: %s", err)
+		response.Error(c, errInvalidID)
+		return
+	}
+
+	// Bước 2: Xử lý và validate request
+	req, sc, err := h.processPatchRequest(c)
+	if err != nil {
+		h.l.Warnf(ctx, "shop.handler.patch.processPatchRequest: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 3: Gọi usecase để update shop
+	shop, err := h.uc.Update(ctx, sc, req.toInput(id))
+	if err != nil {
+		h.l.Warnf(ctx, "shop.handler.patch.uc.Update: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 4: Trả về kết quả
+	util.SetETag(c, shop.Version)
 	response.OK(c, h.newDetailResp(shop))
 }
 
@@ -107,8 +226,47 @@ func (h handler) delete(c *gin.Context) {
 		return
 	}
 
-	// Bước 2: Gọi usecase để xóa shop
-	err = h.uc.Delete(ctx, h.emptyScope(), id)
+	// Bước 2: Đọc query cascade, dry_run, reassign_branch_id
+	req, err := cascadeHTTP.ProcessDeleteRequest(c)
+	if err != nil {
+		h.l.Warnf(ctx, "shop.handler.delete.ProcessDeleteRequest: %s", err)
+		response.Error(c, err)
+		return
+	}
+
+	// Bước 3: Lấy scope của user đang đăng nhập, dùng để kiểm tra phạm vi và ghi lại người xóa
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "shop.handler.delete.processScope: %s", err)
+		response.Error(c, err)
+		return
+	}
+
+	// Bước 4: Đọc version từ If-Match, xem trước (dry_run) thì không cần
+	version := 0
+	if !(req.Cascade && req.DryRun) {
+		version, err = h.processVersion(c)
+		if err != nil {
+			h.l.Warnf(ctx, "shop.handler.delete.processVersion: %s", err)
+			response.Error(c, err)
+			return
+		}
+	}
+
+	// Bước 5: Xóa dây chuyền (hoặc xem trước) nếu có cascade=true
+	if req.Cascade {
+		out, err := h.cascadeUC.Delete(ctx, sc, req.ToInput(cascade.LevelShop, id, version))
+		if err != nil {
+			h.l.Warnf(ctx, "shop.handler.delete.cascadeUC.Delete: %s", err)
+			response.Error(c, cascadeHTTP.MapError(err))
+			return
+		}
+		response.OK(c, cascadeHTTP.NewDeleteResp(out))
+		return
+	}
+
+	// Bước 6: Gọi usecase để xóa shop
+	err = h.uc.Delete(ctx, sc, id, version)
 	if err != nil {
 		h.l.Warnf(ctx, "shop.handler.delete.uc.Delete: %s", err)
 		mapErr := h.mapError(err)
@@ -116,6 +274,6 @@ func (h handler) delete(c *gin.Context) {
 		return
 	}
 
-	// Bước 3: Trả về success
+	// Bước 7: Trả về success
 	response.OK(c, gin.H{"message": "Shop deleted successfully"})
 }
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/shop"
+	pkgErrors "thuchanhgolang/pkg/errors"
+	"thuchanhgolang/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeUsecase giả lập shop đang ở version currentVersion, chỉ dùng Update
+type fakeUsecase struct {
+	shop.Usecase
+	currentVersion int
+	gotVersion     int
+}
+
+func (f *fakeUsecase) Update(ctx context.Context, sc models.Scope, input shop.UpdateInput) (models.Shop, error) {
+	f.gotVersion = input.Version
+	if input.Version > 0 && input.Version != f.currentVersion {
+		return models.Shop{}, shop.ErrVersionMismatch
+	}
+	return models.Shop{ID: input.ID, Name: *input.Name, Version: f.currentVersion + 1}, nil
+}
+
+type mockLogger struct{}
+
+func (m *mockLogger) Debug(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Debugf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Info(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Infof(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Warn(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Warnf(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Error(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Errorf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Fatal(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Fatalf(ctx context.Context, template string, arg ...any) {}
+
+func TestUpdateIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	v, _ := binding.Validator.Engine().(*validator.Validate)
+	if err := pkgErrors.RegisterValidators(v, func(r string) bool { return models.Role(r).IsValid() }); err != nil {
+		t.Fatalf("Không mong đợi lỗi: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		ifMatch     string
+		wantStatus  int
+		wantETag    string
+		wantVersion int // Version usecase nhận được, -1 khi usecase không được gọi
+	}{
+		{name: "không có If-Match", wantStatus: http.StatusPreconditionRequired, wantVersion: -1},
+		{name: "weak ETag", ifMatch: `W/"3"`, wantStatus: http.StatusPreconditionFailed, wantVersion: -1},
+		{name: "ETag không hợp lệ", ifMatch: `"abc"`, wantStatus: http.StatusPreconditionFailed, wantVersion: -1},
+		{name: "version cũ", ifMatch: `"2"`, wantStatus: http.StatusPreconditionFailed, wantVersion: 2},
+		{name: "wildcard bỏ qua kiểm tra version", ifMatch: "*", wantStatus: http.StatusOK, wantETag: `"4"`},
+		{name: "version khớp", ifMatch: `"3"`, wantStatus: http.StatusOK, wantETag: `"4"`, wantVersion: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &fakeUsecase{currentVersion: 3, gotVersion: -1}
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				payload := jwt.Payload{UserID: "u1", Role: string(models.RoleManager)}
+				c.Request = c.Request.WithContext(jwt.SetPayloadToContext(c.Request.Context(), payload))
+			})
+			MapRoutes(r.Group("/shops"), New(&mockLogger{}, uc, nil))
+
+			req := httptest.NewRequest(http.MethodPut, "/shops/"+primitive.NewObjectID().Hex(), strings.NewReader(`{"name": "Shop mới"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Mong đợi status %d, nhận được %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if got := w.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("Mong đợi ETag %q, nhận được %q", tt.wantETag, got)
+			}
+			if uc.gotVersion != tt.wantVersion {
+				t.Errorf("Mong đợi usecase nhận version %d, nhận được %d", tt.wantVersion, uc.gotVersion)
+			}
+		})
+	}
+}
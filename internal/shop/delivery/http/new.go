@@ -1,6 +1,7 @@
 package http
 
 import (
+	"thuchanhgolang/internal/cascade"
 	"thuchanhgolang/internal/models"
 	"thuchanhgolang/internal/shop"
 	"thuchanhgolang/pkg/log"
@@ -12,21 +13,26 @@ import (
 type Handler interface {
 	create(c *gin.Context)
 	getByID(c *gin.Context)
+	history(c *gin.Context)
+	list(c *gin.Context)
 	update(c *gin.Context)
+	patch(c *gin.Context)
 	delete(c *gin.Context)
 }
 
 // handler là implementation của Handler interface
 type handler struct {
-	l  log.Logger   // Logger để ghi log
-	uc shop.Usecase // Usecase để xử lý business logic
+	l         log.Logger      // Logger để ghi log
+	uc        shop.Usecase    // Usecase để xử lý business logic
+	cascadeUC cascade.Usecase // Usecase xóa dây chuyền
 }
 
 // New tạo HTTP handler mới cho shop
-func New(l log.Logger, uc shop.Usecase) Handler {
+func New(l log.Logger, uc shop.Usecase, cascadeUC cascade.Usecase) Handler {
 	return handler{
-		l:  l,
-		uc: uc,
+		l:         l,
+		uc:        uc,
+		cascadeUC: cascadeUC,
 	}
 }
 
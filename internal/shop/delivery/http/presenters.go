@@ -1,32 +1,23 @@
 package http
 
 import (
-	"strings"
+	"errors"
 
 	"thuchanhgolang/internal/models"
 	"thuchanhgolang/internal/shop"
+	pkgErrors "thuchanhgolang/pkg/errors"
+	"thuchanhgolang/pkg/paginator"
+	"thuchanhgolang/pkg/patch"
 	"thuchanhgolang/pkg/response"
+	"thuchanhgolang/pkg/util"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // createReq là cấu trúc nhận dữ liệu từ HTTP request
 type createReq struct {
-	Name string `json:"name" binding:"required"` // Tên shop (bắt buộc)
-	Code string `json:"code" binding:"required"` // Mã code shop (bắt buộc)
-}
-
-// validate kiểm tra dữ liệu đầu vào
-func (r createReq) validate() error {
-	// Kiểm tra Name không được rỗng
-	if strings.TrimSpace(r.Name) == "" {
-		return errWrongBody
-	}
-	// Kiểm tra Code không được rỗng
-	if strings.TrimSpace(r.Code) == "" {
-		return errWrongBody
-	}
-	return nil
+	Name string `json:"name" binding:"required,notblank,max=255"` // Tên shop (bắt buộc)
+	Code string `json:"code" binding:"required,shopcode"`         // Mã code shop (bắt buộc, A-Z, 0-9, - hoặc _)
 }
 
 // toInput chuyển đổi request thành input cho usecase
@@ -39,42 +30,123 @@ func (r createReq) toInput() shop.CreateInput {
 
 // updateReq là cấu trúc nhận dữ liệu update từ HTTP request
 type updateReq struct {
-	Name *string `json:"name"` // Tên shop mới (optional)
-	Code *string `json:"code"` // Code mới (optional)
+	Name *string `json:"name" binding:"omitempty,notblank,max=255"` // Tên shop mới (optional)
+	Code *string `json:"code" binding:"omitempty,shopcode"`         // Code mới (optional)
+
+	version int // Version lấy từ If-Match, không đọc từ body
 }
 
-// validate kiểm tra dữ liệu update
+// validate kiểm tra dữ liệu update, định dạng từng field đã được kiểm tra qua binding tag
 func (r updateReq) validate() error {
 	// Ít nhất 1 field phải có giá trị
 	if r.Name == nil && r.Code == nil {
 		return errWrongBody
 	}
-	// Nếu Name có giá trị, không được rỗng
-	if r.Name != nil && strings.TrimSpace(*r.Name) == "" {
-		return errWrongBody
+	return nil
+}
+
+// toInput chuyển đổi update request thành input cho usecase
+func (r updateReq) toInput(id primitive.ObjectID) shop.UpdateInput {
+	return shop.UpdateInput{
+		ID:      id,
+		Name:    r.Name,
+		Code:    r.Code,
+		Version: r.version,
 	}
-	// Nếu Code có giá trị, không được rỗng
-	if r.Code != nil && strings.TrimSpace(*r.Code) == "" {
+}
+
+// patchReq là JSON Merge Patch (RFC 7396) của shop: field không gửi thì giữ nguyên, các field đều bắt buộc nên không nhận null
+type patchReq struct {
+	Name patch.Field[string] `json:"name" binding:"omitempty,notblank,max=255"` // Tên shop mới (không được null)
+	Code patch.Field[string] `json:"code" binding:"omitempty,shopcode"`         // Code mới (không được null)
+
+	version int // Version lấy từ If-Match, không đọc từ body
+}
+
+// validate từ chối null cho field bắt buộc, định dạng từng field đã được kiểm tra qua binding tag
+func (r patchReq) validate() error {
+	// Ít nhất 1 field phải được gửi
+	if !r.Name.Present && !r.Code.Present {
 		return errWrongBody
 	}
+
+	collector := pkgErrors.NewValidationErrorCollector()
+	if r.Name.IsNull() {
+		collector.Add(pkgErrors.NewValidationError("name", "cannot be null"))
+	}
+	if r.Code.IsNull() {
+		collector.Add(pkgErrors.NewValidationError("code", "cannot be null"))
+	}
+	if collector.HasError() {
+		return collector
+	}
 	return nil
 }
 
-// toInput chuyển đổi update request thành input cho usecase
-func (r updateReq) toInput(id primitive.ObjectID) shop.UpdateInput {
+// toInput chuyển đổi merge patch thành input cho usecase
+func (r patchReq) toInput(id primitive.ObjectID) shop.UpdateInput {
 	return shop.UpdateInput{
-		ID:   id,
-		Name: r.Name,
-		Code: r.Code,
+		ID:      id,
+		Name:    r.Name.Ptr(),
+		Code:    r.Code.Ptr(),
+		Version: r.version,
+	}
+}
+
+// listReq là query lấy danh sách shop, các mốc thời gian theo định dạng "2006-01-02 15:04:05"
+type listReq struct {
+	paginator.PaginatorQuery
+	CreatedFrom string `form:"created_from"` // Tạo từ thời điểm (optional)
+	CreatedTo   string `form:"created_to"`   // Tạo đến thời điểm (optional)
+	UpdatedFrom string `form:"updated_from"` // Cập nhật từ thời điểm (optional)
+	UpdatedTo   string `form:"updated_to"`   // Cập nhật đến thời điểm (optional)
+}
+
+// validate kiểm tra query lấy danh sách
+func (r listReq) validate() error {
+	// Các mốc thời gian phải đúng định dạng, mốc bắt đầu không được sau mốc kết thúc
+	filter, err := r.timeFilter()
+	if err != nil || !filter.IsValid() {
+		return errWrongQuery
+	}
+	return nil
+}
+
+// toInput chuyển đổi query thành input cho usecase
+func (r listReq) toInput() shop.ListInput {
+	filter, _ := r.timeFilter()
+	input := shop.ListInput{
+		Filter: filter,
+		Pagin:  r.PaginatorQuery,
 	}
+	return input
+}
+
+// timeFilter đọc các mốc thời gian trong query, mốc rỗng thì không giới hạn
+func (r listReq) timeFilter() (models.TimeFilter, error) {
+	createdFrom, errCreatedFrom := util.StrToDateTimePtr(r.CreatedFrom)
+	createdTo, errCreatedTo := util.StrToDateTimePtr(r.CreatedTo)
+	updatedFrom, errUpdatedFrom := util.StrToDateTimePtr(r.UpdatedFrom)
+	updatedTo, errUpdatedTo := util.StrToDateTimePtr(r.UpdatedTo)
+
+	filter := models.TimeFilter{
+		CreatedFrom: createdFrom,
+		CreatedTo:   createdTo,
+		UpdatedFrom: updatedFrom,
+		UpdatedTo:   updatedTo,
+	}
+	return filter, errors.Join(errCreatedFrom, errCreatedTo, errUpdatedFrom, errUpdatedTo)
 }
 
 // detailResp là cấu trúc trả về cho client
 type detailResp struct {
-	ID        string            `json:"id"`         // ID của shop
-	Name      string            `json:"name"`       // Tên shop
-	Code      string            `json:"code"`       // Mã code shop
-	CreatedAt response.DateTime `json:"created_at"` // Thời gian tạo
+	ID        string            `json:"id"`                   // ID của shop
+	Name      string            `json:"name"`                 // Tên shop
+	Code      string            `json:"code"`                 // Mã code shop
+	CreatedAt response.DateTime `json:"created_at"`           // Thời gian tạo
+	UpdatedAt response.DateTime `json:"updated_at"`           // Thời gian cập nhật gần nhất
+	CreatedBy string            `json:"created_by,omitempty"` // ID người tạo
+	UpdatedBy string            `json:"updated_by,omitempty"` // ID người cập nhật gần nhất
 }
 
 // newDetailResp tạo response từ shop model
@@ -84,5 +156,74 @@ func (h handler) newDetailResp(d models.Shop) detailResp {
 		Name:      d.Name,
 		Code:      d.Code,
 		CreatedAt: response.DateTime(d.CreatedAt),
+		UpdatedAt: response.DateTime(d.UpdatedAt),
+		CreatedBy: d.CreatedBy,
+		UpdatedBy: d.UpdatedBy,
+	}
+}
+
+// listResp là danh sách shop trả về cho client
+type listResp struct {
+	Items []detailResp                `json:"items"` // Các shop của trang hiện tại
+	Meta  paginator.PaginatorResponse `json:"meta"`  // Thông tin phân trang
+}
+
+// newListResp tạo response từ danh sách shop
+func (h handler) newListResp(out shop.ListOutput) listResp {
+	items := make([]detailResp, 0, len(out.Shops))
+	for _, d := range out.Shops {
+		items = append(items, h.newDetailResp(d))
+	}
+	return listResp{
+		Items: items,
+		Meta:  out.Pagin.ToResponse(),
+	}
+}
+
+// historyReq là query lấy lịch sử thay đổi của shop
+type historyReq struct {
+	paginator.PaginatorQuery
+}
+
+// toInput chuyển đổi query thành input cho usecase
+func (r historyReq) toInput(id primitive.ObjectID) shop.HistoryInput {
+	return shop.HistoryInput{
+		ID:    id,
+		Pagin: r.PaginatorQuery,
+	}
+}
+
+// revisionResp là trạng thái của shop trước một lần sửa hoặc xóa
+type revisionResp struct {
+	Version   int               `json:"version"`              // Version của snapshot
+	Action    string            `json:"action"`               // Thao tác đã thay thế snapshot: update | delete
+	ValidFrom response.DateTime `json:"valid_from"`           // Snapshot có hiệu lực từ
+	ValidTo   response.DateTime `json:"valid_to"`             // Snapshot bị thay thế lúc
+	ChangedBy string            `json:"changed_by,omitempty"` // ID người sửa / xóa
+	Snapshot  detailResp        `json:"snapshot"`             // Trạng thái của shop
+}
+
+// historyResp là lịch sử thay đổi của shop trả về cho client
+type historyResp struct {
+	Items []revisionResp              `json:"items"` // Các revision của trang hiện tại, mới nhất đứng trước
+	Meta  paginator.PaginatorResponse `json:"meta"`  // Thông tin phân trang
+}
+
+// newHistoryResp tạo response từ lịch sử thay đổi của shop
+func (h handler) newHistoryResp(out shop.HistoryOutput) historyResp {
+	items := make([]revisionResp, 0, len(out.Revisions))
+	for _, r := range out.Revisions {
+		items = append(items, revisionResp{
+			Version:   r.Shop.Version,
+			Action:    string(r.Action),
+			ValidFrom: response.DateTime(r.Shop.UpdatedAt),
+			ValidTo:   response.DateTime(r.ChangedAt),
+			ChangedBy: r.ChangedBy,
+			Snapshot:  h.newDetailResp(r.Shop),
+		})
+	}
+	return historyResp{
+		Items: items,
+		Meta:  out.Pagin.ToResponse(),
 	}
 }
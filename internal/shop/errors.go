@@ -1,8 +1,17 @@
 package shop
 
-import "errors"
+import pkgErrors "thuchanhgolang/pkg/errors"
 
 var (
 	// ErrShopInUse trả về khi shop đang được sử dụng bởi region
-	ErrShopInUse = errors.New("shop is being used by regions, cannot delete")
+	ErrShopInUse = pkgErrors.Conflict("shop is being used by regions, cannot delete")
+
+	// ErrCodeExists trả về khi code đã được shop khác sử dụng
+	ErrCodeExists = pkgErrors.Conflict("shop code already exists")
+
+	// ErrShopNotFound trả về khi không tìm thấy shop
+	ErrShopNotFound = pkgErrors.NotFound("shop not found")
+
+	// ErrVersionMismatch trả về khi shop đã bị thay đổi sau lần đọc của client (If-Match không khớp version)
+	ErrVersionMismatch = pkgErrors.PreconditionFailed("shop has been modified by another request")
 )
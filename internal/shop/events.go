@@ -0,0 +1,13 @@
+package shop
+
+const (
+	// AggregateType là loại aggregate của các event shop
+	AggregateType = "shop"
+
+	// EventShopCreated được phát khi shop được tạo
+	EventShopCreated = "shop.created"
+	// EventShopUpdated được phát khi thông tin shop thay đổi
+	EventShopUpdated = "shop.updated"
+	// EventShopDeleted được phát khi shop bị xóa (kể cả xóa dây chuyền)
+	EventShopDeleted = "shop.deleted"
+)
@@ -3,6 +3,7 @@ package shop
 import (
 	"context"
 	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/paginator"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -17,11 +18,14 @@ type Repository interface {
 	// GetByID lấy shop theo ID từ database
 	GetByID(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Shop, error)
 
+	// List lấy danh sách shop theo bộ lọc, có phân trang
+	List(ctx context.Context, sc models.Scope, opts ListOptions) ([]models.Shop, paginator.Paginator, error)
+
 	// Update cập nhật shop trong database
 	Update(ctx context.Context, sc models.Scope, opts UpdateOptions) (models.Shop, error)
 
-	// Delete xóa shop khỏi database
-	Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error
+	// Delete xóa shop khỏi database, version là version client đã đọc (0 → không kiểm tra)
+	Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error
 
 	// HasRegions kiểm tra xem shop có region nào không
 	HasRegions(ctx context.Context, shopID primitive.ObjectID) (bool, error)
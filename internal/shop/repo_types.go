@@ -1,6 +1,11 @@
 package shop
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // CreateOptions là tùy chọn để tạo shop trong database
 type CreateOptions struct {
@@ -10,7 +15,14 @@ type CreateOptions struct {
 
 // UpdateOptions là tùy chọn để cập nhật shop
 type UpdateOptions struct {
-	ID   primitive.ObjectID // ID shop cần cập nhật
-	Name *string            // Tên mới (nếu có)
-	Code *string            // Code mới (nếu có)
+	ID      primitive.ObjectID // ID shop cần cập nhật
+	Name    *string            // Tên mới (nếu có)
+	Code    *string            // Code mới (nếu có)
+	Version int                // Version client đã đọc (If-Match), 0 → không kiểm tra
+}
+
+// ListOptions là bộ lọc và phân trang khi lấy danh sách shop
+type ListOptions struct {
+	Filter models.TimeFilter        // Lọc theo thời gian tạo, cập nhật
+	Pagin  paginator.PaginatorQuery // Trang cần lấy (đã Adjust)
 }
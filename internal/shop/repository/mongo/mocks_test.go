@@ -39,9 +39,13 @@ func (m *mockDatabase) NewObjectID() primitive.ObjectID {
 type mockCollection struct {
 	findOneFunc        func(context.Context, interface{}) mongo.SingleResult
 	insertOneFunc      func(context.Context, interface{}) (interface{}, error)
+	insertManyFunc     func(context.Context, []interface{}) ([]interface{}, error)
 	deleteOneFunc      func(context.Context, interface{}) (int64, error)
 	updateOneFunc      func(context.Context, interface{}, interface{}, ...*options.UpdateOptions) (*driverMongo.UpdateResult, error)
 	countDocumentsFunc func(context.Context, interface{}, ...*options.CountOptions) (int64, error)
+
+	findOneAndUpdateFunc func(context.Context, interface{}, interface{}, ...*options.FindOneAndUpdateOptions) mongo.SingleResult
+	findOneAndDeleteFunc func(context.Context, interface{}, ...*options.FindOneAndDeleteOptions) mongo.SingleResult
 }
 
 func (m *mockCollection) FindOne(ctx context.Context, filter interface{}) mongo.SingleResult {
@@ -59,6 +63,9 @@ func (m *mockCollection) InsertOne(ctx context.Context, document interface{}) (i
 }
 
 func (m *mockCollection) InsertMany(ctx context.Context, documents []interface{}) ([]interface{}, error) {
+	if m.insertManyFunc != nil {
+		return m.insertManyFunc(ctx, documents)
+	}
 	return nil, nil
 }
 
@@ -69,6 +76,18 @@ func (m *mockCollection) DeleteOne(ctx context.Context, filter interface{}) (int
 	return 0, nil
 }
 
+func (m *mockCollection) DeleteMany(ctx context.Context, filter interface{}) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockCollection) CreateIndexes(ctx context.Context, models []driverMongo.IndexModel) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockCollection) DropIndex(ctx context.Context, name string) error {
+	return nil
+}
+
 func (m *mockCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (mongo.Cursor, error) {
 	return nil, nil
 }
@@ -95,6 +114,20 @@ func (m *mockCollection) UpdateMany(ctx context.Context, filter interface{}, upd
 	return nil, nil
 }
 
+func (m *mockCollection) FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) mongo.SingleResult {
+	if m.findOneAndUpdateFunc != nil {
+		return m.findOneAndUpdateFunc(ctx, filter, update, opts...)
+	}
+	return nil
+}
+
+func (m *mockCollection) FindOneAndDelete(ctx context.Context, filter interface{}, opts ...*options.FindOneAndDeleteOptions) mongo.SingleResult {
+	if m.findOneAndDeleteFunc != nil {
+		return m.findOneAndDeleteFunc(ctx, filter, opts...)
+	}
+	return nil
+}
+
 // mockSingleResult implements mongo.SingleResult
 type mockSingleResult struct {
 	decodeFunc func(interface{}) error
@@ -5,10 +5,12 @@ import (
 	"errors"
 	"testing"
 
+	"thuchanhgolang/internal/history"
 	"thuchanhgolang/internal/models"
 	"thuchanhgolang/internal/shop"
 	"thuchanhgolang/pkg/mongo"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	driverMongo "go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -135,8 +137,8 @@ func TestUpdate(t *testing.T) {
 		updated := models.Shop{ID: id, Name: name}
 
 		mockColl := &mockCollection{
-			updateOneFunc: func(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*driverMongo.UpdateResult, error) {
-				return &driverMongo.UpdateResult{ModifiedCount: 1}, nil
+			findOneAndUpdateFunc: func(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) mongo.SingleResult {
+				return newMockSingleResult(models.Shop{ID: id, Version: 1}, nil)
 			},
 			findOneFunc: func(ctx context.Context, filter interface{}) mongo.SingleResult {
 				return newMockSingleResult(updated, nil)
@@ -160,14 +162,56 @@ func TestUpdate(t *testing.T) {
 		}
 	})
 
+	t.Run("update only sets given fields", func(t *testing.T) {
+		ctx := context.Background()
+		id := primitive.NewObjectID()
+		code := "NEW"
+		var doc bson.M
+
+		mockColl := &mockCollection{
+			findOneAndUpdateFunc: func(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) mongo.SingleResult {
+				doc = update.(bson.M)
+				return newMockSingleResult(models.Shop{ID: id, Version: 1}, nil)
+			},
+			findOneFunc: func(ctx context.Context, filter interface{}) mongo.SingleResult {
+				return newMockSingleResult(models.Shop{ID: id, Code: code}, nil)
+			},
+		}
+
+		mockDB := &mockDatabase{
+			collectionFunc: func(name string) mongo.Collection {
+				return mockColl
+			},
+		}
+
+		repo := &implRepository{db: mockDB, l: &mockLogger{}}
+		if _, err := repo.Update(ctx, models.Scope{}, shop.UpdateOptions{ID: id, Code: &code}); err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+
+		set, _ := doc["$set"].(bson.M)
+		if set["code"] != code {
+			t.Errorf("Mong đợi $set code = %s, nhận được %v", code, set["code"])
+		}
+		if _, ok := set["name"]; ok {
+			t.Errorf("Không mong đợi $set name khi không gửi name")
+		}
+		if _, ok := doc["$unset"]; ok {
+			t.Errorf("Không mong đợi $unset")
+		}
+		if inc, _ := doc["$inc"].(bson.M); inc["version"] != 1 {
+			t.Errorf("Mong đợi $inc version = 1")
+		}
+	})
+
 	t.Run("update with error", func(t *testing.T) {
 		ctx := context.Background()
 		id := primitive.NewObjectID()
 		name := "Updated"
 
 		mockColl := &mockCollection{
-			updateOneFunc: func(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*driverMongo.UpdateResult, error) {
-				return nil, errors.New("update failed")
+			findOneAndUpdateFunc: func(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) mongo.SingleResult {
+				return newMockSingleResult(nil, errors.New("update failed"))
 			},
 		}
 
@@ -184,6 +228,50 @@ func TestUpdate(t *testing.T) {
 			t.Fatal("Mong đợi có lỗi")
 		}
 	})
+
+	t.Run("update records prior snapshot", func(t *testing.T) {
+		ctx := context.Background()
+		id := primitive.NewObjectID()
+		name := "Updated"
+		var recorded []interface{}
+
+		mockColl := &mockCollection{
+			findOneAndUpdateFunc: func(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) mongo.SingleResult {
+				return newMockSingleResult(models.Shop{ID: id, Name: "Old", Version: 3}, nil)
+			},
+			findOneFunc: func(ctx context.Context, filter interface{}) mongo.SingleResult {
+				return newMockSingleResult(models.Shop{ID: id, Name: name, Version: 4}, nil)
+			},
+			insertManyFunc: func(ctx context.Context, documents []interface{}) ([]interface{}, error) {
+				recorded = documents
+				return nil, nil
+			},
+		}
+
+		mockDB := &mockDatabase{
+			collectionFunc: func(name string) mongo.Collection {
+				return mockColl
+			},
+		}
+
+		repo := &implRepository{db: mockDB, l: &mockLogger{}}
+		_, err := repo.Update(ctx, models.Scope{UserID: "u1"}, shop.UpdateOptions{ID: id, Name: &name, Version: 3})
+
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if len(recorded) != 1 {
+			t.Fatalf("Mong đợi 1 revision, nhận được %d", len(recorded))
+		}
+		rev := recorded[0].(models.Revision)
+		if rev.Entity != history.EntityShop || rev.EntityID != id || rev.Version != 3 || rev.Action != models.RevisionUpdate || rev.ChangedBy != "u1" {
+			t.Errorf("Revision không khớp: %+v", rev)
+		}
+		var prior models.Shop
+		if err := rev.Decode(&prior); err != nil || prior.Name != "Old" {
+			t.Errorf("Mong đợi snapshot trước khi sửa, nhận được %+v (%v)", prior, err)
+		}
+	})
 }
 
 func TestDelete(t *testing.T) {
@@ -191,8 +279,8 @@ func TestDelete(t *testing.T) {
 		ctx := context.Background()
 
 		mockColl := &mockCollection{
-			deleteOneFunc: func(ctx context.Context, filter interface{}) (int64, error) {
-				return 1, nil
+			findOneAndDeleteFunc: func(ctx context.Context, filter interface{}, opts ...*options.FindOneAndDeleteOptions) mongo.SingleResult {
+				return newMockSingleResult(models.Shop{ID: primitive.NewObjectID(), Version: 1}, nil)
 			},
 		}
 
@@ -203,7 +291,7 @@ func TestDelete(t *testing.T) {
 		}
 
 		repo := &implRepository{db: mockDB, l: &mockLogger{}}
-		err := repo.Delete(ctx, models.Scope{}, primitive.NewObjectID())
+		err := repo.Delete(ctx, models.Scope{}, primitive.NewObjectID(), 0)
 
 		if err != nil {
 			t.Fatalf("Không mong đợi lỗi: %v", err)
@@ -214,8 +302,8 @@ func TestDelete(t *testing.T) {
 		ctx := context.Background()
 
 		mockColl := &mockCollection{
-			deleteOneFunc: func(ctx context.Context, filter interface{}) (int64, error) {
-				return 0, errors.New("delete failed")
+			findOneAndDeleteFunc: func(ctx context.Context, filter interface{}, opts ...*options.FindOneAndDeleteOptions) mongo.SingleResult {
+				return newMockSingleResult(nil, errors.New("delete failed"))
 			},
 		}
 
@@ -226,12 +314,38 @@ func TestDelete(t *testing.T) {
 		}
 
 		repo := &implRepository{db: mockDB, l: &mockLogger{}}
-		err := repo.Delete(ctx, models.Scope{}, primitive.NewObjectID())
+		err := repo.Delete(ctx, models.Scope{}, primitive.NewObjectID(), 0)
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi")
 		}
 	})
+
+	t.Run("delete with stale version", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockColl := &mockCollection{
+			findOneAndDeleteFunc: func(ctx context.Context, filter interface{}, opts ...*options.FindOneAndDeleteOptions) mongo.SingleResult {
+				return newMockSingleResult(nil, mongo.ErrNoDocuments)
+			},
+			findOneFunc: func(ctx context.Context, filter interface{}) mongo.SingleResult {
+				return &mockSingleResult{}
+			},
+		}
+
+		mockDB := &mockDatabase{
+			collectionFunc: func(name string) mongo.Collection {
+				return mockColl
+			},
+		}
+
+		repo := &implRepository{db: mockDB, l: &mockLogger{}}
+		err := repo.Delete(ctx, models.Scope{}, primitive.NewObjectID(), 2)
+
+		if !errors.Is(err, shop.ErrVersionMismatch) {
+			t.Fatalf("Mong đợi ErrVersionMismatch, nhận được %v", err)
+		}
+	})
 }
 
 func TestHasRegions(t *testing.T) {
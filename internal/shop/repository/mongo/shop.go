@@ -2,14 +2,18 @@ package mongo
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"thuchanhgolang/internal/history"
 	"thuchanhgolang/internal/models"
 	"thuchanhgolang/internal/shop"
 	"thuchanhgolang/pkg/mongo"
+	"thuchanhgolang/pkg/paginator"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const (
@@ -32,6 +36,10 @@ func (repo implRepository) Create(ctx context.Context, sc models.Scope, opts sho
 		Name:      opts.Name,
 		Code:      opts.Code,
 		CreatedAt: now,
+		UpdatedAt: now,
+		CreatedBy: sc.UserID,
+		UpdatedBy: sc.UserID,
+		Version:   1,
 	}
 
 	// Lưu vào database
@@ -60,30 +68,85 @@ func (repo implRepository) GetByID(ctx context.Context, sc models.Scope, id prim
 	return shop, nil
 }
 
-// Update cập nhật thông tin shop trong MongoDB
-func (repo implRepository) Update(ctx context.Context, sc models.Scope, opts shop.UpdateOptions) (models.Shop, error) {
+// List lấy danh sách shop theo bộ lọc, shop tạo sau đứng trước
+func (repo implRepository) List(ctx context.Context, sc models.Scope, opts shop.ListOptions) ([]models.Shop, paginator.Paginator, error) {
 	col := repo.getShopCollection()
 
-	// Tạo update document
-	update := bson.M{}
-	if opts.Name != nil {
-		update["name"] = *opts.Name
+	// Bước 1: Đếm tổng số shop khớp bộ lọc
+	filter := repo.buildListQuery(opts)
+	total, err := col.CountDocuments(ctx, filter)
+	if err != nil {
+		repo.l.Errorf(ctx, "shop.mongo.List.CountDocuments: %v", err)
+		return nil, paginator.Paginator{}, err
+	}
+
+	// Bước 2: Lấy shop của trang hiện tại
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetSkip(opts.Pagin.Offset()).
+		SetLimit(opts.Pagin.Limit)
+	cursor, err := col.Find(ctx, filter, findOpts)
+	if err != nil {
+		repo.l.Errorf(ctx, "shop.mongo.List.Find: %v", err)
+		return nil, paginator.Paginator{}, err
 	}
-	if opts.Code != nil {
-		update["code"] = *opts.Code
+
+	var shops []models.Shop
+	if err := cursor.All(ctx, &shops); err != nil {
+		repo.l.Errorf(ctx, "shop.mongo.List.All: %v", err)
+		return nil, paginator.Paginator{}, err
 	}
 
+	return shops, paginator.Paginator{
+		Total:       total,
+		Count:       int64(len(shops)),
+		PerPage:     opts.Pagin.Limit,
+		CurrentPage: opts.Pagin.Page,
+	}, nil
+}
+
+// Update cập nhật thông tin shop trong MongoDB
+func (repo implRepository) Update(ctx context.Context, sc models.Scope, opts shop.UpdateOptions) (models.Shop, error) {
+	col := repo.getShopCollection()
+
+	// Tạo update document, field nil thì giữ nguyên
+	update := mongo.NewUpdate()
+	mongo.SetPtr(update, "name", opts.Name)
+	mongo.SetPtr(update, "code", opts.Code)
+
 	// Nếu không có gì để update
-	if len(update) == 0 {
-		return repo.GetByID(ctx, sc, opts.ID)
+	if !update.HasChanges() {
+		current, err := repo.GetByID(ctx, sc, opts.ID)
+		if err != nil {
+			return models.Shop{}, err
+		}
+		if opts.Version > 0 && current.Version != opts.Version {
+			return models.Shop{}, shop.ErrVersionMismatch
+		}
+		return current, nil
 	}
 
-	// Update shop
+	// Ghi lại thời điểm và người sửa
+	now := time.Now()
+	update.Set("updated_at", now).Set("updated_by", sc.UserID).Inc("version", 1)
+
+	// Update shop, chỉ khi version khớp với version client đã đọc, đồng thời tăng version.
+	// Lấy về shop trước khi update để lưu vào lịch sử
 	filter := bson.M{"_id": opts.ID}
-	updateDoc := bson.M{"$set": update}
-	_, err := col.UpdateOne(ctx, filter, updateDoc)
+	if opts.Version > 0 {
+		filter["version"] = opts.Version
+	}
+	var prior bson.Raw
+	err := col.FindOneAndUpdate(ctx, filter, update.Document()).Decode(&prior)
+	if errors.Is(err, mongo.ErrNoDocuments) && opts.Version > 0 {
+		return models.Shop{}, repo.versionError(ctx, sc, opts.ID)
+	}
 	if err != nil {
-		repo.l.Errorf(ctx, "shop.mongo.Update.UpdateOne: %v", err)
+		repo.l.Errorf(ctx, "shop.mongo.Update.FindOneAndUpdate: %v", err)
+		return models.Shop{}, err
+	}
+	if err := history.RecordRevisions(ctx, repo.db, history.EntityShop, models.RevisionUpdate, sc.UserID, now, prior); err != nil {
+		repo.l.Errorf(ctx, "shop.mongo.Update.history.RecordRevisions: %v", err)
 		return models.Shop{}, err
 	}
 
@@ -92,17 +155,31 @@ func (repo implRepository) Update(ctx context.Context, sc models.Scope, opts sho
 }
 
 // Delete xóa shop khỏi MongoDB
-func (repo implRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+func (repo implRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
 	col := repo.getShopCollection()
 
-	// Xóa shop theo ID
+	// Xóa shop theo ID, lấy về shop đã xóa để lưu vào lịch sử
 	filter := bson.M{"_id": id}
-	_, err := col.DeleteOne(ctx, filter)
+	if version > 0 {
+		filter["version"] = version
+	}
+	var prior bson.Raw
+	err := col.FindOneAndDelete(ctx, filter).Decode(&prior)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if version > 0 {
+			return repo.versionError(ctx, sc, id)
+		}
+		return nil
+	}
 	if err != nil {
-		repo.l.Errorf(ctx, "shop.mongo.Delete.DeleteOne: %v", err)
+		repo.l.Errorf(ctx, "shop.mongo.Delete.FindOneAndDelete: %v", err)
 		return err
 	}
 
+	if err := history.RecordRevisions(ctx, repo.db, history.EntityShop, models.RevisionDelete, sc.UserID, time.Now(), prior); err != nil {
+		repo.l.Errorf(ctx, "shop.mongo.Delete.history.RecordRevisions: %v", err)
+		return err
+	}
 	return nil
 }
 
@@ -120,3 +197,20 @@ func (repo implRepository) HasRegions(ctx context.Context, shopID primitive.Obje
 
 	return count > 0, nil
 }
+
+// versionError xác định lý do update/delete theo version không khớp document nào:
+// document không còn thì trả lỗi của GetByID, còn tồn tại thì version đã bị thay đổi
+func (repo implRepository) versionError(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+	if _, err := repo.GetByID(ctx, sc, id); err != nil {
+		return err
+	}
+	return shop.ErrVersionMismatch
+}
+
+// buildListQuery tạo filter lấy danh sách shop từ bộ lọc
+func (repo implRepository) buildListQuery(opts shop.ListOptions) bson.M {
+	filter := bson.M{}
+	mongo.BuildQueryWithTimeRange(filter, "created_at", opts.Filter.CreatedFrom, opts.Filter.CreatedTo)
+	mongo.BuildQueryWithTimeRange(filter, "updated_at", opts.Filter.UpdatedFrom, opts.Filter.UpdatedTo)
+	return filter
+}
@@ -2,6 +2,7 @@ package shop
 
 import (
 	"context"
+	"time"
 
 	"thuchanhgolang/internal/models"
 
@@ -16,9 +17,18 @@ type Usecase interface {
 	// GetByID lấy thông tin shop theo ID
 	GetByID(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Shop, error)
 
+	// List lấy danh sách shop có phân trang, lọc theo thời gian tạo và cập nhật
+	List(ctx context.Context, sc models.Scope, input ListInput) (ListOutput, error)
+
 	// Update cập nhật thông tin shop
 	Update(ctx context.Context, sc models.Scope, input UpdateInput) (models.Shop, error)
 
-	// Delete xóa shop
-	Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error
+	// Delete xóa shop, version là version client đã đọc (0 → không kiểm tra)
+	Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error
+
+	// GetAsOf lấy trạng thái của shop tại thời điểm asOf
+	GetAsOf(ctx context.Context, sc models.Scope, id primitive.ObjectID, asOf time.Time) (models.Shop, error)
+
+	// History lấy lịch sử thay đổi của shop, mới nhất đứng trước
+	History(ctx context.Context, sc models.Scope, input HistoryInput) (HistoryOutput, error)
 }
@@ -1,6 +1,13 @@
 package shop
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // CreateInput là dữ liệu đầu vào để tạo shop mới
 type CreateInput struct {
@@ -10,7 +17,40 @@ type CreateInput struct {
 
 // UpdateInput là dữ liệu đầu vào để cập nhật shop
 type UpdateInput struct {
-	ID   primitive.ObjectID // ID shop cần cập nhật
-	Name *string            // Tên shop mới (optional)
-	Code *string            // Mã code mới (optional)
+	ID      primitive.ObjectID // ID shop cần cập nhật
+	Name    *string            // Tên shop mới (optional)
+	Code    *string            // Mã code mới (optional)
+	Version int                // Version client đã đọc (If-Match), 0 → không kiểm tra
+}
+
+// ListInput là dữ liệu đầu vào để lấy danh sách shop
+type ListInput struct {
+	Filter models.TimeFilter        // Lọc theo thời gian tạo, cập nhật
+	Pagin  paginator.PaginatorQuery // Trang cần lấy
+}
+
+// ListOutput là danh sách shop của một trang
+type ListOutput struct {
+	Shops []models.Shop
+	Pagin paginator.Paginator
+}
+
+// HistoryInput là dữ liệu đầu vào để lấy lịch sử thay đổi của shop
+type HistoryInput struct {
+	ID    primitive.ObjectID       // ID shop
+	Pagin paginator.PaginatorQuery // Trang cần lấy
+}
+
+// Revision là trạng thái của shop trước một lần sửa hoặc xóa
+type Revision struct {
+	Shop      models.Shop           // Snapshot, có hiệu lực từ Shop.UpdatedAt đến ChangedAt
+	Action    models.RevisionAction // Thao tác đã thay thế snapshot
+	ChangedAt time.Time             // Thời điểm sửa / xóa
+	ChangedBy string                // UserID của người sửa / xóa
+}
+
+// HistoryOutput là lịch sử thay đổi của shop trong một trang, mới nhất đứng trước
+type HistoryOutput struct {
+	Revisions []Revision
+	Pagin     paginator.Paginator
 }
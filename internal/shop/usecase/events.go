@@ -0,0 +1,25 @@
+package usecase
+
+import (
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/shop"
+	"thuchanhgolang/pkg/event"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newEvent tạo event của shop, actor là user đang thao tác, vị trí là chính shop
+func newEvent(typ string, sc models.Scope, id primitive.ObjectID, payload map[string]interface{}) event.Event {
+	e := event.New(typ, shop.AggregateType, id.Hex(), sc.UserID, payload)
+	e.Location = models.Hierarchy{ShopID: id}.Location()
+	return e
+}
+
+// newPayload là nội dung event khi shop được tạo hoặc cập nhật
+func newPayload(s models.Shop) map[string]interface{} {
+	return map[string]interface{}{
+		"name":    s.Name,
+		"code":    s.Code,
+		"version": s.Version,
+	}
+}
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"thuchanhgolang/internal/history"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/shop"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// historyReader đọc lịch sử của shop, trạng thái hiện tại được lấy trong scope của người gọi
+func (uc *implUsecase) historyReader(sc models.Scope) history.Reader[models.Shop] {
+	return history.Reader[models.Shop]{
+		Repo:   uc.history,
+		Entity: history.EntityShop,
+		Current: func(ctx context.Context, id primitive.ObjectID) (models.Shop, error) {
+			return uc.repo.GetByID(ctx, sc, id)
+		},
+		CreatedAt: func(v models.Shop) time.Time { return v.CreatedAt },
+	}
+}
+
+// GetAsOf lấy trạng thái của shop tại thời điểm asOf
+func (uc *implUsecase) GetAsOf(ctx context.Context, sc models.Scope, id primitive.ObjectID, asOf time.Time) (models.Shop, error) {
+	result, err := uc.historyReader(sc).GetAsOf(ctx, id, asOf)
+	if err != nil {
+		if errors.Is(err, history.ErrNotCreated) {
+			return models.Shop{}, shop.ErrShopNotFound
+		}
+		uc.l.Errorf(ctx, "shop.usecase.GetAsOf.historyReader.GetAsOf: %v", err)
+		return models.Shop{}, notFoundError(err)
+	}
+
+	return result, nil
+}
+
+// History lấy lịch sử thay đổi của shop, mới nhất đứng trước
+func (uc *implUsecase) History(ctx context.Context, sc models.Scope, input shop.HistoryInput) (shop.HistoryOutput, error) {
+	entries, pag, err := uc.historyReader(sc).List(ctx, input.ID, input.Pagin)
+	if err != nil {
+		uc.l.Errorf(ctx, "shop.usecase.History.historyReader.List: %v", err)
+		return shop.HistoryOutput{}, notFoundError(err)
+	}
+
+	out := shop.HistoryOutput{
+		Revisions: make([]shop.Revision, 0, len(entries)),
+		Pagin:     pag,
+	}
+	for _, e := range entries {
+		out.Revisions = append(out.Revisions, shop.Revision{
+			Shop:      e.Snapshot,
+			Action:    e.Action,
+			ChangedAt: e.ChangedAt,
+			ChangedBy: e.ChangedBy,
+		})
+	}
+
+	return out, nil
+}
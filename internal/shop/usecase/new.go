@@ -1,20 +1,29 @@
 package usecase
 
 import (
+	"thuchanhgolang/internal/history"
 	"thuchanhgolang/internal/shop"
+	"thuchanhgolang/pkg/event"
 	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/mongo"
 )
 
 // implUsecase là implementation của shop.Usecase interface
 type implUsecase struct {
-	l    log.Logger      // Logger để ghi log
-	repo shop.Repository // Repository để tương tác với database
+	l         log.Logger         // Logger để ghi log
+	repo      shop.Repository    // Repository để tương tác với database
+	history   history.Repository // Đọc lịch sử thay đổi
+	tx        mongo.Transactor   // Chạy các thao tác nhiều bước trong transaction
+	publisher event.Publisher    // Ghi event vào outbox khi shop thay đổi
 }
 
 // NewUsecase tạo usecase mới cho shop
-func NewUsecase(l log.Logger, repo shop.Repository) shop.Usecase {
+func NewUsecase(l log.Logger, repo shop.Repository, historyRepo history.Repository, tx mongo.Transactor, publisher event.Publisher) shop.Usecase {
 	return &implUsecase{
-		l:    l,
-		repo: repo,
+		l:         l,
+		repo:      repo,
+		history:   historyRepo,
+		tx:        tx,
+		publisher: publisher,
 	}
 }
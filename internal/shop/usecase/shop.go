@@ -2,9 +2,11 @@ package usecase
 
 import (
 	"context"
+	"errors"
 
 	"thuchanhgolang/internal/models"
 	"thuchanhgolang/internal/shop"
+	"thuchanhgolang/pkg/mongo"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -18,11 +20,23 @@ func (uc *implUsecase) Create(ctx context.Context, sc models.Scope, input shop.C
 		Code: input.Code,
 	}
 
-	// Bước 2: Gọi repository để lưu vào database
-	newShop, err := uc.repo.Create(ctx, sc, opts)
+	// Bước 2: Lưu vào database và ghi event vào outbox trong cùng một transaction
+	var newShop models.Shop
+	err := uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		newShop, err = uc.repo.Create(ctx, sc, opts)
+		if err != nil {
+			uc.l.Errorf(ctx, "shop.usecase.Create.repo.Create: %v", err)
+			return err
+		}
+		if err := uc.publisher.Publish(ctx, newEvent(shop.EventShopCreated, sc, newShop.ID, newPayload(newShop))); err != nil {
+			uc.l.Errorf(ctx, "shop.usecase.Create.publisher.Publish: %v", err)
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		uc.l.Errorf(ctx, "shop.usecase.Create.repo.Create: %v", err)
-		return models.Shop{}, err
+		return models.Shop{}, uniqueError(err)
 	}
 
 	// Bước 3: Trả về shop đã tạo
@@ -35,52 +49,109 @@ func (uc *implUsecase) GetByID(ctx context.Context, sc models.Scope, id primitiv
 	shop, err := uc.repo.GetByID(ctx, sc, id)
 	if err != nil {
 		uc.l.Errorf(ctx, "shop.usecase.GetByID.repo.GetByID: %v", err)
-		return models.Shop{}, err
+		return models.Shop{}, notFoundError(err)
 	}
 
 	return shop, nil
 }
 
+// List lấy danh sách shop có phân trang, lọc theo thời gian tạo và cập nhật
+func (uc *implUsecase) List(ctx context.Context, sc models.Scope, input shop.ListInput) (shop.ListOutput, error) {
+	// Bước 1: Đưa page, limit về giá trị mặc định nếu không hợp lệ
+	input.Pagin.Adjust()
+
+	// Bước 2: Gọi repository để lấy danh sách
+	opts := shop.ListOptions{
+		Filter: input.Filter,
+		Pagin:  input.Pagin,
+	}
+	items, pag, err := uc.repo.List(ctx, sc, opts)
+	if err != nil {
+		uc.l.Errorf(ctx, "shop.usecase.List.repo.List: %v", err)
+		return shop.ListOutput{}, err
+	}
+
+	return shop.ListOutput{Shops: items, Pagin: pag}, nil
+}
+
 // Update cập nhật thông tin shop
 func (uc *implUsecase) Update(ctx context.Context, sc models.Scope, input shop.UpdateInput) (models.Shop, error) {
 	// Bước 1: Chuyển đổi input thành options
 	opts := shop.UpdateOptions{
-		ID:   input.ID,
-		Name: input.Name,
-		Code: input.Code,
+		ID:      input.ID,
+		Name:    input.Name,
+		Code:    input.Code,
+		Version: input.Version,
 	}
 
-	// Bước 2: Gọi repository để update
-	updatedShop, err := uc.repo.Update(ctx, sc, opts)
+	// Bước 2: Gọi repository để update, sửa và ghi lịch sử chạy trong cùng một transaction
+	var updatedShop models.Shop
+	err := uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		updatedShop, err = uc.repo.Update(ctx, sc, opts)
+		if err != nil {
+			uc.l.Errorf(ctx, "shop.usecase.Update.repo.Update: %v", err)
+			return err
+		}
+		if err := uc.publisher.Publish(ctx, newEvent(shop.EventShopUpdated, sc, updatedShop.ID, newPayload(updatedShop))); err != nil {
+			uc.l.Errorf(ctx, "shop.usecase.Update.publisher.Publish: %v", err)
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		uc.l.Errorf(ctx, "shop.usecase.Update.repo.Update: %v", err)
-		return models.Shop{}, err
+		return models.Shop{}, notFoundError(uniqueError(err))
 	}
 
 	return updatedShop, nil
 }
 
 // Delete xóa shop (kiểm tra trước xem có đang được dùng không)
-func (uc *implUsecase) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
-	// Bước 1: Kiểm tra xem shop có region nào không
-	hasRegions, err := uc.repo.HasRegions(ctx, id)
-	if err != nil {
-		uc.l.Errorf(ctx, "shop.usecase.Delete.repo.HasRegions: %v", err)
-		return err
-	}
+func (uc *implUsecase) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
+	// Kiểm tra và xóa chạy trong cùng một transaction, tránh trường hợp kiểm tra xong mới bị thay đổi
+	return uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		// Bước 1: Kiểm tra xem shop có region nào không
+		hasRegions, err := uc.repo.HasRegions(ctx, id)
+		if err != nil {
+			uc.l.Errorf(ctx, "shop.usecase.Delete.repo.HasRegions: %v", err)
+			return err
+		}
 
-	// Bước 2: Nếu có region, không cho phép xóa
-	if hasRegions {
-		uc.l.Warnf(ctx, "shop.usecase.Delete: shop is being used by regions")
-		return shop.ErrShopInUse
-	}
+		// Bước 2: Nếu có region, không cho phép xóa
+		if hasRegions {
+			uc.l.Warnf(ctx, "shop.usecase.Delete: shop is being used by regions")
+			return shop.ErrShopInUse
+		}
 
-	// Bước 3: Gọi repository để xóa shop
-	err = uc.repo.Delete(ctx, sc, id)
-	if err != nil {
-		uc.l.Errorf(ctx, "shop.usecase.Delete.repo.Delete: %v", err)
-		return err
+		// Bước 3: Gọi repository để xóa shop
+		err = uc.repo.Delete(ctx, sc, id, version)
+		if err != nil {
+			uc.l.Errorf(ctx, "shop.usecase.Delete.repo.Delete: %v", err)
+			return notFoundError(err)
+		}
+
+		// Bước 4: Ghi event vào outbox cùng transaction với thao tác xóa
+		if err := uc.publisher.Publish(ctx, newEvent(shop.EventShopDeleted, sc, id, nil)); err != nil {
+			uc.l.Errorf(ctx, "shop.usecase.Delete.publisher.Publish: %v", err)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// uniqueError chuyển lỗi vi phạm unique index thành lỗi domain tương ứng
+func uniqueError(err error) error {
+	if dup, ok := mongo.AsDuplicateKey(err); ok && dup.Field() == "code" {
+		return shop.ErrCodeExists
 	}
+	return err
+}
 
-	return nil
+// notFoundError chuyển lỗi không tìm thấy document thành lỗi domain
+func notFoundError(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return shop.ErrShopNotFound
+	}
+	return err
 }
@@ -4,10 +4,16 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"thuchanhgolang/internal/history"
 	"thuchanhgolang/internal/models"
 	"thuchanhgolang/internal/shop"
+	"thuchanhgolang/pkg/event"
+	"thuchanhgolang/pkg/mongo"
+	"thuchanhgolang/pkg/paginator"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -25,8 +31,9 @@ func TestCreate(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
-		result, err := uc.Create(context.Background(), models.Scope{}, input)
+		publisher := event.NewMemoryPublisher()
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: &mockTransactor{}, publisher: publisher}
+		result, err := uc.Create(context.Background(), models.Scope{UserID: "manager"}, input)
 
 		if err != nil {
 			t.Fatalf("Không mong đợi lỗi: %v", err)
@@ -34,6 +41,27 @@ func TestCreate(t *testing.T) {
 		if result.ID != expected.ID {
 			t.Errorf("ID không khớp")
 		}
+		events := publisher.Events()
+		if len(events) != 1 || events[0].Type != shop.EventShopCreated || events[0].AggregateID != expected.ID.Hex() || events[0].ActorID != "manager" {
+			t.Errorf("Mong đợi 1 event %s, nhận được %+v", shop.EventShopCreated, events)
+		}
+	})
+
+	t.Run("publish error fails the transaction", func(t *testing.T) {
+		mockRepo := &mockRepository{
+			createFunc: func(ctx context.Context, sc models.Scope, opts shop.CreateOptions) (models.Shop, error) {
+				return models.Shop{ID: primitive.NewObjectID()}, nil
+			},
+		}
+		publisher := event.NewMemoryPublisher()
+		publisher.FailWith(errors.New("outbox error"))
+
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: &mockTransactor{}, publisher: publisher}
+		_, err := uc.Create(context.Background(), models.Scope{}, shop.CreateInput{Name: "A"})
+
+		if err == nil {
+			t.Fatal("Không ghi được event thì transaction phải bị hủy")
+		}
 	})
 
 	t.Run("create with error", func(t *testing.T) {
@@ -43,13 +71,28 @@ func TestCreate(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		_, err := uc.Create(context.Background(), models.Scope{}, shop.CreateInput{})
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi")
 		}
 	})
+
+	t.Run("create with duplicated code", func(t *testing.T) {
+		mockRepo := &mockRepository{
+			createFunc: func(ctx context.Context, sc models.Scope, opts shop.CreateOptions) (models.Shop, error) {
+				return models.Shop{}, &mongo.DuplicateKeyError{Index: "code_unique", Fields: []string{"code"}}
+			},
+		}
+
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		_, err := uc.Create(context.Background(), models.Scope{}, shop.CreateInput{Name: "B", Code: "CHA"})
+
+		if !errors.Is(err, shop.ErrCodeExists) {
+			t.Fatalf("Mong đợi ErrCodeExists, nhận được %v", err)
+		}
+	})
 }
 
 func TestGetByID(t *testing.T) {
@@ -63,7 +106,7 @@ func TestGetByID(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		result, err := uc.GetByID(context.Background(), models.Scope{}, id)
 
 		if err != nil {
@@ -81,13 +124,68 @@ func TestGetByID(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		_, err := uc.GetByID(context.Background(), models.Scope{}, primitive.NewObjectID())
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi")
 		}
 	})
+
+	t.Run("get not found", func(t *testing.T) {
+		mockRepo := &mockRepository{
+			getByIDFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Shop, error) {
+				return models.Shop{}, mongo.ErrNoDocuments
+			},
+		}
+
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		_, err := uc.GetByID(context.Background(), models.Scope{}, primitive.NewObjectID())
+
+		if !errors.Is(err, shop.ErrShopNotFound) {
+			t.Fatalf("Mong đợi ErrShopNotFound, nhận được %v", err)
+		}
+	})
+}
+
+func TestList(t *testing.T) {
+	t.Run("list adjusts pagination", func(t *testing.T) {
+		var got shop.ListOptions
+		mockRepo := &mockRepository{
+			listFunc: func(ctx context.Context, sc models.Scope, opts shop.ListOptions) ([]models.Shop, paginator.Paginator, error) {
+				got = opts
+				return []models.Shop{{Name: "A"}}, paginator.Paginator{Total: 1, Count: 1}, nil
+			},
+		}
+
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		result, err := uc.List(context.Background(), models.Scope{}, shop.ListInput{})
+
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if got.Pagin.Page < 1 || got.Pagin.Limit < 1 {
+			t.Errorf("Mong đợi page, limit được đưa về mặc định, nhận được %+v", got.Pagin)
+		}
+		if len(result.Shops) != 1 || result.Pagin.Total != 1 {
+			t.Errorf("Kết quả không khớp: %+v", result)
+		}
+	})
+
+	t.Run("list with error", func(t *testing.T) {
+		mockRepo := &mockRepository{
+			listFunc: func(ctx context.Context, sc models.Scope, opts shop.ListOptions) ([]models.Shop, paginator.Paginator, error) {
+				return nil, paginator.Paginator{}, errors.New("db error")
+			},
+		}
+
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		_, err := uc.List(context.Background(), models.Scope{}, shop.ListInput{})
+
+		if err == nil {
+			t.Fatal("Mong đợi có lỗi")
+		}
+	})
 }
 
 func TestUpdate(t *testing.T) {
@@ -103,7 +201,7 @@ func TestUpdate(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		result, err := uc.Update(context.Background(), models.Scope{}, input)
 
 		if err != nil {
@@ -121,7 +219,7 @@ func TestUpdate(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
 		_, err := uc.Update(context.Background(), models.Scope{}, shop.UpdateInput{})
 
 		if err == nil {
@@ -139,17 +237,21 @@ func TestDelete(t *testing.T) {
 			hasRegionsFunc: func(ctx context.Context, shopID primitive.ObjectID) (bool, error) {
 				return false, nil
 			},
-			deleteFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+			deleteFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
 				return nil
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
-		err := uc.Delete(context.Background(), models.Scope{}, primitive.NewObjectID())
+		tx := &mockTransactor{}
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: tx, publisher: event.NewMemoryPublisher()}
+		err := uc.Delete(context.Background(), models.Scope{}, primitive.NewObjectID(), 0)
 
 		if err != nil {
 			t.Fatalf("Không mong đợi lỗi: %v", err)
 		}
+		if tx.calls != 1 {
+			t.Errorf("Delete phải chạy trong transaction, số lần gọi: %d", tx.calls)
+		}
 	})
 
 	t.Run("delete shop in use", func(t *testing.T) {
@@ -162,8 +264,8 @@ func TestDelete(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
-		err := uc.Delete(context.Background(), models.Scope{}, primitive.NewObjectID())
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		err := uc.Delete(context.Background(), models.Scope{}, primitive.NewObjectID(), 0)
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi")
@@ -180,8 +282,8 @@ func TestDelete(t *testing.T) {
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
-		err := uc.Delete(context.Background(), models.Scope{}, primitive.NewObjectID())
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		err := uc.Delete(context.Background(), models.Scope{}, primitive.NewObjectID(), 0)
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi")
@@ -193,16 +295,134 @@ func TestDelete(t *testing.T) {
 			hasRegionsFunc: func(ctx context.Context, shopID primitive.ObjectID) (bool, error) {
 				return false, nil
 			},
-			deleteFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+			deleteFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
 				return errors.New("delete failed")
 			},
 		}
 
-		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}}
-		err := uc.Delete(context.Background(), models.Scope{}, primitive.NewObjectID())
+		uc := &implUsecase{repo: mockRepo, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		err := uc.Delete(context.Background(), models.Scope{}, primitive.NewObjectID(), 0)
 
 		if err == nil {
 			t.Fatal("Mong đợi có lỗi")
 		}
 	})
 }
+
+func TestGetAsOf(t *testing.T) {
+	id := primitive.NewObjectID()
+	asOf := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("get from revision changed after as_of", func(t *testing.T) {
+		snapshot, _ := bson.Marshal(models.Shop{ID: id, Name: "Tên cũ", CreatedAt: asOf.AddDate(0, -1, 0)})
+		mockHistory := &mockHistoryRepository{
+			getAsOfFunc: func(ctx context.Context, opts history.GetAsOfOptions) (models.Revision, error) {
+				if opts.Entity != history.EntityShop || opts.EntityID != id || !opts.AsOf.Equal(asOf) {
+					t.Errorf("Options không khớp: %+v", opts)
+				}
+				return models.Revision{Snapshot: snapshot}, nil
+			},
+		}
+
+		uc := &implUsecase{repo: &mockRepository{}, history: mockHistory, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		result, err := uc.GetAsOf(context.Background(), models.Scope{}, id, asOf)
+
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if result.Name != "Tên cũ" {
+			t.Errorf("Mong đợi trạng thái trong snapshot, nhận được %q", result.Name)
+		}
+	})
+
+	t.Run("get current when unchanged after as_of", func(t *testing.T) {
+		mockRepo := &mockRepository{
+			getByIDFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Shop, error) {
+				return models.Shop{ID: id, Name: "Hiện tại", CreatedAt: asOf.AddDate(0, -1, 0)}, nil
+			},
+		}
+
+		uc := &implUsecase{repo: mockRepo, history: &mockHistoryRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		result, err := uc.GetAsOf(context.Background(), models.Scope{}, id, asOf)
+
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if result.Name != "Hiện tại" {
+			t.Errorf("Mong đợi trạng thái hiện tại, nhận được %q", result.Name)
+		}
+	})
+
+	t.Run("get before created", func(t *testing.T) {
+		mockRepo := &mockRepository{
+			getByIDFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Shop, error) {
+				return models.Shop{ID: id, CreatedAt: asOf.AddDate(0, 0, 1)}, nil
+			},
+		}
+
+		uc := &implUsecase{repo: mockRepo, history: &mockHistoryRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		_, err := uc.GetAsOf(context.Background(), models.Scope{}, id, asOf)
+
+		if !errors.Is(err, shop.ErrShopNotFound) {
+			t.Fatalf("Mong đợi ErrShopNotFound, nhận được %v", err)
+		}
+	})
+
+	t.Run("get after deleted", func(t *testing.T) {
+		mockRepo := &mockRepository{
+			getByIDFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Shop, error) {
+				return models.Shop{}, mongo.ErrNoDocuments
+			},
+		}
+
+		uc := &implUsecase{repo: mockRepo, history: &mockHistoryRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		_, err := uc.GetAsOf(context.Background(), models.Scope{}, id, asOf)
+
+		if !errors.Is(err, shop.ErrShopNotFound) {
+			t.Fatalf("Mong đợi ErrShopNotFound, nhận được %v", err)
+		}
+	})
+}
+
+func TestHistory(t *testing.T) {
+	t.Run("history decodes snapshots", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		changedAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		snapshot, _ := bson.Marshal(models.Shop{ID: id, Name: "Tên cũ", Version: 1})
+		mockHistory := &mockHistoryRepository{
+			listFunc: func(ctx context.Context, opts history.ListOptions) ([]models.Revision, paginator.Paginator, error) {
+				rev := models.Revision{Action: models.RevisionUpdate, Snapshot: snapshot, ChangedAt: changedAt, ChangedBy: "u1"}
+				return []models.Revision{rev}, paginator.Paginator{Total: 1, Count: 1}, nil
+			},
+		}
+
+		uc := &implUsecase{repo: &mockRepository{}, history: mockHistory, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		out, err := uc.History(context.Background(), models.Scope{}, shop.HistoryInput{ID: id})
+
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if len(out.Revisions) != 1 {
+			t.Fatalf("Mong đợi 1 revision, nhận được %d", len(out.Revisions))
+		}
+		rev := out.Revisions[0]
+		if rev.Shop.Name != "Tên cũ" || rev.Shop.Version != 1 || rev.Action != models.RevisionUpdate || rev.ChangedBy != "u1" {
+			t.Errorf("Revision không khớp: %+v", rev)
+		}
+	})
+
+	t.Run("history of missing shop", func(t *testing.T) {
+		mockRepo := &mockRepository{
+			getByIDFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Shop, error) {
+				return models.Shop{}, mongo.ErrNoDocuments
+			},
+		}
+
+		uc := &implUsecase{repo: mockRepo, history: &mockHistoryRepository{}, l: &mockLogger{}, tx: &mockTransactor{}, publisher: event.NewMemoryPublisher()}
+		_, err := uc.History(context.Background(), models.Scope{}, shop.HistoryInput{ID: primitive.NewObjectID()})
+
+		if !errors.Is(err, shop.ErrShopNotFound) {
+			t.Fatalf("Mong đợi ErrShopNotFound, nhận được %v", err)
+		}
+	})
+}
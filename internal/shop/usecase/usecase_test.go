@@ -3,8 +3,11 @@ package usecase
 import (
 	"context"
 
+	"thuchanhgolang/internal/history"
 	"thuchanhgolang/internal/models"
 	"thuchanhgolang/internal/shop"
+	"thuchanhgolang/pkg/mongo"
+	"thuchanhgolang/pkg/paginator"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -12,8 +15,9 @@ import (
 type mockRepository struct {
 	createFunc     func(context.Context, models.Scope, shop.CreateOptions) (models.Shop, error)
 	getByIDFunc    func(context.Context, models.Scope, primitive.ObjectID) (models.Shop, error)
+	listFunc       func(context.Context, models.Scope, shop.ListOptions) ([]models.Shop, paginator.Paginator, error)
 	updateFunc     func(context.Context, models.Scope, shop.UpdateOptions) (models.Shop, error)
-	deleteFunc     func(context.Context, models.Scope, primitive.ObjectID) error
+	deleteFunc     func(context.Context, models.Scope, primitive.ObjectID, int) error
 	hasUsersFunc   func(context.Context, primitive.ObjectID) (bool, error)
 	hasRegionsFunc func(context.Context, primitive.ObjectID) (bool, error)
 }
@@ -32,6 +36,13 @@ func (m *mockRepository) GetByID(ctx context.Context, sc models.Scope, id primit
 	return models.Shop{}, nil
 }
 
+func (m *mockRepository) List(ctx context.Context, sc models.Scope, opts shop.ListOptions) ([]models.Shop, paginator.Paginator, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, sc, opts)
+	}
+	return nil, paginator.Paginator{}, nil
+}
+
 func (m *mockRepository) Update(ctx context.Context, sc models.Scope, opts shop.UpdateOptions) (models.Shop, error) {
 	if m.updateFunc != nil {
 		return m.updateFunc(ctx, sc, opts)
@@ -39,9 +50,9 @@ func (m *mockRepository) Update(ctx context.Context, sc models.Scope, opts shop.
 	return models.Shop{}, nil
 }
 
-func (m *mockRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+func (m *mockRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
 	if m.deleteFunc != nil {
-		return m.deleteFunc(ctx, sc, id)
+		return m.deleteFunc(ctx, sc, id, version)
 	}
 	return nil
 }
@@ -72,3 +83,31 @@ func (m *mockLogger) Error(ctx context.Context, arg ...any)                   {}
 func (m *mockLogger) Errorf(ctx context.Context, template string, arg ...any) {}
 func (m *mockLogger) Fatal(ctx context.Context, arg ...any)                   {}
 func (m *mockLogger) Fatalf(ctx context.Context, template string, arg ...any) {}
+
+type mockTransactor struct {
+	calls int
+}
+
+func (m *mockTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	return fn(ctx)
+}
+
+type mockHistoryRepository struct {
+	listFunc    func(context.Context, history.ListOptions) ([]models.Revision, paginator.Paginator, error)
+	getAsOfFunc func(context.Context, history.GetAsOfOptions) (models.Revision, error)
+}
+
+func (m *mockHistoryRepository) List(ctx context.Context, opts history.ListOptions) ([]models.Revision, paginator.Paginator, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, opts)
+	}
+	return nil, paginator.Paginator{}, nil
+}
+
+func (m *mockHistoryRepository) GetAsOf(ctx context.Context, opts history.GetAsOfOptions) (models.Revision, error) {
+	if m.getAsOfFunc != nil {
+		return m.getAsOfFunc(ctx, opts)
+	}
+	return models.Revision{}, mongo.ErrNoDocuments
+}
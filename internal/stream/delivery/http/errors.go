@@ -0,0 +1,9 @@
+package http
+
+import (
+	pkgErrors "thuchanhgolang/pkg/errors"
+)
+
+var (
+	errUnauthorized = pkgErrors.NewUnauthorizedHTTPError()
+)
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"thuchanhgolang/internal/stream"
+	"thuchanhgolang/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// retryMillis là thời gian trình duyệt chờ trước khi tự nối lại
+const retryMillis = 3000
+
+// stream giữ kết nối SSE và đẩy các thay đổi user được phép xem cho tới khi client ngắt kết nối
+// hoặc access token hết hạn. Token chỉ được kiểm tra lúc mở kết nối nên stream phải tự đóng khi tới exp,
+// nếu không token đã hết hạn (hoặc user đã bị đổi quyền) vẫn nhận được thay đổi mãi mãi.
+func (h handler) stream(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Lấy scope của user đang đăng nhập
+	sc, err := h.processScope(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// Bước 2: Xác định vị trí bắt đầu từ Last-Event-ID
+	start, err := h.uc.Start(ctx, h.processLastEventID(c))
+	if err != nil {
+		h.l.Errorf(ctx, "stream.handler.stream.uc.Start: %s", err)
+		response.Error(c, err)
+		return
+	}
+
+	// Bước 3: Mở stream, báo cho client vị trí bắt đầu hoặc yêu cầu tải lại dữ liệu
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Tắt buffer của nginx để event tới client ngay
+	c.Status(http.StatusOK)
+
+	name := eventReady
+	if start.Reset {
+		name = eventReset
+	}
+	if _, err := io.WriteString(c.Writer, fmt.Sprintf("retry: %d\n", retryMillis)); err != nil {
+		return
+	}
+	if err := writeEvent(c.Writer, start.Cursor, name, startResp{Cursor: start.Cursor}); err != nil {
+		return
+	}
+	c.Writer.Flush()
+
+	// Bước 4: Hẹn giờ đóng stream khi access token hết hạn
+	var expired <-chan time.Time
+	if exp, ok := h.processExpiry(c); ok {
+		timer := time.NewTimer(time.Until(exp))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	// Bước 5: Đọc change log định kỳ, gửi heartbeat khi không có gì để gửi
+	poll := time.NewTicker(stream.DefaultPollInterval)
+	defer poll.Stop()
+	heartbeat := time.NewTicker(stream.DefaultHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	cursor := start.Cursor
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-expired:
+			// Báo client vị trí hiện tại để nối lại bằng token mới mà không bỏ lỡ thay đổi
+			_ = writeEvent(w, cursor, eventExpired, startResp{Cursor: cursor})
+			return false
+		case <-heartbeat.C:
+			return writeHeartbeat(w) == nil
+		case <-poll.C:
+			for {
+				out, err := h.uc.Poll(ctx, sc, stream.PollInput{Cursor: cursor})
+				if err != nil {
+					// Lỗi tạm thời của database: giữ kết nối và thử lại ở lượt sau
+					h.l.Errorf(ctx, "stream.handler.stream.uc.Poll: %s", err)
+					return true
+				}
+				for _, change := range out.Changes {
+					if err := writeChange(w, change); err != nil {
+						return false
+					}
+				}
+				cursor = out.Cursor
+				if !out.More {
+					return true
+				}
+			}
+		}
+	})
+}
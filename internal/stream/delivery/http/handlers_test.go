@@ -0,0 +1,71 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/stream"
+	"thuchanhgolang/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeUsecase luôn bắt đầu ở cursor cố định và không có change mới
+type fakeUsecase struct{}
+
+func (fakeUsecase) Start(ctx context.Context, lastEventID string) (stream.StartOutput, error) {
+	return stream.StartOutput{Cursor: "c1"}, nil
+}
+
+func (fakeUsecase) Poll(ctx context.Context, sc models.Scope, input stream.PollInput) (stream.PollOutput, error) {
+	return stream.PollOutput{Cursor: input.Cursor}, nil
+}
+
+type mockLogger struct{}
+
+func (m *mockLogger) Debug(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Debugf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Info(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Infof(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Warn(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Warnf(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Error(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Errorf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Fatal(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Fatalf(ctx context.Context, template string, arg ...any) {}
+
+func TestStreamClosesWhenTokenExpires(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		payload := jwt.Payload{UserID: "u1", Role: string(models.RoleEmployee)}
+		payload.ExpiresAt = time.Now().Add(time.Second).Unix()
+		c.Request = c.Request.WithContext(jwt.SetPayloadToContext(c.Request.Context(), payload))
+	})
+	MapRoutes(r.Group(""), New(&mockLogger{}, fakeUsecase{}))
+
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(srv.URL + "/stream")
+	if err != nil {
+		t.Fatalf("Không mở được stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Server phải tự đóng stream, nếu không ReadAll bị timeout của client chặn lại
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Stream không được đóng khi token hết hạn: %v", err)
+	}
+	if !strings.Contains(string(body), "id: c1\nevent: expired\n") {
+		t.Errorf("Mong đợi event expired kèm cursor, nhận được %q", body)
+	}
+}
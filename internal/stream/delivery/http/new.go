@@ -0,0 +1,27 @@
+package http
+
+import (
+	"thuchanhgolang/internal/stream"
+	"thuchanhgolang/pkg/log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler định nghĩa interface cho HTTP handler
+type Handler interface {
+	stream(c *gin.Context)
+}
+
+// handler là implementation của Handler interface
+type handler struct {
+	l  log.Logger     // Logger để ghi log
+	uc stream.Usecase // Usecase để xử lý business logic
+}
+
+// New tạo HTTP handler mới cho SSE stream
+func New(l log.Logger, uc stream.Usecase) Handler {
+	return handler{
+		l:  l,
+		uc: uc,
+	}
+}
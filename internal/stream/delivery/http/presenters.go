@@ -0,0 +1,48 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/stream"
+)
+
+// Tên các SSE event không phải là thay đổi dữ liệu
+const (
+	eventReady   = "ready"   // Kết nối đã sẵn sàng, id là vị trí bắt đầu
+	eventReset   = "reset"   // Client đã bỏ lỡ thay đổi, phải tải lại dữ liệu rồi tiếp tục từ id này
+	eventExpired = "expired" // Access token đã hết hạn, server đóng stream; client nối lại bằng token mới từ id này
+)
+
+// writeEvent ghi một SSE event, data là một dòng JSON
+func writeEvent(w io.Writer, id, name string, data interface{}) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if id != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, body)
+	return err
+}
+
+// writeChange ghi change dưới dạng SSE event, tên event là loại event (ví dụ branch.moved)
+func writeChange(w io.Writer, c models.Change) error {
+	return writeEvent(w, stream.FormatCursor(c.Seq), c.Event.Type, c.Event)
+}
+
+// writeHeartbeat ghi comment để giữ kết nối qua proxy khi không có thay đổi
+func writeHeartbeat(w io.Writer) error {
+	_, err := io.WriteString(w, ": heartbeat\n\n")
+	return err
+}
+
+// startResp là data của event ready / reset
+type startResp struct {
+	Cursor string `json:"cursor"`
+}
@@ -0,0 +1,40 @@
+package http
+
+import (
+	"strings"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// processLastEventID lấy ID của event cuối cùng client đã nhận.
+// Trình duyệt tự gửi header Last-Event-ID khi nối lại, query last_event_id dùng khi client tự mở kết nối mới.
+func (h handler) processLastEventID(c *gin.Context) string {
+	if id := strings.TrimSpace(c.GetHeader("Last-Event-ID")); id != "" {
+		return id
+	}
+	return strings.TrimSpace(c.Query("last_event_id"))
+}
+
+// processScope lấy scope của user từ JWT payload trong context
+func (h handler) processScope(c *gin.Context) (models.Scope, error) {
+	payload, ok := jwt.GetPayloadFromContext(c.Request.Context())
+	if !ok {
+		return models.Scope{}, errUnauthorized
+	}
+
+	return jwt.NewScope(payload), nil
+}
+
+// processExpiry lấy thời điểm hết hạn của access token, ok = false khi token không có exp
+func (h handler) processExpiry(c *gin.Context) (time.Time, bool) {
+	payload, ok := jwt.GetPayloadFromContext(c.Request.Context())
+	if !ok || payload.ExpiresAt == 0 {
+		return time.Time{}, false
+	}
+
+	return time.Unix(payload.ExpiresAt, 0), true
+}
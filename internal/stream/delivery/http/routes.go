@@ -0,0 +1,28 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"thuchanhgolang/pkg/openapi"
+)
+
+// MapRoutes maps the routes to the handler functions
+func MapRoutes(r *gin.RouterGroup, h Handler) {
+	r.GET("/stream", h.stream) // SSE stream các thay đổi của cây tổ chức trong scope của user
+}
+
+// Spec mô tả các route của MapRoutes cho OpenAPI, sửa route thì sửa cả ở đây
+func Spec() []openapi.Route {
+	return []openapi.Route{
+		{
+			Method: http.MethodGet, Path: "/stream", Summary: "SSE stream các thay đổi của cây tổ chức trong scope của user",
+			Params: []openapi.Parameter{
+				openapi.HeaderParam("Last-Event-ID", "ID của event cuối cùng đã nhận, stream tiếp từ sau event này"),
+				openapi.QueryParam("last_event_id", "Như header Last-Event-ID, cho client không gửi được header"),
+			},
+			ResponseTypes: []string{openapi.ContentSSE},
+		},
+	}
+}
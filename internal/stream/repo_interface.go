@@ -0,0 +1,27 @@
+package stream
+
+import (
+	"context"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/event"
+)
+
+// Repository lưu change log có giới hạn thời gian, client SSE đọc lại từ đây khi nối lại
+//
+//go:generate mockery --name=Repository
+type Repository interface {
+	// Append ghi event vào cuối change log với số thứ tự mới, event đã có trong log thì bỏ qua
+	// để nhận lại cùng một event từ outbox không bị ghi trùng
+	Append(ctx context.Context, events []event.Event, at time.Time) error
+
+	// Exists kiểm tra change có số thứ tự seq còn nằm trong log không
+	Exists(ctx context.Context, seq int64) (bool, error)
+
+	// Last lấy số thứ tự của change mới nhất, 0 khi log rỗng
+	Last(ctx context.Context) (int64, error)
+
+	// ListAfter lấy tối đa limit change có số thứ tự lớn hơn after, cũ nhất đứng trước
+	ListAfter(ctx context.Context, after int64, limit int64) ([]models.Change, error)
+}
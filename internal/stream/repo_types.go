@@ -0,0 +1,8 @@
+package stream
+
+const (
+	// Collection là collection lưu change log
+	Collection = "changes"
+	// SequenceCollection là collection lưu bộ đếm cấp số thứ tự cho change
+	SequenceCollection = "sequences"
+)
@@ -0,0 +1,137 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/stream"
+	"thuchanhgolang/pkg/event"
+	"thuchanhgolang/pkg/mongo"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// getChangeCollection lấy collection changes từ database
+func (repo implRepository) getChangeCollection() mongo.Collection {
+	return repo.db.Collection(stream.Collection)
+}
+
+// Append ghi event vào change log bằng upsert theo event._id.
+// Số thứ tự lấy từ bộ đếm chung bằng $inc nên tăng dần trên mọi instance chạy dispatcher,
+// khác với ObjectID do từng instance tự sinh.
+func (repo implRepository) Append(ctx context.Context, events []event.Event, at time.Time) error {
+	col := repo.getChangeCollection()
+	upsert := options.Update().SetUpsert(true)
+
+	for _, e := range events {
+		// Bước 1: Event đã có trong log thì bỏ qua, không cấp số thứ tự mới
+		count, err := col.CountDocuments(ctx, bson.M{"event._id": e.ID})
+		if err != nil {
+			repo.l.Errorf(ctx, "stream.mongo.Append.CountDocuments: %v", err)
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		// Bước 2: Lấy số thứ tự tiếp theo
+		seq, err := repo.nextSeq(ctx)
+		if err != nil {
+			repo.l.Errorf(ctx, "stream.mongo.Append.nextSeq: %v", err)
+			return err
+		}
+
+		// Bước 3: Ghi change, upsert để event không bị ghi trùng khi hai dispatcher cùng ghi
+		change := models.Change{
+			ID:        repo.db.NewObjectID(),
+			Seq:       seq,
+			Event:     e,
+			CreatedAt: at,
+		}
+		filter := bson.M{"event._id": e.ID}
+		update := bson.M{"$setOnInsert": change}
+		if _, err := col.UpdateOne(ctx, filter, update, upsert); err != nil {
+			// Hai dispatcher cùng ghi một event: bản kia đã ghi, coi như thành công
+			if _, ok := mongo.AsDuplicateKey(err); ok {
+				continue
+			}
+			repo.l.Errorf(ctx, "stream.mongo.Append.UpdateOne: %v", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nextSeq tăng bộ đếm của change log và trả về số thứ tự mới
+func (repo implRepository) nextSeq(ctx context.Context) (int64, error) {
+	filter := bson.M{"_id": stream.Collection}
+	update := bson.M{"$inc": bson.M{"seq": 1}}
+	findOpts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	if err := repo.db.Collection(stream.SequenceCollection).FindOneAndUpdate(ctx, filter, update, findOpts).Decode(&counter); err != nil {
+		return 0, err
+	}
+	return counter.Seq, nil
+}
+
+// Exists kiểm tra change có số thứ tự seq còn nằm trong log không
+func (repo implRepository) Exists(ctx context.Context, seq int64) (bool, error) {
+	count, err := repo.getChangeCollection().CountDocuments(ctx, bson.M{"seq": seq})
+	if err != nil {
+		repo.l.Errorf(ctx, "stream.mongo.Exists.CountDocuments: %v", err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Last lấy số thứ tự của change mới nhất, 0 khi log rỗng
+func (repo implRepository) Last(ctx context.Context) (int64, error) {
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "seq", Value: -1}}).
+		SetProjection(bson.M{"seq": 1}).
+		SetLimit(1)
+
+	cursor, err := repo.getChangeCollection().Find(ctx, bson.M{}, findOpts)
+	if err != nil {
+		repo.l.Errorf(ctx, "stream.mongo.Last.Find: %v", err)
+		return 0, err
+	}
+
+	var changes []models.Change
+	if err := cursor.All(ctx, &changes); err != nil {
+		repo.l.Errorf(ctx, "stream.mongo.Last.All: %v", err)
+		return 0, err
+	}
+	if len(changes) == 0 {
+		return 0, nil
+	}
+	return changes[0].Seq, nil
+}
+
+// ListAfter lấy tối đa limit change có số thứ tự lớn hơn after, cũ nhất đứng trước
+func (repo implRepository) ListAfter(ctx context.Context, after int64, limit int64) ([]models.Change, error) {
+	filter := bson.M{"seq": bson.M{"$gt": after}}
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "seq", Value: 1}}).
+		SetLimit(limit)
+
+	cursor, err := repo.getChangeCollection().Find(ctx, filter, findOpts)
+	if err != nil {
+		repo.l.Errorf(ctx, "stream.mongo.ListAfter.Find: %v", err)
+		return nil, err
+	}
+
+	var changes []models.Change
+	if err := cursor.All(ctx, &changes); err != nil {
+		repo.l.Errorf(ctx, "stream.mongo.ListAfter.All: %v", err)
+		return nil, err
+	}
+
+	return changes, nil
+}
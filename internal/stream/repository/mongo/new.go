@@ -0,0 +1,21 @@
+package mongo
+
+import (
+	"thuchanhgolang/internal/stream"
+	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/mongo"
+)
+
+// implRepository là implementation của stream.Repository
+type implRepository struct {
+	l  log.Logger     // Logger để ghi log
+	db mongo.Database // Database connection
+}
+
+// NewRepository tạo một stream repository mới
+func NewRepository(l log.Logger, db mongo.Database) stream.Repository {
+	return &implRepository{
+		l:  l,
+		db: db,
+	}
+}
@@ -0,0 +1,20 @@
+package stream
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/models"
+)
+
+// Usecase đọc change log của cây tổ chức cho dashboard trực tiếp.
+// Mỗi user chỉ nhận change của dữ liệu họ được xem qua REST API.
+//
+//go:generate mockery --name=Usecase
+type Usecase interface {
+	// Start xác định vị trí bắt đầu của kết nối từ Last-Event-ID,
+	// rỗng thì bắt đầu từ cuối log (chỉ nhận change mới)
+	Start(ctx context.Context, lastEventID string) (StartOutput, error)
+
+	// Poll lấy các change sau cursor mà user được phép xem
+	Poll(ctx context.Context, sc models.Scope, input PollInput) (PollOutput, error)
+}
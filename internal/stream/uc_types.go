@@ -0,0 +1,61 @@
+package stream
+
+import (
+	"strconv"
+	"time"
+
+	"thuchanhgolang/internal/models"
+)
+
+// Giá trị mặc định khi stream change tới client
+const (
+	DefaultBatchSize         = 100
+	DefaultPollInterval      = time.Second
+	DefaultHeartbeatInterval = 15 * time.Second
+
+	// GapTimeout là thời gian chờ change còn thiếu trong dãy số thứ tự.
+	// Số thứ tự được cấp trước khi ghi nên change số nhỏ hơn có thể ghi xong sau change số lớn hơn,
+	// khoảng trống tồn tại lâu hơn GapTimeout là số thứ tự bị bỏ (ghi lỗi, event ghi trùng) và được đọc qua.
+	GapTimeout = 10 * time.Second
+)
+
+// FormatCursor trả về cursor (id của SSE event) của change có số thứ tự seq
+func FormatCursor(seq int64) string {
+	return strconv.FormatInt(seq, 10)
+}
+
+// ParseCursor đọc số thứ tự từ cursor, cursor rỗng là đầu log (0)
+func ParseCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	seq, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if seq < 0 {
+		return 0, strconv.ErrRange
+	}
+	return seq, nil
+}
+
+// StartOutput là vị trí bắt đầu đọc change log của một kết nối
+type StartOutput struct {
+	Cursor string // Số thứ tự của change cuối cùng client đã có, rỗng khi log rỗng
+	// Reset là true khi Last-Event-ID không còn trong log (quá cũ hoặc không hợp lệ),
+	// client đã bỏ lỡ change nên phải tải lại dữ liệu
+	Reset bool
+}
+
+// PollInput là input để lấy change mới sau cursor
+type PollInput struct {
+	Cursor string
+	Limit  int64 // <= 0 → DefaultBatchSize
+}
+
+// PollOutput là các change user được phép xem cùng cursor cho lần đọc tiếp theo
+type PollOutput struct {
+	Changes []models.Change
+	Cursor  string // Change cuối cùng đã đọc, kể cả change bị lọc bỏ
+	More    bool   // Còn change chưa đọc, nên đọc tiếp ngay
+}
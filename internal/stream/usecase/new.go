@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"time"
+
+	"thuchanhgolang/internal/stream"
+	"thuchanhgolang/pkg/event"
+	"thuchanhgolang/pkg/log"
+)
+
+// implUsecase là implementation của stream.Usecase interface
+type implUsecase struct {
+	l    log.Logger        // Logger để ghi log
+	repo stream.Repository // Đọc change log
+	now  func() time.Time  // Thời điểm hiện tại, dùng để chờ change còn thiếu trong dãy số thứ tự
+}
+
+// NewUsecase tạo usecase mới cho SSE stream
+func NewUsecase(l log.Logger, repo stream.Repository) stream.Usecase {
+	return &implUsecase{
+		l:    l,
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// implRecorder nhận event từ outbox và ghi event của cây tổ chức vào change log
+type implRecorder struct {
+	l    log.Logger
+	repo stream.Repository
+	now  func() time.Time
+}
+
+// NewRecorder tạo publisher ghi event của shop, region, branch, department, user vào change log
+func NewRecorder(l log.Logger, repo stream.Repository) event.Publisher {
+	return &implRecorder{
+		l:    l,
+		repo: repo,
+		now:  time.Now,
+	}
+}
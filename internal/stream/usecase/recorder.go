@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"context"
+
+	"thuchanhgolang/pkg/event"
+)
+
+// Publish ghi event của cây tổ chức vào change log, event của aggregate khác bị bỏ qua.
+// Lỗi được trả về để outbox gửi lại event, event đã ghi không bị ghi trùng.
+func (r *implRecorder) Publish(ctx context.Context, events ...event.Event) error {
+	var changes []event.Event
+	for _, e := range events {
+		if _, ok := readers[e.AggregateType]; ok {
+			changes = append(changes, e)
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if err := r.repo.Append(ctx, changes, r.now()); err != nil {
+		r.l.Errorf(ctx, "stream.usecase.Publish.repo.Append: %v", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"thuchanhgolang/internal/branch"
+	"thuchanhgolang/internal/department"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/region"
+	"thuchanhgolang/internal/shop"
+	"thuchanhgolang/internal/stream"
+	"thuchanhgolang/internal/user"
+	"thuchanhgolang/pkg/event"
+)
+
+// readers là các role được đọc từng loại aggregate, giống phân quyền của REST API
+// (CheckShopAccess, CheckRegionAccess, CheckBranchAccess, CheckDepartmentAccess, CheckUserAccess)
+var readers = map[string][]models.Role{
+	shop.AggregateType:   {models.RoleManager},
+	region.AggregateType: {models.RoleManager, models.RoleRegionManager},
+	branch.AggregateType: {models.RoleManager, models.RoleRegionManager, models.RoleBranchManager},
+	department.AggregateType: {
+		models.RoleManager,
+		models.RoleRegionManager,
+		models.RoleBranchManager,
+		models.RoleHeadOfDepartment,
+	},
+	user.AggregateType: {
+		models.RoleManager,
+		models.RoleRegionManager,
+		models.RoleBranchManager,
+		models.RoleHeadOfDepartment,
+		models.RoleEmployee,
+	},
+}
+
+// canRead kiểm tra user được xem event không: role phải được đọc loại aggregate
+// và vị trí của aggregate phải nằm trong phạm vi dữ liệu của user
+func canRead(sc models.Scope, e event.Event) bool {
+	allowed := false
+	for _, role := range readers[e.AggregateType] {
+		if sc.Role == role {
+			allowed = true
+			break
+		}
+	}
+	return allowed && sc.Contains(models.HierarchyOf(e.Location))
+}
+
+// Start xác định vị trí bắt đầu đọc change log từ Last-Event-ID của client
+func (uc *implUsecase) Start(ctx context.Context, lastEventID string) (stream.StartOutput, error) {
+	// Bước 1: Kết nối mới thì bắt đầu từ cuối log
+	if lastEventID == "" {
+		return uc.tail(ctx, false)
+	}
+
+	// Bước 2: Last-Event-ID không hợp lệ thì client không biết mình đã bỏ lỡ những gì
+	after, err := stream.ParseCursor(lastEventID)
+	if err != nil || after == 0 {
+		uc.l.Warnf(ctx, "stream.usecase.Start: invalid Last-Event-ID %q", lastEventID)
+		return uc.tail(ctx, true)
+	}
+
+	// Bước 3: Change đã bị xóa khỏi log thì các change sau nó có thể cũng đã bị xóa
+	exists, err := uc.repo.Exists(ctx, after)
+	if err != nil {
+		uc.l.Errorf(ctx, "stream.usecase.Start.repo.Exists: %v", err)
+		return stream.StartOutput{}, err
+	}
+	if !exists {
+		return uc.tail(ctx, true)
+	}
+
+	return stream.StartOutput{Cursor: stream.FormatCursor(after)}, nil
+}
+
+// tail trả về vị trí cuối log
+func (uc *implUsecase) tail(ctx context.Context, reset bool) (stream.StartOutput, error) {
+	last, err := uc.repo.Last(ctx)
+	if err != nil {
+		uc.l.Errorf(ctx, "stream.usecase.tail.repo.Last: %v", err)
+		return stream.StartOutput{}, err
+	}
+
+	out := stream.StartOutput{Reset: reset}
+	if last > 0 {
+		out.Cursor = stream.FormatCursor(last)
+	}
+	return out, nil
+}
+
+// Poll lấy các change sau cursor, chỉ giữ lại change user được phép xem
+func (uc *implUsecase) Poll(ctx context.Context, sc models.Scope, input stream.PollInput) (stream.PollOutput, error) {
+	// Bước 1: Đưa limit về giá trị mặc định nếu không hợp lệ
+	if input.Limit <= 0 {
+		input.Limit = stream.DefaultBatchSize
+	}
+
+	// Bước 2: Cursor rỗng (log rỗng lúc bắt đầu) thì đọc từ đầu log
+	after, err := stream.ParseCursor(input.Cursor)
+	if err != nil {
+		uc.l.Warnf(ctx, "stream.usecase.Poll: invalid cursor %q", input.Cursor)
+		return stream.PollOutput{}, err
+	}
+
+	// Bước 3: Đọc change mới, dừng trước khoảng trống số thứ tự còn chờ change ghi xong
+	changes, err := uc.repo.ListAfter(ctx, after, input.Limit)
+	if err != nil {
+		uc.l.Errorf(ctx, "stream.usecase.Poll.repo.ListAfter: %v", err)
+		return stream.PollOutput{}, err
+	}
+	ready := contiguous(changes, after, uc.now())
+
+	// Bước 4: Lọc theo quyền, cursor vẫn tiến qua change bị lọc để không đọc lại
+	out := stream.PollOutput{
+		Cursor: input.Cursor,
+		More:   len(ready) == len(changes) && int64(len(changes)) == input.Limit,
+	}
+	for _, c := range ready {
+		out.Cursor = stream.FormatCursor(c.Seq)
+		if canRead(sc, c.Event) {
+			out.Changes = append(out.Changes, c)
+		}
+	}
+
+	return out, nil
+}
+
+// contiguous cắt changes tại khoảng trống số thứ tự đầu tiên chưa quá stream.GapTimeout.
+// Change số nhỏ hơn có thể ghi xong sau change số lớn hơn, đọc qua khoảng trống thì cursor
+// đã vượt qua và client bỏ lỡ change đó. after = 0 là đầu log nên change đầu tiên không cần liền kề.
+func contiguous(changes []models.Change, after int64, now time.Time) []models.Change {
+	next := after + 1
+	for i, c := range changes {
+		if (i > 0 || after > 0) && c.Seq != next && now.Sub(c.CreatedAt) < stream.GapTimeout {
+			return changes[:i]
+		}
+		next = c.Seq + 1
+	}
+	return changes
+}
@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"thuchanhgolang/internal/branch"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/shop"
+	"thuchanhgolang/internal/stream"
+	"thuchanhgolang/internal/user"
+	"thuchanhgolang/pkg/event"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPublish(t *testing.T) {
+	t.Run("record org events only, once per event", func(t *testing.T) {
+		repo := &mockRepository{}
+		recorder := NewRecorder(&mockLogger{}, repo)
+
+		e := event.New(branch.EventBranchCreated, branch.AggregateType, primitive.NewObjectID().Hex(), "u1", nil)
+		other := event.New("webhook.created", "webhook", primitive.NewObjectID().Hex(), "u1", nil)
+
+		if err := recorder.Publish(context.Background(), e, other); err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if err := recorder.Publish(context.Background(), e); err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if len(repo.changes) != 1 || repo.changes[0].Event.ID != e.ID {
+			t.Fatalf("Change log phải có đúng một event branch: %+v", repo.changes)
+		}
+	})
+}
+
+func TestStart(t *testing.T) {
+	repo := &mockRepository{}
+	uc := NewUsecase(&mockLogger{}, repo)
+	ctx := context.Background()
+
+	t.Run("empty log starts with empty cursor", func(t *testing.T) {
+		out, err := uc.Start(ctx, "")
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if out.Cursor != "" || out.Reset {
+			t.Errorf("Kết quả không đúng: %+v", out)
+		}
+	})
+
+	e := event.New(shop.EventShopCreated, shop.AggregateType, primitive.NewObjectID().Hex(), "u1", nil)
+	if err := repo.Append(ctx, []event.Event{e, e}, e.OccurredAt); err != nil {
+		t.Fatal(err)
+	}
+	last := stream.FormatCursor(repo.changes[0].Seq)
+
+	t.Run("new connection starts at tail", func(t *testing.T) {
+		out, err := uc.Start(ctx, "")
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if out.Cursor != last || out.Reset {
+			t.Errorf("Kết nối mới phải bắt đầu từ cuối log: %+v", out)
+		}
+	})
+
+	t.Run("resume from Last-Event-ID in log", func(t *testing.T) {
+		out, err := uc.Start(ctx, last)
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if out.Cursor != last || out.Reset {
+			t.Errorf("Phải tiếp tục từ Last-Event-ID: %+v", out)
+		}
+	})
+
+	t.Run("reset when Last-Event-ID expired or invalid", func(t *testing.T) {
+		for _, id := range []string{"42", "0", primitive.NewObjectID().Hex(), "not-an-id"} {
+			out, err := uc.Start(ctx, id)
+			if err != nil {
+				t.Fatalf("Không mong đợi lỗi: %v", err)
+			}
+			if !out.Reset || out.Cursor != last {
+				t.Errorf("Last-Event-ID %q phải trả về reset từ cuối log: %+v", id, out)
+			}
+		}
+	})
+}
+
+func TestPoll(t *testing.T) {
+	ctx := context.Background()
+	shopID := primitive.NewObjectID()
+	regionID := primitive.NewObjectID()
+	branchA := models.Hierarchy{ShopID: shopID, RegionID: regionID, BranchID: primitive.NewObjectID()}
+	branchB := models.Hierarchy{ShopID: shopID, RegionID: regionID, BranchID: primitive.NewObjectID()}
+	otherShop := models.Hierarchy{ShopID: primitive.NewObjectID(), RegionID: primitive.NewObjectID(), BranchID: primitive.NewObjectID()}
+
+	newEvent := func(typ, aggregateType string, h models.Hierarchy) event.Event {
+		e := event.New(typ, aggregateType, primitive.NewObjectID().Hex(), "u1", nil)
+		e.Location = h.Location()
+		return e
+	}
+
+	repo := &mockRepository{}
+	events := []event.Event{
+		newEvent(branch.EventBranchUpdated, branch.AggregateType, branchA),
+		newEvent(branch.EventBranchUpdated, branch.AggregateType, branchB),
+		newEvent(user.EventUserCreated, user.AggregateType, branchA),
+		newEvent(branch.EventBranchUpdated, branch.AggregateType, otherShop),
+		newEvent(shop.EventShopUpdated, shop.AggregateType, models.Hierarchy{ShopID: shopID}),
+	}
+	if err := repo.Append(ctx, events, events[0].OccurredAt); err != nil {
+		t.Fatal(err)
+	}
+	uc := NewUsecase(&mockLogger{}, repo)
+
+	poll := func(t *testing.T, sc models.Scope) []string {
+		out, err := uc.Poll(ctx, sc, stream.PollInput{})
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if out.Cursor != stream.FormatCursor(repo.changes[len(repo.changes)-1].Seq) {
+			t.Errorf("Cursor phải tiến qua cả change bị lọc")
+		}
+		var ids []string
+		for _, c := range out.Changes {
+			ids = append(ids, c.Event.ID)
+		}
+		return ids
+	}
+
+	t.Run("branch manager only sees own branch", func(t *testing.T) {
+		sc := models.Scope{Role: models.RoleBranchManager, ShopID: &shopID, RegionID: &regionID, BranchID: &branchA.BranchID}
+		got := poll(t, sc)
+		if len(got) != 2 || got[0] != events[0].ID || got[1] != events[2].ID {
+			t.Errorf("Branch manager chỉ được thấy branch và user của branch mình: %v", got)
+		}
+	})
+
+	t.Run("employee sees users but not branches", func(t *testing.T) {
+		sc := models.Scope{Role: models.RoleEmployee, ShopID: &shopID, RegionID: &regionID, BranchID: &branchA.BranchID}
+		got := poll(t, sc)
+		if len(got) != 1 || got[0] != events[2].ID {
+			t.Errorf("Employee chỉ được thấy user như REST API: %v", got)
+		}
+	})
+
+	t.Run("manager sees whole shop only", func(t *testing.T) {
+		sc := models.Scope{Role: models.RoleManager, ShopID: &shopID}
+		got := poll(t, sc)
+		if len(got) != 4 {
+			t.Errorf("Manager phải thấy mọi thay đổi của shop mình, không thấy shop khác: %v", got)
+		}
+	})
+
+	t.Run("resume after cursor with batch limit", func(t *testing.T) {
+		sc := models.Scope{Role: models.RoleManager, ShopID: &shopID}
+		out, err := uc.Poll(ctx, sc, stream.PollInput{Cursor: stream.FormatCursor(repo.changes[0].Seq), Limit: 2})
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if !out.More || out.Cursor != stream.FormatCursor(repo.changes[2].Seq) || len(out.Changes) != 2 {
+			t.Errorf("Phải đọc 2 change sau cursor và báo còn change: %+v", out)
+		}
+	})
+}
+
+func TestPollWaitsForOutOfOrderChange(t *testing.T) {
+	ctx := context.Background()
+	shopID := primitive.NewObjectID()
+	sc := models.Scope{Role: models.RoleManager, ShopID: &shopID}
+	now := time.Now()
+
+	newChange := func(seq int64, at time.Time) models.Change {
+		e := event.New(shop.EventShopUpdated, shop.AggregateType, shopID.Hex(), "u1", nil)
+		e.Location = models.Hierarchy{ShopID: shopID}.Location()
+		return models.Change{ID: primitive.NewObjectID(), Seq: seq, Event: e, CreatedAt: at}
+	}
+
+	// Change 2 được cấp số trước nhưng ghi xong sau change 3 (hai dispatcher chạy song song)
+	repo := &mockRepository{changes: []models.Change{newChange(1, now), newChange(3, now)}}
+	uc := &implUsecase{l: &mockLogger{}, repo: repo, now: func() time.Time { return now }}
+
+	t.Run("stop before a recent gap", func(t *testing.T) {
+		out, err := uc.Poll(ctx, sc, stream.PollInput{Cursor: "1"})
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if len(out.Changes) != 0 || out.Cursor != "1" || out.More {
+			t.Errorf("Cursor không được vượt qua change 2 chưa ghi xong: %+v", out)
+		}
+	})
+
+	t.Run("read the late change once committed", func(t *testing.T) {
+		repo.changes = append(repo.changes, newChange(2, now))
+		out, err := uc.Poll(ctx, sc, stream.PollInput{Cursor: "1"})
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if len(out.Changes) != 2 || out.Changes[0].Seq != 2 || out.Changes[1].Seq != 3 || out.Cursor != "3" {
+			t.Errorf("Phải đọc change 2 rồi change 3: %+v", out)
+		}
+	})
+
+	t.Run("skip a gap older than GapTimeout", func(t *testing.T) {
+		repo.changes = append(repo.changes, newChange(5, now))
+		uc.now = func() time.Time { return now.Add(stream.GapTimeout) }
+		out, err := uc.Poll(ctx, sc, stream.PollInput{Cursor: "3"})
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if len(out.Changes) != 1 || out.Cursor != "5" {
+			t.Errorf("Số thứ tự 4 bị bỏ phải được đọc qua sau GapTimeout: %+v", out)
+		}
+	})
+
+	t.Run("first change from start of log", func(t *testing.T) {
+		repo := &mockRepository{changes: []models.Change{newChange(7, now)}}
+		uc := &implUsecase{l: &mockLogger{}, repo: repo, now: func() time.Time { return now }}
+		out, err := uc.Poll(ctx, sc, stream.PollInput{})
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if len(out.Changes) != 1 || out.Cursor != "7" {
+			t.Errorf("Đọc từ đầu log không cần chờ các số thứ tự đã hết hạn: %+v", out)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/event"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// mockRepository giữ change log trong bộ nhớ, số thứ tự tăng dần theo thứ tự ghi.
+// Test có thể thêm thẳng vào changes để giả lập change ghi xong không theo thứ tự số.
+type mockRepository struct {
+	changes []models.Change
+	seq     int64
+}
+
+func (m *mockRepository) Append(ctx context.Context, events []event.Event, at time.Time) error {
+	for _, e := range events {
+		exists := false
+		for _, c := range m.changes {
+			if c.Event.ID == e.ID {
+				exists = true
+			}
+		}
+		if !exists {
+			m.seq++
+			m.changes = append(m.changes, models.Change{ID: primitive.NewObjectID(), Seq: m.seq, Event: e, CreatedAt: at})
+		}
+	}
+	return nil
+}
+
+func (m *mockRepository) Exists(ctx context.Context, seq int64) (bool, error) {
+	for _, c := range m.changes {
+		if c.Seq == seq {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockRepository) Last(ctx context.Context) (int64, error) {
+	var last int64
+	for _, c := range m.changes {
+		if c.Seq > last {
+			last = c.Seq
+		}
+	}
+	return last, nil
+}
+
+func (m *mockRepository) ListAfter(ctx context.Context, after int64, limit int64) ([]models.Change, error) {
+	var out []models.Change
+	for _, c := range m.changes {
+		if c.Seq > after {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Seq < out[j].Seq })
+	if int64(len(out)) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// Mock Logger - Giả lập Logger interface
+type mockLogger struct{}
+
+func (m *mockLogger) Debug(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Debugf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Info(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Infof(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Warn(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Warnf(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Error(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Errorf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Fatal(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Fatalf(ctx context.Context, template string, arg ...any) {}
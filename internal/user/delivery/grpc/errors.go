@@ -0,0 +1,32 @@
+package grpc
+
+import (
+	"errors"
+
+	"thuchanhgolang/internal/user"
+	pkgErrors "thuchanhgolang/pkg/errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errUnauthorized   = status.Error(codes.Unauthenticated, "Unauthorized")
+	errForbidden      = status.Error(codes.PermissionDenied, "Forbidden: You don't have permission to access this resource")
+	errBranchRequired = status.Error(codes.InvalidArgument, "branch_id or department_id is required")
+
+	errUsernameExists = pkgErrors.NewConflictError(30015, "Username already exists", "username")
+	errEmailExists    = pkgErrors.NewConflictError(30016, "Email already exists in this shop", "email")
+)
+
+// mapError chuyển lỗi trùng giá trị duy nhất thành ConflictError (AlreadyExists kèm field) như REST,
+// các lỗi nghiệp vụ khác được chuyển theo loại lỗi ở grpcserver
+func (h handler) mapError(err error) error {
+	if errors.Is(err, user.ErrUsernameExists) {
+		return errUsernameExists
+	}
+	if errors.Is(err, user.ErrEmailExists) {
+		return errEmailExists
+	}
+	return err
+}
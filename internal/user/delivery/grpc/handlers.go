@@ -0,0 +1,112 @@
+package grpc
+
+import (
+	"context"
+
+	"thuchanhgolang/pkg/orgapi"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Get lấy user theo ID
+func (h handler) Get(ctx context.Context, req *orgapi.GetRequest) (*orgapi.User, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để lấy user
+	id, _ := primitive.ObjectIDFromHex(req.ID)
+	user, err := h.uc.GetByID(ctx, sc, id)
+	if err != nil {
+		h.l.Warnf(ctx, "user.grpc.Get.uc.GetByID: %s", err)
+		return nil, err
+	}
+
+	// Bước 3: Chỉ trả về user nằm trong phạm vi dữ liệu của người gọi
+	if !sc.Contains(user.Hierarchy()) {
+		h.l.Warnf(ctx, "user.grpc.Get: user %s out of scope", req.ID)
+		return nil, errForbidden
+	}
+
+	return h.newUser(user), nil
+}
+
+// List lấy danh sách user có phân trang
+func (h handler) List(ctx context.Context, req *orgapi.ListUsersRequest) (*orgapi.ListUsersResponse, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để lấy danh sách
+	out, err := h.uc.List(ctx, sc, h.toListInput(req))
+	if err != nil {
+		h.l.Warnf(ctx, "user.grpc.List.uc.List: %s", err)
+		return nil, err
+	}
+
+	return h.newListResp(out), nil
+}
+
+// Create tạo user mới
+func (h handler) Create(ctx context.Context, req *orgapi.CreateUserRequest) (*orgapi.User, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Phải có branch hoặc department
+	if err := h.validateCreateRequest(req); err != nil {
+		h.l.Warnf(ctx, "user.grpc.Create.validateCreateRequest: %s", err)
+		return nil, err
+	}
+
+	// Bước 3: Gọi usecase để tạo user
+	user, err := h.uc.Create(ctx, sc, h.toCreateInput(req))
+	if err != nil {
+		h.l.Warnf(ctx, "user.grpc.Create.uc.Create: %s", err)
+		return nil, h.mapError(err)
+	}
+
+	return h.newUser(user), nil
+}
+
+// Update cập nhật user, chỉ khi user chưa bị sửa sau version client đã đọc
+func (h handler) Update(ctx context.Context, req *orgapi.UpdateUserRequest) (*orgapi.User, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để cập nhật user
+	user, err := h.uc.Update(ctx, sc, h.toUpdateInput(req))
+	if err != nil {
+		h.l.Warnf(ctx, "user.grpc.Update.uc.Update: %s", err)
+		return nil, h.mapError(err)
+	}
+
+	return h.newUser(user), nil
+}
+
+// Delete xóa user, chỉ khi user chưa bị sửa sau version client đã đọc
+func (h handler) Delete(ctx context.Context, req *orgapi.DeleteRequest) (*orgapi.Empty, error) {
+	// Bước 1: Lấy scope và kiểm tra quyền
+	sc, err := h.processScope(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bước 2: Gọi usecase để xóa user
+	id, _ := primitive.ObjectIDFromHex(req.ID)
+	if err := h.uc.Delete(ctx, sc, id, req.Version); err != nil {
+		h.l.Warnf(ctx, "user.grpc.Delete.uc.Delete: %s", err)
+		return nil, err
+	}
+
+	return &orgapi.Empty{}, nil
+}
@@ -0,0 +1,21 @@
+package grpc
+
+import (
+	"thuchanhgolang/internal/user"
+	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/orgapi"
+)
+
+// handler là implementation của orgapi.UserServiceServer
+type handler struct {
+	l  log.Logger   // Logger để ghi log
+	uc user.Usecase // Usecase để xử lý business logic
+}
+
+// New tạo gRPC service cho user, dùng chung usecase với REST
+func New(l log.Logger, uc user.Usecase) orgapi.UserServiceServer {
+	return handler{
+		l:  l,
+		uc: uc,
+	}
+}
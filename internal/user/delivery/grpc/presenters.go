@@ -0,0 +1,134 @@
+package grpc
+
+import (
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/user"
+	"thuchanhgolang/pkg/orgapi"
+	"thuchanhgolang/pkg/paginator"
+	"thuchanhgolang/pkg/patch"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// toCreateInput chuyển đổi request thành input cho usecase, các ID không gửi được usecase lấy từ department / branch
+func (h handler) toCreateInput(req *orgapi.CreateUserRequest) user.CreateInput {
+	input := user.CreateInput{
+		Username: req.Username,
+		Password: req.Password,
+		Email:    req.Email,
+	}
+	if req.DepartmentID != nil {
+		id, _ := primitive.ObjectIDFromHex(*req.DepartmentID)
+		input.DepartmentID = &id
+	}
+	if req.BranchID != nil {
+		input.BranchID, _ = primitive.ObjectIDFromHex(*req.BranchID)
+	}
+	if req.ShopID != nil {
+		input.ShopID, _ = primitive.ObjectIDFromHex(*req.ShopID)
+	}
+	if req.RegionID != nil {
+		input.RegionID, _ = primitive.ObjectIDFromHex(*req.RegionID)
+	}
+	return input
+}
+
+// toUpdateInput chuyển đổi request thành input cho usecase
+func (h handler) toUpdateInput(req *orgapi.UpdateUserRequest) user.UpdateInput {
+	id, _ := primitive.ObjectIDFromHex(req.ID)
+	input := user.UpdateInput{
+		ID:       id,
+		Username: req.Username,
+		Password: req.Password,
+		Email:    req.Email,
+		ShopID:   objectIDPtr(req.ShopID),
+		RegionID: objectIDPtr(req.RegionID),
+		BranchID: objectIDPtr(req.BranchID),
+		Version:  req.Version,
+	}
+	if deptID := objectIDPtr(req.DepartmentID); deptID != nil {
+		input.DepartmentID = patch.Set(*deptID)
+	}
+	return input
+}
+
+// toListInput chuyển đổi request thành input cho usecase
+func (h handler) toListInput(req *orgapi.ListUsersRequest) user.ListInput {
+	input := user.ListInput{
+		Pagin: paginator.PaginatorQuery{Page: req.Page.Page, Limit: req.Page.Limit},
+	}
+	if req.BranchID != "" {
+		branchID, _ := primitive.ObjectIDFromHex(req.BranchID)
+		input.BranchID = &branchID
+	}
+	if req.DepartmentID != "" {
+		deptID, _ := primitive.ObjectIDFromHex(req.DepartmentID)
+		input.DepartmentID = &deptID
+	}
+	return input
+}
+
+// objectIDPtr đọc ID dạng hex (có thể nil)
+func objectIDPtr(hex *string) *primitive.ObjectID {
+	if hex == nil {
+		return nil
+	}
+	id, _ := primitive.ObjectIDFromHex(*hex)
+	return &id
+}
+
+// newUser tạo message từ user model, không trả về mật khẩu
+func (h handler) newUser(d models.User) *orgapi.User {
+	resp := &orgapi.User{
+		ID:            d.ID.Hex(),
+		Username:      d.Username,
+		Email:         d.Email,
+		Role:          string(d.Role),
+		DeactivatedAt: d.DeactivatedAt,
+		Version:       d.Version,
+		CreatedAt:     d.CreatedAt,
+		UpdatedAt:     d.UpdatedAt,
+		CreatedBy:     d.CreatedBy,
+		UpdatedBy:     d.UpdatedBy,
+	}
+
+	// Chỉ thêm các field nếu có giá trị (không phải zero value)
+	if !d.ShopID.IsZero() {
+		resp.ShopID = d.ShopID.Hex()
+	}
+	if !d.RegionID.IsZero() {
+		resp.RegionID = d.RegionID.Hex()
+	}
+	if !d.BranchID.IsZero() {
+		resp.BranchID = d.BranchID.Hex()
+	}
+	if d.DepartmentID != nil && !d.DepartmentID.IsZero() {
+		deptID := d.DepartmentID.Hex()
+		resp.DepartmentID = &deptID
+	}
+	return resp
+}
+
+// newListResp tạo message từ danh sách user
+func (h handler) newListResp(out user.ListOutput) *orgapi.ListUsersResponse {
+	items := make([]orgapi.User, 0, len(out.Users))
+	for _, d := range out.Users {
+		items = append(items, *h.newUser(d))
+	}
+	return &orgapi.ListUsersResponse{
+		Items: items,
+		Meta:  newPageInfo(out.Pagin),
+	}
+}
+
+// newPageInfo tạo thông tin phân trang
+func newPageInfo(p paginator.Paginator) orgapi.PageInfo {
+	resp := p.ToResponse()
+	return orgapi.PageInfo{
+		Total:       resp.Total,
+		Count:       resp.Count,
+		PerPage:     resp.PerPage,
+		CurrentPage: resp.CurrentPage,
+		TotalPages:  resp.TotalPages,
+	}
+}
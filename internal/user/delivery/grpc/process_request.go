@@ -0,0 +1,35 @@
+package grpc
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/jwt"
+	"thuchanhgolang/pkg/orgapi"
+)
+
+// processScope lấy scope của user đang đăng nhập và kiểm tra quyền như middleware CheckUserAccess của REST:
+// mọi role được đọc, Employee không được tạo / sửa / xóa. Phạm vi dữ liệu được giới hạn trong usecase theo scope
+func (h handler) processScope(ctx context.Context, write bool) (models.Scope, error) {
+	payload, ok := jwt.GetPayloadFromContext(ctx)
+	if !ok {
+		return models.Scope{}, errUnauthorized
+	}
+
+	role := models.Role(payload.Role)
+	if write && role == models.RoleEmployee {
+		h.l.Warnf(ctx, "user.grpc.processScope: employee cannot modify users")
+		return models.Scope{}, errForbidden
+	}
+
+	return jwt.NewScope(payload), nil
+}
+
+// validateCreateRequest kiểm tra request tạo user, định dạng từng field đã được kiểm tra qua binding tag
+func (h handler) validateCreateRequest(req *orgapi.CreateUserRequest) error {
+	// Phải có ít nhất department_id HOẶC branch_id
+	if req.DepartmentID == nil && req.BranchID == nil {
+		return errBranchRequired
+	}
+	return nil
+}
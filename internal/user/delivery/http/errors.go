@@ -2,18 +2,33 @@ package http
 
 import (
 	"errors"
+	"net/http"
 	"thuchanhgolang/internal/user"
 	pkgErrors "thuchanhgolang/pkg/errors"
 )
 
 var (
-	errWrongBody       = pkgErrors.NewHTTPError(30000, "Wrong body")
-	errInvalidID       = pkgErrors.NewHTTPError(30001, "Invalid user ID")
-	errInvalidShopID   = pkgErrors.NewHTTPError(30002, "Invalid shop ID")
-	errInvalidRegionID = pkgErrors.NewHTTPError(30003, "Invalid region ID")
-	errInvalidBranchID = pkgErrors.NewHTTPError(30004, "Invalid branch ID")
-	errInvalidDeptID   = pkgErrors.NewHTTPError(30005, "Invalid department ID")
-	errUserInUse       = pkgErrors.NewHTTPError(30006, "User is being used, cannot delete")
+	errWrongBody         = pkgErrors.NewHTTPError(30000, "Wrong body")
+	errInvalidID         = pkgErrors.NewHTTPError(30001, "Invalid user ID")
+	errInvalidBranchID   = pkgErrors.NewHTTPError(30004, "Invalid branch ID")
+	errInvalidDeptID     = pkgErrors.NewHTTPError(30005, "Invalid department ID")
+	errUserInUse         = pkgErrors.NewHTTPErrorWithStatus(30006, "User is being used, cannot delete", http.StatusConflict)
+	errUserOutOfScope    = pkgErrors.NewHTTPErrorWithStatus(30007, "User is outside of your scope", http.StatusForbidden)
+	errWrongQuery        = pkgErrors.NewHTTPError(30008, "Wrong query")
+	errInvalidCSV        = pkgErrors.NewHTTPError(30009, "Invalid CSV file")
+	errMissingColumns    = pkgErrors.NewHTTPError(30010, "CSV header must contain username, email and branch or department")
+	errImportEmpty       = pkgErrors.NewHTTPErrorWithStatus(30011, "Import file has no data rows", http.StatusUnprocessableEntity)
+	errImportTooLarge    = pkgErrors.NewHTTPErrorWithStatus(30012, "Import file is too large", http.StatusUnprocessableEntity)
+	errImportJobNotFound = pkgErrors.NewHTTPErrorWithStatus(30013, "Import job not found", http.StatusNotFound)
+	errInvalidJobID      = pkgErrors.NewHTTPError(30014, "Invalid import job ID")
+	errUnauthorized      = pkgErrors.NewUnauthorizedHTTPError()
+
+	errUsernameExists = pkgErrors.NewConflictError(30015, "Username already exists", "username")
+	errEmailExists    = pkgErrors.NewConflictError(30016, "Email already exists in this shop", "email")
+	errUserNotFound   = pkgErrors.NewHTTPErrorWithStatus(30017, "User not found", http.StatusNotFound)
+
+	errVersionMismatch      = pkgErrors.NewHTTPErrorWithStatus(30018, "User has been modified by another request, reload and try again", http.StatusPreconditionFailed)
+	errPreconditionRequired = pkgErrors.NewHTTPErrorWithStatus(30019, "If-Match header with the user ETag is required", http.StatusPreconditionRequired)
 )
 
 func (h handler) mapError(err error) error {
@@ -21,5 +36,29 @@ func (h handler) mapError(err error) error {
 	if errors.Is(err, user.ErrUserInUse) {
 		return errUserInUse
 	}
+	if errors.Is(err, user.ErrUserNotFound) {
+		return errUserNotFound
+	}
+	if errors.Is(err, user.ErrUserOutOfScope) {
+		return errUserOutOfScope
+	}
+	if errors.Is(err, user.ErrImportEmpty) {
+		return errImportEmpty
+	}
+	if errors.Is(err, user.ErrImportTooLarge) {
+		return errImportTooLarge
+	}
+	if errors.Is(err, user.ErrImportJobNotFound) {
+		return errImportJobNotFound
+	}
+	if errors.Is(err, user.ErrUsernameExists) {
+		return errUsernameExists
+	}
+	if errors.Is(err, user.ErrEmailExists) {
+		return errEmailExists
+	}
+	if errors.Is(err, user.ErrVersionMismatch) {
+		return errVersionMismatch
+	}
 	return err
 }
@@ -3,6 +3,7 @@ package http
 import (
 	"thuchanhgolang/internal/models"
 	"thuchanhgolang/pkg/response"
+	"thuchanhgolang/pkg/util"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -31,6 +32,7 @@ func (h handler) create(c *gin.Context) {
 	}
 
 	// Trả về kết quả thành công
+	util.SetETag(c, user.Version)
 	response.OK(c, h.newDetailResp(user))
 }
 
@@ -47,11 +49,24 @@ func (h handler) getByID(c *gin.Context) {
 		return
 	}
 
+	// Đọc thời điểm cần xem (as_of), không có thì xem trạng thái hiện tại
+	asOf, err := h.processAsOf(c)
+	if err != nil {
+		h.l.Warnf(ctx, "user.handler.getByID.processAsOf: %s", err)
+		response.Error(c, err)
+		return
+	}
+
 	// Tạo scope trống với đúng type
 	sc := models.Scope{}
 
 	// Gọi usecase để lấy user
-	user, err := h.uc.GetByID(ctx, sc, id)
+	var user models.User
+	if asOf != nil {
+		user, err = h.uc.GetAsOf(ctx, sc, id, *asOf)
+	} else {
+		user, err = h.uc.GetByID(ctx, sc, id)
+	}
 	if err != nil {
 		h.l.Warnf(ctx, "user.handler.getByID.uc.GetByID: %s", err)
 		mapErr := h.mapError(err)
@@ -59,10 +74,74 @@ func (h handler) getByID(c *gin.Context) {
 		return
 	}
 
-	// Trả về kết quả
+	// Trả về kết quả, trạng thái trong quá khứ không có ETag vì không dùng để sửa được
+	if asOf == nil {
+		util.SetETag(c, user.Version)
+	}
 	response.OK(c, h.newDetailResp(user))
 }
 
+// history xử lý HTTP request để lấy lịch sử thay đổi của user
+func (h handler) history(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Lấy ID từ URL param
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		h.l.Warnf(ctx, "user.handler.history.ObjectIDFromHex: %s", err)
+		response.Error(c, errInvalidID)
+		return
+	}
+
+	// Xử lý và validate query
+	req, sc, err := h.processHistoryRequest(c)
+	if err != nil {
+		h.l.Warnf(ctx, "user.handler.history.processHistoryRequest: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Gọi usecase để lấy lịch sử thay đổi
+	out, err := h.uc.History(ctx, sc, req.toInput(id))
+	if err != nil {
+		h.l.Warnf(ctx, "user.handler.history.uc.History: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Trả về lịch sử, mới nhất đứng trước
+	response.OK(c, h.newHistoryResp(out))
+}
+
+// list xử lý HTTP request để lấy danh sách user trong scope
+func (h handler) list(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Xử lý và validate query
+	req, sc, err := h.processListRequest(c)
+	if err != nil {
+		h.l.Warnf(ctx, "user.handler.list.processListRequest: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Gọi usecase để lấy danh sách user
+	out, err := h.uc.List(ctx, sc, req.toInput())
+	if err != nil {
+		h.l.Warnf(ctx, "user.handler.list.uc.List: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Trả về danh sách
+	response.OK(c, h.newListResp(out))
+}
+
 // update xử lý HTTP request để cập nhật user
 func (h handler) update(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -95,6 +174,43 @@ func (h handler) update(c *gin.Context) {
 	}
 
 	// Trả về kết quả
+	util.SetETag(c, user.Version)
+	response.OK(c, h.newDetailResp(user))
+}
+
+// patch xử lý HTTP request cập nhật một phần user theo JSON Merge Patch
+func (h handler) patch(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Lấy ID từ URL param
+	idParam := c.Param("id")
+	id, err := primitive.ObjectIDFromHex(idParam)
+	if err != nil {
+		h.l.Warnf(ctx, "user.handler.patch.ObjectIDFromHex: %s", err)
+		response.Error(c, errInvalidID)
+		return
+	}
+
+	// Xử lý và validate request
+	req, sc, err := h.processPatchRequest(c)
+	if err != nil {
+		h.l.Warnf(ctx, "user.handler.patch.processPatchRequest: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Gọi usecase để update user
+	user, err := h.uc.Update(ctx, sc, req.toInput(id))
+	if err != nil {
+		h.l.Warnf(ctx, "user.handler.patch.uc.Update: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Trả về kết quả
+	util.SetETag(c, user.Version)
 	response.OK(c, h.newDetailResp(user))
 }
 
@@ -111,11 +227,24 @@ func (h handler) delete(c *gin.Context) {
 		return
 	}
 
-	// Tạo scope trống với đúng type
-	sc := models.Scope{}
+	// Lấy scope của user đang đăng nhập
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "user.handler.delete.processScope: %s", err)
+		response.Error(c, err)
+		return
+	}
+
+	// Đọc version từ If-Match
+	version, err := h.processVersion(c)
+	if err != nil {
+		h.l.Warnf(ctx, "user.handler.delete.processVersion: %s", err)
+		response.Error(c, err)
+		return
+	}
 
 	// Gọi usecase để xóa user
-	err = h.uc.Delete(ctx, sc, id)
+	err = h.uc.Delete(ctx, sc, id, version)
 	if err != nil {
 		h.l.Warnf(ctx, "user.handler.delete.uc.Delete: %s", err)
 		mapErr := h.mapError(err)
@@ -126,3 +255,52 @@ func (h handler) delete(c *gin.Context) {
 	// Trả về success
 	response.OK(c, gin.H{"message": "User deleted successfully"})
 }
+
+// importUsers xử lý HTTP request import nhiều user từ file CSV
+// File nhỏ trả kết quả ngay, file lớn trả về job để theo dõi qua GET /import/:job_id
+func (h handler) importUsers(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Đọc file CSV và scope của người import
+	input, sc, err := h.processImportRequest(c)
+	if err != nil {
+		h.l.Warnf(ctx, "user.handler.importUsers.processImportRequest: %s", err)
+		response.Error(c, h.mapError(err))
+		return
+	}
+
+	// Gọi usecase để validate / import
+	job, err := h.uc.Import(ctx, sc, input)
+	if err != nil {
+		h.l.Warnf(ctx, "user.handler.importUsers.uc.Import: %s", err)
+		response.Error(c, h.mapError(err))
+		return
+	}
+
+	// Kết quả import ngay chứa mật khẩu tạm nên không được cache hay lưu lại
+	c.Header("Cache-Control", "no-store")
+	response.OK(c, h.newImportJobResp(job, util.GetLanguage(c)))
+}
+
+// getImportJob xử lý HTTP request lấy tiến độ / kết quả của job import
+func (h handler) getImportJob(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, sc, err := h.processGetImportJobRequest(c)
+	if err != nil {
+		h.l.Warnf(ctx, "user.handler.getImportJob.processGetImportJobRequest: %s", err)
+		response.Error(c, h.mapError(err))
+		return
+	}
+
+	job, err := h.uc.GetImportJob(ctx, sc, id)
+	if err != nil {
+		h.l.Warnf(ctx, "user.handler.getImportJob.uc.GetImportJob: %s", err)
+		response.Error(c, h.mapError(err))
+		return
+	}
+
+	// Job đã xong trả mật khẩu tạm một lần, không được cache
+	c.Header("Cache-Control", "no-store")
+	response.OK(c, h.newImportJobResp(job, util.GetLanguage(c)))
+}
@@ -1,10 +1,17 @@
 package http
 
 import (
-	"strings"
+	"errors"
+	"time"
 
 	"thuchanhgolang/internal/models"
 	"thuchanhgolang/internal/user"
+	pkgErrors "thuchanhgolang/pkg/errors"
+	"thuchanhgolang/pkg/i18n"
+	"thuchanhgolang/pkg/paginator"
+	"thuchanhgolang/pkg/patch"
+	"thuchanhgolang/pkg/response"
+	"thuchanhgolang/pkg/util"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -12,55 +19,22 @@ import (
 // createReq là cấu trúc nhận dữ liệu từ HTTP request
 // CHỈ CẦN: department_id (nếu user thuộc dept) HOẶC branch_id (nếu không thuộc dept)
 type createReq struct {
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
-	Email    string `json:"email" binding:"required"`
-	// Các ID sau là OPTIONAL - hệ thống tự động lấy từ department/branch
-	ShopID       *string `json:"shop_id"`       // Optional - auto-fetched
-	RegionID     *string `json:"region_id"`     // Optional - auto-fetched
-	BranchID     *string `json:"branch_id"`     // Optional nếu có department_id
-	DepartmentID *string `json:"department_id"` // Optional - nhưng phải có branch_id HOẶC department_id
+	Username string `json:"username" binding:"required,username"`
+	Password string `json:"password" binding:"required,min=6,max=72"`
+	Email    string `json:"email" binding:"required,email"`
+	// Các ID sau là OPTIONAL - hệ thống tự động lấy từ department/branch, nếu gửi thì phải khớp
+	ShopID       *string `json:"shop_id" binding:"omitempty,objectid"`       // Optional - phải khớp với hierarchy
+	RegionID     *string `json:"region_id" binding:"omitempty,objectid"`     // Optional - phải khớp với hierarchy
+	BranchID     *string `json:"branch_id" binding:"omitempty,objectid"`     // Optional nếu có department_id
+	DepartmentID *string `json:"department_id" binding:"omitempty,objectid"` // Optional - nhưng phải có branch_id HOẶC department_id
 }
 
-// validate kiểm tra dữ liệu đầu vào
+// validate kiểm tra dữ liệu đầu vào, định dạng từng field đã được kiểm tra qua binding tag
 func (r createReq) validate() error {
 	// Kiểm tra phải có ít nhất department_id HOẶC branch_id
 	if r.DepartmentID == nil && r.BranchID == nil {
 		return errInvalidDeptID // Reuse error (hoặc tạo error mới)
 	}
-
-	// Kiểm tra DepartmentID nếu có
-	if r.DepartmentID != nil {
-		if _, err := primitive.ObjectIDFromHex(*r.DepartmentID); err != nil {
-			return errInvalidDeptID
-		}
-	}
-
-	// Kiểm tra BranchID nếu có
-	if r.BranchID != nil {
-		if _, err := primitive.ObjectIDFromHex(*r.BranchID); err != nil {
-			return errInvalidBranchID
-		}
-	}
-
-	// Kiểm tra ShopID nếu có (backward compatibility)
-	if r.ShopID != nil {
-		if _, err := primitive.ObjectIDFromHex(*r.ShopID); err != nil {
-			return errInvalidShopID
-		}
-	}
-
-	// Kiểm tra RegionID nếu có (backward compatibility)
-	if r.RegionID != nil {
-		if _, err := primitive.ObjectIDFromHex(*r.RegionID); err != nil {
-			return errInvalidRegionID
-		}
-	}
-
-	// Kiểm tra các fields không rỗng
-	if strings.TrimSpace(r.Username) == "" || strings.TrimSpace(r.Password) == "" || strings.TrimSpace(r.Email) == "" {
-		return errWrongBody
-	}
 	return nil
 }
 
@@ -84,13 +58,13 @@ func (r createReq) toInput() user.CreateInput {
 		input.BranchID = id
 	}
 
-	// Parse ShopID nếu có (backward compatibility)
+	// Parse ShopID nếu có (usecase sẽ đối chiếu với hierarchy)
 	if r.ShopID != nil {
 		id, _ := primitive.ObjectIDFromHex(*r.ShopID)
 		input.ShopID = id
 	}
 
-	// Parse RegionID nếu có (backward compatibility)
+	// Parse RegionID nếu có (usecase sẽ đối chiếu với hierarchy)
 	if r.RegionID != nil {
 		id, _ := primitive.ObjectIDFromHex(*r.RegionID)
 		input.RegionID = id
@@ -101,42 +75,15 @@ func (r createReq) toInput() user.CreateInput {
 
 // updateReq là cấu trúc nhận dữ liệu update từ HTTP request
 type updateReq struct {
-	Username     *string `json:"username"`
-	Password     *string `json:"password"`
-	Email        *string `json:"email"`
-	ShopID       *string `json:"shop_id"`
-	RegionID     *string `json:"region_id"`
-	BranchID     *string `json:"branch_id"`
-	DepartmentID *string `json:"department_id"`
-}
-
-// validate kiểm tra dữ liệu update
-func (r updateReq) validate() error {
-	// Kiểm tra ShopID nếu có
-	if r.ShopID != nil {
-		if _, err := primitive.ObjectIDFromHex(*r.ShopID); err != nil {
-			return errInvalidShopID
-		}
-	}
-	// Kiểm tra RegionID nếu có
-	if r.RegionID != nil {
-		if _, err := primitive.ObjectIDFromHex(*r.RegionID); err != nil {
-			return errInvalidRegionID
-		}
-	}
-	// Kiểm tra BranchID nếu có
-	if r.BranchID != nil {
-		if _, err := primitive.ObjectIDFromHex(*r.BranchID); err != nil {
-			return errInvalidBranchID
-		}
-	}
-	// Kiểm tra DepartmentID nếu có
-	if r.DepartmentID != nil {
-		if _, err := primitive.ObjectIDFromHex(*r.DepartmentID); err != nil {
-			return errInvalidDeptID
-		}
-	}
-	return nil
+	Username     *string `json:"username" binding:"omitempty,username"`
+	Password     *string `json:"password" binding:"omitempty,min=6,max=72"`
+	Email        *string `json:"email" binding:"omitempty,email"`
+	ShopID       *string `json:"shop_id" binding:"omitempty,objectid"`
+	RegionID     *string `json:"region_id" binding:"omitempty,objectid"`
+	BranchID     *string `json:"branch_id" binding:"omitempty,objectid"`
+	DepartmentID *string `json:"department_id" binding:"omitempty,objectid"`
+
+	version int // Version lấy từ If-Match, không đọc từ body
 }
 
 // toInput chuyển đổi update request thành input cho usecase
@@ -146,6 +93,7 @@ func (r updateReq) toInput(id primitive.ObjectID) user.UpdateInput {
 		Username: r.Username,
 		Password: r.Password,
 		Email:    r.Email,
+		Version:  r.version,
 	}
 
 	if r.ShopID != nil {
@@ -162,29 +110,182 @@ func (r updateReq) toInput(id primitive.ObjectID) user.UpdateInput {
 	}
 	if r.DepartmentID != nil {
 		deptID, _ := primitive.ObjectIDFromHex(*r.DepartmentID)
-		input.DepartmentID = &deptID
+		input.DepartmentID = patch.Set(deptID)
+	}
+
+	return input
+}
+
+// patchReq là JSON Merge Patch (RFC 7396) của user: field không gửi thì giữ nguyên,
+// department_id null thì user rời department và ở lại branch hiện tại (hoặc branch_id gửi kèm)
+type patchReq struct {
+	Username     patch.Field[string] `json:"username" binding:"omitempty,username"`
+	Password     patch.Field[string] `json:"password" binding:"omitempty,min=6,max=72"`
+	Email        patch.Field[string] `json:"email" binding:"omitempty,email"`
+	ShopID       patch.Field[string] `json:"shop_id" binding:"omitempty,objectid"`
+	RegionID     patch.Field[string] `json:"region_id" binding:"omitempty,objectid"`
+	BranchID     patch.Field[string] `json:"branch_id" binding:"omitempty,objectid"`
+	DepartmentID patch.Field[string] `json:"department_id" binding:"omitempty,objectid"`
+
+	version int // Version lấy từ If-Match, không đọc từ body
+}
+
+// validate từ chối null cho các field bắt buộc, chỉ department_id được xóa
+func (r patchReq) validate() error {
+	// Ít nhất 1 field phải được gửi
+	if !r.Username.Present && !r.Password.Present && !r.Email.Present &&
+		!r.ShopID.Present && !r.RegionID.Present && !r.BranchID.Present && !r.DepartmentID.Present {
+		return errWrongBody
+	}
+
+	collector := pkgErrors.NewValidationErrorCollector()
+	if r.Username.IsNull() {
+		collector.Add(pkgErrors.NewValidationError("username", "cannot be null"))
+	}
+	if r.Password.IsNull() {
+		collector.Add(pkgErrors.NewValidationError("password", "cannot be null"))
+	}
+	if r.Email.IsNull() {
+		collector.Add(pkgErrors.NewValidationError("email", "cannot be null"))
+	}
+	if r.ShopID.IsNull() {
+		collector.Add(pkgErrors.NewValidationError("shop_id", "cannot be null"))
+	}
+	if r.RegionID.IsNull() {
+		collector.Add(pkgErrors.NewValidationError("region_id", "cannot be null"))
+	}
+	if r.BranchID.IsNull() {
+		collector.Add(pkgErrors.NewValidationError("branch_id", "cannot be null"))
+	}
+	if collector.HasError() {
+		return collector
+	}
+	return nil
+}
+
+// toInput chuyển đổi merge patch thành input cho usecase
+func (r patchReq) toInput(id primitive.ObjectID) user.UpdateInput {
+	input := user.UpdateInput{
+		ID:       id,
+		Username: r.Username.Ptr(),
+		Password: r.Password.Ptr(),
+		Email:    r.Email.Ptr(),
+		ShopID:   objectIDPtr(r.ShopID),
+		RegionID: objectIDPtr(r.RegionID),
+		BranchID: objectIDPtr(r.BranchID),
+		Version:  r.version,
+	}
+
+	switch {
+	case r.DepartmentID.IsNull():
+		input.DepartmentID = patch.Null[primitive.ObjectID]()
+	case r.DepartmentID.IsSet():
+		deptID, _ := primitive.ObjectIDFromHex(r.DepartmentID.Value)
+		input.DepartmentID = patch.Set(deptID)
 	}
 
 	return input
 }
 
+// objectIDPtr chuyển ID trong merge patch thành ObjectID, nil khi không gửi
+func objectIDPtr(f patch.Field[string]) *primitive.ObjectID {
+	if !f.IsSet() {
+		return nil
+	}
+	id, _ := primitive.ObjectIDFromHex(f.Value)
+	return &id
+}
+
+// listReq là query lấy danh sách user, các mốc thời gian theo định dạng "2006-01-02 15:04:05"
+type listReq struct {
+	paginator.PaginatorQuery
+	BranchID     string `form:"branch_id"`
+	DepartmentID string `form:"department_id"`
+	CreatedFrom  string `form:"created_from"`
+	CreatedTo    string `form:"created_to"`
+	UpdatedFrom  string `form:"updated_from"`
+	UpdatedTo    string `form:"updated_to"`
+}
+
+// validate kiểm tra query lấy danh sách
+func (r listReq) validate() error {
+	if r.BranchID != "" {
+		if _, err := primitive.ObjectIDFromHex(r.BranchID); err != nil {
+			return errInvalidBranchID
+		}
+	}
+	if r.DepartmentID != "" {
+		if _, err := primitive.ObjectIDFromHex(r.DepartmentID); err != nil {
+			return errInvalidDeptID
+		}
+	}
+	// Các mốc thời gian phải đúng định dạng, mốc bắt đầu không được sau mốc kết thúc
+	filter, err := r.timeFilter()
+	if err != nil || !filter.IsValid() {
+		return errWrongQuery
+	}
+	return nil
+}
+
+// toInput chuyển đổi query thành input cho usecase
+func (r listReq) toInput() user.ListInput {
+	filter, _ := r.timeFilter()
+	input := user.ListInput{
+		Filter: filter,
+		Pagin:  r.PaginatorQuery,
+	}
+	if r.BranchID != "" {
+		branchID, _ := primitive.ObjectIDFromHex(r.BranchID)
+		input.BranchID = &branchID
+	}
+	if r.DepartmentID != "" {
+		deptID, _ := primitive.ObjectIDFromHex(r.DepartmentID)
+		input.DepartmentID = &deptID
+	}
+	return input
+}
+
+// timeFilter đọc các mốc thời gian trong query, mốc rỗng thì không giới hạn
+func (r listReq) timeFilter() (models.TimeFilter, error) {
+	createdFrom, errCreatedFrom := util.StrToDateTimePtr(r.CreatedFrom)
+	createdTo, errCreatedTo := util.StrToDateTimePtr(r.CreatedTo)
+	updatedFrom, errUpdatedFrom := util.StrToDateTimePtr(r.UpdatedFrom)
+	updatedTo, errUpdatedTo := util.StrToDateTimePtr(r.UpdatedTo)
+
+	filter := models.TimeFilter{
+		CreatedFrom: createdFrom,
+		CreatedTo:   createdTo,
+		UpdatedFrom: updatedFrom,
+		UpdatedTo:   updatedTo,
+	}
+	return filter, errors.Join(errCreatedFrom, errCreatedTo, errUpdatedFrom, errUpdatedTo)
+}
+
 // detailResp là cấu trúc trả về cho client
 type detailResp struct {
-	ID           string  `json:"id"`
-	Username     string  `json:"username"`
-	Email        string  `json:"email"`
-	ShopID       string  `json:"shop_id,omitempty"`
-	RegionID     string  `json:"region_id,omitempty"`
-	BranchID     string  `json:"branch_id,omitempty"`
-	DepartmentID *string `json:"department_id,omitempty"`
+	ID           string            `json:"id"`
+	Username     string            `json:"username"`
+	Email        string            `json:"email"`
+	ShopID       string            `json:"shop_id,omitempty"`
+	RegionID     string            `json:"region_id,omitempty"`
+	BranchID     string            `json:"branch_id,omitempty"`
+	DepartmentID *string           `json:"department_id,omitempty"`
+	CreatedAt    response.DateTime `json:"created_at"`
+	UpdatedAt    response.DateTime `json:"updated_at"`
+	CreatedBy    string            `json:"created_by,omitempty"`
+	UpdatedBy    string            `json:"updated_by,omitempty"`
 }
 
 // newDetailResp tạo response từ user model
 func (h handler) newDetailResp(d models.User) detailResp {
 	resp := detailResp{
-		ID:       d.ID.Hex(),
-		Username: d.Username,
-		Email:    d.Email,
+		ID:        d.ID.Hex(),
+		Username:  d.Username,
+		Email:     d.Email,
+		CreatedAt: response.DateTime(d.CreatedAt),
+		UpdatedAt: response.DateTime(d.UpdatedAt),
+		CreatedBy: d.CreatedBy,
+		UpdatedBy: d.UpdatedBy,
 	}
 
 	// Chỉ thêm các field nếu có giá trị (không phải zero value)
@@ -204,3 +305,152 @@ func (h handler) newDetailResp(d models.User) detailResp {
 
 	return resp
 }
+
+// listResp là danh sách user trả về cho client
+type listResp struct {
+	Items []detailResp                `json:"items"`
+	Meta  paginator.PaginatorResponse `json:"meta"`
+}
+
+// newListResp tạo response từ danh sách user
+func (h handler) newListResp(out user.ListOutput) listResp {
+	items := make([]detailResp, 0, len(out.Users))
+	for _, u := range out.Users {
+		items = append(items, h.newDetailResp(u))
+	}
+	return listResp{
+		Items: items,
+		Meta:  out.Pagin.ToResponse(),
+	}
+}
+
+// importErrorResp là lỗi của một ô trong file CSV, cùng định dạng với ValidationErrorCollector
+type importErrorResp struct {
+	Field    string   `json:"field"`
+	Messages []string `json:"messages"`
+}
+
+// importedUserResp là một dòng hợp lệ trong kết quả import
+type importedUserResp struct {
+	Line              int     `json:"line"`
+	ID                string  `json:"id,omitempty"` // Rỗng khi dry-run
+	Username          string  `json:"username"`
+	Email             string  `json:"email"`
+	Role              string  `json:"role"`
+	ShopID            string  `json:"shop_id"`
+	RegionID          string  `json:"region_id"`
+	BranchID          string  `json:"branch_id"`
+	DepartmentID      *string `json:"department_id,omitempty"`
+	TemporaryPassword string  `json:"temporary_password,omitempty"`
+}
+
+// importJobResp là kết quả (hoặc tiến độ) của một lần import
+type importJobResp struct {
+	ID         string             `json:"id,omitempty"` // Chỉ có khi import chạy nền
+	Status     string             `json:"status"`
+	DryRun     bool               `json:"dry_run"`
+	Total      int                `json:"total"`
+	Processed  int                `json:"processed"`
+	Created    int                `json:"created"`
+	Errors     []importErrorResp  `json:"errors,omitempty"`
+	Users      []importedUserResp `json:"users,omitempty"`
+	Error      string             `json:"error,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+	FinishedAt *time.Time         `json:"finished_at,omitempty"`
+}
+
+// newImportJobResp tạo response từ import job, lỗi từng dòng được dịch theo ngôn ngữ của request
+func (h handler) newImportJobResp(j models.ImportJob, lang string) importJobResp {
+	resp := importJobResp{
+		Status:     string(j.Status),
+		DryRun:     j.DryRun,
+		Total:      j.Total,
+		Processed:  j.Processed,
+		Created:    j.Created,
+		Error:      j.Error,
+		CreatedAt:  j.CreatedAt,
+		FinishedAt: j.FinishedAt,
+	}
+	if !j.ID.IsZero() {
+		resp.ID = j.ID.Hex()
+	}
+
+	for _, e := range j.Errors {
+		messages := make([]string, 0, len(e.Messages))
+		for _, m := range e.Messages {
+			messages = append(messages, i18n.Validation(lang, m))
+		}
+		resp.Errors = append(resp.Errors, importErrorResp{Field: e.Field, Messages: messages})
+	}
+
+	for _, u := range j.Users {
+		ur := importedUserResp{
+			Line:              u.Line,
+			Username:          u.Username,
+			Email:             u.Email,
+			Role:              u.Role.String(),
+			ShopID:            u.ShopID.Hex(),
+			RegionID:          u.RegionID.Hex(),
+			BranchID:          u.BranchID.Hex(),
+			TemporaryPassword: u.TemporaryPassword,
+		}
+		if !u.UserID.IsZero() {
+			ur.ID = u.UserID.Hex()
+		}
+		if u.DepartmentID != nil && *u.DepartmentID != primitive.NilObjectID {
+			deptID := u.DepartmentID.Hex()
+			ur.DepartmentID = &deptID
+		}
+		resp.Users = append(resp.Users, ur)
+	}
+
+	return resp
+}
+
+// historyReq là query lấy lịch sử thay đổi của user
+type historyReq struct {
+	paginator.PaginatorQuery
+}
+
+// toInput chuyển đổi query thành input cho usecase
+func (r historyReq) toInput(id primitive.ObjectID) user.HistoryInput {
+	return user.HistoryInput{
+		ID:    id,
+		Pagin: r.PaginatorQuery,
+	}
+}
+
+// revisionResp là trạng thái của user trước một lần sửa hoặc xóa
+type revisionResp struct {
+	Version   int               `json:"version"`              // Version của snapshot
+	Action    string            `json:"action"`               // Thao tác đã thay thế snapshot: update | delete
+	ValidFrom response.DateTime `json:"valid_from"`           // Snapshot có hiệu lực từ
+	ValidTo   response.DateTime `json:"valid_to"`             // Snapshot bị thay thế lúc
+	ChangedBy string            `json:"changed_by,omitempty"` // ID người sửa / xóa
+	Snapshot  detailResp        `json:"snapshot"`             // Trạng thái của user
+}
+
+// historyResp là lịch sử thay đổi của user trả về cho client
+type historyResp struct {
+	Items []revisionResp              `json:"items"` // Các revision của trang hiện tại, mới nhất đứng trước
+	Meta  paginator.PaginatorResponse `json:"meta"`  // Thông tin phân trang
+}
+
+// newHistoryResp tạo response từ lịch sử thay đổi của user
+func (h handler) newHistoryResp(out user.HistoryOutput) historyResp {
+	items := make([]revisionResp, 0, len(out.Revisions))
+	for _, r := range out.Revisions {
+		items = append(items, revisionResp{
+			Version:   r.User.Version,
+			Action:    string(r.Action),
+			ValidFrom: response.DateTime(r.User.UpdatedAt),
+			ValidTo:   response.DateTime(r.ChangedAt),
+			ChangedBy: r.ChangedBy,
+			Snapshot:  h.newDetailResp(r.User),
+		})
+	}
+	return historyResp{
+		Items: items,
+		Meta:  out.Pagin.ToResponse(),
+	}
+}
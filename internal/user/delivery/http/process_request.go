@@ -1,11 +1,27 @@
 package http
 
 import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
 	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/user"
+	pkgErrors "thuchanhgolang/pkg/errors"
+	"thuchanhgolang/pkg/jwt"
+	"thuchanhgolang/pkg/util"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// importMaxBytes là dung lượng tối đa của file CSV import
+const importMaxBytes = 5 << 20
+
 // processCreateRequest xử lý và validate request tạo user
 func (h handler) processCreateRequest(c *gin.Context) (createReq, models.Scope, error) {
 	ctx := c.Request.Context()
@@ -14,7 +30,7 @@ func (h handler) processCreateRequest(c *gin.Context) (createReq, models.Scope,
 	var req createReq
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.l.Warnf(ctx, "user.http.processCreateRequest.ShouldBindJSON: %v", err)
-		return createReq{}, models.Scope{}, errWrongBody
+		return createReq{}, models.Scope{}, pkgErrors.BindingError(err, errWrongBody)
 	}
 
 	// Validate dữ liệu
@@ -23,8 +39,12 @@ func (h handler) processCreateRequest(c *gin.Context) (createReq, models.Scope,
 		return createReq{}, models.Scope{}, err
 	}
 
-	// Tạo scope trống
-	sc := models.Scope{}
+	// Lấy scope của user đang đăng nhập (dùng để kiểm tra hierarchy của user mới)
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "user.http.processCreateRequest.processScope: %v", err)
+		return createReq{}, models.Scope{}, err
+	}
 
 	return req, sc, nil
 }
@@ -37,17 +57,298 @@ func (h handler) processUpdateRequest(c *gin.Context) (updateReq, models.Scope,
 	var req updateReq
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.l.Warnf(ctx, "user.http.processUpdateRequest.ShouldBindJSON: %v", err)
-		return updateReq{}, models.Scope{}, errWrongBody
+		return updateReq{}, models.Scope{}, pkgErrors.BindingError(err, errWrongBody)
+	}
+
+	// Lấy scope của user đang đăng nhập
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "user.http.processUpdateRequest.processScope: %v", err)
+		return updateReq{}, models.Scope{}, err
+	}
+
+	// Đọc version client đã đọc từ If-Match
+	version, err := h.processVersion(c)
+	if err != nil {
+		h.l.Warnf(ctx, "user.http.processUpdateRequest.processVersion: %v", err)
+		return updateReq{}, models.Scope{}, err
+	}
+	req.version = version
+
+	return req, sc, nil
+}
+
+// processPatchRequest xử lý và validate merge patch của user
+func (h handler) processPatchRequest(c *gin.Context) (patchReq, models.Scope, error) {
+	ctx := c.Request.Context()
+
+	// Parse merge patch thành patchReq struct
+	var req patchReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.l.Warnf(ctx, "user.http.processPatchRequest.ShouldBindJSON: %v", err)
+		return patchReq{}, models.Scope{}, pkgErrors.BindingError(err, errWrongBody)
 	}
 
 	// Validate dữ liệu
 	if err := req.validate(); err != nil {
-		h.l.Warnf(ctx, "user.http.processUpdateRequest.validate: %v", err)
-		return updateReq{}, models.Scope{}, err
+		h.l.Warnf(ctx, "user.http.processPatchRequest.validate: %v", err)
+		return patchReq{}, models.Scope{}, err
+	}
+
+	// Lấy scope của user đang đăng nhập
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "user.http.processPatchRequest.processScope: %v", err)
+		return patchReq{}, models.Scope{}, err
+	}
+
+	// Đọc version client đã đọc từ If-Match
+	version, err := h.processVersion(c)
+	if err != nil {
+		h.l.Warnf(ctx, "user.http.processPatchRequest.processVersion: %v", err)
+		return patchReq{}, models.Scope{}, err
+	}
+	req.version = version
+
+	return req, sc, nil
+}
+
+// processListRequest xử lý và validate query lấy danh sách user
+func (h handler) processListRequest(c *gin.Context) (listReq, models.Scope, error) {
+	ctx := c.Request.Context()
+
+	var req listReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.l.Warnf(ctx, "user.http.processListRequest.ShouldBindQuery: %v", err)
+		return listReq{}, models.Scope{}, errWrongQuery
+	}
+
+	if err := req.validate(); err != nil {
+		h.l.Warnf(ctx, "user.http.processListRequest.validate: %v", err)
+		return listReq{}, models.Scope{}, err
+	}
+
+	// Danh sách bị giới hạn theo scope của người gọi
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "user.http.processListRequest.processScope: %v", err)
+		return listReq{}, models.Scope{}, err
+	}
+
+	return req, sc, nil
+}
+
+// processScope lấy scope của user đang đăng nhập từ JWT payload
+func (h handler) processScope(c *gin.Context) (models.Scope, error) {
+	payload, ok := jwt.GetPayloadFromContext(c.Request.Context())
+	if !ok {
+		return models.Scope{}, errUnauthorized
+	}
+
+	return jwt.NewScope(payload), nil
+}
+
+// processImportRequest đọc file CSV (multipart field "file" hoặc body text/csv) thành các dòng import
+func (h handler) processImportRequest(c *gin.Context) (user.ImportInput, models.Scope, error) {
+	ctx := c.Request.Context()
+
+	// dry_run=true → chỉ validate, không insert
+	var input user.ImportInput
+	if v := c.Query("dry_run"); v != "" {
+		dryRun, err := strconv.ParseBool(v)
+		if err != nil {
+			h.l.Warnf(ctx, "user.http.processImportRequest.ParseBool: %v", err)
+			return user.ImportInput{}, models.Scope{}, errWrongQuery
+		}
+		input.DryRun = dryRun
 	}
 
-	// Tạo scope trống
-	sc := models.Scope{}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, importMaxBytes)
+
+	var src io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			h.l.Warnf(ctx, "user.http.processImportRequest.FormFile: %v", err)
+			return user.ImportInput{}, models.Scope{}, importReadError(err)
+		}
+		f, err := fh.Open()
+		if err != nil {
+			h.l.Warnf(ctx, "user.http.processImportRequest.Open: %v", err)
+			return user.ImportInput{}, models.Scope{}, errInvalidCSV
+		}
+		defer f.Close()
+		src = f
+	}
+
+	rows, err := parseImportCSV(src)
+	if err != nil {
+		h.l.Warnf(ctx, "user.http.processImportRequest.parseImportCSV: %v", err)
+		return user.ImportInput{}, models.Scope{}, importReadError(err)
+	}
+	input.Rows = rows
+
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "user.http.processImportRequest.processScope: %v", err)
+		return user.ImportInput{}, models.Scope{}, err
+	}
+
+	return input, sc, nil
+}
+
+// processGetImportJobRequest lấy job ID từ URL và scope của người gọi
+func (h handler) processGetImportJobRequest(c *gin.Context) (primitive.ObjectID, models.Scope, error) {
+	ctx := c.Request.Context()
+
+	id, err := primitive.ObjectIDFromHex(c.Param("job_id"))
+	if err != nil {
+		h.l.Warnf(ctx, "user.http.processGetImportJobRequest.ObjectIDFromHex: %v", err)
+		return primitive.NilObjectID, models.Scope{}, errInvalidJobID
+	}
+
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "user.http.processGetImportJobRequest.processScope: %v", err)
+		return primitive.NilObjectID, models.Scope{}, err
+	}
+
+	return id, sc, nil
+}
+
+// importColumns map tên cột trong header (đã chuẩn hóa) sang trường của ImportRow
+var importColumns = map[string]string{
+	"username":        "username",
+	"email":           "email",
+	"password":        "password",
+	"role":            "role",
+	"branch":          "branch",
+	"branch_name":     "branch",
+	"branch_id":       "branch",
+	"department":      "department",
+	"department_code": "department",
+	"department_name": "department",
+	"department_id":   "department",
+}
+
+// parseImportCSV đọc header rồi chuyển từng dòng thành ImportRow, cột không biết sẽ bị bỏ qua
+func parseImportCSV(src io.Reader) ([]user.ImportRow, error) {
+	r := csv.NewReader(src)
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1 // Dòng thiếu cột cuối vẫn đọc được, cột thiếu coi như rỗng
+
+	header, err := r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// Chuẩn hóa header: bỏ BOM, chữ thường, khoảng trắng → "_"
+	index := make(map[string]int, len(header))
+	for i, col := range header {
+		col = strings.TrimPrefix(col, "\ufeff")
+		col = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(col)), " ", "_")
+		if field, ok := importColumns[col]; ok {
+			if _, dup := index[field]; !dup {
+				index[field] = i
+			}
+		}
+	}
+	_, hasBranch := index["branch"]
+	_, hasDept := index["department"]
+	if _, ok := index["username"]; !ok {
+		return nil, errMissingColumns
+	}
+	if _, ok := index["email"]; !ok || (!hasBranch && !hasDept) {
+		return nil, errMissingColumns
+	}
+
+	var rows []user.ImportRow
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := r.FieldPos(0)
+		get := func(field string) string {
+			if i, ok := index[field]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		rows = append(rows, user.ImportRow{
+			Line:       line,
+			Username:   get("username"),
+			Email:      get("email"),
+			Password:   get("password"),
+			Role:       get("role"),
+			Branch:     get("branch"),
+			Department: get("department"),
+		})
+	}
+
+	return rows, nil
+}
+
+// importReadError chuyển lỗi khi đọc file sang lỗi HTTP tương ứng
+func importReadError(err error) error {
+	var maxErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxErr):
+		return errImportTooLarge
+	case errors.Is(err, errMissingColumns):
+		return errMissingColumns
+	default:
+		return errInvalidCSV
+	}
+}
+
+// processVersion đọc version client đã đọc từ header If-Match, bắt buộc khi sửa hoặc xóa
+func (h handler) processVersion(c *gin.Context) (int, error) {
+	version, err := util.GetIfMatchVersion(c)
+	if errors.Is(err, util.ErrIfMatchMissing) {
+		return 0, errPreconditionRequired
+	}
+	if err != nil {
+		return 0, errVersionMismatch
+	}
+	return version, nil
+}
+
+// processHistoryRequest xử lý query lấy lịch sử thay đổi của user
+func (h handler) processHistoryRequest(c *gin.Context) (historyReq, models.Scope, error) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Parse query thành historyReq struct
+	var req historyReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.l.Warnf(ctx, "user.http.processHistoryRequest.ShouldBindQuery: %v", err)
+		return historyReq{}, models.Scope{}, errWrongQuery
+	}
+
+	// Bước 2: Lấy scope của user đang đăng nhập
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "user.http.processHistoryRequest.processScope: %v", err)
+		return historyReq{}, models.Scope{}, err
+	}
 
 	return req, sc, nil
 }
+
+// processAsOf đọc thời điểm cần xem từ query as_of theo định dạng "2006-01-02 15:04:05",
+// không có as_of thì trả về nil (xem trạng thái hiện tại)
+func (h handler) processAsOf(c *gin.Context) (*time.Time, error) {
+	asOf, err := util.StrToDateTimePtr(c.Query("as_of"))
+	if err != nil {
+		h.l.Warnf(c.Request.Context(), "user.http.processAsOf.StrToDateTimePtr: %v", err)
+		return nil, errWrongQuery
+	}
+	return asOf, nil
+}
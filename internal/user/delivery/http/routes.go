@@ -1,12 +1,56 @@
 package http
 
-import "github.com/gin-gonic/gin"
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"thuchanhgolang/pkg/openapi"
+	"thuchanhgolang/pkg/patch"
+)
 
 // MapRoutes map các routes cho user
 func MapRoutes(g *gin.RouterGroup, h Handler) {
 	hdl := h.(*handler)
 	g.POST("", hdl.create)
+	g.POST("/import", hdl.importUsers)
+	g.GET("/import/:job_id", hdl.getImportJob)
+	g.GET("", hdl.list)
 	g.GET("/:id", hdl.getByID)
+	g.GET("/:id/history", hdl.history)
 	g.PUT("/:id", hdl.update)
+	g.PATCH("/:id", hdl.patch)
 	g.DELETE("/:id", hdl.delete)
 }
+
+// importFileReq là body multipart của import, processImportRequest đọc field "file"
+type importFileReq struct {
+	File openapi.File `json:"file" binding:"required"` // File CSV
+}
+
+// Spec mô tả các route của MapRoutes cho OpenAPI, sửa route thì sửa cả ở đây
+func Spec() []openapi.Route {
+	return []openapi.Route{
+		{Method: http.MethodPost, Path: "", Summary: "Tạo user mới", Body: createReq{}, Response: detailResp{}, ETag: true},
+		{
+			Method: http.MethodPost, Path: "/import", Summary: "Import user từ file CSV, file lớn chạy nền và trả về ID job",
+			Params:   []openapi.Parameter{openapi.QueryParam("dry_run", "true để chỉ validate, không tạo user")},
+			Body:     openapi.Bodies{openapi.ContentCSV: "", openapi.ContentMultipart: importFileReq{}},
+			Response: importJobResp{},
+		},
+		{Method: http.MethodGet, Path: "/import/:job_id", Summary: "Xem tiến độ của job import", Response: importJobResp{}},
+		{Method: http.MethodGet, Path: "", Summary: "Lấy danh sách user", Query: listReq{}, Response: listResp{}},
+		{
+			Method: http.MethodGet, Path: "/:id", Summary: "Xem chi tiết user theo ID",
+			Params:   []openapi.Parameter{openapi.QueryParam("as_of", "Thời điểm cần xem, định dạng 2006-01-02 15:04:05")},
+			Response: detailResp{}, ETag: true,
+		},
+		{Method: http.MethodGet, Path: "/:id/history", Summary: "Xem lịch sử thay đổi, mới nhất đứng trước", Query: historyReq{}, Response: historyResp{}},
+		{Method: http.MethodPut, Path: "/:id", Summary: "Cập nhật user theo ID", IfMatch: true, Body: updateReq{}, Response: detailResp{}, ETag: true},
+		{
+			Method: http.MethodPatch, Path: "/:id", Summary: "Cập nhật một phần user theo JSON Merge Patch, department_id null để rời department",
+			IfMatch: true, Body: patchReq{}, BodyTypes: []string{patch.ContentType, openapi.ContentJSON}, Response: detailResp{}, ETag: true,
+		},
+		{Method: http.MethodDelete, Path: "/:id", Summary: "Xóa user theo ID", IfMatch: true, Response: gin.H{}},
+	}
+}
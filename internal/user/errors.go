@@ -1,7 +1,28 @@
 package user
 
-import "errors"
+import pkgErrors "thuchanhgolang/pkg/errors"
 
 var (
-	ErrUserInUse = errors.New("user is being used")
+	ErrUserInUse = pkgErrors.Conflict("user is being used")
+
+	// ErrUserNotFound trả về khi không tìm thấy user
+	ErrUserNotFound = pkgErrors.NotFound("user not found")
+
+	// ErrUserOutOfScope trả về khi user cần thao tác nằm ngoài scope của người gọi
+	ErrUserOutOfScope = pkgErrors.Forbidden("user is outside of your scope")
+
+	// ErrUsernameExists trả về khi username đã được user khác sử dụng
+	ErrUsernameExists = pkgErrors.Conflict("username already exists")
+	// ErrEmailExists trả về khi email đã được user khác trong cùng shop sử dụng
+	ErrEmailExists = pkgErrors.Conflict("email already exists in this shop")
+
+	// ErrImportEmpty trả về khi file import không có dòng dữ liệu nào
+	ErrImportEmpty = pkgErrors.Invalid("import file has no data rows")
+	// ErrImportTooLarge trả về khi file import vượt quá số dòng cho phép
+	ErrImportTooLarge = pkgErrors.Invalid("import file has too many rows")
+	// ErrImportJobNotFound trả về khi job không tồn tại hoặc không phải của người gọi
+	ErrImportJobNotFound = pkgErrors.NotFound("import job not found")
+
+	// ErrVersionMismatch trả về khi user đã bị thay đổi sau lần đọc của client (If-Match không khớp version)
+	ErrVersionMismatch = pkgErrors.PreconditionFailed("user has been modified by another request")
 )
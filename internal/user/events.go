@@ -0,0 +1,44 @@
+package user
+
+import "thuchanhgolang/internal/models"
+
+const (
+	// AggregateType là loại aggregate của các event user
+	AggregateType = "user"
+
+	// EventUserCreated được phát khi user được tạo (đăng ký, tạo mới, import)
+	EventUserCreated = "user.created"
+	// EventUserUpdated được phát khi thông tin user thay đổi
+	EventUserUpdated = "user.updated"
+	// EventUserDeleted được phát khi user bị xóa
+	EventUserDeleted = "user.deleted"
+	// EventUserMoved được phát khi user chuyển sang branch / department khác
+	EventUserMoved = "user.moved"
+	// EventUserRoleChanged được phát khi role của user thay đổi
+	EventUserRoleChanged = "user.role_changed"
+	// EventUserDeactivated được phát khi user bị vô hiệu hóa (xóa dây chuyền đơn vị của user)
+	EventUserDeactivated = "user.deactivated"
+)
+
+// Payload là nội dung event khi user được tạo hoặc cập nhật, không chứa mật khẩu
+func Payload(u models.User) map[string]interface{} {
+	payload := HierarchyPayload(u.Hierarchy())
+	payload["username"] = u.Username
+	payload["email"] = u.Email
+	payload["role"] = string(u.Role)
+	payload["version"] = u.Version
+	return payload
+}
+
+// HierarchyPayload là vị trí của user trong cây tổ chức, department_id chỉ có khi user thuộc department
+func HierarchyPayload(h models.Hierarchy) map[string]interface{} {
+	payload := map[string]interface{}{
+		"shop_id":   h.ShopID.Hex(),
+		"region_id": h.RegionID.Hex(),
+		"branch_id": h.BranchID.Hex(),
+	}
+	if h.DepartmentID != nil && !h.DepartmentID.IsZero() {
+		payload["department_id"] = h.DepartmentID.Hex()
+	}
+	return payload
+}
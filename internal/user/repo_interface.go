@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/paginator"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -21,12 +22,42 @@ type Repository interface {
 	// GetByID lấy user theo ID
 	GetByID(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.User, error)
 
+	// List lấy danh sách user theo bộ lọc, có phân trang
+	List(ctx context.Context, sc models.Scope, opts ListOptions) ([]models.User, paginator.Paginator, error)
+
 	// GetByUsername lấy user theo username
 	GetByUsername(ctx context.Context, username string) (models.User, error)
 
 	// Update cập nhật thông tin user
 	Update(ctx context.Context, sc models.Scope, opts UpdateOptions) (models.User, error)
 
-	// Delete xóa user
-	Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error
+	// Delete xóa user, version là version client đã đọc (0 → không kiểm tra)
+	Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error
+
+	// CreateMany tạo nhiều user cùng lúc (dùng cho import)
+	CreateMany(ctx context.Context, sc models.Scope, opts []CreateOptions) ([]models.User, error)
+
+	// ExistingUsernames trả về các username trong danh sách đã có trong database
+	ExistingUsernames(ctx context.Context, usernames []string) ([]string, error)
+
+	// FindBranchesByName lấy các branch có tên đúng bằng name
+	FindBranchesByName(ctx context.Context, name string) ([]models.Branch, error)
+
+	// FindDepartmentsByName lấy các department có tên đúng bằng name
+	FindDepartmentsByName(ctx context.Context, name string) ([]models.Department, error)
+
+	// CreateImportJob lưu job import chạy nền
+	CreateImportJob(ctx context.Context, job models.ImportJob) (models.ImportJob, error)
+
+	// UpdateImportJob ghi lại trạng thái và kết quả của job
+	UpdateImportJob(ctx context.Context, job models.ImportJob) error
+
+	// UpdateImportJobProgress cập nhật số dòng đã xử lý của job
+	UpdateImportJobProgress(ctx context.Context, id primitive.ObjectID, processed int) error
+
+	// GetImportJob lấy job import theo ID
+	GetImportJob(ctx context.Context, id primitive.ObjectID) (models.ImportJob, error)
+
+	// ClearImportJobPasswords xóa mật khẩu tạm khỏi job sau khi đã trả cho người import
+	ClearImportJobPasswords(ctx context.Context, id primitive.ObjectID) error
 }
@@ -1,6 +1,12 @@
 package user
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/paginator"
+	"thuchanhgolang/pkg/patch"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // RegisterOptions là options để đăng ký user mới (chỉ thông tin cơ bản)
 type RegisterOptions struct {
@@ -14,6 +20,7 @@ type CreateOptions struct {
 	Username     string
 	Password     string
 	Email        string
+	Role         models.Role
 	ShopID       primitive.ObjectID
 	RegionID     primitive.ObjectID
 	BranchID     primitive.ObjectID
@@ -26,8 +33,19 @@ type UpdateOptions struct {
 	Username     *string
 	Password     *string
 	Email        *string
+	ShopID       *primitive.ObjectID
+	RegionID     *primitive.ObjectID
+	BranchID     *primitive.ObjectID
+	DepartmentID patch.Field[primitive.ObjectID] // null → xóa department của user
+	Version      int                             // Version client đã đọc (If-Match), 0 → không kiểm tra
+}
+
+// ListOptions là bộ lọc và phân trang khi lấy danh sách user, đã được giới hạn theo scope (nil = không lọc)
+type ListOptions struct {
 	ShopID       *primitive.ObjectID
 	RegionID     *primitive.ObjectID
 	BranchID     *primitive.ObjectID
 	DepartmentID *primitive.ObjectID
+	Filter       models.TimeFilter        // Lọc theo thời gian tạo, cập nhật
+	Pagin        paginator.PaginatorQuery // Trang cần lấy (đã Adjust)
 }
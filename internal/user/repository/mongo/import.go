@@ -0,0 +1,203 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/user"
+	"thuchanhgolang/pkg/mongo"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	branchCollection     = "branches"
+	departmentCollection = "departments"
+	importJobCollection  = "import_jobs"
+)
+
+// getImportJobCollection lấy collection import_jobs từ database
+func (repo implRepository) getImportJobCollection() mongo.Collection {
+	return repo.db.Collection(importJobCollection)
+}
+
+// CreateMany tạo nhiều user trong MongoDB bằng một lệnh InsertMany
+func (repo implRepository) CreateMany(ctx context.Context, sc models.Scope, opts []user.CreateOptions) ([]models.User, error) {
+	if len(opts) == 0 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	users := make([]models.User, 0, len(opts))
+	docs := make([]interface{}, 0, len(opts))
+	for _, o := range opts {
+		u := models.User{
+			ID:           repo.db.NewObjectID(),
+			Username:     o.Username,
+			PassWord:     o.Password,
+			Email:        o.Email,
+			Role:         o.Role,
+			ShopID:       o.ShopID,
+			RegionID:     o.RegionID,
+			BranchID:     o.BranchID,
+			DepartmentID: o.DepartmentID,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+			CreatedBy:    sc.UserID,
+			UpdatedBy:    sc.UserID,
+			Version:      1,
+		}
+		users = append(users, u)
+		docs = append(docs, u)
+	}
+
+	if _, err := repo.getUserCollection().InsertMany(ctx, docs); err != nil {
+		repo.l.Errorf(ctx, "user.mongo.CreateMany.InsertMany: %v", err)
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// ExistingUsernames trả về các username trong danh sách đã tồn tại
+func (repo implRepository) ExistingUsernames(ctx context.Context, usernames []string) ([]string, error) {
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+
+	filter := bson.M{"username": bson.M{"$in": usernames}}
+	findOpts := options.Find().SetProjection(bson.M{"username": 1})
+	cursor, err := repo.getUserCollection().Find(ctx, filter, findOpts)
+	if err != nil {
+		repo.l.Errorf(ctx, "user.mongo.ExistingUsernames.Find: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		repo.l.Errorf(ctx, "user.mongo.ExistingUsernames.All: %v", err)
+		return nil, err
+	}
+
+	existing := make([]string, 0, len(users))
+	for _, u := range users {
+		existing = append(existing, u.Username)
+	}
+	return existing, nil
+}
+
+// FindBranchesByName lấy các branch có tên đúng bằng name
+func (repo implRepository) FindBranchesByName(ctx context.Context, name string) ([]models.Branch, error) {
+	cursor, err := repo.db.Collection(branchCollection).Find(ctx, bson.M{"name": name})
+	if err != nil {
+		repo.l.Errorf(ctx, "user.mongo.FindBranchesByName.Find: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var branches []models.Branch
+	if err := cursor.All(ctx, &branches); err != nil {
+		repo.l.Errorf(ctx, "user.mongo.FindBranchesByName.All: %v", err)
+		return nil, err
+	}
+	return branches, nil
+}
+
+// FindDepartmentsByName lấy các department có tên đúng bằng name
+func (repo implRepository) FindDepartmentsByName(ctx context.Context, name string) ([]models.Department, error) {
+	cursor, err := repo.db.Collection(departmentCollection).Find(ctx, bson.M{"name": name})
+	if err != nil {
+		repo.l.Errorf(ctx, "user.mongo.FindDepartmentsByName.Find: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var departments []models.Department
+	if err := cursor.All(ctx, &departments); err != nil {
+		repo.l.Errorf(ctx, "user.mongo.FindDepartmentsByName.All: %v", err)
+		return nil, err
+	}
+	return departments, nil
+}
+
+// CreateImportJob lưu job import mới
+func (repo implRepository) CreateImportJob(ctx context.Context, job models.ImportJob) (models.ImportJob, error) {
+	now := time.Now()
+	job.ID = repo.db.NewObjectID()
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	if _, err := repo.getImportJobCollection().InsertOne(ctx, job); err != nil {
+		repo.l.Errorf(ctx, "user.mongo.CreateImportJob.InsertOne: %v", err)
+		return models.ImportJob{}, err
+	}
+
+	return job, nil
+}
+
+// UpdateImportJob ghi lại trạng thái và kết quả của job
+func (repo implRepository) UpdateImportJob(ctx context.Context, job models.ImportJob) error {
+	update := bson.M{
+		"$set": bson.M{
+			"status":      job.Status,
+			"processed":   job.Processed,
+			"created":     job.Created,
+			"errors":      job.Errors,
+			"users":       job.Users,
+			"error":       job.Error,
+			"finished_at": job.FinishedAt,
+			"updated_at":  time.Now(),
+		},
+	}
+
+	if _, err := repo.getImportJobCollection().UpdateOne(ctx, bson.M{"_id": job.ID}, update); err != nil {
+		repo.l.Errorf(ctx, "user.mongo.UpdateImportJob.UpdateOne: %v", err)
+		return err
+	}
+	return nil
+}
+
+// UpdateImportJobProgress cập nhật số dòng đã xử lý của job
+func (repo implRepository) UpdateImportJobProgress(ctx context.Context, id primitive.ObjectID, processed int) error {
+	update := bson.M{
+		"$set": bson.M{
+			"status":     models.ImportJobRunning,
+			"processed":  processed,
+			"updated_at": time.Now(),
+		},
+	}
+
+	if _, err := repo.getImportJobCollection().UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		repo.l.Errorf(ctx, "user.mongo.UpdateImportJobProgress.UpdateOne: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetImportJob lấy job import theo ID
+func (repo implRepository) GetImportJob(ctx context.Context, id primitive.ObjectID) (models.ImportJob, error) {
+	var job models.ImportJob
+	if err := repo.getImportJobCollection().FindOne(ctx, bson.M{"_id": id}).Decode(&job); err != nil {
+		repo.l.Errorf(ctx, "user.mongo.GetImportJob.FindOne: %v", err)
+		return models.ImportJob{}, err
+	}
+	return job, nil
+}
+
+// ClearImportJobPasswords xóa mật khẩu tạm của mọi user trong job
+func (repo implRepository) ClearImportJobPasswords(ctx context.Context, id primitive.ObjectID) error {
+	update := bson.M{
+		"$unset": bson.M{"users.$[].temporary_password": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+	}
+
+	if _, err := repo.getImportJobCollection().UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		repo.l.Errorf(ctx, "user.mongo.ClearImportJobPasswords.UpdateOne: %v", err)
+		return err
+	}
+	return nil
+}
@@ -2,13 +2,18 @@ package mongo
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"thuchanhgolang/internal/history"
 	"thuchanhgolang/internal/models"
 	"thuchanhgolang/internal/user"
 	"thuchanhgolang/pkg/mongo"
+	"thuchanhgolang/pkg/paginator"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const (
@@ -23,13 +28,17 @@ func (repo implRepository) getUserCollection() mongo.Collection {
 // Register tạo user mới trong MongoDB với thông tin cơ bản
 func (repo implRepository) Register(ctx context.Context, opts user.RegisterOptions) (models.User, error) {
 	col := repo.getUserCollection()
+	now := time.Now()
 
 	// Tạo user object mới (chỉ thông tin cơ bản)
 	newUser := models.User{
-		ID:       repo.db.NewObjectID(),
-		Username: opts.Username,
-		PassWord: opts.Password, // Password đã được hash
-		Email:    opts.Email,
+		ID:        repo.db.NewObjectID(),
+		Username:  opts.Username,
+		PassWord:  opts.Password, // Password đã được hash
+		Email:     opts.Email,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
 	}
 
 	// Lưu vào database
@@ -61,6 +70,7 @@ func (repo implRepository) GetByUsername(ctx context.Context, username string) (
 // Create tạo user mới trong MongoDB
 func (repo implRepository) Create(ctx context.Context, sc models.Scope, opts user.CreateOptions) (models.User, error) {
 	col := repo.getUserCollection()
+	now := time.Now()
 
 	// Tạo user object mới
 	newUser := models.User{
@@ -68,10 +78,16 @@ func (repo implRepository) Create(ctx context.Context, sc models.Scope, opts use
 		Username:     opts.Username,
 		PassWord:     opts.Password,
 		Email:        opts.Email,
+		Role:         opts.Role,
 		ShopID:       opts.ShopID,
 		RegionID:     opts.RegionID,
 		BranchID:     opts.BranchID,
 		DepartmentID: opts.DepartmentID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		CreatedBy:    sc.UserID,
+		UpdatedBy:    sc.UserID,
+		Version:      1,
 	}
 
 	// Lưu vào database
@@ -100,58 +116,90 @@ func (repo implRepository) GetByID(ctx context.Context, sc models.Scope, id prim
 	return user, nil
 }
 
-// Update cập nhật thông tin user trong MongoDB
-func (repo implRepository) Update(ctx context.Context, sc models.Scope, opts user.UpdateOptions) (models.User, error) {
+// List lấy danh sách user theo bộ lọc, user tạo sau đứng trước
+func (repo implRepository) List(ctx context.Context, sc models.Scope, opts user.ListOptions) ([]models.User, paginator.Paginator, error) {
 	col := repo.getUserCollection()
 
-	// Tạo update document
-	update := bson.M{}
-	unset := bson.M{} // Dùng để xóa fields (set về null)
-
-	if opts.Username != nil {
-		update["username"] = *opts.Username
-	}
-	if opts.Password != nil {
-		update["password"] = *opts.Password
-	}
-	if opts.Email != nil {
-		update["email"] = *opts.Email
-	}
-	if opts.ShopID != nil {
-		update["shop_id"] = *opts.ShopID
-	}
-	if opts.RegionID != nil {
-		update["region_id"] = *opts.RegionID
+	// Bước 1: Đếm tổng số user khớp bộ lọc
+	filter := repo.buildListQuery(opts)
+	total, err := col.CountDocuments(ctx, filter)
+	if err != nil {
+		repo.l.Errorf(ctx, "user.mongo.List.CountDocuments: %v", err)
+		return nil, paginator.Paginator{}, err
 	}
-	if opts.BranchID != nil {
-		update["branch_id"] = *opts.BranchID
+
+	// Bước 2: Lấy user của trang hiện tại
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetSkip(opts.Pagin.Offset()).
+		SetLimit(opts.Pagin.Limit)
+	cursor, err := col.Find(ctx, filter, findOpts)
+	if err != nil {
+		repo.l.Errorf(ctx, "user.mongo.List.Find: %v", err)
+		return nil, paginator.Paginator{}, err
 	}
-	if opts.DepartmentID != nil {
-		// Nếu là NilObjectID → xóa field (unset)
-		if *opts.DepartmentID == primitive.NilObjectID {
-			unset["department_id"] = ""
-		} else {
-			update["department_id"] = *opts.DepartmentID
-		}
+
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		repo.l.Errorf(ctx, "user.mongo.List.All: %v", err)
+		return nil, paginator.Paginator{}, err
 	}
 
+	return users, paginator.Paginator{
+		Total:       total,
+		Count:       int64(len(users)),
+		PerPage:     opts.Pagin.Limit,
+		CurrentPage: opts.Pagin.Page,
+	}, nil
+}
+
+// Update cập nhật thông tin user trong MongoDB
+func (repo implRepository) Update(ctx context.Context, sc models.Scope, opts user.UpdateOptions) (models.User, error) {
+	col := repo.getUserCollection()
+
+	// Tạo update document, field nil thì giữ nguyên, DepartmentID null thì xóa field
+	update := mongo.NewUpdate()
+	mongo.SetPtr(update, "username", opts.Username)
+	mongo.SetPtr(update, "password", opts.Password)
+	mongo.SetPtr(update, "email", opts.Email)
+	mongo.SetPtr(update, "shop_id", opts.ShopID)
+	mongo.SetPtr(update, "region_id", opts.RegionID)
+	mongo.SetPtr(update, "branch_id", opts.BranchID)
+	mongo.SetPatch(update, "department_id", opts.DepartmentID)
+
 	// Nếu không có gì để update
-	if len(update) == 0 && len(unset) == 0 {
-		return repo.GetByID(ctx, sc, opts.ID)
+	if !update.HasChanges() {
+		current, err := repo.GetByID(ctx, sc, opts.ID)
+		if err != nil {
+			return models.User{}, err
+		}
+		if opts.Version > 0 && current.Version != opts.Version {
+			return models.User{}, user.ErrVersionMismatch
+		}
+		return current, nil
 	}
 
-	// Update user
+	// Ghi lại thời điểm và người sửa
+	now := time.Now()
+	update.Set("updated_at", now).Set("updated_by", sc.UserID).Inc("version", 1)
+
+	// Update user, chỉ khi version khớp với version client đã đọc, đồng thời tăng version.
+	// Lấy về user trước khi update để lưu vào lịch sử
 	filter := bson.M{"_id": opts.ID}
-	updateDoc := bson.M{}
-	if len(update) > 0 {
-		updateDoc["$set"] = update
+	if opts.Version > 0 {
+		filter["version"] = opts.Version
 	}
-	if len(unset) > 0 {
-		updateDoc["$unset"] = unset
+	var prior bson.Raw
+	err := col.FindOneAndUpdate(ctx, filter, update.Document(), options.FindOneAndUpdate().SetProjection(history.UserProjection)).Decode(&prior)
+	if errors.Is(err, mongo.ErrNoDocuments) && opts.Version > 0 {
+		return models.User{}, repo.versionError(ctx, sc, opts.ID)
 	}
-	_, err := col.UpdateOne(ctx, filter, updateDoc)
 	if err != nil {
-		repo.l.Errorf(ctx, "user.mongo.Update.UpdateOne: %v", err)
+		repo.l.Errorf(ctx, "user.mongo.Update.FindOneAndUpdate: %v", err)
+		return models.User{}, err
+	}
+	if err := history.RecordRevisions(ctx, repo.db, history.EntityUser, models.RevisionUpdate, sc.UserID, now, prior); err != nil {
+		repo.l.Errorf(ctx, "user.mongo.Update.history.RecordRevisions: %v", err)
 		return models.User{}, err
 	}
 
@@ -160,16 +208,59 @@ func (repo implRepository) Update(ctx context.Context, sc models.Scope, opts use
 }
 
 // Delete xóa user khỏi MongoDB
-func (repo implRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+func (repo implRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
 	col := repo.getUserCollection()
 
-	// Xóa user theo ID
+	// Xóa user theo ID, lấy về user đã xóa để lưu vào lịch sử
 	filter := bson.M{"_id": id}
-	_, err := col.DeleteOne(ctx, filter)
+	if version > 0 {
+		filter["version"] = version
+	}
+	var prior bson.Raw
+	err := col.FindOneAndDelete(ctx, filter, options.FindOneAndDelete().SetProjection(history.UserProjection)).Decode(&prior)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if version > 0 {
+			return repo.versionError(ctx, sc, id)
+		}
+		return nil
+	}
 	if err != nil {
-		repo.l.Errorf(ctx, "user.mongo.Delete.DeleteOne: %v", err)
+		repo.l.Errorf(ctx, "user.mongo.Delete.FindOneAndDelete: %v", err)
 		return err
 	}
 
+	if err := history.RecordRevisions(ctx, repo.db, history.EntityUser, models.RevisionDelete, sc.UserID, time.Now(), prior); err != nil {
+		repo.l.Errorf(ctx, "user.mongo.Delete.history.RecordRevisions: %v", err)
+		return err
+	}
 	return nil
 }
+
+// versionError xác định lý do update/delete theo version không khớp document nào:
+// document không còn thì trả lỗi của GetByID, còn tồn tại thì version đã bị thay đổi
+func (repo implRepository) versionError(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+	if _, err := repo.GetByID(ctx, sc, id); err != nil {
+		return err
+	}
+	return user.ErrVersionMismatch
+}
+
+// buildListQuery tạo filter lấy danh sách user từ bộ lọc
+func (repo implRepository) buildListQuery(opts user.ListOptions) bson.M {
+	filter := bson.M{}
+	if opts.ShopID != nil {
+		filter["shop_id"] = *opts.ShopID
+	}
+	if opts.RegionID != nil {
+		filter["region_id"] = *opts.RegionID
+	}
+	if opts.BranchID != nil {
+		filter["branch_id"] = *opts.BranchID
+	}
+	if opts.DepartmentID != nil {
+		filter["department_id"] = *opts.DepartmentID
+	}
+	mongo.BuildQueryWithTimeRange(filter, "created_at", opts.Filter.CreatedFrom, opts.Filter.CreatedTo)
+	mongo.BuildQueryWithTimeRange(filter, "updated_at", opts.Filter.UpdatedFrom, opts.Filter.UpdatedTo)
+	return filter
+}
@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"thuchanhgolang/internal/models"
 
@@ -21,9 +22,25 @@ type Usecase interface {
 	// GetByID lấy thông tin user theo ID
 	GetByID(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.User, error)
 
+	// List lấy danh sách user trong scope của người gọi, có phân trang
+	List(ctx context.Context, sc models.Scope, input ListInput) (ListOutput, error)
+
 	// Update cập nhật thông tin user
 	Update(ctx context.Context, sc models.Scope, input UpdateInput) (models.User, error)
 
-	// Delete xóa user
-	Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error
+	// Delete xóa user, version là version client đã đọc (0 → không kiểm tra)
+	Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error
+
+	// Import tạo nhiều user từ các dòng CSV.
+	// File nhỏ được xử lý ngay, file lớn trả về job ở trạng thái pending để client theo dõi tiến độ.
+	Import(ctx context.Context, sc models.Scope, input ImportInput) (models.ImportJob, error)
+
+	// GetImportJob lấy tiến độ / kết quả của job import
+	GetImportJob(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.ImportJob, error)
+
+	// GetAsOf lấy trạng thái của user tại thời điểm asOf
+	GetAsOf(ctx context.Context, sc models.Scope, id primitive.ObjectID, asOf time.Time) (models.User, error)
+
+	// History lấy lịch sử thay đổi của user, mới nhất đứng trước
+	History(ctx context.Context, sc models.Scope, input HistoryInput) (HistoryOutput, error)
 }
@@ -1,6 +1,14 @@
 package user
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/paginator"
+	"thuchanhgolang/pkg/patch"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // RegisterInput là input để đăng ký user mới (chỉ cần thông tin cơ bản)
 type RegisterInput struct {
@@ -29,5 +37,57 @@ type UpdateInput struct {
 	ShopID       *primitive.ObjectID
 	RegionID     *primitive.ObjectID
 	BranchID     *primitive.ObjectID
-	DepartmentID *primitive.ObjectID
+	DepartmentID patch.Field[primitive.ObjectID] // null → rời department, ở lại branch hiện tại (hoặc branch_id gửi kèm)
+	Version      int                             // Version client đã đọc (If-Match), 0 → không kiểm tra
+}
+
+// ListInput là input để lấy danh sách user, bộ lọc chỉ được thu hẹp scope của người gọi
+type ListInput struct {
+	BranchID     *primitive.ObjectID      // Chỉ lấy user thuộc branch này (optional)
+	DepartmentID *primitive.ObjectID      // Chỉ lấy user thuộc department này (optional)
+	Filter       models.TimeFilter        // Lọc theo thời gian tạo, cập nhật
+	Pagin        paginator.PaginatorQuery // Trang cần lấy
+}
+
+// ListOutput là danh sách user của một trang
+type ListOutput struct {
+	Users []models.User
+	Pagin paginator.Paginator
+}
+
+// ImportRow là một dòng dữ liệu trong file CSV import user
+type ImportRow struct {
+	Line       int // Số dòng trong file (dòng header là dòng 1)
+	Username   string
+	Email      string
+	Password   string // Rỗng → hệ thống sinh mật khẩu tạm
+	Role       string // Rỗng → employee
+	Branch     string // ID hoặc tên branch
+	Department string // ID hoặc tên department (ưu tiên hơn branch)
+}
+
+// ImportInput là input để import nhiều user từ file CSV
+type ImportInput struct {
+	Rows   []ImportRow
+	DryRun bool // true → chỉ validate và báo cáo, không insert
+}
+
+// HistoryInput là dữ liệu đầu vào để lấy lịch sử thay đổi của user
+type HistoryInput struct {
+	ID    primitive.ObjectID       // ID user
+	Pagin paginator.PaginatorQuery // Trang cần lấy
+}
+
+// Revision là trạng thái của user trước một lần sửa hoặc xóa
+type Revision struct {
+	User      models.User           // Snapshot, có hiệu lực từ User.UpdatedAt đến ChangedAt
+	Action    models.RevisionAction // Thao tác đã thay thế snapshot
+	ChangedAt time.Time             // Thời điểm sửa / xóa
+	ChangedBy string                // UserID của người sửa / xóa
+}
+
+// HistoryOutput là lịch sử thay đổi của user trong một trang, mới nhất đứng trước
+type HistoryOutput struct {
+	Revisions []Revision
+	Pagin     paginator.Paginator
 }
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/user"
+	"thuchanhgolang/pkg/event"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newEvent tạo event của user u đặt tại vị trí của u, actor là user đang thao tác
+func newEvent(typ string, sc models.Scope, u models.User, payload map[string]interface{}) event.Event {
+	e := event.New(typ, user.AggregateType, u.ID.Hex(), sc.UserID, payload)
+	e.Location = u.Hierarchy().Location()
+	return e
+}
+
+// updateEvents tạo các event khi user thay đổi từ before thành after:
+// luôn có user.updated, thêm user.moved khi đổi vị trí và user.role_changed khi đổi role
+func updateEvents(sc models.Scope, before, after models.User) []event.Event {
+	events := []event.Event{newEvent(user.EventUserUpdated, sc, after, user.Payload(after))}
+
+	if !sameHierarchy(before.Hierarchy(), after.Hierarchy()) {
+		events = append(events, newEvent(user.EventUserMoved, sc, after, map[string]interface{}{
+			"from": user.HierarchyPayload(before.Hierarchy()),
+			"to":   user.HierarchyPayload(after.Hierarchy()),
+		}))
+	}
+	if before.Role != after.Role {
+		events = append(events, newEvent(user.EventUserRoleChanged, sc, after, map[string]interface{}{
+			"from_role": string(before.Role),
+			"to_role":   string(after.Role),
+		}))
+	}
+
+	return events
+}
+
+// sameHierarchy so sánh hai vị trí, department rỗng và không có department được coi là như nhau
+func sameHierarchy(a, b models.Hierarchy) bool {
+	return a.ShopID == b.ShopID && a.RegionID == b.RegionID && a.BranchID == b.BranchID &&
+		departmentID(a) == departmentID(b)
+}
+
+// departmentID trả về department của vị trí, NilObjectID khi không thuộc department nào
+func departmentID(h models.Hierarchy) primitive.ObjectID {
+	if h.DepartmentID == nil {
+		return primitive.NilObjectID
+	}
+	return *h.DepartmentID
+}
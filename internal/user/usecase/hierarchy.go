@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/user/repository/query"
+	pkgErrors "thuchanhgolang/pkg/errors"
+	"thuchanhgolang/pkg/mongo"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// hierarchyInput là các ID tổ chức client gửi lên (nil = không gửi)
+type hierarchyInput struct {
+	ShopID       *primitive.ObjectID
+	RegionID     *primitive.ObjectID
+	BranchID     *primitive.ObjectID
+	DepartmentID *primitive.ObjectID
+}
+
+// resolveHierarchy suy ra hierarchy đầy đủ từ department_id (ưu tiên) hoặc branch_id,
+// sau đó kiểm tra các ID client gửi kèm có khớp không và hierarchy có nằm trong scope không.
+// Mọi vi phạm được trả về dưới dạng lỗi theo từng field qua ValidationErrorCollector.
+func (uc *implUsecase) resolveHierarchy(ctx context.Context, sc models.Scope, in hierarchyInput) (models.Hierarchy, error) {
+	collector := pkgErrors.NewValidationErrorCollector()
+
+	// Bước 1: Resolve từ department hoặc branch
+	var (
+		result *query.CascadeResult
+		anchor string
+		err    error
+	)
+	switch {
+	case in.DepartmentID != nil:
+		anchor = "department_id"
+		result, err = uc.queryService.ResolveFromDepartment(ctx, sc, *in.DepartmentID)
+	case in.BranchID != nil:
+		anchor = "branch_id"
+		result, err = uc.queryService.ResolveFromBranch(ctx, sc, *in.BranchID)
+	default:
+		collector.Add(pkgErrors.NewValidationError("branch_id", "branch_id or department_id is required"))
+		return models.Hierarchy{}, collector
+	}
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			collector.Add(pkgErrors.NewValidationError(anchor, "does not exist in the organization tree"))
+			return models.Hierarchy{}, collector
+		}
+		uc.l.Errorf(ctx, "user.usecase.resolveHierarchy.%s: %v", anchor, err)
+		return models.Hierarchy{}, err
+	}
+
+	h := models.Hierarchy{
+		ShopID:       result.ShopID,
+		RegionID:     result.RegionID,
+		BranchID:     result.BranchID,
+		DepartmentID: result.DepartmentID,
+	}
+
+	// Bước 2: ID gửi kèm phải khớp với hierarchy đã resolve
+	if in.DepartmentID != nil && in.BranchID != nil && *in.BranchID != h.BranchID {
+		collector.Add(pkgErrors.NewValidationError("branch_id", "does not match department_id"))
+	}
+	if in.RegionID != nil && *in.RegionID != h.RegionID {
+		collector.Add(pkgErrors.NewValidationError("region_id", "does not match "+anchor))
+	}
+	if in.ShopID != nil && *in.ShopID != h.ShopID {
+		collector.Add(pkgErrors.NewValidationError("shop_id", "does not match "+anchor))
+	}
+
+	// Bước 3: Hierarchy phải nằm trong scope của user đang thao tác
+	if !sc.Contains(h) {
+		collector.Add(pkgErrors.NewValidationError(anchor, "is outside of your scope"))
+	}
+
+	if collector.HasError() {
+		uc.l.Warnf(ctx, "user.usecase.resolveHierarchy: %v", collector)
+		return models.Hierarchy{}, collector
+	}
+
+	return h, nil
+}
+
+// optionalID trả về nil nếu ID là zero (client không gửi)
+func optionalID(id primitive.ObjectID) *primitive.ObjectID {
+	if id.IsZero() {
+		return nil
+	}
+	return &id
+}
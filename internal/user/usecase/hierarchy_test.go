@@ -0,0 +1,388 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/user"
+	"thuchanhgolang/internal/user/repository/query"
+	"thuchanhgolang/pkg/event"
+	"thuchanhgolang/pkg/patch"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// hierarchyFixture là cây tổ chức: shop → region1 → branch1 → department1, shop → region2 → branch2.
+// User thao tác là region manager của region1.
+type hierarchyFixture struct {
+	shopID        primitive.ObjectID
+	region1ID     primitive.ObjectID
+	region2ID     primitive.ObjectID
+	branch1ID     primitive.ObjectID
+	branch2ID     primitive.ObjectID
+	department1ID primitive.ObjectID
+	sc            models.Scope
+}
+
+func newHierarchyFixture() hierarchyFixture {
+	f := hierarchyFixture{
+		shopID:        primitive.NewObjectID(),
+		region1ID:     primitive.NewObjectID(),
+		region2ID:     primitive.NewObjectID(),
+		branch1ID:     primitive.NewObjectID(),
+		branch2ID:     primitive.NewObjectID(),
+		department1ID: primitive.NewObjectID(),
+	}
+	f.sc = models.Scope{UserID: "region-manager-1", Role: models.RoleRegionManager, ShopID: &f.shopID, RegionID: &f.region1ID}
+	return f
+}
+
+func (f hierarchyFixture) queryService() *mockQueryService {
+	return &mockQueryService{
+		branches: map[primitive.ObjectID]query.CascadeResult{
+			f.branch1ID: {ShopID: f.shopID, RegionID: f.region1ID, BranchID: f.branch1ID},
+			f.branch2ID: {ShopID: f.shopID, RegionID: f.region2ID, BranchID: f.branch2ID},
+		},
+		departments: map[primitive.ObjectID]query.CascadeResult{
+			f.department1ID: {ShopID: f.shopID, RegionID: f.region1ID, BranchID: f.branch1ID, DepartmentID: ptr(f.department1ID)},
+		},
+	}
+}
+
+func (f hierarchyFixture) usecase(repo *mockRepository) (*implUsecase, *event.MemoryPublisher) {
+	publisher := event.NewMemoryPublisher()
+	return &implUsecase{
+		l:            &mockLogger{},
+		repo:         repo,
+		queryService: f.queryService(),
+		tx:           &mockTransactor{},
+		publisher:    publisher,
+	}, publisher
+}
+
+// member trả về user đang thuộc branch1 (trong scope của fixture)
+func (f hierarchyFixture) member() models.User {
+	return models.User{
+		ID:       primitive.NewObjectID(),
+		Username: "member",
+		ShopID:   f.shopID,
+		RegionID: f.region1ID,
+		BranchID: f.branch1ID,
+		Version:  3,
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestResolveHierarchy(t *testing.T) {
+	f := newHierarchyFixture()
+	otherID := primitive.NewObjectID()
+
+	tests := []struct {
+		name       string
+		input      hierarchyInput
+		wantFields map[string][]string
+		want       models.Hierarchy
+	}{
+		{
+			name:       "thiếu branch_id và department_id",
+			input:      hierarchyInput{ShopID: &f.shopID},
+			wantFields: map[string][]string{"branch_id": {"branch_id or department_id is required"}},
+		},
+		{
+			name:       "department không tồn tại",
+			input:      hierarchyInput{DepartmentID: &otherID},
+			wantFields: map[string][]string{"department_id": {"does not exist in the organization tree"}},
+		},
+		{
+			name:       "branch không tồn tại",
+			input:      hierarchyInput{BranchID: &otherID},
+			wantFields: map[string][]string{"branch_id": {"does not exist in the organization tree"}},
+		},
+		{
+			name:       "branch_id không khớp department_id",
+			input:      hierarchyInput{DepartmentID: &f.department1ID, BranchID: &f.branch2ID},
+			wantFields: map[string][]string{"branch_id": {"does not match department_id"}},
+		},
+		{
+			name:       "region_id không khớp branch_id",
+			input:      hierarchyInput{BranchID: &f.branch1ID, RegionID: &f.region2ID},
+			wantFields: map[string][]string{"region_id": {"does not match branch_id"}},
+		},
+		{
+			name:       "shop_id không khớp department_id",
+			input:      hierarchyInput{DepartmentID: &f.department1ID, ShopID: &otherID},
+			wantFields: map[string][]string{"shop_id": {"does not match department_id"}},
+		},
+		{
+			name:       "branch nằm ngoài scope",
+			input:      hierarchyInput{BranchID: &f.branch2ID},
+			wantFields: map[string][]string{"branch_id": {"is outside of your scope"}},
+		},
+		{
+			name:  "gom lỗi của nhiều field",
+			input: hierarchyInput{BranchID: &f.branch2ID, RegionID: &f.region1ID, ShopID: &otherID},
+			wantFields: map[string][]string{
+				"region_id": {"does not match branch_id"},
+				"shop_id":   {"does not match branch_id"},
+				"branch_id": {"is outside of your scope"},
+			},
+		},
+		{
+			name:  "resolve từ department",
+			input: hierarchyInput{DepartmentID: &f.department1ID, BranchID: &f.branch1ID, RegionID: &f.region1ID, ShopID: &f.shopID},
+			want:  models.Hierarchy{ShopID: f.shopID, RegionID: f.region1ID, BranchID: f.branch1ID, DepartmentID: &f.department1ID},
+		},
+		{
+			name:  "resolve từ branch",
+			input: hierarchyInput{BranchID: &f.branch1ID},
+			want:  models.Hierarchy{ShopID: f.shopID, RegionID: f.region1ID, BranchID: f.branch1ID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, _ := f.usecase(&mockRepository{})
+
+			h, err := uc.resolveHierarchy(context.Background(), f.sc, tt.input)
+			if tt.wantFields != nil {
+				if fields := validationFields(t, err); !reflect.DeepEqual(fields, tt.wantFields) {
+					t.Errorf("Mong đợi lỗi %v, nhận được %v", tt.wantFields, fields)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Không mong đợi lỗi: %v", err)
+			}
+			if !reflect.DeepEqual(h, tt.want) {
+				t.Errorf("Mong đợi hierarchy %+v, nhận được %+v", tt.want, h)
+			}
+		})
+	}
+}
+
+func TestResolveHierarchyQueryError(t *testing.T) {
+	f := newHierarchyFixture()
+	queryErr := errors.New("connection refused")
+	uc, _ := f.usecase(&mockRepository{})
+	uc.queryService = &mockQueryService{err: queryErr}
+
+	_, err := uc.resolveHierarchy(context.Background(), f.sc, hierarchyInput{BranchID: &f.branch1ID})
+	if !errors.Is(err, queryErr) {
+		t.Errorf("Mong đợi lỗi %v, nhận được %v", queryErr, err)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	f := newHierarchyFixture()
+
+	tests := []struct {
+		name       string
+		input      user.CreateInput
+		wantFields map[string][]string
+		want       models.Hierarchy
+	}{
+		{
+			name:       "thiếu vị trí",
+			input:      user.CreateInput{ShopID: f.shopID},
+			wantFields: map[string][]string{"branch_id": {"branch_id or department_id is required"}},
+		},
+		{
+			name:       "department không tồn tại",
+			input:      user.CreateInput{DepartmentID: ptr(primitive.NewObjectID())},
+			wantFields: map[string][]string{"department_id": {"does not exist in the organization tree"}},
+		},
+		{
+			name:       "region_id không khớp",
+			input:      user.CreateInput{BranchID: f.branch1ID, RegionID: f.region2ID},
+			wantFields: map[string][]string{"region_id": {"does not match branch_id"}},
+		},
+		{
+			name:       "branch nằm ngoài scope",
+			input:      user.CreateInput{BranchID: f.branch2ID},
+			wantFields: map[string][]string{"branch_id": {"is outside of your scope"}},
+		},
+		{
+			name:  "tạo trong department",
+			input: user.CreateInput{DepartmentID: &f.department1ID},
+			want:  models.Hierarchy{ShopID: f.shopID, RegionID: f.region1ID, BranchID: f.branch1ID, DepartmentID: &f.department1ID},
+		},
+		{
+			name:  "tạo trong branch",
+			input: user.CreateInput{ShopID: f.shopID, BranchID: f.branch1ID},
+			want:  models.Hierarchy{ShopID: f.shopID, RegionID: f.region1ID, BranchID: f.branch1ID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *user.CreateOptions
+			repo := &mockRepository{
+				createFunc: func(ctx context.Context, sc models.Scope, opts user.CreateOptions) (models.User, error) {
+					created = &opts
+					return models.User{ID: primitive.NewObjectID(), Username: opts.Username, ShopID: opts.ShopID, RegionID: opts.RegionID, BranchID: opts.BranchID, DepartmentID: opts.DepartmentID}, nil
+				},
+			}
+			uc, publisher := f.usecase(repo)
+
+			tt.input.Username = "new-user"
+			tt.input.Password = "secret123"
+			_, err := uc.Create(context.Background(), f.sc, tt.input)
+			if tt.wantFields != nil {
+				if fields := validationFields(t, err); !reflect.DeepEqual(fields, tt.wantFields) {
+					t.Errorf("Mong đợi lỗi %v, nhận được %v", tt.wantFields, fields)
+				}
+				if created != nil {
+					t.Error("Không mong đợi repository.Create được gọi")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Không mong đợi lỗi: %v", err)
+			}
+			if created == nil {
+				t.Fatal("Mong đợi repository.Create được gọi")
+			}
+			got := models.Hierarchy{ShopID: created.ShopID, RegionID: created.RegionID, BranchID: created.BranchID, DepartmentID: created.DepartmentID}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Mong đợi hierarchy %+v, nhận được %+v", tt.want, got)
+			}
+			if created.Password == tt.input.Password {
+				t.Error("Mong đợi password đã được hash")
+			}
+			if events := publisher.Events(); len(events) != 1 || events[0].Type != user.EventUserCreated {
+				t.Errorf("Mong đợi 1 event %s, nhận được %v", user.EventUserCreated, events)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	f := newHierarchyFixture()
+	member := f.member()
+	outsider := member
+	outsider.RegionID = f.region2ID
+	outsider.BranchID = f.branch2ID
+	inDepartment := member
+	inDepartment.DepartmentID = &f.department1ID
+
+	tests := []struct {
+		name       string
+		current    models.User
+		input      user.UpdateInput
+		wantErr    error
+		wantFields map[string][]string
+		wantOpts   func(opts user.UpdateOptions) bool
+	}{
+		{
+			name:    "user nằm ngoài scope",
+			current: outsider,
+			input:   user.UpdateInput{Username: ptr("renamed")},
+			wantErr: user.ErrUserOutOfScope,
+		},
+		{
+			name:    "version không khớp",
+			current: member,
+			input:   user.UpdateInput{Username: ptr("renamed"), Version: 2},
+			wantErr: user.ErrVersionMismatch,
+		},
+		{
+			name:    "chỉ gửi shop_id và region_id",
+			current: member,
+			input:   user.UpdateInput{ShopID: &f.shopID, RegionID: &f.region1ID},
+			wantFields: map[string][]string{
+				"shop_id":   {"cannot be changed without branch_id or department_id"},
+				"region_id": {"cannot be changed without branch_id or department_id"},
+			},
+		},
+		{
+			name:       "chuyển tới branch ngoài scope",
+			current:    member,
+			input:      user.UpdateInput{BranchID: &f.branch2ID},
+			wantFields: map[string][]string{"branch_id": {"is outside of your scope"}},
+		},
+		{
+			name:       "chuyển tới department không tồn tại",
+			current:    member,
+			input:      user.UpdateInput{DepartmentID: patch.Set(primitive.NewObjectID())},
+			wantFields: map[string][]string{"department_id": {"does not exist in the organization tree"}},
+		},
+		{
+			name:       "branch_id không khớp department_id",
+			current:    member,
+			input:      user.UpdateInput{DepartmentID: patch.Set(f.department1ID), BranchID: &f.branch2ID},
+			wantFields: map[string][]string{"branch_id": {"does not match department_id"}},
+		},
+		{
+			name:    "chuyển vào department",
+			current: member,
+			input:   user.UpdateInput{DepartmentID: patch.Set(f.department1ID), Version: 3},
+			wantOpts: func(opts user.UpdateOptions) bool {
+				return *opts.BranchID == f.branch1ID && *opts.RegionID == f.region1ID && *opts.ShopID == f.shopID &&
+					opts.DepartmentID.IsSet() && *opts.DepartmentID.Ptr() == f.department1ID && opts.Version == 3
+			},
+		},
+		{
+			name:    "rời department, ở lại branch hiện tại",
+			current: inDepartment,
+			input:   user.UpdateInput{DepartmentID: patch.Null[primitive.ObjectID]()},
+			wantOpts: func(opts user.UpdateOptions) bool {
+				return opts.BranchID != nil && *opts.BranchID == f.branch1ID && opts.DepartmentID.IsNull()
+			},
+		},
+		{
+			name:    "chỉ đổi username không đổi vị trí",
+			current: member,
+			input:   user.UpdateInput{Username: ptr("renamed")},
+			wantOpts: func(opts user.UpdateOptions) bool {
+				return *opts.Username == "renamed" && opts.BranchID == nil && opts.ShopID == nil &&
+					opts.RegionID == nil && !opts.DepartmentID.Present
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated *user.UpdateOptions
+			repo := &mockRepository{
+				getByIDFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.User, error) {
+					return tt.current, nil
+				},
+				updateFunc: func(ctx context.Context, sc models.Scope, opts user.UpdateOptions) (models.User, error) {
+					updated = &opts
+					return tt.current, nil
+				},
+			}
+			uc, _ := f.usecase(repo)
+
+			tt.input.ID = tt.current.ID
+			_, err := uc.Update(context.Background(), f.sc, tt.input)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Mong đợi lỗi %v, nhận được %v", tt.wantErr, err)
+				}
+			case tt.wantFields != nil:
+				if fields := validationFields(t, err); !reflect.DeepEqual(fields, tt.wantFields) {
+					t.Errorf("Mong đợi lỗi %v, nhận được %v", tt.wantFields, fields)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("Không mong đợi lỗi: %v", err)
+				}
+				if updated == nil || !tt.wantOpts(*updated) {
+					t.Errorf("UpdateOptions không như mong đợi: %+v", updated)
+				}
+				return
+			}
+			if updated != nil {
+				t.Error("Không mong đợi repository.Update được gọi")
+			}
+		})
+	}
+}
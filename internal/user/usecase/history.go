@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"thuchanhgolang/internal/history"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/user"
+	"thuchanhgolang/pkg/mongo"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// historyReader đọc lịch sử của user, trạng thái hiện tại được lấy trong scope của người gọi
+func (uc *implUsecase) historyReader(sc models.Scope) history.Reader[models.User] {
+	return history.Reader[models.User]{
+		Repo:   uc.history,
+		Entity: history.EntityUser,
+		Current: func(ctx context.Context, id primitive.ObjectID) (models.User, error) {
+			return uc.repo.GetByID(ctx, sc, id)
+		},
+		CreatedAt: func(v models.User) time.Time { return v.CreatedAt },
+	}
+}
+
+// GetAsOf lấy trạng thái của user tại thời điểm asOf, vị trí của user tại asOf phải nằm trong scope
+func (uc *implUsecase) GetAsOf(ctx context.Context, sc models.Scope, id primitive.ObjectID, asOf time.Time) (models.User, error) {
+	result, err := uc.historyReader(sc).GetAsOf(ctx, id, asOf)
+	if err != nil {
+		if errors.Is(err, history.ErrNotCreated) {
+			return models.User{}, user.ErrUserNotFound
+		}
+		uc.l.Errorf(ctx, "user.usecase.GetAsOf.historyReader.GetAsOf: %v", err)
+		return models.User{}, notFoundError(err)
+	}
+
+	if !sc.Contains(result.Hierarchy()) {
+		uc.l.Warnf(ctx, "user.usecase.GetAsOf: user %s out of scope", id.Hex())
+		return models.User{}, user.ErrUserOutOfScope
+	}
+
+	return result, nil
+}
+
+// History lấy lịch sử thay đổi của user, mới nhất đứng trước
+func (uc *implUsecase) History(ctx context.Context, sc models.Scope, input user.HistoryInput) (user.HistoryOutput, error) {
+	// Bước 1: User phải đang nằm trong scope, user đã xóa thì xét vị trí lúc bị xóa
+	if err := uc.checkHistoryScope(ctx, sc, input.ID); err != nil {
+		return user.HistoryOutput{}, err
+	}
+
+	// Bước 2: Đọc các revision
+	entries, pag, err := uc.historyReader(sc).List(ctx, input.ID, input.Pagin)
+	if err != nil {
+		uc.l.Errorf(ctx, "user.usecase.History.historyReader.List: %v", err)
+		return user.HistoryOutput{}, notFoundError(err)
+	}
+
+	out := user.HistoryOutput{
+		Revisions: make([]user.Revision, 0, len(entries)),
+		Pagin:     pag,
+	}
+	for _, e := range entries {
+		out.Revisions = append(out.Revisions, user.Revision{
+			User:      e.Snapshot,
+			Action:    e.Action,
+			ChangedAt: e.ChangedAt,
+			ChangedBy: e.ChangedBy,
+		})
+	}
+
+	return out, nil
+}
+
+// checkHistoryScope kiểm tra vị trí mới nhất của user (hiện tại, hoặc snapshot cuối cùng nếu đã bị xóa)
+// nằm trong scope của người gọi
+func (uc *implUsecase) checkHistoryScope(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
+	latest, err := uc.repo.GetByID(ctx, sc, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		latest, err = uc.lastSnapshot(ctx, id)
+	}
+	if err != nil {
+		uc.l.Errorf(ctx, "user.usecase.checkHistoryScope: %v", err)
+		return notFoundError(err)
+	}
+
+	if !sc.Contains(latest.Hierarchy()) {
+		uc.l.Warnf(ctx, "user.usecase.checkHistoryScope: user %s out of scope", id.Hex())
+		return user.ErrUserOutOfScope
+	}
+	return nil
+}
+
+// lastSnapshot lấy snapshot trong revision mới nhất của user, mongo.ErrNoDocuments khi chưa có revision nào
+func (uc *implUsecase) lastSnapshot(ctx context.Context, id primitive.ObjectID) (models.User, error) {
+	revs, _, err := uc.history.List(ctx, history.ListOptions{
+		Entity:   history.EntityUser,
+		EntityID: id,
+		Pagin:    paginator.PaginatorQuery{Page: 1, Limit: 1},
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+	if len(revs) == 0 {
+		return models.User{}, mongo.ErrNoDocuments
+	}
+
+	var snapshot models.User
+	if err := revs[0].Decode(&snapshot); err != nil {
+		return models.User{}, err
+	}
+	return snapshot, nil
+}
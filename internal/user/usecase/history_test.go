@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"thuchanhgolang/internal/history"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/user"
+	"thuchanhgolang/pkg/mongo"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// historyFixture là một user ở branch của người gọi và một user ở branch khác cùng shop
+type historyFixture struct {
+	inScope  models.User
+	outScope models.User
+	sc       models.Scope
+}
+
+func newHistoryFixture() historyFixture {
+	shopID, branchID := primitive.NewObjectID(), primitive.NewObjectID()
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return historyFixture{
+		inScope:  models.User{ID: primitive.NewObjectID(), Username: "colleague", ShopID: shopID, BranchID: branchID, CreatedAt: created},
+		outScope: models.User{ID: primitive.NewObjectID(), Username: "stranger", ShopID: shopID, BranchID: primitive.NewObjectID(), CreatedAt: created},
+		sc:       models.Scope{UserID: "bm-1", Role: models.RoleBranchManager, ShopID: &shopID, BranchID: &branchID},
+	}
+}
+
+// usecase dựng usecase với các user hiện tại và revision cho trước (theo ID của user)
+func (f historyFixture) usecase(current map[primitive.ObjectID]models.User, revisions map[primitive.ObjectID][]models.User) *implUsecase {
+	repo := &mockRepository{
+		getByIDFunc: func(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.User, error) {
+			u, ok := current[id]
+			if !ok {
+				return models.User{}, mongo.ErrNoDocuments
+			}
+			return u, nil
+		},
+	}
+	historyRepo := &mockHistoryRepository{
+		listFunc: func(ctx context.Context, opts history.ListOptions) ([]models.Revision, paginator.Paginator, error) {
+			var revs []models.Revision
+			for _, snapshot := range revisions[opts.EntityID] {
+				raw, _ := bson.Marshal(snapshot)
+				revs = append(revs, models.Revision{Action: models.RevisionUpdate, Snapshot: raw})
+			}
+			return revs, paginator.Paginator{Total: int64(len(revs)), Count: int64(len(revs))}, nil
+		},
+		getAsOfFunc: func(ctx context.Context, opts history.GetAsOfOptions) (models.Revision, error) {
+			revs := revisions[opts.EntityID]
+			if len(revs) == 0 {
+				return models.Revision{}, mongo.ErrNoDocuments
+			}
+			raw, _ := bson.Marshal(revs[len(revs)-1])
+			return models.Revision{Snapshot: raw}, nil
+		},
+	}
+	return &implUsecase{l: &mockLogger{}, repo: repo, history: historyRepo}
+}
+
+func TestHistoryScope(t *testing.T) {
+	f := newHistoryFixture()
+	moved := f.inScope
+	moved.BranchID = f.outScope.BranchID
+
+	tests := []struct {
+		name      string
+		id        primitive.ObjectID
+		current   map[primitive.ObjectID]models.User
+		revisions map[primitive.ObjectID][]models.User
+		wantErr   error
+	}{
+		{
+			name:    "user in scope",
+			id:      f.inScope.ID,
+			current: map[primitive.ObjectID]models.User{f.inScope.ID: f.inScope},
+		},
+		{
+			name:    "user out of scope",
+			id:      f.outScope.ID,
+			current: map[primitive.ObjectID]models.User{f.outScope.ID: f.outScope},
+			wantErr: user.ErrUserOutOfScope,
+		},
+		{
+			name:      "user moved out of scope",
+			id:        f.inScope.ID,
+			current:   map[primitive.ObjectID]models.User{f.inScope.ID: moved},
+			revisions: map[primitive.ObjectID][]models.User{f.inScope.ID: {f.inScope}},
+			wantErr:   user.ErrUserOutOfScope,
+		},
+		{
+			name:      "deleted user last in scope",
+			id:        f.inScope.ID,
+			revisions: map[primitive.ObjectID][]models.User{f.inScope.ID: {f.inScope}},
+		},
+		{
+			name:      "deleted user last out of scope",
+			id:        f.outScope.ID,
+			revisions: map[primitive.ObjectID][]models.User{f.outScope.ID: {f.outScope}},
+			wantErr:   user.ErrUserOutOfScope,
+		},
+		{
+			name:    "unknown user",
+			id:      primitive.NewObjectID(),
+			wantErr: user.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := f.usecase(tt.current, tt.revisions)
+
+			out, err := uc.History(context.Background(), f.sc, user.HistoryInput{ID: tt.id})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Mong đợi %v, nhận được %v", tt.wantErr, err)
+			}
+			if err == nil && len(out.Revisions) != len(tt.revisions[tt.id]) {
+				t.Errorf("Mong đợi %d revision, nhận được %d", len(tt.revisions[tt.id]), len(out.Revisions))
+			}
+		})
+	}
+}
+
+func TestGetAsOfScope(t *testing.T) {
+	f := newHistoryFixture()
+	asOf := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("current user in scope", func(t *testing.T) {
+		uc := f.usecase(map[primitive.ObjectID]models.User{f.inScope.ID: f.inScope}, nil)
+
+		got, err := uc.GetAsOf(context.Background(), f.sc, f.inScope.ID, asOf)
+
+		if err != nil || got.ID != f.inScope.ID {
+			t.Errorf("Mong đợi đọc được user, nhận được %+v, %v", got, err)
+		}
+	})
+
+	t.Run("current user out of scope", func(t *testing.T) {
+		uc := f.usecase(map[primitive.ObjectID]models.User{f.outScope.ID: f.outScope}, nil)
+
+		_, err := uc.GetAsOf(context.Background(), f.sc, f.outScope.ID, asOf)
+
+		if !errors.Is(err, user.ErrUserOutOfScope) {
+			t.Errorf("Mong đợi ErrUserOutOfScope, nhận được %v", err)
+		}
+	})
+
+	t.Run("snapshot out of scope", func(t *testing.T) {
+		// User hiện ở branch của người gọi nhưng tại asOf còn ở branch khác
+		before := f.inScope
+		before.BranchID = f.outScope.BranchID
+		uc := f.usecase(
+			map[primitive.ObjectID]models.User{f.inScope.ID: f.inScope},
+			map[primitive.ObjectID][]models.User{f.inScope.ID: {before}},
+		)
+
+		_, err := uc.GetAsOf(context.Background(), f.sc, f.inScope.ID, asOf)
+
+		if !errors.Is(err, user.ErrUserOutOfScope) {
+			t.Errorf("Mong đợi ErrUserOutOfScope, nhận được %v", err)
+		}
+	})
+
+	t.Run("before created", func(t *testing.T) {
+		uc := f.usecase(map[primitive.ObjectID]models.User{f.inScope.ID: f.inScope}, nil)
+
+		_, err := uc.GetAsOf(context.Background(), f.sc, f.inScope.ID, f.inScope.CreatedAt.Add(-time.Hour))
+
+		if !errors.Is(err, user.ErrUserNotFound) {
+			t.Errorf("Mong đợi ErrUserNotFound, nhận được %v", err)
+		}
+	})
+}
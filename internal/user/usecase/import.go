@@ -0,0 +1,728 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/mail"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/user"
+	pkgErrors "thuchanhgolang/pkg/errors"
+	"thuchanhgolang/pkg/event"
+	"thuchanhgolang/pkg/mongo"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// importSyncLimit là số dòng tối đa được xử lý ngay trong request, file lớn hơn chạy nền
+	importSyncLimit = 200
+	// importMaxRows là số dòng tối đa của một file import
+	importMaxRows = 5000
+	// importProgressEvery là số dòng giữa hai lần ghi tiến độ của job chạy nền
+	importProgressEvery = 25
+	// importJobTimeout là thời gian tối đa cho một job chạy nền
+	importJobTimeout = 10 * time.Minute
+
+	// importPasswordMinLength, importPasswordMaxLength giới hạn password trong file như khi tạo user (min=6,max=72)
+	importPasswordMinLength = 6
+	importPasswordMaxLength = 72
+
+	// tempPasswordLength là độ dài mật khẩu tạm sinh cho dòng không có password
+	tempPasswordLength = 12
+	// tempPasswordAlphabet bỏ các ký tự dễ nhầm (0/O, 1/l/I)
+	tempPasswordAlphabet = "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+// importPlan là một dòng CSV hợp lệ, sẵn sàng để insert
+type importPlan struct {
+	line         int
+	opts         user.CreateOptions // Password ở dạng plain text cho tới khi hash
+	tempPassword bool               // Password do hệ thống sinh
+}
+
+// Import tạo nhiều user từ các dòng CSV.
+// Mọi dòng được validate trước; ở chế độ commit chỉ cần một dòng lỗi là không user nào được tạo.
+// File có nhiều hơn importSyncLimit dòng được xử lý nền, client theo dõi qua GetImportJob.
+func (uc *implUsecase) Import(ctx context.Context, sc models.Scope, input user.ImportInput) (models.ImportJob, error) {
+	if len(input.Rows) == 0 {
+		return models.ImportJob{}, user.ErrImportEmpty
+	}
+	if len(input.Rows) > importMaxRows {
+		return models.ImportJob{}, user.ErrImportTooLarge
+	}
+
+	job := models.ImportJob{
+		CreatedBy: sc.UserID,
+		Status:    models.ImportJobPending,
+		DryRun:    input.DryRun,
+		Total:     len(input.Rows),
+	}
+
+	// File nhỏ: xử lý ngay và trả kết quả luôn
+	if len(input.Rows) <= importSyncLimit {
+		now := time.Now()
+		job.CreatedAt = now
+		job.UpdatedAt = now
+
+		if err := uc.runImport(ctx, sc, &job, input.Rows, nil); err != nil {
+			return models.ImportJob{}, err
+		}
+		if !job.DryRun && len(job.Errors) > 0 {
+			return models.ImportJob{}, newImportCollector(job.Errors)
+		}
+		return job, nil
+	}
+
+	// File lớn: lưu job rồi xử lý nền
+	job, err := uc.repo.CreateImportJob(ctx, job)
+	if err != nil {
+		uc.l.Errorf(ctx, "user.usecase.Import.repo.CreateImportJob: %v", err)
+		return models.ImportJob{}, err
+	}
+
+	// Job không bị hủy theo request nhưng vẫn giữ các giá trị trong ctx (trace, logger...)
+	go uc.runImportJob(context.WithoutCancel(ctx), sc, job, input.Rows)
+
+	return job, nil
+}
+
+// GetImportJob lấy tiến độ / kết quả của job import.
+// Chỉ người tạo job mới xem được; mật khẩu tạm (lưu ở dạng mã hóa) chỉ được giải mã và trả về
+// ở lần xem đầu tiên sau khi job xong.
+func (uc *implUsecase) GetImportJob(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.ImportJob, error) {
+	job, err := uc.repo.GetImportJob(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.ImportJob{}, user.ErrImportJobNotFound
+		}
+		uc.l.Errorf(ctx, "user.usecase.GetImportJob.repo.GetImportJob: %v", err)
+		return models.ImportJob{}, err
+	}
+
+	// Không tiết lộ job của người khác có tồn tại hay không
+	if job.CreatedBy != sc.UserID {
+		return models.ImportJob{}, user.ErrImportJobNotFound
+	}
+
+	if job.IsFinished() && hasTempPasswords(job.Users) {
+		if err := uc.repo.ClearImportJobPasswords(ctx, job.ID); err != nil {
+			uc.l.Errorf(ctx, "user.usecase.GetImportJob.repo.ClearImportJobPasswords: %v", err)
+		}
+		job.Users = uc.openTempPasswords(ctx, job.Users)
+	}
+
+	return job, nil
+}
+
+// runImportJob xử lý job chạy nền và ghi kết quả vào database
+func (uc *implUsecase) runImportJob(ctx context.Context, sc models.Scope, job models.ImportJob, rows []user.ImportRow) {
+	ctx, cancel := context.WithTimeout(ctx, importJobTimeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			uc.l.Errorf(ctx, "user.usecase.runImportJob: panic: %v", r)
+			job.Status = models.ImportJobFailed
+			job.Error = "internal error while importing"
+			job.Users = nil
+			uc.saveImportJob(ctx, job)
+		}
+	}()
+
+	progress := func(processed int) {
+		if processed%importProgressEvery != 0 {
+			return
+		}
+		if err := uc.repo.UpdateImportJobProgress(ctx, job.ID, processed); err != nil {
+			uc.l.Warnf(ctx, "user.usecase.runImportJob.repo.UpdateImportJobProgress: %v", err)
+		}
+	}
+
+	if err := uc.runImport(ctx, sc, &job, rows, progress); err != nil {
+		job.Status = models.ImportJobFailed
+		job.Error = "internal error while importing"
+		job.Users = nil
+		now := time.Now()
+		job.FinishedAt = &now
+	}
+
+	uc.saveImportJob(ctx, job)
+}
+
+// saveImportJob ghi trạng thái cuối của job, dùng context riêng để vẫn ghi được khi job bị timeout.
+// Mật khẩu tạm được mã hóa trước khi lưu, không bao giờ nằm trong database ở dạng plain text.
+func (uc *implUsecase) saveImportJob(ctx context.Context, job models.ImportJob) {
+	if job.FinishedAt == nil {
+		now := time.Now()
+		job.FinishedAt = &now
+	}
+	job.Users = uc.sealTempPasswords(ctx, job.Users)
+	if err := uc.repo.UpdateImportJob(context.WithoutCancel(ctx), job); err != nil {
+		uc.l.Errorf(ctx, "user.usecase.saveImportJob.repo.UpdateImportJob: %v", err)
+	}
+}
+
+// runImport validate toàn bộ các dòng, sau đó insert nếu không phải dry-run và không có lỗi.
+// Kết quả được ghi vào job; error chỉ trả về khi gặp lỗi hệ thống.
+func (uc *implUsecase) runImport(ctx context.Context, sc models.Scope, job *models.ImportJob, rows []user.ImportRow, progress func(processed int)) error {
+	job.Status = models.ImportJobRunning
+
+	plans, collector, err := uc.validateImportRows(ctx, sc, rows, progress)
+	if err != nil {
+		return err
+	}
+	job.Processed = len(rows)
+
+	switch {
+	case job.DryRun:
+		// Dry-run: báo cáo cả dòng hợp lệ lẫn dòng lỗi, không tiết lộ mật khẩu tạm
+		job.Errors = toImportErrors(collector)
+		job.Users = make([]models.ImportedUser, 0, len(plans))
+		for _, p := range plans {
+			job.Users = append(job.Users, newImportedUser(p, models.User{}))
+		}
+		job.Status = models.ImportJobCompleted
+
+	case collector.HasError():
+		job.Errors = toImportErrors(collector)
+		job.Status = models.ImportJobFailed
+
+	default:
+		imported, err := uc.commitImport(ctx, sc, plans)
+		if err != nil {
+			var vErr *pkgErrors.ValidationErrorCollector
+			if !errors.As(err, &vErr) {
+				return err
+			}
+			job.Errors = toImportErrors(vErr)
+			job.Status = models.ImportJobFailed
+			break
+		}
+		job.Users = imported
+		job.Created = len(imported)
+		job.Status = models.ImportJobCompleted
+	}
+
+	now := time.Now()
+	job.FinishedAt = &now
+	return nil
+}
+
+// validateImportRows kiểm tra từng dòng và gom lỗi theo dạng rows[<dòng>].<cột>
+func (uc *implUsecase) validateImportRows(ctx context.Context, sc models.Scope, rows []user.ImportRow, progress func(processed int)) ([]importPlan, *pkgErrors.ValidationErrorCollector, error) {
+	collector := pkgErrors.NewValidationErrorCollector()
+
+	// Lấy trước các username đã tồn tại bằng một query
+	usernames := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if name := strings.TrimSpace(row.Username); name != "" {
+			usernames = append(usernames, name)
+		}
+	}
+	existing, err := uc.repo.ExistingUsernames(ctx, usernames)
+	if err != nil {
+		uc.l.Errorf(ctx, "user.usecase.validateImportRows.repo.ExistingUsernames: %v", err)
+		return nil, nil, err
+	}
+	taken := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		taken[name] = true
+	}
+
+	resolver := newImportResolver(uc, sc)
+	seen := make(map[string]int, len(rows)) // username → dòng xuất hiện đầu tiên
+	plans := make([]importPlan, 0, len(rows))
+
+	for i, row := range rows {
+		rowErrs := 0
+		addErr := func(column, message string) {
+			collector.Add(pkgErrors.NewValidationError(importField(row.Line, column), message))
+			rowErrs++
+		}
+
+		// Username: bắt buộc, không trùng trong file và trong database
+		username := strings.TrimSpace(row.Username)
+		switch {
+		case username == "":
+			addErr("username", "is required")
+		case seen[username] != 0:
+			addErr("username", fmt.Sprintf("is duplicated with line %d", seen[username]))
+		case taken[username]:
+			addErr("username", "already exists")
+		}
+		if username != "" && seen[username] == 0 {
+			seen[username] = row.Line
+		}
+
+		// Email: bắt buộc và đúng định dạng
+		email := strings.TrimSpace(row.Email)
+		if email == "" {
+			addErr("email", "is required")
+		} else if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+			addErr("email", "is not a valid email")
+		}
+
+		// Password: không bắt buộc (để trống thì sinh mật khẩu tạm), có thì phải đúng độ dài.
+		// Kiểm tra ở đây để một dòng lỗi không làm hỏng bước hash của cả file;
+		// bcrypt giới hạn 72 byte nên độ dài tối đa tính theo byte
+		if row.Password != "" {
+			if utf8.RuneCountInString(row.Password) < importPasswordMinLength {
+				addErr("password", fmt.Sprintf("must be at least %d characters", importPasswordMinLength))
+			} else if len(row.Password) > importPasswordMaxLength {
+				addErr("password", fmt.Sprintf("must be at most %d characters", importPasswordMaxLength))
+			}
+		}
+
+		// Role: mặc định employee, không được cao hơn role của người import
+		role := models.Role(strings.ToLower(strings.TrimSpace(row.Role)))
+		if role == "" {
+			role = models.RoleEmployee
+		}
+		if !role.IsValid() {
+			addErr("role", "is not a valid role")
+		} else if !sc.Role.CanAssign(role) {
+			addErr("role", "cannot be assigned by your role")
+		}
+
+		// Vị trí trong cây tổ chức
+		h, placementErrs, err := resolver.resolve(ctx, row)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, pe := range placementErrs {
+			for _, msg := range pe.Messages {
+				addErr(pe.Field, msg)
+			}
+		}
+
+		if rowErrs == 0 {
+			p := importPlan{
+				line: row.Line,
+				opts: user.CreateOptions{
+					Username:     username,
+					Password:     row.Password,
+					Email:        email,
+					Role:         role,
+					ShopID:       h.ShopID,
+					RegionID:     h.RegionID,
+					BranchID:     h.BranchID,
+					DepartmentID: h.DepartmentID,
+				},
+			}
+			if p.opts.Password == "" {
+				p.tempPassword = true
+			}
+			plans = append(plans, p)
+		}
+
+		if progress != nil {
+			progress(i + 1)
+		}
+	}
+
+	return plans, collector, nil
+}
+
+// commitImport hash mật khẩu rồi insert toàn bộ user trong một transaction
+func (uc *implUsecase) commitImport(ctx context.Context, sc models.Scope, plans []importPlan) ([]models.ImportedUser, error) {
+	// Sinh mật khẩu tạm và hash ngoài transaction vì bcrypt chậm
+	plain := make([]string, len(plans))
+	for i := range plans {
+		if plans[i].tempPassword {
+			pw, err := generateTempPassword()
+			if err != nil {
+				uc.l.Errorf(ctx, "user.usecase.commitImport.generateTempPassword: %v", err)
+				return nil, err
+			}
+			plans[i].opts.Password = pw
+		}
+		plain[i] = plans[i].opts.Password
+	}
+	if err := hashImportPasswords(plans); err != nil {
+		uc.l.Errorf(ctx, "user.usecase.commitImport.hashImportPasswords: %v", err)
+		return nil, err
+	}
+
+	opts := make([]user.CreateOptions, 0, len(plans))
+	for _, p := range plans {
+		opts = append(opts, p.opts)
+	}
+
+	var created []models.User
+	err := uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = uc.repo.CreateMany(ctx, sc, opts)
+		if err != nil {
+			uc.l.Errorf(ctx, "user.usecase.commitImport.repo.CreateMany: %v", err)
+			return err
+		}
+
+		events := make([]event.Event, 0, len(created))
+		for _, u := range created {
+			events = append(events, newEvent(user.EventUserCreated, sc, u, user.Payload(u)))
+		}
+		if err := uc.publisher.Publish(ctx, events...); err != nil {
+			uc.l.Errorf(ctx, "user.usecase.commitImport.publisher.Publish: %v", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		// Username/email bị tạo bởi request khác sau bước validate: báo lỗi đúng dòng bị trùng
+		if dup, ok := mongo.AsDuplicateKey(err); ok && dup.Position < len(plans) && dup.Field() != "" {
+			collector := pkgErrors.NewValidationErrorCollector()
+			collector.Add(pkgErrors.NewValidationError(importField(plans[dup.Position].line, dup.Field()), "already exists"))
+			return nil, collector
+		}
+		return nil, err
+	}
+
+	imported := make([]models.ImportedUser, 0, len(created))
+	for i, u := range created {
+		iu := newImportedUser(plans[i], u)
+		if plans[i].tempPassword {
+			iu.TemporaryPassword = plain[i]
+		}
+		imported = append(imported, iu)
+	}
+	return imported, nil
+}
+
+// importResolver tìm vị trí trong cây tổ chức cho các dòng CSV, cache theo tên / ID để
+// các dòng cùng branch/department không phải query lại
+type importResolver struct {
+	uc          *implUsecase
+	sc          models.Scope
+	branches    map[string]importRef
+	departments map[string]importRef
+	hierarchies map[string]importPlacement
+}
+
+// importRef là kết quả tra cứu một giá trị trong cột branch / department
+type importRef struct {
+	id      primitive.ObjectID
+	message string // Khác rỗng khi không tra cứu được
+}
+
+// importPlacement là hierarchy đã resolve (hoặc lỗi) của một cặp branch/department
+type importPlacement struct {
+	h    models.Hierarchy
+	errs []*pkgErrors.ValidationError
+}
+
+func newImportResolver(uc *implUsecase, sc models.Scope) *importResolver {
+	return &importResolver{
+		uc:          uc,
+		sc:          sc,
+		branches:    make(map[string]importRef),
+		departments: make(map[string]importRef),
+		hierarchies: make(map[string]importPlacement),
+	}
+}
+
+// resolve trả về hierarchy của dòng, hoặc các lỗi với field là tên cột (branch / department)
+func (r *importResolver) resolve(ctx context.Context, row user.ImportRow) (models.Hierarchy, []*pkgErrors.ValidationError, error) {
+	branchRef := strings.TrimSpace(row.Branch)
+	deptRef := strings.TrimSpace(row.Department)
+	if branchRef == "" && deptRef == "" {
+		return models.Hierarchy{}, []*pkgErrors.ValidationError{
+			pkgErrors.NewValidationError("branch", "branch or department is required"),
+		}, nil
+	}
+
+	var in hierarchyInput
+	if branchRef != "" {
+		ref, err := r.branch(ctx, branchRef)
+		if err != nil {
+			return models.Hierarchy{}, nil, err
+		}
+		if ref.message != "" {
+			return models.Hierarchy{}, []*pkgErrors.ValidationError{pkgErrors.NewValidationError("branch", ref.message)}, nil
+		}
+		in.BranchID = &ref.id
+	}
+	if deptRef != "" {
+		ref, err := r.department(ctx, deptRef, in.BranchID)
+		if err != nil {
+			return models.Hierarchy{}, nil, err
+		}
+		if ref.message != "" {
+			return models.Hierarchy{}, []*pkgErrors.ValidationError{pkgErrors.NewValidationError("department", ref.message)}, nil
+		}
+		in.DepartmentID = &ref.id
+	}
+
+	var key string
+	if in.BranchID != nil {
+		key = in.BranchID.Hex()
+	}
+	key += "|"
+	if in.DepartmentID != nil {
+		key += in.DepartmentID.Hex()
+	}
+	if p, ok := r.hierarchies[key]; ok {
+		return p.h, p.errs, nil
+	}
+
+	// Dùng chung logic resolve với API tạo user, đổi tên field về tên cột trong CSV
+	var p importPlacement
+	h, err := r.uc.resolveHierarchy(ctx, r.sc, in)
+	if err != nil {
+		var vErr *pkgErrors.ValidationErrorCollector
+		if !errors.As(err, &vErr) {
+			return models.Hierarchy{}, nil, err
+		}
+		for _, e := range vErr.Errors() {
+			p.errs = append(p.errs, pkgErrors.NewValidationError(strings.TrimSuffix(e.Field, "_id"), e.Messages...))
+		}
+	} else {
+		p.h = h
+	}
+	r.hierarchies[key] = p
+
+	return p.h, p.errs, nil
+}
+
+// branch tra cứu giá trị cột branch: ObjectID hoặc tên branch nằm trong scope
+func (r *importResolver) branch(ctx context.Context, value string) (importRef, error) {
+	if id, err := primitive.ObjectIDFromHex(value); err == nil {
+		return importRef{id: id}, nil
+	}
+	if ref, ok := r.branches[value]; ok {
+		return ref, nil
+	}
+
+	candidates, err := r.uc.repo.FindBranchesByName(ctx, value)
+	if err != nil {
+		return importRef{}, err
+	}
+
+	var matched []primitive.ObjectID
+	for _, b := range candidates {
+		res, err := r.uc.queryService.ResolveFromBranch(ctx, r.sc, b.ID)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				continue // Branch mồ côi, bỏ qua
+			}
+			return importRef{}, err
+		}
+		if r.sc.Contains(models.Hierarchy{ShopID: res.ShopID, RegionID: res.RegionID, BranchID: res.BranchID}) {
+			matched = append(matched, b.ID)
+		}
+	}
+
+	ref := pickImportRef(matched, "branch")
+	r.branches[value] = ref
+	return ref, nil
+}
+
+// department tra cứu giá trị cột department: ObjectID hoặc tên department nằm trong scope.
+// Nếu dòng có cột branch thì chỉ tìm department thuộc branch đó.
+func (r *importResolver) department(ctx context.Context, value string, branchID *primitive.ObjectID) (importRef, error) {
+	if id, err := primitive.ObjectIDFromHex(value); err == nil {
+		return importRef{id: id}, nil
+	}
+
+	key := value + "|"
+	if branchID != nil {
+		key += branchID.Hex()
+	}
+	if ref, ok := r.departments[key]; ok {
+		return ref, nil
+	}
+
+	candidates, err := r.uc.repo.FindDepartmentsByName(ctx, value)
+	if err != nil {
+		return importRef{}, err
+	}
+
+	var matched []primitive.ObjectID
+	for _, d := range candidates {
+		if branchID != nil && d.BranchID != *branchID {
+			continue
+		}
+		res, err := r.uc.queryService.ResolveFromDepartment(ctx, r.sc, d.ID)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				continue // Department mồ côi, bỏ qua
+			}
+			return importRef{}, err
+		}
+		h := models.Hierarchy{ShopID: res.ShopID, RegionID: res.RegionID, BranchID: res.BranchID, DepartmentID: res.DepartmentID}
+		if r.sc.Contains(h) {
+			matched = append(matched, d.ID)
+		}
+	}
+
+	ref := pickImportRef(matched, "department")
+	r.departments[key] = ref
+	return ref, nil
+}
+
+// pickImportRef chọn kết quả duy nhất, báo lỗi nếu không có hoặc có nhiều hơn một
+func pickImportRef(matched []primitive.ObjectID, kind string) importRef {
+	switch len(matched) {
+	case 0:
+		return importRef{message: "is not found in your scope"}
+	case 1:
+		return importRef{id: matched[0]}
+	default:
+		return importRef{message: fmt.Sprintf("matches %d %s entries, use the %s ID instead", len(matched), kind, kind)}
+	}
+}
+
+// hashImportPasswords hash password của các dòng song song, giới hạn theo số CPU
+func hashImportPasswords(plans []importPlan) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, runtime.NumCPU())
+
+	for i := range plans {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(p *importPlan) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			hashed, err := bcrypt.GenerateFromPassword([]byte(p.opts.Password), bcrypt.DefaultCost)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				return
+			}
+			p.opts.Password = string(hashed)
+		}(&plans[i])
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// generateTempPassword sinh mật khẩu ngẫu nhiên cho user không có password trong file
+func generateTempPassword() (string, error) {
+	max := big.NewInt(int64(len(tempPasswordAlphabet)))
+	b := make([]byte, tempPasswordLength)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = tempPasswordAlphabet[n.Int64()]
+	}
+	return string(b), nil
+}
+
+// importField tạo tên field cho lỗi của một ô, ví dụ rows[3].email
+func importField(line int, column string) string {
+	return fmt.Sprintf("rows[%d].%s", line, column)
+}
+
+// newImportedUser tạo kết quả cho một dòng hợp lệ, u rỗng khi dry-run
+func newImportedUser(p importPlan, u models.User) models.ImportedUser {
+	return models.ImportedUser{
+		Line:         p.line,
+		UserID:       u.ID,
+		Username:     p.opts.Username,
+		Email:        p.opts.Email,
+		Role:         p.opts.Role,
+		ShopID:       p.opts.ShopID,
+		RegionID:     p.opts.RegionID,
+		BranchID:     p.opts.BranchID,
+		DepartmentID: p.opts.DepartmentID,
+	}
+}
+
+// toImportErrors chuyển lỗi trong collector sang dạng lưu trong job
+func toImportErrors(collector *pkgErrors.ValidationErrorCollector) []models.ImportError {
+	if collector == nil || !collector.HasError() {
+		return nil
+	}
+	out := make([]models.ImportError, 0, len(collector.Errors()))
+	for _, e := range collector.Errors() {
+		out = append(out, models.ImportError{Field: e.Field, Messages: e.Messages})
+	}
+	return out
+}
+
+// newImportCollector chuyển lỗi của job về ValidationErrorCollector để trả cho client
+func newImportCollector(errs []models.ImportError) *pkgErrors.ValidationErrorCollector {
+	collector := pkgErrors.NewValidationErrorCollector()
+	for _, e := range errs {
+		collector.Add(pkgErrors.NewValidationError(e.Field, e.Messages...))
+	}
+	return collector
+}
+
+// sealTempPasswords trả về bản sao của users với mật khẩu tạm đã mã hóa.
+// Không mã hóa được thì bỏ mật khẩu đó (người import phải đặt lại mật khẩu cho user) thay vì lưu plain text.
+func (uc *implUsecase) sealTempPasswords(ctx context.Context, users []models.ImportedUser) []models.ImportedUser {
+	if !hasTempPasswords(users) {
+		return users
+	}
+
+	out := make([]models.ImportedUser, len(users))
+	copy(out, users)
+	for i := range out {
+		if out[i].TemporaryPassword == "" {
+			continue
+		}
+		if uc.encrypter == nil {
+			uc.l.Errorf(ctx, "user.usecase.sealTempPasswords: no encrypter, dropping temporary password of line %d", out[i].Line)
+			out[i].TemporaryPassword = ""
+			continue
+		}
+		sealed, err := uc.encrypter.Encrypt(out[i].TemporaryPassword)
+		if err != nil {
+			uc.l.Errorf(ctx, "user.usecase.sealTempPasswords.encrypter.Encrypt: %v", err)
+			sealed = ""
+		}
+		out[i].TemporaryPassword = sealed
+	}
+	return out
+}
+
+// openTempPasswords giải mã mật khẩu tạm đọc từ database, mật khẩu không giải mã được bị bỏ
+func (uc *implUsecase) openTempPasswords(ctx context.Context, users []models.ImportedUser) []models.ImportedUser {
+	for i := range users {
+		if users[i].TemporaryPassword == "" {
+			continue
+		}
+		if uc.encrypter == nil {
+			users[i].TemporaryPassword = ""
+			continue
+		}
+		plain, err := uc.encrypter.Decrypt(users[i].TemporaryPassword)
+		if err != nil {
+			uc.l.Errorf(ctx, "user.usecase.openTempPasswords.encrypter.Decrypt: %v", err)
+			plain = ""
+		}
+		users[i].TemporaryPassword = plain
+	}
+	return users
+}
+
+// hasTempPasswords kiểm tra job còn mật khẩu tạm chưa xóa không
+func hasTempPasswords(users []models.ImportedUser) bool {
+	for _, u := range users {
+		if u.TemporaryPassword != "" {
+			return true
+		}
+	}
+	return false
+}
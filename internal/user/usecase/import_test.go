@@ -0,0 +1,441 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/user"
+	"thuchanhgolang/internal/user/repository/query"
+	"thuchanhgolang/pkg/encrypter"
+	pkgErrors "thuchanhgolang/pkg/errors"
+	"thuchanhgolang/pkg/event"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const testEncryptKey = "0123456789abcdef0123456789abcdef"
+
+// importFixture là cây tổ chức shop → region → branch dùng chung cho các test import
+type importFixture struct {
+	shopID   primitive.ObjectID
+	regionID primitive.ObjectID
+	branchID primitive.ObjectID
+	sc       models.Scope
+}
+
+func newImportFixture() importFixture {
+	f := importFixture{
+		shopID:   primitive.NewObjectID(),
+		regionID: primitive.NewObjectID(),
+		branchID: primitive.NewObjectID(),
+	}
+	f.sc = models.Scope{UserID: "manager-1", Role: models.RoleManager, ShopID: &f.shopID}
+	return f
+}
+
+func (f importFixture) usecase(repo *mockRepository) *implUsecase {
+	qs := &mockQueryService{
+		branches: map[primitive.ObjectID]query.CascadeResult{
+			f.branchID: {ShopID: f.shopID, RegionID: f.regionID, BranchID: f.branchID},
+		},
+	}
+	return &implUsecase{
+		l:            &mockLogger{},
+		repo:         repo,
+		queryService: qs,
+		tx:           &mockTransactor{},
+		publisher:    event.NewMemoryPublisher(),
+		encrypter:    encrypter.NewEncrypter(testEncryptKey),
+	}
+}
+
+func (f importFixture) row(line int, username string) user.ImportRow {
+	return user.ImportRow{
+		Line:     line,
+		Username: username,
+		Email:    username + "@example.com",
+		Branch:   f.branchID.Hex(),
+	}
+}
+
+// validationFields trả về map field → messages của lỗi validate
+func validationFields(t *testing.T, err error) map[string][]string {
+	t.Helper()
+	var vErr *pkgErrors.ValidationErrorCollector
+	if !errors.As(err, &vErr) {
+		t.Fatalf("Mong đợi ValidationErrorCollector, nhận được %v", err)
+	}
+	fields := make(map[string][]string)
+	for _, e := range vErr.Errors() {
+		fields[e.Field] = append(fields[e.Field], e.Messages...)
+	}
+	return fields
+}
+
+func TestImportRowValidation(t *testing.T) {
+	f := newImportFixture()
+
+	tests := []struct {
+		name    string
+		row     user.ImportRow
+		field   string
+		message string
+	}{
+		{
+			name:    "missing username",
+			row:     user.ImportRow{Line: 2, Email: "a@example.com", Branch: f.branchID.Hex()},
+			field:   "rows[2].username",
+			message: "is required",
+		},
+		{
+			name:    "invalid email",
+			row:     user.ImportRow{Line: 2, Username: "a", Email: "not-an-email", Branch: f.branchID.Hex()},
+			field:   "rows[2].email",
+			message: "is not a valid email",
+		},
+		{
+			name:    "password too short",
+			row:     user.ImportRow{Line: 2, Username: "a", Email: "a@example.com", Password: "12345", Branch: f.branchID.Hex()},
+			field:   "rows[2].password",
+			message: "must be at least 6 characters",
+		},
+		{
+			name:    "password too long",
+			row:     user.ImportRow{Line: 2, Username: "a", Email: "a@example.com", Password: strings.Repeat("p", 73), Branch: f.branchID.Hex()},
+			field:   "rows[2].password",
+			message: "must be at most 72 characters",
+		},
+		{
+			name:    "password over bcrypt limit in bytes",
+			row:     user.ImportRow{Line: 2, Username: "a", Email: "a@example.com", Password: strings.Repeat("ậ", 30), Branch: f.branchID.Hex()},
+			field:   "rows[2].password",
+			message: "must be at most 72 characters",
+		},
+		{
+			name:    "invalid role",
+			row:     user.ImportRow{Line: 2, Username: "a", Email: "a@example.com", Role: "boss", Branch: f.branchID.Hex()},
+			field:   "rows[2].role",
+			message: "is not a valid role",
+		},
+		{
+			name:    "missing branch and department",
+			row:     user.ImportRow{Line: 2, Username: "a", Email: "a@example.com"},
+			field:   "rows[2].branch",
+			message: "branch or department is required",
+		},
+		{
+			name:    "branch not in tree",
+			row:     user.ImportRow{Line: 2, Username: "a", Email: "a@example.com", Branch: primitive.NewObjectID().Hex()},
+			field:   "rows[2].branch",
+			message: "does not exist in the organization tree",
+		},
+		{
+			name:    "branch name not found",
+			row:     user.ImportRow{Line: 2, Username: "a", Email: "a@example.com", Branch: "Chi nhánh không có"},
+			field:   "rows[2].branch",
+			message: "is not found in your scope",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRepository{}
+			uc := f.usecase(repo)
+
+			_, err := uc.Import(context.Background(), f.sc, user.ImportInput{Rows: []user.ImportRow{tt.row}})
+
+			fields := validationFields(t, err)
+			msgs, ok := fields[tt.field]
+			if !ok {
+				t.Fatalf("Mong đợi lỗi ở %s, nhận được %v", tt.field, fields)
+			}
+			if msgs[0] != tt.message {
+				t.Errorf("Mong đợi message %q, nhận được %q", tt.message, msgs[0])
+			}
+		})
+	}
+
+	t.Run("role above importer", func(t *testing.T) {
+		sc := models.Scope{UserID: "bm-1", Role: models.RoleBranchManager, ShopID: &f.shopID, BranchID: &f.branchID}
+		row := f.row(2, "a")
+		row.Role = string(models.RoleManager)
+
+		_, err := f.usecase(&mockRepository{}).Import(context.Background(), sc, user.ImportInput{Rows: []user.ImportRow{row}})
+
+		fields := validationFields(t, err)
+		if _, ok := fields["rows[2].role"]; !ok {
+			t.Errorf("Mong đợi lỗi ở rows[2].role, nhận được %v", fields)
+		}
+	})
+}
+
+func TestImportDuplicateRows(t *testing.T) {
+	f := newImportFixture()
+
+	t.Run("duplicated in file", func(t *testing.T) {
+		rows := []user.ImportRow{f.row(2, "alice"), f.row(3, "bob"), f.row(4, "alice")}
+
+		_, err := f.usecase(&mockRepository{}).Import(context.Background(), f.sc, user.ImportInput{Rows: rows})
+
+		fields := validationFields(t, err)
+		if got := fields["rows[4].username"]; len(got) != 1 || got[0] != "is duplicated with line 2" {
+			t.Errorf("Mong đợi lỗi trùng với dòng 2, nhận được %v", got)
+		}
+		if _, ok := fields["rows[2].username"]; ok {
+			t.Errorf("Dòng xuất hiện đầu tiên không được báo lỗi")
+		}
+	})
+
+	t.Run("already exists in database", func(t *testing.T) {
+		repo := &mockRepository{
+			existingUsernamesFunc: func(ctx context.Context, usernames []string) ([]string, error) {
+				return []string{"bob"}, nil
+			},
+		}
+
+		_, err := f.usecase(repo).Import(context.Background(), f.sc, user.ImportInput{Rows: []user.ImportRow{f.row(2, "alice"), f.row(3, "bob")}})
+
+		fields := validationFields(t, err)
+		if got := fields["rows[3].username"]; len(got) != 1 || got[0] != "already exists" {
+			t.Errorf("Mong đợi lỗi already exists, nhận được %v", got)
+		}
+	})
+}
+
+func TestImportDryRunAndCommit(t *testing.T) {
+	f := newImportFixture()
+
+	t.Run("dry run does not insert or reveal passwords", func(t *testing.T) {
+		bad := f.row(3, "bob")
+		bad.Email = "bob"
+		repo := &mockRepository{
+			createManyFunc: func(ctx context.Context, sc models.Scope, opts []user.CreateOptions) ([]models.User, error) {
+				t.Fatal("Dry-run không được gọi CreateMany")
+				return nil, nil
+			},
+		}
+
+		job, err := f.usecase(repo).Import(context.Background(), f.sc, user.ImportInput{
+			Rows:   []user.ImportRow{f.row(2, "alice"), bad},
+			DryRun: true,
+		})
+
+		if err != nil {
+			t.Fatalf("Dry-run trả về lỗi theo dòng trong job, không trả error: %v", err)
+		}
+		if job.Status != models.ImportJobCompleted {
+			t.Errorf("Mong đợi status completed, nhận được %s", job.Status)
+		}
+		if len(job.Users) != 1 || job.Users[0].Username != "alice" || !job.Users[0].UserID.IsZero() {
+			t.Errorf("Mong đợi 1 user hợp lệ chưa có ID, nhận được %+v", job.Users)
+		}
+		if job.Users[0].TemporaryPassword != "" {
+			t.Errorf("Dry-run không được trả mật khẩu tạm")
+		}
+		if len(job.Errors) != 1 || job.Errors[0].Field != "rows[3].email" {
+			t.Errorf("Mong đợi lỗi ở rows[3].email, nhận được %+v", job.Errors)
+		}
+	})
+
+	t.Run("commit returns temporary passwords without saving the job", func(t *testing.T) {
+		withPassword := f.row(3, "bob")
+		withPassword.Password = "Secret123"
+		repo := &mockRepository{
+			createImportJobFunc: func(ctx context.Context, job models.ImportJob) (models.ImportJob, error) {
+				t.Fatal("Import chạy ngay không được lưu job")
+				return job, nil
+			},
+			updateImportJobFunc: func(ctx context.Context, job models.ImportJob) error {
+				t.Fatal("Import chạy ngay không được lưu job")
+				return nil
+			},
+		}
+
+		job, err := f.usecase(repo).Import(context.Background(), f.sc, user.ImportInput{Rows: []user.ImportRow{f.row(2, "alice"), withPassword}})
+
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if job.Created != 2 || len(job.Users) != 2 {
+			t.Fatalf("Mong đợi tạo 2 user, nhận được %d", job.Created)
+		}
+		if len(job.Users[0].TemporaryPassword) != tempPasswordLength {
+			t.Errorf("Mong đợi mật khẩu tạm cho dòng không có password, nhận được %q", job.Users[0].TemporaryPassword)
+		}
+		if job.Users[1].TemporaryPassword != "" {
+			t.Errorf("Dòng có password không được trả mật khẩu tạm")
+		}
+	})
+
+	t.Run("commit with row errors creates nothing", func(t *testing.T) {
+		bad := f.row(3, "bob")
+		bad.Email = ""
+		called := false
+		repo := &mockRepository{
+			createManyFunc: func(ctx context.Context, sc models.Scope, opts []user.CreateOptions) ([]models.User, error) {
+				called = true
+				return nil, nil
+			},
+		}
+
+		_, err := f.usecase(repo).Import(context.Background(), f.sc, user.ImportInput{Rows: []user.ImportRow{f.row(2, "alice"), bad}})
+
+		if _, ok := validationFields(t, err)["rows[3].email"]; !ok {
+			t.Errorf("Mong đợi lỗi ở rows[3].email")
+		}
+		if called {
+			t.Errorf("Không user nào được tạo khi có dòng lỗi")
+		}
+	})
+}
+
+func TestImportSyncThreshold(t *testing.T) {
+	f := newImportFixture()
+
+	rowsOf := func(n int) []user.ImportRow {
+		rows := make([]user.ImportRow, 0, n)
+		for i := 0; i < n; i++ {
+			rows = append(rows, f.row(i+2, fmt.Sprintf("user%d", i)))
+		}
+		return rows
+	}
+
+	t.Run("at limit runs in request", func(t *testing.T) {
+		repo := &mockRepository{
+			createImportJobFunc: func(ctx context.Context, job models.ImportJob) (models.ImportJob, error) {
+				t.Fatal("File nhỏ không được tạo job nền")
+				return job, nil
+			},
+		}
+
+		job, err := f.usecase(repo).Import(context.Background(), f.sc, user.ImportInput{Rows: rowsOf(importSyncLimit), DryRun: true})
+
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if job.Status != models.ImportJobCompleted || job.Processed != importSyncLimit {
+			t.Errorf("Mong đợi job đã xong với %d dòng, nhận được %s/%d", importSyncLimit, job.Status, job.Processed)
+		}
+	})
+
+	t.Run("over limit runs in background", func(t *testing.T) {
+		saved := make(chan models.ImportJob, 1)
+		repo := &mockRepository{
+			updateImportJobFunc: func(ctx context.Context, job models.ImportJob) error {
+				saved <- job
+				return nil
+			},
+		}
+
+		job, err := f.usecase(repo).Import(context.Background(), f.sc, user.ImportInput{Rows: rowsOf(importSyncLimit + 1), DryRun: true})
+
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if job.ID.IsZero() || job.Status != models.ImportJobPending {
+			t.Errorf("Mong đợi job pending đã lưu, nhận được %+v", job)
+		}
+
+		select {
+		case done := <-saved:
+			if done.Status != models.ImportJobCompleted || done.Processed != importSyncLimit+1 {
+				t.Errorf("Mong đợi job nền xong với %d dòng, nhận được %s/%d", importSyncLimit+1, done.Status, done.Processed)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Job nền không ghi kết quả")
+		}
+	})
+}
+
+func TestImportJobTempPasswords(t *testing.T) {
+	f := newImportFixture()
+	finished := time.Now()
+	job := models.ImportJob{
+		ID:         primitive.NewObjectID(),
+		CreatedBy:  f.sc.UserID,
+		Status:     models.ImportJobCompleted,
+		FinishedAt: &finished,
+		Users: []models.ImportedUser{
+			{Line: 2, Username: "alice", TemporaryPassword: "Plain1234567"},
+			{Line: 3, Username: "bob"},
+		},
+	}
+
+	t.Run("stored encrypted and returned decrypted once", func(t *testing.T) {
+		var stored models.ImportJob
+		cleared := false
+		repo := &mockRepository{
+			updateImportJobFunc: func(ctx context.Context, j models.ImportJob) error {
+				stored = j
+				return nil
+			},
+			getImportJobFunc: func(ctx context.Context, id primitive.ObjectID) (models.ImportJob, error) {
+				return stored, nil
+			},
+			clearImportJobPasswordsFunc: func(ctx context.Context, id primitive.ObjectID) error {
+				cleared = true
+				return nil
+			},
+		}
+		uc := f.usecase(repo)
+
+		uc.saveImportJob(context.Background(), job)
+
+		if pw := stored.Users[0].TemporaryPassword; pw == "" || pw == "Plain1234567" {
+			t.Fatalf("Mật khẩu tạm phải được lưu ở dạng mã hóa, nhận được %q", pw)
+		}
+		if job.Users[0].TemporaryPassword != "Plain1234567" {
+			t.Errorf("saveImportJob không được sửa slice của job gốc")
+		}
+
+		got, err := uc.GetImportJob(context.Background(), f.sc, job.ID)
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if got.Users[0].TemporaryPassword != "Plain1234567" {
+			t.Errorf("Mong đợi mật khẩu đã giải mã, nhận được %q", got.Users[0].TemporaryPassword)
+		}
+		if !cleared {
+			t.Errorf("Mong đợi mật khẩu tạm bị xóa khỏi job sau lần xem đầu tiên")
+		}
+	})
+
+	t.Run("dropped without encrypter", func(t *testing.T) {
+		var stored models.ImportJob
+		repo := &mockRepository{
+			updateImportJobFunc: func(ctx context.Context, j models.ImportJob) error {
+				stored = j
+				return nil
+			},
+		}
+		uc := f.usecase(repo)
+		uc.encrypter = nil
+
+		uc.saveImportJob(context.Background(), job)
+
+		if stored.Users[0].TemporaryPassword != "" {
+			t.Errorf("Không có encrypter thì không được lưu mật khẩu tạm, nhận được %q", stored.Users[0].TemporaryPassword)
+		}
+	})
+
+	t.Run("other user cannot read job", func(t *testing.T) {
+		repo := &mockRepository{
+			getImportJobFunc: func(ctx context.Context, id primitive.ObjectID) (models.ImportJob, error) {
+				return job, nil
+			},
+		}
+		other := f.sc
+		other.UserID = "manager-2"
+
+		_, err := f.usecase(repo).GetImportJob(context.Background(), other, job.ID)
+
+		if !errors.Is(err, user.ErrImportJobNotFound) {
+			t.Errorf("Mong đợi ErrImportJobNotFound, nhận được %v", err)
+		}
+	})
+}
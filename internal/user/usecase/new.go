@@ -3,21 +3,29 @@ package usecase
 import (
 	"thuchanhgolang/internal/branch"
 	"thuchanhgolang/internal/department"
+	"thuchanhgolang/internal/history"
 	"thuchanhgolang/internal/region"
 	"thuchanhgolang/internal/user"
 	"thuchanhgolang/internal/user/repository/query"
+	"thuchanhgolang/pkg/encrypter"
+	"thuchanhgolang/pkg/event"
 	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/mongo"
 )
 
 // implUsecase là implementation của user.Usecase
 type implUsecase struct {
-	l            log.Logger      // Logger
-	repo         user.Repository // User repository
-	queryService query.Service   // Query service (để lấy parent IDs)
+	l            log.Logger          // Logger
+	repo         user.Repository     // User repository
+	queryService query.Service       // Query service (để lấy parent IDs)
+	history      history.Repository  // Đọc lịch sử thay đổi
+	tx           mongo.Transactor    // Transaction cho các thao tác nhiều bước
+	publisher    event.Publisher     // Ghi event vào outbox khi user thay đổi
+	encrypter    encrypter.Encrypter // Mã hóa mật khẩu tạm của job import chạy nền trước khi lưu
 }
 
 // NewUsecase tạo user usecase mới
-func NewUsecase(l log.Logger, repo user.Repository, branchRepo branch.Repository, deptRepo department.Repository, regionRepo region.Repository) user.Usecase {
+func NewUsecase(l log.Logger, repo user.Repository, branchRepo branch.Repository, deptRepo department.Repository, regionRepo region.Repository, historyRepo history.Repository, tx mongo.Transactor, publisher event.Publisher, enc encrypter.Encrypter) user.Usecase {
 	// Tạo query service
 	queryService := query.NewService(l, branchRepo, deptRepo, regionRepo)
 
@@ -25,5 +33,9 @@ func NewUsecase(l log.Logger, repo user.Repository, branchRepo branch.Repository
 		l:            l,
 		repo:         repo,
 		queryService: queryService,
+		history:      historyRepo,
+		tx:           tx,
+		publisher:    publisher,
+		encrypter:    enc,
 	}
 }
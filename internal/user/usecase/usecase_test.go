@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/history"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/user"
+	"thuchanhgolang/internal/user/repository/query"
+	"thuchanhgolang/pkg/mongo"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// mockRepository là mock repository cho testing
+type mockRepository struct {
+	createFunc                  func(context.Context, models.Scope, user.CreateOptions) (models.User, error)
+	getByIDFunc                 func(context.Context, models.Scope, primitive.ObjectID) (models.User, error)
+	updateFunc                  func(context.Context, models.Scope, user.UpdateOptions) (models.User, error)
+	deleteFunc                  func(context.Context, models.Scope, primitive.ObjectID, int) error
+	createManyFunc              func(context.Context, models.Scope, []user.CreateOptions) ([]models.User, error)
+	existingUsernamesFunc       func(context.Context, []string) ([]string, error)
+	findBranchesByNameFunc      func(context.Context, string) ([]models.Branch, error)
+	findDepartmentsByNameFunc   func(context.Context, string) ([]models.Department, error)
+	createImportJobFunc         func(context.Context, models.ImportJob) (models.ImportJob, error)
+	updateImportJobFunc         func(context.Context, models.ImportJob) error
+	getImportJobFunc            func(context.Context, primitive.ObjectID) (models.ImportJob, error)
+	clearImportJobPasswordsFunc func(context.Context, primitive.ObjectID) error
+}
+
+func (m *mockRepository) Register(ctx context.Context, opts user.RegisterOptions) (models.User, error) {
+	return models.User{}, nil
+}
+
+func (m *mockRepository) Create(ctx context.Context, sc models.Scope, opts user.CreateOptions) (models.User, error) {
+	if m.createFunc != nil {
+		return m.createFunc(ctx, sc, opts)
+	}
+	return models.User{}, nil
+}
+
+func (m *mockRepository) GetByID(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.User, error) {
+	if m.getByIDFunc != nil {
+		return m.getByIDFunc(ctx, sc, id)
+	}
+	return models.User{}, nil
+}
+
+func (m *mockRepository) List(ctx context.Context, sc models.Scope, opts user.ListOptions) ([]models.User, paginator.Paginator, error) {
+	return nil, paginator.Paginator{}, nil
+}
+
+func (m *mockRepository) GetByUsername(ctx context.Context, username string) (models.User, error) {
+	return models.User{}, nil
+}
+
+func (m *mockRepository) Update(ctx context.Context, sc models.Scope, opts user.UpdateOptions) (models.User, error) {
+	if m.updateFunc != nil {
+		return m.updateFunc(ctx, sc, opts)
+	}
+	return models.User{}, nil
+}
+
+func (m *mockRepository) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(ctx, sc, id, version)
+	}
+	return nil
+}
+
+func (m *mockRepository) CreateMany(ctx context.Context, sc models.Scope, opts []user.CreateOptions) ([]models.User, error) {
+	if m.createManyFunc != nil {
+		return m.createManyFunc(ctx, sc, opts)
+	}
+	users := make([]models.User, 0, len(opts))
+	for _, o := range opts {
+		users = append(users, models.User{ID: primitive.NewObjectID(), Username: o.Username, Email: o.Email})
+	}
+	return users, nil
+}
+
+func (m *mockRepository) ExistingUsernames(ctx context.Context, usernames []string) ([]string, error) {
+	if m.existingUsernamesFunc != nil {
+		return m.existingUsernamesFunc(ctx, usernames)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) FindBranchesByName(ctx context.Context, name string) ([]models.Branch, error) {
+	if m.findBranchesByNameFunc != nil {
+		return m.findBranchesByNameFunc(ctx, name)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) FindDepartmentsByName(ctx context.Context, name string) ([]models.Department, error) {
+	if m.findDepartmentsByNameFunc != nil {
+		return m.findDepartmentsByNameFunc(ctx, name)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) CreateImportJob(ctx context.Context, job models.ImportJob) (models.ImportJob, error) {
+	if m.createImportJobFunc != nil {
+		return m.createImportJobFunc(ctx, job)
+	}
+	job.ID = primitive.NewObjectID()
+	return job, nil
+}
+
+func (m *mockRepository) UpdateImportJob(ctx context.Context, job models.ImportJob) error {
+	if m.updateImportJobFunc != nil {
+		return m.updateImportJobFunc(ctx, job)
+	}
+	return nil
+}
+
+func (m *mockRepository) UpdateImportJobProgress(ctx context.Context, id primitive.ObjectID, processed int) error {
+	return nil
+}
+
+func (m *mockRepository) GetImportJob(ctx context.Context, id primitive.ObjectID) (models.ImportJob, error) {
+	if m.getImportJobFunc != nil {
+		return m.getImportJobFunc(ctx, id)
+	}
+	return models.ImportJob{}, nil
+}
+
+func (m *mockRepository) ClearImportJobPasswords(ctx context.Context, id primitive.ObjectID) error {
+	if m.clearImportJobPasswordsFunc != nil {
+		return m.clearImportJobPasswordsFunc(ctx, id)
+	}
+	return nil
+}
+
+// mockQueryService giả lập query.Service bằng một cây tổ chức cố định
+type mockQueryService struct {
+	branches    map[primitive.ObjectID]query.CascadeResult
+	departments map[primitive.ObjectID]query.CascadeResult
+	err         error // Lỗi trả về cho mọi lời gọi (nếu khác nil)
+}
+
+func (m *mockQueryService) ResolveFromDepartment(ctx context.Context, sc models.Scope, departmentID primitive.ObjectID) (*query.CascadeResult, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	res, ok := m.departments[departmentID]
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+	return &res, nil
+}
+
+func (m *mockQueryService) ResolveFromBranch(ctx context.Context, sc models.Scope, branchID primitive.ObjectID) (*query.CascadeResult, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	res, ok := m.branches[branchID]
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+	return &res, nil
+}
+
+type mockHistoryRepository struct {
+	listFunc    func(context.Context, history.ListOptions) ([]models.Revision, paginator.Paginator, error)
+	getAsOfFunc func(context.Context, history.GetAsOfOptions) (models.Revision, error)
+}
+
+func (m *mockHistoryRepository) List(ctx context.Context, opts history.ListOptions) ([]models.Revision, paginator.Paginator, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, opts)
+	}
+	return nil, paginator.Paginator{}, nil
+}
+
+func (m *mockHistoryRepository) GetAsOf(ctx context.Context, opts history.GetAsOfOptions) (models.Revision, error) {
+	if m.getAsOfFunc != nil {
+		return m.getAsOfFunc(ctx, opts)
+	}
+	return models.Revision{}, mongo.ErrNoDocuments
+}
+
+type mockTransactor struct {
+	calls int
+}
+
+func (m *mockTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	return fn(ctx)
+}
+
+// mockLogger là mock logger cho testing
+type mockLogger struct{}
+
+func (m *mockLogger) Debug(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Debugf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Info(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Infof(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Warn(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Warnf(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Error(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Errorf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Fatal(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Fatalf(ctx context.Context, template string, arg ...any) {}
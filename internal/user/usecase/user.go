@@ -2,10 +2,15 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"thuchanhgolang/internal/models"
 	"thuchanhgolang/internal/user"
+	pkgErrors "thuchanhgolang/pkg/errors"
+	"thuchanhgolang/pkg/mongo"
+	"thuchanhgolang/pkg/paginator"
+	"thuchanhgolang/pkg/patch"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/crypto/bcrypt"
@@ -24,69 +29,54 @@ func (uc *implUsecase) Register(ctx context.Context, input user.RegisterInput) (
 		return models.User{}, fmt.Errorf("email is required")
 	}
 
-	// 2. Kiểm tra username đã tồn tại chưa
-	existingUser, err := uc.repo.GetByUsername(ctx, input.Username)
-	if err == nil && existingUser.ID != primitive.NilObjectID {
-		return models.User{}, fmt.Errorf("username already exists")
-	}
-
-	// 3. Hash password
+	// 2. Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
 	if err != nil {
 		uc.l.Errorf(ctx, "user.usecase.Register.bcrypt.GenerateFromPassword: %v", err)
 		return models.User{}, fmt.Errorf("failed to hash password")
 	}
 
-	// 4. Tạo RegisterOptions
+	// 3. Tạo RegisterOptions
 	opts := user.RegisterOptions{
 		Username: input.Username,
 		Password: string(hashedPassword),
 		Email:    input.Email,
 	}
 
-	// 5. Gọi repository để lưu vào database
-	newUser, err := uc.repo.Register(ctx, opts)
+	// 4. Gọi repository để lưu vào database, username trùng bị chặn bởi unique index.
+	// Event được ghi vào outbox trong cùng transaction, người đăng ký chưa có scope nên không có actor
+	var newUser models.User
+	err = uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		newUser, err = uc.repo.Register(ctx, opts)
+		if err != nil {
+			uc.l.Errorf(ctx, "user.usecase.Register.repo.Register: %v", err)
+			return err
+		}
+		if err := uc.publisher.Publish(ctx, newEvent(user.EventUserCreated, models.Scope{}, newUser, user.Payload(newUser))); err != nil {
+			uc.l.Errorf(ctx, "user.usecase.Register.publisher.Publish: %v", err)
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		uc.l.Errorf(ctx, "user.usecase.Register.repo.Register: %v", err)
-		return models.User{}, err
+		return models.User{}, uniqueError(err)
 	}
 
 	return newUser, nil
 }
 
 // Create tạo user mới
+// Hierarchy được suy ra từ department_id hoặc branch_id, các ID gửi kèm phải khớp và nằm trong scope
 func (uc *implUsecase) Create(ctx context.Context, sc models.Scope, input user.CreateInput) (models.User, error) {
-	var shopID, regionID, branchID primitive.ObjectID
-	var departmentID *primitive.ObjectID
-
-	// TH1: Có department_id → cascade query: Department → Branch → Region
-	if input.DepartmentID != nil {
-		result, err := uc.queryService.ResolveFromDepartment(ctx, sc, *input.DepartmentID)
-		if err != nil {
-			return models.User{}, err
-		}
-		shopID = result.ShopID
-		regionID = result.RegionID
-		branchID = result.BranchID
-		departmentID = result.DepartmentID
-
-	} else if input.BranchID != primitive.NilObjectID {
-		// TH2: Có branch_id (không có department) → cascade query: Branch → Region
-		result, err := uc.queryService.ResolveFromBranch(ctx, sc, input.BranchID)
-		if err != nil {
-			return models.User{}, err
-		}
-		shopID = result.ShopID
-		regionID = result.RegionID
-		branchID = result.BranchID
-		departmentID = result.DepartmentID
-
-	} else {
-		// TH3: Fallback - sử dụng các ID được cung cấp trực tiếp (backward compatibility)
-		shopID = input.ShopID
-		regionID = input.RegionID
-		branchID = input.BranchID
-		departmentID = input.DepartmentID
+	h, err := uc.resolveHierarchy(ctx, sc, hierarchyInput{
+		ShopID:       optionalID(input.ShopID),
+		RegionID:     optionalID(input.RegionID),
+		BranchID:     optionalID(input.BranchID),
+		DepartmentID: input.DepartmentID,
+	})
+	if err != nil {
+		return models.User{}, err
 	}
 
 	// Hash password trước khi lưu
@@ -101,17 +91,29 @@ func (uc *implUsecase) Create(ctx context.Context, sc models.Scope, input user.C
 		Username:     input.Username,
 		Password:     string(hashedPassword), // Sử dụng password đã hash
 		Email:        input.Email,
-		ShopID:       shopID,
-		RegionID:     regionID,
-		BranchID:     branchID,
-		DepartmentID: departmentID,
+		ShopID:       h.ShopID,
+		RegionID:     h.RegionID,
+		BranchID:     h.BranchID,
+		DepartmentID: h.DepartmentID,
 	}
 
-	// Gọi repository để lưu vào database
-	newUser, err := uc.repo.Create(ctx, sc, opts)
+	// Lưu vào database và ghi event vào outbox trong cùng một transaction
+	var newUser models.User
+	err = uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		newUser, err = uc.repo.Create(ctx, sc, opts)
+		if err != nil {
+			uc.l.Errorf(ctx, "user.usecase.Create.repo.Create: %v", err)
+			return err
+		}
+		if err := uc.publisher.Publish(ctx, newEvent(user.EventUserCreated, sc, newUser, user.Payload(newUser))); err != nil {
+			uc.l.Errorf(ctx, "user.usecase.Create.publisher.Publish: %v", err)
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		uc.l.Errorf(ctx, "user.usecase.Create.repo.Create: %v", err)
-		return models.User{}, err
+		return models.User{}, uniqueError(err)
 	}
 
 	return newUser, nil
@@ -123,51 +125,120 @@ func (uc *implUsecase) GetByID(ctx context.Context, sc models.Scope, id primitiv
 	user, err := uc.repo.GetByID(ctx, sc, id)
 	if err != nil {
 		uc.l.Errorf(ctx, "user.usecase.GetByID.repo.GetByID: %v", err)
-		return models.User{}, err
+		return models.User{}, notFoundError(err)
 	}
 
 	return user, nil
 }
 
+// List lấy danh sách user trong scope của người gọi, có phân trang
+func (uc *implUsecase) List(ctx context.Context, sc models.Scope, input user.ListInput) (user.ListOutput, error) {
+	// Bước 1: Đưa page, limit về giá trị mặc định nếu không hợp lệ
+	input.Pagin.Adjust()
+	empty := user.ListOutput{Pagin: paginator.Paginator{PerPage: input.Pagin.Limit, CurrentPage: input.Pagin.Page}}
+
+	// Bước 2: Giới hạn bộ lọc theo scope của người gọi
+	opts, visible, err := listOptions(sc, input)
+	if err != nil {
+		uc.l.Warnf(ctx, "user.usecase.List.listOptions: %v", err)
+		return user.ListOutput{}, err
+	}
+	if !visible {
+		return empty, nil
+	}
+
+	// Bước 3: Gọi repository để lấy danh sách
+	users, pag, err := uc.repo.List(ctx, sc, opts)
+	if err != nil {
+		uc.l.Errorf(ctx, "user.usecase.List.repo.List: %v", err)
+		return user.ListOutput{}, err
+	}
+
+	return user.ListOutput{Users: users, Pagin: pag}, nil
+}
+
 // Update cập nhật thông tin user
 func (uc *implUsecase) Update(ctx context.Context, sc models.Scope, input user.UpdateInput) (models.User, error) {
-	// AUTO-RESOLVE parent IDs khi update department_id hoặc branch_id
-	var resolvedShopID, resolvedRegionID, resolvedBranchID *primitive.ObjectID
-	var resolvedDepartmentID *primitive.ObjectID
-
-	// TH1: Update department_id → cascade query để lấy tất cả parent IDs
-	if input.DepartmentID != nil {
-		result, err := uc.queryService.ResolveFromDepartment(ctx, sc, *input.DepartmentID)
+	// Resolve parent IDs và update chạy trong cùng một transaction,
+	// tránh trường hợp department/branch bị di chuyển hoặc xóa giữa hai bước
+	var updatedUser models.User
+	err := uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		u, err := uc.update(ctx, sc, input)
 		if err != nil {
-			return models.User{}, err
+			return err
 		}
-		resolvedShopID = &result.ShopID
-		resolvedRegionID = &result.RegionID
-		resolvedBranchID = &result.BranchID
-		resolvedDepartmentID = result.DepartmentID
+		updatedUser = u
+		return nil
+	})
+	if err != nil {
+		return models.User{}, err
+	}
 
-	} else if input.BranchID != nil && *input.BranchID != primitive.NilObjectID {
-		// TH2: Update branch_id (không update department) → cascade query để lấy shop & region
-		result, err := uc.queryService.ResolveFromBranch(ctx, sc, *input.BranchID)
-		if err != nil {
-			return models.User{}, err
-		}
-		resolvedShopID = &result.ShopID
-		resolvedRegionID = &result.RegionID
-		resolvedBranchID = &result.BranchID
-		// Khi update branch → xóa department (user không còn thuộc dept nữa)
-		emptyID := primitive.NilObjectID
-		resolvedDepartmentID = &emptyID
+	return updatedUser, nil
+}
+
+// update kiểm tra scope, resolve parent IDs và gọi repository, ctx có thể mang session của transaction
+func (uc *implUsecase) update(ctx context.Context, sc models.Scope, input user.UpdateInput) (models.User, error) {
+	// Bước 1: User cần sửa phải nằm trong scope
+	current, err := uc.repo.GetByID(ctx, sc, input.ID)
+	if err != nil {
+		uc.l.Errorf(ctx, "user.usecase.Update.repo.GetByID: %v", err)
+		return models.User{}, notFoundError(err)
+	}
+	if !sc.Contains(current.Hierarchy()) {
+		uc.l.Warnf(ctx, "user.usecase.Update: user %s is outside of scope", input.ID.Hex())
+		return models.User{}, user.ErrUserOutOfScope
+	}
+	// Client đang sửa trên bản cũ: báo lỗi sớm, repository vẫn kiểm tra lại version khi update
+	if input.Version > 0 && current.Version != input.Version {
+		return models.User{}, user.ErrVersionMismatch
 	}
 
-	// Build options: Ưu tiên resolved IDs, fallback về input IDs
 	opts := user.UpdateOptions{
 		ID:       input.ID,
 		Username: input.Username,
 		Email:    input.Email,
+		Version:  input.Version,
+	}
+
+	// Bước 2: Đổi vị trí → resolve lại toàn bộ hierarchy từ department_id hoặc branch_id
+	if input.DepartmentID.Present || input.BranchID != nil {
+		branchID := input.BranchID
+		if input.DepartmentID.IsNull() && branchID == nil {
+			// Rời department, ở lại branch hiện tại
+			branchID = &current.BranchID
+		}
+		h, err := uc.resolveHierarchy(ctx, sc, hierarchyInput{
+			ShopID:       input.ShopID,
+			RegionID:     input.RegionID,
+			BranchID:     branchID,
+			DepartmentID: input.DepartmentID.Ptr(),
+		})
+		if err != nil {
+			return models.User{}, err
+		}
+
+		opts.ShopID = &h.ShopID
+		opts.RegionID = &h.RegionID
+		opts.BranchID = &h.BranchID
+		opts.DepartmentID = patch.FromPtr(h.DepartmentID)
+		if h.DepartmentID == nil {
+			// Chỉ đổi branch → user không còn thuộc department cũ
+			opts.DepartmentID = patch.Null[primitive.ObjectID]()
+		}
+	} else if input.ShopID != nil || input.RegionID != nil {
+		// shop_id/region_id không được sửa riêng lẻ vì luôn suy ra từ branch
+		collector := pkgErrors.NewValidationErrorCollector()
+		if input.ShopID != nil {
+			collector.Add(pkgErrors.NewValidationError("shop_id", "cannot be changed without branch_id or department_id"))
+		}
+		if input.RegionID != nil {
+			collector.Add(pkgErrors.NewValidationError("region_id", "cannot be changed without branch_id or department_id"))
+		}
+		return models.User{}, collector
 	}
 
-	// Hash password nếu có thay đổi
+	// Bước 3: Hash password nếu có thay đổi
 	if input.Password != nil && *input.Password != "" {
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(*input.Password), bcrypt.DefaultCost)
 		if err != nil {
@@ -178,35 +249,16 @@ func (uc *implUsecase) Update(ctx context.Context, sc models.Scope, input user.U
 		opts.Password = &hashedStr
 	}
 
-	// Sử dụng resolved IDs nếu có, không thì dùng input IDs
-	if resolvedShopID != nil {
-		opts.ShopID = resolvedShopID
-	} else {
-		opts.ShopID = input.ShopID
-	}
-
-	if resolvedRegionID != nil {
-		opts.RegionID = resolvedRegionID
-	} else {
-		opts.RegionID = input.RegionID
-	}
-
-	if resolvedBranchID != nil {
-		opts.BranchID = resolvedBranchID
-	} else {
-		opts.BranchID = input.BranchID
-	}
-
-	if resolvedDepartmentID != nil {
-		opts.DepartmentID = resolvedDepartmentID
-	} else {
-		opts.DepartmentID = input.DepartmentID
-	}
-
-	// Gọi repository để update
+	// Bước 4: Gọi repository để update
 	updatedUser, err := uc.repo.Update(ctx, sc, opts)
 	if err != nil {
 		uc.l.Errorf(ctx, "user.usecase.Update.repo.Update: %v", err)
+		return models.User{}, notFoundError(uniqueError(err))
+	}
+
+	// Bước 5: Ghi event vào outbox (cập nhật, chuyển vị trí, đổi role)
+	if err := uc.publisher.Publish(ctx, updateEvents(sc, current, updatedUser)...); err != nil {
+		uc.l.Errorf(ctx, "user.usecase.Update.publisher.Publish: %v", err)
 		return models.User{}, err
 	}
 
@@ -214,13 +266,93 @@ func (uc *implUsecase) Update(ctx context.Context, sc models.Scope, input user.U
 }
 
 // Delete xóa user
-func (uc *implUsecase) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID) error {
-	// Gọi repository để xóa user
-	err := uc.repo.Delete(ctx, sc, id)
-	if err != nil {
-		uc.l.Errorf(ctx, "user.usecase.Delete.repo.Delete: %v", err)
+func (uc *implUsecase) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
+	// Xóa và ghi event vào outbox trong cùng một transaction
+	return uc.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		// Lấy user trước khi xóa để kiểm tra scope và để event mang vị trí của user
+		u, err := uc.repo.GetByID(ctx, sc, id)
+		if err != nil {
+			uc.l.Errorf(ctx, "user.usecase.Delete.repo.GetByID: %v", err)
+			return notFoundError(err)
+		}
+		if !sc.Contains(u.Hierarchy()) {
+			uc.l.Warnf(ctx, "user.usecase.Delete: user %s is outside of scope", id.Hex())
+			return user.ErrUserOutOfScope
+		}
+
+		err = uc.repo.Delete(ctx, sc, id, version)
+		if err != nil {
+			uc.l.Errorf(ctx, "user.usecase.Delete.repo.Delete: %v", err)
+			return notFoundError(err)
+		}
+
+		if err := uc.publisher.Publish(ctx, newEvent(user.EventUserDeleted, sc, u, nil)); err != nil {
+			uc.l.Errorf(ctx, "user.usecase.Delete.publisher.Publish: %v", err)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// uniqueError chuyển lỗi vi phạm unique index thành lỗi domain tương ứng
+func uniqueError(err error) error {
+	dup, ok := mongo.AsDuplicateKey(err)
+	if !ok {
 		return err
 	}
+	switch dup.Field() {
+	case "username":
+		return user.ErrUsernameExists
+	case "email":
+		return user.ErrEmailExists
+	}
+	return err
+}
+
+// notFoundError chuyển lỗi không tìm thấy document thành lỗi domain
+func notFoundError(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return user.ErrUserNotFound
+	}
+	return err
+}
+
+// listOptions kết hợp scope của người gọi với bộ lọc của client.
+// visible = false khi scope không thấy được user nào (role không hợp lệ hoặc thiếu ID).
+func listOptions(sc models.Scope, input user.ListInput) (user.ListOptions, bool, error) {
+	opts := user.ListOptions{
+		Filter: input.Filter,
+		Pagin:  input.Pagin,
+	}
+
+	switch sc.Role {
+	case models.RoleManager:
+		opts.ShopID = sc.ShopID
+	case models.RoleRegionManager:
+		opts.ShopID, opts.RegionID = sc.ShopID, sc.RegionID
+	case models.RoleBranchManager, models.RoleEmployee:
+		opts.ShopID, opts.BranchID = sc.ShopID, sc.BranchID
+	case models.RoleHeadOfDepartment:
+		opts.DepartmentID = sc.DepartmentID
+	}
+	if opts.ShopID == nil && opts.DepartmentID == nil {
+		return user.ListOptions{}, false, nil
+	}
+
+	// Bộ lọc của client chỉ được thu hẹp, không được mở rộng scope
+	if input.BranchID != nil {
+		if opts.BranchID != nil && *opts.BranchID != *input.BranchID {
+			return user.ListOptions{}, false, user.ErrUserOutOfScope
+		}
+		opts.BranchID = input.BranchID
+	}
+	if input.DepartmentID != nil {
+		if opts.DepartmentID != nil && *opts.DepartmentID != *input.DepartmentID {
+			return user.ListOptions{}, false, user.ErrUserOutOfScope
+		}
+		opts.DepartmentID = input.DepartmentID
+	}
 
-	return nil
+	return opts, true, nil
 }
@@ -0,0 +1,37 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"thuchanhgolang/internal/webhook"
+	pkgErrors "thuchanhgolang/pkg/errors"
+)
+
+var (
+	errWrongBody            = pkgErrors.NewHTTPError(70000, "Wrong body")
+	errInvalidID            = pkgErrors.NewHTTPError(70001, "Invalid webhook ID")
+	errInvalidDeliveryID    = pkgErrors.NewHTTPError(70002, "Invalid delivery ID")
+	errWebhookNotFound      = pkgErrors.NewHTTPErrorWithStatus(70003, "Webhook not found", http.StatusNotFound)
+	errDeliveryNotFound     = pkgErrors.NewHTTPErrorWithStatus(70004, "Webhook delivery not found", http.StatusNotFound)
+	errVersionMismatch      = pkgErrors.NewHTTPErrorWithStatus(70005, "Webhook has been modified by another request, reload and try again", http.StatusPreconditionFailed)
+	errPreconditionRequired = pkgErrors.NewHTTPErrorWithStatus(70006, "If-Match header with the webhook ETag is required", http.StatusPreconditionRequired)
+	errWrongQuery           = pkgErrors.NewHTTPError(70007, "Wrong query")
+	errShopRequired         = pkgErrors.NewHTTPErrorWithStatus(70008, "Webhooks belong to a shop, your account has no shop", http.StatusForbidden)
+	errUnauthorized         = pkgErrors.NewUnauthorizedHTTPError()
+)
+
+func (h handler) mapError(err error) error {
+	if errors.Is(err, webhook.ErrWebhookNotFound) {
+		return errWebhookNotFound
+	}
+	if errors.Is(err, webhook.ErrDeliveryNotFound) {
+		return errDeliveryNotFound
+	}
+	if errors.Is(err, webhook.ErrVersionMismatch) {
+		return errVersionMismatch
+	}
+	if errors.Is(err, webhook.ErrShopRequired) {
+		return errShopRequired
+	}
+	return err
+}
@@ -0,0 +1,274 @@
+package http
+
+import (
+	"thuchanhgolang/internal/webhook"
+	"thuchanhgolang/pkg/response"
+	"thuchanhgolang/pkg/util"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// create xử lý HTTP request để tạo webhook mới
+func (h handler) create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Xử lý và validate request
+	req, sc, err := h.processCreateRequest(c)
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.handler.create.processCreateRequest: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 2: Gọi usecase để tạo webhook
+	w, err := h.uc.Create(ctx, sc, req.toInput())
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.handler.create.uc.Create: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 3: Trả về kết quả kèm secret, đây là lần duy nhất client thấy secret
+	util.SetETag(c, w.Version)
+	response.OK(c, h.newCreateResp(w))
+}
+
+// getByID xử lý HTTP request để lấy webhook theo ID
+func (h handler) getByID(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Lấy ID từ URL param
+	id, err := h.processID(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// Bước 2: Lấy scope của user đang đăng nhập
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.handler.getByID.processScope: %s", err)
+		response.Error(c, err)
+		return
+	}
+
+	// Bước 3: Gọi usecase để lấy webhook
+	w, err := h.uc.GetByID(ctx, sc, id)
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.handler.getByID.uc.GetByID: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 4: Trả về kết quả
+	util.SetETag(c, w.Version)
+	response.OK(c, h.newDetailResp(w))
+}
+
+// list xử lý HTTP request để lấy danh sách webhook của shop
+func (h handler) list(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Xử lý query
+	req, sc, err := h.processListRequest(c)
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.handler.list.processListRequest: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 2: Gọi usecase để lấy danh sách webhook
+	out, err := h.uc.List(ctx, sc, req.toInput())
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.handler.list.uc.List: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 3: Trả về danh sách
+	response.OK(c, h.newListResp(out))
+}
+
+// update xử lý HTTP request để cập nhật webhook
+func (h handler) update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Lấy ID từ URL param
+	id, err := h.processID(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// Bước 2: Xử lý và validate request
+	req, sc, err := h.processUpdateRequest(c)
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.handler.update.processUpdateRequest: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 3: Gọi usecase để update webhook
+	w, err := h.uc.Update(ctx, sc, req.toInput(id))
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.handler.update.uc.Update: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 4: Trả về kết quả
+	util.SetETag(c, w.Version)
+	response.OK(c, h.newDetailResp(w))
+}
+
+// patch xử lý HTTP request cập nhật một phần webhook theo JSON Merge Patch
+func (h handler) patch(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Lấy ID từ URL param
+	id, err := h.processID(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// Bước 2: Xử lý và validate request
+	req, sc, err := h.processPatchRequest(c)
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.handler.patch.processPatchRequest: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 3: Gọi usecase để update webhook
+	w, err := h.uc.Update(ctx, sc, req.toInput(id))
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.handler.patch.uc.Update: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 4: Trả về kết quả
+	util.SetETag(c, w.Version)
+	response.OK(c, h.newDetailResp(w))
+}
+
+// delete xử lý HTTP request để xóa webhook
+func (h handler) delete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Lấy ID từ URL param
+	id, err := h.processID(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// Bước 2: Lấy scope của user đang đăng nhập
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.handler.delete.processScope: %s", err)
+		response.Error(c, err)
+		return
+	}
+
+	// Bước 3: Đọc version từ If-Match
+	version, err := h.processVersion(c)
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.handler.delete.processVersion: %s", err)
+		response.Error(c, err)
+		return
+	}
+
+	// Bước 4: Gọi usecase để xóa webhook
+	if err := h.uc.Delete(ctx, sc, id, version); err != nil {
+		h.l.Warnf(ctx, "webhook.handler.delete.uc.Delete: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 5: Trả về success
+	response.OK(c, gin.H{"message": "Webhook deleted successfully"})
+}
+
+// listDeliveries xử lý HTTP request để lấy delivery log của webhook
+func (h handler) listDeliveries(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Lấy ID từ URL param
+	id, err := h.processID(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// Bước 2: Xử lý query
+	req, sc, err := h.processListDeliveriesRequest(c)
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.handler.listDeliveries.processListDeliveriesRequest: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 3: Gọi usecase để lấy delivery log
+	out, err := h.uc.ListDeliveries(ctx, sc, req.toInput(id))
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.handler.listDeliveries.uc.ListDeliveries: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 4: Trả về delivery log, mới nhất đứng trước
+	response.OK(c, h.newListDeliveriesResp(out))
+}
+
+// redeliver xử lý HTTP request để gửi lại một delivery
+func (h handler) redeliver(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Lấy ID webhook và ID delivery từ URL param
+	id, err := h.processID(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	deliveryID, err := primitive.ObjectIDFromHex(c.Param("delivery_id"))
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.handler.redeliver.ObjectIDFromHex: %s", err)
+		response.Error(c, errInvalidDeliveryID)
+		return
+	}
+
+	// Bước 2: Lấy scope của user đang đăng nhập
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.handler.redeliver.processScope: %s", err)
+		response.Error(c, err)
+		return
+	}
+
+	// Bước 3: Gọi usecase để đưa delivery về hàng chờ
+	dl, err := h.uc.Redeliver(ctx, sc, webhook.RedeliverInput{WebhookID: id, DeliveryID: deliveryID})
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.handler.redeliver.uc.Redeliver: %s", err)
+		mapErr := h.mapError(err)
+		response.Error(c, mapErr)
+		return
+	}
+
+	// Bước 4: Trả về delivery đang chờ gửi lại
+	response.OK(c, h.newDeliveryResp(dl))
+}
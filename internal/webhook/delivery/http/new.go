@@ -0,0 +1,34 @@
+package http
+
+import (
+	"thuchanhgolang/internal/webhook"
+	"thuchanhgolang/pkg/log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler định nghĩa interface cho HTTP handler
+type Handler interface {
+	create(c *gin.Context)
+	getByID(c *gin.Context)
+	list(c *gin.Context)
+	update(c *gin.Context)
+	patch(c *gin.Context)
+	delete(c *gin.Context)
+	listDeliveries(c *gin.Context)
+	redeliver(c *gin.Context)
+}
+
+// handler là implementation của Handler interface
+type handler struct {
+	l  log.Logger      // Logger để ghi log
+	uc webhook.Usecase // Usecase để xử lý business logic
+}
+
+// New tạo HTTP handler mới cho webhook
+func New(l log.Logger, uc webhook.Usecase) Handler {
+	return handler{
+		l:  l,
+		uc: uc,
+	}
+}
@@ -0,0 +1,242 @@
+package http
+
+import (
+	"strings"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/webhook"
+	pkgErrors "thuchanhgolang/pkg/errors"
+	"thuchanhgolang/pkg/event"
+	"thuchanhgolang/pkg/paginator"
+	"thuchanhgolang/pkg/patch"
+	"thuchanhgolang/pkg/response"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// createReq là cấu trúc nhận dữ liệu từ HTTP request
+type createReq struct {
+	URL        string   `json:"url" binding:"required,notblank"`                    // URL nhận POST (bắt buộc)
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,notblank"` // Loại event cần nhận, "*" → mọi event (bắt buộc)
+	Secret     string   `json:"secret"`                                             // Khóa ký HMAC (optional, rỗng → sinh ngẫu nhiên)
+}
+
+// toInput chuyển đổi request thành input cho usecase
+func (r createReq) toInput() webhook.CreateInput {
+	return webhook.CreateInput{
+		URL:        strings.TrimSpace(r.URL),
+		EventTypes: r.EventTypes,
+		Secret:     r.Secret,
+	}
+}
+
+// updateReq là cấu trúc nhận dữ liệu update từ HTTP request
+type updateReq struct {
+	URL        *string  `json:"url" binding:"omitempty,notblank"`                    // URL mới (optional)
+	EventTypes []string `json:"event_types" binding:"omitempty,min=1,dive,notblank"` // Loại event mới (optional)
+	Secret     *string  `json:"secret"`                                              // Secret mới (optional)
+	Active     *bool    `json:"active"`                                              // Bật / tắt webhook (optional)
+
+	version int // Version lấy từ If-Match, không đọc từ body
+}
+
+// validate kiểm tra dữ liệu update, định dạng từng field đã được kiểm tra qua binding tag
+func (r updateReq) validate() error {
+	// Ít nhất 1 field phải có giá trị
+	if r.URL == nil && r.EventTypes == nil && r.Secret == nil && r.Active == nil {
+		return errWrongBody
+	}
+	return nil
+}
+
+// toInput chuyển đổi update request thành input cho usecase
+func (r updateReq) toInput(id primitive.ObjectID) webhook.UpdateInput {
+	return webhook.UpdateInput{
+		ID:         id,
+		URL:        r.URL,
+		EventTypes: r.EventTypes,
+		Secret:     r.Secret,
+		Active:     r.Active,
+		Version:    r.version,
+	}
+}
+
+// patchReq là JSON Merge Patch (RFC 7396) của webhook: field không gửi thì giữ nguyên, các field đều bắt buộc nên không nhận null
+type patchReq struct {
+	URL        patch.Field[string]   `json:"url" binding:"omitempty,notblank"`                    // URL mới (không được null)
+	EventTypes patch.Field[[]string] `json:"event_types" binding:"omitempty,min=1,dive,notblank"` // Loại event mới (không được null)
+	Secret     patch.Field[string]   `json:"secret"`                                              // Secret mới (không được null)
+	Active     patch.Field[bool]     `json:"active"`                                              // Bật / tắt webhook (không được null)
+
+	version int // Version lấy từ If-Match, không đọc từ body
+}
+
+// validate từ chối null cho field bắt buộc, định dạng từng field đã được kiểm tra qua binding tag
+func (r patchReq) validate() error {
+	// Ít nhất 1 field phải được gửi
+	if !r.URL.Present && !r.EventTypes.Present && !r.Secret.Present && !r.Active.Present {
+		return errWrongBody
+	}
+
+	collector := pkgErrors.NewValidationErrorCollector()
+	if r.URL.IsNull() {
+		collector.Add(pkgErrors.NewValidationError("url", "cannot be null"))
+	}
+	if r.EventTypes.IsNull() {
+		collector.Add(pkgErrors.NewValidationError("event_types", "cannot be null"))
+	}
+	if r.Secret.IsNull() {
+		collector.Add(pkgErrors.NewValidationError("secret", "cannot be null"))
+	}
+	if r.Active.IsNull() {
+		collector.Add(pkgErrors.NewValidationError("active", "cannot be null"))
+	}
+	if collector.HasError() {
+		return collector
+	}
+	return nil
+}
+
+// toInput chuyển đổi merge patch thành input cho usecase
+func (r patchReq) toInput(id primitive.ObjectID) webhook.UpdateInput {
+	return webhook.UpdateInput{
+		ID:         id,
+		URL:        r.URL.Ptr(),
+		EventTypes: r.EventTypes.Value, // nil khi không gửi
+		Secret:     r.Secret.Ptr(),
+		Active:     r.Active.Ptr(),
+		Version:    r.version,
+	}
+}
+
+// listReq là query lấy danh sách webhook
+type listReq struct {
+	paginator.PaginatorQuery
+}
+
+// toInput chuyển đổi query thành input cho usecase
+func (r listReq) toInput() webhook.ListInput {
+	return webhook.ListInput{Pagin: r.PaginatorQuery}
+}
+
+// listDeliveriesReq là query lấy delivery log của webhook
+type listDeliveriesReq struct {
+	paginator.PaginatorQuery
+	Status string `form:"status"` // pending | succeeded | dead (optional)
+}
+
+// toInput chuyển đổi query thành input cho usecase
+func (r listDeliveriesReq) toInput(id primitive.ObjectID) webhook.ListDeliveriesInput {
+	return webhook.ListDeliveriesInput{
+		WebhookID: id,
+		Status:    models.DeliveryStatus(r.Status),
+		Pagin:     r.PaginatorQuery,
+	}
+}
+
+// detailResp là cấu trúc trả về cho client, secret chỉ có khi vừa tạo
+type detailResp struct {
+	ID         string            `json:"id"`                   // ID của webhook
+	URL        string            `json:"url"`                  // URL nhận POST
+	EventTypes []string          `json:"event_types"`          // Loại event đăng ký
+	Active     bool              `json:"active"`               // Webhook đang bật
+	Secret     string            `json:"secret,omitempty"`     // Khóa ký HMAC, chỉ trả về khi tạo
+	CreatedAt  response.DateTime `json:"created_at"`           // Thời gian tạo
+	UpdatedAt  response.DateTime `json:"updated_at"`           // Thời gian cập nhật gần nhất
+	CreatedBy  string            `json:"created_by,omitempty"` // ID người tạo
+	UpdatedBy  string            `json:"updated_by,omitempty"` // ID người cập nhật gần nhất
+}
+
+// newDetailResp tạo response từ webhook model, không kèm secret
+func (h handler) newDetailResp(w models.Webhook) detailResp {
+	return detailResp{
+		ID:         w.ID.Hex(),
+		URL:        w.URL,
+		EventTypes: w.EventTypes,
+		Active:     w.Active,
+		CreatedAt:  response.DateTime(w.CreatedAt),
+		UpdatedAt:  response.DateTime(w.UpdatedAt),
+		CreatedBy:  w.CreatedBy,
+		UpdatedBy:  w.UpdatedBy,
+	}
+}
+
+// newCreateResp tạo response cho webhook vừa tạo, kèm secret để client lưu lại
+func (h handler) newCreateResp(w models.Webhook) detailResp {
+	resp := h.newDetailResp(w)
+	resp.Secret = w.Secret
+	return resp
+}
+
+// listResp là danh sách webhook trả về cho client
+type listResp struct {
+	Items []detailResp                `json:"items"` // Các webhook của trang hiện tại
+	Meta  paginator.PaginatorResponse `json:"meta"`  // Thông tin phân trang
+}
+
+// newListResp tạo response từ danh sách webhook
+func (h handler) newListResp(out webhook.ListOutput) listResp {
+	items := make([]detailResp, 0, len(out.Webhooks))
+	for _, w := range out.Webhooks {
+		items = append(items, h.newDetailResp(w))
+	}
+	return listResp{
+		Items: items,
+		Meta:  out.Pagin.ToResponse(),
+	}
+}
+
+// deliveryResp là một dòng trong delivery log
+type deliveryResp struct {
+	ID             string             `json:"id"`                         // ID của delivery, cũng là header X-Webhook-Delivery
+	WebhookID      string             `json:"webhook_id"`                 // ID của webhook
+	Event          event.Event        `json:"event"`                      // Nội dung được POST tới webhook
+	Status         string             `json:"status"`                     // pending | succeeded | dead
+	Attempts       int                `json:"attempts"`                   // Số lần đã gửi
+	NextAttemptAt  *response.DateTime `json:"next_attempt_at,omitempty"`  // Lần gửi tiếp theo, chỉ có khi đang chờ
+	LastStatusCode int                `json:"last_status_code,omitempty"` // HTTP status của lần gửi gần nhất
+	LastError      string             `json:"last_error,omitempty"`       // Lỗi của lần gửi gần nhất
+	DeliveredAt    *response.DateTime `json:"delivered_at,omitempty"`     // Thời điểm gửi thành công
+	CreatedAt      response.DateTime  `json:"created_at"`                 // Thời điểm event được đưa vào hàng chờ
+}
+
+// newDeliveryResp tạo response từ delivery model
+func (h handler) newDeliveryResp(dl models.WebhookDelivery) deliveryResp {
+	resp := deliveryResp{
+		ID:             dl.ID.Hex(),
+		WebhookID:      dl.WebhookID.Hex(),
+		Event:          dl.Event,
+		Status:         string(dl.Status),
+		Attempts:       dl.Attempts,
+		LastStatusCode: dl.LastStatusCode,
+		LastError:      dl.LastError,
+		CreatedAt:      response.DateTime(dl.CreatedAt),
+	}
+	if dl.Status == models.DeliveryPending {
+		next := response.DateTime(dl.NextAttemptAt)
+		resp.NextAttemptAt = &next
+	}
+	if dl.DeliveredAt != nil {
+		delivered := response.DateTime(*dl.DeliveredAt)
+		resp.DeliveredAt = &delivered
+	}
+	return resp
+}
+
+// listDeliveriesResp là delivery log trả về cho client
+type listDeliveriesResp struct {
+	Items []deliveryResp              `json:"items"` // Các delivery của trang hiện tại, mới nhất đứng trước
+	Meta  paginator.PaginatorResponse `json:"meta"`  // Thông tin phân trang
+}
+
+// newListDeliveriesResp tạo response từ delivery log
+func (h handler) newListDeliveriesResp(out webhook.ListDeliveriesOutput) listDeliveriesResp {
+	items := make([]deliveryResp, 0, len(out.Deliveries))
+	for _, dl := range out.Deliveries {
+		items = append(items, h.newDeliveryResp(dl))
+	}
+	return listDeliveriesResp{
+		Items: items,
+		Meta:  out.Pagin.ToResponse(),
+	}
+}
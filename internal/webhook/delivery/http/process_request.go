@@ -0,0 +1,178 @@
+package http
+
+import (
+	"errors"
+
+	"thuchanhgolang/internal/models"
+	pkgErrors "thuchanhgolang/pkg/errors"
+	"thuchanhgolang/pkg/jwt"
+	"thuchanhgolang/pkg/util"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// processCreateRequest xử lý và validate request tạo webhook
+func (h handler) processCreateRequest(c *gin.Context) (createReq, models.Scope, error) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Parse JSON body thành createReq struct
+	var req createReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.l.Warnf(ctx, "webhook.http.processCreateRequest.ShouldBindJSON: %v", err)
+		return createReq{}, models.Scope{}, pkgErrors.BindingError(err, errWrongBody)
+	}
+
+	// Bước 2: Lấy scope của user đang đăng nhập, webhook thuộc shop của user
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.http.processCreateRequest.processScope: %v", err)
+		return createReq{}, models.Scope{}, err
+	}
+
+	return req, sc, nil
+}
+
+// processUpdateRequest xử lý và validate request update webhook
+func (h handler) processUpdateRequest(c *gin.Context) (updateReq, models.Scope, error) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Parse JSON body thành updateReq struct
+	var req updateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.l.Warnf(ctx, "webhook.http.processUpdateRequest.ShouldBindJSON: %v", err)
+		return updateReq{}, models.Scope{}, pkgErrors.BindingError(err, errWrongBody)
+	}
+
+	// Bước 2: Validate dữ liệu
+	if err := req.validate(); err != nil {
+		h.l.Warnf(ctx, "webhook.http.processUpdateRequest.validate: %v", err)
+		return updateReq{}, models.Scope{}, err
+	}
+
+	// Bước 3: Lấy scope của user đang đăng nhập
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.http.processUpdateRequest.processScope: %v", err)
+		return updateReq{}, models.Scope{}, err
+	}
+
+	// Bước 4: Đọc version client đã đọc từ If-Match
+	version, err := h.processVersion(c)
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.http.processUpdateRequest.processVersion: %v", err)
+		return updateReq{}, models.Scope{}, err
+	}
+	req.version = version
+
+	return req, sc, nil
+}
+
+// processPatchRequest xử lý và validate merge patch của webhook
+func (h handler) processPatchRequest(c *gin.Context) (patchReq, models.Scope, error) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Parse merge patch thành patchReq struct
+	var req patchReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.l.Warnf(ctx, "webhook.http.processPatchRequest.ShouldBindJSON: %v", err)
+		return patchReq{}, models.Scope{}, pkgErrors.BindingError(err, errWrongBody)
+	}
+
+	// Bước 2: Validate dữ liệu
+	if err := req.validate(); err != nil {
+		h.l.Warnf(ctx, "webhook.http.processPatchRequest.validate: %v", err)
+		return patchReq{}, models.Scope{}, err
+	}
+
+	// Bước 3: Lấy scope của user đang đăng nhập, dùng để ghi lại người sửa
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.http.processPatchRequest.processScope: %v", err)
+		return patchReq{}, models.Scope{}, err
+	}
+
+	// Bước 4: Đọc version client đã đọc từ If-Match
+	version, err := h.processVersion(c)
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.http.processPatchRequest.processVersion: %v", err)
+		return patchReq{}, models.Scope{}, err
+	}
+	req.version = version
+
+	return req, sc, nil
+}
+
+// processListRequest xử lý query lấy danh sách webhook
+func (h handler) processListRequest(c *gin.Context) (listReq, models.Scope, error) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Parse query thành listReq struct
+	var req listReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.l.Warnf(ctx, "webhook.http.processListRequest.ShouldBindQuery: %v", err)
+		return listReq{}, models.Scope{}, errWrongQuery
+	}
+
+	// Bước 2: Lấy scope của user đang đăng nhập
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.http.processListRequest.processScope: %v", err)
+		return listReq{}, models.Scope{}, err
+	}
+
+	return req, sc, nil
+}
+
+// processListDeliveriesRequest xử lý query lấy delivery log của webhook
+func (h handler) processListDeliveriesRequest(c *gin.Context) (listDeliveriesReq, models.Scope, error) {
+	ctx := c.Request.Context()
+
+	// Bước 1: Parse query thành listDeliveriesReq struct, trạng thái được kiểm tra ở usecase
+	var req listDeliveriesReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.l.Warnf(ctx, "webhook.http.processListDeliveriesRequest.ShouldBindQuery: %v", err)
+		return listDeliveriesReq{}, models.Scope{}, errWrongQuery
+	}
+
+	// Bước 2: Lấy scope của user đang đăng nhập
+	sc, err := h.processScope(c)
+	if err != nil {
+		h.l.Warnf(ctx, "webhook.http.processListDeliveriesRequest.processScope: %v", err)
+		return listDeliveriesReq{}, models.Scope{}, err
+	}
+
+	return req, sc, nil
+}
+
+// processID đọc ID webhook từ URL param
+func (h handler) processID(c *gin.Context) (primitive.ObjectID, error) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		h.l.Warnf(c.Request.Context(), "webhook.http.processID.ObjectIDFromHex: %v", err)
+		return primitive.NilObjectID, errInvalidID
+	}
+	return id, nil
+}
+
+// processVersion đọc version client đã đọc từ header If-Match, bắt buộc khi sửa hoặc xóa
+func (h handler) processVersion(c *gin.Context) (int, error) {
+	version, err := util.GetIfMatchVersion(c)
+	if errors.Is(err, util.ErrIfMatchMissing) {
+		return 0, errPreconditionRequired
+	}
+	if err != nil {
+		return 0, errVersionMismatch
+	}
+	return version, nil
+}
+
+// processScope lấy scope của user đang đăng nhập từ JWT payload
+func (h handler) processScope(c *gin.Context) (models.Scope, error) {
+	payload, ok := jwt.GetPayloadFromContext(c.Request.Context())
+	if !ok {
+		return models.Scope{}, errUnauthorized
+	}
+
+	return jwt.NewScope(payload), nil
+}
@@ -0,0 +1,39 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"thuchanhgolang/pkg/openapi"
+	"thuchanhgolang/pkg/patch"
+)
+
+// MapRoutes maps the routes to the handler functions
+func MapRoutes(r *gin.RouterGroup, h Handler) {
+	r.POST("", h.create)                                          // Tạo webhook cho shop, secret chỉ trả về ở đây
+	r.GET("", h.list)                                             // Lấy danh sách webhook của shop
+	r.GET("/:id", h.getByID)                                      // Xem chi tiết webhook theo ID
+	r.PUT("/:id", h.update)                                       // Cập nhật webhook (URL, loại event, secret, bật / tắt)
+	r.PATCH("/:id", h.patch)                                      // Cập nhật một phần webhook theo JSON Merge Patch
+	r.DELETE("/:id", h.delete)                                    // Xóa webhook
+	r.GET("/:id/deliveries", h.listDeliveries)                    // Delivery log, lọc theo trạng thái
+	r.POST("/:id/deliveries/:delivery_id/redeliver", h.redeliver) // Gửi lại một delivery
+}
+
+// Spec mô tả các route của MapRoutes cho OpenAPI, sửa route thì sửa cả ở đây
+func Spec() []openapi.Route {
+	return []openapi.Route{
+		{Method: http.MethodPost, Path: "", Summary: "Tạo webhook cho shop, secret chỉ trả về ở đây", Body: createReq{}, Response: detailResp{}, ETag: true},
+		{Method: http.MethodGet, Path: "", Summary: "Lấy danh sách webhook của shop", Query: listReq{}, Response: listResp{}},
+		{Method: http.MethodGet, Path: "/:id", Summary: "Xem chi tiết webhook theo ID", Response: detailResp{}, ETag: true},
+		{Method: http.MethodPut, Path: "/:id", Summary: "Cập nhật webhook (URL, loại event, secret, bật / tắt)", IfMatch: true, Body: updateReq{}, Response: detailResp{}, ETag: true},
+		{
+			Method: http.MethodPatch, Path: "/:id", Summary: "Cập nhật một phần webhook theo JSON Merge Patch, field không gửi thì giữ nguyên",
+			IfMatch: true, Body: patchReq{}, BodyTypes: []string{patch.ContentType, openapi.ContentJSON}, Response: detailResp{}, ETag: true,
+		},
+		{Method: http.MethodDelete, Path: "/:id", Summary: "Xóa webhook", IfMatch: true, Response: gin.H{}},
+		{Method: http.MethodGet, Path: "/:id/deliveries", Summary: "Delivery log, lọc theo trạng thái", Query: listDeliveriesReq{}, Response: listDeliveriesResp{}},
+		{Method: http.MethodPost, Path: "/:id/deliveries/:delivery_id/redeliver", Summary: "Gửi lại một delivery", Response: deliveryResp{}},
+	}
+}
@@ -0,0 +1,17 @@
+package webhook
+
+import pkgErrors "thuchanhgolang/pkg/errors"
+
+var (
+	// ErrWebhookNotFound trả về khi không tìm thấy webhook trong shop của user
+	ErrWebhookNotFound = pkgErrors.NotFound("webhook not found")
+
+	// ErrDeliveryNotFound trả về khi không tìm thấy delivery của webhook
+	ErrDeliveryNotFound = pkgErrors.NotFound("webhook delivery not found")
+
+	// ErrShopRequired trả về khi user không thuộc shop nào nên không có webhook
+	ErrShopRequired = pkgErrors.Forbidden("webhooks belong to a shop, your account has no shop")
+
+	// ErrVersionMismatch trả về khi webhook đã bị thay đổi sau lần đọc của client (If-Match không khớp version)
+	ErrVersionMismatch = pkgErrors.PreconditionFailed("webhook has been modified by another request")
+)
@@ -0,0 +1,32 @@
+package webhook
+
+import (
+	"thuchanhgolang/internal/branch"
+	"thuchanhgolang/internal/department"
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/region"
+	"thuchanhgolang/internal/shop"
+	"thuchanhgolang/internal/user"
+)
+
+// EventTypes là các loại event webhook có thể đăng ký, ngoài "*" (mọi event)
+var EventTypes = []string{
+	shop.EventShopCreated, shop.EventShopUpdated, shop.EventShopDeleted,
+	region.EventRegionCreated, region.EventRegionUpdated, region.EventRegionDeleted,
+	branch.EventBranchCreated, branch.EventBranchUpdated, branch.EventBranchDeleted, branch.EventBranchMoved,
+	department.EventDepartmentCreated, department.EventDepartmentUpdated, department.EventDepartmentDeleted, department.EventDepartmentMoved,
+	user.EventUserCreated, user.EventUserUpdated, user.EventUserDeleted, user.EventUserMoved, user.EventUserRoleChanged, user.EventUserDeactivated,
+}
+
+// IsEventType kiểm tra loại event có đăng ký được không
+func IsEventType(typ string) bool {
+	if typ == models.WebhookAllEvents {
+		return true
+	}
+	for _, t := range EventTypes {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Repository định nghĩa các thao tác với webhook và delivery log trong database.
+// Webhook luôn được đọc theo shop để user không thấy webhook của shop khác.
+//
+//go:generate mockery --name=Repository
+type Repository interface {
+	// Create tạo webhook mới
+	Create(ctx context.Context, sc models.Scope, opts CreateOptions) (models.Webhook, error)
+
+	// GetByID lấy webhook của shop theo ID
+	GetByID(ctx context.Context, shopID, id primitive.ObjectID) (models.Webhook, error)
+
+	// List lấy danh sách webhook của shop
+	List(ctx context.Context, opts ListOptions) ([]models.Webhook, paginator.Paginator, error)
+
+	// Update cập nhật webhook
+	Update(ctx context.Context, sc models.Scope, opts UpdateOptions) (models.Webhook, error)
+
+	// Delete xóa webhook
+	Delete(ctx context.Context, shopID, id primitive.ObjectID, version int) error
+
+	// ListSubscribers lấy các webhook đang bật của shop có đăng ký loại event
+	ListSubscribers(ctx context.Context, shopID primitive.ObjectID, eventType string) ([]models.Webhook, error)
+
+	// EnqueueDeliveries tạo delivery chờ gửi, bỏ qua delivery đã có cùng webhook và event
+	// để nhận lại cùng một event (at-least-once) không gửi trùng
+	EnqueueDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error
+
+	// ListDueDeliveries lấy tối đa limit delivery đang chờ và đã tới lúc gửi
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int64) ([]models.WebhookDelivery, error)
+
+	// ListDeliveries lấy delivery log của webhook, mới nhất đứng trước
+	ListDeliveries(ctx context.Context, opts ListDeliveriesOptions) ([]models.WebhookDelivery, paginator.Paginator, error)
+
+	// MarkDelivered ghi nhận lần gửi thành công
+	MarkDelivered(ctx context.Context, opts MarkDeliveredOptions) error
+
+	// MarkFailed ghi nhận lần gửi thất bại
+	MarkFailed(ctx context.Context, opts MarkFailedOptions) error
+
+	// Redeliver đưa delivery của webhook về trạng thái chờ gửi ngay với số lần thử mới
+	Redeliver(ctx context.Context, shopID, webhookID, id primitive.ObjectID, at time.Time) (models.WebhookDelivery, error)
+}
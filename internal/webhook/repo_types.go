@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Collection là collection lưu webhook
+	Collection = "webhooks"
+	// DeliveryCollection là collection lưu delivery (delivery log)
+	DeliveryCollection = "webhook_deliveries"
+)
+
+// CreateOptions là tùy chọn để tạo webhook trong database
+type CreateOptions struct {
+	ShopID     primitive.ObjectID
+	URL        string
+	EventTypes []string
+	Secret     string
+}
+
+// UpdateOptions là tùy chọn để cập nhật webhook, field nil thì giữ nguyên
+type UpdateOptions struct {
+	ID         primitive.ObjectID
+	ShopID     primitive.ObjectID
+	URL        *string
+	EventTypes []string
+	Secret     *string
+	Active     *bool
+	Version    int // Version client đã đọc (If-Match), 0 → không kiểm tra
+}
+
+// ListOptions là phân trang khi lấy danh sách webhook của shop
+type ListOptions struct {
+	ShopID primitive.ObjectID
+	Pagin  paginator.PaginatorQuery // Trang cần lấy (đã Adjust)
+}
+
+// ListDeliveriesOptions là bộ lọc và phân trang khi lấy delivery log của webhook
+type ListDeliveriesOptions struct {
+	ShopID    primitive.ObjectID
+	WebhookID primitive.ObjectID
+	Status    models.DeliveryStatus // Rỗng → mọi trạng thái
+	Pagin     paginator.PaginatorQuery
+}
+
+// MarkDeliveredOptions là kết quả của lần gửi thành công
+type MarkDeliveredOptions struct {
+	ID         primitive.ObjectID
+	StatusCode int
+	At         time.Time
+}
+
+// MarkFailedOptions là kết quả của lần gửi thất bại
+type MarkFailedOptions struct {
+	ID            primitive.ObjectID
+	Attempts      int       // Tổng số lần đã gửi, tính cả lần này
+	NextAttemptAt time.Time // Thời điểm sớm nhất được gửi lại
+	StatusCode    int       // 0 khi không kết nối được
+	LastError     string
+	Dead          bool // Đã hết số lần thử, chuyển sang dead letter
+	At            time.Time
+}
@@ -0,0 +1,178 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/webhook"
+	"thuchanhgolang/pkg/mongo"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// getDeliveryCollection lấy collection webhook_deliveries từ database
+func (repo implRepository) getDeliveryCollection() mongo.Collection {
+	return repo.db.Collection(webhook.DeliveryCollection)
+}
+
+// EnqueueDeliveries tạo delivery chờ gửi bằng upsert theo (webhook_id, event._id),
+// delivery đã có thì giữ nguyên nên nhận lại cùng event không tạo delivery trùng
+func (repo implRepository) EnqueueDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	col := repo.getDeliveryCollection()
+	upsert := options.Update().SetUpsert(true)
+
+	for _, dl := range deliveries {
+		if dl.ID.IsZero() {
+			dl.ID = repo.db.NewObjectID()
+		}
+
+		filter := bson.M{"webhook_id": dl.WebhookID, "event._id": dl.Event.ID}
+		update := bson.M{"$setOnInsert": dl}
+		if _, err := col.UpdateOne(ctx, filter, update, upsert); err != nil {
+			// Hai dispatcher cùng upsert một delivery: bản kia đã tạo, coi như thành công
+			if _, ok := mongo.AsDuplicateKey(err); ok {
+				continue
+			}
+			repo.l.Errorf(ctx, "webhook.mongo.EnqueueDeliveries.UpdateOne: %v", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListDueDeliveries lấy các delivery đang chờ và đã tới lúc gửi, cũ nhất trước
+func (repo implRepository) ListDueDeliveries(ctx context.Context, now time.Time, limit int64) ([]models.WebhookDelivery, error) {
+	filter := bson.M{
+		"status":          models.DeliveryPending,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(limit)
+
+	cursor, err := repo.getDeliveryCollection().Find(ctx, filter, findOpts)
+	if err != nil {
+		repo.l.Errorf(ctx, "webhook.mongo.ListDueDeliveries.Find: %v", err)
+		return nil, err
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		repo.l.Errorf(ctx, "webhook.mongo.ListDueDeliveries.All: %v", err)
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// ListDeliveries lấy delivery log của webhook, mới nhất đứng trước
+func (repo implRepository) ListDeliveries(ctx context.Context, opts webhook.ListDeliveriesOptions) ([]models.WebhookDelivery, paginator.Paginator, error) {
+	col := repo.getDeliveryCollection()
+
+	// Bước 1: Đếm tổng số delivery khớp bộ lọc
+	filter := bson.M{"webhook_id": opts.WebhookID, "shop_id": opts.ShopID}
+	if opts.Status != "" {
+		filter["status"] = opts.Status
+	}
+	total, err := col.CountDocuments(ctx, filter)
+	if err != nil {
+		repo.l.Errorf(ctx, "webhook.mongo.ListDeliveries.CountDocuments: %v", err)
+		return nil, paginator.Paginator{}, err
+	}
+
+	// Bước 2: Lấy delivery của trang hiện tại
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetSkip(opts.Pagin.Offset()).
+		SetLimit(opts.Pagin.Limit)
+	cursor, err := col.Find(ctx, filter, findOpts)
+	if err != nil {
+		repo.l.Errorf(ctx, "webhook.mongo.ListDeliveries.Find: %v", err)
+		return nil, paginator.Paginator{}, err
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		repo.l.Errorf(ctx, "webhook.mongo.ListDeliveries.All: %v", err)
+		return nil, paginator.Paginator{}, err
+	}
+
+	return deliveries, paginator.Paginator{
+		Total:       total,
+		Count:       int64(len(deliveries)),
+		PerPage:     opts.Pagin.Limit,
+		CurrentPage: opts.Pagin.Page,
+	}, nil
+}
+
+// MarkDelivered đánh dấu delivery đã gửi thành công
+func (repo implRepository) MarkDelivered(ctx context.Context, opts webhook.MarkDeliveredOptions) error {
+	update := bson.M{
+		"$set": bson.M{
+			"status":           models.DeliverySucceeded,
+			"last_status_code": opts.StatusCode,
+			"delivered_at":     opts.At,
+			"updated_at":       opts.At,
+		},
+		"$inc":   bson.M{"attempts": 1},
+		"$unset": bson.M{"last_error": ""},
+	}
+	if _, err := repo.getDeliveryCollection().UpdateOne(ctx, bson.M{"_id": opts.ID}, update); err != nil {
+		repo.l.Errorf(ctx, "webhook.mongo.MarkDelivered.UpdateOne: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// MarkFailed ghi nhận lần gửi thất bại, chuyển sang dead letter khi hết số lần thử
+func (repo implRepository) MarkFailed(ctx context.Context, opts webhook.MarkFailedOptions) error {
+	status := models.DeliveryPending
+	if opts.Dead {
+		status = models.DeliveryDead
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":           status,
+			"attempts":         opts.Attempts,
+			"next_attempt_at":  opts.NextAttemptAt,
+			"last_status_code": opts.StatusCode,
+			"last_error":       opts.LastError,
+			"updated_at":       opts.At,
+		},
+	}
+	if _, err := repo.getDeliveryCollection().UpdateOne(ctx, bson.M{"_id": opts.ID}, update); err != nil {
+		repo.l.Errorf(ctx, "webhook.mongo.MarkFailed.UpdateOne: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// Redeliver đưa delivery về trạng thái chờ gửi ngay, số lần thử tính lại từ đầu
+func (repo implRepository) Redeliver(ctx context.Context, shopID, webhookID, id primitive.ObjectID, at time.Time) (models.WebhookDelivery, error) {
+	filter := bson.M{"_id": id, "webhook_id": webhookID, "shop_id": shopID}
+	update := bson.M{
+		"$set": bson.M{
+			"status":          models.DeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": at,
+			"updated_at":      at,
+		},
+	}
+	findOpts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var dl models.WebhookDelivery
+	if err := repo.getDeliveryCollection().FindOneAndUpdate(ctx, filter, update, findOpts).Decode(&dl); err != nil {
+		repo.l.Errorf(ctx, "webhook.mongo.Redeliver.FindOneAndUpdate: %v", err)
+		return models.WebhookDelivery{}, err
+	}
+
+	return dl, nil
+}
@@ -0,0 +1,21 @@
+package mongo
+
+import (
+	"thuchanhgolang/internal/webhook"
+	"thuchanhgolang/pkg/log"
+	"thuchanhgolang/pkg/mongo"
+)
+
+// implRepository là implementation của webhook.Repository
+type implRepository struct {
+	l  log.Logger     // Logger để ghi log
+	db mongo.Database // Database connection
+}
+
+// NewRepository tạo một webhook repository mới
+func NewRepository(l log.Logger, db mongo.Database) webhook.Repository {
+	return &implRepository{
+		l:  l,
+		db: db,
+	}
+}
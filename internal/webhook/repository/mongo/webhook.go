@@ -0,0 +1,193 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/webhook"
+	"thuchanhgolang/pkg/mongo"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// getWebhookCollection lấy collection webhooks từ database
+func (repo implRepository) getWebhookCollection() mongo.Collection {
+	return repo.db.Collection(webhook.Collection)
+}
+
+// Create tạo webhook mới trong MongoDB, webhook mới luôn được bật
+func (repo implRepository) Create(ctx context.Context, sc models.Scope, opts webhook.CreateOptions) (models.Webhook, error) {
+	now := time.Now()
+	w := models.Webhook{
+		ID:         repo.db.NewObjectID(),
+		ShopID:     opts.ShopID,
+		URL:        opts.URL,
+		EventTypes: opts.EventTypes,
+		Secret:     opts.Secret,
+		Active:     true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		CreatedBy:  sc.UserID,
+		UpdatedBy:  sc.UserID,
+		Version:    1,
+	}
+
+	if _, err := repo.getWebhookCollection().InsertOne(ctx, w); err != nil {
+		repo.l.Errorf(ctx, "webhook.mongo.Create.InsertOne: %v", err)
+		return models.Webhook{}, err
+	}
+
+	return w, nil
+}
+
+// GetByID lấy webhook của shop theo ID
+func (repo implRepository) GetByID(ctx context.Context, shopID, id primitive.ObjectID) (models.Webhook, error) {
+	var w models.Webhook
+	filter := bson.M{"_id": id, "shop_id": shopID}
+	if err := repo.getWebhookCollection().FindOne(ctx, filter).Decode(&w); err != nil {
+		repo.l.Errorf(ctx, "webhook.mongo.GetByID.FindOne: %v", err)
+		return models.Webhook{}, err
+	}
+
+	return w, nil
+}
+
+// List lấy danh sách webhook của shop, webhook tạo sau đứng trước
+func (repo implRepository) List(ctx context.Context, opts webhook.ListOptions) ([]models.Webhook, paginator.Paginator, error) {
+	col := repo.getWebhookCollection()
+
+	// Bước 1: Đếm tổng số webhook của shop
+	filter := bson.M{"shop_id": opts.ShopID}
+	total, err := col.CountDocuments(ctx, filter)
+	if err != nil {
+		repo.l.Errorf(ctx, "webhook.mongo.List.CountDocuments: %v", err)
+		return nil, paginator.Paginator{}, err
+	}
+
+	// Bước 2: Lấy webhook của trang hiện tại
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetSkip(opts.Pagin.Offset()).
+		SetLimit(opts.Pagin.Limit)
+	cursor, err := col.Find(ctx, filter, findOpts)
+	if err != nil {
+		repo.l.Errorf(ctx, "webhook.mongo.List.Find: %v", err)
+		return nil, paginator.Paginator{}, err
+	}
+
+	var webhooks []models.Webhook
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		repo.l.Errorf(ctx, "webhook.mongo.List.All: %v", err)
+		return nil, paginator.Paginator{}, err
+	}
+
+	return webhooks, paginator.Paginator{
+		Total:       total,
+		Count:       int64(len(webhooks)),
+		PerPage:     opts.Pagin.Limit,
+		CurrentPage: opts.Pagin.Page,
+	}, nil
+}
+
+// Update cập nhật webhook, chỉ khi version khớp với version client đã đọc
+func (repo implRepository) Update(ctx context.Context, sc models.Scope, opts webhook.UpdateOptions) (models.Webhook, error) {
+	// Tạo update document, field nil thì giữ nguyên
+	update := mongo.NewUpdate()
+	mongo.SetPtr(update, "url", opts.URL)
+	if opts.EventTypes != nil {
+		update.Set("event_types", opts.EventTypes)
+	}
+	mongo.SetPtr(update, "secret", opts.Secret)
+	mongo.SetPtr(update, "active", opts.Active)
+
+	// Nếu không có gì để update
+	if !update.HasChanges() {
+		current, err := repo.GetByID(ctx, opts.ShopID, opts.ID)
+		if err != nil {
+			return models.Webhook{}, err
+		}
+		if opts.Version > 0 && current.Version != opts.Version {
+			return models.Webhook{}, webhook.ErrVersionMismatch
+		}
+		return current, nil
+	}
+
+	// Ghi lại thời điểm và người sửa, tăng version
+	update.Set("updated_at", time.Now()).Set("updated_by", sc.UserID).Inc("version", 1)
+
+	filter := bson.M{"_id": opts.ID, "shop_id": opts.ShopID}
+	if opts.Version > 0 {
+		filter["version"] = opts.Version
+	}
+	findOpts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var w models.Webhook
+	err := repo.getWebhookCollection().FindOneAndUpdate(ctx, filter, update.Document(), findOpts).Decode(&w)
+	if errors.Is(err, mongo.ErrNoDocuments) && opts.Version > 0 {
+		return models.Webhook{}, repo.versionError(ctx, opts.ShopID, opts.ID)
+	}
+	if err != nil {
+		repo.l.Errorf(ctx, "webhook.mongo.Update.FindOneAndUpdate: %v", err)
+		return models.Webhook{}, err
+	}
+
+	return w, nil
+}
+
+// Delete xóa webhook, delivery log của webhook được giữ lại để tra cứu
+func (repo implRepository) Delete(ctx context.Context, shopID, id primitive.ObjectID, version int) error {
+	filter := bson.M{"_id": id, "shop_id": shopID}
+	if version > 0 {
+		filter["version"] = version
+	}
+
+	deleted, err := repo.getWebhookCollection().DeleteOne(ctx, filter)
+	if err != nil {
+		repo.l.Errorf(ctx, "webhook.mongo.Delete.DeleteOne: %v", err)
+		return err
+	}
+	if deleted == 0 {
+		if version > 0 {
+			return repo.versionError(ctx, shopID, id)
+		}
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// ListSubscribers lấy các webhook đang bật của shop có đăng ký loại event (hoặc "*")
+func (repo implRepository) ListSubscribers(ctx context.Context, shopID primitive.ObjectID, eventType string) ([]models.Webhook, error) {
+	filter := bson.M{
+		"shop_id":     shopID,
+		"active":      true,
+		"event_types": bson.M{"$in": []string{eventType, models.WebhookAllEvents}},
+	}
+
+	cursor, err := repo.getWebhookCollection().Find(ctx, filter)
+	if err != nil {
+		repo.l.Errorf(ctx, "webhook.mongo.ListSubscribers.Find: %v", err)
+		return nil, err
+	}
+
+	var webhooks []models.Webhook
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		repo.l.Errorf(ctx, "webhook.mongo.ListSubscribers.All: %v", err)
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// versionError phân biệt webhook không tồn tại với webhook đã bị sửa bởi request khác
+func (repo implRepository) versionError(ctx context.Context, shopID, id primitive.ObjectID) error {
+	if _, err := repo.GetByID(ctx, shopID, id); err != nil {
+		return err
+	}
+	return webhook.ErrVersionMismatch
+}
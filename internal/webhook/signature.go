@@ -0,0 +1,33 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// Header của request gửi tới webhook
+const (
+	HeaderEvent     = "X-Webhook-Event"     // Loại event
+	HeaderDelivery  = "X-Webhook-Delivery"  // ID delivery, trùng nhau khi gửi lại cùng một delivery
+	HeaderTimestamp = "X-Webhook-Timestamp" // Unix giây lúc gửi, bên nhận dùng để chặn replay
+	HeaderSignature = "X-Webhook-Signature" // "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body))
+)
+
+// signaturePrefix là tiền tố thuật toán của chữ ký
+const signaturePrefix = "sha256="
+
+// Sign ký body gửi tới webhook, timestamp nằm trong nội dung ký để không dùng lại được chữ ký cũ
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify kiểm tra chữ ký của request, bên nhận webhook dùng cùng cách tính với Sign
+func Verify(secret, signature string, timestamp int64, body []byte) bool {
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body)))
+}
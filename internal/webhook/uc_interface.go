@@ -0,0 +1,45 @@
+package webhook
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Usecase quản lý webhook và delivery log của shop trong scope của user
+//
+//go:generate mockery --name=Usecase
+type Usecase interface {
+	// Create tạo webhook mới, secret được trả về trong kết quả
+	Create(ctx context.Context, sc models.Scope, input CreateInput) (models.Webhook, error)
+
+	// GetByID lấy webhook theo ID
+	GetByID(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Webhook, error)
+
+	// List lấy danh sách webhook có phân trang
+	List(ctx context.Context, sc models.Scope, input ListInput) (ListOutput, error)
+
+	// Update cập nhật webhook
+	Update(ctx context.Context, sc models.Scope, input UpdateInput) (models.Webhook, error)
+
+	// Delete xóa webhook
+	Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error
+
+	// ListDeliveries lấy delivery log của webhook, mới nhất đứng trước
+	ListDeliveries(ctx context.Context, sc models.Scope, input ListDeliveriesInput) (ListDeliveriesOutput, error)
+
+	// Redeliver gửi lại một delivery (kể cả delivery đã thành công hoặc đã vào dead letter)
+	Redeliver(ctx context.Context, sc models.Scope, input RedeliverInput) (models.WebhookDelivery, error)
+}
+
+// Deliverer gửi delivery đang chờ tới URL của webhook, ký HMAC và gửi lại với backoff
+//
+//go:generate mockery --name=Deliverer
+type Deliverer interface {
+	// Run gửi delivery định kỳ cho tới khi ctx bị hủy
+	Run(ctx context.Context)
+	// Deliver chạy một lượt gửi, trả về số delivery đã gửi thành công
+	Deliver(ctx context.Context) (int, error)
+}
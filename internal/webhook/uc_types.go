@@ -0,0 +1,102 @@
+package webhook
+
+import (
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CreateInput là input để tạo webhook cho shop của user
+type CreateInput struct {
+	URL        string
+	EventTypes []string
+	Secret     string // Rỗng → sinh ngẫu nhiên
+}
+
+// UpdateInput là input để cập nhật webhook, field nil thì giữ nguyên
+type UpdateInput struct {
+	ID         primitive.ObjectID
+	URL        *string
+	EventTypes []string
+	Secret     *string
+	Active     *bool
+	Version    int // Version client đã đọc (If-Match), 0 → không kiểm tra
+}
+
+// ListInput là input để lấy danh sách webhook
+type ListInput struct {
+	Pagin paginator.PaginatorQuery
+}
+
+// ListOutput là danh sách webhook của một trang
+type ListOutput struct {
+	Webhooks []models.Webhook
+	Pagin    paginator.Paginator
+}
+
+// ListDeliveriesInput là input để lấy delivery log của webhook
+type ListDeliveriesInput struct {
+	WebhookID primitive.ObjectID
+	Status    models.DeliveryStatus // Rỗng → mọi trạng thái
+	Pagin     paginator.PaginatorQuery
+}
+
+// ListDeliveriesOutput là delivery log của một trang
+type ListDeliveriesOutput struct {
+	Deliveries []models.WebhookDelivery
+	Pagin      paginator.Paginator
+}
+
+// RedeliverInput là input để gửi lại một delivery
+type RedeliverInput struct {
+	WebhookID  primitive.ObjectID
+	DeliveryID primitive.ObjectID
+}
+
+// Giá trị mặc định của DelivererOptions
+const (
+	DefaultBatchSize    = 50
+	DefaultPollInterval = 2 * time.Second
+	DefaultMinBackoff   = 10 * time.Second
+	DefaultMaxBackoff   = time.Hour
+	DefaultMaxAttempts  = 8
+	DefaultTimeout      = 10 * time.Second
+)
+
+// DelivererOptions cấu hình việc gửi webhook, giá trị <= 0 được thay bằng mặc định
+type DelivererOptions struct {
+	BatchSize    int64         // Số delivery tối đa gửi mỗi lượt
+	PollInterval time.Duration // Khoảng nghỉ giữa hai lượt
+	MinBackoff   time.Duration // Chờ sau lần thất bại đầu tiên, nhân đôi sau mỗi lần thất bại
+	MaxBackoff   time.Duration // Thời gian chờ tối đa giữa hai lần gửi lại
+	MaxAttempts  int           // Thất bại đủ số lần này thì chuyển sang dead letter
+	Timeout      time.Duration // Timeout của mỗi request tới webhook
+}
+
+// Adjust thay các giá trị không hợp lệ bằng giá trị mặc định
+func (o *DelivererOptions) Adjust() {
+	if o.BatchSize <= 0 {
+		o.BatchSize = DefaultBatchSize
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = DefaultPollInterval
+	}
+	if o.MinBackoff <= 0 {
+		o.MinBackoff = DefaultMinBackoff
+	}
+	if o.MaxBackoff < o.MinBackoff {
+		o.MaxBackoff = DefaultMaxBackoff
+		if o.MaxBackoff < o.MinBackoff {
+			o.MaxBackoff = o.MinBackoff
+		}
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = DefaultMaxAttempts
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+}
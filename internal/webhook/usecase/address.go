@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// errAddressNotAllowed trả về khi webhook trỏ tới địa chỉ nội bộ (loopback, private, link-local...)
+var errAddressNotAllowed = errors.New("webhook address is not allowed")
+
+// sharedAddressSpace là dải CGNAT (RFC 6598), không nằm trong IsPrivate nhưng vẫn là mạng nội bộ của nhà mạng
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// isPublicIP kiểm tra ip có phải địa chỉ công khai mà webhook được phép gọi tới không.
+// Chặn để URL của webhook không dùng được để dò hoặc đọc dịch vụ trong mạng nội bộ (SSRF).
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	return !sharedAddressSpace.Contains(ip)
+}
+
+// lookupIP phân giải host thành các IP, dùng khi kiểm tra URL lúc tạo/sửa webhook
+func lookupIP(ctx context.Context, host string) ([]net.IP, error) {
+	return net.DefaultResolver.LookupIP(ctx, "ip", host)
+}
+
+// dialControl chặn kết nối tới địa chỉ nội bộ. Chạy sau khi DNS đã phân giải nên chặn được cả
+// trường hợp host đổi sang IP nội bộ sau khi webhook đã được kiểm tra (DNS rebinding).
+func dialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+		return errAddressNotAllowed
+	}
+	return nil
+}
+
+// newClient tạo http.Client gửi webhook: chỉ kết nối tới địa chỉ công khai và không đi theo redirect,
+// response 3xx được coi là lần gửi thất bại
+func newClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: dialControl}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/webhook"
+	"thuchanhgolang/pkg/mongo"
+	"thuchanhgolang/pkg/util"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxErrorBody là số byte tối đa của response lỗi được lưu vào delivery log
+const maxErrorBody = 512
+
+// Run gửi delivery định kỳ cho tới khi ctx bị hủy
+func (d *implDeliverer) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.Deliver(ctx); err != nil && ctx.Err() == nil {
+			d.l.Errorf(ctx, "webhook.usecase.Run.Deliver: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Deliver gửi các delivery đã tới lúc gửi.
+// Flow: Lấy delivery tới hạn -> Lấy webhook -> POST có chữ ký -> Đánh dấu thành công hoặc hẹn gửi lại / dead letter
+func (d *implDeliverer) Deliver(ctx context.Context) (int, error) {
+	// Bước 1: Lấy delivery đang chờ và đã tới lúc gửi
+	now := d.now()
+	due, err := d.repo.ListDueDeliveries(ctx, now, d.opts.BatchSize)
+	if err != nil {
+		d.l.Errorf(ctx, "webhook.usecase.Deliver.repo.ListDueDeliveries: %v", err)
+		return 0, err
+	}
+
+	webhooks := make(map[primitive.ObjectID]*models.Webhook)
+	delivered := 0
+
+	for _, dl := range due {
+		// Bước 2: Lấy webhook, mỗi webhook chỉ đọc một lần trong một lượt
+		w, ok := webhooks[dl.WebhookID]
+		if !ok {
+			found, err := d.repo.GetByID(ctx, dl.ShopID, dl.WebhookID)
+			switch {
+			case errors.Is(err, mongo.ErrNoDocuments):
+				w = nil
+			case err != nil:
+				d.l.Errorf(ctx, "webhook.usecase.Deliver.repo.GetByID: %v", err)
+				continue
+			default:
+				w = &found
+			}
+			webhooks[dl.WebhookID] = w
+		}
+
+		// Webhook đã bị xóa hoặc tắt thì không gửi nữa, delivery vào dead letter để còn redeliver
+		if w == nil || !w.Active {
+			reason := "webhook has been deleted"
+			if w != nil {
+				reason = "webhook is disabled"
+			}
+			d.markFailed(ctx, dl, 0, reason, true)
+			continue
+		}
+
+		// Bước 3: Gửi event tới URL của webhook
+		statusCode, err := d.send(ctx, *w, dl)
+		if err != nil {
+			attempts := dl.Attempts + 1
+			d.l.Warnf(ctx, "webhook.usecase.Deliver.send: delivery %s to %s attempt %d: %v", dl.ID.Hex(), w.URL, attempts, err)
+			d.markFailed(ctx, dl, statusCode, err.Error(), attempts >= d.opts.MaxAttempts)
+			continue
+		}
+
+		// Bước 4: Đánh dấu đã gửi. Lỗi ở bước này thì delivery được gửi lại ở lượt sau (at-least-once)
+		err = d.repo.MarkDelivered(ctx, webhook.MarkDeliveredOptions{
+			ID:         dl.ID,
+			StatusCode: statusCode,
+			At:         d.now(),
+		})
+		if err != nil {
+			d.l.Errorf(ctx, "webhook.usecase.Deliver.repo.MarkDelivered: %v", err)
+			continue
+		}
+		delivered++
+	}
+
+	return delivered, nil
+}
+
+// send POST event tới webhook kèm chữ ký HMAC, trả về status code và lỗi nếu không phải 2xx
+func (d *implDeliverer) send(ctx context.Context, w models.Webhook, dl models.WebhookDelivery) (int, error) {
+	body, err := json.Marshal(dl.Event)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	// Chữ ký ký cả timestamp để bên nhận từ chối request cũ bị gửi lại (replay)
+	timestamp := d.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.HeaderEvent, dl.Event.Type)
+	req.Header.Set(webhook.HeaderDelivery, dl.ID.Hex())
+	req.Header.Set(webhook.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhook.HeaderSignature, webhook.Sign(w.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return resp.StatusCode, fmt.Errorf("webhook responded %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	// Đọc hết body để connection được dùng lại
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode, nil
+}
+
+// markFailed ghi nhận lần gửi thất bại, hẹn gửi lại với backoff hoặc chuyển sang dead letter
+func (d *implDeliverer) markFailed(ctx context.Context, dl models.WebhookDelivery, statusCode int, reason string, dead bool) {
+	now := d.now()
+	attempts := dl.Attempts + 1
+	if dead {
+		d.l.Warnf(ctx, "webhook.usecase.Deliver: delivery %s is dead after %d attempts: %s", dl.ID.Hex(), attempts, reason)
+	}
+
+	err := d.repo.MarkFailed(ctx, webhook.MarkFailedOptions{
+		ID:            dl.ID,
+		Attempts:      attempts,
+		NextAttemptAt: now.Add(d.backoff(attempts)),
+		StatusCode:    statusCode,
+		LastError:     reason,
+		Dead:          dead,
+		At:            now,
+	})
+	if err != nil {
+		d.l.Errorf(ctx, "webhook.usecase.Deliver.repo.MarkFailed: %v", err)
+	}
+}
+
+// backoff tính thời gian chờ sau lần thất bại thứ attempts: MinBackoff nhân đôi mỗi lần, tối đa MaxBackoff
+func (d *implDeliverer) backoff(attempts int) time.Duration {
+	return util.Backoff(d.opts.MinBackoff, d.opts.MaxBackoff, attempts)
+}
@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/shop"
+	"thuchanhgolang/internal/webhook"
+	"thuchanhgolang/pkg/event"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// receiver là webhook chạy trên httptest server, kiểm tra chữ ký của mỗi request nhận được
+type receiver struct {
+	mu       sync.Mutex
+	secret   string
+	status   int
+	received []event.Event
+	invalid  int
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	body, _ := io.ReadAll(req.Body)
+	timestamp, _ := strconv.ParseInt(req.Header.Get(webhook.HeaderTimestamp), 10, 64)
+	if !webhook.Verify(r.secret, req.Header.Get(webhook.HeaderSignature), timestamp, body) {
+		r.invalid++
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var e event.Event
+	_ = json.Unmarshal(body, &e)
+	r.received = append(r.received, e)
+	w.WriteHeader(r.status)
+}
+
+// newTestDeliverer tạo deliverer với đồng hồ cố định tại now
+func newTestDeliverer(repo webhook.Repository, now time.Time) *implDeliverer {
+	opts := webhook.DelivererOptions{MinBackoff: time.Second, MaxBackoff: 4 * time.Second, MaxAttempts: 3}
+	opts.Adjust()
+	return &implDeliverer{l: &mockLogger{}, repo: repo, client: http.DefaultClient, opts: opts, now: func() time.Time { return now }}
+}
+
+// setup tạo một webhook trỏ tới server và enqueue một event shop.updated qua publisher
+func setup(t *testing.T, status int) (*mockRepository, *receiver, models.WebhookDelivery, time.Time) {
+	t.Helper()
+	rcv := &receiver{secret: "s3cret", status: status}
+	srv := httptest.NewServer(rcv)
+	t.Cleanup(srv.Close)
+
+	shopID := primitive.NewObjectID()
+	repo := &mockRepository{}
+	_, _ = repo.Create(context.Background(), models.Scope{}, webhook.CreateOptions{
+		ShopID: shopID, URL: srv.URL, EventTypes: []string{shop.EventShopUpdated}, Secret: rcv.secret,
+	})
+
+	now := time.Now()
+	e := event.New(shop.EventShopUpdated, shop.AggregateType, shopID.Hex(), "u1", map[string]interface{}{"name": "A"})
+	e.ShopID = shopID.Hex()
+	publisher := &implPublisher{l: &mockLogger{}, repo: repo, now: func() time.Time { return now }}
+	if err := publisher.Publish(context.Background(), e, e); err != nil {
+		t.Fatalf("Không mong đợi lỗi: %v", err)
+	}
+	if len(repo.deliveries) != 1 {
+		t.Fatalf("Mong đợi 1 delivery cho event nhận 2 lần, nhận được %d", len(repo.deliveries))
+	}
+	return repo, rcv, repo.deliveries[0], now
+}
+
+func TestPublish(t *testing.T) {
+	t.Run("skip unsubscribed event and event without shop", func(t *testing.T) {
+		repo, _, _, _ := setup(t, http.StatusOK)
+		shopID := repo.webhooks[0].ShopID
+		created := event.New(shop.EventShopCreated, shop.AggregateType, shopID.Hex(), "u1", nil)
+		created.ShopID = shopID.Hex()
+		noShop := event.New(shop.EventShopUpdated, shop.AggregateType, "x", "u1", nil)
+
+		publisher := &implPublisher{l: &mockLogger{}, repo: repo, now: time.Now}
+		if err := publisher.Publish(context.Background(), created, noShop); err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+
+		if len(repo.deliveries) != 1 {
+			t.Errorf("Không được tạo delivery cho event không đăng ký, nhận được %d", len(repo.deliveries))
+		}
+	})
+}
+
+func TestDeliver(t *testing.T) {
+	t.Run("deliver with valid signature", func(t *testing.T) {
+		repo, rcv, dl, now := setup(t, http.StatusOK)
+
+		n, err := newTestDeliverer(repo, now).Deliver(context.Background())
+
+		if err != nil || n != 1 {
+			t.Fatalf("Mong đợi gửi 1 delivery, nhận được %d, lỗi %v", n, err)
+		}
+		if rcv.invalid != 0 || len(rcv.received) != 1 || rcv.received[0].ID != dl.Event.ID {
+			t.Errorf("Webhook phải nhận đúng event với chữ ký hợp lệ, nhận được %+v", rcv.received)
+		}
+		got := repo.get(dl.ID)
+		if got.Status != models.DeliverySucceeded || got.LastStatusCode != http.StatusOK || got.DeliveredAt == nil {
+			t.Errorf("Delivery phải được đánh dấu thành công, nhận được %+v", got)
+		}
+	})
+
+	t.Run("retry with backoff then dead letter", func(t *testing.T) {
+		repo, rcv, dl, now := setup(t, http.StatusInternalServerError)
+
+		// Lần 1 thất bại: hẹn gửi lại sau 1s
+		_, _ = newTestDeliverer(repo, now).Deliver(context.Background())
+		got := repo.get(dl.ID)
+		if got.Status != models.DeliveryPending || got.Attempts != 1 || got.LastStatusCode != http.StatusInternalServerError {
+			t.Fatalf("Lần gửi lỗi phải được ghi nhận, nhận được %+v", got)
+		}
+		if !got.NextAttemptAt.Equal(now.Add(time.Second)) {
+			t.Errorf("Mong đợi gửi lại sau 1s, nhận được %v", got.NextAttemptAt.Sub(now))
+		}
+
+		// Chưa tới lúc gửi lại: không gửi
+		_, _ = newTestDeliverer(repo, now.Add(500*time.Millisecond)).Deliver(context.Background())
+		if len(rcv.received) != 1 {
+			t.Errorf("Không được gửi trước thời điểm backoff, webhook đã nhận %d lần", len(rcv.received))
+		}
+
+		// Lần 2 thất bại: backoff nhân đôi
+		_, _ = newTestDeliverer(repo, now.Add(time.Second)).Deliver(context.Background())
+		if got = repo.get(dl.ID); !got.NextAttemptAt.Equal(now.Add(3 * time.Second)) {
+			t.Errorf("Mong đợi gửi lại sau 2s, nhận được %v", got.NextAttemptAt.Sub(now.Add(time.Second)))
+		}
+
+		// Lần 3 thất bại: hết số lần thử, vào dead letter và không được gửi nữa
+		_, _ = newTestDeliverer(repo, now.Add(3*time.Second)).Deliver(context.Background())
+		_, _ = newTestDeliverer(repo, now.Add(time.Hour)).Deliver(context.Background())
+		if got = repo.get(dl.ID); got.Status != models.DeliveryDead || got.Attempts != 3 {
+			t.Errorf("Mong đợi dead letter sau 3 lần, nhận được %+v", got)
+		}
+		if len(rcv.received) != 3 {
+			t.Errorf("Mong đợi webhook nhận 3 lần, nhận được %d", len(rcv.received))
+		}
+
+		// Redeliver: delivery được gửi lại ngay
+		rcv.status = http.StatusNoContent
+		_, _ = repo.Redeliver(context.Background(), dl.ShopID, dl.WebhookID, dl.ID, now.Add(time.Hour))
+		n, _ := newTestDeliverer(repo, now.Add(time.Hour)).Deliver(context.Background())
+		if got = repo.get(dl.ID); n != 1 || got.Status != models.DeliverySucceeded {
+			t.Errorf("Delivery được redeliver phải gửi thành công, nhận được %+v", got)
+		}
+	})
+
+	t.Run("deleted webhook goes to dead letter", func(t *testing.T) {
+		repo, rcv, dl, now := setup(t, http.StatusOK)
+		repo.webhooks = nil
+
+		_, _ = newTestDeliverer(repo, now).Deliver(context.Background())
+
+		if got := repo.get(dl.ID); got.Status != models.DeliveryDead {
+			t.Errorf("Mong đợi dead letter khi webhook bị xóa, nhận được %+v", got)
+		}
+		if len(rcv.received) != 0 {
+			t.Error("Không được gửi tới webhook đã bị xóa")
+		}
+	})
+
+	t.Run("internal address is not dialed", func(t *testing.T) {
+		// Webhook trỏ tới httptest server (127.0.0.1), giống host bị đổi DNS sang IP nội bộ sau khi tạo
+		repo, rcv, dl, now := setup(t, http.StatusOK)
+		d := newTestDeliverer(repo, now)
+		d.client = newClient(time.Second)
+
+		_, _ = d.Deliver(context.Background())
+
+		got := repo.get(dl.ID)
+		if got.Status != models.DeliveryPending || got.LastStatusCode != 0 || !strings.Contains(got.LastError, errAddressNotAllowed.Error()) {
+			t.Errorf("Mong đợi lần gửi bị chặn trước khi kết nối, nhận được %+v", got)
+		}
+		if len(rcv.received) != 0 {
+			t.Error("Không được gửi tới địa chỉ nội bộ")
+		}
+	})
+
+	t.Run("redirect is not followed", func(t *testing.T) {
+		repo, rcv, dl, now := setup(t, http.StatusOK)
+		followed := false
+		target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { followed = true }))
+		t.Cleanup(target.Close)
+		redirect := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+		t.Cleanup(redirect.Close)
+		repo.webhooks[0].URL = redirect.URL
+
+		// Giữ chính sách redirect của newClient, bỏ kiểm tra IP để gọi được httptest server
+		client := newClient(time.Second)
+		client.Transport = http.DefaultTransport
+		d := newTestDeliverer(repo, now)
+		d.client = client
+
+		_, _ = d.Deliver(context.Background())
+
+		if followed || len(rcv.received) != 0 {
+			t.Error("Không được đi theo redirect của webhook")
+		}
+		if got := repo.get(dl.ID); got.Status != models.DeliveryPending || got.LastStatusCode != http.StatusTemporaryRedirect {
+			t.Errorf("Redirect phải được coi là gửi thất bại, nhận được %+v", got)
+		}
+	})
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.215.14", true},
+		{"2606:2800:21f:cb07:6820:80da:af6b:8b2c", true},
+		{"127.0.0.1", false},
+		{"10.0.0.1", false},
+		{"172.16.5.4", false},
+		{"192.168.0.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"224.0.0.1", false},
+		{"::1", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+	for _, tt := range tests {
+		if got := isPublicIP(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("isPublicIP(%s) = %v, mong đợi %v", tt.ip, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"thuchanhgolang/internal/webhook"
+	"thuchanhgolang/pkg/event"
+	"thuchanhgolang/pkg/log"
+)
+
+// implUsecase là implementation của webhook.Usecase interface
+type implUsecase struct {
+	l        log.Logger                                               // Logger để ghi log
+	repo     webhook.Repository                                       // Repository để tương tác với database
+	now      func() time.Time                                         // Đồng hồ, thay được khi test
+	lookupIP func(ctx context.Context, host string) ([]net.IP, error) // Phân giải host của URL, thay được khi test
+}
+
+// NewUsecase tạo usecase mới cho webhook
+func NewUsecase(l log.Logger, repo webhook.Repository) webhook.Usecase {
+	return &implUsecase{
+		l:        l,
+		repo:     repo,
+		now:      time.Now,
+		lookupIP: lookupIP,
+	}
+}
+
+// implPublisher nhận event từ outbox và tạo delivery cho các webhook đã đăng ký
+type implPublisher struct {
+	l    log.Logger
+	repo webhook.Repository
+	now  func() time.Time
+}
+
+// NewPublisher tạo publisher chuyển event thành delivery chờ gửi tới webhook của shop
+func NewPublisher(l log.Logger, repo webhook.Repository) event.Publisher {
+	return &implPublisher{
+		l:    l,
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// implDeliverer là implementation của webhook.Deliverer
+type implDeliverer struct {
+	l      log.Logger         // Logger để ghi log
+	repo   webhook.Repository // Đọc và đánh dấu delivery
+	client *http.Client       // Client gửi request tới webhook
+	opts   webhook.DelivererOptions
+	now    func() time.Time // Đồng hồ, thay được khi test backoff
+}
+
+// NewDeliverer tạo deliverer gửi delivery đang chờ qua client,
+// client nil → client chỉ gọi địa chỉ công khai, không theo redirect, với opts.Timeout
+func NewDeliverer(l log.Logger, repo webhook.Repository, client *http.Client, opts webhook.DelivererOptions) webhook.Deliverer {
+	opts.Adjust()
+	if client == nil {
+		client = newClient(opts.Timeout)
+	}
+	return &implDeliverer{
+		l:      l,
+		repo:   repo,
+		client: client,
+		opts:   opts,
+		now:    time.Now,
+	}
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/pkg/event"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Publish tạo delivery cho mỗi webhook của shop đã đăng ký loại event.
+// Lỗi được trả về để outbox gửi lại event, delivery đã tạo không bị tạo trùng.
+func (p *implPublisher) Publish(ctx context.Context, events ...event.Event) error {
+	for _, e := range events {
+		// Event không gắn với shop (ví dụ user chưa thuộc shop nào) thì không có webhook nhận
+		shopID, err := primitive.ObjectIDFromHex(e.ShopID)
+		if err != nil {
+			continue
+		}
+
+		webhooks, err := p.repo.ListSubscribers(ctx, shopID, e.Type)
+		if err != nil {
+			p.l.Errorf(ctx, "webhook.usecase.Publish.repo.ListSubscribers: %v", err)
+			return err
+		}
+		if len(webhooks) == 0 {
+			continue
+		}
+
+		now := p.now()
+		deliveries := make([]models.WebhookDelivery, 0, len(webhooks))
+		for _, w := range webhooks {
+			deliveries = append(deliveries, models.WebhookDelivery{
+				WebhookID:     w.ID,
+				ShopID:        w.ShopID,
+				Event:         e,
+				Status:        models.DeliveryPending,
+				NextAttemptAt: now,
+				CreatedAt:     now,
+				UpdatedAt:     now,
+			})
+		}
+
+		if err := p.repo.EnqueueDeliveries(ctx, deliveries); err != nil {
+			p.l.Errorf(ctx, "webhook.usecase.Publish.repo.EnqueueDeliveries: %v", err)
+			return err
+		}
+	}
+
+	return nil
+}
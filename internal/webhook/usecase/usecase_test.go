@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/webhook"
+	"thuchanhgolang/pkg/mongo"
+	"thuchanhgolang/pkg/paginator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// mockRepository giữ webhook và delivery trong bộ nhớ
+type mockRepository struct {
+	webhooks   []models.Webhook
+	deliveries []models.WebhookDelivery
+}
+
+func (m *mockRepository) Create(ctx context.Context, sc models.Scope, opts webhook.CreateOptions) (models.Webhook, error) {
+	w := models.Webhook{
+		ID:         primitive.NewObjectID(),
+		ShopID:     opts.ShopID,
+		URL:        opts.URL,
+		EventTypes: opts.EventTypes,
+		Secret:     opts.Secret,
+		Active:     true,
+		Version:    1,
+	}
+	m.webhooks = append(m.webhooks, w)
+	return w, nil
+}
+
+func (m *mockRepository) GetByID(ctx context.Context, shopID, id primitive.ObjectID) (models.Webhook, error) {
+	for _, w := range m.webhooks {
+		if w.ID == id && w.ShopID == shopID {
+			return w, nil
+		}
+	}
+	return models.Webhook{}, mongo.ErrNoDocuments
+}
+
+func (m *mockRepository) List(ctx context.Context, opts webhook.ListOptions) ([]models.Webhook, paginator.Paginator, error) {
+	var out []models.Webhook
+	for _, w := range m.webhooks {
+		if w.ShopID == opts.ShopID {
+			out = append(out, w)
+		}
+	}
+	return out, paginator.Paginator{Total: int64(len(out)), Count: int64(len(out))}, nil
+}
+
+func (m *mockRepository) Update(ctx context.Context, sc models.Scope, opts webhook.UpdateOptions) (models.Webhook, error) {
+	for i, w := range m.webhooks {
+		if w.ID != opts.ID || w.ShopID != opts.ShopID {
+			continue
+		}
+		if opts.Version > 0 && w.Version != opts.Version {
+			return models.Webhook{}, webhook.ErrVersionMismatch
+		}
+		if opts.URL != nil {
+			w.URL = *opts.URL
+		}
+		if opts.EventTypes != nil {
+			w.EventTypes = opts.EventTypes
+		}
+		if opts.Secret != nil {
+			w.Secret = *opts.Secret
+		}
+		if opts.Active != nil {
+			w.Active = *opts.Active
+		}
+		w.Version++
+		m.webhooks[i] = w
+		return w, nil
+	}
+	return models.Webhook{}, mongo.ErrNoDocuments
+}
+
+func (m *mockRepository) Delete(ctx context.Context, shopID, id primitive.ObjectID, version int) error {
+	for i, w := range m.webhooks {
+		if w.ID == id && w.ShopID == shopID {
+			m.webhooks = append(m.webhooks[:i], m.webhooks[i+1:]...)
+			return nil
+		}
+	}
+	return mongo.ErrNoDocuments
+}
+
+func (m *mockRepository) ListSubscribers(ctx context.Context, shopID primitive.ObjectID, eventType string) ([]models.Webhook, error) {
+	var out []models.Webhook
+	for _, w := range m.webhooks {
+		if w.ShopID == shopID && w.Active && w.Subscribes(eventType) {
+			out = append(out, w)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockRepository) EnqueueDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	for _, dl := range deliveries {
+		exists := false
+		for _, d := range m.deliveries {
+			if d.WebhookID == dl.WebhookID && d.Event.ID == dl.Event.ID {
+				exists = true
+			}
+		}
+		if exists {
+			continue
+		}
+		if dl.ID.IsZero() {
+			dl.ID = primitive.NewObjectID()
+		}
+		m.deliveries = append(m.deliveries, dl)
+	}
+	return nil
+}
+
+func (m *mockRepository) ListDueDeliveries(ctx context.Context, now time.Time, limit int64) ([]models.WebhookDelivery, error) {
+	var out []models.WebhookDelivery
+	for _, dl := range m.deliveries {
+		if dl.Status == models.DeliveryPending && !dl.NextAttemptAt.After(now) && int64(len(out)) < limit {
+			out = append(out, dl)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockRepository) ListDeliveries(ctx context.Context, opts webhook.ListDeliveriesOptions) ([]models.WebhookDelivery, paginator.Paginator, error) {
+	var out []models.WebhookDelivery
+	for _, dl := range m.deliveries {
+		if dl.WebhookID == opts.WebhookID && (opts.Status == "" || dl.Status == opts.Status) {
+			out = append(out, dl)
+		}
+	}
+	return out, paginator.Paginator{Total: int64(len(out)), Count: int64(len(out))}, nil
+}
+
+func (m *mockRepository) MarkDelivered(ctx context.Context, opts webhook.MarkDeliveredOptions) error {
+	for i := range m.deliveries {
+		if m.deliveries[i].ID == opts.ID {
+			m.deliveries[i].Status = models.DeliverySucceeded
+			m.deliveries[i].Attempts++
+			m.deliveries[i].LastStatusCode = opts.StatusCode
+			m.deliveries[i].LastError = ""
+			at := opts.At
+			m.deliveries[i].DeliveredAt = &at
+		}
+	}
+	return nil
+}
+
+func (m *mockRepository) MarkFailed(ctx context.Context, opts webhook.MarkFailedOptions) error {
+	for i := range m.deliveries {
+		if m.deliveries[i].ID == opts.ID {
+			m.deliveries[i].Status = models.DeliveryPending
+			if opts.Dead {
+				m.deliveries[i].Status = models.DeliveryDead
+			}
+			m.deliveries[i].Attempts = opts.Attempts
+			m.deliveries[i].NextAttemptAt = opts.NextAttemptAt
+			m.deliveries[i].LastStatusCode = opts.StatusCode
+			m.deliveries[i].LastError = opts.LastError
+		}
+	}
+	return nil
+}
+
+func (m *mockRepository) Redeliver(ctx context.Context, shopID, webhookID, id primitive.ObjectID, at time.Time) (models.WebhookDelivery, error) {
+	for i, dl := range m.deliveries {
+		if dl.ID == id && dl.WebhookID == webhookID && dl.ShopID == shopID {
+			m.deliveries[i].Status = models.DeliveryPending
+			m.deliveries[i].Attempts = 0
+			m.deliveries[i].NextAttemptAt = at
+			return m.deliveries[i], nil
+		}
+	}
+	return models.WebhookDelivery{}, mongo.ErrNoDocuments
+}
+
+// get trả về delivery theo ID
+func (m *mockRepository) get(id primitive.ObjectID) models.WebhookDelivery {
+	for _, dl := range m.deliveries {
+		if dl.ID == id {
+			return dl
+		}
+	}
+	return models.WebhookDelivery{}
+}
+
+type mockLogger struct{}
+
+func (m *mockLogger) Debug(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Debugf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Info(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Infof(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Warn(ctx context.Context, arg ...any)                    {}
+func (m *mockLogger) Warnf(ctx context.Context, template string, arg ...any)  {}
+func (m *mockLogger) Error(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Errorf(ctx context.Context, template string, arg ...any) {}
+func (m *mockLogger) Fatal(ctx context.Context, arg ...any)                   {}
+func (m *mockLogger) Fatalf(ctx context.Context, template string, arg ...any) {}
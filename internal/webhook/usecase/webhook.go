@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/url"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/webhook"
+	pkgErrors "thuchanhgolang/pkg/errors"
+	"thuchanhgolang/pkg/mongo"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// secretPrefix giúp nhận ra secret của webhook khi nó bị lộ trong log hoặc code
+const secretPrefix = "whsec_"
+
+// Create tạo webhook cho shop của user, sinh secret nếu client không gửi
+func (uc *implUsecase) Create(ctx context.Context, sc models.Scope, input webhook.CreateInput) (models.Webhook, error) {
+	// Bước 1: Webhook thuộc shop nên user phải có shop
+	shopID, err := scopeShop(sc)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	// Bước 2: Kiểm tra URL và loại event
+	collector := pkgErrors.NewValidationErrorCollector()
+	uc.validateURL(ctx, collector, input.URL)
+	eventTypes := validateEventTypes(collector, input.EventTypes)
+	if collector.HasError() {
+		return models.Webhook{}, collector
+	}
+
+	// Bước 3: Sinh secret nếu client không gửi
+	secret := input.Secret
+	if secret == "" {
+		secret, err = generateSecret()
+		if err != nil {
+			uc.l.Errorf(ctx, "webhook.usecase.Create.generateSecret: %v", err)
+			return models.Webhook{}, err
+		}
+	}
+
+	// Bước 4: Lưu vào database
+	w, err := uc.repo.Create(ctx, sc, webhook.CreateOptions{
+		ShopID:     shopID,
+		URL:        input.URL,
+		EventTypes: eventTypes,
+		Secret:     secret,
+	})
+	if err != nil {
+		uc.l.Errorf(ctx, "webhook.usecase.Create.repo.Create: %v", err)
+		return models.Webhook{}, err
+	}
+
+	return w, nil
+}
+
+// GetByID lấy webhook theo ID trong shop của user
+func (uc *implUsecase) GetByID(ctx context.Context, sc models.Scope, id primitive.ObjectID) (models.Webhook, error) {
+	shopID, err := scopeShop(sc)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	w, err := uc.repo.GetByID(ctx, shopID, id)
+	if err != nil {
+		uc.l.Errorf(ctx, "webhook.usecase.GetByID.repo.GetByID: %v", err)
+		return models.Webhook{}, notFoundError(err)
+	}
+
+	return w, nil
+}
+
+// List lấy danh sách webhook của shop có phân trang
+func (uc *implUsecase) List(ctx context.Context, sc models.Scope, input webhook.ListInput) (webhook.ListOutput, error) {
+	shopID, err := scopeShop(sc)
+	if err != nil {
+		return webhook.ListOutput{}, err
+	}
+
+	// Đưa page, limit về giá trị mặc định nếu không hợp lệ
+	input.Pagin.Adjust()
+
+	items, pag, err := uc.repo.List(ctx, webhook.ListOptions{ShopID: shopID, Pagin: input.Pagin})
+	if err != nil {
+		uc.l.Errorf(ctx, "webhook.usecase.List.repo.List: %v", err)
+		return webhook.ListOutput{}, err
+	}
+
+	return webhook.ListOutput{Webhooks: items, Pagin: pag}, nil
+}
+
+// Update cập nhật webhook, field nil thì giữ nguyên
+func (uc *implUsecase) Update(ctx context.Context, sc models.Scope, input webhook.UpdateInput) (models.Webhook, error) {
+	// Bước 1: Webhook thuộc shop nên user phải có shop
+	shopID, err := scopeShop(sc)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	// Bước 2: Kiểm tra các field được gửi lên
+	collector := pkgErrors.NewValidationErrorCollector()
+	if input.URL != nil {
+		uc.validateURL(ctx, collector, *input.URL)
+	}
+	var eventTypes []string
+	if input.EventTypes != nil {
+		eventTypes = validateEventTypes(collector, input.EventTypes)
+	}
+	if input.Secret != nil && *input.Secret == "" {
+		collector.Add(pkgErrors.NewValidationError("secret", "is required"))
+	}
+	if collector.HasError() {
+		return models.Webhook{}, collector
+	}
+
+	// Bước 3: Gọi repository để update
+	w, err := uc.repo.Update(ctx, sc, webhook.UpdateOptions{
+		ID:         input.ID,
+		ShopID:     shopID,
+		URL:        input.URL,
+		EventTypes: eventTypes,
+		Secret:     input.Secret,
+		Active:     input.Active,
+		Version:    input.Version,
+	})
+	if err != nil {
+		uc.l.Errorf(ctx, "webhook.usecase.Update.repo.Update: %v", err)
+		return models.Webhook{}, notFoundError(err)
+	}
+
+	return w, nil
+}
+
+// Delete xóa webhook, delivery đang chờ của webhook sẽ vào dead letter khi tới lượt gửi
+func (uc *implUsecase) Delete(ctx context.Context, sc models.Scope, id primitive.ObjectID, version int) error {
+	shopID, err := scopeShop(sc)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repo.Delete(ctx, shopID, id, version); err != nil {
+		uc.l.Errorf(ctx, "webhook.usecase.Delete.repo.Delete: %v", err)
+		return notFoundError(err)
+	}
+
+	return nil
+}
+
+// ListDeliveries lấy delivery log của webhook có phân trang
+func (uc *implUsecase) ListDeliveries(ctx context.Context, sc models.Scope, input webhook.ListDeliveriesInput) (webhook.ListDeliveriesOutput, error) {
+	// Bước 1: Webhook phải tồn tại trong shop của user
+	w, err := uc.GetByID(ctx, sc, input.WebhookID)
+	if err != nil {
+		return webhook.ListDeliveriesOutput{}, err
+	}
+
+	// Bước 2: Kiểm tra bộ lọc trạng thái
+	if input.Status != "" && !input.Status.IsValid() {
+		collector := pkgErrors.NewValidationErrorCollector()
+		collector.Add(pkgErrors.NewValidationError("status", "is not a valid status"))
+		return webhook.ListDeliveriesOutput{}, collector
+	}
+
+	// Bước 3: Lấy delivery log
+	input.Pagin.Adjust()
+	items, pag, err := uc.repo.ListDeliveries(ctx, webhook.ListDeliveriesOptions{
+		ShopID:    w.ShopID,
+		WebhookID: input.WebhookID,
+		Status:    input.Status,
+		Pagin:     input.Pagin,
+	})
+	if err != nil {
+		uc.l.Errorf(ctx, "webhook.usecase.ListDeliveries.repo.ListDeliveries: %v", err)
+		return webhook.ListDeliveriesOutput{}, err
+	}
+
+	return webhook.ListDeliveriesOutput{Deliveries: items, Pagin: pag}, nil
+}
+
+// Redeliver đưa delivery về hàng chờ để deliverer gửi lại ngay ở lượt tiếp theo
+func (uc *implUsecase) Redeliver(ctx context.Context, sc models.Scope, input webhook.RedeliverInput) (models.WebhookDelivery, error) {
+	// Bước 1: Webhook phải tồn tại trong shop của user
+	w, err := uc.GetByID(ctx, sc, input.WebhookID)
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+
+	// Bước 2: Đưa delivery về trạng thái chờ gửi
+	dl, err := uc.repo.Redeliver(ctx, w.ShopID, input.WebhookID, input.DeliveryID, uc.now())
+	if err != nil {
+		uc.l.Errorf(ctx, "webhook.usecase.Redeliver.repo.Redeliver: %v", err)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.WebhookDelivery{}, webhook.ErrDeliveryNotFound
+		}
+		return models.WebhookDelivery{}, err
+	}
+
+	return dl, nil
+}
+
+// scopeShop lấy shop của user, webhook luôn thuộc về một shop
+func scopeShop(sc models.Scope) (primitive.ObjectID, error) {
+	if sc.ShopID == nil || sc.ShopID.IsZero() {
+		return primitive.NilObjectID, webhook.ErrShopRequired
+	}
+	return *sc.ShopID, nil
+}
+
+// validateURL kiểm tra URL là địa chỉ http(s) tuyệt đối và host chỉ phân giải ra địa chỉ công khai.
+// Deliverer vẫn kiểm tra lại IP khi kết nối vì DNS có thể đổi sau lúc tạo webhook.
+func (uc *implUsecase) validateURL(ctx context.Context, collector *pkgErrors.ValidationErrorCollector, raw string) {
+	if raw == "" {
+		collector.Add(pkgErrors.NewValidationError("url", "is required"))
+		return
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		collector.Add(pkgErrors.NewValidationError("url", "is not a valid URL"))
+		return
+	}
+
+	ips, err := uc.lookupIP(ctx, u.Hostname())
+	if err != nil || len(ips) == 0 {
+		uc.l.Warnf(ctx, "webhook.usecase.validateURL.lookupIP: %v", err)
+		collector.Add(pkgErrors.NewValidationError("url", "host cannot be resolved"))
+		return
+	}
+	for _, ip := range ips {
+		if !isPublicIP(ip) {
+			collector.Add(pkgErrors.NewValidationError("url", "must not point to a private or local address"))
+			return
+		}
+	}
+}
+
+// validateEventTypes kiểm tra các loại event và bỏ loại bị lặp, giữ nguyên thứ tự
+func validateEventTypes(collector *pkgErrors.ValidationErrorCollector, types []string) []string {
+	if len(types) == 0 {
+		collector.Add(pkgErrors.NewValidationError("event_types", "is required"))
+		return nil
+	}
+
+	seen := make(map[string]bool, len(types))
+	out := make([]string, 0, len(types))
+	for _, t := range types {
+		if !webhook.IsEventType(t) {
+			collector.Add(pkgErrors.NewValidationError("event_types", "is not a supported event type"))
+			return nil
+		}
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return out
+}
+
+// generateSecret sinh secret ngẫu nhiên 32 byte dạng hex
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return secretPrefix + hex.EncodeToString(b), nil
+}
+
+// notFoundError chuyển lỗi không tìm thấy document thành lỗi domain
+func notFoundError(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return webhook.ErrWebhookNotFound
+	}
+	return err
+}
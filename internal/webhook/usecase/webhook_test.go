@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"thuchanhgolang/internal/models"
+	"thuchanhgolang/internal/shop"
+	"thuchanhgolang/internal/webhook"
+	pkgErrors "thuchanhgolang/pkg/errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeLookupIP phân giải host không cần DNS: IP giữ nguyên, localhost và *.internal có IP nội bộ,
+// *.invalid không phân giải được, còn lại là một IP công khai
+func fakeLookupIP(ctx context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+	switch {
+	case host == "localhost":
+		return []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")}, nil
+	case strings.HasSuffix(host, ".internal"):
+		return []net.IP{net.ParseIP("93.184.215.14"), net.ParseIP("10.0.0.5")}, nil
+	case strings.HasSuffix(host, ".invalid"):
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return []net.IP{net.ParseIP("93.184.215.14")}, nil
+}
+
+// newTestUsecase tạo usecase với repository trong bộ nhớ
+func newTestUsecase() (*implUsecase, *mockRepository) {
+	repo := &mockRepository{}
+	return &implUsecase{l: &mockLogger{}, repo: repo, now: time.Now, lookupIP: fakeLookupIP}, repo
+}
+
+// shopScope tạo scope của manager thuộc shop
+func shopScope(shopID primitive.ObjectID) models.Scope {
+	return models.Scope{UserID: "manager", Role: models.RoleManager, ShopID: &shopID}
+}
+
+func TestCreate(t *testing.T) {
+	shopID := primitive.NewObjectID()
+
+	t.Run("create with generated secret", func(t *testing.T) {
+		uc, _ := newTestUsecase()
+		input := webhook.CreateInput{
+			URL:        "https://example.com/hook",
+			EventTypes: []string{shop.EventShopUpdated, shop.EventShopUpdated, models.WebhookAllEvents},
+		}
+
+		w, err := uc.Create(context.Background(), shopScope(shopID), input)
+
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if w.ShopID != shopID || !w.Active {
+			t.Errorf("Webhook phải thuộc shop của user và được bật, nhận được %+v", w)
+		}
+		if !strings.HasPrefix(w.Secret, secretPrefix) || len(w.Secret) != len(secretPrefix)+64 {
+			t.Errorf("Mong đợi secret được sinh ngẫu nhiên, nhận được %q", w.Secret)
+		}
+		if len(w.EventTypes) != 2 {
+			t.Errorf("Loại event bị lặp phải được bỏ, nhận được %v", w.EventTypes)
+		}
+	})
+
+	t.Run("keep secret from client", func(t *testing.T) {
+		uc, _ := newTestUsecase()
+		input := webhook.CreateInput{URL: "http://hooks.example.com:9000", EventTypes: []string{"*"}, Secret: "my-secret"}
+
+		w, err := uc.Create(context.Background(), shopScope(shopID), input)
+
+		if err != nil || w.Secret != "my-secret" {
+			t.Errorf("Mong đợi giữ secret của client, nhận được %q, lỗi %v", w.Secret, err)
+		}
+	})
+
+	t.Run("invalid url and event type", func(t *testing.T) {
+		uc, repo := newTestUsecase()
+		input := webhook.CreateInput{URL: "ftp://example.com", EventTypes: []string{"shop.exploded"}}
+
+		_, err := uc.Create(context.Background(), shopScope(shopID), input)
+
+		var collector *pkgErrors.ValidationErrorCollector
+		if !errors.As(err, &collector) || len(collector.Errors()) != 2 {
+			t.Fatalf("Mong đợi 2 lỗi validation, nhận được %v", err)
+		}
+		if len(repo.webhooks) != 0 {
+			t.Error("Không được lưu webhook không hợp lệ")
+		}
+	})
+
+	t.Run("url pointing to internal address", func(t *testing.T) {
+		urls := []string{
+			"http://localhost:9000",
+			"http://127.0.0.1/admin",
+			"http://10.1.2.3",
+			"http://192.168.1.1",
+			"http://169.254.169.254/latest/meta-data",
+			"http://[::1]:8080",
+			"http://[fe80::1]",
+			"http://100.64.0.1",
+			"https://api.internal/hook",
+		}
+		for _, u := range urls {
+			uc, repo := newTestUsecase()
+
+			_, err := uc.Create(context.Background(), shopScope(shopID), webhook.CreateInput{URL: u, EventTypes: []string{"*"}})
+
+			var collector *pkgErrors.ValidationErrorCollector
+			if !errors.As(err, &collector) || collector.Errors()[0].Field != "url" {
+				t.Errorf("Mong đợi URL %s bị từ chối, nhận được %v", u, err)
+			}
+			if len(repo.webhooks) != 0 {
+				t.Errorf("Không được lưu webhook trỏ tới %s", u)
+			}
+		}
+	})
+
+	t.Run("url host cannot be resolved", func(t *testing.T) {
+		uc, _ := newTestUsecase()
+
+		_, err := uc.Create(context.Background(), shopScope(shopID), webhook.CreateInput{URL: "https://hooks.invalid", EventTypes: []string{"*"}})
+
+		var collector *pkgErrors.ValidationErrorCollector
+		if !errors.As(err, &collector) {
+			t.Errorf("Mong đợi lỗi validation, nhận được %v", err)
+		}
+	})
+
+	t.Run("user without shop", func(t *testing.T) {
+		uc, _ := newTestUsecase()
+
+		_, err := uc.Create(context.Background(), models.Scope{UserID: "admin"}, webhook.CreateInput{URL: "https://example.com", EventTypes: []string{"*"}})
+
+		if !errors.Is(err, webhook.ErrShopRequired) {
+			t.Errorf("Mong đợi ErrShopRequired, nhận được %v", err)
+		}
+	})
+}
+
+func TestUpdateURL(t *testing.T) {
+	shopID := primitive.NewObjectID()
+	uc, repo := newTestUsecase()
+	w, _ := uc.Create(context.Background(), shopScope(shopID), webhook.CreateInput{URL: "https://example.com", EventTypes: []string{"*"}})
+	url := "http://169.254.169.254/latest/meta-data"
+
+	_, err := uc.Update(context.Background(), shopScope(shopID), webhook.UpdateInput{ID: w.ID, URL: &url, Version: w.Version})
+
+	var collector *pkgErrors.ValidationErrorCollector
+	if !errors.As(err, &collector) {
+		t.Fatalf("Mong đợi lỗi validation, nhận được %v", err)
+	}
+	if repo.webhooks[0].URL != "https://example.com" {
+		t.Errorf("URL không được đổi sang địa chỉ nội bộ, nhận được %s", repo.webhooks[0].URL)
+	}
+}
+
+func TestGetByID(t *testing.T) {
+	t.Run("webhook of another shop is not found", func(t *testing.T) {
+		uc, _ := newTestUsecase()
+		w, _ := uc.Create(context.Background(), shopScope(primitive.NewObjectID()), webhook.CreateInput{URL: "https://example.com", EventTypes: []string{"*"}})
+
+		_, err := uc.GetByID(context.Background(), shopScope(primitive.NewObjectID()), w.ID)
+
+		if !errors.Is(err, webhook.ErrWebhookNotFound) {
+			t.Errorf("Mong đợi ErrWebhookNotFound, nhận được %v", err)
+		}
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	shopID := primitive.NewObjectID()
+
+	t.Run("disable webhook", func(t *testing.T) {
+		uc, _ := newTestUsecase()
+		w, _ := uc.Create(context.Background(), shopScope(shopID), webhook.CreateInput{URL: "https://example.com", EventTypes: []string{"*"}})
+		active := false
+
+		updated, err := uc.Update(context.Background(), shopScope(shopID), webhook.UpdateInput{ID: w.ID, Active: &active, Version: w.Version})
+
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if updated.Active || updated.Version != w.Version+1 {
+			t.Errorf("Mong đợi webhook bị tắt và tăng version, nhận được %+v", updated)
+		}
+	})
+
+	t.Run("empty secret", func(t *testing.T) {
+		uc, _ := newTestUsecase()
+		secret := ""
+
+		_, err := uc.Update(context.Background(), shopScope(shopID), webhook.UpdateInput{ID: primitive.NewObjectID(), Secret: &secret})
+
+		var collector *pkgErrors.ValidationErrorCollector
+		if !errors.As(err, &collector) {
+			t.Errorf("Mong đợi lỗi validation, nhận được %v", err)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		uc, _ := newTestUsecase()
+		url := "https://example.com"
+
+		_, err := uc.Update(context.Background(), shopScope(shopID), webhook.UpdateInput{ID: primitive.NewObjectID(), URL: &url})
+
+		if !errors.Is(err, webhook.ErrWebhookNotFound) {
+			t.Errorf("Mong đợi ErrWebhookNotFound, nhận được %v", err)
+		}
+	})
+}
+
+func TestRedeliver(t *testing.T) {
+	shopID := primitive.NewObjectID()
+
+	t.Run("reset dead delivery", func(t *testing.T) {
+		uc, repo := newTestUsecase()
+		w, _ := uc.Create(context.Background(), shopScope(shopID), webhook.CreateInput{URL: "https://example.com", EventTypes: []string{"*"}})
+		dl := models.WebhookDelivery{ID: primitive.NewObjectID(), WebhookID: w.ID, ShopID: shopID, Status: models.DeliveryDead, Attempts: 8}
+		repo.deliveries = append(repo.deliveries, dl)
+
+		got, err := uc.Redeliver(context.Background(), shopScope(shopID), webhook.RedeliverInput{WebhookID: w.ID, DeliveryID: dl.ID})
+
+		if err != nil {
+			t.Fatalf("Không mong đợi lỗi: %v", err)
+		}
+		if got.Status != models.DeliveryPending || got.Attempts != 0 {
+			t.Errorf("Mong đợi delivery chờ gửi lại với số lần thử mới, nhận được %+v", got)
+		}
+	})
+
+	t.Run("delivery not found", func(t *testing.T) {
+		uc, _ := newTestUsecase()
+		w, _ := uc.Create(context.Background(), shopScope(shopID), webhook.CreateInput{URL: "https://example.com", EventTypes: []string{"*"}})
+
+		_, err := uc.Redeliver(context.Background(), shopScope(shopID), webhook.RedeliverInput{WebhookID: w.ID, DeliveryID: primitive.NewObjectID()})
+
+		if !errors.Is(err, webhook.ErrDeliveryNotFound) {
+			t.Errorf("Mong đợi ErrDeliveryNotFound, nhận được %v", err)
+		}
+	})
+}
+
+func TestListDeliveries(t *testing.T) {
+	t.Run("invalid status", func(t *testing.T) {
+		shopID := primitive.NewObjectID()
+		uc, _ := newTestUsecase()
+		w, _ := uc.Create(context.Background(), shopScope(shopID), webhook.CreateInput{URL: "https://example.com", EventTypes: []string{"*"}})
+
+		_, err := uc.ListDeliveries(context.Background(), shopScope(shopID), webhook.ListDeliveriesInput{WebhookID: w.ID, Status: "lost"})
+
+		var collector *pkgErrors.ValidationErrorCollector
+		if !errors.As(err, &collector) {
+			t.Errorf("Mong đợi lỗi validation, nhận được %v", err)
+		}
+	})
+}
@@ -0,0 +1,141 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Binding tags of the custom validators registered by RegisterValidators.
+const (
+	// TagNotBlank rejects strings made only of whitespace.
+	TagNotBlank = "notblank"
+	// TagObjectID accepts a hex encoded MongoDB ObjectID.
+	TagObjectID = "objectid"
+	// TagRole accepts the roles reported valid by the isRole function given to RegisterValidators.
+	TagRole = "role"
+	// TagShopCode accepts 2-20 upper case letters, digits, "-" or "_", starting with a letter or a digit.
+	TagShopCode = "shopcode"
+	// TagUsername accepts 3-32 letters, digits, ".", "-" or "_", starting with a letter or a digit.
+	TagUsername = "username"
+)
+
+var (
+	shopCodePattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9_-]{1,19}$`)
+	usernamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{2,31}$`)
+)
+
+// RegisterValidators registers the custom validators on v and makes field errors
+// use the JSON names of the fields. isRole reports whether a string is a valid role.
+func RegisterValidators(v *validator.Validate, isRole func(string) bool) error {
+	v.RegisterTagNameFunc(jsonFieldName)
+
+	validators := map[string]validator.Func{
+		TagNotBlank: func(fl validator.FieldLevel) bool {
+			return strings.TrimSpace(fl.Field().String()) != ""
+		},
+		TagObjectID: func(fl validator.FieldLevel) bool {
+			return primitive.IsValidObjectID(fl.Field().String())
+		},
+		TagRole: func(fl validator.FieldLevel) bool {
+			return isRole(fl.Field().String())
+		},
+		TagShopCode: func(fl validator.FieldLevel) bool {
+			return shopCodePattern.MatchString(fl.Field().String())
+		},
+		TagUsername: func(fl validator.FieldLevel) bool {
+			return usernamePattern.MatchString(fl.Field().String())
+		},
+	}
+	for tag, fn := range validators {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BindingError translates the validation errors of a binding into a ValidationErrorCollector
+// with one error per field. Other errors (malformed JSON, wrong types) are replaced by fallback.
+func BindingError(err error, fallback error) error {
+	var vErrs validator.ValidationErrors
+	if !errors.As(err, &vErrs) {
+		return fallback
+	}
+
+	collector := NewValidationErrorCollector()
+	for _, fe := range vErrs {
+		collector.Add(NewValidationError(fieldPath(fe), bindingMessage(fe)))
+	}
+	return collector
+}
+
+// bindingMessage returns the validation message of a failed tag, in the English format of the i18n catalog
+func bindingMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", TagNotBlank:
+		return "is required"
+	case "email":
+		return "is not a valid email"
+	case "url", "http_url":
+		return "is not a valid URL"
+	case TagObjectID:
+		return "is not a valid ID"
+	case TagRole:
+		return "is not a valid role"
+	case TagShopCode:
+		return "is not a valid shop code"
+	case TagUsername:
+		return "is not a valid username"
+	case "oneof":
+		return fmt.Sprintf("must be one of %s", fe.Param())
+	case "min", "max", "len":
+		return lengthMessage(fe)
+	default:
+		return "is not valid"
+	}
+}
+
+// lengthFormats are the messages of the min, max and len tags by the kind of the field
+var lengthFormats = map[string]map[reflect.Kind]string{
+	"min": {reflect.String: "must be at least %s characters", reflect.Slice: "must have at least %s items", reflect.Invalid: "must be greater than or equal to %s"},
+	"max": {reflect.String: "must be at most %s characters", reflect.Slice: "must have at most %s items", reflect.Invalid: "must be less than or equal to %s"},
+	"len": {reflect.String: "must be exactly %s characters", reflect.Slice: "must have exactly %s items", reflect.Invalid: "must equal %s"},
+}
+
+// lengthMessage returns the message of a min, max or len tag, counted in characters for strings
+// and in items for slices and maps
+func lengthMessage(fe validator.FieldError) string {
+	kind := fe.Kind()
+	switch kind {
+	case reflect.String, reflect.Slice:
+	case reflect.Array, reflect.Map:
+		kind = reflect.Slice
+	default:
+		kind = reflect.Invalid
+	}
+	return fmt.Sprintf(lengthFormats[fe.Tag()][kind], fe.Param())
+}
+
+// fieldPath returns the field of the error without the struct name, for example event_types[0]
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return fe.Field()
+}
+
+// jsonFieldName returns the JSON name of a struct field, fields skipped by encoding/json keep their Go name
+func jsonFieldName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return f.Name
+	}
+	return name
+}
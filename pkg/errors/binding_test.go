@@ -0,0 +1,191 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// bindingRequest is a sample request using the binding tags of the handlers
+type bindingRequest struct {
+	Name       string         `json:"name" binding:"notblank,max=10"`
+	Username   string         `json:"username,omitempty" binding:"omitempty,username"`
+	ShopCode   string         `json:"shop_code" binding:"omitempty,shopcode"`
+	ShopID     string         `json:"shop_id" binding:"omitempty,objectid"`
+	Role       string         `json:"role" binding:"omitempty,role"`
+	Email      string         `json:"email" binding:"omitempty,email"`
+	URL        string         `json:"url" binding:"omitempty,url"`
+	Status     string         `json:"status" binding:"omitempty,oneof=active inactive"`
+	EventTypes []string       `json:"event_types" binding:"max=2,dive,notblank"`
+	Labels     map[string]int `json:"labels" binding:"max=1"`
+	Limit      int            `json:"limit" binding:"max=100"`
+	Internal   string         `json:"-" binding:"max=1"`
+}
+
+func newBindingValidator(t *testing.T) *validator.Validate {
+	t.Helper()
+	v := validator.New()
+	v.SetTagName("binding")
+	isRole := func(s string) bool { return s == "manager" || s == "employee" }
+	if err := RegisterValidators(v, isRole); err != nil {
+		t.Fatalf("Không mong đợi lỗi: %v", err)
+	}
+	return v
+}
+
+// collectorFields returns the messages of a validation error by field
+func collectorFields(t *testing.T, err error) map[string][]string {
+	t.Helper()
+	var vErr *ValidationErrorCollector
+	if !errors.As(err, &vErr) {
+		t.Fatalf("Mong đợi ValidationErrorCollector, nhận được %v", err)
+	}
+	fields := make(map[string][]string)
+	for _, e := range vErr.Errors() {
+		fields[e.Field] = append(fields[e.Field], e.Messages...)
+	}
+	return fields
+}
+
+func TestBindingError(t *testing.T) {
+	v := newBindingValidator(t)
+	fallback := errors.New("wrong body")
+
+	tests := []struct {
+		name   string
+		modify func(r *bindingRequest)
+		want   map[string][]string
+	}{
+		{
+			name:   "notblank chỉ có khoảng trắng",
+			modify: func(r *bindingRequest) { r.Name = "   " },
+			want:   map[string][]string{"name": {"is required"}},
+		},
+		{
+			name:   "max của string đếm theo ký tự",
+			modify: func(r *bindingRequest) { r.Name = "abcdefghijk" },
+			want:   map[string][]string{"name": {"must be at most 10 characters"}},
+		},
+		{
+			name:   "username không hợp lệ",
+			modify: func(r *bindingRequest) { r.Username = ".bob" },
+			want:   map[string][]string{"username": {"is not a valid username"}},
+		},
+		{
+			name:   "username quá ngắn",
+			modify: func(r *bindingRequest) { r.Username = "bo" },
+			want:   map[string][]string{"username": {"is not a valid username"}},
+		},
+		{
+			name:   "shop code chữ thường",
+			modify: func(r *bindingRequest) { r.ShopCode = "shop-1" },
+			want:   map[string][]string{"shop_code": {"is not a valid shop code"}},
+		},
+		{
+			name:   "objectid không hợp lệ",
+			modify: func(r *bindingRequest) { r.ShopID = "123" },
+			want:   map[string][]string{"shop_id": {"is not a valid ID"}},
+		},
+		{
+			name:   "role không hợp lệ",
+			modify: func(r *bindingRequest) { r.Role = "admin" },
+			want:   map[string][]string{"role": {"is not a valid role"}},
+		},
+		{
+			name:   "email không hợp lệ",
+			modify: func(r *bindingRequest) { r.Email = "bob" },
+			want:   map[string][]string{"email": {"is not a valid email"}},
+		},
+		{
+			name:   "url không hợp lệ",
+			modify: func(r *bindingRequest) { r.URL = "not a url" },
+			want:   map[string][]string{"url": {"is not a valid URL"}},
+		},
+		{
+			name:   "oneof",
+			modify: func(r *bindingRequest) { r.Status = "deleted" },
+			want:   map[string][]string{"status": {"must be one of active inactive"}},
+		},
+		{
+			name:   "max của slice đếm theo phần tử",
+			modify: func(r *bindingRequest) { r.EventTypes = []string{"a", "b", "c"} },
+			want:   map[string][]string{"event_types": {"must have at most 2 items"}},
+		},
+		{
+			name:   "dive trả về vị trí phần tử lỗi",
+			modify: func(r *bindingRequest) { r.EventTypes = []string{"user.created", " "} },
+			want:   map[string][]string{"event_types[1]": {"is required"}},
+		},
+		{
+			name:   "max của map đếm theo phần tử",
+			modify: func(r *bindingRequest) { r.Labels = map[string]int{"a": 1, "b": 2} },
+			want:   map[string][]string{"labels": {"must have at most 1 items"}},
+		},
+		{
+			name:   "max của số",
+			modify: func(r *bindingRequest) { r.Limit = 101 },
+			want:   map[string][]string{"limit": {"must be less than or equal to 100"}},
+		},
+		{
+			name:   "field bỏ qua khi encode JSON giữ tên Go",
+			modify: func(r *bindingRequest) { r.Internal = "ab" },
+			want:   map[string][]string{"Internal": {"must be at most 1 characters"}},
+		},
+		{
+			name: "mỗi field một lỗi",
+			modify: func(r *bindingRequest) {
+				r.Name = ""
+				r.Role = "admin"
+				r.EventTypes = []string{""}
+			},
+			want: map[string][]string{
+				"name":           {"is required"},
+				"role":           {"is not a valid role"},
+				"event_types[0]": {"is required"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := bindingRequest{Name: "Bob", Username: "bob.smith", ShopCode: "SHOP-1", ShopID: "665f1c2e9b1e8a3d4c5b6a79", Role: "manager"}
+			if err := v.Struct(req); err != nil {
+				t.Fatalf("Request mẫu phải hợp lệ: %v", err)
+			}
+
+			tt.modify(&req)
+			err := BindingError(v.Struct(req), fallback)
+			if fields := collectorFields(t, err); !reflect.DeepEqual(fields, tt.want) {
+				t.Errorf("Mong đợi lỗi %v, nhận được %v", tt.want, fields)
+			}
+		})
+	}
+}
+
+func TestBindingErrorFallback(t *testing.T) {
+	fallback := errors.New("wrong body")
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "JSON sai cú pháp", body: `{"name": "Bob"`},
+		{name: "sai kiểu dữ liệu", body: `{"limit": "ten"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req bindingRequest
+			err := json.Unmarshal([]byte(tt.body), &req)
+			if err == nil {
+				t.Fatal("Mong đợi lỗi khi đọc JSON")
+			}
+			if got := BindingError(err, fallback); got != fallback {
+				t.Errorf("Mong đợi lỗi %v, nhận được %v", fallback, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package errors
+
+// ConflictError is an error returned when a unique value is already taken (HTTP 409).
+type ConflictError struct {
+	Code    int
+	Message string
+	Field   string
+}
+
+// NewConflictError returns a new ConflictError with the given code, message and conflicting field.
+func NewConflictError(code int, message, field string) *ConflictError {
+	return &ConflictError{
+		Code:    code,
+		Message: message,
+		Field:   field,
+	}
+}
+
+// Error returns the error message.
+func (e ConflictError) Error() string {
+	return e.Message
+}
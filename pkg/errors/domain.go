@@ -0,0 +1,65 @@
+package errors
+
+import "errors"
+
+// Kind is the category of a domain error, used by the response layer to pick the HTTP status.
+type Kind int
+
+const (
+	// KindNotFound means the requested resource does not exist (HTTP 404).
+	KindNotFound Kind = iota + 1
+	// KindConflict means the request conflicts with the current state (HTTP 409).
+	KindConflict
+	// KindForbidden means the caller is not allowed to act on the resource (HTTP 403).
+	KindForbidden
+	// KindValidation means the request is well-formed but its values are not acceptable (HTTP 422).
+	KindValidation
+	// KindPreconditionFailed means a precondition of the request does not hold (HTTP 412).
+	KindPreconditionFailed
+)
+
+// DomainError is an error returned by repositories and usecases together with its kind.
+// Domain packages declare them as sentinel variables so they can still be compared with errors.Is.
+type DomainError struct {
+	Kind    Kind
+	Message string
+}
+
+// NotFound returns a new DomainError of kind KindNotFound.
+func NotFound(message string) *DomainError {
+	return &DomainError{Kind: KindNotFound, Message: message}
+}
+
+// Conflict returns a new DomainError of kind KindConflict.
+func Conflict(message string) *DomainError {
+	return &DomainError{Kind: KindConflict, Message: message}
+}
+
+// Forbidden returns a new DomainError of kind KindForbidden.
+func Forbidden(message string) *DomainError {
+	return &DomainError{Kind: KindForbidden, Message: message}
+}
+
+// Invalid returns a new DomainError of kind KindValidation.
+func Invalid(message string) *DomainError {
+	return &DomainError{Kind: KindValidation, Message: message}
+}
+
+// PreconditionFailed returns a new DomainError of kind KindPreconditionFailed.
+func PreconditionFailed(message string) *DomainError {
+	return &DomainError{Kind: KindPreconditionFailed, Message: message}
+}
+
+// Error returns the error message.
+func (e DomainError) Error() string {
+	return e.Message
+}
+
+// KindOf returns the kind of the first DomainError in err's chain, or 0 if there is none.
+func KindOf(err error) Kind {
+	var dErr *DomainError
+	if errors.As(err, &dErr) {
+		return dErr.Kind
+	}
+	return 0
+}
@@ -16,6 +16,15 @@ func NewHTTPError(code int, message string) *HTTPError {
 	}
 }
 
+// NewHTTPErrorWithStatus returns a new HTTPError with the given code, message and HTTP status.
+func NewHTTPErrorWithStatus(code int, message string, statusCode int) *HTTPError {
+	return &HTTPError{
+		Code:       code,
+		Message:    message,
+		StatusCode: statusCode,
+	}
+}
+
 // NewUnauthorizedHTTPError returns a new HTTPError with the given code and message.
 func NewUnauthorizedHTTPError() *HTTPError {
 	return &HTTPError{
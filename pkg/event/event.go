@@ -0,0 +1,49 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Event là một sự kiện nghiệp vụ đã xảy ra (ví dụ branch được chuyển region)
+type Event struct {
+	ID            string                 `json:"id" bson:"_id"`
+	Type          string                 `json:"type" bson:"type"`                     // Ví dụ: "branch.moved"
+	AggregateType string                 `json:"aggregate_type" bson:"aggregate_type"` // Ví dụ: "branch"
+	AggregateID   string                 `json:"aggregate_id" bson:"aggregate_id"`
+	ActorID       string                 `json:"actor_id,omitempty" bson:"actor_id,omitempty"` // User thực hiện
+	Location      `bson:",inline"`       // Vị trí của aggregate trong cây tổ chức
+	Payload       map[string]interface{} `json:"payload" bson:"payload"`
+	OccurredAt    time.Time              `json:"occurred_at" bson:"occurred_at"`
+}
+
+// Location là vị trí của aggregate trong cây tổ chức Shop → Region → Branch → Department,
+// dùng để gửi webhook theo shop và lọc event theo quyền của người nhận
+type Location struct {
+	ShopID       string `json:"shop_id,omitempty" bson:"shop_id,omitempty"`
+	RegionID     string `json:"region_id,omitempty" bson:"region_id,omitempty"`
+	BranchID     string `json:"branch_id,omitempty" bson:"branch_id,omitempty"`
+	DepartmentID string `json:"department_id,omitempty" bson:"department_id,omitempty"`
+}
+
+// New tạo event mới với ID và thời điểm hiện tại
+func New(typ, aggregateType, aggregateID, actorID string, payload map[string]interface{}) Event {
+	return Event{
+		ID:            primitive.NewObjectID().Hex(),
+		Type:          typ,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		ActorID:       actorID,
+		Payload:       payload,
+		OccurredAt:    time.Now(),
+	}
+}
+
+// Publisher phát event ra bên ngoài
+//
+//go:generate mockery --name=Publisher --output=mocks --case=underscore
+type Publisher interface {
+	Publish(ctx context.Context, events ...Event) error
+}
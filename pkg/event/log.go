@@ -0,0 +1,23 @@
+package event
+
+import (
+	"context"
+
+	"thuchanhgolang/pkg/log"
+)
+
+type logPublisher struct {
+	l log.Logger
+}
+
+// NewLogPublisher tạo Publisher chỉ ghi event ra log
+func NewLogPublisher(l log.Logger) Publisher {
+	return &logPublisher{l: l}
+}
+
+func (p *logPublisher) Publish(ctx context.Context, events ...Event) error {
+	for _, e := range events {
+		p.l.Infof(ctx, "event.Publish: type=%s aggregate=%s/%s actor=%s payload=%v", e.Type, e.AggregateType, e.AggregateID, e.ActorID, e.Payload)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package event
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryPublisher giữ event trong bộ nhớ, dùng cho test hoặc khi chạy local
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []Event
+	err    error
+}
+
+// NewMemoryPublisher tạo MemoryPublisher rỗng
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+// Publish lưu event vào bộ nhớ, trả lỗi đã đặt bằng FailWith nếu có
+func (p *MemoryPublisher) Publish(ctx context.Context, events ...Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+	p.events = append(p.events, events...)
+	return nil
+}
+
+// Events trả về bản sao các event đã nhận theo đúng thứ tự
+func (p *MemoryPublisher) Events() []Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	out := make([]Event, len(p.events))
+	copy(out, p.events)
+	return out
+}
+
+// FailWith khiến các lần Publish sau trả về err, truyền nil để hoạt động lại bình thường
+func (p *MemoryPublisher) FailWith(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.err = err
+}
@@ -0,0 +1,22 @@
+package event
+
+import "context"
+
+type multiPublisher struct {
+	publishers []Publisher
+}
+
+// NewMultiPublisher tạo Publisher gửi event lần lượt tới từng publisher, dừng ở lỗi đầu tiên.
+// Khi có lỗi, các publisher đứng trước có thể nhận lại event ở lần gửi sau nên phải chịu được event trùng.
+func NewMultiPublisher(publishers ...Publisher) Publisher {
+	return &multiPublisher{publishers: publishers}
+}
+
+func (p *multiPublisher) Publish(ctx context.Context, events ...Event) error {
+	for _, pub := range p.publishers {
+		if err := pub.Publish(ctx, events...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package i18n
+
+import "thuchanhgolang/pkg/util"
+
+const (
+	vi = util.ViLanguage
+	en = util.EnLanguage
+)
+
+// codeMessages is the message catalog of error codes.
+// Ranges: generic HTTP codes, shop 10xxx, region 11xxx, branch 12xxx, department 13xxx,
+// user 30xxx, auth 40xxx, cascade 50xxx, export 60xxx, webhook 70xxx, idempotency 80xxx,
+// rate limit 90xxx.
+// Generic codes only have Vietnamese: in English the original message is more specific.
+var codeMessages = map[int]Text{
+	// Generic
+	401: {vi: "Bạn chưa đăng nhập hoặc phiên đăng nhập đã hết hạn"},
+	403: {vi: "Bạn không có quyền truy cập tài nguyên này"},
+	404: {vi: "Không tìm thấy dữ liệu"},
+	409: {vi: "Dữ liệu bị xung đột với dữ liệu hiện có"},
+	412: {vi: "Điều kiện của yêu cầu không được thỏa mãn"},
+	428: {vi: "Yêu cầu phải có điều kiện"},
+	422: {vi: "Dữ liệu không hợp lệ"},
+	500: {vi: "Đã có lỗi xảy ra"},
+
+	// Shop
+	10000: {vi: "Dữ liệu gửi lên không hợp lệ", en: "Wrong body"},
+	10001: {vi: "ID shop không hợp lệ", en: "Invalid shop ID"},
+	10003: {vi: "Shop đang có region, không thể xóa", en: "Shop is being used by regions, cannot delete"},
+	10005: {vi: "Không tìm thấy shop", en: "Shop not found"},
+	10009: {vi: "Mã shop đã tồn tại", en: "Shop code already exists"},
+	10010: {vi: "Shop đã bị thay đổi bởi yêu cầu khác, hãy tải lại", en: "Shop has been modified by another request, reload and try again"},
+	10011: {vi: "Cần gửi header If-Match với ETag của shop", en: "If-Match header with the shop ETag is required"},
+	10012: {vi: "Tham số truy vấn không hợp lệ", en: "Wrong query"},
+
+	// Region
+	11000: {vi: "Dữ liệu gửi lên không hợp lệ", en: "Wrong body"},
+	11001: {vi: "ID region không hợp lệ", en: "Invalid region ID"},
+	11002: {vi: "ID shop không hợp lệ", en: "Invalid shop ID"},
+	11004: {vi: "Region đang có branch, không thể xóa", en: "Region is being used by branches, cannot delete"},
+	11005: {vi: "Không tìm thấy region", en: "Region not found"},
+	11006: {vi: "Region đã bị thay đổi bởi yêu cầu khác, hãy tải lại", en: "Region has been modified by another request, reload and try again"},
+	11007: {vi: "Cần gửi header If-Match với ETag của region", en: "If-Match header with the region ETag is required"},
+	11008: {vi: "Tham số truy vấn không hợp lệ", en: "Wrong query"},
+
+	// Branch
+	12000: {vi: "Dữ liệu gửi lên không hợp lệ", en: "Wrong body"},
+	12001: {vi: "ID branch không hợp lệ", en: "Invalid branch ID"},
+	12002: {vi: "ID region không hợp lệ", en: "Invalid region ID"},
+	12004: {vi: "Branch đang có department, không thể xóa", en: "Branch is being used by departments, cannot delete"},
+	12005: {vi: "Không tìm thấy branch", en: "Branch not found"},
+	12006: {vi: "Không tìm thấy region đích", en: "Target region not found"},
+	12007: {vi: "Region đích thuộc shop khác", en: "Target region belongs to another shop"},
+	12008: {vi: "Branch hoặc region đích nằm ngoài phạm vi của bạn", en: "Branch or target region is outside of your scope"},
+	12009: {vi: "Branch đã bị thay đổi bởi yêu cầu khác, hãy tải lại", en: "Branch has been modified by another request, reload and try again"},
+	12010: {vi: "Cần gửi header If-Match với ETag của branch", en: "If-Match header with the branch ETag is required"},
+	12011: {vi: "Tham số truy vấn không hợp lệ", en: "Wrong query"},
+
+	// Department
+	13000: {vi: "Dữ liệu gửi lên không hợp lệ", en: "Wrong body"},
+	13001: {vi: "ID department không hợp lệ", en: "Invalid department ID"},
+	13002: {vi: "ID branch không hợp lệ", en: "Invalid branch ID"},
+	13004: {vi: "Department đang có user, không thể xóa", en: "Department is being used by users, cannot delete"},
+	13005: {vi: "Không tìm thấy department", en: "Department not found"},
+	13006: {vi: "Không tìm thấy branch đích", en: "Target branch not found"},
+	13007: {vi: "Branch đích thuộc shop khác", en: "Target branch belongs to another shop"},
+	13008: {vi: "Department hoặc branch đích nằm ngoài phạm vi của bạn", en: "Department or target branch is outside of your scope"},
+	13009: {vi: "Department đã bị thay đổi bởi yêu cầu khác, hãy tải lại", en: "Department has been modified by another request, reload and try again"},
+	13010: {vi: "Cần gửi header If-Match với ETag của department", en: "If-Match header with the department ETag is required"},
+	13011: {vi: "Tham số truy vấn không hợp lệ", en: "Wrong query"},
+
+	// User
+	30000: {vi: "Dữ liệu gửi lên không hợp lệ", en: "Wrong body"},
+	30001: {vi: "ID user không hợp lệ", en: "Invalid user ID"},
+	30004: {vi: "ID branch không hợp lệ", en: "Invalid branch ID"},
+	30005: {vi: "ID department không hợp lệ", en: "Invalid department ID"},
+	30006: {vi: "User đang được sử dụng, không thể xóa", en: "User is being used, cannot delete"},
+	30007: {vi: "User nằm ngoài phạm vi của bạn", en: "User is outside of your scope"},
+	30008: {vi: "Tham số truy vấn không hợp lệ", en: "Wrong query"},
+	30009: {vi: "File CSV không hợp lệ", en: "Invalid CSV file"},
+	30010: {vi: "Header CSV phải có username, email và branch hoặc department", en: "CSV header must contain username, email and branch or department"},
+	30011: {vi: "File import không có dòng dữ liệu nào", en: "Import file has no data rows"},
+	30012: {vi: "File import quá lớn", en: "Import file is too large"},
+	30013: {vi: "Không tìm thấy job import", en: "Import job not found"},
+	30014: {vi: "ID job import không hợp lệ", en: "Invalid import job ID"},
+	30015: {vi: "Username đã tồn tại", en: "Username already exists"},
+	30016: {vi: "Email đã được sử dụng trong shop này", en: "Email already exists in this shop"},
+	30017: {vi: "Không tìm thấy user", en: "User not found"},
+	30018: {vi: "User đã bị thay đổi bởi yêu cầu khác, hãy tải lại", en: "User has been modified by another request, reload and try again"},
+	30019: {vi: "Cần gửi header If-Match với ETag của user", en: "If-Match header with the user ETag is required"},
+
+	// Auth
+	40000: {vi: "Dữ liệu gửi lên không hợp lệ", en: "Wrong body"},
+	40001: {vi: "Username đã tồn tại", en: "Username already exists"},
+	40002: {vi: "Sai username hoặc mật khẩu", en: "Invalid username or password"},
+	40003: {vi: "Không tìm thấy user", en: "User not found"},
+	40004: {vi: "Mật khẩu hiện tại không đúng", en: "Current password is incorrect"},
+	40005: {vi: "Mật khẩu mới phải khác mật khẩu hiện tại", en: "New password must be different from current password"},
+	40007: {vi: "Bạn không được phép đăng nhập thay người khác", en: "Impersonation is not allowed"},
+	40008: {vi: "User cần đăng nhập thay nằm ngoài phạm vi của bạn", en: "Target user is outside of your scope"},
+	40009: {vi: "ID phiên đăng nhập thay không hợp lệ", en: "Invalid impersonation ID"},
+	40010: {vi: "Không tìm thấy phiên đăng nhập thay", en: "Impersonation session not found"},
+	40011: {vi: "User đã bị vô hiệu hóa", en: "User is deactivated"},
+	40012: {vi: "Email đã được sử dụng trong shop này", en: "Email already exists in this shop"},
+
+	// Cascade
+	50000: {vi: "Tham số truy vấn không hợp lệ", en: "Wrong query"},
+	50001: {vi: "ID branch nhận user không hợp lệ", en: "Invalid reassign branch ID"},
+	50002: {vi: "Không tìm thấy đơn vị cần xóa", en: "Target not found"},
+	50003: {vi: "Không tìm thấy branch nhận user", en: "Reassign branch not found"},
+	50004: {vi: "Branch nhận user cũng đang bị xóa", en: "Reassign branch is being deleted"},
+	50005: {vi: "Đơn vị cần xóa đã bị thay đổi bởi yêu cầu khác, hãy tải lại", en: "Target has been modified by another request, reload and try again"},
+	50006: {vi: "Đơn vị cần xóa hoặc branch nhận user nằm ngoài phạm vi của bạn", en: "Target or reassign branch is outside of your scope"},
+	50007: {vi: "Branch nhận user thuộc shop khác", en: "Reassign branch belongs to another shop"},
+
+	// Export
+	60000: {vi: "Tham số truy vấn không hợp lệ", en: "Wrong query"},
+	60001: {vi: "Định dạng export không được hỗ trợ, hãy dùng csv hoặc ndjson", en: "Unsupported export format, use csv or ndjson"},
+	60002: {vi: "ID branch không hợp lệ", en: "Invalid branch ID"},
+	60003: {vi: "ID department không hợp lệ", en: "Invalid department ID"},
+	60004: {vi: "Role không hợp lệ", en: "Invalid role"},
+	60005: {vi: "Bộ lọc export nằm ngoài phạm vi của bạn", en: "Export filter is outside of your scope"},
+
+	// Webhook
+	70000: {vi: "Dữ liệu gửi lên không hợp lệ", en: "Wrong body"},
+	70001: {vi: "ID webhook không hợp lệ", en: "Invalid webhook ID"},
+	70002: {vi: "ID delivery không hợp lệ", en: "Invalid delivery ID"},
+	70003: {vi: "Không tìm thấy webhook", en: "Webhook not found"},
+	70004: {vi: "Không tìm thấy delivery của webhook", en: "Webhook delivery not found"},
+	70005: {vi: "Webhook đã bị thay đổi bởi yêu cầu khác, hãy tải lại", en: "Webhook has been modified by another request, reload and try again"},
+	70006: {vi: "Cần gửi header If-Match với ETag của webhook", en: "If-Match header with the webhook ETag is required"},
+	70007: {vi: "Tham số truy vấn không hợp lệ", en: "Wrong query"},
+	70008: {vi: "Webhook thuộc về shop, tài khoản của bạn không thuộc shop nào", en: "Webhooks belong to a shop, your account has no shop"},
+
+	// Idempotency-Key
+	80001: {vi: "Header Idempotency-Key phải có từ 1 đến 255 ký tự", en: "Idempotency-Key header must have 1-255 characters"},
+	80002: {vi: "Idempotency-Key đã được dùng cho một yêu cầu khác", en: "Idempotency-Key has already been used with a different request"},
+	80003: {vi: "Yêu cầu với Idempotency-Key này đang được xử lý, hãy thử lại sau", en: "A request with this Idempotency-Key is still being processed, retry later"},
+	80004: {vi: "Body quá lớn để dùng với Idempotency-Key", en: "Request body is too large to be used with Idempotency-Key"},
+
+	// Rate limit
+	90001: {vi: "Bạn đã gửi quá nhiều yêu cầu, hãy thử lại sau", en: "Too many requests, retry later"},
+}
+
+// validationMessages is the catalog of validation messages, keyed by their English format
+var validationMessages = map[string]Text{
+	"is required":                                          {vi: "là bắt buộc"},
+	"already exists":                                       {vi: "đã tồn tại"},
+	"is duplicated with line %d":                           {vi: "bị trùng với dòng %s"},
+	"is not a valid email":                                 {vi: "không phải email hợp lệ"},
+	"is not a valid role":                                  {vi: "không phải role hợp lệ"},
+	"cannot be assigned by your role":                      {vi: "vượt quá quyền của role hiện tại"},
+	"is not found in your scope":                           {vi: "không tìm thấy trong phạm vi của bạn"},
+	"matches %d %s entries, use the %s ID instead":         {vi: "khớp với %[1]s %[2]s, hãy dùng ID của %[3]s"},
+	"branch or department is required":                     {vi: "cần có branch hoặc department"},
+	"branch_id or department_id is required":               {vi: "cần có branch_id hoặc department_id"},
+	"cannot be changed without branch_id or department_id": {vi: "chỉ được đổi cùng với branch_id hoặc department_id"},
+	"does not exist in the organization tree":              {vi: "không tồn tại trong cây tổ chức"},
+	"does not match %s":                                    {vi: "không khớp với %s"},
+	"is outside of your scope":                             {vi: "nằm ngoài phạm vi của bạn"},
+	"is not a valid URL":                                   {vi: "không phải URL hợp lệ"},
+	"host cannot be resolved":                              {vi: "không phân giải được tên miền"},
+	"must not point to a private or local address":         {vi: "không được trỏ tới địa chỉ nội bộ"},
+	"is not a supported event type":                        {vi: "không phải loại event được hỗ trợ"},
+	"is not a valid status":                                {vi: "không phải trạng thái hợp lệ"},
+	"is not a valid ID":                                    {vi: "không phải ID hợp lệ"},
+	"is not a valid shop code":                             {vi: "không phải mã shop hợp lệ (2-20 ký tự A-Z, 0-9, - hoặc _)"},
+	"is not a valid username":                              {vi: "không phải username hợp lệ (3-32 ký tự chữ, số, ., - hoặc _)"},
+	"is not valid":                                         {vi: "không hợp lệ"},
+	"must be one of %s":                                    {vi: "phải là một trong %s"},
+	"must be at least %d characters":                       {vi: "phải có ít nhất %s ký tự"},
+	"must be at most %d characters":                        {vi: "không được quá %s ký tự"},
+	"must be exactly %d characters":                        {vi: "phải có đúng %s ký tự"},
+	"must have at least %d items":                          {vi: "phải có ít nhất %s phần tử"},
+	"must have at most %d items":                           {vi: "không được quá %s phần tử"},
+	"must have exactly %d items":                           {vi: "phải có đúng %s phần tử"},
+	"must be greater than or equal to %s":                  {vi: "phải lớn hơn hoặc bằng %s"},
+	"must be less than or equal to %s":                     {vi: "phải nhỏ hơn hoặc bằng %s"},
+	"must equal %s":                                        {vi: "phải bằng %s"},
+	"cannot be null":                                       {vi: "không được là null"},
+}
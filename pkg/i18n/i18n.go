@@ -0,0 +1,90 @@
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Text is a message in every supported language, keyed by language ("vi", "en")
+type Text map[string]string
+
+// Message returns the message of an error code in lang.
+// fallback is returned when the code or the language is not in the catalog.
+func Message(lang string, code int, fallback string) string {
+	if text, ok := codeMessages[code]; ok {
+		if msg, ok := text[lang]; ok {
+			return msg
+		}
+	}
+	return fallback
+}
+
+// validationFormat is a compiled validation message, its format verbs (%d, %s) become capture groups
+type validationFormat struct {
+	pattern *regexp.Regexp
+	text    Text
+}
+
+var validationFormats = compileValidationFormats(validationMessages)
+
+// Validation translates a validation message into lang.
+// Messages are matched against the English formats of the catalog, so messages built with
+// fmt.Sprintf are translated too; unknown messages are returned unchanged.
+func Validation(lang, message string) string {
+	for _, f := range validationFormats {
+		args := f.pattern.FindStringSubmatch(message)
+		if args == nil {
+			continue
+		}
+
+		// English is the source language of validation messages
+		format, ok := f.text[lang]
+		if !ok {
+			return message
+		}
+		values := make([]any, 0, len(args)-1)
+		for _, a := range args[1:] {
+			values = append(values, a)
+		}
+		return fmt.Sprintf(format, values...)
+	}
+	return message
+}
+
+// compileValidationFormats turns the English formats into regular expressions.
+// Captured values are passed to the translation as strings, translations may use %[n]s to reorder them.
+// Formats are tried from the most specific (longest literal text) so a message matching several
+// formats is always translated by the same one.
+func compileValidationFormats(messages map[string]Text) []validationFormat {
+	formats := make([]string, 0, len(messages))
+	for en := range messages {
+		formats = append(formats, en)
+	}
+	sort.Slice(formats, func(i, j int) bool {
+		li, lj := literalLength(formats[i]), literalLength(formats[j])
+		if li != lj {
+			return li > lj
+		}
+		return formats[i] < formats[j]
+	})
+
+	out := make([]validationFormat, 0, len(formats))
+	for _, en := range formats {
+		expr := regexp.QuoteMeta(en)
+		expr = strings.ReplaceAll(expr, "%d", `(\d+)`)
+		expr = strings.ReplaceAll(expr, "%s", `(.+?)`)
+
+		out = append(out, validationFormat{
+			pattern: regexp.MustCompile("^" + expr + "$"),
+			text:    messages[en],
+		})
+	}
+	return out
+}
+
+// literalLength returns the length of a format without its verbs
+func literalLength(format string) int {
+	return len(strings.NewReplacer("%d", "", "%s", "").Replace(format))
+}
@@ -0,0 +1,99 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		lang    string
+		message string
+		want    string
+	}{
+		{name: "message cố định", lang: vi, message: "is required", want: "là bắt buộc"},
+		{name: "tiếng Anh giữ nguyên", lang: en, message: "is required", want: "is required"},
+		{name: "ngôn ngữ không hỗ trợ giữ nguyên", lang: "fr", message: "is required", want: "is required"},
+		{name: "message không có trong catalog", lang: vi, message: "is on fire", want: "is on fire"},
+		{name: "tham số %d", lang: vi, message: "is duplicated with line 12", want: "bị trùng với dòng 12"},
+		{name: "tham số %s", lang: vi, message: "does not match department_id", want: "không khớp với department_id"},
+		{name: "tham số %s có khoảng trắng", lang: vi, message: "must be one of active inactive", want: "phải là một trong active inactive"},
+		{
+			name:    "đổi thứ tự tham số với %[n]s",
+			lang:    vi,
+			message: "matches 2 branch entries, use the branch ID instead",
+			want:    "khớp với 2 branch, hãy dùng ID của branch",
+		},
+		{name: "%d không khớp chữ", lang: vi, message: "must be at least three characters", want: "must be at least three characters"},
+		{name: "phải khớp toàn bộ message", lang: vi, message: "name is required", want: "name is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Validation(tt.lang, tt.message); got != tt.want {
+				t.Errorf("Mong đợi %q, nhận được %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestValidationCatalog kiểm tra mọi bản dịch dùng đúng số tham số của format tiếng Anh
+func TestValidationCatalog(t *testing.T) {
+	args := strings.NewReplacer("%d", "7", "%s", "abc")
+	for en, text := range validationMessages {
+		message := args.Replace(en)
+		got := Validation(vi, message)
+		if got == message {
+			t.Errorf("%q: không được dịch", en)
+		}
+		if strings.Contains(got, "%!") {
+			t.Errorf("%q: bản dịch sai tham số: %q", en, got)
+		}
+		if _, ok := text[vi]; !ok {
+			t.Errorf("%q: thiếu bản dịch tiếng Việt", en)
+		}
+	}
+}
+
+func TestCompileValidationFormatsOrder(t *testing.T) {
+	messages := map[string]Text{
+		"is %s":             {vi: "là %s"},
+		"is not valid":      {vi: "không hợp lệ"},
+		"is not a valid %s": {vi: "không phải %s hợp lệ"},
+		"is %s %s":          {vi: "là %s %s"},
+	}
+	want := []string{"không phải %s hợp lệ", "không hợp lệ", "là %s %s", "là %s"}
+
+	// Thứ tự duyệt map thay đổi mỗi lần chạy, kết quả phải luôn giống nhau
+	for i := 0; i < 20; i++ {
+		formats := compileValidationFormats(messages)
+		for j, f := range formats {
+			if f.text[vi] != want[j] {
+				t.Fatalf("Mong đợi format thứ %d là %q, nhận được %q", j, want[j], f.text[vi])
+			}
+		}
+	}
+}
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		lang string
+		code int
+		want string
+	}{
+		{name: "có bản dịch", lang: vi, code: 90001, want: "Bạn đã gửi quá nhiều yêu cầu, hãy thử lại sau"},
+		{name: "có bản dịch tiếng Anh", lang: en, code: 90001, want: "Too many requests, retry later"},
+		{name: "không có bản dịch tiếng Anh", lang: en, code: 404, want: "fallback"},
+		{name: "mã lỗi không có trong catalog", lang: vi, code: 1, want: "fallback"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Message(tt.lang, tt.code, "fallback"); got != tt.want {
+				t.Errorf("Mong đợi %q, nhận được %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -14,12 +14,29 @@ type Manager interface {
 
 type Payload struct {
 	jwt.StandardClaims
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	Role     string `json:"role"` // Role của user
-	ShopID   string `json:"shop_id,omitempty"`
-	RegionID string `json:"region_id,omitempty"`
-	BranchID string `json:"branch_id,omitempty"`
+	UserID       string `json:"user_id"`
+	Username     string `json:"username"`
+	Role         string `json:"role"` // Role của user
+	ShopID       string `json:"shop_id,omitempty"`
+	RegionID     string `json:"region_id,omitempty"`
+	BranchID     string `json:"branch_id,omitempty"`
+	DepartmentID string `json:"department_id,omitempty"`
+
+	// Actor chỉ có trong token impersonation, định danh admin thật
+	Actor *Actor `json:"act,omitempty"`
+}
+
+// Actor là claim "act" (RFC 8693) của token impersonation
+type Actor struct {
+	UserID          string `json:"sub"`
+	Username        string `json:"username"`
+	ImpersonationID string `json:"impersonation_id"`
+	AllowWrite      bool   `json:"allow_write,omitempty"`
+}
+
+// IsImpersonated kiểm tra token có phải token impersonation không
+func (p Payload) IsImpersonated() bool {
+	return p.Actor != nil
 }
 
 type implManager struct {
@@ -5,13 +5,37 @@ import (
 	"encoding/json"
 
 	"thuchanhgolang/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// NewScope creates a new scope.
+// NewScope creates a new scope from the token payload.
+// IDs that are empty or not valid hex are left nil.
 func NewScope(payload Payload) models.Scope {
-	return models.Scope{
-		UserID: payload.UserID,
+	sc := models.Scope{
+		UserID:       payload.UserID,
+		Role:         models.Role(payload.Role),
+		ShopID:       objectIDOrNil(payload.ShopID),
+		RegionID:     objectIDOrNil(payload.RegionID),
+		BranchID:     objectIDOrNil(payload.BranchID),
+		DepartmentID: objectIDOrNil(payload.DepartmentID),
+	}
+	if payload.Actor != nil {
+		sc.ActorID = payload.Actor.UserID
+		sc.ImpersonationID = payload.Actor.ImpersonationID
+	}
+	return sc
+}
+
+func objectIDOrNil(hex string) *primitive.ObjectID {
+	if hex == "" {
+		return nil
+	}
+	id, err := primitive.ObjectIDFromHex(hex)
+	if err != nil {
+		return nil
 	}
+	return &id
 }
 
 func CreateScopeHeader(scope models.Scope) (string, error) {
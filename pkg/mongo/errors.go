@@ -2,7 +2,11 @@ package mongo
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -10,3 +14,118 @@ var (
 	ErrNoDocuments     = mongo.ErrNoDocuments
 	ErrInvalidObjectID = errors.New("invalid object id")
 )
+
+// duplicateKeyCode là mã lỗi của server khi vi phạm unique index
+const duplicateKeyCode = 11000
+
+var (
+	// dupIndexPattern lấy tên index trong message E11000 "... index: username_unique dup key: ..."
+	dupIndexPattern = regexp.MustCompile(`index: (\S+) dup key`)
+	// dupFieldsPattern lấy tên field trong phần "dup key: { username: \"x\" }"
+	dupFieldsPattern = regexp.MustCompile(`(\w+): `)
+)
+
+// DuplicateKeyError là lỗi vi phạm unique index, được dịch từ lỗi của driver
+// để các tầng trên biết field nào bị trùng mà không phải đọc message của server
+type DuplicateKeyError struct {
+	Index    string   // Tên index bị vi phạm
+	Fields   []string // Các field của index, theo thứ tự khai báo
+	Position int      // Vị trí document bị lỗi trong InsertMany (0 với thao tác đơn)
+	err      error
+}
+
+// Error trả về message của lỗi
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("duplicate key on %s (index %s)", strings.Join(e.Fields, ", "), e.Index)
+}
+
+// Unwrap trả về lỗi gốc của driver
+func (e *DuplicateKeyError) Unwrap() error {
+	return e.err
+}
+
+// Field trả về field bị trùng. Với index ghép (ví dụ shop_id + email) field cuối cùng
+// là field mà client có thể sửa nên được trả về.
+func (e *DuplicateKeyError) Field() string {
+	if len(e.Fields) == 0 {
+		return ""
+	}
+	return e.Fields[len(e.Fields)-1]
+}
+
+// IsDuplicateKey kiểm tra lỗi có phải do vi phạm unique index không
+func IsDuplicateKey(err error) bool {
+	var dup *DuplicateKeyError
+	return errors.As(err, &dup) || mongo.IsDuplicateKeyError(err)
+}
+
+// AsDuplicateKey lấy DuplicateKeyError từ err (kể cả khi đã bị wrap)
+func AsDuplicateKey(err error) (*DuplicateKeyError, bool) {
+	var dup *DuplicateKeyError
+	if errors.As(err, &dup) {
+		return dup, true
+	}
+	return nil, false
+}
+
+// translateError chuyển lỗi duplicate key của driver thành DuplicateKeyError, lỗi khác giữ nguyên
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var we mongo.WriteException
+	if errors.As(err, &we) {
+		for _, e := range we.WriteErrors {
+			if e.Code == duplicateKeyCode {
+				return newDuplicateKeyError(err, e.Index, e.Message, e.Raw)
+			}
+		}
+	}
+
+	var bwe mongo.BulkWriteException
+	if errors.As(err, &bwe) {
+		for _, e := range bwe.WriteErrors {
+			if e.Code == duplicateKeyCode {
+				return newDuplicateKeyError(err, e.Index, e.Message, e.Raw)
+			}
+		}
+	}
+
+	var ce mongo.CommandError
+	if errors.As(err, &ce) && ce.Code == duplicateKeyCode {
+		return newDuplicateKeyError(err, 0, ce.Message, ce.Raw)
+	}
+
+	return err
+}
+
+// newDuplicateKeyError đọc tên index và các field từ phản hồi của server.
+// Ưu tiên keyValue (MongoDB 4.2+), nếu không có thì phân tích message.
+func newDuplicateKeyError(err error, position int, message string, raw bson.Raw) *DuplicateKeyError {
+	dup := &DuplicateKeyError{Position: position, err: err}
+
+	if m := dupIndexPattern.FindStringSubmatch(message); m != nil {
+		dup.Index = m[1]
+	}
+
+	if raw != nil {
+		if kv, ok := raw.Lookup("keyValue").DocumentOK(); ok {
+			if elems, err := kv.Elements(); err == nil {
+				for _, el := range elems {
+					dup.Fields = append(dup.Fields, el.Key())
+				}
+			}
+		}
+	}
+
+	if len(dup.Fields) == 0 {
+		if i := strings.Index(message, "dup key:"); i >= 0 {
+			for _, m := range dupFieldsPattern.FindAllStringSubmatch(message[i+len("dup key:"):], -1) {
+				dup.Fields = append(dup.Fields, m[1])
+			}
+		}
+	}
+
+	return dup
+}
@@ -0,0 +1,174 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// rawKeyValue tạo phản hồi của server có keyValue (MongoDB 4.2+) với các field theo thứ tự
+func rawKeyValue(t *testing.T, kv bson.D) bson.Raw {
+	t.Helper()
+	raw, err := bson.Marshal(bson.D{{Key: "code", Value: duplicateKeyCode}, {Key: "keyValue", Value: kv}})
+	if err != nil {
+		t.Fatalf("Không mong đợi lỗi: %v", err)
+	}
+	return raw
+}
+
+func TestTranslateError(t *testing.T) {
+	const (
+		usernameMsg  = `E11000 duplicate key error collection: app.users index: username_unique dup key: { username: "bob" }`
+		shopEmailMsg = `E11000 duplicate key error collection: app.users index: shop_id_1_email_1 dup key: { shop_id: ObjectId('665f1c2e9b1e8a3d4c5b6a79'), email: "bob@example.com" }`
+	)
+
+	tests := []struct {
+		name         string
+		err          error
+		wantIndex    string
+		wantFields   []string
+		wantField    string
+		wantPosition int
+	}{
+		{
+			name: "WriteException có keyValue",
+			err: mongo.WriteException{WriteErrors: []mongo.WriteError{{
+				Code:    duplicateKeyCode,
+				Message: usernameMsg,
+				Raw:     rawKeyValue(t, bson.D{{Key: "username", Value: "bob"}}),
+			}}},
+			wantIndex:  "username_unique",
+			wantFields: []string{"username"},
+			wantField:  "username",
+		},
+		{
+			name: "WriteException không có keyValue thì đọc từ message",
+			err: mongo.WriteException{WriteErrors: []mongo.WriteError{{
+				Code:    duplicateKeyCode,
+				Message: usernameMsg,
+			}}},
+			wantIndex:  "username_unique",
+			wantFields: []string{"username"},
+			wantField:  "username",
+		},
+		{
+			name: "index ghép có keyValue trả về field cuối",
+			err: mongo.WriteException{WriteErrors: []mongo.WriteError{{
+				Code:    duplicateKeyCode,
+				Message: shopEmailMsg,
+				Raw:     rawKeyValue(t, bson.D{{Key: "shop_id", Value: "665f1c2e9b1e8a3d4c5b6a79"}, {Key: "email", Value: "bob@example.com"}}),
+			}}},
+			wantIndex:  "shop_id_1_email_1",
+			wantFields: []string{"shop_id", "email"},
+			wantField:  "email",
+		},
+		{
+			name: "index ghép không có keyValue trả về field cuối",
+			err: mongo.WriteException{WriteErrors: []mongo.WriteError{{
+				Code:    duplicateKeyCode,
+				Message: shopEmailMsg,
+			}}},
+			wantIndex:  "shop_id_1_email_1",
+			wantFields: []string{"shop_id", "email"},
+			wantField:  "email",
+		},
+		{
+			name: "BulkWriteException của InsertMany giữ vị trí document bị trùng",
+			err: mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{
+				{WriteError: mongo.WriteError{Index: 1, Code: 121, Message: "Document failed validation"}},
+				{WriteError: mongo.WriteError{
+					Index:   3,
+					Code:    duplicateKeyCode,
+					Message: usernameMsg,
+					Raw:     rawKeyValue(t, bson.D{{Key: "username", Value: "bob"}}),
+				}},
+			}},
+			wantIndex:    "username_unique",
+			wantFields:   []string{"username"},
+			wantField:    "username",
+			wantPosition: 3,
+		},
+		{
+			name:       "CommandError",
+			err:        mongo.CommandError{Code: duplicateKeyCode, Message: usernameMsg},
+			wantIndex:  "username_unique",
+			wantFields: []string{"username"},
+			wantField:  "username",
+		},
+		{
+			name: "lỗi đã bị wrap",
+			err: fmt.Errorf("insert user: %w", mongo.WriteException{WriteErrors: []mongo.WriteError{{
+				Code:    duplicateKeyCode,
+				Message: usernameMsg,
+			}}}),
+			wantIndex:  "username_unique",
+			wantFields: []string{"username"},
+			wantField:  "username",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := translateError(tt.err)
+
+			dup, ok := AsDuplicateKey(err)
+			if !ok {
+				t.Fatalf("Mong đợi DuplicateKeyError, nhận được %v", err)
+			}
+			if dup.Index != tt.wantIndex {
+				t.Errorf("Mong đợi index %q, nhận được %q", tt.wantIndex, dup.Index)
+			}
+			if !reflect.DeepEqual(dup.Fields, tt.wantFields) {
+				t.Errorf("Mong đợi fields %v, nhận được %v", tt.wantFields, dup.Fields)
+			}
+			if dup.Field() != tt.wantField {
+				t.Errorf("Mong đợi field %q, nhận được %q", tt.wantField, dup.Field())
+			}
+			if dup.Position != tt.wantPosition {
+				t.Errorf("Mong đợi position %d, nhận được %d", tt.wantPosition, dup.Position)
+			}
+			if !IsDuplicateKey(err) {
+				t.Error("Mong đợi IsDuplicateKey trả về true")
+			}
+			if !reflect.DeepEqual(errors.Unwrap(err), tt.err) {
+				t.Error("Mong đợi giữ được lỗi gốc của driver qua Unwrap")
+			}
+		})
+	}
+}
+
+func TestTranslateErrorKeepsOtherErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "nil", err: nil},
+		{name: "ErrNoDocuments", err: ErrNoDocuments},
+		{name: "WriteException không phải duplicate key", err: mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 121, Message: "Document failed validation"}}}},
+		{name: "BulkWriteException không phải duplicate key", err: mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{{WriteError: mongo.WriteError{Code: 121}}}}},
+		{name: "CommandError khác", err: mongo.CommandError{Code: 112, Message: "WriteConflict"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := translateError(tt.err)
+			if !reflect.DeepEqual(err, tt.err) {
+				t.Errorf("Mong đợi giữ nguyên lỗi %v, nhận được %v", tt.err, err)
+			}
+			if _, ok := AsDuplicateKey(err); ok {
+				t.Error("Không mong đợi DuplicateKeyError")
+			}
+		})
+	}
+}
+
+func TestDuplicateKeyErrorFieldWithoutFields(t *testing.T) {
+	dup := newDuplicateKeyError(errors.New("E11000 duplicate key error"), 0, "E11000 duplicate key error", nil)
+	if dup.Field() != "" {
+		t.Errorf("Mong đợi field rỗng, nhận được %q", dup.Field())
+	}
+}
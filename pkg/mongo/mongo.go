@@ -28,11 +28,16 @@ type Collection interface {
 	InsertOne(context.Context, interface{}) (interface{}, error)
 	InsertMany(context.Context, []interface{}) ([]interface{}, error)
 	DeleteOne(context.Context, interface{}) (int64, error)
+	DeleteMany(context.Context, interface{}) (int64, error)
 	Find(context.Context, interface{}, ...*options.FindOptions) (Cursor, error)
 	CountDocuments(context.Context, interface{}, ...*options.CountOptions) (int64, error)
 	Aggregate(context.Context, interface{}) (Cursor, error)
 	UpdateOne(context.Context, interface{}, interface{}, ...*options.UpdateOptions) (*mongo.UpdateResult, error)
 	UpdateMany(context.Context, interface{}, interface{}, ...*options.UpdateOptions) (*mongo.UpdateResult, error)
+	FindOneAndUpdate(context.Context, interface{}, interface{}, ...*options.FindOneAndUpdateOptions) SingleResult
+	FindOneAndDelete(context.Context, interface{}, ...*options.FindOneAndDeleteOptions) SingleResult
+	CreateIndexes(context.Context, []mongo.IndexModel) ([]string, error)
+	DropIndex(context.Context, string) error
 }
 
 //go:generate mockery --name=SingleResult --output=mocks --case=underscore
@@ -158,17 +163,27 @@ func (mc *mongoCollection) FindOne(ctx context.Context, filter interface{}) Sing
 }
 
 func (mc *mongoCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
-	return mc.coll.UpdateOne(ctx, filter, update, opts[:]...)
+	res, err := mc.coll.UpdateOne(ctx, filter, update, opts[:]...)
+	return res, translateError(err)
 }
 
 func (mc *mongoCollection) InsertOne(ctx context.Context, document interface{}) (interface{}, error) {
-	id, err := mc.coll.InsertOne(ctx, document)
-	return id.InsertedID, err
+	res, err := mc.coll.InsertOne(ctx, document)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return res.InsertedID, nil
 }
 
 func (mc *mongoCollection) InsertMany(ctx context.Context, document []interface{}) ([]interface{}, error) {
 	res, err := mc.coll.InsertMany(ctx, document)
-	return res.InsertedIDs, err
+	if err != nil {
+		if res != nil {
+			return res.InsertedIDs, translateError(err)
+		}
+		return nil, translateError(err)
+	}
+	return res.InsertedIDs, nil
 }
 
 func (mc *mongoCollection) DeleteOne(ctx context.Context, filter interface{}) (int64, error) {
@@ -176,6 +191,14 @@ func (mc *mongoCollection) DeleteOne(ctx context.Context, filter interface{}) (i
 	return count.DeletedCount, err
 }
 
+func (mc *mongoCollection) DeleteMany(ctx context.Context, filter interface{}) (int64, error) {
+	res, err := mc.coll.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
 func (mc *mongoCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (Cursor, error) {
 	findResult, err := mc.coll.Find(ctx, filter, opts...)
 	return &mongoCursor{mc: findResult}, err
@@ -187,15 +210,39 @@ func (mc *mongoCollection) Aggregate(ctx context.Context, pipeline interface{})
 }
 
 func (mc *mongoCollection) UpdateMany(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
-	return mc.coll.UpdateMany(ctx, filter, update, opts[:]...)
+	res, err := mc.coll.UpdateMany(ctx, filter, update, opts[:]...)
+	return res, translateError(err)
+}
+
+// FindOneAndUpdate cập nhật một document và trả về document trước khi cập nhật
+// (hoặc sau khi cập nhật nếu opts có ReturnDocument(options.After))
+func (mc *mongoCollection) FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) SingleResult {
+	singleResult := mc.coll.FindOneAndUpdate(ctx, filter, update, opts...)
+	return &mongoSingleResult{sr: singleResult}
+}
+
+// FindOneAndDelete xóa một document và trả về document đã xóa
+func (mc *mongoCollection) FindOneAndDelete(ctx context.Context, filter interface{}, opts ...*options.FindOneAndDeleteOptions) SingleResult {
+	singleResult := mc.coll.FindOneAndDelete(ctx, filter, opts...)
+	return &mongoSingleResult{sr: singleResult}
 }
 
 func (mc *mongoCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
 	return mc.coll.CountDocuments(ctx, filter, opts...)
 }
 
+func (mc *mongoCollection) CreateIndexes(ctx context.Context, models []mongo.IndexModel) ([]string, error) {
+	return mc.coll.Indexes().CreateMany(ctx, models)
+}
+
+func (mc *mongoCollection) DropIndex(ctx context.Context, name string) error {
+	_, err := mc.coll.Indexes().DropOne(ctx, name)
+	return err
+}
+
 func (sr *mongoSingleResult) Decode(v interface{}) error {
-	return sr.sr.Decode(v)
+	// FindOneAndUpdate trả lỗi ghi (ví dụ duplicate key) qua Decode
+	return translateError(sr.sr.Decode(v))
 }
 
 func (mr *mongoCursor) Close(ctx context.Context) error {
@@ -0,0 +1,89 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// labelTransientTransaction là label server gắn vào lỗi khi cả transaction có thể chạy lại
+	labelTransientTransaction = "TransientTransactionError"
+	// labelUnknownCommitResult là label server gắn vào lỗi khi không rõ commit đã thành công hay chưa
+	labelUnknownCommitResult = "UnknownTransactionCommitResult"
+
+	// DefaultTransactionAttempts là số lần thử tối đa khi gặp lỗi tạm thời
+	DefaultTransactionAttempts = 3
+)
+
+// Transactor chạy một nhóm thao tác trong cùng một transaction.
+// Context truyền vào fn mang theo session, repository chỉ cần dùng đúng ctx đó
+// là mọi thao tác sẽ nằm trong transaction.
+//
+//go:generate mockery --name=Transactor --output=mocks --case=underscore
+type Transactor interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type mongoTransactor struct {
+	client      Client
+	maxAttempts int
+}
+
+// NewTransactor tạo Transactor dựa trên client, dùng DefaultTransactionAttempts lần thử
+func NewTransactor(client Client) Transactor {
+	return &mongoTransactor{
+		client:      client,
+		maxAttempts: DefaultTransactionAttempts,
+	}
+}
+
+// WithTransaction mở session, chạy fn trong transaction rồi commit.
+// Nếu fn trả lỗi thì transaction bị abort. Lỗi có label TransientTransactionError
+// sẽ chạy lại toàn bộ transaction, lỗi UnknownTransactionCommitResult chỉ chạy lại bước commit.
+// Nếu ctx đã nằm trong một transaction thì fn chạy luôn trong transaction đó.
+func (t *mongoTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	return retryOnLabel(t.maxAttempts, labelTransientTransaction, func() error {
+		return t.client.UseSession(ctx, func(sc mongo.SessionContext) error {
+			if err := sc.StartTransaction(); err != nil {
+				return err
+			}
+
+			if err := fn(sc); err != nil {
+				// Abort dùng context riêng để vẫn chạy được khi ctx gốc đã bị hủy
+				_ = sc.AbortTransaction(context.Background())
+				return err
+			}
+
+			return retryOnLabel(t.maxAttempts, labelUnknownCommitResult, func() error {
+				return sc.CommitTransaction(sc)
+			})
+		})
+	})
+}
+
+// retryOnLabel gọi op tối đa maxAttempts lần, chỉ thử lại khi lỗi có label tương ứng
+func retryOnLabel(maxAttempts int, label string, op func() error) error {
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		err = op()
+		if err == nil || !hasErrorLabel(err, label) {
+			return err
+		}
+	}
+	return err
+}
+
+// hasErrorLabel kiểm tra lỗi (hoặc lỗi được wrap bên trong) có mang label của server không
+func hasErrorLabel(err error, label string) bool {
+	var le mongo.LabeledError
+	if errors.As(err, &le) {
+		return le.HasErrorLabel(label)
+	}
+	return false
+}
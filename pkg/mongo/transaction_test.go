@@ -0,0 +1,187 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// fakeSession ghi lại các lời gọi transaction, commit lần lượt trả về các lỗi trong commitErrs.
+// mongo.Session được nhúng để có method không export session(), các method khác không được dùng.
+type fakeSession struct {
+	mongo.Session
+	commitErrs []error
+	starts     int
+	aborts     int
+	commits    int
+}
+
+func (s *fakeSession) StartTransaction(...*options.TransactionOptions) error {
+	s.starts++
+	return nil
+}
+
+func (s *fakeSession) AbortTransaction(context.Context) error {
+	s.aborts++
+	return nil
+}
+
+func (s *fakeSession) CommitTransaction(context.Context) error {
+	s.commits++
+	if len(s.commitErrs) == 0 {
+		return nil
+	}
+	err := s.commitErrs[0]
+	s.commitErrs = s.commitErrs[1:]
+	return err
+}
+
+// fakeClient mở mọi session trên cùng một fakeSession
+type fakeClient struct {
+	Client
+	session  *fakeSession
+	sessions int
+}
+
+func (c *fakeClient) UseSession(ctx context.Context, fn func(mongo.SessionContext) error) error {
+	c.sessions++
+	return fn(mongo.NewSessionContext(ctx, c.session))
+}
+
+// labeledError tạo lỗi của server mang label
+func labeledError(label string) error {
+	return mongo.CommandError{Code: 112, Message: "WriteConflict", Labels: []string{label}}
+}
+
+func TestWithTransactionRetry(t *testing.T) {
+	errFatal := errors.New("document failed validation")
+
+	tests := []struct {
+		name        string
+		fnErrs      []error // Lỗi fn trả về ở từng lần chạy, hết danh sách thì trả về nil
+		commitErrs  []error
+		wantErr     error
+		wantLabel   string
+		wantRuns    int
+		wantAborts  int
+		wantCommits int
+	}{
+		{
+			name:        "thành công ngay lần đầu",
+			wantRuns:    1,
+			wantCommits: 1,
+		},
+		{
+			name:        "TransientTransactionError chạy lại cả transaction",
+			fnErrs:      []error{labeledError(labelTransientTransaction)},
+			wantRuns:    2,
+			wantAborts:  1,
+			wantCommits: 1,
+		},
+		{
+			name:        "UnknownTransactionCommitResult chỉ chạy lại commit",
+			commitErrs:  []error{labeledError(labelUnknownCommitResult)},
+			wantRuns:    1,
+			wantCommits: 2,
+		},
+		{
+			name:        "commit lỗi TransientTransactionError chạy lại cả transaction",
+			commitErrs:  []error{labeledError(labelTransientTransaction)},
+			wantRuns:    2,
+			wantCommits: 2,
+		},
+		{
+			name:       "lỗi không thử lại được",
+			fnErrs:     []error{errFatal},
+			wantErr:    errFatal,
+			wantRuns:   1,
+			wantAborts: 1,
+		},
+		{
+			name: "hết số lần thử",
+			fnErrs: []error{
+				labeledError(labelTransientTransaction),
+				labeledError(labelTransientTransaction),
+				labeledError(labelTransientTransaction),
+			},
+			wantLabel:  labelTransientTransaction,
+			wantRuns:   DefaultTransactionAttempts,
+			wantAborts: DefaultTransactionAttempts,
+		},
+		{
+			name: "commit hết số lần thử",
+			commitErrs: []error{
+				labeledError(labelUnknownCommitResult),
+				labeledError(labelUnknownCommitResult),
+				labeledError(labelUnknownCommitResult),
+			},
+			wantLabel:   labelUnknownCommitResult,
+			wantRuns:    1,
+			wantCommits: DefaultTransactionAttempts,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &fakeSession{commitErrs: tt.commitErrs}
+			client := &fakeClient{session: session}
+			tx := NewTransactor(client)
+
+			runs := 0
+			err := tx.WithTransaction(context.Background(), func(ctx context.Context) error {
+				if mongo.SessionFromContext(ctx) == nil {
+					t.Error("Mong đợi ctx mang session của transaction")
+				}
+				runs++
+				if runs <= len(tt.fnErrs) {
+					return tt.fnErrs[runs-1]
+				}
+				return nil
+			})
+
+			switch {
+			case tt.wantLabel != "":
+				if !hasErrorLabel(err, tt.wantLabel) {
+					t.Errorf("Mong đợi lỗi có label %s, nhận được %v", tt.wantLabel, err)
+				}
+			case !errors.Is(err, tt.wantErr):
+				t.Errorf("Mong đợi lỗi %v, nhận được %v", tt.wantErr, err)
+			}
+			if runs != tt.wantRuns {
+				t.Errorf("Mong đợi fn chạy %d lần, nhận được %d", tt.wantRuns, runs)
+			}
+			if client.sessions != tt.wantRuns || session.starts != tt.wantRuns {
+				t.Errorf("Mong đợi mở %d transaction, nhận được %d session, %d transaction", tt.wantRuns, client.sessions, session.starts)
+			}
+			if session.aborts != tt.wantAborts {
+				t.Errorf("Mong đợi abort %d lần, nhận được %d", tt.wantAborts, session.aborts)
+			}
+			if session.commits != tt.wantCommits {
+				t.Errorf("Mong đợi commit %d lần, nhận được %d", tt.wantCommits, session.commits)
+			}
+		})
+	}
+}
+
+func TestWithTransactionNested(t *testing.T) {
+	session := &fakeSession{}
+	client := &fakeClient{session: session}
+	tx := NewTransactor(client)
+
+	ctx := mongo.NewSessionContext(context.Background(), session)
+	runs := 0
+	err := tx.WithTransaction(ctx, func(ctx context.Context) error {
+		runs++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Không mong đợi lỗi: %v", err)
+	}
+	// ctx đã có session thì fn chạy luôn trong transaction bên ngoài
+	if runs != 1 || client.sessions != 0 || session.commits != 0 {
+		t.Errorf("Mong đợi chạy trong transaction sẵn có, nhận được %d lần chạy, %d session, %d commit", runs, client.sessions, session.commits)
+	}
+}
@@ -0,0 +1,78 @@
+package mongo
+
+import (
+	"thuchanhgolang/pkg/patch"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Update builds an update document from $set, $unset and $inc operators.
+type Update struct {
+	set   bson.M
+	unset bson.M
+	inc   bson.M
+}
+
+// NewUpdate returns an empty update.
+func NewUpdate() *Update {
+	return &Update{set: bson.M{}, unset: bson.M{}, inc: bson.M{}}
+}
+
+// Set sets field to value.
+func (u *Update) Set(field string, value interface{}) *Update {
+	delete(u.unset, field)
+	u.set[field] = value
+	return u
+}
+
+// Unset removes field from the document.
+func (u *Update) Unset(field string) *Update {
+	delete(u.set, field)
+	u.unset[field] = ""
+	return u
+}
+
+// Inc increments field by n.
+func (u *Update) Inc(field string, n int) *Update {
+	u.inc[field] = n
+	return u
+}
+
+// HasChanges reports whether the update sets or removes any field.
+func (u *Update) HasChanges() bool {
+	return len(u.set) > 0 || len(u.unset) > 0
+}
+
+// Document returns the update document, operators without fields are left out.
+func (u *Update) Document() bson.M {
+	doc := bson.M{}
+	if len(u.set) > 0 {
+		doc["$set"] = u.set
+	}
+	if len(u.unset) > 0 {
+		doc["$unset"] = u.unset
+	}
+	if len(u.inc) > 0 {
+		doc["$inc"] = u.inc
+	}
+	return doc
+}
+
+// SetPtr sets field to *v, a nil v leaves the field unchanged.
+func SetPtr[T any](u *Update, field string, v *T) *Update {
+	if v != nil {
+		u.Set(field, *v)
+	}
+	return u
+}
+
+// SetPatch applies a merge patch field: omitted leaves the field unchanged, null removes it, a value sets it.
+func SetPatch[T any](u *Update, field string, f patch.Field[T]) *Update {
+	switch {
+	case f.IsNull():
+		u.Unset(field)
+	case f.IsSet():
+		u.Set(field, f.Value)
+	}
+	return u
+}
@@ -0,0 +1,104 @@
+package mongo
+
+import (
+	"reflect"
+	"testing"
+
+	"thuchanhgolang/pkg/patch"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestUpdateDocument(t *testing.T) {
+	name := "bob"
+
+	tests := []struct {
+		name        string
+		build       func(u *Update)
+		want        bson.M
+		wantChanges bool
+	}{
+		{
+			name:  "không có thay đổi",
+			build: func(u *Update) {},
+			want:  bson.M{},
+		},
+		{
+			name:  "chỉ $inc không tính là thay đổi",
+			build: func(u *Update) { u.Inc("version", 1) },
+			want:  bson.M{"$inc": bson.M{"version": 1}},
+		},
+		{
+			name: "$set và $unset",
+			build: func(u *Update) {
+				u.Set("name", "bob").Unset("department_id").Inc("version", 1)
+			},
+			want: bson.M{
+				"$set":   bson.M{"name": "bob"},
+				"$unset": bson.M{"department_id": ""},
+				"$inc":   bson.M{"version": 1},
+			},
+			wantChanges: true,
+		},
+		{
+			name:        "Unset sau Set thì xóa field",
+			build:       func(u *Update) { u.Set("name", "bob").Unset("name") },
+			want:        bson.M{"$unset": bson.M{"name": ""}},
+			wantChanges: true,
+		},
+		{
+			name:        "Set sau Unset thì gán field",
+			build:       func(u *Update) { u.Unset("name").Set("name", "bob") },
+			want:        bson.M{"$set": bson.M{"name": "bob"}},
+			wantChanges: true,
+		},
+		{
+			name:  "SetPtr nil giữ nguyên field",
+			build: func(u *Update) { SetPtr[string](u, "name", nil) },
+			want:  bson.M{},
+		},
+		{
+			name:        "SetPtr gán giá trị",
+			build:       func(u *Update) { SetPtr(u, "name", &name) },
+			want:        bson.M{"$set": bson.M{"name": "bob"}},
+			wantChanges: true,
+		},
+		{
+			name:  "SetPatch bỏ qua field giữ nguyên",
+			build: func(u *Update) { SetPatch(u, "name", patch.Field[string]{}) },
+			want:  bson.M{},
+		},
+		{
+			name:        "SetPatch null xóa field",
+			build:       func(u *Update) { SetPatch(u, "name", patch.Null[string]()) },
+			want:        bson.M{"$unset": bson.M{"name": ""}},
+			wantChanges: true,
+		},
+		{
+			name:        "SetPatch có giá trị gán field",
+			build:       func(u *Update) { SetPatch(u, "name", patch.Set("bob")) },
+			want:        bson.M{"$set": bson.M{"name": "bob"}},
+			wantChanges: true,
+		},
+		{
+			name:        "SetPatch chuỗi rỗng vẫn gán field",
+			build:       func(u *Update) { SetPatch(u, "name", patch.Set("")) },
+			want:        bson.M{"$set": bson.M{"name": ""}},
+			wantChanges: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewUpdate()
+			tt.build(u)
+
+			if got := u.Document(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Mong đợi %v, nhận được %v", tt.want, got)
+			}
+			if u.HasChanges() != tt.wantChanges {
+				t.Errorf("Mong đợi HasChanges=%v, nhận được %v", tt.wantChanges, u.HasChanges())
+			}
+		})
+	}
+}
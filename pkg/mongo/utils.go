@@ -41,3 +41,19 @@ func BuildQueryWithSoftDelete(query bson.M) bson.M {
 func GetMongoDateTimeNow() primitive.DateTime {
 	return primitive.NewDateTimeFromTime(time.Now())
 }
+
+// BuildQueryWithTimeRange adds a {$gte: from, $lte: to} condition on field.
+// Nil bounds are left open; the query is unchanged when both are nil.
+func BuildQueryWithTimeRange(query bson.M, field string, from, to *time.Time) bson.M {
+	cond := bson.M{}
+	if from != nil {
+		cond["$gte"] = *from
+	}
+	if to != nil {
+		cond["$lte"] = *to
+	}
+	if len(cond) > 0 {
+		query[field] = cond
+	}
+	return query
+}
@@ -0,0 +1,218 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// securityScheme is the name of the bearer token scheme in the document.
+const securityScheme = "bearerAuth"
+
+// Builder collects the routes of the API and generates the OpenAPI document.
+// Success responses are wrapped in the envelope: its "data" property is replaced by the route's response.
+type Builder struct {
+	info     Info
+	envelope *Schema
+	schemas  *schemas
+	paths    map[string]PathItem
+	routes   []Route // Mounted routes with full gin paths
+}
+
+// NewBuilder creates a builder. envelope is the response wrapper, for example response.Resp{}.
+func NewBuilder(info Info, envelope interface{}) *Builder {
+	s := newSchemas()
+	return &Builder{
+		info:     info,
+		envelope: s.of(reflect.TypeOf(envelope)),
+		schemas:  s,
+		paths:    map[string]PathItem{},
+	}
+}
+
+// Add mounts routes under prefix (the base path of the router group) with the given tag.
+// It panics when a route is documented twice, like gin does for duplicate routes.
+func (b *Builder) Add(prefix, tag string, routes ...Route) {
+	for _, r := range routes {
+		r.Path = joinPath(prefix, r.Path)
+		path, params := convertPath(r.Path)
+
+		item, ok := b.paths[path]
+		if !ok {
+			item = PathItem{}
+			b.paths[path] = item
+		}
+		method := strings.ToLower(r.Method)
+		if _, ok := item[method]; ok {
+			panic(fmt.Sprintf("openapi: %s %s documented twice", r.Method, r.Path))
+		}
+
+		item[method] = b.operation(r, tag, path, params)
+		b.routes = append(b.routes, r)
+	}
+}
+
+// Routes returns the mounted routes with full gin paths.
+func (b *Builder) Routes() []Route {
+	return b.routes
+}
+
+// Document generates the OpenAPI document of the mounted routes.
+func (b *Builder) Document() Document {
+	return Document{
+		OpenAPI: Version,
+		Info:    b.info,
+		Paths:   b.paths,
+		Components: Components{
+			Schemas: b.schemas.components,
+			SecuritySchemes: map[string]SecurityScheme{
+				securityScheme: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+}
+
+// operation builds the operation of a route
+func (b *Builder) operation(r Route, tag, path string, pathParams []string) *Operation {
+	op := &Operation{
+		OperationID: operationID(r.Method, path),
+		Summary:     r.Summary,
+		Tags:        []string{tag},
+		Responses:   map[string]Response{},
+		Security:    []map[string][]string{},
+	}
+	if !r.Public {
+		op.Security = append(op.Security, map[string][]string{securityScheme: {}})
+	}
+
+	// Parameters: path, query, If-Match, then the ones read directly by the handler
+	for _, name := range pathParams {
+		op.Parameters = append(op.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+	if r.Query != nil {
+		op.Parameters = append(op.Parameters, b.schemas.queryParams(reflect.TypeOf(r.Query))...)
+	}
+	if r.IfMatch {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:        "If-Match",
+			In:          "header",
+			Description: "ETag of the resource as last read by the client",
+			Required:    true,
+			Schema:      &Schema{Type: "string"},
+		})
+	}
+	op.Parameters = append(op.Parameters, r.Params...)
+
+	// Request body
+	if bodies, ok := r.Body.(Bodies); ok {
+		body := &RequestBody{Required: true, Content: map[string]MediaType{}}
+		for t, v := range bodies {
+			body.Content[t] = MediaType{Schema: b.schemas.of(reflect.TypeOf(v))}
+		}
+		op.RequestBody = body
+	} else if r.Body != nil {
+		types := r.BodyTypes
+		if len(types) == 0 {
+			types = []string{ContentJSON}
+		}
+		body := &RequestBody{Required: true, Content: map[string]MediaType{}}
+		for _, t := range types {
+			body.Content[t] = MediaType{Schema: b.schemas.of(reflect.TypeOf(r.Body))}
+		}
+		op.RequestBody = body
+	}
+
+	// Responses: success and the error envelope
+	ok := Response{Description: "Success", Content: map[string]MediaType{}}
+	types := r.ResponseTypes
+	if len(types) == 0 {
+		types = []string{ContentJSON}
+	}
+	for _, t := range types {
+		switch {
+		case t != ContentJSON:
+			ok.Content[t] = MediaType{Schema: &Schema{Type: "string"}}
+		case r.Raw:
+			ok.Content[t] = MediaType{Schema: b.schemas.of(reflect.TypeOf(r.Response))}
+		default:
+			ok.Content[t] = MediaType{Schema: b.wrap(r.Response)}
+		}
+	}
+	if r.ETag {
+		ok.Headers = map[string]Header{
+			"ETag": {Description: "Version of the resource, send it back in If-Match", Schema: &Schema{Type: "string"}},
+		}
+	}
+	op.Responses[fmt.Sprint(http.StatusOK)] = ok
+	op.Responses["default"] = Response{
+		Description: "Error",
+		Content:     map[string]MediaType{ContentJSON: {Schema: b.envelope}},
+	}
+	if r.RateLimited {
+		op.Responses[fmt.Sprint(http.StatusTooManyRequests)] = Response{
+			Description: "Too many requests",
+			Headers: map[string]Header{
+				"Retry-After": {Description: "Seconds to wait before retrying", Schema: &Schema{Type: "integer"}},
+			},
+			Content: map[string]MediaType{ContentJSON: {Schema: b.envelope}},
+		}
+	}
+
+	return op
+}
+
+// wrap returns the envelope with its data replaced by the response schema
+func (b *Builder) wrap(resp interface{}) *Schema {
+	if resp == nil {
+		return b.envelope
+	}
+
+	var data *Schema
+	if oneOf, ok := resp.(OneOf); ok {
+		data = &Schema{}
+		for _, v := range oneOf {
+			data.OneOf = append(data.OneOf, b.schemas.of(reflect.TypeOf(v)))
+		}
+	} else {
+		data = b.schemas.of(reflect.TypeOf(resp))
+	}
+
+	return &Schema{AllOf: []*Schema{
+		b.envelope,
+		{Type: "object", Properties: map[string]*Schema{"data": data}},
+	}}
+}
+
+// joinPath joins the group prefix and the route path the way gin does
+func joinPath(prefix, path string) string {
+	if path == "" {
+		return prefix
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// convertPath converts a gin path (/:id, /*file) to an OpenAPI path ({id}) and returns the parameter names
+func convertPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var params []string
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			params = append(params, s[1:])
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// operationID derives a unique ID from the method and the path, e.g. get_shops_id_history
+func operationID(method, path string) string {
+	parts := []string{strings.ToLower(method)}
+	for _, s := range strings.Split(path, "/") {
+		s = strings.Trim(s, "{}")
+		if s != "" {
+			parts = append(parts, strings.ReplaceAll(s, ".", "_"))
+		}
+	}
+	return strings.Join(parts, "_")
+}
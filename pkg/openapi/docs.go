@@ -0,0 +1,27 @@
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed docs.html
+var docsPage string
+
+// JSONHandler serves the document as JSON.
+func JSONHandler(doc Document) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, doc)
+	}
+}
+
+// DocsHandler serves the embedded documentation page, which renders the document at specURL.
+func DocsHandler(specURL string) gin.HandlerFunc {
+	page := strings.ReplaceAll(docsPage, "{{SPEC_URL}}", specURL)
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+	}
+}
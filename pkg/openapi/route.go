@@ -0,0 +1,60 @@
+package openapi
+
+// Content types used by routes.
+const (
+	ContentJSON      = "application/json"
+	ContentMultipart = "multipart/form-data"
+	ContentCSV       = "text/csv"
+	ContentSSE       = "text/event-stream"
+	ContentNDJSON    = "application/x-ndjson"
+	ContentHTML      = "text/html"
+)
+
+// Route documents one route registered by a delivery package. Path is relative to the
+// router group and uses gin syntax (/:id), so the list can sit next to the MapRoutes it describes.
+type Route struct {
+	Method  string
+	Path    string
+	Summary string
+	// Public routes do not require a bearer token.
+	Public bool
+	// Query is a struct whose form-tagged fields are the query parameters.
+	Query interface{}
+	// Params are the parameters the handler reads directly (c.Query, c.GetHeader).
+	Params []Parameter
+	// IfMatch requires the ETag of the resource in the If-Match header.
+	IfMatch bool
+	// Body is the request body, BodyTypes lists its content types (default application/json).
+	// Use Bodies when the shape depends on the content type.
+	Body      interface{}
+	BodyTypes []string
+	// Response is the data of the response envelope, use OneOf when the handler returns different shapes.
+	// ResponseTypes other than application/json are sent as is without the envelope.
+	Response      interface{}
+	ResponseTypes []string
+	// Raw JSON responses are sent without the envelope.
+	Raw bool
+	// ETag marks responses carrying the version of the resource in the ETag header.
+	ETag bool
+	// RateLimited marks routes answering 429 with Retry-After when the caller exceeds its limit.
+	RateLimited bool
+}
+
+// OneOf is a response that has one of several shapes.
+type OneOf []interface{}
+
+// Bodies is a request body whose shape depends on its content type.
+type Bodies map[string]interface{}
+
+// File is an uploaded file in a multipart body.
+type File []byte
+
+// QueryParam returns an optional query parameter of type string.
+func QueryParam(name, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: "string"}}
+}
+
+// HeaderParam returns an optional header parameter of type string.
+func HeaderParam(name, description string) Parameter {
+	return Parameter{Name: name, In: "header", Description: description, Schema: &Schema{Type: "string"}}
+}
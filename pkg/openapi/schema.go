@@ -0,0 +1,242 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	fileType          = reflect.TypeOf(File(nil))
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	schemaTyperType   = reflect.TypeOf((*schemaTyper)(nil)).Elem()
+)
+
+// schemaTyper is implemented by wrappers documented as the type they wrap, like patch.Field.
+type schemaTyper interface {
+	SchemaType() reflect.Type
+}
+
+// transportDirs are package directories skipped when naming schemas, so that
+// internal/shop/delivery/http.createReq is named shop.createReq.
+var transportDirs = map[string]bool{"http": true, "delivery": true, "grpc": true}
+
+// schemas generates JSON Schemas from Go types. Named struct types become
+// components referenced by $ref, other types are inlined.
+type schemas struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+	taken      map[string]reflect.Type
+}
+
+func newSchemas() *schemas {
+	return &schemas{
+		components: map[string]*Schema{},
+		names:      map[reflect.Type]string{},
+		taken:      map[string]reflect.Type{},
+	}
+}
+
+// of returns the schema of t, nil types (untyped nil) accept any value
+func (s *schemas) of(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Implements(schemaTyperType) {
+		return s.of(reflect.Zero(t).Interface().(schemaTyper).SchemaType())
+	}
+	// Types with their own JSON encoding (ObjectID, custom date formats) are written as strings
+	if t == fileType {
+		return &Schema{Type: "string", Format: "binary"}
+	}
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) ||
+		t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.of(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.of(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + s.component(t)}
+	}
+	return &Schema{}
+}
+
+// component registers the named struct t as a component and returns its name
+func (s *schemas) component(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+
+	name := schemaName(t)
+	for i := 2; s.taken[name] != nil; i++ {
+		name = schemaName(t) + strconv.Itoa(i)
+	}
+	s.names[t] = name
+	s.taken[name] = t
+
+	// Register the name before building the object so that recursive types end in a $ref
+	s.components[name] = &Schema{}
+	*s.components[name] = *s.object(t)
+	return name
+}
+
+// object returns the object schema of struct t, embedded structs are flattened like encoding/json does
+func (s *schemas) object(t reflect.Type) *Schema {
+	obj := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	s.fields(t, obj)
+	return obj
+}
+
+// fields adds the JSON fields of struct t to obj
+func (s *schemas) fields(t reflect.Type, obj *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts := tagName(f.Tag.Get("json"))
+		if name == "-" && opts == "" {
+			continue
+		}
+
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			s.fields(ft, obj)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		field := s.of(f.Type)
+		required := applyBinding(field, f.Tag.Get("binding"))
+		obj.Properties[name] = field
+		if required {
+			obj.Required = append(obj.Required, name)
+		}
+	}
+}
+
+// queryParams returns the query parameters of the form-tagged fields of struct t
+func (s *schemas) queryParams(t reflect.Type) []Parameter {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var params []Parameter
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _ := tagName(f.Tag.Get("form"))
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			params = append(params, s.queryParams(f.Type)...)
+			continue
+		}
+		if !f.IsExported() || name == "" || name == "-" {
+			continue
+		}
+
+		schema := s.of(f.Type)
+		params = append(params, Parameter{
+			Name:     name,
+			In:       "query",
+			Required: applyBinding(schema, f.Tag.Get("binding")),
+			Schema:   schema,
+		})
+	}
+	return params
+}
+
+// applyBinding adds the gin binding rules that JSON Schema can express and reports whether the field is required.
+// Rules after "dive" apply to the items of a slice.
+func applyBinding(schema *Schema, binding string) bool {
+	required := false
+	rules := strings.Split(binding, ",")
+	for i, rule := range rules {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "dive":
+			if schema.Items != nil {
+				applyBinding(schema.Items, strings.Join(rules[i+1:], ","))
+			}
+			return required
+		case "required":
+			required = true
+		case "email":
+			schema.Format = "email"
+		case "url":
+			schema.Format = "uri"
+		case "objectid":
+			schema.Pattern = "^[0-9a-fA-F]{24}$"
+		case "oneof":
+			schema.Enum = strings.Fields(value)
+		case "min", "max":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				continue
+			}
+			switch {
+			case schema.Type == "string" && key == "min":
+				schema.MinLength = &n
+			case schema.Type == "string":
+				schema.MaxLength = &n
+			case schema.Type == "array" && key == "min":
+				schema.MinItems = &n
+			}
+		}
+	}
+	return required
+}
+
+// tagName splits a struct tag into the name and the options
+func tagName(tag string) (string, string) {
+	name, opts, _ := strings.Cut(tag, ",")
+	return name, opts
+}
+
+// schemaName names t after its package, skipping transport directories
+func schemaName(t reflect.Type) string {
+	segments := strings.Split(t.PkgPath(), "/")
+	pkg := segments[len(segments)-1]
+	for i := len(segments) - 1; i >= 0; i-- {
+		if !transportDirs[segments[i]] {
+			pkg = segments[i]
+			break
+		}
+	}
+	return pkg + "." + t.Name()
+}
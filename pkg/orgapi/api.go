@@ -0,0 +1,80 @@
+package orgapi
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+)
+
+// MetadataAuthorization is the metadata key carrying the access token as "Bearer <token>",
+// the same token as the Authorization header of the REST API.
+const MetadataAuthorization = "authorization"
+
+// readOnlyMethods are the methods allowed with a read-only impersonation token.
+var readOnlyMethods = map[string]bool{
+	"Get":  true,
+	"List": true,
+}
+
+// IsReadOnly reports whether the method of fullMethod ("/orgapi.ShopService/Get") only reads data.
+func IsReadOnly(fullMethod string) bool {
+	return readOnlyMethods[fullMethod[strings.LastIndex(fullMethod, "/")+1:]]
+}
+
+// Empty is the response of methods returning no data.
+type Empty struct{}
+
+// GetRequest identifies the resource to read.
+type GetRequest struct {
+	ID string `json:"id" binding:"required,objectid"`
+}
+
+// DeleteRequest identifies the resource to delete. Version is the version last read by the client,
+// the resource is only deleted when it has not been modified since.
+type DeleteRequest struct {
+	ID      string `json:"id" binding:"required,objectid"`
+	Version int    `json:"version" binding:"required,min=1"`
+}
+
+// Page is the page to list, pages start at 1. Zero values use the server defaults.
+type Page struct {
+	Page  int   `json:"page" binding:"min=0"`
+	Limit int64 `json:"limit" binding:"min=0"`
+}
+
+// PageInfo describes the listed page.
+type PageInfo struct {
+	Total       int64 `json:"total"`
+	Count       int64 `json:"count"`
+	PerPage     int64 `json:"per_page"`
+	CurrentPage int   `json:"current_page"`
+	TotalPages  int   `json:"total_pages"`
+}
+
+// unaryHandler returns the grpc.MethodHandler calling fn on the service implementation.
+func unaryHandler[S any, Req any, Resp any](fullMethod string, fn func(S, context.Context, *Req) (*Resp, error)) grpc.MethodHandler {
+	return func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		in := new(Req)
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		if interceptor == nil {
+			return fn(srv.(S), ctx, in)
+		}
+		info := &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod}
+		return interceptor(ctx, in, info, func(ctx context.Context, req any) (any, error) {
+			return fn(srv.(S), ctx, req.(*Req))
+		})
+	}
+}
+
+// invoke calls method on cc with the JSON codec.
+func invoke[Resp any](ctx context.Context, cc grpc.ClientConnInterface, method string, in any, opts ...grpc.CallOption) (*Resp, error) {
+	out := new(Resp)
+	opts = append([]grpc.CallOption{grpc.CallContentSubtype(CodecName)}, opts...)
+	if err := cc.Invoke(ctx, method, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,115 @@
+package orgapi
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// BranchServiceName is the full name of the branch service.
+const BranchServiceName = "orgapi.BranchService"
+
+// Branch is a branch of a region.
+type Branch struct {
+	ID        string    `json:"id"`
+	RegionID  string    `json:"region_id"`
+	Name      string    `json:"name"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+}
+
+// CreateBranchRequest creates a branch in a region.
+type CreateBranchRequest struct {
+	RegionID string `json:"region_id" binding:"required,objectid"`
+	Name     string `json:"name" binding:"required,notblank,max=255"`
+}
+
+// UpdateBranchRequest renames a branch, a nil name keeps it.
+// Version is the version last read by the client.
+type UpdateBranchRequest struct {
+	ID      string  `json:"id" binding:"required,objectid"`
+	Name    *string `json:"name,omitempty" binding:"omitempty,notblank,max=255"`
+	Version int     `json:"version" binding:"required,min=1"`
+}
+
+// ListBranchesRequest lists the branches, newest first, only of RegionID when given.
+type ListBranchesRequest struct {
+	RegionID string `json:"region_id,omitempty" binding:"omitempty,objectid"`
+	Page     Page   `json:"page"`
+}
+
+// ListBranchesResponse is a page of branches.
+type ListBranchesResponse struct {
+	Items []Branch `json:"items"`
+	Meta  PageInfo `json:"meta"`
+}
+
+// BranchServiceServer is the server API of the branch service.
+type BranchServiceServer interface {
+	Get(context.Context, *GetRequest) (*Branch, error)
+	List(context.Context, *ListBranchesRequest) (*ListBranchesResponse, error)
+	Create(context.Context, *CreateBranchRequest) (*Branch, error)
+	Update(context.Context, *UpdateBranchRequest) (*Branch, error)
+	Delete(context.Context, *DeleteRequest) (*Empty, error)
+}
+
+// BranchService_ServiceDesc is the grpc.ServiceDesc of the branch service.
+var BranchService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: BranchServiceName,
+	HandlerType: (*BranchServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Get", Handler: unaryHandler("/"+BranchServiceName+"/Get", BranchServiceServer.Get)},
+		{MethodName: "List", Handler: unaryHandler("/"+BranchServiceName+"/List", BranchServiceServer.List)},
+		{MethodName: "Create", Handler: unaryHandler("/"+BranchServiceName+"/Create", BranchServiceServer.Create)},
+		{MethodName: "Update", Handler: unaryHandler("/"+BranchServiceName+"/Update", BranchServiceServer.Update)},
+		{MethodName: "Delete", Handler: unaryHandler("/"+BranchServiceName+"/Delete", BranchServiceServer.Delete)},
+	},
+	Metadata: "orgapi/branch",
+}
+
+// RegisterBranchServiceServer registers srv on s.
+func RegisterBranchServiceServer(s grpc.ServiceRegistrar, srv BranchServiceServer) {
+	s.RegisterService(&BranchService_ServiceDesc, srv)
+}
+
+// BranchServiceClient is the client API of the branch service.
+type BranchServiceClient interface {
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Branch, error)
+	List(ctx context.Context, in *ListBranchesRequest, opts ...grpc.CallOption) (*ListBranchesResponse, error)
+	Create(ctx context.Context, in *CreateBranchRequest, opts ...grpc.CallOption) (*Branch, error)
+	Update(ctx context.Context, in *UpdateBranchRequest, opts ...grpc.CallOption) (*Branch, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error)
+}
+
+type branchServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewBranchServiceClient returns a client of the branch service using cc.
+func NewBranchServiceClient(cc grpc.ClientConnInterface) BranchServiceClient {
+	return branchServiceClient{cc: cc}
+}
+
+func (c branchServiceClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Branch, error) {
+	return invoke[Branch](ctx, c.cc, "/"+BranchServiceName+"/Get", in, opts...)
+}
+
+func (c branchServiceClient) List(ctx context.Context, in *ListBranchesRequest, opts ...grpc.CallOption) (*ListBranchesResponse, error) {
+	return invoke[ListBranchesResponse](ctx, c.cc, "/"+BranchServiceName+"/List", in, opts...)
+}
+
+func (c branchServiceClient) Create(ctx context.Context, in *CreateBranchRequest, opts ...grpc.CallOption) (*Branch, error) {
+	return invoke[Branch](ctx, c.cc, "/"+BranchServiceName+"/Create", in, opts...)
+}
+
+func (c branchServiceClient) Update(ctx context.Context, in *UpdateBranchRequest, opts ...grpc.CallOption) (*Branch, error) {
+	return invoke[Branch](ctx, c.cc, "/"+BranchServiceName+"/Update", in, opts...)
+}
+
+func (c branchServiceClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error) {
+	return invoke[Empty](ctx, c.cc, "/"+BranchServiceName+"/Delete", in, opts...)
+}
@@ -0,0 +1,33 @@
+package orgapi
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// CodecName is the content subtype of the JSON codec, requests are sent as application/grpc+json.
+// Messages are plain Go structs with JSON tags, so no protobuf code generation is needed.
+const CodecName = "json"
+
+func init() {
+	encoding.RegisterCodec(codec{})
+}
+
+// codec encodes messages as JSON.
+type codec struct{}
+
+// Marshal encodes v as JSON.
+func (codec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes the JSON data into v.
+func (codec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// Name returns CodecName.
+func (codec) Name() string {
+	return CodecName
+}
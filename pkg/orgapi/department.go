@@ -0,0 +1,115 @@
+package orgapi
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// DepartmentServiceName is the full name of the department service.
+const DepartmentServiceName = "orgapi.DepartmentService"
+
+// Department is a department of a branch.
+type Department struct {
+	ID        string    `json:"id"`
+	BranchID  string    `json:"branch_id"`
+	Name      string    `json:"name"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+}
+
+// CreateDepartmentRequest creates a department in a branch.
+type CreateDepartmentRequest struct {
+	BranchID string `json:"branch_id" binding:"required,objectid"`
+	Name     string `json:"name" binding:"required,notblank,max=255"`
+}
+
+// UpdateDepartmentRequest renames a department, a nil name keeps it.
+// Version is the version last read by the client.
+type UpdateDepartmentRequest struct {
+	ID      string  `json:"id" binding:"required,objectid"`
+	Name    *string `json:"name,omitempty" binding:"omitempty,notblank,max=255"`
+	Version int     `json:"version" binding:"required,min=1"`
+}
+
+// ListDepartmentsRequest lists the departments, newest first, only of BranchID when given.
+type ListDepartmentsRequest struct {
+	BranchID string `json:"branch_id,omitempty" binding:"omitempty,objectid"`
+	Page     Page   `json:"page"`
+}
+
+// ListDepartmentsResponse is a page of departments.
+type ListDepartmentsResponse struct {
+	Items []Department `json:"items"`
+	Meta  PageInfo     `json:"meta"`
+}
+
+// DepartmentServiceServer is the server API of the department service.
+type DepartmentServiceServer interface {
+	Get(context.Context, *GetRequest) (*Department, error)
+	List(context.Context, *ListDepartmentsRequest) (*ListDepartmentsResponse, error)
+	Create(context.Context, *CreateDepartmentRequest) (*Department, error)
+	Update(context.Context, *UpdateDepartmentRequest) (*Department, error)
+	Delete(context.Context, *DeleteRequest) (*Empty, error)
+}
+
+// DepartmentService_ServiceDesc is the grpc.ServiceDesc of the department service.
+var DepartmentService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: DepartmentServiceName,
+	HandlerType: (*DepartmentServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Get", Handler: unaryHandler("/"+DepartmentServiceName+"/Get", DepartmentServiceServer.Get)},
+		{MethodName: "List", Handler: unaryHandler("/"+DepartmentServiceName+"/List", DepartmentServiceServer.List)},
+		{MethodName: "Create", Handler: unaryHandler("/"+DepartmentServiceName+"/Create", DepartmentServiceServer.Create)},
+		{MethodName: "Update", Handler: unaryHandler("/"+DepartmentServiceName+"/Update", DepartmentServiceServer.Update)},
+		{MethodName: "Delete", Handler: unaryHandler("/"+DepartmentServiceName+"/Delete", DepartmentServiceServer.Delete)},
+	},
+	Metadata: "orgapi/department",
+}
+
+// RegisterDepartmentServiceServer registers srv on s.
+func RegisterDepartmentServiceServer(s grpc.ServiceRegistrar, srv DepartmentServiceServer) {
+	s.RegisterService(&DepartmentService_ServiceDesc, srv)
+}
+
+// DepartmentServiceClient is the client API of the department service.
+type DepartmentServiceClient interface {
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Department, error)
+	List(ctx context.Context, in *ListDepartmentsRequest, opts ...grpc.CallOption) (*ListDepartmentsResponse, error)
+	Create(ctx context.Context, in *CreateDepartmentRequest, opts ...grpc.CallOption) (*Department, error)
+	Update(ctx context.Context, in *UpdateDepartmentRequest, opts ...grpc.CallOption) (*Department, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error)
+}
+
+type departmentServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewDepartmentServiceClient returns a client of the department service using cc.
+func NewDepartmentServiceClient(cc grpc.ClientConnInterface) DepartmentServiceClient {
+	return departmentServiceClient{cc: cc}
+}
+
+func (c departmentServiceClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Department, error) {
+	return invoke[Department](ctx, c.cc, "/"+DepartmentServiceName+"/Get", in, opts...)
+}
+
+func (c departmentServiceClient) List(ctx context.Context, in *ListDepartmentsRequest, opts ...grpc.CallOption) (*ListDepartmentsResponse, error) {
+	return invoke[ListDepartmentsResponse](ctx, c.cc, "/"+DepartmentServiceName+"/List", in, opts...)
+}
+
+func (c departmentServiceClient) Create(ctx context.Context, in *CreateDepartmentRequest, opts ...grpc.CallOption) (*Department, error) {
+	return invoke[Department](ctx, c.cc, "/"+DepartmentServiceName+"/Create", in, opts...)
+}
+
+func (c departmentServiceClient) Update(ctx context.Context, in *UpdateDepartmentRequest, opts ...grpc.CallOption) (*Department, error) {
+	return invoke[Department](ctx, c.cc, "/"+DepartmentServiceName+"/Update", in, opts...)
+}
+
+func (c departmentServiceClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error) {
+	return invoke[Empty](ctx, c.cc, "/"+DepartmentServiceName+"/Delete", in, opts...)
+}
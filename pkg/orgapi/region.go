@@ -0,0 +1,115 @@
+package orgapi
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// RegionServiceName is the full name of the region service.
+const RegionServiceName = "orgapi.RegionService"
+
+// Region is a region of a shop.
+type Region struct {
+	ID        string    `json:"id"`
+	ShopID    string    `json:"shop_id"`
+	Name      string    `json:"name"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+}
+
+// CreateRegionRequest creates a region in a shop.
+type CreateRegionRequest struct {
+	ShopID string `json:"shop_id" binding:"required,objectid"`
+	Name   string `json:"name" binding:"required,notblank,max=255"`
+}
+
+// UpdateRegionRequest renames a region, a nil name keeps it.
+// Version is the version last read by the client.
+type UpdateRegionRequest struct {
+	ID      string  `json:"id" binding:"required,objectid"`
+	Name    *string `json:"name,omitempty" binding:"omitempty,notblank,max=255"`
+	Version int     `json:"version" binding:"required,min=1"`
+}
+
+// ListRegionsRequest lists the regions, newest first, only of ShopID when given.
+type ListRegionsRequest struct {
+	ShopID string `json:"shop_id,omitempty" binding:"omitempty,objectid"`
+	Page   Page   `json:"page"`
+}
+
+// ListRegionsResponse is a page of regions.
+type ListRegionsResponse struct {
+	Items []Region `json:"items"`
+	Meta  PageInfo `json:"meta"`
+}
+
+// RegionServiceServer is the server API of the region service.
+type RegionServiceServer interface {
+	Get(context.Context, *GetRequest) (*Region, error)
+	List(context.Context, *ListRegionsRequest) (*ListRegionsResponse, error)
+	Create(context.Context, *CreateRegionRequest) (*Region, error)
+	Update(context.Context, *UpdateRegionRequest) (*Region, error)
+	Delete(context.Context, *DeleteRequest) (*Empty, error)
+}
+
+// RegionService_ServiceDesc is the grpc.ServiceDesc of the region service.
+var RegionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: RegionServiceName,
+	HandlerType: (*RegionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Get", Handler: unaryHandler("/"+RegionServiceName+"/Get", RegionServiceServer.Get)},
+		{MethodName: "List", Handler: unaryHandler("/"+RegionServiceName+"/List", RegionServiceServer.List)},
+		{MethodName: "Create", Handler: unaryHandler("/"+RegionServiceName+"/Create", RegionServiceServer.Create)},
+		{MethodName: "Update", Handler: unaryHandler("/"+RegionServiceName+"/Update", RegionServiceServer.Update)},
+		{MethodName: "Delete", Handler: unaryHandler("/"+RegionServiceName+"/Delete", RegionServiceServer.Delete)},
+	},
+	Metadata: "orgapi/region",
+}
+
+// RegisterRegionServiceServer registers srv on s.
+func RegisterRegionServiceServer(s grpc.ServiceRegistrar, srv RegionServiceServer) {
+	s.RegisterService(&RegionService_ServiceDesc, srv)
+}
+
+// RegionServiceClient is the client API of the region service.
+type RegionServiceClient interface {
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Region, error)
+	List(ctx context.Context, in *ListRegionsRequest, opts ...grpc.CallOption) (*ListRegionsResponse, error)
+	Create(ctx context.Context, in *CreateRegionRequest, opts ...grpc.CallOption) (*Region, error)
+	Update(ctx context.Context, in *UpdateRegionRequest, opts ...grpc.CallOption) (*Region, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error)
+}
+
+type regionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewRegionServiceClient returns a client of the region service using cc.
+func NewRegionServiceClient(cc grpc.ClientConnInterface) RegionServiceClient {
+	return regionServiceClient{cc: cc}
+}
+
+func (c regionServiceClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Region, error) {
+	return invoke[Region](ctx, c.cc, "/"+RegionServiceName+"/Get", in, opts...)
+}
+
+func (c regionServiceClient) List(ctx context.Context, in *ListRegionsRequest, opts ...grpc.CallOption) (*ListRegionsResponse, error) {
+	return invoke[ListRegionsResponse](ctx, c.cc, "/"+RegionServiceName+"/List", in, opts...)
+}
+
+func (c regionServiceClient) Create(ctx context.Context, in *CreateRegionRequest, opts ...grpc.CallOption) (*Region, error) {
+	return invoke[Region](ctx, c.cc, "/"+RegionServiceName+"/Create", in, opts...)
+}
+
+func (c regionServiceClient) Update(ctx context.Context, in *UpdateRegionRequest, opts ...grpc.CallOption) (*Region, error) {
+	return invoke[Region](ctx, c.cc, "/"+RegionServiceName+"/Update", in, opts...)
+}
+
+func (c regionServiceClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error) {
+	return invoke[Empty](ctx, c.cc, "/"+RegionServiceName+"/Delete", in, opts...)
+}
@@ -0,0 +1,115 @@
+package orgapi
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// ShopServiceName is the full name of the shop service.
+const ShopServiceName = "orgapi.ShopService"
+
+// Shop is a shop.
+type Shop struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Code      string    `json:"code"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+}
+
+// CreateShopRequest creates a shop.
+type CreateShopRequest struct {
+	Name string `json:"name" binding:"required,notblank,max=255"`
+	Code string `json:"code" binding:"required,shopcode"`
+}
+
+// UpdateShopRequest updates the given fields of a shop, nil fields are kept.
+// Version is the version last read by the client.
+type UpdateShopRequest struct {
+	ID      string  `json:"id" binding:"required,objectid"`
+	Name    *string `json:"name,omitempty" binding:"omitempty,notblank,max=255"`
+	Code    *string `json:"code,omitempty" binding:"omitempty,shopcode"`
+	Version int     `json:"version" binding:"required,min=1"`
+}
+
+// ListShopsRequest lists the shops, newest first.
+type ListShopsRequest struct {
+	Page Page `json:"page"`
+}
+
+// ListShopsResponse is a page of shops.
+type ListShopsResponse struct {
+	Items []Shop   `json:"items"`
+	Meta  PageInfo `json:"meta"`
+}
+
+// ShopServiceServer is the server API of the shop service.
+type ShopServiceServer interface {
+	Get(context.Context, *GetRequest) (*Shop, error)
+	List(context.Context, *ListShopsRequest) (*ListShopsResponse, error)
+	Create(context.Context, *CreateShopRequest) (*Shop, error)
+	Update(context.Context, *UpdateShopRequest) (*Shop, error)
+	Delete(context.Context, *DeleteRequest) (*Empty, error)
+}
+
+// ShopService_ServiceDesc is the grpc.ServiceDesc of the shop service.
+var ShopService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: ShopServiceName,
+	HandlerType: (*ShopServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Get", Handler: unaryHandler("/"+ShopServiceName+"/Get", ShopServiceServer.Get)},
+		{MethodName: "List", Handler: unaryHandler("/"+ShopServiceName+"/List", ShopServiceServer.List)},
+		{MethodName: "Create", Handler: unaryHandler("/"+ShopServiceName+"/Create", ShopServiceServer.Create)},
+		{MethodName: "Update", Handler: unaryHandler("/"+ShopServiceName+"/Update", ShopServiceServer.Update)},
+		{MethodName: "Delete", Handler: unaryHandler("/"+ShopServiceName+"/Delete", ShopServiceServer.Delete)},
+	},
+	Metadata: "orgapi/shop",
+}
+
+// RegisterShopServiceServer registers srv on s.
+func RegisterShopServiceServer(s grpc.ServiceRegistrar, srv ShopServiceServer) {
+	s.RegisterService(&ShopService_ServiceDesc, srv)
+}
+
+// ShopServiceClient is the client API of the shop service.
+type ShopServiceClient interface {
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Shop, error)
+	List(ctx context.Context, in *ListShopsRequest, opts ...grpc.CallOption) (*ListShopsResponse, error)
+	Create(ctx context.Context, in *CreateShopRequest, opts ...grpc.CallOption) (*Shop, error)
+	Update(ctx context.Context, in *UpdateShopRequest, opts ...grpc.CallOption) (*Shop, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error)
+}
+
+type shopServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewShopServiceClient returns a client of the shop service using cc.
+func NewShopServiceClient(cc grpc.ClientConnInterface) ShopServiceClient {
+	return shopServiceClient{cc: cc}
+}
+
+func (c shopServiceClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Shop, error) {
+	return invoke[Shop](ctx, c.cc, "/"+ShopServiceName+"/Get", in, opts...)
+}
+
+func (c shopServiceClient) List(ctx context.Context, in *ListShopsRequest, opts ...grpc.CallOption) (*ListShopsResponse, error) {
+	return invoke[ListShopsResponse](ctx, c.cc, "/"+ShopServiceName+"/List", in, opts...)
+}
+
+func (c shopServiceClient) Create(ctx context.Context, in *CreateShopRequest, opts ...grpc.CallOption) (*Shop, error) {
+	return invoke[Shop](ctx, c.cc, "/"+ShopServiceName+"/Create", in, opts...)
+}
+
+func (c shopServiceClient) Update(ctx context.Context, in *UpdateShopRequest, opts ...grpc.CallOption) (*Shop, error) {
+	return invoke[Shop](ctx, c.cc, "/"+ShopServiceName+"/Update", in, opts...)
+}
+
+func (c shopServiceClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error) {
+	return invoke[Empty](ctx, c.cc, "/"+ShopServiceName+"/Delete", in, opts...)
+}
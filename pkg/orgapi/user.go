@@ -0,0 +1,136 @@
+package orgapi
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// UserServiceName is the full name of the user service.
+const UserServiceName = "orgapi.UserService"
+
+// User is a user with its place in the organization. DepartmentID is nil when the user
+// belongs directly to the branch, DeactivatedAt is set when the user has been deactivated.
+type User struct {
+	ID            string     `json:"id"`
+	Username      string     `json:"username"`
+	Email         string     `json:"email"`
+	Role          string     `json:"role"`
+	ShopID        string     `json:"shop_id,omitempty"`
+	RegionID      string     `json:"region_id,omitempty"`
+	BranchID      string     `json:"branch_id,omitempty"`
+	DepartmentID  *string    `json:"department_id,omitempty"`
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+	Version       int        `json:"version"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	CreatedBy     string     `json:"created_by,omitempty"`
+	UpdatedBy     string     `json:"updated_by,omitempty"`
+}
+
+// CreateUserRequest creates a user in a branch or a department, one of BranchID and DepartmentID is required.
+// The other IDs are filled from the branch or the department, when given they must match.
+type CreateUserRequest struct {
+	Username     string  `json:"username" binding:"required,username"`
+	Password     string  `json:"password" binding:"required,min=6,max=72"`
+	Email        string  `json:"email" binding:"required,email"`
+	ShopID       *string `json:"shop_id,omitempty" binding:"omitempty,objectid"`
+	RegionID     *string `json:"region_id,omitempty" binding:"omitempty,objectid"`
+	BranchID     *string `json:"branch_id,omitempty" binding:"omitempty,objectid"`
+	DepartmentID *string `json:"department_id,omitempty" binding:"omitempty,objectid"`
+}
+
+// UpdateUserRequest updates the given fields of a user, nil fields are kept.
+// Version is the version last read by the client.
+type UpdateUserRequest struct {
+	ID           string  `json:"id" binding:"required,objectid"`
+	Username     *string `json:"username,omitempty" binding:"omitempty,username"`
+	Password     *string `json:"password,omitempty" binding:"omitempty,min=6,max=72"`
+	Email        *string `json:"email,omitempty" binding:"omitempty,email"`
+	ShopID       *string `json:"shop_id,omitempty" binding:"omitempty,objectid"`
+	RegionID     *string `json:"region_id,omitempty" binding:"omitempty,objectid"`
+	BranchID     *string `json:"branch_id,omitempty" binding:"omitempty,objectid"`
+	DepartmentID *string `json:"department_id,omitempty" binding:"omitempty,objectid"`
+	Version      int     `json:"version" binding:"required,min=1"`
+}
+
+// ListUsersRequest lists the users visible to the caller, newest first.
+// BranchID and DepartmentID can only narrow the scope of the caller.
+type ListUsersRequest struct {
+	BranchID     string `json:"branch_id,omitempty" binding:"omitempty,objectid"`
+	DepartmentID string `json:"department_id,omitempty" binding:"omitempty,objectid"`
+	Page         Page   `json:"page"`
+}
+
+// ListUsersResponse is a page of users.
+type ListUsersResponse struct {
+	Items []User   `json:"items"`
+	Meta  PageInfo `json:"meta"`
+}
+
+// UserServiceServer is the server API of the user service.
+type UserServiceServer interface {
+	Get(context.Context, *GetRequest) (*User, error)
+	List(context.Context, *ListUsersRequest) (*ListUsersResponse, error)
+	Create(context.Context, *CreateUserRequest) (*User, error)
+	Update(context.Context, *UpdateUserRequest) (*User, error)
+	Delete(context.Context, *DeleteRequest) (*Empty, error)
+}
+
+// UserService_ServiceDesc is the grpc.ServiceDesc of the user service.
+var UserService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: UserServiceName,
+	HandlerType: (*UserServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Get", Handler: unaryHandler("/"+UserServiceName+"/Get", UserServiceServer.Get)},
+		{MethodName: "List", Handler: unaryHandler("/"+UserServiceName+"/List", UserServiceServer.List)},
+		{MethodName: "Create", Handler: unaryHandler("/"+UserServiceName+"/Create", UserServiceServer.Create)},
+		{MethodName: "Update", Handler: unaryHandler("/"+UserServiceName+"/Update", UserServiceServer.Update)},
+		{MethodName: "Delete", Handler: unaryHandler("/"+UserServiceName+"/Delete", UserServiceServer.Delete)},
+	},
+	Metadata: "orgapi/user",
+}
+
+// RegisterUserServiceServer registers srv on s.
+func RegisterUserServiceServer(s grpc.ServiceRegistrar, srv UserServiceServer) {
+	s.RegisterService(&UserService_ServiceDesc, srv)
+}
+
+// UserServiceClient is the client API of the user service.
+type UserServiceClient interface {
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*User, error)
+	List(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
+	Create(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*User, error)
+	Update(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*User, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error)
+}
+
+type userServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewUserServiceClient returns a client of the user service using cc.
+func NewUserServiceClient(cc grpc.ClientConnInterface) UserServiceClient {
+	return userServiceClient{cc: cc}
+}
+
+func (c userServiceClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*User, error) {
+	return invoke[User](ctx, c.cc, "/"+UserServiceName+"/Get", in, opts...)
+}
+
+func (c userServiceClient) List(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error) {
+	return invoke[ListUsersResponse](ctx, c.cc, "/"+UserServiceName+"/List", in, opts...)
+}
+
+func (c userServiceClient) Create(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*User, error) {
+	return invoke[User](ctx, c.cc, "/"+UserServiceName+"/Create", in, opts...)
+}
+
+func (c userServiceClient) Update(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*User, error) {
+	return invoke[User](ctx, c.cc, "/"+UserServiceName+"/Update", in, opts...)
+}
+
+func (c userServiceClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error) {
+	return invoke[Empty](ctx, c.cc, "/"+UserServiceName+"/Delete", in, opts...)
+}
@@ -0,0 +1,95 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
+
+// ContentType is the media type of a JSON Merge Patch document (RFC 7396).
+const ContentType = "application/merge-patch+json"
+
+// Field is a member of a JSON Merge Patch document.
+// An omitted member leaves the value unchanged, null removes it and any other value replaces it.
+type Field[T any] struct {
+	Present bool // The member is in the document
+	Null    bool // The member is null
+	Value   T    // The new value, only when Present and not Null
+}
+
+// Set returns a field replacing the value with v.
+func Set[T any](v T) Field[T] {
+	return Field[T]{Present: true, Value: v}
+}
+
+// Null returns a field removing the value.
+func Null[T any]() Field[T] {
+	return Field[T]{Present: true, Null: true}
+}
+
+// FromPtr returns a field replacing the value with *v, or an omitted field when v is nil.
+func FromPtr[T any](v *T) Field[T] {
+	if v == nil {
+		return Field[T]{}
+	}
+	return Set(*v)
+}
+
+// IsSet reports whether the field replaces the value.
+func (f Field[T]) IsSet() bool {
+	return f.Present && !f.Null
+}
+
+// IsNull reports whether the field removes the value.
+func (f Field[T]) IsNull() bool {
+	return f.Present && f.Null
+}
+
+// Ptr returns the new value, or nil when the field is omitted or null.
+func (f Field[T]) Ptr() *T {
+	if !f.IsSet() {
+		return nil
+	}
+	v := f.Value
+	return &v
+}
+
+// UnmarshalJSON marks the field as present. encoding/json only calls it for members of the document.
+func (f *Field[T]) UnmarshalJSON(data []byte) error {
+	f.Present = true
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		f.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &f.Value)
+}
+
+// MarshalJSON writes the value, or null when the field is omitted or null.
+func (f Field[T]) MarshalJSON() ([]byte, error) {
+	if !f.IsSet() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(f.Value)
+}
+
+// SchemaType returns the type of the value, used to document the field.
+func (f Field[T]) SchemaType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// validationValue returns the value to validate, nil when the field is omitted or null
+// so that "omitempty" skips it.
+func (f Field[T]) validationValue() interface{} {
+	if !f.IsSet() {
+		return nil
+	}
+	return f.Value
+}
+
+// ValidationValue is a validator.CustomTypeFunc validating the value of a Field instead of the struct.
+func ValidationValue(v reflect.Value) interface{} {
+	if f, ok := v.Interface().(interface{ validationValue() interface{} }); ok {
+		return f.validationValue()
+	}
+	return nil
+}
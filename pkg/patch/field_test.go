@@ -0,0 +1,132 @@
+package patch
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// patchRequest is a sample merge patch document
+type patchRequest struct {
+	Name  Field[string] `json:"name" binding:"omitempty,min=3"`
+	Count Field[int]    `json:"count" binding:"omitempty,max=10"`
+	Email Field[string] `json:"email" binding:"required"`
+}
+
+func TestFieldUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantPresent bool
+		wantNull    bool
+		wantPtr     *string
+	}{
+		{name: "bỏ qua field", body: `{}`},
+		{name: "field null", body: `{"name": null}`, wantPresent: true, wantNull: true},
+		{name: "field null có khoảng trắng", body: `{"name":  null }`, wantPresent: true, wantNull: true},
+		{name: "field có giá trị", body: `{"name": "bob"}`, wantPresent: true, wantPtr: ptr("bob")},
+		{name: "field là chuỗi rỗng", body: `{"name": ""}`, wantPresent: true, wantPtr: ptr("")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req patchRequest
+			if err := json.Unmarshal([]byte(tt.body), &req); err != nil {
+				t.Fatalf("Không mong đợi lỗi: %v", err)
+			}
+
+			f := req.Name
+			if f.Present != tt.wantPresent || f.IsNull() != tt.wantNull {
+				t.Errorf("Mong đợi present=%v null=%v, nhận được %+v", tt.wantPresent, tt.wantNull, f)
+			}
+			if f.IsSet() != (tt.wantPtr != nil) {
+				t.Errorf("Mong đợi IsSet=%v, nhận được %v", tt.wantPtr != nil, f.IsSet())
+			}
+			if !reflect.DeepEqual(f.Ptr(), tt.wantPtr) {
+				t.Errorf("Mong đợi Ptr %v, nhận được %v", tt.wantPtr, f.Ptr())
+			}
+		})
+	}
+}
+
+func TestFieldUnmarshalJSONWrongType(t *testing.T) {
+	var req patchRequest
+	if err := json.Unmarshal([]byte(`{"count": "ten"}`), &req); err == nil {
+		t.Error("Mong đợi lỗi khi sai kiểu dữ liệu")
+	}
+}
+
+func TestFieldMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		field Field[int]
+		want  string
+	}{
+		{name: "bỏ qua field", field: Field[int]{}, want: "null"},
+		{name: "field null", field: Null[int](), want: "null"},
+		{name: "field có giá trị", field: Set(5), want: "5"},
+		{name: "field từ con trỏ nil", field: FromPtr[int](nil), want: "null"},
+		{name: "field từ con trỏ", field: FromPtr(ptr(0)), want: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.field)
+			if err != nil {
+				t.Fatalf("Không mong đợi lỗi: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Mong đợi %s, nhận được %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidationValue(t *testing.T) {
+	v := validator.New()
+	v.SetTagName("binding")
+	v.RegisterCustomTypeFunc(ValidationValue, Field[string]{}, Field[int]{})
+
+	tests := []struct {
+		name    string
+		req     patchRequest
+		wantErr []string // Các field bị lỗi
+	}{
+		{name: "omitempty bỏ qua field không gửi", req: patchRequest{Email: Set("a@b.c")}},
+		{name: "omitempty bỏ qua field null", req: patchRequest{Name: Null[string](), Count: Null[int](), Email: Set("a@b.c")}},
+		{name: "field có giá trị hợp lệ", req: patchRequest{Name: Set("bob"), Count: Set(10), Email: Set("a@b.c")}},
+		{name: "field có giá trị không hợp lệ", req: patchRequest{Name: Set("bo"), Count: Set(11), Email: Set("a@b.c")}, wantErr: []string{"Name", "Count"}},
+		{name: "required với field không gửi", req: patchRequest{}, wantErr: []string{"Email"}},
+		{name: "required với field null", req: patchRequest{Email: Null[string]()}, wantErr: []string{"Email"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			if err := v.Struct(tt.req); err != nil {
+				vErrs, ok := err.(validator.ValidationErrors)
+				if !ok {
+					t.Fatalf("Mong đợi ValidationErrors, nhận được %v", err)
+				}
+				for _, fe := range vErrs {
+					got = append(got, fe.Field())
+				}
+			}
+			if !reflect.DeepEqual(got, tt.wantErr) {
+				t.Errorf("Mong đợi lỗi ở %v, nhận được %v", tt.wantErr, got)
+			}
+		})
+	}
+}
+
+func TestSchemaType(t *testing.T) {
+	if got := (Field[int]{}).SchemaType(); got != reflect.TypeOf(0) {
+		t.Errorf("Mong đợi int, nhận được %v", got)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,112 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit is a token bucket holding up to Requests tokens, refilled at Requests per Per.
+// The zero Limit disables rate limiting.
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// PerMinute returns a limit of n requests per minute.
+func PerMinute(n int) Limit {
+	return Limit{Requests: n, Per: time.Minute}
+}
+
+// Enabled reports whether the limit throttles requests.
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Per > 0
+}
+
+// String formats the limit as "<requests>/<duration>", for example "100/1m0s".
+func (l Limit) String() string {
+	if !l.Enabled() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", l.Requests, l.Per)
+}
+
+// UnmarshalText parses a limit written as "<requests>/<duration>", for example "100/1m" or "5/10s".
+// "off" and the empty string disable the limit.
+func (l *Limit) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if s == "" || s == "off" {
+		*l = Limit{}
+		return nil
+	}
+
+	requests, per, ok := strings.Cut(s, "/")
+	if !ok {
+		return fmt.Errorf("ratelimit: invalid limit %q, want <requests>/<duration>", s)
+	}
+	n, err := strconv.Atoi(requests)
+	if err != nil || n < 0 {
+		return fmt.Errorf("ratelimit: invalid request count in %q", s)
+	}
+	d, err := time.ParseDuration(per)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("ratelimit: invalid duration in %q", s)
+	}
+
+	*l = Limit{Requests: n, Per: d}
+	return nil
+}
+
+// Result is the outcome of taking a token.
+type Result struct {
+	Allowed    bool
+	Limit      int           // Capacity of the bucket
+	Remaining  int           // Whole tokens left after this request
+	Reset      time.Duration // Time until the bucket is full again
+	RetryAfter time.Duration // Time until the next token, only when not allowed
+}
+
+// Store keeps the buckets. Take refills the bucket of key up to now and takes one token
+// when available. Implementations must be safe for concurrent use.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// rate returns the refill rate in tokens per second.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+// refill returns the tokens of a bucket last updated at updatedAt, capped at the capacity.
+func (l Limit) refill(tokens float64, updatedAt, now time.Time) float64 {
+	elapsed := now.Sub(updatedAt).Seconds()
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return math.Min(float64(l.Requests), tokens+elapsed*l.rate())
+}
+
+// result builds the result from the tokens left after the request.
+func (l Limit) result(tokens float64, allowed bool) Result {
+	r := Result{
+		Allowed:   allowed,
+		Limit:     l.Requests,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     l.wait(float64(l.Requests) - tokens),
+	}
+	if !allowed {
+		r.RetryAfter = l.wait(1 - tokens)
+	}
+	return r
+}
+
+// wait returns the time needed to refill n tokens.
+func (l Limit) wait(n float64) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(n / l.rate() * float64(time.Second)))
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"thuchanhgolang/pkg/mongo"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoCollection is the collection of the buckets kept by MongoStore.
+// A TTL index on expires_at removes the buckets that are full again.
+const MongoCollection = "rate_limits"
+
+// MongoStore keeps the buckets in MongoDB so that limits hold across instances.
+// Each Take is a single atomic update, concurrent requests never take the same token.
+type MongoStore struct {
+	db mongo.Database
+}
+
+// NewMongoStore returns a MongoStore using the MongoCollection of db.
+func NewMongoStore(db mongo.Database) *MongoStore {
+	return &MongoStore{db: db}
+}
+
+// mongoBucket is the bucket document after the update.
+type mongoBucket struct {
+	Tokens  float64 `bson:"tokens"`
+	Allowed bool    `bson:"allowed"`
+}
+
+// Take refills and takes one token on the server with an update pipeline, a new bucket starts full.
+func (s *MongoStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	capacity := float64(limit.Requests)
+	elapsed := bson.M{"$divide": bson.A{
+		bson.M{"$max": bson.A{0, bson.M{"$subtract": bson.A{now, bson.M{"$ifNull": bson.A{"$updated_at", now}}}}}},
+		1000,
+	}}
+	refilled := bson.M{"$min": bson.A{
+		capacity,
+		bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$tokens", capacity}}, bson.M{"$multiply": bson.A{elapsed, limit.rate()}}}},
+	}}
+
+	pipeline := bson.A{
+		bson.M{"$set": bson.M{"tokens": refilled, "updated_at": now}},
+		bson.M{"$set": bson.M{"allowed": bson.M{"$gte": bson.A{"$tokens", 1}}}},
+		bson.M{"$set": bson.M{
+			"tokens":     bson.M{"$cond": bson.A{"$allowed", bson.M{"$subtract": bson.A{"$tokens", 1}}, "$tokens"}},
+			"expires_at": now.Add(limit.Per),
+		}},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var b mongoBucket
+	err := s.db.Collection(MongoCollection).FindOneAndUpdate(ctx, bson.M{"_id": key}, pipeline, opts).Decode(&b)
+	if _, ok := mongo.AsDuplicateKey(err); ok {
+		// Hai request đầu tiên của key cùng upsert, request thua cập nhật bucket vừa được tạo
+		err = s.db.Collection(MongoCollection).FindOneAndUpdate(ctx, bson.M{"_id": key}, pipeline, opts).Decode(&b)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+
+	return limit.result(b.Tokens, b.Allowed), nil
+}
@@ -1,9 +1,12 @@
 package response
 
 import (
+	"errors"
 	"net/http"
 
 	pkgErrors "thuchanhgolang/pkg/errors"
+	"thuchanhgolang/pkg/i18n"
+	"thuchanhgolang/pkg/util"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,11 +15,20 @@ const (
 	// DefaultErrorMessage is the default error message.
 	DefaultErrorMessage = "Something went wrong"
 	// ValidationErrorCode is the validation error code.
-	ValidationErrorCode = 401
+	ValidationErrorCode = 422
 	// ValidationErrorMsg is the validation error message.
 	ValidationErrorMsg = "Validation error"
 )
 
+// kindStatus is the HTTP status of each domain error kind.
+var kindStatus = map[pkgErrors.Kind]int{
+	pkgErrors.KindNotFound:           http.StatusNotFound,
+	pkgErrors.KindConflict:           http.StatusConflict,
+	pkgErrors.KindForbidden:          http.StatusForbidden,
+	pkgErrors.KindValidation:         http.StatusUnprocessableEntity,
+	pkgErrors.KindPreconditionFailed: http.StatusPreconditionFailed,
+}
+
 // Resp is the response format.
 type Resp struct {
 	ErrorCode int    `json:"error_code"`
@@ -24,6 +36,11 @@ type Resp struct {
 	Data      any    `json:"data,omitempty"`
 }
 
+// ConflictData is the data of a conflict response, Field is the field whose value is already taken.
+type ConflictData struct {
+	Field string `json:"field"`
+}
+
 // NewOKResp returns a new OK response with the given data.
 func NewOKResp(data any) Resp {
 	return Resp{
@@ -56,26 +73,55 @@ func OK(c *gin.Context, data any) {
 
 // Unauthorized returns a new Unauthorized response with the given data.
 func Unauthorized(c *gin.Context) {
-	c.JSON(http.StatusUnauthorized, NewUnauthorizedResp())
+	c.JSON(http.StatusUnauthorized, localize(util.GetLanguage(c), NewUnauthorizedResp()))
 }
 
 // Forbidden returns a new Forbidden response
 func Forbidden(c *gin.Context) {
-	c.JSON(http.StatusForbidden, NewForbiddenResp())
+	c.JSON(http.StatusForbidden, localize(util.GetLanguage(c), NewForbiddenResp()))
 }
 
+// parseError chooses the HTTP status and body for err.
+// Errors are matched with errors.As, so wrapped errors are supported.
 func parseError(err error) (int, Resp) {
-	switch parsedErr := err.(type) {
-	case *pkgErrors.ValidationErrorCollector:
-		return http.StatusBadRequest, Resp{
+	var (
+		collector *pkgErrors.ValidationErrorCollector
+		conflict  *pkgErrors.ConflictError
+		httpErr   *pkgErrors.HTTPError
+		domainErr *pkgErrors.DomainError
+	)
+
+	switch {
+	case errors.As(err, &collector):
+		return http.StatusUnprocessableEntity, Resp{
 			ErrorCode: ValidationErrorCode,
 			Message:   ValidationErrorMsg,
-			Data:      parsedErr.Errors(),
+			Data:      collector.Errors(),
+		}
+	case errors.As(err, &conflict):
+		return http.StatusConflict, Resp{
+			ErrorCode: conflict.Code,
+			Message:   conflict.Message,
+			Data:      ConflictData{Field: conflict.Field},
+		}
+	case errors.As(err, &httpErr):
+		status := httpErr.StatusCode
+		if status == 0 {
+			status = http.StatusBadRequest
 		}
-	case *pkgErrors.HTTPError:
-		return http.StatusBadRequest, Resp{
-			ErrorCode: parsedErr.Code,
-			Message:   parsedErr.Message,
+		return status, Resp{
+			ErrorCode: httpErr.Code,
+			Message:   httpErr.Message,
+		}
+	case errors.As(err, &domainErr):
+		// Domain error not mapped by the delivery layer: use the generic code of its kind
+		status := kindStatus[domainErr.Kind]
+		if status == 0 {
+			status = http.StatusBadRequest
+		}
+		return status, Resp{
+			ErrorCode: status,
+			Message:   domainErr.Message,
 		}
 	default:
 		return http.StatusInternalServerError, Resp{
@@ -85,9 +131,29 @@ func parseError(err error) (int, Resp) {
 	}
 }
 
-// Error returns a new Error response with the given error.
+// localize translates the message and the validation messages of resp into lang.
+// Validation errors are copied so that shared error values are not modified.
+func localize(lang string, resp Resp) Resp {
+	resp.Message = i18n.Message(lang, resp.ErrorCode, resp.Message)
+
+	if vErrs, ok := resp.Data.([]*pkgErrors.ValidationError); ok {
+		translated := make([]*pkgErrors.ValidationError, 0, len(vErrs))
+		for _, e := range vErrs {
+			messages := make([]string, 0, len(e.Messages))
+			for _, m := range e.Messages {
+				messages = append(messages, i18n.Validation(lang, m))
+			}
+			translated = append(translated, pkgErrors.NewValidationError(e.Field, messages...))
+		}
+		resp.Data = translated
+	}
+	return resp
+}
+
+// Error returns a new Error response with the given error, in the language of the request.
 func Error(c *gin.Context, err error) {
-	c.JSON(parseError(err))
+	status, resp := parseError(err)
+	c.JSON(status, localize(util.GetLanguage(c), resp))
 }
 
 // ErrorMapping is a map of error to HTTPError.
@@ -0,0 +1,16 @@
+package util
+
+import "time"
+
+// Backoff returns the exponential wait before retrying after the given number of failed attempts:
+// min after the first failure, doubled after each further failure and capped at max
+func Backoff(min, max time.Duration, attempts int) time.Duration {
+	wait := min
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= max {
+			return max
+		}
+	}
+	return wait
+}
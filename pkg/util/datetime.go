@@ -17,6 +17,18 @@ func StrToDateTime(str string) (time.Time, error) {
 	return t.In(GetDefaultTimezone()), nil
 }
 
+// StrToDateTimePtr parses an optional datetime in DateTimeFormat, an empty string gives nil
+func StrToDateTimePtr(str string) (*time.Time, error) {
+	if str == "" {
+		return nil, nil
+	}
+	t, err := StrToDateTime(str)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
 func DateTimeToStr(dt time.Time, ft *string) string {
 	if ft == nil {
 		return dt.Format(DateTimeFormat)
@@ -0,0 +1,48 @@
+package util
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	// ErrIfMatchMissing is returned when a request that must be conditional has no If-Match header
+	ErrIfMatchMissing = errors.New("missing If-Match header")
+	// ErrIfMatchInvalid is returned when If-Match is not an ETag returned by this API
+	ErrIfMatchInvalid = errors.New("invalid If-Match header")
+)
+
+// FormatETag returns the strong ETag of a resource version, e.g. "3"
+func FormatETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// SetETag sets the ETag header to the resource version
+func SetETag(c *gin.Context, version int) {
+	c.Header("ETag", FormatETag(version))
+}
+
+// GetIfMatchVersion returns the version sent in the If-Match header.
+// "*" matches any version and gives 0. Weak ETags are rejected because If-Match uses strong comparison.
+func GetIfMatchVersion(c *gin.Context) (int, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		return 0, ErrIfMatchMissing
+	}
+	if header == "*" {
+		return 0, nil
+	}
+
+	unquoted, err := strconv.Unquote(header)
+	if err != nil {
+		return 0, ErrIfMatchInvalid
+	}
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version <= 0 {
+		return 0, ErrIfMatchInvalid
+	}
+	return version, nil
+}
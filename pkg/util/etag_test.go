@@ -0,0 +1,66 @@
+package util
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetIfMatchVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		header  string
+		want    int
+		wantErr error
+	}{
+		{name: "không có header", header: "", wantErr: ErrIfMatchMissing},
+		{name: "header chỉ có khoảng trắng", header: "  ", wantErr: ErrIfMatchMissing},
+		{name: "wildcard khớp mọi version", header: "*", want: 0},
+		{name: "strong ETag", header: `"3"`, want: 3},
+		{name: "khoảng trắng quanh ETag", header: ` "12" `, want: 12},
+		{name: "weak ETag", header: `W/"3"`, wantErr: ErrIfMatchInvalid},
+		{name: "thiếu dấu nháy", header: "3", wantErr: ErrIfMatchInvalid},
+		{name: "không phải số", header: `"abc"`, wantErr: ErrIfMatchInvalid},
+		{name: "version bằng 0", header: `"0"`, wantErr: ErrIfMatchInvalid},
+		{name: "version âm", header: `"-1"`, wantErr: ErrIfMatchInvalid},
+		{name: "nhiều ETag", header: `"3", "4"`, wantErr: ErrIfMatchInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+			if tt.header != "" {
+				c.Request.Header.Set("If-Match", tt.header)
+			}
+
+			got, err := GetIfMatchVersion(c)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Mong đợi lỗi %v, nhận được %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Mong đợi version %d, nhận được %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFormatETag(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+	c.Request.Header.Set("If-Match", FormatETag(7))
+
+	// ETag trả về cho client phải đọc lại được từ If-Match
+	got, err := GetIfMatchVersion(c)
+	if err != nil {
+		t.Fatalf("Không mong đợi lỗi: %v", err)
+	}
+	if got != 7 {
+		t.Errorf("Mong đợi version 7, nhận được %d", got)
+	}
+}
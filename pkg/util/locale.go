@@ -1,6 +1,11 @@
 package util
 
-import "github.com/gin-gonic/gin"
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
 
 const (
 	ViLanguage      = "vi"
@@ -8,12 +13,57 @@ const (
 	DefaultLanguage = ViLanguage
 )
 
-// GetLanguage returns the language of the request
+// GetLanguage returns the language of the request.
+// The Lang header wins; otherwise the supported language with the highest weight in Accept-Language is used.
 func GetLanguage(c *gin.Context) string {
-	lang := c.GetHeader("Lang")
-	if lang == EnLanguage {
-		return EnLanguage
+	if lang, ok := supportedLanguage(c.GetHeader("Lang")); ok {
+		return lang
+	}
+
+	if lang, ok := parseAcceptLanguage(c.GetHeader("Accept-Language")); ok {
+		return lang
 	}
 
 	return DefaultLanguage
 }
+
+// supportedLanguage returns the supported language of a tag such as "en", "en-US" or "vi_VN"
+func supportedLanguage(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+
+	switch tag {
+	case ViLanguage, EnLanguage:
+		return tag, true
+	}
+	return "", false
+}
+
+// parseAcceptLanguage picks the supported language with the highest q value,
+// e.g. "fr-FR, en;q=0.8, vi;q=0.9" gives "vi"
+func parseAcceptLanguage(header string) (string, bool) {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		lang, ok := supportedLanguage(tag)
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		if v, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		if q > bestQ {
+			best, bestQ = lang, q
+		}
+	}
+	return best, best != ""
+}
@@ -0,0 +1,78 @@
+package util
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+		wantOK bool
+	}{
+		{name: "header rỗng", header: ""},
+		{name: "không có ngôn ngữ được hỗ trợ", header: "fr-FR, de;q=0.9"},
+		{name: "một ngôn ngữ", header: "en", want: EnLanguage, wantOK: true},
+		{name: "q mặc định là 1", header: "en-US, vi;q=0.9", want: EnLanguage, wantOK: true},
+		{name: "chọn q cao nhất", header: "fr-FR, en;q=0.8, vi;q=0.9", want: ViLanguage, wantOK: true},
+		{name: "thứ tự không quan trọng", header: "vi;q=0.5, en;q=0.6", want: EnLanguage, wantOK: true},
+		{name: "q bằng nhau thì lấy ngôn ngữ đầu tiên", header: "vi;q=0.7, en;q=0.7", want: ViLanguage, wantOK: true},
+		{name: "region subtag với dấu gạch ngang", header: "en-GB;q=0.9, fr", want: EnLanguage, wantOK: true},
+		{name: "region subtag với dấu gạch dưới", header: "vi_VN", want: ViLanguage, wantOK: true},
+		{name: "không phân biệt hoa thường", header: "EN-us", want: EnLanguage, wantOK: true},
+		{name: "khoảng trắng quanh tham số", header: "vi; q=0.2 , en ; q=0.3", want: EnLanguage, wantOK: true},
+		{name: "q=0 là không chấp nhận", header: "en;q=0, vi;q=0.1", want: ViLanguage, wantOK: true},
+		{name: "chỉ có q=0", header: "en;q=0"},
+		{name: "bỏ qua q không hợp lệ", header: "en;q=abc, vi;q=0.1", want: ViLanguage, wantOK: true},
+		{name: "bỏ qua wildcard", header: "*, en;q=0.5", want: EnLanguage, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseAcceptLanguage(tt.header)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Mong đợi (%q, %v), nhận được (%q, %v)", tt.want, tt.wantOK, got, ok)
+			}
+		})
+	}
+}
+
+func TestGetLanguage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		lang           string
+		acceptLanguage string
+		want           string
+	}{
+		{name: "không có header", want: DefaultLanguage},
+		{name: "header Lang", lang: "en", acceptLanguage: "vi", want: EnLanguage},
+		{name: "header Lang có region", lang: "en-US", want: EnLanguage},
+		{name: "header Lang không hỗ trợ thì dùng Accept-Language", lang: "fr", acceptLanguage: "en", want: EnLanguage},
+		{name: "Accept-Language", acceptLanguage: "fr, en;q=0.5", want: EnLanguage},
+		{name: "không có ngôn ngữ được hỗ trợ", lang: "fr", acceptLanguage: "de", want: DefaultLanguage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.lang != "" {
+				c.Request.Header.Set("Lang", tt.lang)
+			}
+			if tt.acceptLanguage != "" {
+				c.Request.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			if got := GetLanguage(c); got != tt.want {
+				t.Errorf("Mong đợi %q, nhận được %q", tt.want, got)
+			}
+		})
+	}
+}